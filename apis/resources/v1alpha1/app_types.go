@@ -21,10 +21,46 @@ type AppObservation struct {
 	// the `state` of the application.
 	State string `json:"state,omitempty"`
 
-	// The yaml representation of the environment variables.
+	// The manifest of the application in yaml, as last applied by the provider or generated by Cloud Foundry.
 	AppManifest string `json:"appManifest,omitempty"`
+
+	// (String) The hash of the docker registry credentials the application was last pushed with.
+	// The application is restaged when the resolved credentials change.
+	DockerCredentials []byte `json:"dockerCredentials,omitempty"`
+
+	// The labels and annotations of the application as observed in Cloud Foundry.
+	ResourceMetadata `json:",inline"`
+
+	// The keys of the labels and annotations the provider set from the spec.
+	ManagedMetadata `json:",inline"`
+
+	// The rolling deployment the provider last created to update the application.
+	Deployment *DeploymentObservation `json:"deployment,omitempty"`
 }
 
+// DeploymentObservation is the observed state of a rolling deployment of an application.
+type DeploymentObservation struct {
+	// The GUID of the deployment.
+	GUID string `json:"guid"`
+
+	// The status of the deployment, either `ACTIVE` or `FINALIZED`.
+	Status string `json:"status,omitempty"`
+
+	// The reason of the status of the deployment, e.g. `DEPLOYING`, `DEPLOYED` or `CANCELED`.
+	Reason string `json:"reason,omitempty"`
+}
+
+// DeploymentStrategy is how a started application is rolled onto a new droplet.
+// +kubebuilder:validation:Enum=Rolling;Recreate
+type DeploymentStrategy string
+
+const (
+	// DeploymentStrategyRolling replaces the instances of the application one by one with a rolling deployment.
+	DeploymentStrategyRolling DeploymentStrategy = "Rolling"
+	// DeploymentStrategyRecreate stops all instances of the application before starting them with the new droplet.
+	DeploymentStrategyRecreate DeploymentStrategy = "Recreate"
+)
+
 type AppParameters struct {
 	// The `name` of the application.
 	// +kubebuilder:validation:Required
@@ -37,14 +73,36 @@ type AppParameters struct {
 
 	SpaceReference `json:",inline"`
 
-	// (NOT SUPPORTED YET) An array of one ore more installed buildpack names, e.g., ruby_buildpack, java_buildpack.
+	// (List of String) An array of one ore more installed buildpack names, e.g., ruby_buildpack, java_buildpack.
+	// +crossplane:generate:reference:type=Buildpack
+	// +crossplane:generate:reference:extractor=github.com/SAP/crossplane-provider-cloudfoundry/apis/resources.CloudFoundryName()
+	// +crossplane:generate:reference:refFieldName=BuildpackRefs
+	// +crossplane:generate:reference:selectorFieldName=BuildpackSelector
 	// +kubebuilder:validation:Optional
 	Buildpacks []string `json:"buildpacks,omitempty"`
 
-	// (NOT SUPPORTED YET) The root filesystem to use with the buildpack, for example, cflinuxfs4.
+	// (Attributes) References to `Buildpack` CRs to populate `buildpacks`.
+	// +kubebuilder:validation:Optional
+	BuildpackRefs []v1.NamespacedReference `json:"buildpackRefs,omitempty"`
+
+	// (Attributes) Selector for `Buildpack` CRs to populate `buildpacks`.
+	// +kubebuilder:validation:Optional
+	BuildpackSelector *v1.NamespacedSelector `json:"buildpackSelector,omitempty"`
+
+	// (String) The root filesystem to use with the buildpack, for example, cflinuxfs4.
+	// +crossplane:generate:reference:type=Stack
+	// +crossplane:generate:reference:extractor=github.com/SAP/crossplane-provider-cloudfoundry/apis/resources.CloudFoundryName()
 	// +kubebuilder:validation:Optional
 	Stack *string `json:"stack,omitempty"`
 
+	// (Attributes) Reference to a `Stack` CR to populate `stack`.
+	// +kubebuilder:validation:Optional
+	StackRef *v1.NamespacedReference `json:"stackRef,omitempty"`
+
+	// (Attributes) Selector for a `Stack` CR to populate `stack`.
+	// +kubebuilder:validation:Optional
+	StackSelector *v1.NamespacedSelector `json:"stackSelector,omitempty"`
+
 	// (NOT SUPPORTED YET) The path to the app directory or zip file to push.
 	// +kubebuilder:validation:Optional
 	Path *string `json:"path,omitempty"`
@@ -73,10 +131,44 @@ type AppParameters struct {
 	// +kubebuilder:validation:Optional
 	Services []ServiceBindingConfiguration `json:"services,omitempty"`
 
+	// (Attributes) Managed resources, e.g. service instances, that must be Ready before the application is created.
+	// +kubebuilder:validation:Optional
+	DependsOn []Dependency `json:"dependsOn,omitempty"`
+
+	// The state the application should be in, either `STARTED` or `STOPPED`. The application is started or stopped
+	// when its observed state differs. If unset, the state of the application is not managed.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=STARTED;STOPPED
+	DesiredState string `json:"desiredState,omitempty"`
+
+	// How a started application is rolled onto a new droplet, e.g. of a changed docker image. `Rolling` replaces the
+	// instances one by one with a rolling deployment, which is canceled if it does not finish in time. `Recreate`
+	// restarts all instances at once, which causes a downtime.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=Rolling
+	DeploymentStrategy DeploymentStrategy `json:"deploymentStrategy,omitempty"`
+
+	// When set to true, the credentials of all service instances bound to the application are published in the
+	// `VCAP_SERVICES` format as the `VCAP_SERVICES` key of the connection secret, for consumers outside of Cloud Foundry.
+	// +kubebuilder:validation:Optional
+	PublishVCAPServices bool `json:"publishVcapServices,omitempty"`
+
 	// Configure single process for the application.
 	// +kubebuilder:validation:Optional
 	//	ProcessConfiguration `json:",inline"`
 
+	// The number of instances of the web process to run. The web process is scaled when its observed
+	// number of instances differs. Overridden by the instances of the `web` entry of processes.
+	// +kubebuilder:validation:Optional
+	Instances *uint `json:"instances,omitempty"`
+
+	// The amount of memory allocated to each instance of the web process. This attribute requires a unit of measurement,
+	// such as M, MB, G, GB, T, or TB in upper case or lower case. The web process is scaled when its observed memory differs.
+	// Overridden by the memory of the `web` entry of processes.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+([MmGgTt][Bb]?)$`
+	Memory *string `json:"memory,omitempty"`
+
 	// Configures multiple processes to run for an App. For example, a web application may have a web UI process and a worker process.
 	// +kubebuilder:validation:Optional
 	Processes []ProcessConfiguration `json:"processes,omitempty"`
@@ -89,17 +181,48 @@ type AppParameters struct {
 	// +kubebuilder:validation:Optional
 	// Sidecars []SidecarConfiguration `json:"sidecars,omitempty"`
 
-	// (NOT SUPPORTED YET) A key-value mapping of environment variables to be used for the app when running
+	// A key-value mapping of environment variables to be used for the app when running. Takes precedence over `environmentFrom`.
+	// Values that are not strings are set as JSON. Cloud Foundry applies changed environment variables when the app restarts.
+	// The variables are set when the app is staged as well, as Cloud Foundry has a single environment per app. The staging and
+	// running environment variable groups of Cloud Foundry apply to all apps of the platform and are not managed per app.
 	// +kubebuilder:validation:Optional
 	Environment *runtime.RawExtension `json:"environment,omitempty"`
 
+	// ConfigMaps and Secrets in the namespace of the App whose keys are used as environment variables of the app.
+	// If a key is in several sources, the last source takes precedence. Changes of a referenced ConfigMap are reconciled right away.
+	// +kubebuilder:validation:Optional
+	EnvironmentFrom []EnvironmentSource `json:"environmentFrom,omitempty"`
+
 	// The log rate limit for all instances of an app. This attribute requires a unit of measurement: B, K, KB, M, MB, G, or GB, in either uppercase or lowercase.
+	// The log rate limit applies to all processes of the app, -1 is unlimited.
 	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^(-1|[0-9]+([KkMmGg][Bb]?|[Bb]))$`
 	LogRateLimitPerSecond *string `json:"log-rate-limit-per-second,omitempty"`
 
+	// The labels and annotations of the application. Labels and annotations set by others are kept,
+	// labels and annotations removed from the spec are removed.
 	ResourceMetadata `json:",inline"`
 }
 
+// EnvironmentSource selects a ConfigMap or a Secret to source environment variables from.
+// +kubebuilder:validation:XValidation:rule="has(self.configMapRef) != has(self.secretRef)",message="exactly one of configMapRef or secretRef must be set"
+type EnvironmentSource struct {
+	// The ConfigMap to source environment variables from.
+	// +kubebuilder:validation:Optional
+	ConfigMapRef *LocalObjectReference `json:"configMapRef,omitempty"`
+
+	// The Secret to source environment variables from.
+	// +kubebuilder:validation:Optional
+	SecretRef *LocalObjectReference `json:"secretRef,omitempty"`
+}
+
+// LocalObjectReference references an object in the namespace of the managed resource.
+type LocalObjectReference struct {
+	// Name of the object.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+}
+
 type DockerConfiguration struct {
 	// The URL to the docker image with tag e.g registry.example.com:5000/user/repository/tag or docker image name from the public repo e.g. redis:4.0
 	// +kubebuilder:validation:Required
@@ -224,6 +347,10 @@ type ProcessConfiguration struct {
 	Timeout *uint `json:"timeout,omitempty"`
 
 	HealthCheckConfiguration `json:",inline"`
+
+	// Readiness health check configuration for the process. Defaults to the app-level readiness health check for the `web` process.
+	// +kubebuilder:validation:Optional
+	ReadinessHealthCheckConfiguration `json:",inline"`
 }
 
 // SidecarConfiguration defines the sidecar configuration for the application
@@ -0,0 +1,155 @@
+/*
+Copyright 2023 SAP SE.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	v1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	v2 "github.com/crossplane/crossplane-runtime/v2/apis/common/v2"
+)
+
+type BuildpackObservation struct {
+	// (String) The GUID of the object.
+	ID *string `json:"id,omitempty"`
+
+	// (String) The name of the buildpack.
+	Name *string `json:"name,omitempty"`
+
+	// (String) The state of the buildpack, `AWAITING_UPLOAD` until its bits are uploaded.
+	State *string `json:"state,omitempty"`
+
+	// (String) The filename of the uploaded bits of the buildpack.
+	Filename *string `json:"filename,omitempty"`
+
+	// (String) The name of the stack the buildpack uses.
+	Stack *string `json:"stack,omitempty"`
+
+	// (Number) The order in which the buildpack is checked during buildpack auto-detection.
+	Position *int `json:"position,omitempty"`
+
+	// (Boolean) Whether the buildpack can be used for staging.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// (Boolean) Whether the bits of the buildpack are locked against updates.
+	Locked *bool `json:"locked,omitempty"`
+
+	// (String) The source the bits of the buildpack were last uploaded from by the provider.
+	Source *string `json:"source,omitempty"`
+
+	// (String) The date and time when the resource was created in [RFC3339](https://www.ietf.org/rfc/rfc3339.txt) format.
+	CreatedAt *string `json:"createdAt,omitempty"`
+
+	// (String) The date and time when the resource was updated in [RFC3339](https://www.ietf.org/rfc/rfc3339.txt) format.
+	UpdatedAt *string `json:"updatedAt,omitempty"`
+}
+
+type BuildpackParameters struct {
+	// (String) The name of the buildpack, used by the `buildpacks` of an `App`.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// (Number) The order in which the buildpack is checked during buildpack auto-detection. Defaults to the value observed in Cloud Foundry.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	Position *int `json:"position,omitempty"`
+
+	// (Boolean) Whether the buildpack can be used for staging. Defaults to the value observed in Cloud Foundry.
+	// +kubebuilder:validation:Optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// (Boolean) Whether the bits of the buildpack are locked against updates. Defaults to the value observed in Cloud Foundry.
+	// +kubebuilder:validation:Optional
+	Locked *bool `json:"locked,omitempty"`
+
+	// (String) The name of the stack the buildpack uses. If not set, the stack observed in Cloud Foundry is kept.
+	// +crossplane:generate:reference:type=Stack
+	// +crossplane:generate:reference:extractor=github.com/SAP/crossplane-provider-cloudfoundry/apis/resources.CloudFoundryName()
+	// +kubebuilder:validation:Optional
+	Stack *string `json:"stack,omitempty"`
+
+	// (Attributes) Reference to a `Stack` CR to populate `stack`.
+	// +kubebuilder:validation:Optional
+	StackRef *v1.NamespacedReference `json:"stackRef,omitempty"`
+
+	// (Attributes) Selector for a `Stack` CR to populate `stack`.
+	// +kubebuilder:validation:Optional
+	StackSelector *v1.NamespacedSelector `json:"stackSelector,omitempty"`
+
+	// (String) The zip file with the bits of the buildpack, as `https` URL that starts with one of the `allowedBuildpackSources` of the ProviderConfig.
+	// The bits are uploaded when the buildpack awaits them or the source changes. If not set, the bits are not managed.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^https://`
+	Source *string `json:"source,omitempty"`
+}
+
+// BuildpackSpec defines the desired state of Buildpack
+type BuildpackSpec struct {
+	v2.ManagedResourceSpec `json:",inline"`
+	ForProvider            BuildpackParameters `json:"forProvider"`
+}
+
+// BuildpackStatus defines the observed state of Buildpack.
+type BuildpackStatus struct {
+	v1.ResourceStatus `json:",inline"`
+	AtProvider        BuildpackObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+
+// Buildpack is the Schema for the Buildpacks API. Provides a Cloud Foundry resource to manage admin buildpacks.
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="POSITION",type="integer",JSONPath=".status.atProvider.position"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,cloudfoundry}
+// +kubebuilder:validation:XValidation:rule="[has(self.spec.forProvider.stack), has(self.spec.forProvider.stackRef), has(self.spec.forProvider.stackSelector)].filter(x, x).size() <= 1",message="only one of stack, stackRef, or stackSelector can be set"
+type Buildpack struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              BuildpackSpec   `json:"spec"`
+	Status            BuildpackStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// BuildpackList contains a list of Buildpacks
+type BuildpackList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Buildpack `json:"items"`
+}
+
+// Repository type metadata.
+var (
+	Buildpack_Kind             = "Buildpack"
+	Buildpack_GroupKind        = schema.GroupKind{Group: CRDGroup, Kind: Buildpack_Kind}.String()
+	Buildpack_KindAPIVersion   = Buildpack_Kind + "." + CRDGroupVersion.String()
+	Buildpack_GroupVersionKind = CRDGroupVersion.WithKind(Buildpack_Kind)
+)
+
+func init() {
+	SchemeBuilder.Register(&Buildpack{}, &BuildpackList{})
+}
+
+// GetID returns the ID of the buildpack
+func (b *Buildpack) GetID() string {
+	if b.Status.AtProvider.ID != nil {
+		return *b.Status.AtProvider.ID
+	}
+	return ""
+}
+
+// GetCloudFoundryName implements Namable reference interface, buildpacks are referenced by name
+func (b *Buildpack) GetCloudFoundryName() string {
+	if b.Status.AtProvider.Name != nil {
+		return *b.Status.AtProvider.Name
+	}
+	return ""
+}
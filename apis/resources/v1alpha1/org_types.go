@@ -40,15 +40,19 @@ type OrgObservation struct {
 
 	// (String) The date and time when the resource was updated in [RFC3339](https://www.ietf.org/rfc/rfc3339.txt) format.
 	UpdatedAt *string `json:"updatedAt,omitempty" tf:"updated_at,omitempty"`
+
+	ManagedMetadata `json:",inline"`
 }
 
 type OrgParameters struct {
 	// (Map of String) The annotations associated with Cloud Foundry resources. Add as described [here](https://docs.cloudfoundry.org/adminguide/metadata.html#-view-metadata-for-an-object).
+	// Annotations set by others are kept, annotations removed from the spec are removed.
 	// +kubebuilder:validation:Optional
 	// +mapType=granular
 	Annotations map[string]*string `json:"annotations,omitempty" tf:"annotations,omitempty"`
 
 	// (Map of String) The labels associated with Cloud Foundry resources. Add as described [here](https://docs.cloudfoundry.org/adminguide/metadata.html#-view-metadata-for-an-object).
+	// Labels set by others are kept, labels removed from the spec are removed.
 	// +kubebuilder:validation:Optional
 	// +mapType=granular
 	Labels map[string]*string `json:"labels,omitempty" tf:"labels,omitempty"`
@@ -57,9 +61,24 @@ type OrgParameters struct {
 	// +kubebuilder:validation:Required
 	Name string `json:"name,omitempty" tf:"name,omitempty"`
 
-	// (Boolean) Whether an Organization is suspended or not.
+	// (Boolean) Whether an Organization is suspended or not. Apps keep running in a suspended Organization,
+	// suspending one with running apps sets the `SuspensionWarning` condition.
 	// +kubebuilder:validation:Optional
 	Suspended *bool `json:"suspended,omitempty" tf:"suspended,omitempty"`
+
+	// (String) The ID of the quota to be applied to this Organization. If set, the Organization owns its quota and reverts a quota applied by an `OrgQuota`; if unset, the quota applied in Cloud Foundry is left as it is.
+	// +crossplane:generate:reference:type=OrgQuota
+	// +crossplane:generate:reference:extractor=github.com/SAP/crossplane-provider-cloudfoundry/apis/resources.ExternalID()
+	// +kubebuilder:validation:Optional
+	Quota *string `json:"quota,omitempty" tf:"quota,omitempty"`
+
+	// (Attributes) Reference to an `OrgQuota` CR to populate `quota`.
+	// +kubebuilder:validation:Optional
+	QuotaRef *v1.NamespacedReference `json:"quotaRef,omitempty" tf:"-"`
+
+	// (Attributes) Selector for an `OrgQuota` CR to populate `quota`.
+	// +kubebuilder:validation:Optional
+	QuotaSelector *v1.NamespacedSelector `json:"quotaSelector,omitempty" tf:"-"`
 }
 
 // OrgSpec defines the desired state of Org
@@ -24,9 +24,8 @@ type OrgQuotaInitParameters struct {
 	// (String) The name you use to identify the quota or plan in Cloud Foundry.
 	Name *string `json:"name,omitempty" tf:"name,omitempty"`
 
-	// (Set of String) Set of Org GUIDs to which this org quota would be assigned.
-	// +listType=set
-	Orgs []*string `json:"orgs,omitempty" tf:"orgs,omitempty"`
+	// (List of Attributes) Orgs to which this org quota is applied. Each org is referenced by `org`, `orgName`, `orgRef` or `orgSelector`.
+	Orgs []OrgReference `json:"orgs,omitempty" tf:"-"`
 
 	// (Number) Maximum app instances allowed.
 	TotalAppInstances *float64 `json:"totalAppInstances,omitempty" tf:"total_app_instances,omitempty"`
@@ -107,6 +106,8 @@ type OrgQuotaObservation struct {
 	UpdatedAt *string `json:"updatedAt,omitempty" tf:"updated_at,omitempty"`
 }
 
+// +kubebuilder:validation:XValidation:rule="!(has(self.instanceMemory) && has(self.instanceMemoryWithUnit))",message="instanceMemory and instanceMemoryWithUnit are mutually exclusive"
+// +kubebuilder:validation:XValidation:rule="!(has(self.totalMemory) && has(self.totalMemoryWithUnit))",message="totalMemory and totalMemoryWithUnit are mutually exclusive"
 type OrgQuotaParameters struct {
 	// (Boolean) Determines whether users can provision instances of non-free service plans. Does not control plan visibility. When false, non-free service plans may be visible in the marketplace but instances cannot be provisioned.
 	// +kubebuilder:validation:Optional
@@ -116,14 +117,18 @@ type OrgQuotaParameters struct {
 	// +kubebuilder:validation:Optional
 	InstanceMemory *float64 `json:"instanceMemory,omitempty" tf:"instance_memory,omitempty"`
 
+	// (String) Maximum memory per application instance with a unit of measurement, such as M, MB, G, GB, T, or TB, e.g. `2G`. An alternative to `instanceMemory` in MB, they cannot be set both.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+([MmGgTt][Bb]?)$`
+	InstanceMemoryWithUnit *string `json:"instanceMemoryWithUnit,omitempty" tf:"-"`
+
 	// (String) The name you use to identify the quota or plan in Cloud Foundry.
 	// +kubebuilder:validation:Optional
 	Name *string `json:"name,omitempty" tf:"name,omitempty"`
 
-	// (Set of String) Set of Org GUIDs to which this org quota would be assigned.
+	// (List of Attributes) Orgs to which this org quota is applied. Each org is referenced by `org`, `orgName`, `orgRef` or `orgSelector`. Orgs removed from the list are assigned the `default` org quota once all org references are resolved. An `Organization` that sets `quota` owns the quota of its org, so do not list it here with a different quota. If empty, the orgs the quota is currently applied to are adopted. On deletion, the orgs the org quota is applied to are assigned the `default` org quota.
 	// +kubebuilder:validation:Optional
-	// +listType=set
-	Orgs []*string `json:"orgs,omitempty" tf:"orgs,omitempty"`
+	Orgs []OrgReference `json:"orgs,omitempty" tf:"-"`
 
 	// (Number) Maximum app instances allowed.
 	// +kubebuilder:validation:Optional
@@ -141,6 +146,11 @@ type OrgQuotaParameters struct {
 	// +kubebuilder:validation:Optional
 	TotalMemory *float64 `json:"totalMemory,omitempty" tf:"total_memory,omitempty"`
 
+	// (String) Maximum memory usage allowed with a unit of measurement, such as M, MB, G, GB, T, or TB, e.g. `10G`. An alternative to `totalMemory` in MB, they cannot be set both.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+([MmGgTt][Bb]?)$`
+	TotalMemoryWithUnit *string `json:"totalMemoryWithUnit,omitempty" tf:"-"`
+
 	// (Number) Maximum number of private domains allowed to be created within the Org.
 	// +kubebuilder:validation:Optional
 	TotalPrivateDomains *float64 `json:"totalPrivateDomains,omitempty" tf:"total_private_domains,omitempty"`
@@ -224,3 +234,11 @@ var (
 func init() {
 	SchemeBuilder.Register(&OrgQuota{}, &OrgQuotaList{})
 }
+
+// GetID returns ID of underlying resource of this OrgQuota
+func (tr *OrgQuota) GetID() string {
+	if tr.Status.AtProvider.ID == nil {
+		return ""
+	}
+	return *tr.Status.AtProvider.ID
+}
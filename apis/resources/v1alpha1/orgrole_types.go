@@ -35,6 +35,9 @@ type OrgRoleObservation struct {
 
 	// (String) The username of the Cloud Foundry user to assign the role to.
 	Username *string `json:"username,omitempty" tf:"username,omitempty"`
+
+	// (Map of String) The GUID of the role assigned to each of the `usernames`.
+	RoleAssignments `json:",inline"`
 }
 
 type OrgRoleParameters struct {
@@ -49,9 +52,14 @@ type OrgRoleParameters struct {
 	// +kubebuilder:validation:Optional
 	Origin *string `json:"origin,omitempty" tf:"origin,omitempty"`
 
-	// (String) The username of the Cloud Foundry user to assign the role to.
-	// +kubebuilder:validation:Required
+	// (String) The username of the Cloud Foundry user to assign the role to. Exactly one of `username` or `usernames` must be set.
+	// +kubebuilder:validation:Optional
 	Username string `json:"username,omitempty" tf:"username,omitempty"`
+
+	// (List of String) The usernames of the Cloud Foundry users to assign the role to. Roles of users removed from the list are deleted.
+	// +kubebuilder:validation:Optional
+	// +listType=set
+	Usernames []string `json:"usernames,omitempty"`
 }
 
 // OrgRoleSpec defines the desired state of OrgRole
@@ -76,6 +84,7 @@ type OrgRoleStatus struct {
 // +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,cloudfoundry}
+// +kubebuilder:validation:XValidation:rule="[has(self.spec.forProvider.username), has(self.spec.forProvider.usernames)].filter(x, x).size() == 1",message="exactly one of username or usernames must be set"
 type OrgRole struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -67,3 +67,20 @@ type DomainReference struct {
 	// +kubebuilder:validation:Optional
 	DomainSelector *v1.NamespacedSelector `json:"domainSelector,omitempty"`
 }
+
+// Dependency is a managed resource that must be Ready before a resource is created in Cloud Foundry.
+type Dependency struct {
+	// (String) The API version of the dependency, which must be a resource of this provider.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^cloudfoundry\.crossplane\.io/`
+	// +kubebuilder:default="cloudfoundry.crossplane.io/v1alpha1"
+	APIVersion string `json:"apiVersion,omitempty"`
+
+	// (String) The kind of the dependency, e.g. `ServiceInstance`.
+	// +kubebuilder:validation:Required
+	Kind string `json:"kind"`
+
+	// (String) The name of the dependency in the namespace of the resource.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+}
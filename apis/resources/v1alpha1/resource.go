@@ -20,3 +20,26 @@ type ResourceMetadata struct {
 	// (Map of String) The labels associated with the resource. Add as described [here](https://docs.cloudfoundry.org/adminguide/metadata.html#-view-metadata-for-an-object).
 	Labels map[string]*string `json:"labels,omitempty"`
 }
+
+// ManagedMetadata records the keys of the labels and annotations that a
+// managed resource has set in Cloud Foundry, so that only these are removed
+// when they are removed from the spec, and labels and annotations set by
+// others are kept.
+type ManagedMetadata struct {
+	// (List of String) The keys of the annotations set from the spec.
+	ManagedAnnotations []string `json:"managedAnnotations,omitempty"`
+
+	// (List of String) The keys of the labels set from the spec.
+	ManagedLabels []string `json:"managedLabels,omitempty"`
+}
+
+// ObservedSpec records the spec of a managed resource when it was last
+// observed, so that a change of the spec by the user can be told apart from
+// a drift of the external resource.
+type ObservedSpec struct {
+	// (String) The hash of `spec.forProvider` when the resource was last observed.
+	SpecHash *string `json:"specHash,omitempty"`
+
+	// (Number) The `metadata.generation` of the resource when it was last observed.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
@@ -56,6 +56,48 @@ type RouteParameters struct {
 	// (Attributes) The route options.
 	// +kubebuilder:validation:Optional
 	Options *RouteOptions `json:"options,omitempty"`
+
+	// (List of Attributes) The destinations that the route maps to, e.g. two versions of an app during a blue/green deployment.
+	// If set, destinations that are not listed are removed from the route, including those mapped by an App, and all
+	// destinations are removed before the route is deleted. If unset, the destinations of the route are not managed.
+	// +kubebuilder:validation:Optional
+	Destinations []RouteDestinationParameters `json:"destinations,omitempty"`
+}
+
+// RouteDestinationParameters maps a route to a process of an app.
+type RouteDestinationParameters struct {
+	// (String) The GUID of the app to map the route to.
+	// +crossplane:generate:reference:type=App
+	// +kubebuilder:validation:Optional
+	App *string `json:"app,omitempty"`
+
+	// (Attributes) Reference to an app CR to populate `app`.
+	// +kubebuilder:validation:Optional
+	AppRef *v1.NamespacedReference `json:"appRef,omitempty"`
+
+	// (Attributes) Selector for an app CR to populate `app`.
+	// +kubebuilder:validation:Optional
+	AppSelector *v1.NamespacedSelector `json:"appSelector,omitempty"`
+
+	// (String) The process type of the app that receives the traffic. Defaults to `web`.
+	// +kubebuilder:validation:Optional
+	Process *string `json:"process,omitempty"`
+
+	// (Integer) The port of the app that receives the traffic. Defaults to the port chosen by Cloud Foundry.
+	// +kubebuilder:validation:Optional
+	Port *int `json:"port,omitempty"`
+
+	// (String) The protocol used to send traffic to the app. Defaults to the protocol chosen by Cloud Foundry.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=http1;http2;tcp
+	Protocol *string `json:"protocol,omitempty"`
+
+	// (Integer) The percentage of the traffic of the route sent to this destination. If any destination sets a weight,
+	// the weights of all destinations must add up to 100.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	Weight *int `json:"weight,omitempty"`
 }
 
 type RouteOptions struct {
@@ -75,6 +117,14 @@ type RouteDestination struct {
 	// (Integer) The port to associate with the route for a TCP route. Conflicts with `random_port`.
 	// +kubebuilder:validation:Optional
 	Port *int `json:"port,omitempty"`
+
+	// (String) The protocol used to send traffic to the destination.
+	// +kubebuilder:validation:Optional
+	Protocol *string `json:"protocol,omitempty"`
+
+	// (Integer) The percentage of the traffic of the route sent to the destination, if the route is weighted.
+	// +kubebuilder:validation:Optional
+	Weight *int `json:"weight,omitempty"`
 }
 
 type RouteDestinationApp struct {
@@ -164,3 +214,8 @@ func (r *Route) GetCloudFoundryName() string {
 func (r *Route) GetDomainRef() *DomainReference {
 	return &r.Spec.ForProvider.DomainReference
 }
+
+// GetSpaceRef returns the reference to the space
+func (r *Route) GetSpaceRef() *SpaceReference {
+	return &r.Spec.ForProvider.SpaceReference
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2023 SAP SE.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	v1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	v2 "github.com/crossplane/crossplane-runtime/v2/apis/common/v2"
+)
+
+// SecurityGroupRule is an egress traffic rule of a security group.
+type SecurityGroupRule struct {
+	// (String) Protocol type. Valid values are `tcp`, `udp`, `icmp`, or `all`.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=tcp;udp;icmp;all
+	Protocol string `json:"protocol"`
+
+	// (String) Destinations that the rule applies to. Can be a single IP address, a CIDR block, an IP address range, or a comma-separated list of them.
+	// +kubebuilder:validation:Required
+	Destination string `json:"destination"`
+
+	// (String) Ports that the rule applies to; can be a single port (`443`), a range (`80-90`), or a comma-separated list (`80,443`). Only valid for `tcp` and `udp`.
+	// +kubebuilder:validation:Optional
+	Ports *string `json:"ports,omitempty"`
+
+	// (Number) ICMP type. Only valid for `icmp`.
+	// +kubebuilder:validation:Optional
+	Type *int `json:"type,omitempty"`
+
+	// (Number) ICMP code. Only valid for `icmp`.
+	// +kubebuilder:validation:Optional
+	Code *int `json:"code,omitempty"`
+
+	// (String) A description for the rule.
+	// +kubebuilder:validation:Optional
+	Description *string `json:"description,omitempty"`
+
+	// (Boolean) Enable logging for the rule. Only valid for `tcp`.
+	// +kubebuilder:validation:Optional
+	Log *bool `json:"log,omitempty"`
+}
+
+type SecurityGroupObservation struct {
+	// (String) The GUID of the object.
+	ID *string `json:"id,omitempty"`
+
+	// (String) The name of the security group.
+	Name *string `json:"name,omitempty"`
+
+	// (List of Object) The egress traffic rules of the security group.
+	Rules []SecurityGroupRule `json:"rules,omitempty"`
+
+	// (Boolean) Whether the security group is applied to the running lifecycle of all apps.
+	GloballyEnabledRunning *bool `json:"globallyEnabledRunning,omitempty"`
+
+	// (Boolean) Whether the security group is applied to the staging lifecycle of all apps.
+	GloballyEnabledStaging *bool `json:"globallyEnabledStaging,omitempty"`
+
+	// (Set of String) The GUIDs of the spaces where the security group is applied to running apps.
+	// +listType=set
+	RunningSpaces []*string `json:"runningSpaces,omitempty"`
+
+	// (Set of String) The GUIDs of the spaces where the security group is applied to staging apps.
+	// +listType=set
+	StagingSpaces []*string `json:"stagingSpaces,omitempty"`
+
+	// (Set of String) The GUIDs of the spaces the security group is bound to for running apps because of `runningSpaces`. They are unbound when they are removed from `runningSpaces`.
+	// +listType=set
+	ManagedRunningSpaces []string `json:"managedRunningSpaces,omitempty"`
+
+	// (Set of String) The GUIDs of the spaces the security group is bound to for staging apps because of `stagingSpaces`. They are unbound when they are removed from `stagingSpaces`.
+	// +listType=set
+	ManagedStagingSpaces []string `json:"managedStagingSpaces,omitempty"`
+
+	// (String) The date and time when the resource was created in [RFC3339](https://www.ietf.org/rfc/rfc3339.txt) format.
+	CreatedAt *string `json:"createdAt,omitempty"`
+
+	// (String) The date and time when the resource was updated in [RFC3339](https://www.ietf.org/rfc/rfc3339.txt) format.
+	UpdatedAt *string `json:"updatedAt,omitempty"`
+}
+
+type SecurityGroupParameters struct {
+	// (String) The name of the security group.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// (List of Object) The egress traffic rules of the security group. The order of the rules is not significant. If not set, the rules of the security group are not managed.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinItems=1
+	Rules []SecurityGroupRule `json:"rules,omitempty"`
+
+	// (Boolean) Whether the security group is applied to the running lifecycle of all apps. Defaults to the value observed in Cloud Foundry.
+	// +kubebuilder:validation:Optional
+	GloballyEnabledRunning *bool `json:"globallyEnabledRunning,omitempty"`
+
+	// (Boolean) Whether the security group is applied to the staging lifecycle of all apps. Defaults to the value observed in Cloud Foundry.
+	// +kubebuilder:validation:Optional
+	GloballyEnabledStaging *bool `json:"globallyEnabledStaging,omitempty"`
+
+	// (Set of String) The GUIDs of the spaces where the security group is applied to running apps. Removing a space from the list unbinds it, bindings made by others are kept.
+	// The `runningSecurityGroups` of a `Space` own the running bindings of that space, list the security group there instead when it sets them.
+	// +crossplane:generate:reference:type=Space
+	// +crossplane:generate:reference:extractor=github.com/SAP/crossplane-provider-cloudfoundry/apis/resources.ExternalID()
+	// +kubebuilder:validation:Optional
+	// +listType=set
+	RunningSpaces []*string `json:"runningSpaces,omitempty"`
+
+	// (Attributes) References to `Space` CRs to populate `runningSpaces`.
+	// +kubebuilder:validation:Optional
+	RunningSpacesRefs []v1.NamespacedReference `json:"runningSpacesRefs,omitempty"`
+
+	// (Attributes) Selector for `Space` CRs to populate `runningSpaces`.
+	// +kubebuilder:validation:Optional
+	RunningSpacesSelector *v1.NamespacedSelector `json:"runningSpacesSelector,omitempty"`
+
+	// (Set of String) The GUIDs of the spaces where the security group is applied to staging apps. Removing a space from the list unbinds it, bindings made by others are kept.
+	// The `stagingSecurityGroups` of a `Space` own the staging bindings of that space, list the security group there instead when it sets them.
+	// +crossplane:generate:reference:type=Space
+	// +crossplane:generate:reference:extractor=github.com/SAP/crossplane-provider-cloudfoundry/apis/resources.ExternalID()
+	// +kubebuilder:validation:Optional
+	// +listType=set
+	StagingSpaces []*string `json:"stagingSpaces,omitempty"`
+
+	// (Attributes) References to `Space` CRs to populate `stagingSpaces`.
+	// +kubebuilder:validation:Optional
+	StagingSpacesRefs []v1.NamespacedReference `json:"stagingSpacesRefs,omitempty"`
+
+	// (Attributes) Selector for `Space` CRs to populate `stagingSpaces`.
+	// +kubebuilder:validation:Optional
+	StagingSpacesSelector *v1.NamespacedSelector `json:"stagingSpacesSelector,omitempty"`
+}
+
+// SecurityGroupSpec defines the desired state of SecurityGroup
+type SecurityGroupSpec struct {
+	v2.ManagedResourceSpec `json:",inline"`
+	ForProvider            SecurityGroupParameters `json:"forProvider"`
+}
+
+// SecurityGroupStatus defines the observed state of SecurityGroup.
+type SecurityGroupStatus struct {
+	v1.ResourceStatus `json:",inline"`
+	AtProvider        SecurityGroupObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+
+// SecurityGroup is the Schema for the SecurityGroups API. Provides a Cloud Foundry resource to manage application security groups.
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,cloudfoundry}
+type SecurityGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              SecurityGroupSpec   `json:"spec"`
+	Status            SecurityGroupStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SecurityGroupList contains a list of SecurityGroups
+type SecurityGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SecurityGroup `json:"items"`
+}
+
+// Repository type metadata.
+var (
+	SecurityGroup_Kind             = "SecurityGroup"
+	SecurityGroup_GroupKind        = schema.GroupKind{Group: CRDGroup, Kind: SecurityGroup_Kind}.String()
+	SecurityGroup_KindAPIVersion   = SecurityGroup_Kind + "." + CRDGroupVersion.String()
+	SecurityGroup_GroupVersionKind = CRDGroupVersion.WithKind(SecurityGroup_Kind)
+)
+
+func init() {
+	SchemeBuilder.Register(&SecurityGroup{}, &SecurityGroupList{})
+}
@@ -24,7 +24,8 @@ type ServiceCredentialBindingObservation struct {
 
 // +kubebuilder:validation:XValidation:rule="!(has(self.type) && self.type == 'app') || !has(self.rotation)",message="rotation cannot be enabled when type is app"
 // +kubebuilder:validation:XValidation:rule="!(has(self.type) && self.type == 'key') || has(self.name)",message="name is required when type is key"
-// +kubebuilder:validation:XValidation:rule="!(has(self.type) && self.type == 'app') || has(self.app) || has(self.appRef) || has(self.appSelector)",message="app, appRef, or appSelector is required when type is app"
+// +kubebuilder:validation:XValidation:rule="!(has(self.type) && self.type == 'app') || has(self.app) || has(self.appRef) || has(self.appSelector) || has(self.appName)",message="app, appRef, appSelector, or appName is required when type is app"
+// +kubebuilder:validation:XValidation:rule="!has(self.appName) || has(self.appSpace) || (has(self.appSpaceName) && has(self.appOrgName))",message="appSpace, or appSpaceName and appOrgName, are required when appName is set"
 type ServiceCredentialBindingParameters struct {
 	// (String) The type of the service credential binding in Cloud Foundry. Either "key" or "app".
 	// +kubebuilder:validation:Required
@@ -36,6 +37,10 @@ type ServiceCredentialBindingParameters struct {
 	// +kubebuilder:validation:Optional
 	Name *string `json:"name,omitempty"`
 
+	// (Attributes) Managed resources, e.g. the service instance, that must be Ready before the binding is created.
+	// +kubebuilder:validation:Optional
+	DependsOn []Dependency `json:"dependsOn,omitempty"`
+
 	// (String) The ID of the service instance the binding should be associated with.
 	// +crossplane:generate:reference:type=github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1.ServiceInstance
 	// +kubebuilder:validation:Optional
@@ -62,6 +67,22 @@ type ServiceCredentialBindingParameters struct {
 	// +kubebuilder:validation:Optional
 	AppSelector *v1.NamespacedSelector `json:"appSelector,omitempty"`
 
+	// (String) The name of an app to lookup the GUID of the app in `appSpace`, or in the space named `appSpaceName` of `appOrgName`. The GUID is resolved once and persisted in `app`. Use `appName` only when the app is not managed by Crossplane.
+	// +kubebuilder:validation:Optional
+	AppName *string `json:"appName,omitempty"`
+
+	// (String) The GUID of the space of the app named `appName`.
+	// +kubebuilder:validation:Optional
+	AppSpace *string `json:"appSpace,omitempty"`
+
+	// (String) The name of the space of the app named `appName`.
+	// +kubebuilder:validation:Optional
+	AppSpaceName *string `json:"appSpaceName,omitempty"`
+
+	// (String) The name of the organization containing the space named `appSpaceName`.
+	// +kubebuilder:validation:Optional
+	AppOrgName *string `json:"appOrgName,omitempty"`
+
 	// (Attributes) An optional JSON object to pass `parameters` to the service broker.
 	// +kubebuilder:validation:Optional
 	Parameters *runtime.RawExtension `json:"parameters,omitempty"`
@@ -76,8 +97,14 @@ type ServiceCredentialBindingParameters struct {
 	ConnectionDetailsAsJSON bool `json:"connectionDetailsAsJSON,omitempty"`
 
 	// Rotation defines the parameters for rotating the service credential binding.
+	// The connection secret of a key binding holds the GUID of the current binding in its `binding-guid` key,
+	// so that consumers can detect a rotation.
 	// +kubebuilder:validation:Optional
 	Rotation *RotationParameters `json:"rotation,omitempty"`
+
+	// (Map of String) Additional connection details derived from the credentials, e.g. `jdbcUrl: "jdbc:postgresql://{hostname}:{port}/{dbname}"`. A template references credential fields in braces; nested fields are referenced by their flattened key, e.g. `{uri_host}`. A template that references a missing field is omitted and reported by the `ConnectionDetailTemplates` condition.
+	// +kubebuilder:validation:Optional
+	ConnectionDetailTemplates map[string]string `json:"connectionDetailTemplates,omitempty"`
 }
 
 type ServiceCredentialBindingSpec struct {
@@ -155,3 +182,8 @@ func init() {
 func (s *ServiceCredentialBinding) GetID() string {
 	return s.Status.AtProvider.GUID
 }
+
+// GetLastOperation returns the last operation on the ServiceCredentialBinding, or nil
+func (s *ServiceCredentialBinding) GetLastOperation() *LastOperation {
+	return s.Status.AtProvider.LastOperation
+}
@@ -7,6 +7,8 @@ Copyright 2023 SAP SE.
 package v1alpha1
 
 import (
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -27,6 +29,39 @@ const (
 	UserProvidedService ServiceInstanceType = "user-provided"
 )
 
+// A ParameterComparison defines how the parameters of a service instance are
+// compared to the parameters observed in Cloud Foundry.
+// +kubebuilder:validation:Enum=Subset;Exact
+type ParameterComparison string
+
+const (
+	// ParameterComparisonSubset means the desired parameters must be contained
+	// in the observed parameters. Additional observed keys are not a drift.
+	ParameterComparisonSubset ParameterComparison = "Subset"
+
+	// ParameterComparisonExact means the desired parameters must equal the
+	// observed parameters.
+	ParameterComparisonExact ParameterComparison = "Exact"
+)
+
+// A CredentialMergeStrategy defines how the credentials of a user-provided
+// service instance are applied to its credentials in Cloud Foundry.
+// +kubebuilder:validation:Enum=Replace;Merge
+type CredentialMergeStrategy string
+
+const (
+	// CredentialMergeStrategyReplace means the credentials of the service
+	// instance are replaced with the desired credentials.
+	CredentialMergeStrategyReplace CredentialMergeStrategy = "Replace"
+
+	// CredentialMergeStrategyMerge means the desired credentials are merged
+	// over the credentials of the service instance, preserving the keys that
+	// are not set.
+	CredentialMergeStrategyMerge CredentialMergeStrategy = "Merge"
+)
+
+// +kubebuilder:validation:XValidation:rule="self.type == 'user-provided' || (!has(self.routeServiceUrl) && !has(self.syslogDrainUrl))",message="routeServiceUrl and syslogDrainUrl can only be set when type is user-provided"
+// +kubebuilder:validation:XValidation:rule="!has(self.paramsConfigMapRef) || !has(self.paramsSecretRef)",message="paramsConfigMapRef and paramsSecretRef are mutually exclusive"
 type ServiceInstanceParameters struct {
 	// (String) The name of the service instance
 	// +kubebuilder:validation:Required
@@ -54,10 +89,10 @@ type ServiceInstanceParameters struct {
 	// +kubebuilder:validation:Optional
 	Tags []*string `json:"tags,omitempty" tf:"tags,omitempty"`
 
-	// (Map of String) The annotations associated with Cloud Foundry resources. Add as described [here](https://docs.cloudfoundry.org/adminguide/metadata.html#-view-metadata-for-an-object).
+	// (Attributes) The labels and annotations of the service instance. Labels and annotations set by others are kept,
+	// labels and annotations removed from the spec, including the annotations of removed `contextMetadata`, are removed.
 	// +kubebuilder:validation:Optional
-	// +mapType=granular
-	Annotations map[string]*string `json:"annotations,omitempty" tf:"annotations,omitempty"`
+	ResourceMetadata `json:",inline"`
 }
 
 // Managed configuration for a managed service instance. Only used when `type` is `managed`.
@@ -77,12 +112,34 @@ type Managed struct {
 	// +optional
 	JSONParams *string `json:"jsonParams,omitempty"`
 
-	// (Attributes) Same as `parameters`, supplied as a Secret reference. Ignored if `parameters` or `jsonParams` is set.
+	// (Attributes) Same as `parameters`, supplied as YAML: either structured YAML or a string holding a YAML document.
+	// It is converted to JSON before it is sent to Cloud Foundry. Ignored if `parameters` or `jsonParams` is set.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=""
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	YAMLParams *runtime.RawExtension `json:"yamlParams,omitempty"`
+
+	// (Attributes) Same as `parameters`, supplied as a Secret reference. Ignored if `parameters`, `jsonParams` or `yamlParams` is set.
 	// +kubebuilder:validation:Optional
 	ParametersSecretRef *SecretKeySelector `json:"paramsSecretRef,omitempty" tf:"-"`
 
+	// (Attributes) Non-sensitive parameters supplied as a ConfigMap reference. The parameters of `parameters`, `jsonParams`
+	// or `yamlParams` are merged over them as a JSON merge patch, so inline parameters take precedence. Cannot be set
+	// together with `paramsSecretRef`.
+	// +kubebuilder:validation:Optional
+	ParametersConfigMapRef *ConfigMapKeySelector `json:"paramsConfigMapRef,omitempty" tf:"-"`
+
 	// (Attributes) Information about the version of this service instance; only shown when `type` is `managed`.
 	MaintenanceInfo MaintenanceInfo `json:"maintenanceInfo,omitempty"`
+
+	// (Map of String) Custom metadata for the service broker. Each entry is set as an annotation with the
+	// `context.cloudfoundry.crossplane.io/` prefix, which Cloud Foundry passes to the broker in the
+	// `instance_annotations` of the OSB context when the service instance is created or updated.
+	// Cloud Foundry adds the GUIDs and names of the org and space and the platform to the context itself.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:XValidation:rule="self.all(k, k.matches('^[A-Za-z0-9]([-A-Za-z0-9_.]{0,61}[A-Za-z0-9])?$'))",message="contextMetadata keys must be at most 63 alphanumeric characters, '-', '_' or '.', starting and ending with an alphanumeric character"
+	ContextMetadata map[string]string `json:"contextMetadata,omitempty"`
 }
 
 // UserProvided configuration for a user-provided service instance. Only used when `type` is `user-provided`.
@@ -101,11 +158,18 @@ type UserProvided struct {
 	// +kubebuilder:validation:Optional
 	CredentialsSecretRef *SecretKeySelector `json:"credentialsSecretRef,omitempty"`
 
-	// (String) URL to which requests for bound routes will be forwarded; only shown when `type` is `user-provided`.
+	// (String) How the credentials are applied to the service instance. Either Replace or Merge. Replace sets the credentials
+	// of the service instance to the desired ones. Merge merges the desired credentials over the credentials of the service
+	// instance as a JSON merge patch: keys that are not set are preserved, and keys set to null are removed. Default is Replace.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=Replace
+	CredentialMergeStrategy CredentialMergeStrategy `json:"credentialMergeStrategy,omitempty"`
+
+	// (String) URL to which requests for bound routes will be forwarded. Only allowed when `type` is `user-provided`.
 	// +kubebuilder:validation:Optional
 	RouteServiceURL string `json:"routeServiceUrl,omitempty"`
 
-	// (String) URL to which logs for bound applications will be streamed; only shown when `type` is `user-provided`.
+	// (String) URL to which logs for bound applications will be streamed. Only allowed when `type` is `user-provided`.
 	// +kubebuilder:validation:Optional
 	SyslogDrainURL string `json:"syslogDrainUrl,omitempty"`
 }
@@ -119,6 +183,19 @@ type SecretKeySelector struct {
 	Key string `json:"key,omitempty"`
 }
 
+// A ConfigMapKeySelector is a reference to a ConfigMap key in an arbitrary namespace.
+type ConfigMapKeySelector struct {
+	// Name of the ConfigMap.
+	Name string `json:"name"`
+
+	// Namespace of the ConfigMap.
+	Namespace string `json:"namespace"`
+
+	// The key to select. If not set, all keys of the ConfigMap are selected as an object.
+	// +kubebuilder:validation:Optional
+	Key string `json:"key,omitempty"`
+}
+
 type ServiceInstanceObservation struct {
 	// (String) The GUID of the service instance.
 	ID *string `json:"id,omitempty"`
@@ -152,6 +229,12 @@ type ServiceInstanceObservation struct {
 	// (Attributes) The details of the last operation performed on the resource.
 	LastOperation `json:"lastOperation,omitempty" tf:"last_operation,omitempty"`
 
+	// (Number) The progress in percent of the last operation, as reported by the service broker in its description.
+	Progress *int32 `json:"progress,omitempty"`
+
+	// (String) The step of the last operation, e.g. `step 2 of 5`, as reported by the service broker in its description.
+	ProgressStep *string `json:"progressStep,omitempty"`
+
 	// (Attributes) Information about the version of this service instance; only shown when `type` is `managed`.
 	MaintenanceInfo MaintenanceInfo `json:"maintenanceInfo,omitempty" tf:"maintenance_info,omitempty"`
 
@@ -175,6 +258,30 @@ type ServiceInstanceObservation struct {
 
 	// (Boolean) Whether or not an upgrade of this service instance is available on the current service plan; details are available in the `maintenanceInfo` object; only shown when `type` is `managed`.
 	UpgradeAvailable *bool `json:"upgradeAvailable,omitempty" tf:"upgrade_available,omitempty"`
+
+	// (String) The request body that would be sent to Cloud Foundry to create the service instance. Only set while the `cloudfoundry.crossplane.io/dry-run` annotation is "true".
+	// Parameters and credentials sourced from a Secret are redacted.
+	DryRunPayload *string `json:"dryRunPayload,omitempty"`
+
+	// (Attributes) The desired parameters or credentials of the service instance with the keys of all objects kept and every value masked. Only set if `recordRedactedParameters` is true.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	RedactedParameters *runtime.RawExtension `json:"redactedParameters,omitempty"`
+
+	// (Number) The number of consecutive failed attempts to create the service instance. Reset when a create succeeds or the spec changes.
+	CreateFailures int32 `json:"createFailures,omitempty"`
+
+	// (String) The time of the last failed attempt to create the service instance. The next attempt is delayed by an exponential backoff.
+	LastCreateFailure *metav1.Time `json:"lastCreateFailure,omitempty"`
+
+	// (String) The hash of the spec of the failed attempts to create the service instance, used to detect spec changes.
+	CreateFailureSpecHash *string `json:"createFailureSpecHash,omitempty"`
+
+	// (Number) The number of consecutive failed attempts to delete the service instance.
+	DeleteFailures int32 `json:"deleteFailures,omitempty"`
+
+	ObservedSpec `json:",inline"`
+
+	ManagedMetadata `json:",inline"`
 }
 
 // MaintenanceInfo contains information about the version of this service instance.
@@ -191,7 +298,8 @@ type MaintenanceInfo struct {
 
 // ServicePlanParameters defines a service plan for a managed service instance.
 type ServicePlanParameters struct {
-	// (String) The ID of the service plan from which to create the service instance.
+	// (String) The ID of the service plan from which to create the service instance. If the offering or plan name
+	// is set as well, the service plan must match them.
 	// +optional
 	ID *string `json:"id"`
 
@@ -225,9 +333,56 @@ type ServiceInstanceSpec struct {
 	ForProvider            ServiceInstanceParameters `json:"forProvider"`
 
 	// (Boolean) Enable drift detection for configuration parameters of managed service instance. Default is false.
+	// If the service broker does not support fetching the parameters, the desired parameters are compared with the
+	// last applied ones instead and the `DriftDetectionUnsupported` condition is set.
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default=false
 	EnableParameterDriftDetection bool `json:"enableParameterDriftDetection,omitempty"`
+
+	// (String) How the parameters are compared to the parameters of the service instance when drift detection is enabled. Either Subset or Exact. Subset ignores additional parameters of the service instance. Default is Subset.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=Subset
+	ParameterComparison ParameterComparison `json:"parameterComparison,omitempty"`
+
+	// (Boolean) Record the parameters or credentials of the service instance in `status.atProvider.redactedParameters`, with every value masked, to audit which keys are configured without exposing their values. Default is false.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	RecordRedactedParameters bool `json:"recordRedactedParameters,omitempty"`
+
+	// (Boolean) Publish the credentials of a user-provided service instance as connection details, flattened to one key per value. Keys of credentials that are no longer set are removed from the connection secret. Managed service instances do not expose credentials; publish them with a ServiceCredentialBinding instead. Default is false.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	PublishConnectionDetails bool `json:"publishConnectionDetails,omitempty"`
+
+	// (Boolean) Validate the parameters against the create parameter schema that the service broker publishes for the service plan before creating the service instance. Catches misspelled or invalid parameters before provisioning. Default is false.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	ValidateParameterSchema bool `json:"validateParameterSchema,omitempty"`
+
+	// (Boolean) Withhold the update of a managed service instance whose service plan is changed until the change is approved, since plan changes can be destructive. A pending plan change is reported in the `PendingPlanChange` condition; approve it by setting the `cloudfoundry.crossplane.io/approve-plan-change` annotation to the GUID of the new service plan. Default is false.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	RequirePlanChangeApproval bool `json:"requirePlanChangeApproval,omitempty"`
+
+	// (String) Timeout of a single request creating the service instance, e.g. 2m, for service brokers that respond slowly. Separate from the timeout of the whole create operation. Defaults to the request timeout of the Cloud Foundry client, 30s.
+	// +kubebuilder:validation:Optional
+	RequestTimeout *metav1.Duration `json:"requestTimeout,omitempty"`
+
+	// (Boolean) Purge the service instance from Cloud Foundry after `purgeAfterDeleteFailures` consecutive failed deletes, e.g. when its service broker fails or no longer exists. A purge removes the service instance and its bindings without contacting the service broker, which may leave the service instance orphaned at the broker. Default is false.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	PurgeOnDeleteFailure bool `json:"purgeOnDeleteFailure,omitempty"`
+
+	// (Number) The number of consecutive failed deletes after which the service instance is purged, if `purgeOnDeleteFailure` is true. Default is 3.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=3
+	PurgeAfterDeleteFailures int32 `json:"purgeAfterDeleteFailures,omitempty"`
+
+	// (Boolean) True to delete the credential bindings, i.e. app bindings and service keys, and the route bindings of the service instance one by one, waiting for each, before the service instance is deleted.
+	// Otherwise Cloud Foundry deletes the bindings with the service instance. If it refuses to because of bindings, the bindings are reported by the `DeletionBlocked` condition. Default is false.
+	// +kubebuilder:validation:Optional
+	CascadeDeleteBindings bool `json:"cascadeDeleteBindings,omitempty"`
 }
 
 // ServiceInstanceStatus defines the observed state of ServiceInstance
@@ -244,7 +399,10 @@ type ServiceInstanceStatus struct {
 // +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
 // +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 // +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="PROGRESS",type="integer",JSONPath=".status.atProvider.progress"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="VERSION",type="string",JSONPath=".status.atProvider.maintenanceInfo.version",priority=1
+// +kubebuilder:printcolumn:name="DASHBOARD",type="string",JSONPath=".status.atProvider.dashboardUrl",priority=1
 // +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,cloudfoundry}
 // +kubebuilder:validation:XValidation:rule="self.spec.managementPolicies == ['Observe'] || (has(self.spec.forProvider.spaceName) || has(self.spec.forProvider.spaceRef) || has(self.spec.forProvider.spaceSelector))",message="SpaceReference is required: exactly one of spaceName, spaceRef, or spaceSelector must be set"
 // +kubebuilder:validation:XValidation:rule="[has(self.spec.forProvider.spaceName), has(self.spec.forProvider.spaceRef), has(self.spec.forProvider.spaceSelector)].filter(x, x).size() <= 1",message="SpaceReference validation: only one of spaceName, spaceRef, or spaceSelector can be set"
@@ -292,7 +450,21 @@ func (r *ServiceInstance) GetID() string {
 	return *r.Status.AtProvider.ID
 }
 
+// GetLastOperation returns the last operation on the ServiceInstance
+func (r *ServiceInstance) GetLastOperation() *LastOperation {
+	return &r.Status.AtProvider.LastOperation
+}
+
 // GetSpaceRef returns the reference to the space
 func (s *ServiceInstance) GetSpaceRef() *SpaceReference {
 	return &s.Spec.ForProvider.SpaceReference
 }
+
+// GetRequestTimeout returns the timeout of a single request creating the
+// service instance, or nil if it is not set.
+func (s *ServiceInstance) GetRequestTimeout() *time.Duration {
+	if s.Spec.RequestTimeout == nil {
+		return nil
+	}
+	return &s.Spec.RequestTimeout.Duration
+}
@@ -15,12 +15,13 @@ import (
 // +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 // +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
 // +kubebuilder:printcolumn:name="ROUTE",type="string",JSONPath=".status.atProvider.routeGUID",priority=1
+// +kubebuilder:printcolumn:name="ROUTE-URL",type="string",JSONPath=".status.atProvider.routeURL",priority=1
 // +kubebuilder:printcolumn:name="SERVICE-INSTANCE",type="string",JSONPath=".status.atProvider.serviceInstanceGUID",priority=1
 // +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name",priority=1
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
-// +kubebuilder:validation:XValidation:rule="has(self.spec.forProvider.serviceInstance) || has(self.spec.forProvider.serviceInstanceRef) || has(self.spec.forProvider.serviceInstanceSelector)",message="ServiceInstanceReference validation: one of serviceInstance, serviceInstanceRef, or serviceInstanceSelector must be set"
+// +kubebuilder:validation:XValidation:rule="self.spec.managementPolicies == ['Observe'] || has(self.spec.forProvider.serviceInstance) || has(self.spec.forProvider.serviceInstanceRef) || has(self.spec.forProvider.serviceInstanceSelector)",message="ServiceInstanceReference validation: one of serviceInstance, serviceInstanceRef, or serviceInstanceSelector must be set"
 // +kubebuilder:validation:XValidation:rule="!has(self.spec.forProvider.serviceInstanceRef) || !has(self.spec.forProvider.serviceInstanceSelector)",message="ServiceInstanceReference validation: serviceInstanceRef and serviceInstanceSelector are mutually exclusive"
-// +kubebuilder:validation:XValidation:rule="has(self.spec.forProvider.route) || has(self.spec.forProvider.routeRef) || has(self.spec.forProvider.routeSelector)",message="RouteReference validation: one of route, routeRef, or routeSelector must be set"
+// +kubebuilder:validation:XValidation:rule="self.spec.managementPolicies == ['Observe'] || has(self.spec.forProvider.route) || has(self.spec.forProvider.routeRef) || has(self.spec.forProvider.routeSelector)",message="RouteReference validation: one of route, routeRef, or routeSelector must be set"
 // +kubebuilder:validation:XValidation:rule="!has(self.spec.forProvider.routeRef) || !has(self.spec.forProvider.routeSelector)",message="RouteReference validation: routeRef and routeSelector are mutually exclusive"
 // +kubebuilder:validation:XValidation:rule="!has(oldSelf.spec.forProvider.route) || !has(self.spec.forProvider.route) || oldSelf.spec.forProvider.route.size() == 0 || oldSelf.spec.forProvider.route == self.spec.forProvider.route",message="ServiceRouteBinding is immutable: route GUID cannot be changed once set"
 // +kubebuilder:validation:XValidation:rule="!has(oldSelf.spec.forProvider.serviceInstance) || !has(self.spec.forProvider.serviceInstance) || oldSelf.spec.forProvider.serviceInstance.size() == 0 || oldSelf.spec.forProvider.serviceInstance == self.spec.forProvider.serviceInstance",message="ServiceRouteBinding is immutable: serviceInstance GUID cannot be changed once set"
@@ -78,6 +79,22 @@ type ServiceRouteBindingObservation struct {
 	// +kubebuilder:validation:Optional
 	Route string `json:"routeGUID,omitempty"`
 
+	// (String) The host of the route, empty for a route without host.
+	// +kubebuilder:validation:Optional
+	RouteHost string `json:"routeHost,omitempty"`
+
+	// (String) The path of the route, e.g. `/api`, empty for a route without path.
+	// +kubebuilder:validation:Optional
+	RoutePath string `json:"routePath,omitempty"`
+
+	// (String) The GUID of the domain of the route, which can be an internal domain.
+	// +kubebuilder:validation:Optional
+	RouteDomain string `json:"routeDomainGUID,omitempty"`
+
+	// (String) The URL of the route, i.e. its host, domain and path.
+	// +kubebuilder:validation:Optional
+	RouteURL string `json:"routeURL,omitempty"`
+
 	// A map of arbitrary key/value paris to be send to the service broker during binding only supported for user-provided service instances
 	// +kubebuilder:validation:Optional
 	Parameters runtime.RawExtension `json:"parameters,omitempty"`
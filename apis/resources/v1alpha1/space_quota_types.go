@@ -125,6 +125,8 @@ type SpaceQuotaObservation struct {
 	UpdatedAt *string `json:"updatedAt,omitempty" tf:"updated_at,omitempty"`
 }
 
+// +kubebuilder:validation:XValidation:rule="!(has(self.instanceMemory) && has(self.instanceMemoryWithUnit))",message="instanceMemory and instanceMemoryWithUnit are mutually exclusive"
+// +kubebuilder:validation:XValidation:rule="!(has(self.totalMemory) && has(self.totalMemoryWithUnit))",message="totalMemory and totalMemoryWithUnit are mutually exclusive"
 type SpaceQuotaParameters struct {
 	// (Boolean) Determines whether users can provision instances of non-free service plans. Does not control plan visibility. When false, non-free service plans may be visible in the marketplace but instances cannot be provisioned.
 	// +kubebuilder:validation:Optional
@@ -134,14 +136,23 @@ type SpaceQuotaParameters struct {
 	// +kubebuilder:validation:Optional
 	InstanceMemory *float64 `json:"instanceMemory,omitempty" tf:"instance_memory,omitempty"`
 
+	// (String) Maximum memory per application instance with a unit of measurement, such as M, MB, G, GB, T, or TB, e.g. `2G`. An alternative to `instanceMemory` in MB, they cannot be set both.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+([MmGgTt][Bb]?)$`
+	InstanceMemoryWithUnit *string `json:"instanceMemoryWithUnit,omitempty" tf:"-"`
+
 	// (String) The name you use to identify the quota or plan in Cloud Foundry.
 	// +kubebuilder:validation:Optional
 	Name *string `json:"name,omitempty" tf:"name,omitempty"`
 
-	// (String) The ID of the Org within which to create the space quota.
+	// (String) The ID of the Org within which to create the space quota. The org of an existing space quota cannot be changed; a changed org is reported in the Ready condition.
 	// +kubebuilder:validation:Optional
 	Org *string `json:"org,omitempty" tf:"org,omitempty"`
 
+	// (String) The name of the Cloud Foundry organization to lookup the GUID of the org to populate `org`. Use `orgName` only when the org is not managed by Crossplane; `orgRef` and `orgSelector` take precedence.
+	// +kubebuilder:validation:Optional
+	OrgName *string `json:"orgName,omitempty" tf:"-"`
+
 	// (Attributes) Reference to an Org in resources to populate `org`.
 	// +kubebuilder:validation:Optional
 	OrgRef *v1.NamespacedReference `json:"orgRef,omitempty" tf:"-"`
@@ -181,6 +192,11 @@ type SpaceQuotaParameters struct {
 	// +kubebuilder:validation:Optional
 	TotalMemory *float64 `json:"totalMemory,omitempty" tf:"total_memory,omitempty"`
 
+	// (String) Maximum memory usage allowed with a unit of measurement, such as M, MB, G, GB, T, or TB, e.g. `10G`. An alternative to `totalMemory` in MB, they cannot be set both.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+([MmGgTt][Bb]?)$`
+	TotalMemoryWithUnit *string `json:"totalMemoryWithUnit,omitempty" tf:"-"`
+
 	// (Number) Total number of ports that are reservable by routes in a space.
 	// +kubebuilder:validation:Optional
 	TotalRoutePorts *float64 `json:"totalRoutePorts,omitempty" tf:"total_route_ports,omitempty"`
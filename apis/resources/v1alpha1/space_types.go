@@ -26,6 +26,9 @@ type SpaceObservation struct {
 	// (String) The date and time when the resource was created in [RFC3339](https://www.ietf.org/rfc/rfc3339.txt) format.
 	CreatedAt *string `json:"createdAt,omitempty" tf:"created_at,omitempty"`
 
+	// (Map of Boolean) Whether each feature of the space is enabled, by feature name.
+	Features map[string]bool `json:"features,omitempty"`
+
 	// (String) The GUID of the object.
 	ID string `json:"id,omitempty"`
 
@@ -45,8 +48,31 @@ type SpaceObservation struct {
 	// (String) The space quota applied to the space. To assign a space quota, use the space quota resource instead.
 	Quota *string `json:"quota,omitempty" tf:"quota,omitempty"`
 
+	// (List of String) The names of the running security groups bound to the space; only shown when `runningSecurityGroups` is set.
+	RunningSecurityGroups []string `json:"runningSecurityGroups,omitempty"`
+
+	// (List of String) The names of the staging security groups bound to the space; only shown when `stagingSecurityGroups` is set.
+	StagingSecurityGroups []string `json:"stagingSecurityGroups,omitempty"`
+
 	// (String) The date and time when the resource was updated in [RFC3339](https://www.ietf.org/rfc/rfc3339.txt) format.
 	UpdatedAt *string `json:"updatedAt,omitempty" tf:"updated_at,omitempty"`
+
+	// (Attributes) The resources the space currently uses; omitted if the CF API does not return them.
+	Usage *SpaceUsage `json:"usage,omitempty"`
+
+	ManagedMetadata `json:",inline"`
+}
+
+// SpaceUsage is the resource usage of a space, to compare with its quota.
+type SpaceUsage struct {
+	// (Number) The memory in MB used by the started app instances of the space.
+	MemoryInMB *int `json:"memoryInMb,omitempty"`
+
+	// (Number) The number of routes in the space.
+	Routes *int `json:"routes,omitempty"`
+
+	// (Number) The number of started app instances in the space.
+	StartedInstances *int `json:"startedInstances,omitempty"`
 }
 
 type SpaceParameters struct {
@@ -57,15 +83,32 @@ type SpaceParameters struct {
 	AllowSSH bool `json:"allowSsh,omitempty" tf:"allow_ssh,omitempty"`
 
 	// (Map of String) The annotations associated with Cloud Foundry resources. Add as described [here](https://docs.cloudfoundry.org/adminguide/metadata.html#-view-metadata-for-an-object).
+	// Annotations set by others are kept, annotations removed from the spec are removed.
 	// +kubebuilder:validation:Optional
 	// +mapType=granular
 	Annotations map[string]*string `json:"annotations,omitempty" tf:"annotations,omitempty"`
 
+	// (Map of Boolean) Enables or disables features of the space by feature name, e.g. `ssh`.
+	// Features that are not listed are left as they are. An `ssh` entry takes precedence over `allowSsh`.
+	// +kubebuilder:validation:Optional
+	Features map[string]bool `json:"features,omitempty"`
+
+	// (Boolean) True to delete the apps, routes and service instances of the space when the space is deleted.
+	// Otherwise the deletion of a space that is not empty is blocked and reported by the `DeletionBlocked` condition.
+	// +kubebuilder:validation:Optional
+	ForceDelete bool `json:"forceDelete,omitempty"`
+
 	// (String) The ID of the isolation segment to assign to the space. The isolation segment must be entitled to the space's parent organization.
+	// Set to an empty string to unassign the isolation segment; if unset, the assignment in Cloud Foundry is left as it is.
 	// +kubebuilder:validation:Optional
 	IsolationSegment *string `json:"isolationSegment,omitempty" tf:"isolation_segment,omitempty"`
 
+	// (String) The name of the isolation segment to lookup the GUID of the isolation segment. Takes precedence over `isolationSegment`.
+	// +kubebuilder:validation:Optional
+	IsolationSegmentName *string `json:"isolationSegmentName,omitempty"`
+
 	// (Map of String) The labels associated with Cloud Foundry resources. Add as described [here](https://docs.cloudfoundry.org/adminguide/metadata.html#-view-metadata-for-an-object).
+	// Labels set by others are kept, labels removed from the spec are removed.
 	// +kubebuilder:validation:Optional
 	// +mapType=granular
 	Labels map[string]*string `json:"labels,omitempty" tf:"labels,omitempty"`
@@ -74,6 +117,18 @@ type SpaceParameters struct {
 	// +kubebuilder:validation:Required
 	Name string `json:"name,omitempty" tf:"name,omitempty"`
 
+	// (List of String) The names of the application security groups to bind to the space for running apps.
+	// Security groups bound by others, including by a `SecurityGroup`, are unbound; if unset, the running security groups of the space are left as they are.
+	// +kubebuilder:validation:Optional
+	// +listType=set
+	RunningSecurityGroups []string `json:"runningSecurityGroups,omitempty"`
+
+	// (List of String) The names of the application security groups to bind to the space for staging apps.
+	// Security groups bound by others, including by a `SecurityGroup`, are unbound; if unset, the staging security groups of the space are left as they are.
+	// +kubebuilder:validation:Optional
+	// +listType=set
+	StagingSecurityGroups []string `json:"stagingSecurityGroups,omitempty"`
+
 	// (Attributes) Reference to the organization in which to create the space.
 	OrgReference `json:",inline"`
 }
@@ -94,10 +149,13 @@ type SpaceStatus struct {
 // +kubebuilder:subresource:status
 // +kubebuilder:storageversion
 
-// Space is the Schema for the Spaces API. Provides a Cloud Foundry resource for managing Cloud Foundry spaces within organizations.
+// Space is the Schema for the Spaces API. Provides a Cloud Foundry resource for managing Cloud Foundry spaces within organizations. The connection secret of a space holds its `spaceGuid` and `orgGuid`.
 // +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
 // +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 // +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="MEMORY",type="integer",JSONPath=".status.atProvider.usage.memoryInMb"
+// +kubebuilder:printcolumn:name="INSTANCES",type="integer",JSONPath=".status.atProvider.usage.startedInstances"
+// +kubebuilder:printcolumn:name="ROUTES",type="integer",JSONPath=".status.atProvider.usage.routes"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,cloudfoundry}
 type Space struct {
@@ -25,6 +25,7 @@ type SpaceRoleObservation struct {
 	Origin *string `json:"origin,omitempty" tf:"origin,omitempty"`
 
 	// (String) The space role type; see [Valid role types](https://v3-apidocs.cloudfoundry.org/version/3.154.0/index.html#valid-role-types).
+	// Changing the type replaces the role of the user, as Cloud Foundry roles cannot be updated.
 	Type *string `json:"type,omitempty" tf:"type,omitempty"`
 
 	// (String) The date and time when the resource was updated in [RFC3339](https://www.ietf.org/rfc/rfc3339.txt) format.
@@ -35,6 +36,9 @@ type SpaceRoleObservation struct {
 
 	// (String) The username of the Cloud Foundry user to assign the role to.
 	Username *string `json:"username,omitempty" tf:"username,omitempty"`
+
+	// (Map of String) The GUID of the role assigned to each of the `usernames`.
+	RoleAssignments `json:",inline"`
 }
 
 type SpaceRoleParameters struct {
@@ -42,6 +46,7 @@ type SpaceRoleParameters struct {
 	SpaceReference `json:",inline"`
 
 	// (String) The space role type; see [Valid role types](https://v3-apidocs.cloudfoundry.org/version/3.154.0/index.html#valid-role-types).
+	// Changing the type replaces the role of the user, as Cloud Foundry roles cannot be updated.
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:Enum=Developer;Auditor;Manager;Supporter;Developers;Auditors;Managers;Supporters
 	Type string `json:"type,omitempty" tf:"type,omitempty"`
@@ -50,9 +55,14 @@ type SpaceRoleParameters struct {
 	// +kubebuilder:validation:Optional
 	Origin *string `json:"origin,omitempty" tf:"origin,omitempty"`
 
-	// (String) The username of the Cloud Foundry user to assign the role to.
-	// +kubebuilder:validation:Required
+	// (String) The username of the Cloud Foundry user to assign the role to. Exactly one of `username` or `usernames` must be set.
+	// +kubebuilder:validation:Optional
 	Username string `json:"username,omitempty" tf:"username,omitempty"`
+
+	// (List of String) The usernames of the Cloud Foundry users to assign the role to. Roles of users removed from the list are deleted.
+	// +kubebuilder:validation:Optional
+	// +listType=set
+	Usernames []string `json:"usernames,omitempty"`
 }
 
 // SpaceRoleSpec defines the desired state of SpaceRole
@@ -79,6 +89,7 @@ type SpaceRoleStatus struct {
 // +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,cloudfoundry}
 // +kubebuilder:validation:XValidation:rule="self.spec.managementPolicies == ['Observe'] || (has(self.spec.forProvider.spaceName) || has(self.spec.forProvider.spaceRef) || has(self.spec.forProvider.spaceSelector))",message="SpaceReference is required: exactly one of spaceName, spaceRef, or spaceSelector must be set"
 // +kubebuilder:validation:XValidation:rule="[has(self.spec.forProvider.spaceName), has(self.spec.forProvider.spaceRef), has(self.spec.forProvider.spaceSelector)].filter(x, x).size() <= 1",message="SpaceReference validation: only one of spaceName, spaceRef, or spaceSelector can be set"
+// +kubebuilder:validation:XValidation:rule="[has(self.spec.forProvider.username), has(self.spec.forProvider.usernames)].filter(x, x).size() == 1",message="exactly one of username or usernames must be set"
 type SpaceRole struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -0,0 +1,117 @@
+/*
+Copyright 2023 SAP SE.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	v1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	v2 "github.com/crossplane/crossplane-runtime/v2/apis/common/v2"
+)
+
+type StackObservation struct {
+	// (String) The GUID of the object.
+	ID *string `json:"id,omitempty"`
+
+	// (String) The name of the stack.
+	Name *string `json:"name,omitempty"`
+
+	// (String) The description of the stack.
+	Description *string `json:"description,omitempty"`
+
+	// (String) The name of the image used to run apps on the stack.
+	RunRootfsImage *string `json:"runRootfsImage,omitempty"`
+
+	// (String) The name of the image used to stage apps on the stack.
+	BuildRootfsImage *string `json:"buildRootfsImage,omitempty"`
+
+	// (Boolean) Whether the stack is the default stack of the Cloud Foundry foundation.
+	Default *bool `json:"default,omitempty"`
+
+	// (String) The date and time when the resource was created in [RFC3339](https://www.ietf.org/rfc/rfc3339.txt) format.
+	CreatedAt *string `json:"createdAt,omitempty"`
+
+	// (String) The date and time when the resource was updated in [RFC3339](https://www.ietf.org/rfc/rfc3339.txt) format.
+	UpdatedAt *string `json:"updatedAt,omitempty"`
+}
+
+type StackParameters struct {
+	// (String) The name of the stack. Cannot be changed once the stack is created.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="name is immutable"
+	Name string `json:"name"`
+
+	// (String) The description of the stack. Cannot be changed once the stack is created.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="description is immutable"
+	Description *string `json:"description,omitempty"`
+}
+
+// StackSpec defines the desired state of Stack
+type StackSpec struct {
+	v2.ManagedResourceSpec `json:",inline"`
+	ForProvider            StackParameters `json:"forProvider"`
+}
+
+// StackStatus defines the observed state of Stack.
+type StackStatus struct {
+	v1.ResourceStatus `json:",inline"`
+	AtProvider        StackObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+
+// Stack is the Schema for the Stacks API. Provides a Cloud Foundry resource to manage the root filesystems of apps.
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,cloudfoundry}
+type Stack struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              StackSpec   `json:"spec"`
+	Status            StackStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// StackList contains a list of Stacks
+type StackList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Stack `json:"items"`
+}
+
+// Repository type metadata.
+var (
+	Stack_Kind             = "Stack"
+	Stack_GroupKind        = schema.GroupKind{Group: CRDGroup, Kind: Stack_Kind}.String()
+	Stack_KindAPIVersion   = Stack_Kind + "." + CRDGroupVersion.String()
+	Stack_GroupVersionKind = CRDGroupVersion.WithKind(Stack_Kind)
+)
+
+func init() {
+	SchemeBuilder.Register(&Stack{}, &StackList{})
+}
+
+// GetID returns the ID of the stack
+func (s *Stack) GetID() string {
+	if s.Status.AtProvider.ID != nil {
+		return *s.Status.AtProvider.ID
+	}
+	return ""
+}
+
+// GetCloudFoundryName implements Namable reference interface, stacks are referenced by name
+func (s *Stack) GetCloudFoundryName() string {
+	if s.Status.AtProvider.Name != nil {
+		return *s.Status.AtProvider.Name
+	}
+	return ""
+}
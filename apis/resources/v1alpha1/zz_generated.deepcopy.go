@@ -89,6 +89,18 @@ func (in *AppList) DeepCopyObject() runtime.Object {
 func (in *AppObservation) DeepCopyInto(out *AppObservation) {
 	*out = *in
 	in.Resource.DeepCopyInto(&out.Resource)
+	if in.DockerCredentials != nil {
+		in, out := &in.DockerCredentials, &out.DockerCredentials
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	in.ResourceMetadata.DeepCopyInto(&out.ResourceMetadata)
+	in.ManagedMetadata.DeepCopyInto(&out.ManagedMetadata)
+	if in.Deployment != nil {
+		in, out := &in.Deployment, &out.Deployment
+		*out = new(DeploymentObservation)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppObservation.
@@ -110,11 +122,33 @@ func (in *AppParameters) DeepCopyInto(out *AppParameters) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.BuildpackRefs != nil {
+		in, out := &in.BuildpackRefs, &out.BuildpackRefs
+		*out = make([]v1.NamespacedReference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.BuildpackSelector != nil {
+		in, out := &in.BuildpackSelector, &out.BuildpackSelector
+		*out = new(v1.NamespacedSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Stack != nil {
 		in, out := &in.Stack, &out.Stack
 		*out = new(string)
 		**out = **in
 	}
+	if in.StackRef != nil {
+		in, out := &in.StackRef, &out.StackRef
+		*out = new(v1.NamespacedReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StackSelector != nil {
+		in, out := &in.StackSelector, &out.StackSelector
+		*out = new(v1.NamespacedSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Path != nil {
 		in, out := &in.Path, &out.Path
 		*out = new(string)
@@ -139,6 +173,21 @@ func (in *AppParameters) DeepCopyInto(out *AppParameters) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]Dependency, len(*in))
+		copy(*out, *in)
+	}
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = new(uint)
+		**out = **in
+	}
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		*out = new(string)
+		**out = **in
+	}
 	if in.Processes != nil {
 		in, out := &in.Processes, &out.Processes
 		*out = make([]ProcessConfiguration, len(*in))
@@ -152,6 +201,13 @@ func (in *AppParameters) DeepCopyInto(out *AppParameters) {
 		*out = new(runtime.RawExtension)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.EnvironmentFrom != nil {
+		in, out := &in.EnvironmentFrom, &out.EnvironmentFrom
+		*out = make([]EnvironmentSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.LogRateLimitPerSecond != nil {
 		in, out := &in.LogRateLimitPerSecond, &out.LogRateLimitPerSecond
 		*out = new(string)
@@ -204,6 +260,234 @@ func (in *AppStatus) DeepCopy() *AppStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Buildpack) DeepCopyInto(out *Buildpack) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Buildpack.
+func (in *Buildpack) DeepCopy() *Buildpack {
+	if in == nil {
+		return nil
+	}
+	out := new(Buildpack)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Buildpack) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildpackList) DeepCopyInto(out *BuildpackList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Buildpack, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildpackList.
+func (in *BuildpackList) DeepCopy() *BuildpackList {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildpackList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BuildpackList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildpackObservation) DeepCopyInto(out *BuildpackObservation) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(string)
+		**out = **in
+	}
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.State != nil {
+		in, out := &in.State, &out.State
+		*out = new(string)
+		**out = **in
+	}
+	if in.Filename != nil {
+		in, out := &in.Filename, &out.Filename
+		*out = new(string)
+		**out = **in
+	}
+	if in.Stack != nil {
+		in, out := &in.Stack, &out.Stack
+		*out = new(string)
+		**out = **in
+	}
+	if in.Position != nil {
+		in, out := &in.Position, &out.Position
+		*out = new(int)
+		**out = **in
+	}
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Locked != nil {
+		in, out := &in.Locked, &out.Locked
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(string)
+		**out = **in
+	}
+	if in.CreatedAt != nil {
+		in, out := &in.CreatedAt, &out.CreatedAt
+		*out = new(string)
+		**out = **in
+	}
+	if in.UpdatedAt != nil {
+		in, out := &in.UpdatedAt, &out.UpdatedAt
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildpackObservation.
+func (in *BuildpackObservation) DeepCopy() *BuildpackObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildpackObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildpackParameters) DeepCopyInto(out *BuildpackParameters) {
+	*out = *in
+	if in.Position != nil {
+		in, out := &in.Position, &out.Position
+		*out = new(int)
+		**out = **in
+	}
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Locked != nil {
+		in, out := &in.Locked, &out.Locked
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Stack != nil {
+		in, out := &in.Stack, &out.Stack
+		*out = new(string)
+		**out = **in
+	}
+	if in.StackRef != nil {
+		in, out := &in.StackRef, &out.StackRef
+		*out = new(v1.NamespacedReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StackSelector != nil {
+		in, out := &in.StackSelector, &out.StackSelector
+		*out = new(v1.NamespacedSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildpackParameters.
+func (in *BuildpackParameters) DeepCopy() *BuildpackParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildpackParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildpackSpec) DeepCopyInto(out *BuildpackSpec) {
+	*out = *in
+	in.ManagedResourceSpec.DeepCopyInto(&out.ManagedResourceSpec)
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildpackSpec.
+func (in *BuildpackSpec) DeepCopy() *BuildpackSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildpackSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildpackStatus) DeepCopyInto(out *BuildpackStatus) {
+	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildpackStatus.
+func (in *BuildpackStatus) DeepCopy() *BuildpackStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildpackStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapKeySelector) DeepCopyInto(out *ConfigMapKeySelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapKeySelector.
+func (in *ConfigMapKeySelector) DeepCopy() *ConfigMapKeySelector {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapKeySelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Data) DeepCopyInto(out *Data) {
 	*out = *in
@@ -219,6 +503,36 @@ func (in *Data) DeepCopy() *Data {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Dependency) DeepCopyInto(out *Dependency) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Dependency.
+func (in *Dependency) DeepCopy() *Dependency {
+	if in == nil {
+		return nil
+	}
+	out := new(Dependency)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeploymentObservation) DeepCopyInto(out *DeploymentObservation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentObservation.
+func (in *DeploymentObservation) DeepCopy() *DeploymentObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(DeploymentObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DockerConfiguration) DeepCopyInto(out *DockerConfiguration) {
 	*out = *in
@@ -550,6 +864,31 @@ func (in *DomainStatus) DeepCopy() *DomainStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvironmentSource) DeepCopyInto(out *EnvironmentSource) {
+	*out = *in
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(LocalObjectReference)
+		**out = **in
+	}
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentSource.
+func (in *EnvironmentSource) DeepCopy() *EnvironmentSource {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvironmentSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HealthCheckConfiguration) DeepCopyInto(out *HealthCheckConfiguration) {
 	*out = *in
@@ -642,14 +981,29 @@ func (in Links) DeepCopy() Links {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MaintenanceInfo) DeepCopyInto(out *MaintenanceInfo) {
+func (in *LocalObjectReference) DeepCopyInto(out *LocalObjectReference) {
 	*out = *in
-	if in.Description != nil {
-		in, out := &in.Description, &out.Description
-		*out = new(string)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalObjectReference.
+func (in *LocalObjectReference) DeepCopy() *LocalObjectReference {
+	if in == nil {
+		return nil
 	}
-	if in.Version != nil {
+	out := new(LocalObjectReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceInfo) DeepCopyInto(out *MaintenanceInfo) {
+	*out = *in
+	if in.Description != nil {
+		in, out := &in.Description, &out.Description
+		*out = new(string)
+		**out = **in
+	}
+	if in.Version != nil {
 		in, out := &in.Version, &out.Version
 		*out = new(string)
 		**out = **in
@@ -684,12 +1038,29 @@ func (in *Managed) DeepCopyInto(out *Managed) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.YAMLParams != nil {
+		in, out := &in.YAMLParams, &out.YAMLParams
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ParametersSecretRef != nil {
 		in, out := &in.ParametersSecretRef, &out.ParametersSecretRef
 		*out = new(SecretKeySelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ParametersConfigMapRef != nil {
+		in, out := &in.ParametersConfigMapRef, &out.ParametersConfigMapRef
+		*out = new(ConfigMapKeySelector)
+		**out = **in
+	}
 	in.MaintenanceInfo.DeepCopyInto(&out.MaintenanceInfo)
+	if in.ContextMetadata != nil {
+		in, out := &in.ContextMetadata, &out.ContextMetadata
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Managed.
@@ -702,6 +1073,31 @@ func (in *Managed) DeepCopy() *Managed {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedMetadata) DeepCopyInto(out *ManagedMetadata) {
+	*out = *in
+	if in.ManagedAnnotations != nil {
+		in, out := &in.ManagedAnnotations, &out.ManagedAnnotations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ManagedLabels != nil {
+		in, out := &in.ManagedLabels, &out.ManagedLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedMetadata.
+func (in *ManagedMetadata) DeepCopy() *ManagedMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Member) DeepCopyInto(out *Member) {
 	*out = *in
@@ -743,6 +1139,26 @@ func (in *MemberList) DeepCopy() *MemberList {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObservedSpec) DeepCopyInto(out *ObservedSpec) {
+	*out = *in
+	if in.SpecHash != nil {
+		in, out := &in.SpecHash, &out.SpecHash
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservedSpec.
+func (in *ObservedSpec) DeepCopy() *ObservedSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ObservedSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OrgMembers) DeepCopyInto(out *OrgMembers) {
 	*out = *in
@@ -918,6 +1334,7 @@ func (in *OrgObservation) DeepCopyInto(out *OrgObservation) {
 		*out = new(string)
 		**out = **in
 	}
+	in.ManagedMetadata.DeepCopyInto(&out.ManagedMetadata)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrgObservation.
@@ -970,6 +1387,21 @@ func (in *OrgParameters) DeepCopyInto(out *OrgParameters) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Quota != nil {
+		in, out := &in.Quota, &out.Quota
+		*out = new(string)
+		**out = **in
+	}
+	if in.QuotaRef != nil {
+		in, out := &in.QuotaRef, &out.QuotaRef
+		*out = new(v1.NamespacedReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.QuotaSelector != nil {
+		in, out := &in.QuotaSelector, &out.QuotaSelector
+		*out = new(v1.NamespacedSelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrgParameters.
@@ -1029,13 +1461,9 @@ func (in *OrgQuotaInitParameters) DeepCopyInto(out *OrgQuotaInitParameters) {
 	}
 	if in.Orgs != nil {
 		in, out := &in.Orgs, &out.Orgs
-		*out = make([]*string, len(*in))
+		*out = make([]OrgReference, len(*in))
 		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = new(string)
-				**out = **in
-			}
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 	if in.TotalAppInstances != nil {
@@ -1241,6 +1669,11 @@ func (in *OrgQuotaParameters) DeepCopyInto(out *OrgQuotaParameters) {
 		*out = new(float64)
 		**out = **in
 	}
+	if in.InstanceMemoryWithUnit != nil {
+		in, out := &in.InstanceMemoryWithUnit, &out.InstanceMemoryWithUnit
+		*out = new(string)
+		**out = **in
+	}
 	if in.Name != nil {
 		in, out := &in.Name, &out.Name
 		*out = new(string)
@@ -1248,13 +1681,9 @@ func (in *OrgQuotaParameters) DeepCopyInto(out *OrgQuotaParameters) {
 	}
 	if in.Orgs != nil {
 		in, out := &in.Orgs, &out.Orgs
-		*out = make([]*string, len(*in))
+		*out = make([]OrgReference, len(*in))
 		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = new(string)
-				**out = **in
-			}
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 	if in.TotalAppInstances != nil {
@@ -1277,6 +1706,11 @@ func (in *OrgQuotaParameters) DeepCopyInto(out *OrgQuotaParameters) {
 		*out = new(float64)
 		**out = **in
 	}
+	if in.TotalMemoryWithUnit != nil {
+		in, out := &in.TotalMemoryWithUnit, &out.TotalMemoryWithUnit
+		*out = new(string)
+		**out = **in
+	}
 	if in.TotalPrivateDomains != nil {
 		in, out := &in.TotalPrivateDomains, &out.TotalPrivateDomains
 		*out = new(float64)
@@ -1481,6 +1915,7 @@ func (in *OrgRoleObservation) DeepCopyInto(out *OrgRoleObservation) {
 		*out = new(string)
 		**out = **in
 	}
+	in.RoleAssignments.DeepCopyInto(&out.RoleAssignments)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrgRoleObservation.
@@ -1502,6 +1937,11 @@ func (in *OrgRoleParameters) DeepCopyInto(out *OrgRoleParameters) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Usernames != nil {
+		in, out := &in.Usernames, &out.Usernames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrgRoleParameters.
@@ -1675,6 +2115,7 @@ func (in *ProcessConfiguration) DeepCopyInto(out *ProcessConfiguration) {
 		**out = **in
 	}
 	in.HealthCheckConfiguration.DeepCopyInto(&out.HealthCheckConfiguration)
+	in.ReadinessHealthCheckConfiguration.DeepCopyInto(&out.ReadinessHealthCheckConfiguration)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessConfiguration.
@@ -1933,6 +2374,16 @@ func (in *RouteDestination) DeepCopyInto(out *RouteDestination) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.Protocol != nil {
+		in, out := &in.Protocol, &out.Protocol
+		*out = new(string)
+		**out = **in
+	}
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(int)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteDestination.
@@ -1975,6 +2426,56 @@ func (in *RouteDestinationApp) DeepCopy() *RouteDestinationApp {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouteDestinationParameters) DeepCopyInto(out *RouteDestinationParameters) {
+	*out = *in
+	if in.App != nil {
+		in, out := &in.App, &out.App
+		*out = new(string)
+		**out = **in
+	}
+	if in.AppRef != nil {
+		in, out := &in.AppRef, &out.AppRef
+		*out = new(v1.NamespacedReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AppSelector != nil {
+		in, out := &in.AppSelector, &out.AppSelector
+		*out = new(v1.NamespacedSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Process != nil {
+		in, out := &in.Process, &out.Process
+		*out = new(string)
+		**out = **in
+	}
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int)
+		**out = **in
+	}
+	if in.Protocol != nil {
+		in, out := &in.Protocol, &out.Protocol
+		*out = new(string)
+		**out = **in
+	}
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteDestinationParameters.
+func (in *RouteDestinationParameters) DeepCopy() *RouteDestinationParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(RouteDestinationParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RouteList) DeepCopyInto(out *RouteList) {
 	*out = *in
@@ -2095,6 +2596,13 @@ func (in *RouteParameters) DeepCopyInto(out *RouteParameters) {
 		*out = new(RouteOptions)
 		**out = **in
 	}
+	if in.Destinations != nil {
+		in, out := &in.Destinations, &out.Destinations
+		*out = make([]RouteDestinationParameters, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteParameters.
@@ -2195,12 +2703,307 @@ func (in *SecretKeySelector) DeepCopyInto(out *SecretKeySelector) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeySelector.
-func (in *SecretKeySelector) DeepCopy() *SecretKeySelector {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeySelector.
+func (in *SecretKeySelector) DeepCopy() *SecretKeySelector {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretKeySelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityGroup) DeepCopyInto(out *SecurityGroup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityGroup.
+func (in *SecurityGroup) DeepCopy() *SecurityGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SecurityGroup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityGroupList) DeepCopyInto(out *SecurityGroupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SecurityGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityGroupList.
+func (in *SecurityGroupList) DeepCopy() *SecurityGroupList {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityGroupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SecurityGroupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityGroupObservation) DeepCopyInto(out *SecurityGroupObservation) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(string)
+		**out = **in
+	}
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]SecurityGroupRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.GloballyEnabledRunning != nil {
+		in, out := &in.GloballyEnabledRunning, &out.GloballyEnabledRunning
+		*out = new(bool)
+		**out = **in
+	}
+	if in.GloballyEnabledStaging != nil {
+		in, out := &in.GloballyEnabledStaging, &out.GloballyEnabledStaging
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RunningSpaces != nil {
+		in, out := &in.RunningSpaces, &out.RunningSpaces
+		*out = make([]*string, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(string)
+				**out = **in
+			}
+		}
+	}
+	if in.StagingSpaces != nil {
+		in, out := &in.StagingSpaces, &out.StagingSpaces
+		*out = make([]*string, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(string)
+				**out = **in
+			}
+		}
+	}
+	if in.ManagedRunningSpaces != nil {
+		in, out := &in.ManagedRunningSpaces, &out.ManagedRunningSpaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ManagedStagingSpaces != nil {
+		in, out := &in.ManagedStagingSpaces, &out.ManagedStagingSpaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CreatedAt != nil {
+		in, out := &in.CreatedAt, &out.CreatedAt
+		*out = new(string)
+		**out = **in
+	}
+	if in.UpdatedAt != nil {
+		in, out := &in.UpdatedAt, &out.UpdatedAt
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityGroupObservation.
+func (in *SecurityGroupObservation) DeepCopy() *SecurityGroupObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityGroupObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityGroupParameters) DeepCopyInto(out *SecurityGroupParameters) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]SecurityGroupRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.GloballyEnabledRunning != nil {
+		in, out := &in.GloballyEnabledRunning, &out.GloballyEnabledRunning
+		*out = new(bool)
+		**out = **in
+	}
+	if in.GloballyEnabledStaging != nil {
+		in, out := &in.GloballyEnabledStaging, &out.GloballyEnabledStaging
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RunningSpaces != nil {
+		in, out := &in.RunningSpaces, &out.RunningSpaces
+		*out = make([]*string, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(string)
+				**out = **in
+			}
+		}
+	}
+	if in.RunningSpacesRefs != nil {
+		in, out := &in.RunningSpacesRefs, &out.RunningSpacesRefs
+		*out = make([]v1.NamespacedReference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RunningSpacesSelector != nil {
+		in, out := &in.RunningSpacesSelector, &out.RunningSpacesSelector
+		*out = new(v1.NamespacedSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StagingSpaces != nil {
+		in, out := &in.StagingSpaces, &out.StagingSpaces
+		*out = make([]*string, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(string)
+				**out = **in
+			}
+		}
+	}
+	if in.StagingSpacesRefs != nil {
+		in, out := &in.StagingSpacesRefs, &out.StagingSpacesRefs
+		*out = make([]v1.NamespacedReference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StagingSpacesSelector != nil {
+		in, out := &in.StagingSpacesSelector, &out.StagingSpacesSelector
+		*out = new(v1.NamespacedSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityGroupParameters.
+func (in *SecurityGroupParameters) DeepCopy() *SecurityGroupParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityGroupParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityGroupRule) DeepCopyInto(out *SecurityGroupRule) {
+	*out = *in
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = new(string)
+		**out = **in
+	}
+	if in.Type != nil {
+		in, out := &in.Type, &out.Type
+		*out = new(int)
+		**out = **in
+	}
+	if in.Code != nil {
+		in, out := &in.Code, &out.Code
+		*out = new(int)
+		**out = **in
+	}
+	if in.Description != nil {
+		in, out := &in.Description, &out.Description
+		*out = new(string)
+		**out = **in
+	}
+	if in.Log != nil {
+		in, out := &in.Log, &out.Log
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityGroupRule.
+func (in *SecurityGroupRule) DeepCopy() *SecurityGroupRule {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityGroupRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityGroupSpec) DeepCopyInto(out *SecurityGroupSpec) {
+	*out = *in
+	in.ManagedResourceSpec.DeepCopyInto(&out.ManagedResourceSpec)
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityGroupSpec.
+func (in *SecurityGroupSpec) DeepCopy() *SecurityGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityGroupStatus) DeepCopyInto(out *SecurityGroupStatus) {
+	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityGroupStatus.
+func (in *SecurityGroupStatus) DeepCopy() *SecurityGroupStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretKeySelector)
+	out := new(SecurityGroupStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -2335,6 +3138,11 @@ func (in *ServiceCredentialBindingParameters) DeepCopyInto(out *ServiceCredentia
 		*out = new(string)
 		**out = **in
 	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]Dependency, len(*in))
+		copy(*out, *in)
+	}
 	if in.ServiceInstance != nil {
 		in, out := &in.ServiceInstance, &out.ServiceInstance
 		*out = new(string)
@@ -2365,6 +3173,26 @@ func (in *ServiceCredentialBindingParameters) DeepCopyInto(out *ServiceCredentia
 		*out = new(v1.NamespacedSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AppName != nil {
+		in, out := &in.AppName, &out.AppName
+		*out = new(string)
+		**out = **in
+	}
+	if in.AppSpace != nil {
+		in, out := &in.AppSpace, &out.AppSpace
+		*out = new(string)
+		**out = **in
+	}
+	if in.AppSpaceName != nil {
+		in, out := &in.AppSpaceName, &out.AppSpaceName
+		*out = new(string)
+		**out = **in
+	}
+	if in.AppOrgName != nil {
+		in, out := &in.AppOrgName, &out.AppOrgName
+		*out = new(string)
+		**out = **in
+	}
 	if in.Parameters != nil {
 		in, out := &in.Parameters, &out.Parameters
 		*out = new(runtime.RawExtension)
@@ -2380,6 +3208,13 @@ func (in *ServiceCredentialBindingParameters) DeepCopyInto(out *ServiceCredentia
 		*out = new(RotationParameters)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ConnectionDetailTemplates != nil {
+		in, out := &in.ConnectionDetailTemplates, &out.ConnectionDetailTemplates
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceCredentialBindingParameters.
@@ -2552,6 +3387,16 @@ func (in *ServiceInstanceObservation) DeepCopyInto(out *ServiceInstanceObservati
 		}
 	}
 	out.LastOperation = in.LastOperation
+	if in.Progress != nil {
+		in, out := &in.Progress, &out.Progress
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ProgressStep != nil {
+		in, out := &in.ProgressStep, &out.ProgressStep
+		*out = new(string)
+		**out = **in
+	}
 	in.MaintenanceInfo.DeepCopyInto(&out.MaintenanceInfo)
 	if in.DashboardURL != nil {
 		in, out := &in.DashboardURL, &out.DashboardURL
@@ -2594,6 +3439,27 @@ func (in *ServiceInstanceObservation) DeepCopyInto(out *ServiceInstanceObservati
 		*out = new(bool)
 		**out = **in
 	}
+	if in.DryRunPayload != nil {
+		in, out := &in.DryRunPayload, &out.DryRunPayload
+		*out = new(string)
+		**out = **in
+	}
+	if in.RedactedParameters != nil {
+		in, out := &in.RedactedParameters, &out.RedactedParameters
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastCreateFailure != nil {
+		in, out := &in.LastCreateFailure, &out.LastCreateFailure
+		*out = (*in).DeepCopy()
+	}
+	if in.CreateFailureSpecHash != nil {
+		in, out := &in.CreateFailureSpecHash, &out.CreateFailureSpecHash
+		*out = new(string)
+		**out = **in
+	}
+	in.ObservedSpec.DeepCopyInto(&out.ObservedSpec)
+	in.ManagedMetadata.DeepCopyInto(&out.ManagedMetadata)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceInstanceObservation.
@@ -2629,22 +3495,7 @@ func (in *ServiceInstanceParameters) DeepCopyInto(out *ServiceInstanceParameters
 			}
 		}
 	}
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]*string, len(*in))
-		for key, val := range *in {
-			var outVal *string
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				inVal := (*in)[key]
-				in, out := &inVal, &outVal
-				*out = new(string)
-				**out = **in
-			}
-			(*out)[key] = outVal
-		}
-	}
+	in.ResourceMetadata.DeepCopyInto(&out.ResourceMetadata)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceInstanceParameters.
@@ -2687,6 +3538,11 @@ func (in *ServiceInstanceSpec) DeepCopyInto(out *ServiceInstanceSpec) {
 	*out = *in
 	in.ManagedResourceSpec.DeepCopyInto(&out.ManagedResourceSpec)
 	in.ForProvider.DeepCopyInto(&out.ForProvider)
+	if in.RequestTimeout != nil {
+		in, out := &in.RequestTimeout, &out.RequestTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceInstanceSpec.
@@ -3116,6 +3972,13 @@ func (in *SpaceObservation) DeepCopyInto(out *SpaceObservation) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Features != nil {
+		in, out := &in.Features, &out.Features
+		*out = make(map[string]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.IsolationSegment != nil {
 		in, out := &in.IsolationSegment, &out.IsolationSegment
 		*out = new(string)
@@ -3142,11 +4005,27 @@ func (in *SpaceObservation) DeepCopyInto(out *SpaceObservation) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.RunningSecurityGroups != nil {
+		in, out := &in.RunningSecurityGroups, &out.RunningSecurityGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StagingSecurityGroups != nil {
+		in, out := &in.StagingSecurityGroups, &out.StagingSecurityGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.UpdatedAt != nil {
 		in, out := &in.UpdatedAt, &out.UpdatedAt
 		*out = new(string)
 		**out = **in
 	}
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = new(SpaceUsage)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ManagedMetadata.DeepCopyInto(&out.ManagedMetadata)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpaceObservation.
@@ -3178,11 +4057,23 @@ func (in *SpaceParameters) DeepCopyInto(out *SpaceParameters) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.Features != nil {
+		in, out := &in.Features, &out.Features
+		*out = make(map[string]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.IsolationSegment != nil {
 		in, out := &in.IsolationSegment, &out.IsolationSegment
 		*out = new(string)
 		**out = **in
 	}
+	if in.IsolationSegmentName != nil {
+		in, out := &in.IsolationSegmentName, &out.IsolationSegmentName
+		*out = new(string)
+		**out = **in
+	}
 	if in.Labels != nil {
 		in, out := &in.Labels, &out.Labels
 		*out = make(map[string]*string, len(*in))
@@ -3199,6 +4090,16 @@ func (in *SpaceParameters) DeepCopyInto(out *SpaceParameters) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.RunningSecurityGroups != nil {
+		in, out := &in.RunningSecurityGroups, &out.RunningSecurityGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StagingSecurityGroups != nil {
+		in, out := &in.StagingSecurityGroups, &out.StagingSecurityGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	in.OrgReference.DeepCopyInto(&out.OrgReference)
 }
 
@@ -3493,6 +4394,11 @@ func (in *SpaceQuotaParameters) DeepCopyInto(out *SpaceQuotaParameters) {
 		*out = new(float64)
 		**out = **in
 	}
+	if in.InstanceMemoryWithUnit != nil {
+		in, out := &in.InstanceMemoryWithUnit, &out.InstanceMemoryWithUnit
+		*out = new(string)
+		**out = **in
+	}
 	if in.Name != nil {
 		in, out := &in.Name, &out.Name
 		*out = new(string)
@@ -3503,6 +4409,11 @@ func (in *SpaceQuotaParameters) DeepCopyInto(out *SpaceQuotaParameters) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.OrgName != nil {
+		in, out := &in.OrgName, &out.OrgName
+		*out = new(string)
+		**out = **in
+	}
 	if in.OrgRef != nil {
 		in, out := &in.OrgRef, &out.OrgRef
 		*out = new(v1.NamespacedReference)
@@ -3556,6 +4467,11 @@ func (in *SpaceQuotaParameters) DeepCopyInto(out *SpaceQuotaParameters) {
 		*out = new(float64)
 		**out = **in
 	}
+	if in.TotalMemoryWithUnit != nil {
+		in, out := &in.TotalMemoryWithUnit, &out.TotalMemoryWithUnit
+		*out = new(string)
+		**out = **in
+	}
 	if in.TotalRoutePorts != nil {
 		in, out := &in.TotalRoutePorts, &out.TotalRoutePorts
 		*out = new(float64)
@@ -3760,6 +4676,7 @@ func (in *SpaceRoleObservation) DeepCopyInto(out *SpaceRoleObservation) {
 		*out = new(string)
 		**out = **in
 	}
+	in.RoleAssignments.DeepCopyInto(&out.RoleAssignments)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpaceRoleObservation.
@@ -3781,6 +4698,11 @@ func (in *SpaceRoleParameters) DeepCopyInto(out *SpaceRoleParameters) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Usernames != nil {
+		in, out := &in.Usernames, &out.Usernames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpaceRoleParameters.
@@ -3861,6 +4783,204 @@ func (in *SpaceStatus) DeepCopy() *SpaceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpaceUsage) DeepCopyInto(out *SpaceUsage) {
+	*out = *in
+	if in.MemoryInMB != nil {
+		in, out := &in.MemoryInMB, &out.MemoryInMB
+		*out = new(int)
+		**out = **in
+	}
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = new(int)
+		**out = **in
+	}
+	if in.StartedInstances != nil {
+		in, out := &in.StartedInstances, &out.StartedInstances
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpaceUsage.
+func (in *SpaceUsage) DeepCopy() *SpaceUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(SpaceUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Stack) DeepCopyInto(out *Stack) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Stack.
+func (in *Stack) DeepCopy() *Stack {
+	if in == nil {
+		return nil
+	}
+	out := new(Stack)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Stack) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StackList) DeepCopyInto(out *StackList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Stack, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StackList.
+func (in *StackList) DeepCopy() *StackList {
+	if in == nil {
+		return nil
+	}
+	out := new(StackList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StackList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StackObservation) DeepCopyInto(out *StackObservation) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(string)
+		**out = **in
+	}
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.Description != nil {
+		in, out := &in.Description, &out.Description
+		*out = new(string)
+		**out = **in
+	}
+	if in.RunRootfsImage != nil {
+		in, out := &in.RunRootfsImage, &out.RunRootfsImage
+		*out = new(string)
+		**out = **in
+	}
+	if in.BuildRootfsImage != nil {
+		in, out := &in.BuildRootfsImage, &out.BuildRootfsImage
+		*out = new(string)
+		**out = **in
+	}
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CreatedAt != nil {
+		in, out := &in.CreatedAt, &out.CreatedAt
+		*out = new(string)
+		**out = **in
+	}
+	if in.UpdatedAt != nil {
+		in, out := &in.UpdatedAt, &out.UpdatedAt
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StackObservation.
+func (in *StackObservation) DeepCopy() *StackObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(StackObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StackParameters) DeepCopyInto(out *StackParameters) {
+	*out = *in
+	if in.Description != nil {
+		in, out := &in.Description, &out.Description
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StackParameters.
+func (in *StackParameters) DeepCopy() *StackParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(StackParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StackSpec) DeepCopyInto(out *StackSpec) {
+	*out = *in
+	in.ManagedResourceSpec.DeepCopyInto(&out.ManagedResourceSpec)
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StackSpec.
+func (in *StackSpec) DeepCopy() *StackSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StackSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StackStatus) DeepCopyInto(out *StackStatus) {
+	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StackStatus.
+func (in *StackStatus) DeepCopy() *StackStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StackStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TimeoutsParameters) DeepCopyInto(out *TimeoutsParameters) {
 	*out = *in
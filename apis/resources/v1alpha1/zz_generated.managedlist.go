@@ -29,6 +29,15 @@ func (l *AppList) GetItems() []resource.Managed {
 	return items
 }
 
+// GetItems of this BuildpackList.
+func (l *BuildpackList) GetItems() []resource.Managed {
+	items := make([]resource.Managed, len(l.Items))
+	for i := range l.Items {
+		items[i] = &l.Items[i]
+	}
+	return items
+}
+
 // GetItems of this DomainList.
 func (l *DomainList) GetItems() []resource.Managed {
 	items := make([]resource.Managed, len(l.Items))
@@ -83,6 +92,15 @@ func (l *RouteList) GetItems() []resource.Managed {
 	return items
 }
 
+// GetItems of this SecurityGroupList.
+func (l *SecurityGroupList) GetItems() []resource.Managed {
+	items := make([]resource.Managed, len(l.Items))
+	for i := range l.Items {
+		items[i] = &l.Items[i]
+	}
+	return items
+}
+
 // GetItems of this ServiceCredentialBindingList.
 func (l *ServiceCredentialBindingList) GetItems() []resource.Managed {
 	items := make([]resource.Managed, len(l.Items))
@@ -145,3 +163,12 @@ func (l *SpaceRoleList) GetItems() []resource.Managed {
 	}
 	return items
 }
+
+// GetItems of this StackList.
+func (l *StackList) GetItems() []resource.Managed {
+	items := make([]resource.Managed, len(l.Items))
+	for i := range l.Items {
+		items[i] = &l.Items[i]
+	}
+	return items
+}
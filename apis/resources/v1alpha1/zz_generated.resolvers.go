@@ -31,6 +31,7 @@ func (mg *App) ResolveReferences(ctx context.Context, c client.Reader) error {
 	r := reference.NewAPINamespacedResolver(c, mg)
 
 	var rsp reference.NamespacedResolutionResponse
+	var mrsp reference.MultiNamespacedResolutionResponse
 	var err error
 
 	rsp, err = r.Resolve(ctx, reference.NamespacedResolutionRequest{
@@ -50,6 +51,40 @@ func (mg *App) ResolveReferences(ctx context.Context, c client.Reader) error {
 	mg.Spec.ForProvider.SpaceReference.Space = reference.ToPtrValue(rsp.ResolvedValue)
 	mg.Spec.ForProvider.SpaceReference.SpaceRef = rsp.ResolvedReference
 
+	mrsp, err = r.ResolveMultiple(ctx, reference.MultiNamespacedResolutionRequest{
+		CurrentValues: mg.Spec.ForProvider.Buildpacks,
+		Extract:       resources.CloudFoundryName(),
+		Namespace:     mg.GetNamespace(),
+		References:    mg.Spec.ForProvider.BuildpackRefs,
+		Selector:      mg.Spec.ForProvider.BuildpackSelector,
+		To: reference.To{
+			List:    &BuildpackList{},
+			Managed: &Buildpack{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.ForProvider.Buildpacks")
+	}
+	mg.Spec.ForProvider.Buildpacks = mrsp.ResolvedValues
+	mg.Spec.ForProvider.BuildpackRefs = mrsp.ResolvedReferences
+
+	rsp, err = r.Resolve(ctx, reference.NamespacedResolutionRequest{
+		CurrentValue: reference.FromPtrValue(mg.Spec.ForProvider.Stack),
+		Extract:      resources.CloudFoundryName(),
+		Namespace:    mg.GetNamespace(),
+		Reference:    mg.Spec.ForProvider.StackRef,
+		Selector:     mg.Spec.ForProvider.StackSelector,
+		To: reference.To{
+			List:    &StackList{},
+			Managed: &Stack{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.ForProvider.Stack")
+	}
+	mg.Spec.ForProvider.Stack = reference.ToPtrValue(rsp.ResolvedValue)
+	mg.Spec.ForProvider.StackRef = rsp.ResolvedReference
+
 	for i3 := 0; i3 < len(mg.Spec.ForProvider.Routes); i3++ {
 		rsp, err = r.Resolve(ctx, reference.NamespacedResolutionRequest{
 			CurrentValue: reference.FromPtrValue(mg.Spec.ForProvider.Routes[i3].Route),
@@ -92,6 +127,33 @@ func (mg *App) ResolveReferences(ctx context.Context, c client.Reader) error {
 	return nil
 }
 
+// ResolveReferences of this Buildpack.
+func (mg *Buildpack) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPINamespacedResolver(c, mg)
+
+	var rsp reference.NamespacedResolutionResponse
+	var err error
+
+	rsp, err = r.Resolve(ctx, reference.NamespacedResolutionRequest{
+		CurrentValue: reference.FromPtrValue(mg.Spec.ForProvider.Stack),
+		Extract:      resources.CloudFoundryName(),
+		Namespace:    mg.GetNamespace(),
+		Reference:    mg.Spec.ForProvider.StackRef,
+		Selector:     mg.Spec.ForProvider.StackSelector,
+		To: reference.To{
+			List:    &StackList{},
+			Managed: &Stack{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.ForProvider.Stack")
+	}
+	mg.Spec.ForProvider.Stack = reference.ToPtrValue(rsp.ResolvedValue)
+	mg.Spec.ForProvider.StackRef = rsp.ResolvedReference
+
+	return nil
+}
+
 // ResolveReferences of this Domain.
 func (mg *Domain) ResolveReferences(ctx context.Context, c client.Reader) error {
 	r := reference.NewAPINamespacedResolver(c, mg)
@@ -146,6 +208,55 @@ func (mg *OrgMembers) ResolveReferences(ctx context.Context, c client.Reader) er
 	return nil
 }
 
+// ResolveReferences of this OrgQuota.
+func (mg *OrgQuota) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPINamespacedResolver(c, mg)
+
+	var rsp reference.NamespacedResolutionResponse
+	var err error
+
+	for i3 := 0; i3 < len(mg.Spec.ForProvider.Orgs); i3++ {
+		rsp, err = r.Resolve(ctx, reference.NamespacedResolutionRequest{
+			CurrentValue: reference.FromPtrValue(mg.Spec.ForProvider.Orgs[i3].Org),
+			Extract:      resources.ExternalID(),
+			Namespace:    mg.GetNamespace(),
+			Reference:    mg.Spec.ForProvider.Orgs[i3].OrgRef,
+			Selector:     mg.Spec.ForProvider.Orgs[i3].OrgSelector,
+			To: reference.To{
+				List:    &OrganizationList{},
+				Managed: &Organization{},
+			},
+		})
+		if err != nil {
+			return errors.Wrap(err, "mg.Spec.ForProvider.Orgs[i3].Org")
+		}
+		mg.Spec.ForProvider.Orgs[i3].Org = reference.ToPtrValue(rsp.ResolvedValue)
+		mg.Spec.ForProvider.Orgs[i3].OrgRef = rsp.ResolvedReference
+
+	}
+	for i3 := 0; i3 < len(mg.Spec.InitProvider.Orgs); i3++ {
+		rsp, err = r.Resolve(ctx, reference.NamespacedResolutionRequest{
+			CurrentValue: reference.FromPtrValue(mg.Spec.InitProvider.Orgs[i3].Org),
+			Extract:      resources.ExternalID(),
+			Namespace:    mg.GetNamespace(),
+			Reference:    mg.Spec.InitProvider.Orgs[i3].OrgRef,
+			Selector:     mg.Spec.InitProvider.Orgs[i3].OrgSelector,
+			To: reference.To{
+				List:    &OrganizationList{},
+				Managed: &Organization{},
+			},
+		})
+		if err != nil {
+			return errors.Wrap(err, "mg.Spec.InitProvider.Orgs[i3].Org")
+		}
+		mg.Spec.InitProvider.Orgs[i3].Org = reference.ToPtrValue(rsp.ResolvedValue)
+		mg.Spec.InitProvider.Orgs[i3].OrgRef = rsp.ResolvedReference
+
+	}
+
+	return nil
+}
+
 // ResolveReferences of this OrgRole.
 func (mg *OrgRole) ResolveReferences(ctx context.Context, c client.Reader) error {
 	r := reference.NewAPINamespacedResolver(c, mg)
@@ -173,6 +284,33 @@ func (mg *OrgRole) ResolveReferences(ctx context.Context, c client.Reader) error
 	return nil
 }
 
+// ResolveReferences of this Organization.
+func (mg *Organization) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPINamespacedResolver(c, mg)
+
+	var rsp reference.NamespacedResolutionResponse
+	var err error
+
+	rsp, err = r.Resolve(ctx, reference.NamespacedResolutionRequest{
+		CurrentValue: reference.FromPtrValue(mg.Spec.ForProvider.Quota),
+		Extract:      resources.ExternalID(),
+		Namespace:    mg.GetNamespace(),
+		Reference:    mg.Spec.ForProvider.QuotaRef,
+		Selector:     mg.Spec.ForProvider.QuotaSelector,
+		To: reference.To{
+			List:    &OrgQuotaList{},
+			Managed: &OrgQuota{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.ForProvider.Quota")
+	}
+	mg.Spec.ForProvider.Quota = reference.ToPtrValue(rsp.ResolvedValue)
+	mg.Spec.ForProvider.QuotaRef = rsp.ResolvedReference
+
+	return nil
+}
+
 // ResolveReferences of this Route.
 func (mg *Route) ResolveReferences(ctx context.Context, c client.Reader) error {
 	r := reference.NewAPINamespacedResolver(c, mg)
@@ -214,6 +352,70 @@ func (mg *Route) ResolveReferences(ctx context.Context, c client.Reader) error {
 	mg.Spec.ForProvider.DomainReference.Domain = reference.ToPtrValue(rsp.ResolvedValue)
 	mg.Spec.ForProvider.DomainReference.DomainRef = rsp.ResolvedReference
 
+	for i3 := 0; i3 < len(mg.Spec.ForProvider.Destinations); i3++ {
+		rsp, err = r.Resolve(ctx, reference.NamespacedResolutionRequest{
+			CurrentValue: reference.FromPtrValue(mg.Spec.ForProvider.Destinations[i3].App),
+			Extract:      reference.ExternalName(),
+			Namespace:    mg.GetNamespace(),
+			Reference:    mg.Spec.ForProvider.Destinations[i3].AppRef,
+			Selector:     mg.Spec.ForProvider.Destinations[i3].AppSelector,
+			To: reference.To{
+				List:    &AppList{},
+				Managed: &App{},
+			},
+		})
+		if err != nil {
+			return errors.Wrap(err, "mg.Spec.ForProvider.Destinations[i3].App")
+		}
+		mg.Spec.ForProvider.Destinations[i3].App = reference.ToPtrValue(rsp.ResolvedValue)
+		mg.Spec.ForProvider.Destinations[i3].AppRef = rsp.ResolvedReference
+
+	}
+
+	return nil
+}
+
+// ResolveReferences of this SecurityGroup.
+func (mg *SecurityGroup) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPINamespacedResolver(c, mg)
+
+	var mrsp reference.MultiNamespacedResolutionResponse
+	var err error
+
+	mrsp, err = r.ResolveMultiple(ctx, reference.MultiNamespacedResolutionRequest{
+		CurrentValues: reference.FromPtrValues(mg.Spec.ForProvider.RunningSpaces),
+		Extract:       resources.ExternalID(),
+		Namespace:     mg.GetNamespace(),
+		References:    mg.Spec.ForProvider.RunningSpacesRefs,
+		Selector:      mg.Spec.ForProvider.RunningSpacesSelector,
+		To: reference.To{
+			List:    &SpaceList{},
+			Managed: &Space{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.ForProvider.RunningSpaces")
+	}
+	mg.Spec.ForProvider.RunningSpaces = reference.ToPtrValues(mrsp.ResolvedValues)
+	mg.Spec.ForProvider.RunningSpacesRefs = mrsp.ResolvedReferences
+
+	mrsp, err = r.ResolveMultiple(ctx, reference.MultiNamespacedResolutionRequest{
+		CurrentValues: reference.FromPtrValues(mg.Spec.ForProvider.StagingSpaces),
+		Extract:       resources.ExternalID(),
+		Namespace:     mg.GetNamespace(),
+		References:    mg.Spec.ForProvider.StagingSpacesRefs,
+		Selector:      mg.Spec.ForProvider.StagingSpacesSelector,
+		To: reference.To{
+			List:    &SpaceList{},
+			Managed: &Space{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.ForProvider.StagingSpaces")
+	}
+	mg.Spec.ForProvider.StagingSpaces = reference.ToPtrValues(mrsp.ResolvedValues)
+	mg.Spec.ForProvider.StagingSpacesRefs = mrsp.ResolvedReferences
+
 	return nil
 }
 
@@ -19,8 +19,54 @@ type ProviderConfigSpec struct {
 	// Endpoint provides the connection details
 	// +kubebuilder:validation:Optional
 	Endpoint *EndpointConfig `json:"endpoint"`
+	// AllowedAPIEndpoints are further CF APIs that the managed resources using
+	// this ProviderConfig may target with the
+	// `cloudfoundry.crossplane.io/api-endpoint` annotation, e.g. the staging
+	// and production APIs of a foundation. The credentials of this
+	// ProviderConfig are sent to these APIs.
+	// +kubebuilder:validation:Optional
+	AllowedAPIEndpoints []string `json:"allowedAPIEndpoints,omitempty"`
+	// AllowedBuildpackSources are the https URL prefixes, e.g.
+	// `https://github.com/cloudfoundry/`, that the sources of the Buildpacks
+	// using this ProviderConfig must start with. The provider downloads the
+	// bits of these Buildpacks only from these URLs. Buildpacks with a source
+	// cannot be uploaded if unset.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:items:Pattern=`^https://`
+	AllowedBuildpackSources []string `json:"allowedBuildpackSources,omitempty"`
 	// Credentials required to authenticate to this provider.
 	Credentials ProviderCredentials `json:"credentials"`
+	// Retry configures the retries of requests to the CF API that fail because the API is rate limited or unavailable.
+	// Requests are not retried if unset.
+	// +kubebuilder:validation:Optional
+	Retry *RetryConfig `json:"retry,omitempty"`
+	// PollInterval is the default interval at which the managed resources
+	// using this ProviderConfig are observed. Values below 10s are raised to
+	// 10s; defaults to the poll interval of the provider if unset.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+	PollInterval *metav1.Duration `json:"pollInterval,omitempty"`
+	// OperationTimeout is the default time an observe, create, update or
+	// delete of a managed resource using this ProviderConfig may take.
+	// Managed resources that set their own timeout take precedence. Capped
+	// at 30m; defaults to the timeout of the controller if unset.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+	OperationTimeout *metav1.Duration `json:"operationTimeout,omitempty"`
+}
+
+// RetryConfig configures the retries of requests to the CF API.
+// Only idempotent GET and HEAD requests are retried, when the CF API responds
+// with 429 (Too Many Requests), 502, 503 or 504.
+type RetryConfig struct {
+	// MaxRetries is the maximum number of times a request is retried.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10
+	// +kubebuilder:default=3
+	MaxRetries int `json:"maxRetries,omitempty"`
 }
 
 // EndpointConfig is used to configure cf API endpoint.
@@ -42,6 +88,19 @@ type ProviderCredentials struct {
 // A ProviderConfigStatus reflects the observed state of a ProviderConfig.
 type ProviderConfigStatus struct {
 	xpv1.ProviderConfigStatus `json:",inline"`
+
+	// CloudControllerVersion is the version of the Cloud Controller V3 API
+	// that the provider connects to with this ProviderConfig.
+	// +optional
+	CloudControllerVersion string `json:"cloudControllerVersion,omitempty"`
+	// UAAVersion is the version of the UAA that authenticates the provider
+	// with this ProviderConfig.
+	// +optional
+	UAAVersion string `json:"uaaVersion,omitempty"`
+	// VersionsObservedAt is the time the versions were last observed. They
+	// are refreshed periodically while the ProviderConfig is in use.
+	// +optional
+	VersionsObservedAt *metav1.Time `json:"versionsObservedAt,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -50,6 +109,7 @@ type ProviderConfigStatus struct {
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="SECRET-NAME",type="string",JSONPath=".spec.credentials.secretRef.name",priority=1
+// +kubebuilder:printcolumn:name="CF-API-VERSION",type="string",JSONPath=".status.cloudControllerVersion",priority=1
 // +kubebuilder:resource:scope=Namespaced,categories={crossplane,provider,cloudfoundry}
 type ProviderConfig struct {
 	metav1.TypeMeta   `json:",inline"`
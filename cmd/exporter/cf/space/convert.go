@@ -37,13 +37,13 @@ func convertSpaceResource(ctx context.Context, cfClient *client.Client, space *r
 			},
 			ForProvider: v1alpha1.SpaceParameters{
 				// AllowSSH:         false,
-				Annotations:      space.Metadata.Annotations,
 				IsolationSegment: new(string),
-				Labels:           space.Metadata.Labels,
 				Name:             space.Name,
 				OrgReference: v1alpha1.OrgReference{
 					Org: &space.Relationships.Organization.Data.GUID,
 				},
+				Annotations: space.Metadata.Annotations,
+				Labels:      space.Metadata.Labels,
 			},
 		},
 	}
@@ -13,7 +13,9 @@ import (
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/feature"
@@ -21,7 +23,12 @@ import (
 	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
 
 	"github.com/SAP/crossplane-provider-cloudfoundry/apis"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/role"
 	provider "github.com/SAP/crossplane-provider-cloudfoundry/internal/controller"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/controller/servicecredentialbinding"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/metrics"
+	providerwebhook "github.com/SAP/crossplane-provider-cloudfoundry/internal/webhook"
 )
 
 func main() {
@@ -33,8 +40,32 @@ func main() {
 		syncInterval     = app.Flag("sync", "How often all resources will be double-checked for drift from the desired state.").Short('s').Default("1h").Duration()
 		pollInterval     = app.Flag("poll", "How often individual resources will be checked for drift from the desired state").Default("1m").Duration()
 		maxReconcileRate = app.Flag("max-reconcile-rate", "The global maximum rate per second at which resources may checked for drift from the desired state.").Default("10").Int()
+		roleCacheTTL     = app.Flag("role-cache-ttl", "How long role listings of an org or space are reused by OrgRole and SpaceRole reconciles. Zero disables the cache.").Default(role.DefaultCacheTTL.String()).Duration()
+		healthProbeAddr  = app.Flag("health-probe-bind-address", "The address the liveness and readiness probe endpoints bind to.").Default(":8081").String()
+		apiCheckInterval = app.Flag("api-check-interval", "How often the Cloud Foundry API is checked with every ProviderConfig. The result is exported as the provider_cloudfoundry_providerconfig_api_reachable metric.").Default(clients.DefaultReachabilityInterval.String()).Duration()
+		readAfterWrite   = app.Flag("read-after-write-timeout", "How long a Space or ServiceInstance lookup by name retries while Cloud Foundry does not list a just created resource yet. Zero disables the retry.").Default(clients.DefaultReadAfterWriteTimeout.String()).Duration()
+		webhookCertDir   = app.Flag("webhook-tls-cert-dir", "The directory with the TLS certificate and key that serve the admission webhooks. Crossplane provides it in TLS_SERVER_CERTS_DIR. The webhooks are disabled if unset.").Envar("TLS_SERVER_CERTS_DIR").String()
+
+		enableManagementPolicies = app.Flag("enable-management-policies", "Enable support for management policies, e.g. to only observe a resource with managementPolicies: [Observe].").Default("true").Bool()
+		enableRetiredKeyGC       = app.Flag("enable-retired-key-gc", "Periodically delete keys retired by a rotation whose ServiceCredentialBinding no longer exists.").Default("false").Bool()
+		retiredKeyGCInterval     = app.Flag("retired-key-gc-interval", "How often orphaned retired keys are collected.").Default(servicecredentialbinding.DefaultRetiredKeyGCInterval.String()).Duration()
+		retiredKeyRetention      = app.Flag("retired-key-retention", "How long after its retirement an orphaned retired key is kept before it is deleted.").Default(servicecredentialbinding.DefaultRetiredKeyRetention.String()).Duration()
+		enableRecoveryRequeue    = app.Flag("enable-recovery-requeue", "Requeue failed resources as soon as the Cloud Foundry API is reachable again after an outage, instead of at their next poll.").Default("false").Bool()
+		recoveryCheckInterval    = app.Flag("recovery-check-interval", "How often the reachability of the Cloud Foundry API is checked to requeue failed resources after an outage.").Default(provider.DefaultRecoveryCheckInterval.String()).Duration()
+
+		_           = app.Command("start", "Start the controller manager.").Default()
+		validateCmd = newValidateCommand(app)
 	)
-	kingpin.MustParse(app.Parse(os.Args[1:]))
+	if kingpin.MustParse(app.Parse(os.Args[1:])) == validateCmd.cmd.FullCommand() {
+		// Validating a ProviderConfig does not start the controller manager.
+		kingpin.FatalIfError(validateCmd.execute(os.Stdout), "ProviderConfig is not valid")
+		return
+	}
+	role.CacheTTL = *roleCacheTTL
+	clients.ReadAfterWriteTimeout = *readAfterWrite
+	servicecredentialbinding.RetiredKeyGC = *enableRetiredKeyGC
+	servicecredentialbinding.RetiredKeyGCInterval = *retiredKeyGCInterval
+	servicecredentialbinding.RetiredKeyRetention = *retiredKeyRetention
 
 	zl := zap.New(zap.UseDevMode(*debug))
 	log := logging.NewLogrLogger(zl.WithName("provider-cloudfoundry"))
@@ -67,9 +98,25 @@ func main() {
 		LeaderElectionResourceLock: resourcelock.LeasesResourceLock,
 		LeaseDuration:              func() *time.Duration { d := 60 * time.Second; return &d }(),
 		RenewDeadline:              func() *time.Duration { d := 50 * time.Second; return &d }(),
+
+		// The readiness and liveness probes only check that the manager is
+		// running. The reachability of the CF API with each ProviderConfig is
+		// exported as a metric instead.
+		HealthProbeBindAddress: *healthProbeAddr,
+
+		// The webhook server is only started if webhooks are set up.
+		WebhookServer: webhook.NewServer(webhook.Options{CertDir: *webhookCertDir}),
 	})
 	kingpin.FatalIfError(err, "Cannot create controller manager")
 	kingpin.FatalIfError(apis.AddToScheme(mgr.GetScheme()), "Cannot add onboarding APIs to scheme")
+	kingpin.FatalIfError(mgr.AddHealthzCheck("ping", healthz.Ping), "Cannot add liveness check")
+	kingpin.FatalIfError(mgr.AddReadyzCheck("ping", healthz.Ping), "Cannot add readiness check")
+	metrics.Register()
+	reachability := clients.NewReachabilityChecker(mgr.GetClient(), *apiCheckInterval, metrics.ReportProviderConfigReachability)
+	kingpin.FatalIfError(mgr.Add(reachability), "Cannot add Cloud Foundry API check")
+	if *enableRecoveryRequeue {
+		kingpin.FatalIfError(mgr.Add(provider.NewRecoveryRequeuer(mgr.GetClient(), reachability.CheckContext, *recoveryCheckInterval, log.WithValues("task", "recovery-requeue"))), "Cannot add recovery requeuer")
+	}
 
 	o := controller.Options{
 		Logger:                  log,
@@ -79,6 +126,14 @@ func main() {
 		Features:                &feature.Flags{},
 	}
 
+	if *enableManagementPolicies {
+		o.Features.Enable(feature.EnableBetaManagementPolicies)
+		log.Info("Beta feature enabled", "flag", feature.EnableBetaManagementPolicies)
+	}
+
 	kingpin.FatalIfError(provider.CustomSetup(mgr, o), "Cannot setup custom controllers")
+	if *webhookCertDir != "" {
+		kingpin.FatalIfError(providerwebhook.Setup(mgr), "Cannot setup webhooks")
+	}
 	kingpin.FatalIfError(mgr.Start(ctrl.SetupSignalHandler()), "Cannot start controller manager")
 }
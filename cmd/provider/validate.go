@@ -0,0 +1,149 @@
+/*
+Copyright 2023 SAP SE
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	cfv3 "github.com/cloudfoundry/go-cfclient/v3/client"
+	"gopkg.in/alecthomas/kingpin.v2"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis"
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/v1beta1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
+)
+
+// maxListed is the number of orgs and spaces printed by validate-config.
+const maxListed = 10
+
+// validateCommand authenticates with the CF API of a ProviderConfig without
+// starting the controller manager, so that operators can check a
+// ProviderConfig and its credentials before resources use it.
+type validateCommand struct {
+	cmd         *kingpin.CmdClause
+	kubeContext *string
+	namespace   *string
+	name        *string
+	timeout     *time.Duration
+}
+
+// newValidateCommand adds the validate-config command to app.
+func newValidateCommand(app *kingpin.Application) *validateCommand {
+	cmd := app.Command("validate-config", "Authenticate with the Cloud Foundry API of a ProviderConfig and print what its credentials can access.")
+	return &validateCommand{
+		cmd:         cmd,
+		kubeContext: cmd.Flag("context", "The kubeconfig context of the cluster the ProviderConfig is in. Defaults to the current context.").String(),
+		namespace:   cmd.Flag("namespace", "The namespace of the ProviderConfig.").Short('n').Default("default").String(),
+		name:        cmd.Arg("name", "The name of the ProviderConfig.").Required().String(),
+		timeout:     cmd.Flag("timeout", "How long the validation may take.").Default("1m").Duration(),
+	}
+}
+
+// execute validates the ProviderConfig in the cluster of the kubeconfig context.
+func (v *validateCommand) execute(out io.Writer) error {
+	cfg, err := config.GetConfigWithContext(*v.kubeContext)
+	if err != nil {
+		return fmt.Errorf("cannot load kubeconfig context %q: %w", *v.kubeContext, err)
+	}
+	s := runtime.NewScheme()
+	if err := apis.AddToScheme(s); err != nil {
+		return err
+	}
+	kube, err := client.New(cfg, client.Options{Scheme: s})
+	if err != nil {
+		return fmt.Errorf("cannot create kubernetes client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *v.timeout)
+	defer cancel()
+	return v.run(ctx, kube, out)
+}
+
+// run authenticates with the CF API of the ProviderConfig and prints the
+// version of the API and the orgs and spaces the credentials can access.
+func (v *validateCommand) run(ctx context.Context, kube client.Client, out io.Writer) error {
+	ref := fmt.Sprintf("%s/%s", *v.namespace, *v.name)
+	pc := &v1beta1.ProviderConfig{}
+	if err := kube.Get(ctx, types.NamespacedName{Namespace: *v.namespace, Name: *v.name}, pc); err != nil {
+		if kerrors.IsNotFound(err) {
+			return fmt.Errorf("ProviderConfig %s not found, check --namespace and --context", ref)
+		}
+		return fmt.Errorf("cannot get ProviderConfig %s: %w", ref, err)
+	}
+
+	cf, err := clients.ClientForProviderConfig(ctx, kube, pc)
+	if err != nil {
+		return explain(ref, err)
+	}
+	root, err := cf.Root.Get(ctx)
+	if err != nil {
+		return explain(ref, err)
+	}
+
+	fmt.Fprintf(out, "ProviderConfig %s is valid\n", ref)
+	fmt.Fprintf(out, "API:         %s\n", cf.ApiURL(""))
+	fmt.Fprintf(out, "API version: %s\n", root.Links.CloudControllerV3.Meta.Version)
+
+	orgOpts := cfv3.NewOrganizationListOptions()
+	orgOpts.PerPage = maxListed
+	orgs, orgPager, err := cf.Organizations.List(ctx, orgOpts)
+	if err != nil {
+		return explain(ref, err)
+	}
+	names := make([]string, 0, len(orgs))
+	for _, o := range orgs {
+		names = append(names, o.Name)
+	}
+	printScope(out, "Orgs", names, orgPager.TotalResults)
+
+	spaceOpts := cfv3.NewSpaceListOptions()
+	spaceOpts.PerPage = maxListed
+	spaces, spacePager, err := cf.Spaces.List(ctx, spaceOpts)
+	if err != nil {
+		return explain(ref, err)
+	}
+	names = make([]string, 0, len(spaces))
+	for _, s := range spaces {
+		names = append(names, s.Name)
+	}
+	printScope(out, "Spaces", names, spacePager.TotalResults)
+	return nil
+}
+
+// printScope prints the names of the first orgs or spaces and how many there are in total.
+func printScope(out io.Writer, kind string, names []string, total int) {
+	line := fmt.Sprintf("%-12s %d", kind+":", total)
+	if len(names) > 0 {
+		line += " (" + strings.Join(names, ", ")
+		if total > len(names) {
+			line += ", ..."
+		}
+		line += ")"
+	}
+	fmt.Fprintln(out, line)
+}
+
+// explain turns an error of the CF API into a message that says what to check.
+func explain(ref string, err error) error {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "tls:") || strings.Contains(msg, "x509:") || strings.Contains(msg, "HTTP response to HTTPS client"):
+		return fmt.Errorf("TLS handshake with the CF API of ProviderConfig %s failed, check the scheme and port of its API endpoint: %w", ref, err)
+	case clients.ClassifyError(err) == clients.ErrorUnauthorized:
+		return fmt.Errorf("CF API rejected the credentials of ProviderConfig %s, check the email, password and origin in its credentials secret: %w", ref, err)
+	case clients.ClassifyError(err) == clients.ErrorTransient:
+		return fmt.Errorf("CF API of ProviderConfig %s is not reachable, check its API endpoint and the network: %w", ref, err)
+	}
+	return fmt.Errorf("cannot authenticate with the CF API of ProviderConfig %s: %w", ref, err)
+}
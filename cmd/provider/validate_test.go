@@ -0,0 +1,203 @@
+/*
+Copyright 2023 SAP SE
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"gopkg.in/alecthomas/kingpin.v2"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/v1beta1"
+)
+
+func TestValidateCommandParse(t *testing.T) {
+	type want struct {
+		command     string
+		name        string
+		namespace   string
+		kubeContext string
+		timeout     time.Duration
+		err         bool
+	}
+	cases := map[string]struct {
+		args []string
+		want want
+	}{
+		"Defaults": {
+			args: []string{"validate-config", "cf"},
+			want: want{command: "validate-config", name: "cf", namespace: "default", timeout: time.Minute},
+		},
+		"Flags": {
+			args: []string{"validate-config", "--context", "prod", "-n", "crossplane-system", "--timeout", "10s", "cf"},
+			want: want{command: "validate-config", name: "cf", namespace: "crossplane-system", kubeContext: "prod", timeout: 10 * time.Second},
+		},
+		"MissingName": {
+			args: []string{"validate-config"},
+			want: want{err: true},
+		},
+		"StartByDefault": {
+			args: []string{"--debug"},
+			want: want{command: "start"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			app := kingpin.New("provider", "")
+			app.Flag("debug", "").Bool()
+			app.Command("start", "").Default()
+			v := newValidateCommand(app)
+
+			command, err := app.Parse(tc.args)
+			if tc.want.err {
+				if err == nil {
+					t.Fatalf("Parse(%v): want error, got command %q", tc.args, command)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%v): %v", tc.args, err)
+			}
+			got := want{command: command, name: *v.name, namespace: *v.namespace, kubeContext: *v.kubeContext, timeout: *v.timeout}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("Parse(%v): -want, +got:\n%s", tc.args, diff)
+			}
+		})
+	}
+}
+
+// newCFAPI returns a CF API that acts as its own UAA. If unauthorized is true,
+// the UAA rejects all credentials.
+func newCFAPI(t *testing.T, unauthorized bool) *httptest.Server {
+	t.Helper()
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var body any
+		switch r.URL.Path {
+		case "/":
+			body = map[string]any{"links": map[string]any{
+				"login":               map[string]string{"href": srv.URL},
+				"uaa":                 map[string]string{"href": srv.URL},
+				"cloud_controller_v3": map[string]any{"href": srv.URL + "/v3", "meta": map[string]string{"version": "3.180.0"}},
+			}}
+		case "/oauth/token":
+			if unauthorized {
+				w.WriteHeader(http.StatusUnauthorized)
+				body = map[string]string{"error": "unauthorized", "error_description": "Bad credentials"}
+				break
+			}
+			body = map[string]any{"access_token": "token", "token_type": "bearer", "expires_in": 3600}
+		case "/v3/organizations":
+			body = map[string]any{
+				"pagination": map[string]any{"total_results": 12, "total_pages": 6},
+				"resources":  []map[string]string{{"guid": "o1", "name": "dev"}, {"guid": "o2", "name": "prod"}},
+			}
+		case "/v3/spaces":
+			body = map[string]any{
+				"pagination": map[string]any{"total_results": 1, "total_pages": 1},
+				"resources":  []map[string]string{{"guid": "s1", "name": "app"}},
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// withProviderConfig returns a MockGetFn that gets a ProviderConfig for the
+// given API and its credentials.
+func withProviderConfig(api string) test.MockGetFn {
+	return func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+		switch o := obj.(type) {
+		case *v1beta1.ProviderConfig:
+			o.Spec = v1beta1.ProviderConfigSpec{
+				APIEndpoint: &api,
+				Credentials: v1beta1.ProviderCredentials{
+					Source: xpv1.CredentialsSourceSecret,
+					CommonCredentialSelectors: xpv1.CommonCredentialSelectors{SecretRef: &xpv1.SecretKeySelector{
+						SecretReference: xpv1.SecretReference{Name: "cf", Namespace: "default"},
+						Key:             "credentials",
+					}},
+				},
+			}
+		case *corev1.Secret:
+			o.Data = map[string][]byte{"credentials": []byte(`{"email":"admin","password":"secret"}`)}
+		}
+		return nil
+	}
+}
+
+func TestValidateCommandRun(t *testing.T) {
+	api := newCFAPI(t, false)
+	rejecting := newCFAPI(t, true)
+
+	type want struct {
+		out string
+		err string
+	}
+	cases := map[string]struct {
+		get  test.MockGetFn
+		want want
+	}{
+		"Valid": {
+			get: withProviderConfig(api.URL),
+			want: want{out: "ProviderConfig default/cf is valid\n" +
+				"API:         " + api.URL + "\n" +
+				"API version: 3.180.0\n" +
+				"Orgs:        12 (dev, prod, ...)\n" +
+				"Spaces:      1 (app)\n"},
+		},
+		"ProviderConfigNotFound": {
+			get:  test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{Resource: "providerconfigs"}, "cf")),
+			want: want{err: "ProviderConfig default/cf not found, check --namespace and --context"},
+		},
+		"BadCredentials": {
+			get:  withProviderConfig(rejecting.URL),
+			want: want{err: "CF API rejected the credentials of ProviderConfig default/cf, check the email, password and origin in its credentials secret"},
+		},
+		"NoTLS": {
+			get:  withProviderConfig("https://" + strings.TrimPrefix(api.URL, "http://")),
+			want: want{err: "TLS handshake with the CF API of ProviderConfig default/cf failed, check the scheme and port of its API endpoint"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := newValidateCommand(kingpin.New("provider", ""))
+			name, namespace := "cf", "default"
+			v.name, v.namespace = &name, &namespace
+
+			out := &bytes.Buffer{}
+			err := v.run(context.Background(), &test.MockClient{MockGet: tc.get}, out)
+			if tc.want.err != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tc.want.err) {
+					t.Fatalf("run(...): want error %q, got %v", tc.want.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("run(...): %v", err)
+			}
+			if diff := cmp.Diff(tc.want.out, out.String()); diff != "" {
+				t.Errorf("run(...): -want output, +got output:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	cfv3 "github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/pkg/errors"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+)
+
+// Annotations that request an action on an app without changing its spec,
+// e.g. `kubectl annotate app my-app cloudfoundry.crossplane.io/restart=now`.
+// The annotation is removed once the action is performed.
+const (
+	// RestartAnnotation requests a rolling restart of the instances of an app.
+	RestartAnnotation = "cloudfoundry.crossplane.io/restart"
+	// RestageAnnotation requests to rebuild the droplet of an app and to roll
+	// its instances onto the new droplet.
+	RestageAnnotation = "cloudfoundry.crossplane.io/restage"
+)
+
+// deploymentStrategyRolling replaces the instances of an app one by one.
+const deploymentStrategyRolling = "rolling"
+
+// The status values of a deployment.
+const (
+	DeploymentActive    = "ACTIVE"
+	DeploymentFinalized = "FINALIZED"
+)
+
+// The reasons of the status of a deployment.
+const (
+	DeploymentDeploying = "DEPLOYING"
+	DeploymentCanceled  = "CANCELED"
+)
+
+// stagingTimeout is how long a restage or deploy waits for the new droplet to be staged.
+var stagingTimeout = 15 * time.Minute
+
+// DeploymentTimeout is how long a rolling deployment may take before it is canceled.
+var DeploymentTimeout = 15 * time.Minute
+
+// DeployClient is the interface for restarting and restaging an app.
+type DeployClient interface {
+	// Restart rolls the instances of an app with a rolling deployment of its current droplet.
+	Restart(ctx context.Context, appGUID string) error
+	// Restage stages the latest package of an app into a new droplet. The
+	// droplet of a started app is rolled out with a rolling deployment, that
+	// of a stopped app becomes its current droplet.
+	Restage(ctx context.Context, appGUID string, started bool) error
+	// Deploy stages a docker image into a new droplet of an app and rolls it out
+	// with a rolling deployment, without waiting for the deployment to finish.
+	Deploy(ctx context.Context, appGUID string, image string, credentials *resource.DockerCredentials) (*resource.Deployment, error)
+	// GetDeployment returns the deployment with the given GUID.
+	GetDeployment(ctx context.Context, guid string) (*resource.Deployment, error)
+	// CancelDeployment cancels a deployment, which rolls the app back to its previous droplet.
+	CancelDeployment(ctx context.Context, guid string) error
+}
+
+// deployClient implements DeployClient
+type deployClient struct {
+	client *cfv3.Client
+}
+
+// NewDeployClient creates a new DeployClient
+func NewDeployClient(client *cfv3.Client) DeployClient {
+	return &deployClient{client: client}
+}
+
+// Restart implements DeployClient.
+func (d *deployClient) Restart(ctx context.Context, appGUID string) error {
+	_, err := d.deploy(ctx, appGUID, nil)
+	return err
+}
+
+// Restage implements DeployClient.
+func (d *deployClient) Restage(ctx context.Context, appGUID string, started bool) error {
+	opts := cfv3.NewPackageListOptions()
+	opts.States.EqualTo(string(resource.PackageStateReady))
+	opts.OrderBy = "-created_at"
+	pkg, err := d.client.Packages.FirstForApp(ctx, appGUID, opts)
+	if err != nil {
+		return errors.Wrap(err, "cannot find the package to restage")
+	}
+
+	droplet, err := d.stage(ctx, pkg.GUID)
+	if err != nil {
+		return err
+	}
+
+	if !started {
+		_, err := d.client.Droplets.SetCurrentAssociationForApp(ctx, appGUID, droplet.GUID)
+		return errors.Wrap(err, "cannot set the current droplet")
+	}
+	_, err = d.deploy(ctx, appGUID, droplet)
+	return err
+}
+
+// Deploy implements DeployClient.
+func (d *deployClient) Deploy(ctx context.Context, appGUID string, image string, credentials *resource.DockerCredentials) (*resource.Deployment, error) {
+	var username, password string
+	if credentials != nil {
+		username, password = credentials.Username, credentials.Password
+	}
+	pkg, err := d.client.Packages.Create(ctx, resource.NewDockerPackageCreate(appGUID, image, username, password))
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot create the docker package")
+	}
+	droplet, err := d.stage(ctx, pkg.GUID)
+	if err != nil {
+		return nil, err
+	}
+	return d.deploy(ctx, appGUID, droplet)
+}
+
+// GetDeployment implements DeployClient.
+func (d *deployClient) GetDeployment(ctx context.Context, guid string) (*resource.Deployment, error) {
+	return d.client.Deployments.Get(ctx, guid)
+}
+
+// CancelDeployment implements DeployClient.
+func (d *deployClient) CancelDeployment(ctx context.Context, guid string) error {
+	return d.client.Deployments.Cancel(ctx, guid)
+}
+
+// stage stages a package into a new droplet and returns the droplet.
+func (d *deployClient) stage(ctx context.Context, packageGUID string) (*resource.Relationship, error) {
+	build, err := d.client.Builds.Create(ctx, resource.NewBuildCreate(packageGUID))
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot stage the package")
+	}
+	polling := cfv3.NewPollingOptions()
+	polling.Timeout = stagingTimeout
+	if err := d.client.Builds.PollStaged(ctx, build.GUID, polling); err != nil {
+		return nil, errors.Wrap(err, "cannot stage the package")
+	}
+	build, err = d.client.Builds.Get(ctx, build.GUID)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get the staged droplet")
+	}
+	if build.Droplet == nil {
+		return nil, errors.Errorf("build %s did not produce a droplet", build.GUID)
+	}
+	return build.Droplet, nil
+}
+
+// deploy creates a rolling deployment of the given droplet, or of the current droplet if it is nil.
+func (d *deployClient) deploy(ctx context.Context, appGUID string, droplet *resource.Relationship) (*resource.Deployment, error) {
+	r := resource.NewDeploymentCreate(appGUID)
+	r.Strategy = deploymentStrategyRolling
+	r.Droplet = droplet
+	deployment, err := d.client.Deployments.Create(ctx, r)
+	return deployment, errors.Wrap(err, "cannot create a rolling deployment")
+}
+
+// GenerateDeploymentObservation returns the observed state of a rolling deployment.
+func GenerateDeploymentObservation(d *resource.Deployment) *v1alpha1.DeploymentObservation {
+	return &v1alpha1.DeploymentObservation{
+		GUID:   d.GUID,
+		Status: d.Status.Value,
+		Reason: d.Status.Reason,
+	}
+}
+
+// RequestedAction returns the annotation of the action requested on an app,
+// or an empty string. A restage includes a restart, so it takes precedence.
+func RequestedAction(annotations map[string]string) string {
+	for _, a := range []string{RestageAnnotation, RestartAnnotation} {
+		if _, ok := annotations[a]; ok {
+			return a
+		}
+	}
+	return ""
+}
@@ -4,15 +4,18 @@ package app
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/cloudfoundry/go-cfclient/v3/client"
 	"github.com/cloudfoundry/go-cfclient/v3/operation"
 	"github.com/cloudfoundry/go-cfclient/v3/resource"
 	"github.com/google/uuid"
+	"github.com/pkg/errors"
 	"k8s.io/utils/ptr"
 
 	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/job"
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/servicecredentialbinding"
 )
@@ -21,9 +24,12 @@ import (
 type AppClient interface {
 	Get(ctx context.Context, guid string) (*resource.App, error)
 	Single(ctx context.Context, opts *client.AppListOptions) (*resource.App, error)
+	ListAll(ctx context.Context, opts *client.AppListOptions) ([]*resource.App, error)
 	Create(ctx context.Context, r *resource.AppCreate) (*resource.App, error)
 	Update(ctx context.Context, guid string, r *resource.AppUpdate) (*resource.App, error)
 	Delete(ctx context.Context, guid string) (string, error)
+	GetEnvironment(ctx context.Context, guid string) (*resource.AppEnvironment, error)
+	SetEnvironmentVariables(ctx context.Context, guid string, envRequest map[string]*string) (map[string]*string, error)
 
 	Start(ctx context.Context, guid string) (*resource.App, error)
 	Stop(ctx context.Context, guid string) (*resource.App, error)
@@ -37,9 +43,19 @@ type ManifestClient interface {
 	ManifestDiff(ctx context.Context, spaceGUID string, manifest string) (*resource.ManifestDiff, error)
 }
 
+// ProcessClient defines the interface to communicate with Cloud Foundry Process resource.
+type ProcessClient interface {
+	SingleForApp(ctx context.Context, appGUID string, opts *client.ProcessListOptions) (*resource.Process, error)
+	Update(ctx context.Context, guid string, r *resource.ProcessUpdate) (*resource.Process, error)
+}
+
 type Client struct {
 	AppClient
 	PushClient
+	DeployClient
+	ManifestClient
+	ProcessClient
+	RouteClient
 	job.Job
 	servicecredentialbinding.ServiceCredentialBinding
 }
@@ -49,6 +65,10 @@ func NewAppClient(client *client.Client) *Client {
 	return &Client{
 		AppClient:                client.Applications,
 		PushClient:               NewPushClient(client),
+		DeployClient:             NewDeployClient(client),
+		ManifestClient:           client.Manifests,
+		ProcessClient:            client.Processes,
+		RouteClient:              client.Routes,
 		Job:                      client.Jobs,
 		ServiceCredentialBinding: servicecredentialbinding.NewClient(client),
 	}
@@ -56,6 +76,10 @@ func NewAppClient(client *client.Client) *Client {
 
 type DockerCredentials resource.DockerCredentials
 
+// VCAPServicesKey is the environment variable, and connection detail, that
+// holds the credentials of the service instances bound to an app.
+const VCAPServicesKey = "VCAP_SERVICES"
+
 // GetByIDOrSpec gets the App by GUID or spec.
 func (c *Client) GetByIDOrSpec(ctx context.Context, guid string, spec v1alpha1.AppParameters) (*resource.App, error) {
 	_, err := uuid.Parse(guid)
@@ -67,8 +91,8 @@ func (c *Client) GetByIDOrSpec(ctx context.Context, guid string, spec v1alpha1.A
 }
 
 // CreateAndPush creates and pushes an app to the Cloud Foundry.
-func (c *Client) CreateAndPush(ctx context.Context, spec v1alpha1.AppParameters, dockerCredentials *DockerCredentials) (*resource.App, error) {
-	manifest, err := newManifestFromSpec(spec, dockerCredentials)
+func (c *Client) CreateAndPush(ctx context.Context, spec v1alpha1.AppParameters, dockerCredentials *DockerCredentials, env map[string]string) (*resource.App, error) {
+	manifest, err := newManifestFromSpec(spec, dockerCredentials, env)
 	if err != nil {
 		return nil, err
 	}
@@ -80,27 +104,66 @@ func (c *Client) CreateAndPush(ctx context.Context, spec v1alpha1.AppParameters,
 	return c.PushClient.Push(ctx, application, manifest, nil)
 }
 
-// Update updates an app in the Cloud Foundry.
-func (c *Client) Update(ctx context.Context, guid string, spec v1alpha1.AppParameters) (*resource.App, error) {
-	application, err := c.AppClient.Update(ctx, guid, newUpdateOption(spec))
+// Update updates the name, lifecycle, labels and annotations of an app in the Cloud Foundry.
+// Labels and annotations of the observed app that are not in the spec are removed.
+func (c *Client) Update(ctx context.Context, guid string, spec v1alpha1.AppParameters, status v1alpha1.AppObservation) (*resource.App, error) {
+	application, err := c.AppClient.Update(ctx, guid, newUpdateOption(spec, status))
 	if err != nil {
 		return nil, err
 	}
 	return application, nil
 }
 
-// UpdateAndPush updates and pushes an app to the Cloud Foundry.
-func (c *Client) UpdateAndPush(ctx context.Context, guid string, spec v1alpha1.AppParameters, dockerCredentials *DockerCredentials) (*resource.App, error) {
-	manifest, err := newManifestFromSpec(spec, dockerCredentials)
+// UpdateAndPush updates and pushes an app to the Cloud Foundry. A started docker app is rolled onto its new
+// droplet with a rolling deployment, which is returned, unless the deployment strategy is Recreate.
+func (c *Client) UpdateAndPush(ctx context.Context, guid string, spec v1alpha1.AppParameters, status v1alpha1.AppObservation, dockerCredentials *DockerCredentials, env map[string]string) (*resource.App, *resource.Deployment, error) {
+	manifest, err := newManifestFromSpec(spec, dockerCredentials, env)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	application, err := c.AppClient.Update(ctx, guid, newUpdateOption(spec))
+	application, err := c.AppClient.Update(ctx, guid, newUpdateOption(spec, status))
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return c.PushClient.Push(ctx, application, manifest, nil)
+	started := application.State == StateStarted
+
+	if started && manifest.Docker != nil && spec.DeploymentStrategy != v1alpha1.DeploymentStrategyRecreate {
+		rendered, err := RenderManifest(spec, dockerCredentials, env)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := c.Apply(ctx, ptr.Deref(spec.Space, ""), rendered); err != nil {
+			return nil, nil, err
+		}
+		deployment, err := c.DeployClient.Deploy(ctx, application.GUID, manifest.Docker.Image, (*resource.DockerCredentials)(dockerCredentials))
+		if err != nil {
+			return nil, nil, err
+		}
+		return application, deployment, nil
+	}
+
+	application, err = c.PushClient.Push(ctx, application, manifest, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	// the pushed droplet becomes the current droplet, but the instances of a started app run the previous one
+	if started && spec.DeploymentStrategy == v1alpha1.DeploymentStrategyRecreate {
+		if _, err := c.AppClient.Stop(ctx, application.GUID); err != nil {
+			return nil, nil, err
+		}
+		application, err = c.AppClient.Start(ctx, application.GUID)
+	}
+	return application, nil, err
+}
+
+// Apply applies a rendered manifest to the space of an app and waits for the resulting job to complete.
+func (c *Client) Apply(ctx context.Context, spaceGUID string, manifest string) error {
+	jobGUID, err := c.ManifestClient.ApplyManifest(ctx, spaceGUID, manifest)
+	if err != nil {
+		return err
+	}
+	return job.PollJobComplete(ctx, c.Job, jobGUID)
 }
 
 // Delete deletes an app in the Cloud Foundry.
@@ -124,6 +187,30 @@ func (c *Client) ReconcileServiceBinding(ctx context.Context, guid string, spec
 	return nil
 }
 
+// GetVCAPServices returns the VCAP_SERVICES of an app as seen by its running
+// instances: the bound service instances grouped by service offering label.
+// An app without bindings yields an empty JSON object.
+func (c *Client) GetVCAPServices(ctx context.Context, guid string) ([]byte, error) {
+	env, err := c.AppClient.GetEnvironment(ctx, guid)
+	if err != nil {
+		return nil, err
+	}
+	return vcapServices(env)
+}
+
+// vcapServices extracts VCAP_SERVICES from the system environment of an app.
+// Entries are kept as returned by Cloud Foundry, but re-encoded with sorted
+// keys so that the published value only changes when a binding changes.
+func vcapServices(env *resource.AppEnvironment) ([]byte, error) {
+	services := map[string][]map[string]any{}
+	if raw := env.SystemEnvVars[VCAPServicesKey]; len(raw) > 0 {
+		if err := json.Unmarshal(raw, &services); err != nil {
+			return nil, errors.Wrap(err, "cannot parse "+VCAPServicesKey)
+		}
+	}
+	return json.Marshal(services)
+}
+
 // GenerateObservation takes an App resource and returns *AppObservation.
 func GenerateObservation(res *resource.App) v1alpha1.AppObservation {
 	obs := v1alpha1.AppObservation{}
@@ -133,10 +220,17 @@ func GenerateObservation(res *resource.App) v1alpha1.AppObservation {
 	obs.State = res.State
 	obs.CreatedAt = ptr.To(res.CreatedAt.Format(time.RFC3339))
 	obs.UpdatedAt = ptr.To(res.UpdatedAt.Format(time.RFC3339))
+	if res.Metadata != nil {
+		obs.Labels = res.Metadata.Labels
+		obs.Annotations = res.Metadata.Annotations
+	}
 
 	return obs
 }
 
+// manifestFields are the changed fields that are reconciled by applying the manifest of the app.
+var manifestFields = []string{"health_check", "readiness_health_check", "scale", "log_rate_limit"}
+
 // ChangeDetection represents what fields have changed
 type ChangeDetection struct {
 	ChangedFields map[string]struct{}
@@ -146,6 +240,16 @@ func (cd *ChangeDetection) HasChanges() bool {
 	return len(cd.ChangedFields) > 0
 }
 
+// HasManifestChanges checks if any field changed that is reconciled by applying the manifest
+func (cd *ChangeDetection) HasManifestChanges() bool {
+	for _, f := range manifestFields {
+		if cd.HasField(f) {
+			return true
+		}
+	}
+	return false
+}
+
 // HasField checks if a specific field changed
 func (cd *ChangeDetection) HasField(field string) bool {
 	_, ok := cd.ChangedFields[field]
@@ -176,6 +280,57 @@ func DetectChanges(spec v1alpha1.AppParameters, status v1alpha1.AppObservation)
 		changes.ChangedFields["name"] = struct{}{}
 	}
 
+	// Check if labels or annotations changed
+	if !clients.MetadataEqual(spec.Labels, spec.Annotations, status.ManagedMetadata, observedMetadata(status)) {
+		changes.ChangedFields["metadata"] = struct{}{}
+	}
+
+	// Check if the app should be started or stopped
+	if spec.DesiredState != "" && spec.DesiredState != status.State {
+		changes.ChangedFields["state"] = struct{}{}
+	}
+
+	// Check if the instances or memory of the web process changed
+	scale, err := webScaleDiffers(spec, status)
+	if err != nil {
+		return nil, err
+	}
+	if scale {
+		changes.ChangedFields["scale"] = struct{}{}
+	}
+
+	// Check if the log rate limit of any process changed
+	logRateLimit, err := logRateLimitDiffers(spec, status)
+	if err != nil {
+		return nil, err
+	}
+	if logRateLimit {
+		changes.ChangedFields["log_rate_limit"] = struct{}{}
+	}
+
+	// Check if liveness or readiness health checks of any process changed
+	healthChecks, err := diffHealthChecks(spec, status)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range healthChecks {
+		if d.liveness != nil {
+			changes.ChangedFields["health_check"] = struct{}{}
+		}
+		if d.readiness != nil {
+			changes.ChangedFields["readiness_health_check"] = struct{}{}
+		}
+	}
+
+	// Check if the command of any process changed
+	commands, err := diffCommands(spec, status)
+	if err != nil {
+		return nil, err
+	}
+	if len(commands) > 0 {
+		changes.ChangedFields["command"] = struct{}{}
+	}
+
 	return changes, nil
 }
 
@@ -250,11 +405,12 @@ func newCreateOption(spec v1alpha1.AppParameters) *resource.AppCreate {
 	default:
 		appCreate.Lifecycle = nil
 	}
+	appCreate.Metadata = clients.NewMetadata(spec.Labels, spec.Annotations)
 	return appCreate
 }
 
-// newUpdateOption map spec to AppCreate option
-func newUpdateOption(spec v1alpha1.AppParameters) *resource.AppUpdate {
+// newUpdateOption map spec to AppUpdate option, patching the observed labels and annotations
+func newUpdateOption(spec v1alpha1.AppParameters, status v1alpha1.AppObservation) *resource.AppUpdate {
 	var lifecycle *resource.Lifecycle
 	switch spec.Lifecycle {
 	case "buildpack":
@@ -276,10 +432,15 @@ func newUpdateOption(spec v1alpha1.AppParameters) *resource.AppUpdate {
 	return &resource.AppUpdate{
 		Name:      spec.Name,
 		Lifecycle: lifecycle,
-		Metadata:  &resource.Metadata{},
+		Metadata:  clients.MetadataPatch(spec.Labels, spec.Annotations, status.ManagedMetadata, observedMetadata(status)),
 	}
 }
 
+// observedMetadata returns the labels and annotations of the observed app.
+func observedMetadata(status v1alpha1.AppObservation) *resource.Metadata {
+	return &resource.Metadata{Labels: status.Labels, Annotations: status.Annotations}
+}
+
 // newManifestFromSpec creates a manifest from the given spec.
 func bindService(ctx context.Context, scbClient servicecredentialbinding.ServiceCredentialBinding, s v1alpha1.ServiceBindingConfiguration) error {
 	// TODO: Implement the binding logic
@@ -1,8 +1,14 @@
 package app
 
 import (
+	"encoding/json"
 	"testing"
 
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"k8s.io/utils/ptr"
+
 	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
 )
 
@@ -73,6 +79,70 @@ func TestDetectChanges(t *testing.T) {
 			},
 			expectedFields: []string{"docker_image", "name"},
 		},
+		{
+			name: "Readiness health check changed",
+			spec: v1alpha1.AppParameters{
+				Name:      "test-app",
+				Lifecycle: "buildpack",
+				ReadinessHealthCheckConfiguration: v1alpha1.ReadinessHealthCheckConfiguration{
+					ReadinessHealthCheckType: ptr.To("http"),
+				},
+				Processes: []v1alpha1.ProcessConfiguration{
+					{
+						Type: ptr.To("web"),
+						HealthCheckConfiguration: v1alpha1.HealthCheckConfiguration{
+							HealthCheckType: ptr.To("port"),
+						},
+					},
+				},
+			},
+			status: v1alpha1.AppObservation{
+				Name:        "test-app",
+				AppManifest: "applications:\n- name: test-app\n  processes:\n  - type: web\n    health-check-type: port\n    readiness-health-check-type: process",
+			},
+			expectedFields: []string{"readiness_health_check"},
+		},
+		{
+			name: "Liveness health check changed",
+			spec: v1alpha1.AppParameters{
+				Name:      "test-app",
+				Lifecycle: "buildpack",
+				Processes: []v1alpha1.ProcessConfiguration{
+					{
+						Type: ptr.To("worker"),
+						HealthCheckConfiguration: v1alpha1.HealthCheckConfiguration{
+							HealthCheckType: ptr.To("process"),
+						},
+						ReadinessHealthCheckConfiguration: v1alpha1.ReadinessHealthCheckConfiguration{
+							ReadinessHealthCheckType: ptr.To("process"),
+						},
+					},
+				},
+			},
+			status: v1alpha1.AppObservation{
+				Name:        "test-app",
+				AppManifest: "applications:\n- name: test-app\n  processes:\n  - type: worker\n    health-check-type: port\n    readiness-health-check-type: process",
+			},
+			expectedFields: []string{"health_check"},
+		},
+		{
+			name: "Command changed",
+			spec: v1alpha1.AppParameters{
+				Name:      "test-app",
+				Lifecycle: "buildpack",
+				Processes: []v1alpha1.ProcessConfiguration{
+					{
+						Type:    ptr.To("worker"),
+						Command: ptr.To("bin/worker --queue=high"),
+					},
+				},
+			},
+			status: v1alpha1.AppObservation{
+				Name:        "test-app",
+				AppManifest: "applications:\n- name: test-app\n  processes:\n  - type: worker\n    command: bin/worker",
+			},
+			expectedFields: []string{"command"},
+		},
 		{
 			name: "Non-docker app name change",
 			spec: v1alpha1.AppParameters{
@@ -189,3 +259,122 @@ func TestIsUpToDate(t *testing.T) {
 		})
 	}
 }
+
+func TestVCAPServices(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      *resource.AppEnvironment
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "No bound services",
+			env:      &resource.AppEnvironment{},
+			expected: `{}`,
+		},
+		{
+			name: "Bound services grouped by offering",
+			env: &resource.AppEnvironment{
+				SystemEnvVars: map[string]json.RawMessage{
+					VCAPServicesKey: json.RawMessage(`{
+						"xsuaa": [{"name": "uaa", "label": "xsuaa", "plan": "application", "tags": ["xsuaa"], "credentials": {"clientid": "id", "clientsecret": "secret"}}],
+						"destination": [
+							{"name": "dest-a", "label": "destination", "plan": "lite", "credentials": {"uri": "https://a"}},
+							{"name": "dest-b", "label": "destination", "plan": "lite", "credentials": {"uri": "https://b"}}
+						]
+					}`),
+				},
+			},
+			expected: `{"destination":[` +
+				`{"credentials":{"uri":"https://a"},"label":"destination","name":"dest-a","plan":"lite"},` +
+				`{"credentials":{"uri":"https://b"},"label":"destination","name":"dest-b","plan":"lite"}],` +
+				`"xsuaa":[{"credentials":{"clientid":"id","clientsecret":"secret"},"label":"xsuaa","name":"uaa","plan":"application","tags":["xsuaa"]}]}`,
+		},
+		{
+			name: "Malformed VCAP_SERVICES",
+			env: &resource.AppEnvironment{
+				SystemEnvVars: map[string]json.RawMessage{VCAPServicesKey: json.RawMessage(`["not", "grouped"]`)},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := vcapServices(tt.env)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("vcapServices() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(result) != tt.expected {
+				t.Errorf("vcapServices() = %s, want %s", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	tests := []struct {
+		name    string
+		labels  map[string]*string
+		status  v1alpha1.AppObservation
+		changed bool
+		patch   *resource.Metadata
+	}{
+		{
+			name:    "Unchanged",
+			labels:  map[string]*string{"team": ptr.To("a")},
+			status:  v1alpha1.AppObservation{ResourceMetadata: v1alpha1.ResourceMetadata{Labels: map[string]*string{"team": ptr.To("a")}}},
+			changed: false,
+			patch:   &resource.Metadata{Labels: map[string]*string{"team": ptr.To("a")}},
+		},
+		{
+			name:    "LabelAdded",
+			labels:  map[string]*string{"team": ptr.To("a")},
+			status:  v1alpha1.AppObservation{},
+			changed: true,
+			patch:   &resource.Metadata{Labels: map[string]*string{"team": ptr.To("a")}},
+		},
+		{
+			name:    "LabelChanged",
+			labels:  map[string]*string{"team": ptr.To("b")},
+			status:  v1alpha1.AppObservation{ResourceMetadata: v1alpha1.ResourceMetadata{Labels: map[string]*string{"team": ptr.To("a")}}},
+			changed: true,
+			patch:   &resource.Metadata{Labels: map[string]*string{"team": ptr.To("b")}},
+		},
+		{
+			name:   "LabelRemoved",
+			labels: nil,
+			status: v1alpha1.AppObservation{
+				ResourceMetadata: v1alpha1.ResourceMetadata{Labels: map[string]*string{"team": ptr.To("a")}},
+				ManagedMetadata:  v1alpha1.ManagedMetadata{ManagedLabels: []string{"team"}},
+			},
+			changed: true,
+			patch:   &resource.Metadata{Labels: map[string]*string{"team": nil}},
+		},
+		{
+			name:    "SetByOthers",
+			labels:  nil,
+			status:  v1alpha1.AppObservation{ResourceMetadata: v1alpha1.ResourceMetadata{Labels: map[string]*string{"team": ptr.To("a")}}},
+			changed: false,
+			patch:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := v1alpha1.AppParameters{Name: "test-app", ResourceMetadata: v1alpha1.ResourceMetadata{Labels: tt.labels}}
+			tt.status.Name = "test-app"
+
+			changes, err := DetectChanges(spec, tt.status)
+			if err != nil {
+				t.Fatalf("DetectChanges() error = %v", err)
+			}
+			if got := changes.HasField("metadata"); got != tt.changed {
+				t.Errorf("DetectChanges().HasField(metadata) = %v, want %v", got, tt.changed)
+			}
+			if diff := cmp.Diff(tt.patch, newUpdateOption(spec, tt.status).Metadata, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("newUpdateOption().Metadata: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"k8s.io/utils/ptr"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/job"
+)
+
+// RouteClient defines the interface to the routes mapped to an app.
+type RouteClient interface {
+	ListForAppAll(ctx context.Context, appGUID string, opts *client.RouteListOptions) ([]*resource.Route, error)
+	RemoveDestination(ctx context.Context, guid, destinationGUID string) error
+}
+
+// RemoveDependents unbinds the services of an app and then unmaps its routes,
+// so that neither outlives the app. Both steps run even if the other fails,
+// and their errors are joined. Bindings and mappings that are already gone
+// are ignored, so that a failed removal can simply be retried.
+func (c *Client) RemoveDependents(ctx context.Context, guid string) error {
+	return errors.Join(c.UnbindServices(ctx, guid), c.UnmapRoutes(ctx, guid))
+}
+
+// UnbindServices deletes the service credential bindings of an app and waits
+// for asynchronous unbinds to complete.
+func (c *Client) UnbindServices(ctx context.Context, guid string) error {
+	opts := client.NewServiceCredentialBindingListOptions()
+	opts.AppGUIDs.EqualTo(guid)
+	bindings, err := c.ServiceCredentialBinding.ListAll(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("cannot list service bindings: %w", err)
+	}
+
+	var errs []error
+	for _, b := range bindings {
+		jobGUID, err := c.ServiceCredentialBinding.Delete(ctx, b.GUID)
+		if err == nil && jobGUID != "" {
+			err = job.PollJobComplete(ctx, c.Job, jobGUID)
+		}
+		if err != nil && !clients.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("cannot unbind service binding %s: %w", b.GUID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// UnmapRoutes removes the destinations of an app from all routes it is mapped to.
+func (c *Client) UnmapRoutes(ctx context.Context, guid string) error {
+	routes, err := c.RouteClient.ListForAppAll(ctx, guid, nil)
+	if err != nil {
+		return fmt.Errorf("cannot list routes: %w", err)
+	}
+
+	var errs []error
+	for _, r := range routes {
+		for _, d := range r.Destinations {
+			if ptr.Deref(d.App.GUID, "") != guid || d.GUID == nil {
+				continue
+			}
+			if err := c.RouteClient.RemoveDestination(ctx, r.GUID, *d.GUID); err != nil && !clients.IsNotFound(err) {
+				errs = append(errs, fmt.Errorf("cannot unmap route %s: %w", r.URL, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
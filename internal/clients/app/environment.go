@@ -0,0 +1,119 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8s "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+)
+
+const (
+	errGetConfigMap       = "cannot get ConfigMap %q"
+	errGetSecret          = "cannot get Secret %q"
+	errInlineEnvironment  = "cannot parse environment"
+	errEnvironmentSources = "environment source must reference either a ConfigMap or a Secret"
+)
+
+// ManagesEnvironment returns true if the spec sets environment variables of an app.
+func ManagesEnvironment(spec v1alpha1.AppParameters) bool {
+	return spec.Environment != nil || len(spec.EnvironmentFrom) > 0
+}
+
+// Environment returns the environment variables of an app. The sources in
+// `environmentFrom` are merged in order, so that a later source overrides an
+// earlier one, and the inline `environment` overrides all sources. It returns
+// nil if the spec does not manage the environment of the app.
+func Environment(ctx context.Context, kube k8s.Reader, namespace string, spec v1alpha1.AppParameters) (map[string]string, error) {
+	if !ManagesEnvironment(spec) {
+		return nil, nil
+	}
+
+	env := map[string]string{}
+	for _, src := range spec.EnvironmentFrom {
+		switch {
+		case src.ConfigMapRef != nil:
+			cm := &corev1.ConfigMap{}
+			if err := kube.Get(ctx, types.NamespacedName{Namespace: namespace, Name: src.ConfigMapRef.Name}, cm); err != nil {
+				return nil, errors.Wrapf(err, errGetConfigMap, src.ConfigMapRef.Name)
+			}
+			maps.Copy(env, cm.Data)
+		case src.SecretRef != nil:
+			secret := &corev1.Secret{}
+			if err := kube.Get(ctx, types.NamespacedName{Namespace: namespace, Name: src.SecretRef.Name}, secret); err != nil {
+				return nil, errors.Wrapf(err, errGetSecret, src.SecretRef.Name)
+			}
+			for k, v := range secret.Data {
+				env[k] = string(v)
+			}
+		default:
+			return nil, errors.New(errEnvironmentSources)
+		}
+	}
+
+	if spec.Environment != nil && len(spec.Environment.Raw) > 0 {
+		inline := map[string]any{}
+		if err := json.Unmarshal(spec.Environment.Raw, &inline); err != nil {
+			return nil, errors.Wrap(err, errInlineEnvironment)
+		}
+		for k, v := range inline {
+			if s, ok := v.(string); ok {
+				env[k] = s
+				continue
+			}
+			b, err := json.Marshal(v)
+			if err != nil {
+				return nil, errors.Wrap(err, errInlineEnvironment)
+			}
+			env[k] = string(b)
+		}
+	}
+	return env, nil
+}
+
+// UsesConfigMap returns true if an app sources environment variables from the named ConfigMap.
+func UsesConfigMap(spec v1alpha1.AppParameters, name string) bool {
+	for _, src := range spec.EnvironmentFrom {
+		if src.ConfigMapRef != nil && src.ConfigMapRef.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// GetEnvironmentVariables returns the user-provided environment variables of an app.
+func (c *Client) GetEnvironmentVariables(ctx context.Context, guid string) (map[string]string, error) {
+	env, err := c.AppClient.GetEnvironment(ctx, guid)
+	if err != nil {
+		return nil, err
+	}
+	return env.EnvVars, nil
+}
+
+// IsEnvironmentUpToDate checks whether the environment variables of an app are the desired ones.
+// A nil `desired` leaves the environment of the app as it is.
+func IsEnvironmentUpToDate(desired, observed map[string]string) bool {
+	if desired == nil {
+		return true
+	}
+	return maps.Equal(desired, observed)
+}
+
+// UpdateEnvironment sets the environment variables of an app to the desired
+// ones. Variables in `observed` that are no longer desired are removed.
+func (c *Client) UpdateEnvironment(ctx context.Context, guid string, desired, observed map[string]string) error {
+	patch := make(map[string]*string, len(desired)+len(observed))
+	for k := range observed {
+		patch[k] = nil
+	}
+	for k, v := range desired {
+		patch[k] = &v
+	}
+	_, err := c.AppClient.SetEnvironmentVariables(ctx, guid, patch)
+	return err
+}
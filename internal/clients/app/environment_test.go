@@ -0,0 +1,149 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8s "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+)
+
+// mockEnvironmentSources returns a client that knows the ConfigMap "app-config" and the Secret "app-secret".
+func mockEnvironmentSources() *test.MockClient {
+	return &test.MockClient{
+		MockGet: func(_ context.Context, key k8s.ObjectKey, obj k8s.Object) error {
+			switch o := obj.(type) {
+			case *corev1.ConfigMap:
+				if key.Name != "app-config" {
+					return kerrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, key.Name)
+				}
+				o.Data = map[string]string{"LOG_LEVEL": "info", "REGION": "eu10", "FEATURE": "off"}
+			case *corev1.Secret:
+				if key.Name != "app-secret" {
+					return kerrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, key.Name)
+				}
+				o.Data = map[string][]byte{"API_KEY": []byte("s3cr3t"), "REGION": []byte("us10")}
+			}
+			return nil
+		},
+	}
+}
+
+func TestEnvironment(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     v1alpha1.AppParameters
+		expected map[string]string
+		wantErr  bool
+	}{
+		{
+			name:     "Unmanaged environment",
+			spec:     v1alpha1.AppParameters{},
+			expected: nil,
+		},
+		{
+			name: "Environment from ConfigMap",
+			spec: v1alpha1.AppParameters{
+				EnvironmentFrom: []v1alpha1.EnvironmentSource{
+					{ConfigMapRef: &v1alpha1.LocalObjectReference{Name: "app-config"}},
+				},
+			},
+			expected: map[string]string{"LOG_LEVEL": "info", "REGION": "eu10", "FEATURE": "off"},
+		},
+		{
+			name: "Later sources and inline environment take precedence",
+			spec: v1alpha1.AppParameters{
+				EnvironmentFrom: []v1alpha1.EnvironmentSource{
+					{ConfigMapRef: &v1alpha1.LocalObjectReference{Name: "app-config"}},
+					{SecretRef: &v1alpha1.LocalObjectReference{Name: "app-secret"}},
+				},
+				Environment: &runtime.RawExtension{Raw: []byte(`{"FEATURE": "on", "REPLICAS": 2, "LIMITS": {"cpu": 1}}`)},
+			},
+			expected: map[string]string{
+				"LOG_LEVEL": "info",
+				"REGION":    "us10",
+				"API_KEY":   "s3cr3t",
+				"FEATURE":   "on",
+				"REPLICAS":  "2",
+				"LIMITS":    `{"cpu":1}`,
+			},
+		},
+		{
+			name: "Missing ConfigMap",
+			spec: v1alpha1.AppParameters{
+				EnvironmentFrom: []v1alpha1.EnvironmentSource{
+					{ConfigMapRef: &v1alpha1.LocalObjectReference{Name: "missing"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Inline environment is not an object",
+			spec: v1alpha1.AppParameters{
+				Environment: &runtime.RawExtension{Raw: []byte(`["FEATURE"]`)},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Environment(context.Background(), mockEnvironmentSources(), "default", tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Environment() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.expected, result); diff != "" {
+				t.Errorf("Environment() -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIsEnvironmentUpToDate(t *testing.T) {
+	tests := []struct {
+		name     string
+		desired  map[string]string
+		observed map[string]string
+		expected bool
+	}{
+		{
+			name:     "Unmanaged environment",
+			desired:  nil,
+			observed: map[string]string{"LOG_LEVEL": "debug"},
+			expected: true,
+		},
+		{
+			name:     "Same environment",
+			desired:  map[string]string{"LOG_LEVEL": "info"},
+			observed: map[string]string{"LOG_LEVEL": "info"},
+			expected: true,
+		},
+		{
+			name:     "Changed value",
+			desired:  map[string]string{"LOG_LEVEL": "info"},
+			observed: map[string]string{"LOG_LEVEL": "debug"},
+			expected: false,
+		},
+		{
+			name:     "Removed variable",
+			desired:  map[string]string{},
+			observed: map[string]string{"LOG_LEVEL": "debug"},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := IsEnvironmentUpToDate(tt.desired, tt.observed); result != tt.expected {
+				t.Errorf("IsEnvironmentUpToDate() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package app
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+)
+
+// unlimitedLogRate is the log rate limit of an app whose log rate is not limited.
+const unlimitedLogRate = "-1"
+
+// logRateUnits maps the units of a log rate limit to their size in bytes, longest suffix first.
+var logRateUnits = []struct {
+	suffix string
+	bytes  int64
+}{
+	{"KB", 1024}, {"MB", 1024 * 1024}, {"GB", 1024 * 1024 * 1024},
+	{"K", 1024}, {"M", 1024 * 1024}, {"G", 1024 * 1024 * 1024}, {"B", 1},
+}
+
+// logRateLimitInBytes converts a log rate limit with a unit of measurement, e.g. 16K or 1MB, to bytes per second.
+// An unlimited log rate is -1.
+func logRateLimitInBytes(limit string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(limit))
+	if s == unlimitedLogRate {
+		return -1, nil
+	}
+	for _, u := range logRateUnits {
+		if !strings.HasSuffix(s, u.suffix) {
+			continue
+		}
+		n, err := strconv.ParseInt(strings.TrimSuffix(s, u.suffix), 10, 64)
+		if err != nil || n < 0 {
+			return 0, errors.Errorf("invalid log rate limit %q: must be a non-negative number with a unit of measurement", limit)
+		}
+		return n * u.bytes, nil
+	}
+	return 0, errors.Errorf("invalid log rate limit %q: unit of measurement must be one of B, K, KB, M, MB, G or GB", limit)
+}
+
+// logRateLimitDiffers compares the desired log rate limit with the processes of the observed manifest.
+func logRateLimitDiffers(spec v1alpha1.AppParameters, status v1alpha1.AppObservation) (bool, error) {
+	if spec.LogRateLimitPerSecond == nil {
+		return false, nil
+	}
+	want, err := logRateLimitInBytes(*spec.LogRateLimitPerSecond)
+	if err != nil || status.AppManifest == "" {
+		return false, err
+	}
+	appManifest, err := getAppManifest(status.Name, status.AppManifest)
+	if err != nil {
+		return false, err
+	}
+
+	observed := []string{appManifest.LogRateLimitPerSecond}
+	if appManifest.Processes != nil {
+		observed = observed[:0]
+		for _, p := range *appManifest.Processes {
+			observed = append(observed, p.LogRateLimitPerSecond)
+		}
+	}
+	for _, o := range observed {
+		got, err := logRateLimitInBytes(o)
+		if err != nil || got != want {
+			return true, nil
+		}
+	}
+	return false, nil
+}
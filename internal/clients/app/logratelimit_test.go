@@ -0,0 +1,88 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+)
+
+func TestLogRateLimitInBytes(t *testing.T) {
+	tests := []struct {
+		limit   string
+		bytes   int64
+		invalid bool
+	}{
+		{limit: "512B", bytes: 512},
+		{limit: "512b", bytes: 512},
+		{limit: "16K", bytes: 16 * 1024},
+		{limit: "16k", bytes: 16 * 1024},
+		{limit: "16KB", bytes: 16 * 1024},
+		{limit: "16kb", bytes: 16 * 1024},
+		{limit: "1M", bytes: 1024 * 1024},
+		{limit: "1m", bytes: 1024 * 1024},
+		{limit: "1MB", bytes: 1024 * 1024},
+		{limit: "1mb", bytes: 1024 * 1024},
+		{limit: "2G", bytes: 2 * 1024 * 1024 * 1024},
+		{limit: "2g", bytes: 2 * 1024 * 1024 * 1024},
+		{limit: "2GB", bytes: 2 * 1024 * 1024 * 1024},
+		{limit: "2gb", bytes: 2 * 1024 * 1024 * 1024},
+		{limit: "-1", bytes: -1},
+		{limit: "16", invalid: true},
+		{limit: "16TB", invalid: true},
+		{limit: "MB", invalid: true},
+		{limit: "-2K", invalid: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.limit, func(t *testing.T) {
+			got, err := logRateLimitInBytes(tc.limit)
+			if tc.invalid {
+				assert.ErrorContains(t, err, "invalid log rate limit")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.bytes, got)
+		})
+	}
+}
+
+func TestLogRateLimitDrift(t *testing.T) {
+	status := v1alpha1.AppObservation{
+		Name:        "test-app",
+		AppManifest: "applications:\n- name: test-app\n  processes:\n  - type: web\n    log-rate-limit-per-second: 16K\n  - type: worker\n    log-rate-limit-per-second: 1M",
+	}
+
+	tests := []struct {
+		name    string
+		limit   string
+		differs bool
+	}{
+		{name: "WorkerDrifted", limit: "16KB", differs: true},
+		{name: "Increased", limit: "1m", differs: true},
+		{name: "Unlimited", limit: "-1", differs: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := v1alpha1.AppParameters{Name: "test-app", LogRateLimitPerSecond: ptr.To(tc.limit)}
+			changes, err := DetectChanges(spec, status)
+			require.NoError(t, err)
+			assert.Equal(t, tc.differs, changes.HasField("log_rate_limit"))
+			assert.Equal(t, tc.differs, changes.HasManifestChanges())
+		})
+	}
+
+	upToDate := v1alpha1.AppObservation{
+		Name:        "test-app",
+		AppManifest: "applications:\n- name: test-app\n  processes:\n  - type: web\n    log-rate-limit-per-second: 1024K\n  - type: worker\n    log-rate-limit-per-second: 1M",
+	}
+	changes, err := DetectChanges(v1alpha1.AppParameters{Name: "test-app", LogRateLimitPerSecond: ptr.To("1MB")}, upToDate)
+	require.NoError(t, err)
+	assert.False(t, changes.HasChanges())
+
+	_, err = DetectChanges(v1alpha1.AppParameters{Name: "test-app", LogRateLimitPerSecond: ptr.To("1X")}, upToDate)
+	assert.ErrorContains(t, err, "unit of measurement must be one of")
+}
@@ -0,0 +1,184 @@
+package app
+
+import (
+	"context"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/operation"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+)
+
+const webProcessType = "web"
+
+// healthCheckDrift holds the desired health checks of a process that differ from the observed ones.
+// A nil check is up-to-date.
+type healthCheckDrift struct {
+	liveness  *processHealthCheck
+	readiness *v1alpha1.ReadinessHealthCheckConfiguration
+}
+
+// processHealthCheck is the desired liveness health check of a process
+type processHealthCheck struct {
+	v1alpha1.HealthCheckConfiguration
+	Timeout *uint
+}
+
+// desiredHealthChecks returns the liveness and readiness health checks of the spec by process type.
+// The app-level readiness health check applies to the web process unless the process overrides it.
+func desiredHealthChecks(spec v1alpha1.AppParameters) map[string]healthCheckDrift {
+	desired := map[string]healthCheckDrift{}
+	for _, p := range spec.Processes {
+		processType := ptr.Deref(p.Type, webProcessType)
+		desired[processType] = healthCheckDrift{
+			liveness:  &processHealthCheck{HealthCheckConfiguration: p.HealthCheckConfiguration, Timeout: p.Timeout},
+			readiness: p.ReadinessHealthCheckConfiguration.DeepCopy(),
+		}
+	}
+
+	app := spec.ReadinessHealthCheckConfiguration
+	if app == (v1alpha1.ReadinessHealthCheckConfiguration{}) {
+		return desired
+	}
+	web, ok := desired[webProcessType]
+	if !ok {
+		web = healthCheckDrift{readiness: &v1alpha1.ReadinessHealthCheckConfiguration{}}
+	}
+	web.readiness.ReadinessHealthCheckType = firstNonNil(web.readiness.ReadinessHealthCheckType, app.ReadinessHealthCheckType)
+	web.readiness.ReadinessHealthCheckHTTPEndpoint = firstNonNil(web.readiness.ReadinessHealthCheckHTTPEndpoint, app.ReadinessHealthCheckHTTPEndpoint)
+	web.readiness.ReadinessHealthCheckInterval = firstNonNil(web.readiness.ReadinessHealthCheckInterval, app.ReadinessHealthCheckInterval)
+	web.readiness.ReadinessHealthCheckInvocationTimeout = firstNonNil(web.readiness.ReadinessHealthCheckInvocationTimeout, app.ReadinessHealthCheckInvocationTimeout)
+	desired[webProcessType] = web
+
+	return desired
+}
+
+// diffHealthChecks compares the desired health checks with the processes of the observed manifest.
+// Processes missing from the manifest are skipped, they are configured when the app is pushed.
+func diffHealthChecks(spec v1alpha1.AppParameters, status v1alpha1.AppObservation) (map[string]healthCheckDrift, error) {
+	desired := desiredHealthChecks(spec)
+	if status.AppManifest == "" || len(desired) == 0 {
+		return nil, nil
+	}
+	appManifest, err := getAppManifest(status.Name, status.AppManifest)
+	if err != nil {
+		return nil, err
+	}
+
+	drift := map[string]healthCheckDrift{}
+	for processType, want := range desired {
+		got := observedProcess(appManifest, processType)
+		if got == nil {
+			continue
+		}
+		d := healthCheckDrift{}
+		if want.liveness != nil && livenessDiffers(*want.liveness, *got) {
+			d.liveness = want.liveness
+		}
+		if want.readiness != nil && readinessDiffers(*want.readiness, *got) {
+			d.readiness = want.readiness
+		}
+		if d.liveness != nil || d.readiness != nil {
+			drift[processType] = d
+		}
+	}
+	return drift, nil
+}
+
+// diffCommands returns the desired commands by process type that differ from the processes of the observed manifest.
+// Processes missing from the manifest are skipped, they are configured when the app is pushed.
+func diffCommands(spec v1alpha1.AppParameters, status v1alpha1.AppObservation) (map[string]string, error) {
+	desired := map[string]string{}
+	for _, p := range spec.Processes {
+		if p.Command != nil {
+			desired[ptr.Deref(p.Type, webProcessType)] = *p.Command
+		}
+	}
+	if status.AppManifest == "" || len(desired) == 0 {
+		return nil, nil
+	}
+	appManifest, err := getAppManifest(status.Name, status.AppManifest)
+	if err != nil {
+		return nil, err
+	}
+
+	commands := map[string]string{}
+	for processType, command := range desired {
+		got := observedProcess(appManifest, processType)
+		if got == nil || got.Command == command {
+			continue
+		}
+		commands[processType] = command
+	}
+	return commands, nil
+}
+
+// UpdateCommands updates the commands of the processes of an app that differ
+// from the observed ones. The instances of a process run the new command once
+// they are restarted, the droplet of the app is not restaged.
+func (c *Client) UpdateCommands(ctx context.Context, guid string, spec v1alpha1.AppParameters, status v1alpha1.AppObservation) error {
+	commands, err := diffCommands(spec, status)
+	if err != nil {
+		return err
+	}
+	for processType, command := range commands {
+		opts := client.NewProcessOptions()
+		opts.Types = client.Filter{Values: []string{processType}}
+		process, err := c.ProcessClient.SingleForApp(ctx, guid, opts)
+		if err != nil {
+			return errors.Wrapf(err, "cannot find the %s process", processType)
+		}
+		if _, err := c.ProcessClient.Update(ctx, process.GUID, &resource.ProcessUpdate{Command: ptr.To(command)}); err != nil {
+			return errors.Wrapf(err, "cannot update the command of the %s process", processType)
+		}
+	}
+	return nil
+}
+
+// observedProcess returns the process of the given type from the manifest.
+// The web process may be described by the top-level app attributes.
+func observedProcess(appManifest *operation.AppManifest, processType string) *operation.AppManifestProcess {
+	if appManifest.Processes != nil {
+		for i, p := range *appManifest.Processes {
+			if string(p.Type) == processType {
+				return &(*appManifest.Processes)[i]
+			}
+		}
+	}
+	if processType == webProcessType {
+		return &appManifest.AppManifestProcess
+	}
+	return nil
+}
+
+func livenessDiffers(want processHealthCheck, got operation.AppManifestProcess) bool {
+	return differs(want.HealthCheckType, string(got.HealthCheckType)) ||
+		differs(want.HealthCheckHTTPEndpoint, got.HealthCheckHTTPEndpoint) ||
+		differs(want.HealthCheckInterval, got.HealthCheckInterval) ||
+		differs(want.HealthCheckInvocationTimeout, got.HealthCheckInvocationTimeout) ||
+		differs(want.Timeout, got.Timeout)
+}
+
+func readinessDiffers(want v1alpha1.ReadinessHealthCheckConfiguration, got operation.AppManifestProcess) bool {
+	return differs(want.ReadinessHealthCheckType, got.ReadinessHealthCheckType) ||
+		differs(want.ReadinessHealthCheckHTTPEndpoint, got.ReadinessHealthCheckHttpEndpoint) ||
+		differs(want.ReadinessHealthCheckInterval, got.ReadinessHealthCheckInterval) ||
+		differs(want.ReadinessHealthCheckInvocationTimeout, got.ReadinessHealthInvocationTimeout)
+}
+
+// differs reports whether a desired value is set and different from the observed one
+func differs[T comparable](want *T, got T) bool {
+	return want != nil && *want != got
+}
+
+func firstNonNil[T any](values ...*T) *T {
+	for _, v := range values {
+		if v != nil {
+			return v
+		}
+	}
+	return nil
+}
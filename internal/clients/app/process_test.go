@@ -0,0 +1,76 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+)
+
+func TestDiffHealthChecksReadinessOnly(t *testing.T) {
+	spec := v1alpha1.AppParameters{
+		Name: "test-app",
+		ReadinessHealthCheckConfiguration: v1alpha1.ReadinessHealthCheckConfiguration{
+			ReadinessHealthCheckType:         ptr.To("http"),
+			ReadinessHealthCheckHTTPEndpoint: ptr.To("/ready"),
+		},
+	}
+	status := v1alpha1.AppObservation{
+		Name:        "test-app",
+		AppManifest: "applications:\n- name: test-app\n  processes:\n  - type: web\n    health-check-type: http\n    health-check-http-endpoint: /health\n    readiness-health-check-type: process",
+	}
+
+	drift, err := diffHealthChecks(spec, status)
+	require.NoError(t, err)
+	require.Contains(t, drift, "web")
+	assert.Nil(t, drift["web"].liveness, "liveness health check must not drift when only readiness changed")
+	require.NotNil(t, drift["web"].readiness)
+	assert.Equal(t, ptr.To("http"), drift["web"].readiness.ReadinessHealthCheckType)
+	assert.Equal(t, ptr.To("/ready"), drift["web"].readiness.ReadinessHealthCheckHTTPEndpoint)
+}
+
+func TestDiffHealthChecksUpToDate(t *testing.T) {
+	spec := v1alpha1.AppParameters{
+		Name: "test-app",
+		Processes: []v1alpha1.ProcessConfiguration{
+			{
+				Type:                     ptr.To("web"),
+				HealthCheckConfiguration: v1alpha1.HealthCheckConfiguration{HealthCheckType: ptr.To("http")},
+			},
+		},
+	}
+	status := v1alpha1.AppObservation{
+		Name:        "test-app",
+		AppManifest: "applications:\n- name: test-app\n  processes:\n  - type: web\n    health-check-type: http",
+	}
+
+	drift, err := diffHealthChecks(spec, status)
+	require.NoError(t, err)
+	assert.Empty(t, drift)
+}
+
+func TestDiffHealthChecksLivenessOnly(t *testing.T) {
+	spec := v1alpha1.AppParameters{
+		Name: "test-app",
+		Processes: []v1alpha1.ProcessConfiguration{
+			{
+				Type:                     ptr.To("web"),
+				HealthCheckConfiguration: v1alpha1.HealthCheckConfiguration{HealthCheckType: ptr.To("port")},
+			},
+		},
+	}
+	status := v1alpha1.AppObservation{
+		Name:        "test-app",
+		AppManifest: "applications:\n- name: test-app\n  processes:\n  - type: web\n    health-check-type: http\n    readiness-health-check-type: process",
+	}
+
+	drift, err := diffHealthChecks(spec, status)
+	require.NoError(t, err)
+	require.Contains(t, drift, "web")
+	assert.Nil(t, drift["web"].readiness, "readiness health check must not drift when only liveness changed")
+	require.NotNil(t, drift["web"].liveness)
+	assert.Equal(t, ptr.To("port"), drift["web"].liveness.HealthCheckType)
+}
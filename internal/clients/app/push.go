@@ -2,6 +2,7 @@ package app
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"io"
 	"os"
@@ -63,11 +64,25 @@ func (p *pushClient) GenerateManifest(ctx context.Context, appGUID string) (stri
 	return p.client.Manifests.Generate(ctx, appGUID)
 }
 
+// RenderManifest renders the Cloud Foundry application manifest of the app spec, as applied to the space of the app.
+func RenderManifest(forProvider v1alpha1.AppParameters, dockerCredentials *DockerCredentials, env map[string]string) (string, error) {
+	manifest, err := newManifestFromSpec(forProvider, dockerCredentials, env)
+	if err != nil {
+		return "", err
+	}
+	out, err := yaml.Marshal(&operation.Manifest{Applications: []*operation.AppManifest{manifest}})
+	if err != nil {
+		return "", errors.Wrap(err, "cannot render app manifest")
+	}
+	return string(out), nil
+}
+
 // newManifest maps the app spec to the manifest
 //
 //nolint:gocyclo
-func newManifestFromSpec(forProvider v1alpha1.AppParameters, dockerCredentials *DockerCredentials) (*operation.AppManifest, error) {
+func newManifestFromSpec(forProvider v1alpha1.AppParameters, dockerCredentials *DockerCredentials, env map[string]string) (*operation.AppManifest, error) {
 	manifest := operation.NewAppManifest(forProvider.Name)
+	manifest.Env = env
 
 	if forProvider.Lifecycle == "docker" {
 		docker, err := configDocker(forProvider, dockerCredentials)
@@ -77,6 +92,13 @@ func newManifestFromSpec(forProvider v1alpha1.AppParameters, dockerCredentials *
 		manifest.Docker = docker
 	}
 
+	if forProvider.Lifecycle == "buildpack" {
+		manifest.Buildpacks = forProvider.Buildpacks
+		if forProvider.Stack != nil {
+			manifest.Stack = *forProvider.Stack
+		}
+	}
+
 	services, err := configServices(forProvider)
 	if err != nil {
 		return nil, err
@@ -96,6 +118,14 @@ func newManifestFromSpec(forProvider v1alpha1.AppParameters, dockerCredentials *
 
 	manifest.Processes = configProcess(forProvider)
 
+	if forProvider.Instances != nil {
+		manifest.Instances = forProvider.Instances
+	}
+
+	if forProvider.Memory != nil {
+		manifest.Memory = *forProvider.Memory
+	}
+
 	if forProvider.ReadinessHealthCheckType != nil {
 		manifest.ReadinessHealthCheckType = *forProvider.ReadinessHealthCheckType
 	}
@@ -138,6 +168,15 @@ func configDocker(forProvider v1alpha1.AppParameters, dockerCredentials *DockerC
 	return docker, nil
 }
 
+// HashDockerCredentials returns the hash of the docker registry credentials, or nil if there are none.
+func HashDockerCredentials(dockerCredentials *DockerCredentials) []byte {
+	if dockerCredentials == nil {
+		return nil
+	}
+	s := sha256.Sum256([]byte(dockerCredentials.Username + "\x00" + dockerCredentials.Password))
+	return s[:]
+}
+
 // configProcess map the process from app spec
 //
 //nolint:gocyclo
@@ -176,6 +215,21 @@ func configProcess(forProvider v1alpha1.AppParameters) *operation.AppManifestPro
 			if process.Instances != nil {
 				processManifest.Instances = process.Instances
 			}
+			if forProvider.LogRateLimitPerSecond != nil {
+				processManifest.LogRateLimitPerSecond = *forProvider.LogRateLimitPerSecond
+			}
+			if process.ReadinessHealthCheckType != nil {
+				processManifest.ReadinessHealthCheckType = *process.ReadinessHealthCheckType
+			}
+			if process.ReadinessHealthCheckHTTPEndpoint != nil {
+				processManifest.ReadinessHealthCheckHttpEndpoint = *process.ReadinessHealthCheckHTTPEndpoint
+			}
+			if process.ReadinessHealthCheckInterval != nil {
+				processManifest.ReadinessHealthCheckInterval = *process.ReadinessHealthCheckInterval
+			}
+			if process.ReadinessHealthCheckInvocationTimeout != nil {
+				processManifest.ReadinessHealthInvocationTimeout = *process.ReadinessHealthCheckInvocationTimeout
+			}
 
 			processes = append(processes, processManifest)
 		}
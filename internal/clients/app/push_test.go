@@ -0,0 +1,36 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+)
+
+func TestRenderManifest(t *testing.T) {
+	spec := v1alpha1.AppParameters{
+		Name:       "test-app",
+		Lifecycle:  "buildpack",
+		Buildpacks: []string{"go_buildpack"},
+		Stack:      ptr.To("cflinuxfs4"),
+		Instances:  ptr.To[uint](2),
+		Memory:     ptr.To("1G"),
+		Routes:     []v1alpha1.RouteConfiguration{{Route: ptr.To("test-app.example.com")}},
+	}
+
+	manifest, err := RenderManifest(spec, nil, map[string]string{"FOO": "bar"})
+	require.NoError(t, err)
+
+	got, err := getAppManifest("test-app", manifest)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go_buildpack"}, got.Buildpacks)
+	assert.Equal(t, "cflinuxfs4", got.Stack)
+	assert.Equal(t, ptr.To[uint](2), got.Instances)
+	assert.Equal(t, "1G", got.Memory)
+	assert.Equal(t, map[string]string{"FOO": "bar"}, got.Env)
+	require.NotNil(t, got.Routes)
+	assert.Equal(t, "test-app.example.com", (*got.Routes)[0].Route)
+}
@@ -0,0 +1,38 @@
+package app
+
+import (
+	"context"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/pkg/errors"
+)
+
+const (
+	errAppNameEmpty = "appName is empty"
+	errAppNotFound  = "cannot find app %q in space %s"
+	errAppAmbiguous = "app name %q is ambiguous in space %s"
+)
+
+// GetGUID returns the GUID of an app by name within a space. It returns an
+// error if no app or more than one app has the name.
+func GetGUID(ctx context.Context, appClient AppClient, spaceGUID, appName string) (*string, error) {
+	if appName == "" {
+		return nil, errors.New(errAppNameEmpty)
+	}
+	opts := client.NewAppListOptions()
+	opts.Names = client.Filter{Values: []string{appName}}
+	opts.SpaceGUIDs = client.Filter{Values: []string{spaceGUID}}
+
+	apps, err := appClient.ListAll(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	switch len(apps) {
+	case 0:
+		return nil, errors.Errorf(errAppNotFound, appName, spaceGUID)
+	case 1:
+		return &apps[0].GUID, nil
+	default:
+		return nil, errors.Errorf(errAppAmbiguous, appName, spaceGUID)
+	}
+}
@@ -0,0 +1,48 @@
+package app
+
+import (
+	"k8s.io/utils/ptr"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
+)
+
+// desiredWebScale returns the instances and memory of the web process from the spec.
+// The `web` entry of the processes takes precedence over the app-level attributes.
+func desiredWebScale(spec v1alpha1.AppParameters) (instances *uint, memory *string) {
+	instances, memory = spec.Instances, spec.Memory
+	for _, p := range spec.Processes {
+		if ptr.Deref(p.Type, webProcessType) != webProcessType {
+			continue
+		}
+		instances = firstNonNil(p.Instances, instances)
+		memory = firstNonNil(p.Memory, memory)
+	}
+	return instances, memory
+}
+
+// webScaleDiffers compares the desired instances and memory of the web process with the observed manifest.
+func webScaleDiffers(spec v1alpha1.AppParameters, status v1alpha1.AppObservation) (bool, error) {
+	instances, memory := desiredWebScale(spec)
+	if status.AppManifest == "" || (instances == nil && memory == nil) {
+		return false, nil
+	}
+	appManifest, err := getAppManifest(status.Name, status.AppManifest)
+	if err != nil {
+		return false, err
+	}
+	got := observedProcess(appManifest, webProcessType)
+
+	if instances != nil && (got.Instances == nil || *got.Instances != *instances) {
+		return true, nil
+	}
+	if memory == nil {
+		return false, nil
+	}
+	want, err := clients.MemoryInMB(*memory)
+	if err != nil {
+		return false, err
+	}
+	observed, err := clients.MemoryInMB(got.Memory)
+	return err != nil || observed != want, nil
+}
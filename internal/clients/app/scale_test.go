@@ -0,0 +1,60 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+)
+
+const webManifest = "applications:\n- name: test-app\n  processes:\n  - type: web\n    instances: 1\n    memory: 256M\n  - type: worker\n    instances: 1\n    memory: 256M"
+
+func TestWebScaleDiffers(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    v1alpha1.AppParameters
+		differs bool
+	}{
+		{
+			name:    "ScaleUp",
+			spec:    v1alpha1.AppParameters{Name: "test-app", Instances: ptr.To[uint](3), Memory: ptr.To("256M")},
+			differs: true,
+		},
+		{
+			name:    "MemoryChanged",
+			spec:    v1alpha1.AppParameters{Name: "test-app", Instances: ptr.To[uint](1), Memory: ptr.To("1G")},
+			differs: true,
+		},
+		{
+			name: "WebProcessTakesPrecedence",
+			spec: v1alpha1.AppParameters{
+				Name:      "test-app",
+				Instances: ptr.To[uint](1),
+				Processes: []v1alpha1.ProcessConfiguration{{Type: ptr.To("web"), Instances: ptr.To[uint](2)}},
+			},
+			differs: true,
+		},
+		{
+			name: "UpToDate",
+			spec: v1alpha1.AppParameters{Name: "test-app", Instances: ptr.To[uint](1), Memory: ptr.To("256mb")},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			status := v1alpha1.AppObservation{Name: "test-app", AppManifest: webManifest}
+
+			differs, err := webScaleDiffers(tc.spec, status)
+			require.NoError(t, err)
+			assert.Equal(t, tc.differs, differs)
+
+			changes, err := DetectChanges(tc.spec, status)
+			require.NoError(t, err)
+			assert.Equal(t, tc.differs, changes.HasField("scale"))
+			assert.Equal(t, tc.differs, changes.HasManifestChanges())
+		})
+	}
+}
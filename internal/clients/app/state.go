@@ -0,0 +1,65 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The states of an app.
+const (
+	StateStarted = "STARTED"
+	StateStopped = "STOPPED"
+)
+
+// TypeDesiredState is the condition type that reports whether an app is in
+// the state its spec asks for.
+const TypeDesiredState xpv1.ConditionType = "DesiredState"
+
+// Reasons of the DesiredState condition.
+const (
+	ReasonStarted  xpv1.ConditionReason = "Started"
+	ReasonStopped  xpv1.ConditionReason = "Stopped"
+	ReasonStarting xpv1.ConditionReason = "Starting"
+	ReasonStopping xpv1.ConditionReason = "Stopping"
+)
+
+// SetState starts or stops an app.
+func (c *Client) SetState(ctx context.Context, guid, state string) (*resource.App, error) {
+	switch state {
+	case StateStarted:
+		return c.AppClient.Start(ctx, guid)
+	case StateStopped:
+		return c.AppClient.Stop(ctx, guid)
+	}
+	return nil, errors.Errorf("unknown app state %q", state)
+}
+
+// DesiredState returns the DesiredState condition of an app in the observed
+// state whose spec asks for the desired state.
+func DesiredState(desired, observed string) xpv1.Condition {
+	c := xpv1.Condition{
+		Type:               TypeDesiredState,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonStarted,
+	}
+	if desired == StateStopped {
+		c.Reason = ReasonStopped
+		c.Message = "app is stopped as desired"
+	}
+	if observed != desired {
+		c.Status = corev1.ConditionFalse
+		c.Reason = ReasonStarting
+		if desired == StateStopped {
+			c.Reason = ReasonStopping
+		}
+		c.Message = fmt.Sprintf("app is %s, desired state is %s", observed, desired)
+	}
+	return c
+}
@@ -0,0 +1,171 @@
+package buildpack
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/job"
+)
+
+// StateAwaitingUpload is the state of a buildpack whose bits have not been uploaded yet.
+const StateAwaitingUpload = "AWAITING_UPLOAD"
+
+// Client is the interface that defines the methods that a Buildpack client should implement.
+type Client interface {
+	Get(ctx context.Context, guid string) (*resource.Buildpack, error)
+	Single(ctx context.Context, opts *client.BuildpackListOptions) (*resource.Buildpack, error)
+	Create(ctx context.Context, r *resource.BuildpackCreateOrUpdate) (*resource.Buildpack, error)
+	Update(ctx context.Context, guid string, r *resource.BuildpackCreateOrUpdate) (*resource.Buildpack, error)
+	Delete(ctx context.Context, guid string) (string, error)
+	Upload(ctx context.Context, guid string, fileName string, zipFile io.Reader) (string, *resource.Buildpack, error)
+}
+
+// NewClient creates a new client instance from a cfclient.Buildpack instance.
+func NewClient(cf *client.Client) Client {
+	return cf.Buildpacks
+}
+
+// GetByIDOrName returns a buildpack by GUID, or by name and stack if the GUID
+// is not valid. Buildpacks are unique by name and stack.
+func GetByIDOrName(ctx context.Context, c Client, guid string, spec v1alpha1.BuildpackParameters) (*resource.Buildpack, error) {
+	if clients.IsValidGUID(guid) {
+		return c.Get(ctx, guid)
+	}
+
+	opts := client.NewBuildpackListOptions()
+	opts.Names.EqualTo(spec.Name)
+	if spec.Stack != nil {
+		opts.Stacks.EqualTo(*spec.Stack)
+	}
+	return c.Single(ctx, opts)
+}
+
+// GenerateCreate generates the BuildpackCreateOrUpdate to create a buildpack
+// from BuildpackParameters. A buildpack with a source is created unlocked, it
+// is locked once its bits are uploaded.
+func GenerateCreate(spec v1alpha1.BuildpackParameters) *resource.BuildpackCreateOrUpdate {
+	create := &resource.BuildpackCreateOrUpdate{
+		Name:     ptr.To(spec.Name),
+		Position: spec.Position,
+		Enabled:  spec.Enabled,
+		Locked:   spec.Locked,
+		Stack:    spec.Stack,
+	}
+	if spec.Source != nil {
+		create.Locked = nil
+	}
+	return create
+}
+
+// GenerateUpdate generates the BuildpackCreateOrUpdate to update a buildpack
+// from BuildpackParameters. The stack is always sent by Cloud Foundry clients,
+// the observed stack is kept if the spec does not set one.
+func GenerateUpdate(spec v1alpha1.BuildpackParameters, observed v1alpha1.BuildpackObservation) *resource.BuildpackCreateOrUpdate {
+	update := &resource.BuildpackCreateOrUpdate{
+		Name:     ptr.To(spec.Name),
+		Position: spec.Position,
+		Enabled:  spec.Enabled,
+		Locked:   spec.Locked,
+		Stack:    spec.Stack,
+	}
+	if update.Stack == nil {
+		update.Stack = observed.Stack
+	}
+	return update
+}
+
+// GenerateObservation takes a Buildpack resource and returns BuildpackObservation.
+// The source is not known to Cloud Foundry and is kept from the previous observation.
+func GenerateObservation(bp *resource.Buildpack, source *string) v1alpha1.BuildpackObservation {
+	return v1alpha1.BuildpackObservation{
+		ID:        ptr.To(bp.GUID),
+		Name:      ptr.To(bp.Name),
+		State:     ptr.To(bp.State),
+		Filename:  bp.Filename,
+		Stack:     bp.Stack,
+		Position:  ptr.To(bp.Position),
+		Enabled:   ptr.To(bp.Enabled),
+		Locked:    ptr.To(bp.Locked),
+		Source:    source,
+		CreatedAt: ptr.To(bp.CreatedAt.Format(time.RFC3339)),
+		UpdatedAt: ptr.To(bp.UpdatedAt.Format(time.RFC3339)),
+	}
+}
+
+// LateInitialize fills the position and flags that are not set in the spec
+// with the values observed in Cloud Foundry. It returns true if the spec was
+// changed.
+func LateInitialize(spec *v1alpha1.BuildpackParameters, bp *resource.Buildpack) bool {
+	li := false
+	if spec.Position == nil {
+		spec.Position = ptr.To(bp.Position)
+		li = true
+	}
+	if spec.Enabled == nil {
+		spec.Enabled = ptr.To(bp.Enabled)
+		li = true
+	}
+	if spec.Locked == nil {
+		spec.Locked = ptr.To(bp.Locked)
+		li = true
+	}
+	return li
+}
+
+// IsUpToDate checks whether the buildpack is up-to-date compared to the spec.
+// The bits are checked separately by NeedsUpload.
+func IsUpToDate(spec v1alpha1.BuildpackParameters, bp *resource.Buildpack) bool {
+	if spec.Name != bp.Name {
+		return false
+	}
+	if spec.Position != nil && *spec.Position != bp.Position {
+		return false
+	}
+	if spec.Enabled != nil && *spec.Enabled != bp.Enabled {
+		return false
+	}
+	if spec.Locked != nil && *spec.Locked != bp.Locked {
+		return false
+	}
+	return spec.Stack == nil || *spec.Stack == ptr.Deref(bp.Stack, "")
+}
+
+// NeedsUpload checks whether the bits of the buildpack must be uploaded from
+// the source in the spec, because the buildpack awaits them or the source
+// changed since the last upload.
+func NeedsUpload(spec v1alpha1.BuildpackParameters, observed v1alpha1.BuildpackObservation) bool {
+	if spec.Source == nil {
+		return false
+	}
+	return ptr.Deref(observed.State, "") == StateAwaitingUpload || ptr.Deref(observed.Source, "") != *spec.Source
+}
+
+// An Opener opens the source of the bits of a buildpack and returns them with
+// their file name.
+type Opener interface {
+	Open(ctx context.Context, source string) (io.ReadCloser, string, error)
+}
+
+// Upload uploads the bits of the buildpack from the source, e.g. an https URL
+// that the Sources allow, and waits for the upload to complete.
+func Upload(ctx context.Context, c Client, j job.Job, o Opener, guid, source string) error {
+	bits, fileName, err := o.Open(ctx, source)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = bits.Close() }()
+
+	jobGUID, _, err := c.Upload(ctx, guid, fileName, bits)
+	if err != nil {
+		return errors.Wrap(err, "cannot upload buildpack bits")
+	}
+	return job.PollJobComplete(ctx, j, jobGUID)
+}
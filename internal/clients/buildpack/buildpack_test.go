@@ -0,0 +1,200 @@
+package buildpack
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/fake"
+)
+
+const (
+	bpGUID  = "b85a788e-671f-4549-814d-e34cdb2f539a"
+	jobGUID = "3d8b0d04-d537-4e4e-8c6f-f09ca0e7f56f"
+	source  = "https://example.com/go-buildpack-v1.zip"
+)
+
+func TestIsUpToDate(t *testing.T) {
+	observed := &fake.NewBuildpack().SetName("go_buildpack").SetStack("cflinuxfs4").SetPosition(2).SetEnabled(true).SetLocked(false).Buildpack
+
+	tests := []struct {
+		name     string
+		spec     v1alpha1.BuildpackParameters
+		expected bool
+	}{
+		{
+			name:     "Unset attributes",
+			spec:     v1alpha1.BuildpackParameters{Name: "go_buildpack"},
+			expected: true,
+		},
+		{
+			name:     "Same attributes",
+			spec:     v1alpha1.BuildpackParameters{Name: "go_buildpack", Position: ptr.To(2), Enabled: ptr.To(true), Locked: ptr.To(false), Stack: ptr.To("cflinuxfs4")},
+			expected: true,
+		},
+		{
+			name:     "Changed position",
+			spec:     v1alpha1.BuildpackParameters{Name: "go_buildpack", Position: ptr.To(1)},
+			expected: false,
+		},
+		{
+			name:     "Locked",
+			spec:     v1alpha1.BuildpackParameters{Name: "go_buildpack", Locked: ptr.To(true)},
+			expected: false,
+		},
+		{
+			name:     "Changed stack",
+			spec:     v1alpha1.BuildpackParameters{Name: "go_buildpack", Stack: ptr.To("cflinuxfs3")},
+			expected: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, IsUpToDate(tc.spec, observed))
+		})
+	}
+}
+
+func TestNeedsUpload(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     v1alpha1.BuildpackParameters
+		observed v1alpha1.BuildpackObservation
+		expected bool
+	}{
+		{
+			name:     "No source",
+			observed: v1alpha1.BuildpackObservation{State: ptr.To(StateAwaitingUpload)},
+			expected: false,
+		},
+		{
+			name:     "Awaiting upload",
+			spec:     v1alpha1.BuildpackParameters{Source: ptr.To(source)},
+			observed: v1alpha1.BuildpackObservation{State: ptr.To(StateAwaitingUpload), Source: ptr.To(source)},
+			expected: true,
+		},
+		{
+			name:     "Changed source",
+			spec:     v1alpha1.BuildpackParameters{Source: ptr.To(source)},
+			observed: v1alpha1.BuildpackObservation{State: ptr.To("READY"), Source: ptr.To("https://example.com/go-buildpack-v0.zip")},
+			expected: true,
+		},
+		{
+			name:     "Uploaded",
+			spec:     v1alpha1.BuildpackParameters{Source: ptr.To(source)},
+			observed: v1alpha1.BuildpackObservation{State: ptr.To("READY"), Source: ptr.To(source)},
+			expected: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, NeedsUpload(tc.spec, tc.observed))
+		})
+	}
+}
+
+func TestGenerateCreate(t *testing.T) {
+	spec := v1alpha1.BuildpackParameters{Name: "go_buildpack", Locked: ptr.To(true)}
+	assert.Equal(t, ptr.To(true), GenerateCreate(spec).Locked)
+
+	// a buildpack with bits is locked after the upload
+	spec.Source = ptr.To(source)
+	assert.Nil(t, GenerateCreate(spec).Locked)
+}
+
+func TestGenerateUpdate(t *testing.T) {
+	observed := v1alpha1.BuildpackObservation{Stack: ptr.To("cflinuxfs4")}
+
+	update := GenerateUpdate(v1alpha1.BuildpackParameters{Name: "go_buildpack"}, observed)
+	assert.Equal(t, ptr.To("cflinuxfs4"), update.Stack)
+
+	update = GenerateUpdate(v1alpha1.BuildpackParameters{Name: "go_buildpack", Stack: ptr.To("cflinuxfs3")}, observed)
+	assert.Equal(t, ptr.To("cflinuxfs3"), update.Stack)
+}
+
+// newSourceServer returns an https server that serves the bits of a
+// buildpack, and Sources that trust it and allow its /buildpacks/ path.
+func newSourceServer(t *testing.T) (*httptest.Server, *Sources) {
+	t.Helper()
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/buildpacks/redirect.zip":
+			http.Redirect(w, r, "/internal/secret.zip", http.StatusFound)
+		case "/buildpacks/go-buildpack.zip", "/internal/secret.zip":
+			_, _ = w.Write([]byte("bits"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	s := NewSources([]string{srv.URL + "/buildpacks/", "http://example.com/", "/var/run/secrets/"})
+	s.client.Transport = srv.Client().Transport
+	return srv, s
+}
+
+func TestUpload(t *testing.T) {
+	srv, s := newSourceServer(t)
+
+	c := &fake.MockBuildpack{}
+	c.On("Upload", bpGUID, "go-buildpack.zip").Return(jobGUID, &resource.Buildpack{}, nil)
+	j := &fake.MockJob{}
+	j.On("PollComplete").Return(nil)
+
+	require.NoError(t, Upload(context.Background(), c, j, s, bpGUID, srv.URL+"/buildpacks/go-buildpack.zip"))
+	c.AssertExpectations(t)
+	j.AssertExpectations(t)
+}
+
+func TestSourcesOpen(t *testing.T) {
+	srv, s := newSourceServer(t)
+
+	tests := []struct {
+		name    string
+		source  string
+		maxSize int64
+		wantErr bool
+	}{
+		{name: "Allowed", source: srv.URL + "/buildpacks/go-buildpack.zip"},
+		{name: "LocalPath", source: "/var/run/secrets/kubernetes.io/serviceaccount/token", wantErr: true},
+		{name: "FileURL", source: "file:///var/run/secrets/kubernetes.io/serviceaccount/token", wantErr: true},
+		{name: "HTTP", source: "http://example.com/go-buildpack.zip", wantErr: true},
+		{name: "OtherPath", source: srv.URL + "/internal/secret.zip", wantErr: true},
+		{name: "PathPrefixOfSegment", source: srv.URL + "/buildpacks-internal/secret.zip", wantErr: true},
+		{name: "DotSegments", source: srv.URL + "/buildpacks/../internal/secret.zip", wantErr: true},
+		{name: "OtherHost", source: "https://169.254.169.254/buildpacks/go-buildpack.zip", wantErr: true},
+		{name: "RedirectToOtherPath", source: srv.URL + "/buildpacks/redirect.zip", wantErr: true},
+		{name: "NotFound", source: srv.URL + "/buildpacks/missing.zip", wantErr: true},
+		{name: "TooLarge", source: srv.URL + "/buildpacks/go-buildpack.zip", maxSize: 3, wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s.maxSize = MaxSourceSize
+			if tc.maxSize > 0 {
+				s.maxSize = tc.maxSize
+			}
+			bits, fileName, err := s.Open(context.Background(), tc.source)
+			if err == nil {
+				defer func() { _ = bits.Close() }()
+				var b []byte
+				b, err = io.ReadAll(bits)
+				if err == nil {
+					assert.Equal(t, "bits", string(b))
+					assert.Equal(t, "go-buildpack.zip", fileName)
+				}
+			}
+			assert.Equal(t, tc.wantErr, err != nil, "Open(%q): %v", tc.source, err)
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package buildpack
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// DownloadTimeout bounds the download of the bits of a buildpack.
+	DownloadTimeout = 5 * time.Minute
+
+	// MaxSourceSize is the largest size of the bits of a buildpack that are
+	// downloaded, well above the size of the buildpacks of Cloud Foundry.
+	MaxSourceSize = 1 << 30
+
+	errSourceNotAllowed = "buildpack source %q is not an https URL that the allowedBuildpackSources of the ProviderConfig allow"
+	errDownload         = "cannot download buildpack source %q"
+	errSourceTooLarge   = "buildpack source %q is larger than %d bytes"
+)
+
+// Sources downloads the bits of buildpacks from the https URLs that the
+// allowedBuildpackSources of a ProviderConfig allow. Buildpacks are
+// namespaced, so their sources are never read from the file system of the
+// provider nor from URLs that the ProviderConfig does not name.
+type Sources struct {
+	allowed []*url.URL
+	client  *http.Client
+	maxSize int64
+}
+
+// NewSources returns Sources that download from the given https URL
+// prefixes. Prefixes that are not https URLs are ignored.
+func NewSources(allowed []string) *Sources {
+	s := &Sources{maxSize: MaxSourceSize}
+	for _, a := range allowed {
+		if u, err := url.Parse(a); err == nil && u.Scheme == "https" && u.Host != "" {
+			s.allowed = append(s.allowed, u)
+		}
+	}
+	s.client = &http.Client{
+		Timeout: DownloadTimeout,
+		// a redirect must stay within the allowed sources
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			if !s.allows(req.URL) {
+				return errors.Errorf(errSourceNotAllowed, req.URL.String())
+			}
+			return nil
+		},
+	}
+	return s
+}
+
+// allows returns true if the URL is an https URL that starts with one of the
+// allowed prefixes. The path is compared by segments after resolving dot
+// segments, so that a prefix cannot be escaped with `..`.
+func (s *Sources) allows(u *url.URL) bool {
+	if u.Scheme != "https" || u.User != nil {
+		return false
+	}
+	p := path.Clean("/" + u.Path)
+	for _, a := range s.allowed {
+		if !strings.EqualFold(u.Host, a.Host) {
+			continue
+		}
+		prefix := strings.TrimSuffix(path.Clean("/"+a.Path), "/")
+		if p == prefix || strings.HasPrefix(p, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// Open downloads the bits of a buildpack from the source and returns them
+// with their file name. Reading more than the maximum size of a source fails.
+func (s *Sources) Open(ctx context.Context, source string) (io.ReadCloser, string, error) {
+	u, err := url.Parse(source)
+	if err != nil || !s.allows(u) {
+		return nil, "", errors.Errorf(errSourceNotAllowed, source)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, errDownload, source)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, errDownload, source)
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, "", errors.Errorf(errDownload+": %s", source, resp.Status)
+	}
+	if resp.ContentLength > s.maxSize {
+		_ = resp.Body.Close()
+		return nil, "", errors.Errorf(errSourceTooLarge, source, s.maxSize)
+	}
+	return &limitedBody{ReadCloser: resp.Body, source: source, max: s.maxSize}, path.Base(u.Path), nil
+}
+
+// limitedBody fails a read once more than max bytes of the body were read.
+type limitedBody struct {
+	io.ReadCloser
+	source string
+	max    int64
+	read   int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+	if b.read > b.max {
+		return n, errors.Errorf(errSourceTooLarge, b.source, b.max)
+	}
+	return n, err
+}
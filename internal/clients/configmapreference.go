@@ -0,0 +1,31 @@
+package clients
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8s "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const errConfigMapKeyNotFound = "key %s not found in ConfigMap %s"
+
+// ExtractConfigMap extracts parameters from a ConfigMap.
+// If a key is specified, returns the raw value for that key.
+// If no key is specified, returns all ConfigMap data as nested JSON.
+func ExtractConfigMap(ctx context.Context, kube k8s.Client, nn types.NamespacedName, key string) ([]byte, error) {
+	cm := &v1.ConfigMap{}
+	if err := kube.Get(ctx, nn, cm); err != nil {
+		return nil, err
+	}
+
+	if key != "" {
+		if v, ok := cm.Data[key]; ok {
+			return []byte(v), nil
+		}
+		return nil, errors.Errorf(errConfigMapKeyNotFound, key, nn)
+	}
+
+	return dataToJSON(cm.Data)
+}
@@ -0,0 +1,39 @@
+package clients
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const errGetConnectionSecret = "cannot get the connection secret"
+
+// PruneConnectionDetails returns details with a nil value for every key of
+// the connection secret of o that is no longer published. The reconciler
+// publishes connection details as a JSON merge patch of the secret, which
+// removes the keys whose value is null.
+func PruneConnectionDetails(ctx context.Context, kube client.Reader, o resource.LocalConnectionSecretOwner, details managed.ConnectionDetails) (managed.ConnectionDetails, error) {
+	ref := o.GetWriteConnectionSecretToReference()
+	if ref == nil {
+		return details, nil
+	}
+	s := &corev1.Secret{}
+	if err := kube.Get(ctx, types.NamespacedName{Namespace: o.GetNamespace(), Name: ref.Name}, s); err != nil {
+		return details, errors.Wrap(client.IgnoreNotFound(err), errGetConnectionSecret)
+	}
+	for k := range s.Data {
+		if _, ok := details[k]; ok {
+			continue
+		}
+		if details == nil {
+			details = managed.ConnectionDetails{}
+		}
+		details[k] = nil
+	}
+	return details, nil
+}
@@ -0,0 +1,86 @@
+package clients
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8s "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+)
+
+func TestPruneConnectionDetails(t *testing.T) {
+	errBoom := errors.New("boom")
+	withSecret := func(data map[string][]byte) test.MockGetFn {
+		return func(_ context.Context, key k8s.ObjectKey, obj k8s.Object) error {
+			if key.Namespace != "default" || key.Name != "conn" {
+				return errBoom
+			}
+			obj.(*corev1.Secret).Data = data
+			return nil
+		}
+	}
+	withRef := func(si *v1alpha1.ServiceInstance) *v1alpha1.ServiceInstance {
+		si.Spec.WriteConnectionSecretToReference = &xpv1.LocalSecretReference{Name: "conn"}
+		return si
+	}
+
+	cases := map[string]struct {
+		mg      *v1alpha1.ServiceInstance
+		get     test.MockGetFn
+		details managed.ConnectionDetails
+		want    managed.ConnectionDetails
+		err     error
+	}{
+		"NoConnectionSecret": {
+			mg:      &v1alpha1.ServiceInstance{},
+			details: managed.ConnectionDetails{"user": []byte("admin")},
+			want:    managed.ConnectionDetails{"user": []byte("admin")},
+		},
+		"NotPublishedYet": {
+			mg:      withRef(&v1alpha1.ServiceInstance{}),
+			get:     test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, "conn")),
+			details: managed.ConnectionDetails{"user": []byte("admin")},
+			want:    managed.ConnectionDetails{"user": []byte("admin")},
+		},
+		"StaleKeys": {
+			mg:      withRef(&v1alpha1.ServiceInstance{}),
+			get:     withSecret(map[string][]byte{"user": []byte("root"), "password": []byte("secret")}),
+			details: managed.ConnectionDetails{"user": []byte("admin")},
+			want:    managed.ConnectionDetails{"user": []byte("admin"), "password": nil},
+		},
+		"NothingPublished": {
+			mg:   withRef(&v1alpha1.ServiceInstance{}),
+			get:  withSecret(map[string][]byte{"user": []byte("root")}),
+			want: managed.ConnectionDetails{"user": nil},
+		},
+		"GetFailed": {
+			mg:      withRef(&v1alpha1.ServiceInstance{}),
+			get:     test.NewMockGetFn(errBoom),
+			details: managed.ConnectionDetails{"user": []byte("admin")},
+			want:    managed.ConnectionDetails{"user": []byte("admin")},
+			err:     errors.Wrap(errBoom, errGetConnectionSecret),
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			tc.mg.SetNamespace("default")
+			got, err := PruneConnectionDetails(context.Background(), &test.MockClient{MockGet: tc.get}, tc.mg, tc.details)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("PruneConnectionDetails(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("PruneConnectionDetails(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
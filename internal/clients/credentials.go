@@ -1,4 +1,4 @@
-package servicecredentialbinding
+package clients
 
 import (
 	"encoding/json"
@@ -23,8 +23,9 @@ func jsonNumToValue(in json.Number) interface{} {
 	return in.String()
 }
 
-// Converts a map with interface{} values to a map with string values by converting each value to a string. Some commit comment.
-func normalizeMap(in interface{}, outMap map[string]string, key, delim string) map[string]string { //nolint:gocyclo
+// NormalizeMap flattens a map with interface{} values to a map with string values by converting each value to a string.
+// Keys of nested maps and indices of slices are joined with delim.
+func NormalizeMap(in interface{}, outMap map[string]string, key, delim string) map[string]string { //nolint:gocyclo
 	if in == nil {
 		outMap[key] = ""
 		return outMap
@@ -74,63 +75,63 @@ func normalizeMap(in interface{}, outMap map[string]string, key, delim string) m
 		switch rt.Elem().Kind() { //nolint:exhaustive
 		case reflect.String:
 			for i, v := range in.([]string) {
-				normalizeMap(v, outMap, fmt.Sprintf("%s%s%d", key, delim, i), delim)
+				NormalizeMap(v, outMap, fmt.Sprintf("%s%s%d", key, delim, i), delim)
 			}
 		case reflect.Bool:
 			for i, v := range in.([]bool) {
-				normalizeMap(v, outMap, fmt.Sprintf("%s%s%d", key, delim, i), delim)
+				NormalizeMap(v, outMap, fmt.Sprintf("%s%s%d", key, delim, i), delim)
 			}
 		case reflect.Int:
 			for i, v := range in.([]int) {
-				normalizeMap(v, outMap, fmt.Sprintf("%s%s%d", key, delim, i), delim)
+				NormalizeMap(v, outMap, fmt.Sprintf("%s%s%d", key, delim, i), delim)
 			}
 		case reflect.Int8:
 			for i, v := range in.([]int8) {
-				normalizeMap(v, outMap, fmt.Sprintf("%s%s%d", key, delim, i), delim)
+				NormalizeMap(v, outMap, fmt.Sprintf("%s%s%d", key, delim, i), delim)
 			}
 		case reflect.Int16:
 			for i, v := range in.([]string) {
-				normalizeMap(v, outMap, fmt.Sprintf("%s%s%d", key, delim, i), delim)
+				NormalizeMap(v, outMap, fmt.Sprintf("%s%s%d", key, delim, i), delim)
 			}
 		case reflect.Int32:
 			for i, v := range in.([]int32) {
-				normalizeMap(v, outMap, fmt.Sprintf("%s%s%d", key, delim, i), delim)
+				NormalizeMap(v, outMap, fmt.Sprintf("%s%s%d", key, delim, i), delim)
 			}
 		case reflect.Int64:
 			for i, v := range in.([]int64) {
-				normalizeMap(v, outMap, fmt.Sprintf("%s%s%d", key, delim, i), delim)
+				NormalizeMap(v, outMap, fmt.Sprintf("%s%s%d", key, delim, i), delim)
 			}
 		case reflect.Uint:
 			for i, v := range in.([]uint) {
-				normalizeMap(v, outMap, fmt.Sprintf("%s%s%d", key, delim, i), delim)
+				NormalizeMap(v, outMap, fmt.Sprintf("%s%s%d", key, delim, i), delim)
 			}
 		case reflect.Uint8:
 			for i, v := range in.([]uint8) {
-				normalizeMap(v, outMap, fmt.Sprintf("%s%s%d", key, delim, i), delim)
+				NormalizeMap(v, outMap, fmt.Sprintf("%s%s%d", key, delim, i), delim)
 			}
 		case reflect.Uint16:
 			for i, v := range in.([]uint16) {
-				normalizeMap(v, outMap, fmt.Sprintf("%s%s%d", key, delim, i), delim)
+				NormalizeMap(v, outMap, fmt.Sprintf("%s%s%d", key, delim, i), delim)
 			}
 		case reflect.Uint32:
 			for i, v := range in.([]uint32) {
-				normalizeMap(v, outMap, fmt.Sprintf("%s%s%d", key, delim, i), delim)
+				NormalizeMap(v, outMap, fmt.Sprintf("%s%s%d", key, delim, i), delim)
 			}
 		case reflect.Uint64:
 			for i, v := range in.([]uint64) {
-				normalizeMap(v, outMap, fmt.Sprintf("%s%s%d", key, delim, i), delim)
+				NormalizeMap(v, outMap, fmt.Sprintf("%s%s%d", key, delim, i), delim)
 			}
 		case reflect.Float32:
 			for i, v := range in.([]float32) {
-				normalizeMap(v, outMap, fmt.Sprintf("%s%s%d", key, delim, i), delim)
+				NormalizeMap(v, outMap, fmt.Sprintf("%s%s%d", key, delim, i), delim)
 			}
 		case reflect.Float64:
 			for i, v := range in.([]float64) {
-				normalizeMap(v, outMap, fmt.Sprintf("%s%s%d", key, delim, i), delim)
+				NormalizeMap(v, outMap, fmt.Sprintf("%s%s%d", key, delim, i), delim)
 			}
 		default:
 			for i, v := range in.([]interface{}) {
-				normalizeMap(v, outMap, fmt.Sprintf("%s%s%d", key, delim, i), delim)
+				NormalizeMap(v, outMap, fmt.Sprintf("%s%s%d", key, delim, i), delim)
 			}
 		}
 
@@ -143,63 +144,63 @@ func normalizeMap(in interface{}, outMap map[string]string, key, delim string) m
 		switch rt.Elem().Kind() { //nolint:exhaustive
 		case reflect.String:
 			for k, v := range in.(map[string]string) {
-				normalizeMap(v, outMap, key+k, delim)
+				NormalizeMap(v, outMap, key+k, delim)
 			}
 		case reflect.Bool:
 			for k, v := range in.(map[string]bool) {
-				normalizeMap(v, outMap, key+k, delim)
+				NormalizeMap(v, outMap, key+k, delim)
 			}
 		case reflect.Int:
 			for k, v := range in.(map[string]int) {
-				normalizeMap(v, outMap, key+k, delim)
+				NormalizeMap(v, outMap, key+k, delim)
 			}
 		case reflect.Int8:
 			for k, v := range in.(map[string]int8) {
-				normalizeMap(v, outMap, key+k, delim)
+				NormalizeMap(v, outMap, key+k, delim)
 			}
 		case reflect.Int16:
 			for k, v := range in.(map[string]string) {
-				normalizeMap(v, outMap, key+k, delim)
+				NormalizeMap(v, outMap, key+k, delim)
 			}
 		case reflect.Int32:
 			for k, v := range in.(map[string]int32) {
-				normalizeMap(v, outMap, key+k, delim)
+				NormalizeMap(v, outMap, key+k, delim)
 			}
 		case reflect.Int64:
 			for k, v := range in.(map[string]int64) {
-				normalizeMap(v, outMap, key+k, delim)
+				NormalizeMap(v, outMap, key+k, delim)
 			}
 		case reflect.Uint:
 			for k, v := range in.(map[string]uint) {
-				normalizeMap(v, outMap, key+k, delim)
+				NormalizeMap(v, outMap, key+k, delim)
 			}
 		case reflect.Uint8:
 			for k, v := range in.(map[string]uint8) {
-				normalizeMap(v, outMap, key+k, delim)
+				NormalizeMap(v, outMap, key+k, delim)
 			}
 		case reflect.Uint16:
 			for k, v := range in.(map[string]uint16) {
-				normalizeMap(v, outMap, key+k, delim)
+				NormalizeMap(v, outMap, key+k, delim)
 			}
 		case reflect.Uint32:
 			for k, v := range in.(map[string]uint32) {
-				normalizeMap(v, outMap, key+k, delim)
+				NormalizeMap(v, outMap, key+k, delim)
 			}
 		case reflect.Uint64:
 			for k, v := range in.(map[string]uint64) {
-				normalizeMap(v, outMap, key+k, delim)
+				NormalizeMap(v, outMap, key+k, delim)
 			}
 		case reflect.Float32:
 			for k, v := range in.(map[string]float32) {
-				normalizeMap(v, outMap, key+k, delim)
+				NormalizeMap(v, outMap, key+k, delim)
 			}
 		case reflect.Float64:
 			for k, v := range in.(map[string]float64) {
-				normalizeMap(v, outMap, key+k, delim)
+				NormalizeMap(v, outMap, key+k, delim)
 			}
 		default:
 			for k, v := range in.(map[string]interface{}) {
-				normalizeMap(v, outMap, key+k, delim)
+				NormalizeMap(v, outMap, key+k, delim)
 			}
 		}
 	}
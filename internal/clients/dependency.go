@@ -0,0 +1,79 @@
+package clients
+
+import (
+	"context"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/fieldpath"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	k8s "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+)
+
+const (
+	errGetDependency      = "cannot get dependency %s %q"
+	errDependencyNotReady = "dependency %s %q is not ready"
+	errDependencyGroup    = "dependency %s %q is not a " + v1alpha1.CRDGroup + " resource"
+)
+
+// ReasonWaitingForDependencies is the reason of the Ready condition of a
+// resource that is not created because its dependencies are not ready.
+const ReasonWaitingForDependencies xpv1.ConditionReason = "WaitingForDependencies"
+
+// CheckDependencies returns an error if a dependency of a managed resource
+// does not exist or is not Ready. Dependencies are looked up in the namespace
+// of the resource and must be resources of this provider. kube should be an
+// uncached reader, so that no informer is started for the kinds of the
+// dependencies.
+func CheckDependencies(ctx context.Context, kube k8s.Reader, mg metav1.Object, deps []v1alpha1.Dependency) error {
+	for _, d := range deps {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion(d.APIVersion)
+		if d.APIVersion == "" {
+			u.SetAPIVersion(v1alpha1.CRDGroupVersion.String())
+		}
+		if u.GroupVersionKind().Group != v1alpha1.CRDGroup {
+			return errors.Errorf(errDependencyGroup, d.Kind, d.Name)
+		}
+		u.SetKind(d.Kind)
+
+		if err := kube.Get(ctx, types.NamespacedName{Namespace: mg.GetNamespace(), Name: d.Name}, u); err != nil {
+			return errors.Wrapf(err, errGetDependency, d.Kind, d.Name)
+		}
+		if !isReady(u) {
+			return errors.Errorf(errDependencyNotReady, d.Kind, d.Name)
+		}
+	}
+	return nil
+}
+
+// WaitingForDependencies returns a condition that indicates the resource has
+// not been created because a dependency is not ready.
+func WaitingForDependencies(msg string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               xpv1.TypeReady,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonWaitingForDependencies,
+		Message:            msg,
+	}
+}
+
+// isReady returns true if the Ready condition of an object is True.
+func isReady(u *unstructured.Unstructured) bool {
+	conditions := []xpv1.Condition{}
+	if err := fieldpath.Pave(u.Object).GetValueInto("status.conditions", &conditions); err != nil {
+		return false
+	}
+	for _, c := range conditions {
+		if c.Type == xpv1.TypeReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package clients
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8s "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+)
+
+// withReady returns a MockGetFn that sets the Ready condition of the dependency to status.
+func withReady(status string) test.MockGetFn {
+	return func(_ context.Context, key k8s.ObjectKey, obj k8s.Object) error {
+		u := obj.(*unstructured.Unstructured)
+		if u.GetAPIVersion() != v1alpha1.CRDGroupVersion.String() || key.Namespace != "default" {
+			return errors.Errorf("unexpected dependency %s %s", u.GetAPIVersion(), key)
+		}
+		u.Object["status"] = map[string]any{
+			"conditions": []any{
+				map[string]any{"type": "Synced", "status": "True"},
+				map[string]any{"type": "Ready", "status": status},
+			},
+		}
+		return nil
+	}
+}
+
+func TestCheckDependencies(t *testing.T) {
+	mg := &metav1.ObjectMeta{Name: "my-app", Namespace: "default"}
+	si := []v1alpha1.Dependency{{Kind: "ServiceInstance", Name: "my-si"}}
+	errNotFound := kerrors.NewNotFound(schema.GroupResource{Group: v1alpha1.CRDGroup, Resource: "serviceinstances"}, "my-si")
+
+	cases := map[string]struct {
+		deps []v1alpha1.Dependency
+		get  test.MockGetFn
+		want error
+	}{
+		"NoDependencies": {
+			deps: nil,
+			get:  test.NewMockGetFn(errors.New("no dependency must be fetched")),
+			want: nil,
+		},
+		"Ready": {
+			deps: si,
+			get:  withReady("True"),
+			want: nil,
+		},
+		"NotReady": {
+			deps: si,
+			get:  withReady("False"),
+			want: errors.Errorf(errDependencyNotReady, "ServiceInstance", "my-si"),
+		},
+		"WithoutConditions": {
+			deps: si,
+			get:  test.NewMockGetFn(nil),
+			want: errors.Errorf(errDependencyNotReady, "ServiceInstance", "my-si"),
+		},
+		"OtherGroup": {
+			deps: []v1alpha1.Dependency{{APIVersion: "v1", Kind: "Secret", Name: "my-secret"}},
+			get:  test.NewMockGetFn(errors.New("no dependency must be fetched")),
+			want: errors.Errorf(errDependencyGroup, "Secret", "my-secret"),
+		},
+		"Missing": {
+			deps: si,
+			get:  test.NewMockGetFn(errNotFound),
+			want: errors.Wrapf(errNotFound, errGetDependency, "ServiceInstance", "my-si"),
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			err := CheckDependencies(context.Background(), &test.MockClient{MockGet: tc.get}, mg, tc.deps)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("CheckDependencies(...): -want error, +got error:\n%s", diff)
+			}
+		})
+	}
+}
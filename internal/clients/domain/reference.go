@@ -26,7 +26,7 @@ func ResolveByName(ctx context.Context, clientFn clients.ClientFn, mg resource.M
 	dr := cr.GetDomainRef()
 	if dr == nil || dr.DomainName == nil {
 		if dr.Domain != nil { // domain GUID is directly set, so we do not need to use names.
-			return nil
+			return clients.ValidateGUID("domain", dr.Domain)
 		}
 		return errors.New("Unknown domain. Please specify `domainRef` or `domainSelector` or using `domainName`. ")
 	}
@@ -0,0 +1,72 @@
+package clients
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AnnotationKeyDryRun is the annotation that makes Create record the request it
+// would send to Cloud Foundry instead of sending it.
+const AnnotationKeyDryRun = "cloudfoundry.crossplane.io/dry-run"
+
+// AnnotationKeyDryRunUpdate is the annotation that makes Update record the
+// change it would apply to Cloud Foundry instead of applying it. It does not
+// affect Create.
+const AnnotationKeyDryRunUpdate = "cloudfoundry.crossplane.io/dry-run-update"
+
+// TypePendingUpdate is the type of the condition that records the change a
+// resource in update dry-run mode would apply.
+const TypePendingUpdate xpv1.ConditionType = "PendingUpdate"
+
+// ReasonNoPendingUpdate is the reason of the PendingUpdate condition of a
+// resource that is up to date.
+const ReasonNoPendingUpdate xpv1.ConditionReason = "NoPendingUpdate"
+
+// ReasonDryRun is the reason of the Ready condition of a resource in dry-run mode.
+const ReasonDryRun xpv1.ConditionReason = "DryRun"
+
+// IsDryRun returns true if the dry-run annotation of the object is set to "true".
+func IsDryRun(o metav1.Object) bool {
+	return o.GetAnnotations()[AnnotationKeyDryRun] == "true"
+}
+
+// IsDryRunUpdate returns true if the update dry-run annotation of the object is set to "true".
+func IsDryRunUpdate(o metav1.Object) bool {
+	return o.GetAnnotations()[AnnotationKeyDryRunUpdate] == "true"
+}
+
+// DryRun returns a condition that indicates the resource has not been created
+// because it is in dry-run mode.
+func DryRun(msg string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               xpv1.TypeReady,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDryRun,
+		Message:            msg,
+	}
+}
+
+// PendingUpdate returns a condition that records the change a resource in
+// update dry-run mode would apply.
+func PendingUpdate(msg string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypePendingUpdate,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDryRun,
+		Message:            msg,
+	}
+}
+
+// NoPendingUpdate returns a condition that indicates a resource has no pending
+// update.
+func NoPendingUpdate() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypePendingUpdate,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNoPendingUpdate,
+	}
+}
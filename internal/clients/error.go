@@ -1,23 +1,34 @@
 package clients
 
 import (
+	"errors"
 	"strings"
 
 	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
 )
 
-// ErrorIsNotFound return true if error is not nil and is a not found issue.
-func ErrorIsNotFound(err error) bool {
+// IsNotFound returns true if err reports that a Cloud Foundry resource
+// does not exist: a list returned no or more than one result, the API
+// responded with a not found error, or the error message of one of these
+// was preserved without its type.
+func IsNotFound(err error) bool {
 	if err == nil {
 		return false
 	}
 
-	if err.Error() == client.ErrNoResultsReturned.Error() || // first()
-		err.Error() == client.ErrExactlyOneResultNotReturned.Error() { // single()
+	if errors.Is(err, client.ErrNoResultsReturned) || // first()
+		errors.Is(err, client.ErrExactlyOneResultNotReturned) || // single()
+		resource.IsResourceNotFoundError(err) ||
+		resource.IsServiceBindingNotFoundError(err) {
 		return true
 	}
 
-	return strings.Contains(err.Error(), "NotFound")
+	msg := err.Error()
+	return msg == client.ErrNoResultsReturned.Error() ||
+		msg == client.ErrExactlyOneResultNotReturned.Error() ||
+		strings.Contains(msg, "NotFound") ||
+		strings.Contains(strings.ToLower(msg), "service route binding not found")
 }
 
 // ErrorIsRoleAlreadyExists returns true if the CF API reports a role already exists.
@@ -30,18 +41,8 @@ func ErrorIsRoleAlreadyExists(err error) bool {
 
 // IgnoreNotFoundErr returns nil if the error a not found issue.
 func IgnoreNotFoundErr(err error) error {
-	if err == nil {
-		return nil
-	}
-	switch err.Error() {
-	case client.ErrNoResultsReturned.Error():
-		return nil
-	case client.ErrExactlyOneResultNotReturned.Error():
+	if IsNotFound(err) {
 		return nil
-	default:
-		if strings.Contains(err.Error(), "NotFound") {
-			return nil
-		}
-		return err
 	}
+	return err
 }
@@ -0,0 +1,71 @@
+package clients
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestIsNotFound(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		want bool
+	}{
+		"Nil": {
+			err:  nil,
+			want: false,
+		},
+		"ErrNoResultsReturned": {
+			err:  client.ErrNoResultsReturned,
+			want: true,
+		},
+		"ErrExactlyOneResultNotReturned": {
+			err:  client.ErrExactlyOneResultNotReturned,
+			want: true,
+		},
+		"WrappedErrNoResultsReturned": {
+			err:  fmt.Errorf("cannot get space: %w", client.ErrNoResultsReturned),
+			want: true,
+		},
+		"ResourceNotFound": {
+			err:  resource.NewResourceNotFoundError(),
+			want: true,
+		},
+		"ServiceBindingNotFound": {
+			err:  resource.NewServiceBindingNotFoundError(),
+			want: true,
+		},
+		"WrappedResourceNotFound": {
+			err:  fmt.Errorf("cannot delete space: %w", resource.NewResourceNotFoundError()),
+			want: true,
+		},
+		"CF-ResourceNotFound": {
+			err:  errors.New("CF-ResourceNotFound: The resource could not be found"),
+			want: true,
+		},
+		"ServiceRouteBindingNotFound": {
+			err:  errors.New("service route binding not found"),
+			want: true,
+		},
+		"OtherCFError": {
+			err:  resource.NewUnprocessableEntityError(),
+			want: false,
+		},
+		"OtherError": {
+			err:  errors.New("boom"),
+			want: false,
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, IsNotFound(tc.err)); diff != "" {
+				t.Errorf("IsNotFound(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	xpresource "github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"golang.org/x/oauth2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ErrorClass is the class of an error returned by the CF API. It is used as
+// the reason of the Ready condition of a managed resource whose operation
+// failed with the error.
+type ErrorClass xpv1.ConditionReason
+
+// Error classes. An error that fits none of them has no class.
+const (
+	ErrorNotFound     ErrorClass = "NotFound"
+	ErrorUnauthorized ErrorClass = "Unauthorized"
+	ErrorRateLimited  ErrorClass = "RateLimited"
+	ErrorBroker       ErrorClass = "BrokerError"
+	ErrorValidation   ErrorClass = "Validation"
+	ErrorTransient    ErrorClass = "Transient"
+)
+
+// CF API error codes that are classified by code rather than by title.
+const (
+	cfInvalidAuthToken             = 1000
+	cfBadRequest                   = 1004
+	cfNotAuthenticated             = 10002
+	cfNotAuthorized                = 10003
+	cfInvalidRequest               = 10004
+	cfBadQueryParameter            = 10005
+	cfUnprocessableEntity          = 10008
+	cfRateLimitExceeded            = 10013
+	cfIPBasedRateLimitExceeded     = 10014
+	cfServiceBrokerRateLimitExceed = 10016
+)
+
+// ClassifyError returns the class of err, or an empty class if err is nil or
+// fits none of the classes.
+func ClassifyError(err error) ErrorClass {
+	if err == nil {
+		return ""
+	}
+	if IsNotFound(err) {
+		return ErrorNotFound
+	}
+
+	var cfErr resource.CloudFoundryError
+	if errors.As(err, &cfErr) {
+		if c := classifyCFError(cfErr); c != "" {
+			return c
+		}
+	}
+
+	var httpErr resource.CloudFoundryHTTPError
+	if errors.As(err, &httpErr) {
+		if c := classifyStatus(httpErr.StatusCode); c != "" {
+			return c
+		}
+	}
+
+	// go-cfclient fetches a new token once if the CF API rejects the current
+	// one, e.g. because it expired mid-reconcile. If UAA rejects the
+	// credentials then, the error of UAA is returned.
+	var tokenErr *oauth2.RetrieveError
+	if errors.As(err, &tokenErr) && tokenErr.Response != nil &&
+		(tokenErr.Response.StatusCode == http.StatusUnauthorized || tokenErr.Response.StatusCode == http.StatusBadRequest) {
+		return ErrorUnauthorized
+	}
+
+	// Errors of UAA that are not wrapped carry neither a CF error code nor a
+	// status we can inspect.
+	msg := err.Error()
+	if strings.Contains(msg, "invalid_grant") || strings.Contains(msg, "invalid_client") ||
+		strings.Contains(msg, `oauth2: "unauthorized"`) || strings.Contains(msg, "401 Unauthorized") || strings.Contains(msg, "403 Forbidden") {
+		return ErrorUnauthorized
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, client.AsyncProcessTimeoutError) ||
+		errors.Is(err, io.ErrUnexpectedEOF) || errors.As(err, &netErr) {
+		return ErrorTransient
+	}
+	return ""
+}
+
+func classifyCFError(e resource.CloudFoundryError) ErrorClass {
+	switch e.Code {
+	case cfInvalidAuthToken, cfNotAuthenticated, cfNotAuthorized:
+		return ErrorUnauthorized
+	case cfRateLimitExceeded, cfIPBasedRateLimitExceeded, cfServiceBrokerRateLimitExceed:
+		return ErrorRateLimited
+	case cfBadRequest, cfInvalidRequest, cfBadQueryParameter, cfUnprocessableEntity:
+		return ErrorValidation
+	}
+	switch {
+	case strings.HasPrefix(e.Title, "CF-ServiceBroker"):
+		return ErrorBroker
+	case strings.HasSuffix(e.Title, "NotAuthorized"):
+		return ErrorUnauthorized
+	case strings.HasSuffix(e.Title, "Unavailable"):
+		return ErrorTransient
+	case strings.HasSuffix(e.Title, "Invalid"):
+		return ErrorValidation
+	}
+	return ""
+}
+
+func classifyStatus(code int) ErrorClass {
+	switch {
+	case code == http.StatusUnauthorized || code == http.StatusForbidden:
+		return ErrorUnauthorized
+	case code == http.StatusTooManyRequests:
+		return ErrorRateLimited
+	case code == http.StatusBadRequest || code == http.StatusUnprocessableEntity:
+		return ErrorValidation
+	case code >= http.StatusInternalServerError:
+		return ErrorTransient
+	}
+	return ""
+}
+
+// OperationFailed returns a condition that indicates the external resource is
+// not available because an operation on it failed with an error of the given class.
+func OperationFailed(class ErrorClass, err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               xpv1.TypeReady,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             xpv1.ConditionReason(class),
+		Message:            err.Error(),
+	}
+}
+
+// WithErrorClassification wraps an ExternalConnecter so that the errors of the
+// ExternalClients it produces are classified once. A classified error is
+// prefixed with its class, which the managed reconciler keeps in the message
+// of the Synced condition. Unless the error is only transient, the Ready
+// condition of the managed resource reports the class as its reason.
+func WithErrorClassification(c managed.ExternalConnecter) managed.ExternalConnecter {
+	return managed.ExternalConnectorFn(func(ctx context.Context, mg xpresource.Managed) (managed.ExternalClient, error) {
+		ec, err := c.Connect(ctx, mg)
+		if err != nil {
+			return nil, classify(mg, err)
+		}
+		return &classifyingClient{ExternalClient: ec}, nil
+	})
+}
+
+// classify prefixes err with its class and sets the Ready condition of mg.
+func classify(mg xpresource.Managed, err error) error {
+	class := ClassifyError(err)
+	if class == "" {
+		return err
+	}
+	if class != ErrorTransient && class != ErrorRateLimited {
+		mg.SetConditions(OperationFailed(class, err))
+	}
+	return fmt.Errorf("%s: %w", class, err)
+}
+
+// classifyingClient classifies the errors of every operation of an ExternalClient.
+type classifyingClient struct {
+	managed.ExternalClient
+}
+
+func (c *classifyingClient) Observe(ctx context.Context, mg xpresource.Managed) (managed.ExternalObservation, error) {
+	o, err := c.ExternalClient.Observe(ctx, mg)
+	if err != nil {
+		return o, classify(mg, err)
+	}
+	return o, nil
+}
+
+func (c *classifyingClient) Create(ctx context.Context, mg xpresource.Managed) (managed.ExternalCreation, error) {
+	o, err := c.ExternalClient.Create(ctx, mg)
+	if err != nil {
+		return o, classify(mg, err)
+	}
+	return o, nil
+}
+
+func (c *classifyingClient) Update(ctx context.Context, mg xpresource.Managed) (managed.ExternalUpdate, error) {
+	o, err := c.ExternalClient.Update(ctx, mg)
+	if err != nil {
+		return o, classify(mg, err)
+	}
+	return o, nil
+}
+
+func (c *classifyingClient) Delete(ctx context.Context, mg xpresource.Managed) (managed.ExternalDelete, error) {
+	o, err := c.ExternalClient.Delete(ctx, mg)
+	if err != nil {
+		return o, classify(mg, err)
+	}
+	return o, nil
+}
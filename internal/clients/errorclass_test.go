@@ -0,0 +1,198 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	xpresource "github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"golang.org/x/oauth2"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+)
+
+func TestClassifyError(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		want ErrorClass
+	}{
+		"Nil": {
+			err: nil,
+		},
+		"Unknown": {
+			err: errors.New("boom"),
+		},
+		"NotFound": {
+			err:  fmt.Errorf("cannot get space: %w", resource.NewResourceNotFoundError()),
+			want: ErrorNotFound,
+		},
+		"NoResults": {
+			err:  client.ErrNoResultsReturned,
+			want: ErrorNotFound,
+		},
+		"InvalidAuthToken": {
+			err:  resource.NewInvalidAuthTokenError(),
+			want: ErrorUnauthorized,
+		},
+		"NotAuthenticated": {
+			err:  resource.NewNotAuthenticatedError(),
+			want: ErrorUnauthorized,
+		},
+		"NotAuthorized": {
+			err:  fmt.Errorf("cannot create org: %w", resource.NewNotAuthorizedError()),
+			want: ErrorUnauthorized,
+		},
+		"HTTP401": {
+			err:  resource.CloudFoundryHTTPError{StatusCode: 401, Status: "401 Unauthorized"},
+			want: ErrorUnauthorized,
+		},
+		"HTTP403": {
+			err:  resource.CloudFoundryHTTPError{StatusCode: 403, Status: "403 Forbidden"},
+			want: ErrorUnauthorized,
+		},
+		"TokenRejected": {
+			err:  errors.New(`oauth2: "invalid_grant" "Bad credentials"`),
+			want: ErrorUnauthorized,
+		},
+		"TokenUnauthorized": {
+			err:  errors.New(`oauth2: "unauthorized" "Bad credentials"`),
+			want: ErrorUnauthorized,
+		},
+		"TokenRefreshRejected": {
+			err:  fmt.Errorf("error re-authenticating with the OAuth2 token source: %w", &oauth2.RetrieveError{Response: &http.Response{StatusCode: http.StatusUnauthorized}}),
+			want: ErrorUnauthorized,
+		},
+		"RateLimitExceeded": {
+			err:  resource.NewRateLimitExceededError(),
+			want: ErrorRateLimited,
+		},
+		"ServiceBrokerRateLimitExceeded": {
+			err:  resource.NewServiceBrokerRateLimitExceededError(),
+			want: ErrorRateLimited,
+		},
+		"HTTP429": {
+			err:  resource.CloudFoundryHTTPError{StatusCode: 429, Status: "429 Too Many Requests"},
+			want: ErrorRateLimited,
+		},
+		"ServiceBrokerBadResponse": {
+			err:  resource.CloudFoundryError{Code: 10001, Title: "CF-ServiceBrokerBadResponse", Detail: "The service broker returned an invalid response: status code: 500"},
+			want: ErrorBroker,
+		},
+		"ServiceBrokerRequestRejected": {
+			err:  resource.NewServiceBrokerRequestRejectedError(),
+			want: ErrorBroker,
+		},
+		"FailedJob": {
+			err:  fmt.Errorf("job failed: %w", errors.Join(resource.NewServiceBrokerRequestRejectedError())),
+			want: ErrorBroker,
+		},
+		"UnprocessableEntity": {
+			err:  resource.NewUnprocessableEntityError(),
+			want: ErrorValidation,
+		},
+		"AppInvalid": {
+			err:  resource.NewAppInvalidError(),
+			want: ErrorValidation,
+		},
+		"HTTP422": {
+			err:  resource.CloudFoundryHTTPError{StatusCode: 422, Status: "422 Unprocessable Entity"},
+			want: ErrorValidation,
+		},
+		"ServiceUnavailable": {
+			err:  resource.NewServiceUnavailableError(),
+			want: ErrorTransient,
+		},
+		"HTTP503": {
+			err:  resource.CloudFoundryHTTPError{StatusCode: 503, Status: "503 Service Unavailable"},
+			want: ErrorTransient,
+		},
+		"DeadlineExceeded": {
+			err:  fmt.Errorf("cannot observe: %w", context.DeadlineExceeded),
+			want: ErrorTransient,
+		},
+		"JobTimeout": {
+			err:  client.AsyncProcessTimeoutError,
+			want: ErrorTransient,
+		},
+		"ConnectionRefused": {
+			err:  &url.Error{Op: "Get", URL: "https://api.example.com", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}},
+			want: ErrorTransient,
+		},
+		"UnexpectedEOF": {
+			err:  io.ErrUnexpectedEOF,
+			want: ErrorTransient,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, ClassifyError(tc.err)); diff != "" {
+				t.Errorf("ClassifyError(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWithErrorClassification(t *testing.T) {
+	cases := map[string]struct {
+		err       error
+		wantErr   error
+		wantReady *xpv1.Condition
+	}{
+		"Unclassified": {
+			err:     errors.New("boom"),
+			wantErr: errors.New("boom"),
+		},
+		"Unauthorized": {
+			err:       resource.NewNotAuthorizedError(),
+			wantErr:   fmt.Errorf("Unauthorized: %w", resource.NewNotAuthorizedError()),
+			wantReady: &xpv1.Condition{Type: xpv1.TypeReady, Status: "False", Reason: "Unauthorized", Message: resource.NewNotAuthorizedError().Error()},
+		},
+		"TransientKeepsReady": {
+			err:     resource.NewServiceUnavailableError(),
+			wantErr: fmt.Errorf("Transient: %w", resource.NewServiceUnavailableError()),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ec := &managed.ExternalClientFns{
+				UpdateFn: func(context.Context, xpresource.Managed) (managed.ExternalUpdate, error) {
+					return managed.ExternalUpdate{}, tc.err
+				},
+			}
+			c := WithErrorClassification(managed.ExternalConnectorFn(func(context.Context, xpresource.Managed) (managed.ExternalClient, error) {
+				return ec, nil
+			}))
+
+			mg := &v1alpha1.Space{}
+			mg.SetConditions(xpv1.Available())
+			ext, err := c.Connect(context.Background(), mg)
+			if err != nil {
+				t.Fatalf("Connect(...): %v", err)
+			}
+			_, err = ext.Update(context.Background(), mg)
+			if diff := cmp.Diff(tc.wantErr, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Update(...): -want error, +got error:\n%s", diff)
+			}
+
+			want := xpv1.Available()
+			if tc.wantReady != nil {
+				want = *tc.wantReady
+			}
+			if diff := cmp.Diff(want, mg.GetCondition(xpv1.TypeReady), cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("Ready condition: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package clients
+
+import (
+	"sync"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// EventRateLimitInterval is the interval within which an identical event is
+// recorded at most once for the same object.
+const EventRateLimitInterval = 5 * time.Minute
+
+// RateLimitEvents returns an event.FilterFn that drops an event if an
+// identical event was recorded for the same object within the given interval,
+// so that polling an in-progress operation does not spam the event stream.
+func RateLimitEvents(interval time.Duration) event.FilterFn {
+	l := &eventLimiter{interval: interval, now: time.Now, seen: map[eventKey]time.Time{}}
+	return l.filter
+}
+
+type eventKey struct {
+	uid     string
+	reason  event.Reason
+	message string
+}
+
+type eventLimiter struct {
+	interval time.Duration
+	now      func() time.Time
+
+	mu   sync.Mutex
+	seen map[eventKey]time.Time
+}
+
+func (l *eventLimiter) filter(obj runtime.Object, e event.Event) bool {
+	o, err := meta.Accessor(obj)
+	if err != nil {
+		return false
+	}
+	k := eventKey{uid: string(o.GetUID()), reason: e.Reason, message: e.Message}
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for key, t := range l.seen {
+		if now.Sub(t) >= l.interval {
+			delete(l.seen, key)
+		}
+	}
+	if _, ok := l.seen[k]; ok {
+		return true
+	}
+	l.seen[k] = now
+	return false
+}
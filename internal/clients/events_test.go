@@ -0,0 +1,66 @@
+package clients
+
+import (
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+)
+
+func TestRateLimitEvents(t *testing.T) {
+	a := &v1alpha1.ServiceInstance{}
+	a.SetUID(types.UID("a"))
+	b := &v1alpha1.ServiceInstance{}
+	b.SetUID(types.UID("b"))
+
+	started := event.Normal("ProvisionStarted", "started")
+	failed := event.Warning("ProvisionFailed", errors.New("failed"))
+
+	type record struct {
+		obj     *v1alpha1.ServiceInstance
+		e       event.Event
+		elapsed time.Duration
+	}
+	cases := map[string]struct {
+		records []record
+		want    []bool
+	}{
+		"Duplicate": {
+			records: []record{{a, started, 0}, {a, started, time.Minute}},
+			want:    []bool{false, true},
+		},
+		"DifferentObject": {
+			records: []record{{a, started, 0}, {b, started, 0}},
+			want:    []bool{false, false},
+		},
+		"DifferentReason": {
+			records: []record{{a, started, 0}, {a, failed, 0}},
+			want:    []bool{false, false},
+		},
+		"IntervalElapsed": {
+			records: []record{{a, started, 0}, {a, started, 5 * time.Minute}},
+			want:    []bool{false, false},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			start := time.Now()
+			now := start
+			l := &eventLimiter{interval: 5 * time.Minute, now: func() time.Time { return now }, seen: map[eventKey]time.Time{}}
+			got := make([]bool, 0, len(tc.records))
+			for _, r := range tc.records {
+				now = start.Add(r.elapsed)
+				got = append(got, l.filter(r.obj, r.e))
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("filter(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -25,6 +25,24 @@ func (m *MockApp) Single(ctx context.Context, opt *client.AppListOptions) (*reso
 	return args.Get(0).(*resource.App), args.Error(1)
 }
 
+// ListAll mocks App.ListAll
+func (m *MockApp) ListAll(ctx context.Context, opts *client.AppListOptions) ([]*resource.App, error) {
+	args := m.Called()
+	return args.Get(0).([]*resource.App), args.Error(1)
+}
+
+// GetEnvironment mocks App.GetEnvironment
+func (m *MockApp) GetEnvironment(ctx context.Context, guid string) (*resource.AppEnvironment, error) {
+	args := m.Called(guid)
+	return args.Get(0).(*resource.AppEnvironment), args.Error(1)
+}
+
+// SetEnvironmentVariables mocks App.SetEnvironmentVariables
+func (m *MockApp) SetEnvironmentVariables(ctx context.Context, guid string, envRequest map[string]*string) (map[string]*string, error) {
+	args := m.Called(guid, envRequest)
+	return envRequest, args.Error(0)
+}
+
 // CreateManaged mocks App.Create
 func (m *MockApp) Create(ctx context.Context, opt *resource.AppCreate) (*resource.App, error) {
 	args := m.Called()
@@ -89,3 +107,9 @@ func (a *App) SetGUID(guid string) *App {
 	a.GUID = guid
 	return a
 }
+
+// SetState assigns App state
+func (a *App) SetState(state string) *App {
+	a.State = state
+	return a
+}
@@ -0,0 +1,108 @@
+package fake
+
+import (
+	"context"
+	"io"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockBuildpack mocks Buildpack interfaces
+type MockBuildpack struct {
+	mock.Mock
+}
+
+// Get mocks Buildpack.Get
+func (m *MockBuildpack) Get(ctx context.Context, guid string) (*resource.Buildpack, error) {
+	args := m.Called(guid)
+	return args.Get(0).(*resource.Buildpack), args.Error(1)
+}
+
+// Single mocks Buildpack.Single
+func (m *MockBuildpack) Single(ctx context.Context, opts *client.BuildpackListOptions) (*resource.Buildpack, error) {
+	args := m.Called(opts.Names.Values, opts.Stacks.Values)
+	return args.Get(0).(*resource.Buildpack), args.Error(1)
+}
+
+// Create mocks Buildpack.Create
+func (m *MockBuildpack) Create(ctx context.Context, r *resource.BuildpackCreateOrUpdate) (*resource.Buildpack, error) {
+	args := m.Called(r)
+	return args.Get(0).(*resource.Buildpack), args.Error(1)
+}
+
+// Update mocks Buildpack.Update
+func (m *MockBuildpack) Update(ctx context.Context, guid string, r *resource.BuildpackCreateOrUpdate) (*resource.Buildpack, error) {
+	args := m.Called(guid, r)
+	return args.Get(0).(*resource.Buildpack), args.Error(1)
+}
+
+// Delete mocks Buildpack.Delete
+func (m *MockBuildpack) Delete(ctx context.Context, guid string) (string, error) {
+	args := m.Called(guid)
+	return args.String(0), args.Error(1)
+}
+
+// Upload mocks Buildpack.Upload
+func (m *MockBuildpack) Upload(ctx context.Context, guid string, fileName string, zipFile io.Reader) (string, *resource.Buildpack, error) {
+	args := m.Called(guid, fileName)
+	return args.String(0), args.Get(1).(*resource.Buildpack), args.Error(2)
+}
+
+// BuildpackNil is a nil Buildpack
+var (
+	BuildpackNil *resource.Buildpack
+)
+
+// Buildpack is a Buildpack object
+type Buildpack struct {
+	resource.Buildpack
+}
+
+// NewBuildpack generate a new Buildpack
+func NewBuildpack() *Buildpack {
+	return &Buildpack{}
+}
+
+// SetName assigns Buildpack name
+func (b *Buildpack) SetName(name string) *Buildpack {
+	b.Name = name
+	return b
+}
+
+// SetGUID assigns Buildpack GUID
+func (b *Buildpack) SetGUID(guid string) *Buildpack {
+	b.GUID = guid
+	return b
+}
+
+// SetState assigns Buildpack state
+func (b *Buildpack) SetState(state string) *Buildpack {
+	b.State = state
+	return b
+}
+
+// SetStack assigns Buildpack stack
+func (b *Buildpack) SetStack(stack string) *Buildpack {
+	b.Stack = &stack
+	return b
+}
+
+// SetPosition assigns Buildpack position
+func (b *Buildpack) SetPosition(position int) *Buildpack {
+	b.Position = position
+	return b
+}
+
+// SetEnabled assigns Buildpack enabled flag
+func (b *Buildpack) SetEnabled(enabled bool) *Buildpack {
+	b.Enabled = enabled
+	return b
+}
+
+// SetLocked assigns Buildpack locked flag
+func (b *Buildpack) SetLocked(locked bool) *Buildpack {
+	b.Locked = locked
+	return b
+}
@@ -0,0 +1,43 @@
+package fake
+
+import (
+	"context"
+
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockDeploy mocks DeployClient interfaces
+type MockDeploy struct {
+	mock.Mock
+}
+
+// Restart mocks DeployClient.Restart
+func (m *MockDeploy) Restart(ctx context.Context, appGUID string) error {
+	args := m.Called(appGUID)
+	return args.Error(0)
+}
+
+// Restage mocks DeployClient.Restage
+func (m *MockDeploy) Restage(ctx context.Context, appGUID string, started bool) error {
+	args := m.Called(appGUID, started)
+	return args.Error(0)
+}
+
+// Deploy mocks DeployClient.Deploy
+func (m *MockDeploy) Deploy(ctx context.Context, appGUID string, image string, credentials *resource.DockerCredentials) (*resource.Deployment, error) {
+	args := m.Called(appGUID, image)
+	return args.Get(0).(*resource.Deployment), args.Error(1)
+}
+
+// GetDeployment mocks DeployClient.GetDeployment
+func (m *MockDeploy) GetDeployment(ctx context.Context, guid string) (*resource.Deployment, error) {
+	args := m.Called(guid)
+	return args.Get(0).(*resource.Deployment), args.Error(1)
+}
+
+// CancelDeployment mocks DeployClient.CancelDeployment
+func (m *MockDeploy) CancelDeployment(ctx context.Context, guid string) error {
+	args := m.Called(guid)
+	return args.Error(0)
+}
@@ -1,9 +1,15 @@
 package fake
 
-import "github.com/cloudfoundry/go-cfclient/v3/client"
+import (
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+)
 
 // ErrNoResultReturned is error return by List()
 var ErrNoResultReturned = client.ErrNoResultsReturned
 
 // ErrExactlyOneResultNotReturned is error returned by Single()
 var ErrExactlyOneResultNotReturned = client.ErrExactlyOneResultNotReturned
+
+// ErrResourceNotFound is the error returned by the CF API for a missing resource
+var ErrResourceNotFound = resource.CloudFoundryError{Code: 10010, Title: "CF-ResourceNotFound", Detail: "Space not found"}
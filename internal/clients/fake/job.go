@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -17,3 +18,9 @@ func (m *MockJob) PollComplete(ctx context.Context, job string, opt *client.Poll
 	args := m.Called()
 	return args.Error(0)
 }
+
+// Get mocks Job.Get
+func (m *MockJob) Get(ctx context.Context, guid string) (*resource.Job, error) {
+	args := m.Called(guid)
+	return args.Get(0).(*resource.Job), args.Error(1)
+}
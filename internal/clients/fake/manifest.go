@@ -0,0 +1,31 @@
+package fake
+
+import (
+	"context"
+
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockManifest mocks Manifest interfaces
+type MockManifest struct {
+	mock.Mock
+}
+
+// Generate mocks Manifest.Generate
+func (m *MockManifest) Generate(ctx context.Context, appGUID string) (string, error) {
+	args := m.Called(appGUID)
+	return args.String(0), args.Error(1)
+}
+
+// ApplyManifest mocks Manifest.ApplyManifest
+func (m *MockManifest) ApplyManifest(ctx context.Context, spaceGUID string, manifest string) (string, error) {
+	args := m.Called(spaceGUID, manifest)
+	return args.String(0), args.Error(1)
+}
+
+// ManifestDiff mocks Manifest.ManifestDiff
+func (m *MockManifest) ManifestDiff(ctx context.Context, spaceGUID string, manifest string) (*resource.ManifestDiff, error) {
+	args := m.Called(spaceGUID, manifest)
+	return args.Get(0).(*resource.ManifestDiff), args.Error(1)
+}
@@ -31,6 +31,18 @@ func (m *MockOrganization) Create(ctx context.Context, opt *resource.Organizatio
 	return args.Get(0).(*resource.Organization), args.Error(1)
 }
 
+// Update mocks Organization.Update
+func (m *MockOrganization) Update(ctx context.Context, guid string, opt *resource.OrganizationUpdate) (*resource.Organization, error) {
+	args := m.Called(guid, opt)
+	return args.Get(0).(*resource.Organization), args.Error(1)
+}
+
+// Delete mocks Organization.Delete
+func (m *MockOrganization) Delete(ctx context.Context, guid string) (string, error) {
+	args := m.Called(guid)
+	return args.String(0), args.Error(1)
+}
+
 // Organization is a nil Organization
 var (
 	OrganizationNil *resource.Organization
@@ -58,3 +70,15 @@ func (s *Organization) SetGUID(guid string) *Organization {
 	s.GUID = guid
 	return s
 }
+
+// SetQuota assigns Organization quota relationship
+func (s *Organization) SetQuota(guid string) *Organization {
+	s.Relationships.Quota.Data = &resource.Relationship{GUID: guid}
+	return s
+}
+
+// SetSuspended assigns Organization suspended flag
+func (s *Organization) SetSuspended(suspended bool) *Organization {
+	s.Suspended = suspended
+	return s
+}
@@ -3,6 +3,7 @@ package fake
 import (
 	"context"
 
+	"github.com/cloudfoundry/go-cfclient/v3/client"
 	"github.com/cloudfoundry/go-cfclient/v3/resource"
 	"github.com/stretchr/testify/mock"
 )
@@ -17,6 +18,11 @@ func (m *MockOrgQuota) Get(ctx context.Context, guid string) (*resource.Organiza
 	return args.Get(0).(*resource.OrganizationQuota), args.Error(1)
 }
 
+func (m *MockOrgQuota) Single(ctx context.Context, opts *client.OrganizationQuotaListOptions) (*resource.OrganizationQuota, error) {
+	args := m.Called(opts.Names.Values)
+	return args.Get(0).(*resource.OrganizationQuota), args.Error(1)
+}
+
 func (m *MockOrgQuota) Create(ctx context.Context, opt *resource.OrganizationQuotaCreateOrUpdate) (*resource.OrganizationQuota, error) {
 	args := m.Called()
 	return args.Get(0).(*resource.OrganizationQuota), args.Error(1)
@@ -27,6 +33,11 @@ func (m *MockOrgQuota) Update(ctx context.Context, guid string, opt *resource.Or
 	return args.Get(0).(*resource.OrganizationQuota), args.Error(1)
 }
 
+func (m *MockOrgQuota) Apply(ctx context.Context, guid string, organizationGUIDs []string) ([]string, error) {
+	args := m.Called(guid, organizationGUIDs)
+	return organizationGUIDs, args.Error(0)
+}
+
 func (m *MockOrgQuota) Delete(ctx context.Context, guid string) (string, error) {
 	args := m.Called()
 	return args.String(0), args.Error(1)
@@ -0,0 +1,26 @@
+package fake
+
+import (
+	"context"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockProcess mocks ProcessClient interfaces
+type MockProcess struct {
+	mock.Mock
+}
+
+// SingleForApp mocks ProcessClient.SingleForApp
+func (m *MockProcess) SingleForApp(ctx context.Context, appGUID string, opts *client.ProcessListOptions) (*resource.Process, error) {
+	args := m.Called(appGUID, opts.Types.Values)
+	return args.Get(0).(*resource.Process), args.Error(1)
+}
+
+// Update mocks ProcessClient.Update
+func (m *MockProcess) Update(ctx context.Context, guid string, r *resource.ProcessUpdate) (*resource.Process, error) {
+	args := m.Called(guid, *r.Command)
+	return args.Get(0).(*resource.Process), args.Error(1)
+}
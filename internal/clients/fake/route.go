@@ -43,6 +43,36 @@ func (m *MockRoute) Delete(ctx context.Context, guid string) (string, error) {
 	return args.Get(0).(string), args.Error(1)
 }
 
+// ListForAppAll mocks Route.ListForAppAll
+func (m *MockRoute) ListForAppAll(ctx context.Context, appGUID string, opts *client.RouteListOptions) ([]*resource.Route, error) {
+	args := m.Called(appGUID)
+	return args.Get(0).([]*resource.Route), args.Error(1)
+}
+
+// RemoveDestination mocks Route.RemoveDestination
+func (m *MockRoute) RemoveDestination(ctx context.Context, guid, destinationGUID string) error {
+	args := m.Called(guid, destinationGUID)
+	return args.Error(0)
+}
+
+// InsertDestinations mocks Route.InsertDestinations
+func (m *MockRoute) InsertDestinations(ctx context.Context, guid string, dest []*resource.RouteDestinationInsertOrReplace) (*resource.RouteDestinations, error) {
+	args := m.Called(guid, dest)
+	return args.Get(0).(*resource.RouteDestinations), args.Error(1)
+}
+
+// ReplaceDestinations mocks Route.ReplaceDestinations
+func (m *MockRoute) ReplaceDestinations(ctx context.Context, guid string, dest []*resource.RouteDestinationInsertOrReplace) (*resource.RouteDestinations, error) {
+	args := m.Called(guid, dest)
+	return args.Get(0).(*resource.RouteDestinations), args.Error(1)
+}
+
+// UpdateDestinationProtocol mocks Route.UpdateDestinationProtocol
+func (m *MockRoute) UpdateDestinationProtocol(ctx context.Context, guid, destinationGUID, protocol string) (*resource.RouteDestinationWithLinks, error) {
+	args := m.Called(guid, destinationGUID, protocol)
+	return args.Get(0).(*resource.RouteDestinationWithLinks), args.Error(1)
+}
+
 // Route is a nil Route
 var (
 	RouteNil *resource.Route
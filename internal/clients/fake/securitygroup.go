@@ -0,0 +1,125 @@
+package fake
+
+import (
+	"context"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSecurityGroup mocks SecurityGroup interfaces
+type MockSecurityGroup struct {
+	mock.Mock
+}
+
+// Get mocks SecurityGroup.Get
+func (m *MockSecurityGroup) Get(ctx context.Context, guid string) (*resource.SecurityGroup, error) {
+	args := m.Called(guid)
+	return args.Get(0).(*resource.SecurityGroup), args.Error(1)
+}
+
+// Single mocks SecurityGroup.Single
+func (m *MockSecurityGroup) Single(ctx context.Context, opts *client.SecurityGroupListOptions) (*resource.SecurityGroup, error) {
+	args := m.Called(opts.Names.Values)
+	return args.Get(0).(*resource.SecurityGroup), args.Error(1)
+}
+
+// Create mocks SecurityGroup.Create
+func (m *MockSecurityGroup) Create(ctx context.Context, r *resource.SecurityGroupCreate) (*resource.SecurityGroup, error) {
+	args := m.Called(r)
+	return args.Get(0).(*resource.SecurityGroup), args.Error(1)
+}
+
+// Update mocks SecurityGroup.Update
+func (m *MockSecurityGroup) Update(ctx context.Context, guid string, r *resource.SecurityGroupUpdate) (*resource.SecurityGroup, error) {
+	args := m.Called(guid, r)
+	return args.Get(0).(*resource.SecurityGroup), args.Error(1)
+}
+
+// Delete mocks SecurityGroup.Delete
+func (m *MockSecurityGroup) Delete(ctx context.Context, guid string) (string, error) {
+	args := m.Called(guid)
+	return args.String(0), args.Error(1)
+}
+
+// ListAll mocks SecurityGroup.ListAll
+func (m *MockSecurityGroup) ListAll(ctx context.Context, opts *client.SecurityGroupListOptions) ([]*resource.SecurityGroup, error) {
+	args := m.Called(opts.Names.Values, opts.RunningSpaceGUIDs.Values, opts.StagingSpaceGUIDs.Values)
+	return args.Get(0).([]*resource.SecurityGroup), args.Error(1)
+}
+
+// BindRunningSecurityGroup mocks SecurityGroup.BindRunningSecurityGroup
+func (m *MockSecurityGroup) BindRunningSecurityGroup(ctx context.Context, guid string, spaceGUIDs []string) ([]string, error) {
+	args := m.Called(guid, spaceGUIDs)
+	return spaceGUIDs, args.Error(0)
+}
+
+// BindStagingSecurityGroup mocks SecurityGroup.BindStagingSecurityGroup
+func (m *MockSecurityGroup) BindStagingSecurityGroup(ctx context.Context, guid string, spaceGUIDs []string) ([]string, error) {
+	args := m.Called(guid, spaceGUIDs)
+	return spaceGUIDs, args.Error(0)
+}
+
+// UnBindRunningSecurityGroup mocks SecurityGroup.UnBindRunningSecurityGroup
+func (m *MockSecurityGroup) UnBindRunningSecurityGroup(ctx context.Context, guid string, spaceGUID string) error {
+	args := m.Called(guid, spaceGUID)
+	return args.Error(0)
+}
+
+// UnBindStagingSecurityGroup mocks SecurityGroup.UnBindStagingSecurityGroup
+func (m *MockSecurityGroup) UnBindStagingSecurityGroup(ctx context.Context, guid string, spaceGUID string) error {
+	args := m.Called(guid, spaceGUID)
+	return args.Error(0)
+}
+
+// SecurityGroupNil is a nil SecurityGroup
+var (
+	SecurityGroupNil *resource.SecurityGroup
+)
+
+// SecurityGroup is a SecurityGroup object
+type SecurityGroup struct {
+	resource.SecurityGroup
+}
+
+// NewSecurityGroup generate a new SecurityGroup
+func NewSecurityGroup() *SecurityGroup {
+	return &SecurityGroup{}
+}
+
+// SetName assigns SecurityGroup name
+func (s *SecurityGroup) SetName(name string) *SecurityGroup {
+	s.Name = name
+	return s
+}
+
+// SetGUID assigns SecurityGroup GUID
+func (s *SecurityGroup) SetGUID(guid string) *SecurityGroup {
+	s.GUID = guid
+	return s
+}
+
+// SetRules assigns SecurityGroup rules
+func (s *SecurityGroup) SetRules(rules ...resource.SecurityGroupRule) *SecurityGroup {
+	s.Rules = rules
+	return s
+}
+
+// SetGloballyEnabled assigns SecurityGroup global running and staging flags
+func (s *SecurityGroup) SetGloballyEnabled(running, staging bool) *SecurityGroup {
+	s.GloballyEnabled = resource.SecurityGroupGloballyEnabled{Running: &running, Staging: &staging}
+	return s
+}
+
+// SetRunningSpaces assigns SecurityGroup running spaces
+func (s *SecurityGroup) SetRunningSpaces(guids ...string) *SecurityGroup {
+	s.Relationships.RunningSpaces = *resource.NewToManyRelationships(guids)
+	return s
+}
+
+// SetStagingSpaces assigns SecurityGroup staging spaces
+func (s *SecurityGroup) SetStagingSpaces(guids ...string) *SecurityGroup {
+	s.Relationships.StagingSpaces = *resource.NewToManyRelationships(guids)
+	return s
+}
@@ -47,6 +47,18 @@ func (m *MockServiceCredentialBinding) Update(ctx context.Context, guid string,
 	return args.Get(0).(*resource.ServiceCredentialBinding), args.Error(1)
 }
 
+// List mocks ServiceCredentialBinding.List
+func (m *MockServiceCredentialBinding) List(ctx context.Context, opt *client.ServiceCredentialBindingListOptions) ([]*resource.ServiceCredentialBinding, *client.Pager, error) {
+	args := m.Called(ctx, opt)
+	return args.Get(0).([]*resource.ServiceCredentialBinding), nil, args.Error(1)
+}
+
+// ListAll mocks ServiceCredentialBinding.ListAll
+func (m *MockServiceCredentialBinding) ListAll(ctx context.Context, opt *client.ServiceCredentialBindingListOptions) ([]*resource.ServiceCredentialBinding, error) {
+	args := m.Called(ctx, opt)
+	return args.Get(0).([]*resource.ServiceCredentialBinding), args.Error(1)
+}
+
 // Single mocks ServiceCredentialBinding.Single
 func (m *MockServiceCredentialBinding) Single(ctx context.Context, opt *client.ServiceCredentialBindingListOptions) (*resource.ServiceCredentialBinding, error) {
 	args := m.Called(ctx, opt)
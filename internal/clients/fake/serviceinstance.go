@@ -69,6 +69,12 @@ func (m *MockServiceInstance) Delete(ctx context.Context, guid string) (string,
 	return args.String(0), args.Error(1)
 }
 
+// Purge mocks Purger.Purge
+func (m *MockServiceInstance) Purge(ctx context.Context, guid string) error {
+	args := m.Called(guid)
+	return args.Error(0)
+}
+
 // PollComplete mocks ServiceInstance.PollComplete
 func (m *MockServiceInstance) PollComplete(ctx context.Context, job string, opt *client.PollingOptions) error {
 	args := m.Called()
@@ -130,3 +136,27 @@ func (s *ServiceInstance) SetLastOperation(op, state string) *ServiceInstance {
 	}
 	return s
 }
+
+// SetDashboardURL assigns ServiceInstance DashboardURL
+func (s *ServiceInstance) SetDashboardURL(url string) *ServiceInstance {
+	s.DashboardURL = &url
+	return s
+}
+
+// SetRouteServiceURL assigns ServiceInstance RouteServiceURL
+func (s *ServiceInstance) SetRouteServiceURL(url string) *ServiceInstance {
+	s.RouteServiceURL = &url
+	return s
+}
+
+// SetSyslogDrainURL assigns ServiceInstance SyslogDrainURL
+func (s *ServiceInstance) SetSyslogDrainURL(url string) *ServiceInstance {
+	s.SyslogDrainURL = &url
+	return s
+}
+
+// SetMaintenanceInfo assigns ServiceInstance MaintenanceInfo
+func (s *ServiceInstance) SetMaintenanceInfo(version, description string) *ServiceInstance {
+	s.MaintenanceInfo = &resource.ServiceInstanceMaintenanceInfo{Version: version, Description: description}
+	return s
+}
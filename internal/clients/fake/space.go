@@ -18,16 +18,16 @@ type MockFeature struct {
 	mock.Mock
 }
 
-// EnableSSH mocks Feature.EnableSSH
-func (m *MockFeature) EnableSSH(ctx context.Context, spaceGUID string, enable bool) error {
+// ListFeatures mocks Feature.ListFeatures
+func (m *MockFeature) ListFeatures(ctx context.Context, spaceGUID string) (map[string]bool, error) {
 	args := m.Called()
-	return args.Error(0)
+	return args.Get(0).(map[string]bool), args.Error(1)
 }
 
-// IsSSHEnabled mocks Feature.IsSSHEnabled
-func (m *MockFeature) IsSSHEnabled(ctx context.Context, spaceGUID string) (bool, error) {
-	args := m.Called()
-	return args.Bool(0), args.Error(1)
+// EnableFeature mocks Feature.EnableFeature
+func (m *MockFeature) EnableFeature(ctx context.Context, spaceGUID, name string, enable bool) error {
+	args := m.Called(name, enable)
+	return args.Error(0)
 }
 
 // Get mocks Space.Get
@@ -60,6 +60,29 @@ func (m *MockSpace) Delete(ctx context.Context, guid string) (string, error) {
 	return args.String(0), args.Error(1)
 }
 
+// GetAssignedIsolationSegment mocks Space.GetAssignedIsolationSegment
+func (m *MockSpace) GetAssignedIsolationSegment(ctx context.Context, guid string) (string, error) {
+	args := m.Called(guid)
+	return args.String(0), args.Error(1)
+}
+
+// AssignIsolationSegment mocks Space.AssignIsolationSegment
+func (m *MockSpace) AssignIsolationSegment(ctx context.Context, guid, isolationSegmentGUID string) error {
+	args := m.Called(guid, isolationSegmentGUID)
+	return args.Error(0)
+}
+
+// MockIsolationSegment mocks IsolationSegment interfaces
+type MockIsolationSegment struct {
+	mock.Mock
+}
+
+// Single mocks IsolationSegment.Single
+func (m *MockIsolationSegment) Single(ctx context.Context, opts *client.IsolationSegmentListOptions) (*resource.IsolationSegment, error) {
+	args := m.Called(opts.Names.Values)
+	return args.Get(0).(*resource.IsolationSegment), args.Error(1)
+}
+
 // Space is a nil Space
 var (
 	SpaceNil *resource.Space
@@ -99,3 +122,12 @@ func (s *Space) SetRelationships(guid string) *Space {
 	}
 	return s
 }
+
+// SetLabels assigns Space Labels
+func (s *Space) SetLabels(labels map[string]*string) *Space {
+	if s.Metadata == nil {
+		s.Metadata = &resource.Metadata{}
+	}
+	s.Metadata.Labels = labels
+	return s
+}
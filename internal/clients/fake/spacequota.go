@@ -28,12 +28,12 @@ func (m *MockSpaceQuota) Update(ctx context.Context, guid string, r *resource.Sp
 }
 
 func (m *MockSpaceQuota) Apply(ctx context.Context, guid string, spaceGUIDs []string) ([]string, error) {
-	args := m.Called()
-	return args.Get(0).([]string), args.Error(1)
+	args := m.Called(spaceGUIDs)
+	return spaceGUIDs, args.Error(0)
 }
 
 func (m *MockSpaceQuota) Remove(ctx context.Context, guid, spaceGUID string) error {
-	args := m.Called()
+	args := m.Called(spaceGUID)
 	return args.Error(0)
 }
 
@@ -80,3 +80,9 @@ func (s *SpaceQuota) SetOrgGUID(guid string) *SpaceQuota {
 	}
 	return s
 }
+
+// SetSpaces assigns the Spaces the SpaceQuota is applied to
+func (s *SpaceQuota) SetSpaces(guids ...string) *SpaceQuota {
+	s.Relationships.Spaces = resource.NewToManyRelationships(guids)
+	return s
+}
@@ -0,0 +1,71 @@
+package fake
+
+import (
+	"context"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockStack mocks Stack interfaces
+type MockStack struct {
+	mock.Mock
+}
+
+// Get mocks Stack.Get
+func (m *MockStack) Get(ctx context.Context, guid string) (*resource.Stack, error) {
+	args := m.Called(guid)
+	return args.Get(0).(*resource.Stack), args.Error(1)
+}
+
+// Single mocks Stack.Single
+func (m *MockStack) Single(ctx context.Context, opts *client.StackListOptions) (*resource.Stack, error) {
+	args := m.Called(opts.Names.Values)
+	return args.Get(0).(*resource.Stack), args.Error(1)
+}
+
+// Create mocks Stack.Create
+func (m *MockStack) Create(ctx context.Context, r *resource.StackCreate) (*resource.Stack, error) {
+	args := m.Called(r)
+	return args.Get(0).(*resource.Stack), args.Error(1)
+}
+
+// Delete mocks Stack.Delete
+func (m *MockStack) Delete(ctx context.Context, guid string) error {
+	args := m.Called(guid)
+	return args.Error(0)
+}
+
+// StackNil is a nil Stack
+var (
+	StackNil *resource.Stack
+)
+
+// Stack is a Stack object
+type Stack struct {
+	resource.Stack
+}
+
+// NewStack generate a new Stack
+func NewStack() *Stack {
+	return &Stack{}
+}
+
+// SetName assigns Stack name
+func (s *Stack) SetName(name string) *Stack {
+	s.Name = name
+	return s
+}
+
+// SetGUID assigns Stack GUID
+func (s *Stack) SetGUID(guid string) *Stack {
+	s.GUID = guid
+	return s
+}
+
+// SetDescription assigns Stack description
+func (s *Stack) SetDescription(description string) *Stack {
+	s.Description = &description
+	return s
+}
@@ -0,0 +1,20 @@
+package fake
+
+import (
+	"context"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockUser mocks User interfaces
+type MockUser struct {
+	mock.Mock
+}
+
+// ListAll mocks User.ListAll
+func (m *MockUser) ListAll(ctx context.Context, opts *client.UserListOptions) ([]*resource.User, error) {
+	args := m.Called()
+	return args.Get(0).([]*resource.User), args.Error(1)
+}
@@ -0,0 +1,55 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// RequestTimeoutOverrider is implemented by managed resources that can
+// extend the timeout of a single request to the CF API beyond the default of
+// go-cfclient, e.g. for service brokers that respond slowly.
+type RequestTimeoutOverrider interface {
+	// GetRequestTimeout returns the request timeout of the managed resource,
+	// or nil if it does not set one.
+	GetRequestTimeout() *time.Duration
+}
+
+type requestHeaderKey struct{}
+
+// WithRequestHeader returns a copy of ctx that makes the CF client send the
+// given header with every request made with the returned context.
+// go-cfclient does not support headers per request.
+func WithRequestHeader(ctx context.Context, key, value string) context.Context {
+	h := RequestHeaders(ctx).Clone()
+	if h == nil {
+		h = http.Header{}
+	}
+	h.Set(key, value)
+	return context.WithValue(ctx, requestHeaderKey{}, h)
+}
+
+// RequestHeaders returns the request headers set on ctx with
+// WithRequestHeader, or nil if there are none.
+func RequestHeaders(ctx context.Context) http.Header {
+	h, _ := ctx.Value(requestHeaderKey{}).(http.Header)
+	return h
+}
+
+// headerTransport sets the request headers of the request context.
+type headerTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	h := RequestHeaders(req.Context())
+	if len(h) == 0 {
+		return t.base.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	for k, v := range h {
+		req.Header[k] = v
+	}
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,55 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHeaderTransport(t *testing.T) {
+	cases := map[string]struct {
+		ctx  context.Context
+		want string
+	}{
+		"NoHeader": {
+			ctx:  context.Background(),
+			want: "",
+		},
+		"Header": {
+			ctx:  WithRequestHeader(context.Background(), "Idempotency-Key", "uid-1"),
+			want: "uid-1",
+		},
+		"HeaderOverridden": {
+			ctx:  WithRequestHeader(WithRequestHeader(context.Background(), "Idempotency-Key", "uid-1"), "Idempotency-Key", "uid-2"),
+			want: "uid-2",
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			var got string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Get("Idempotency-Key")
+			}))
+			defer srv.Close()
+
+			c := &http.Client{Transport: &headerTransport{base: http.DefaultTransport}}
+			req, err := http.NewRequestWithContext(tc.ctx, http.MethodPost, srv.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := c.Do(req)
+			if err != nil {
+				t.Fatalf("Do(...): unexpected error: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Do(...): -want Idempotency-Key, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -3,10 +3,14 @@ package job
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
 	"time"
 
 	"github.com/cloudfoundry/go-cfclient/v3/client"
 	"github.com/cloudfoundry/go-cfclient/v3/config"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
 )
 
 // Job defines interfaces to async operations/jobs.
@@ -48,3 +52,126 @@ func PollJobComplete(ctx context.Context, job Job, jobGUID string) error {
 
 	return err
 }
+
+// ErrPollTimeout is returned by PollWithBackoff if the job has not
+// completed within the polling timeout. It is distinct from a job
+// failure: the job may still complete later.
+var ErrPollTimeout = errors.New("timed out waiting for job to complete")
+
+// Getter defines the interface to read the state of an async job.
+type Getter interface {
+	Get(ctx context.Context, guid string) (*resource.Job, error)
+}
+
+// BackoffOptions configures PollWithBackoff.
+type BackoffOptions struct {
+	// Timeout is the total time to wait for the job to complete.
+	// The default is used if it is not positive.
+	Timeout time.Duration
+	// InitialInterval is the wait time before the second poll.
+	// The default is used if it is not positive.
+	InitialInterval time.Duration
+	// MaxInterval caps the wait time between two polls.
+	MaxInterval time.Duration
+	// Multiplier is applied to the wait time after each poll.
+	// The default is used if it is not greater than 1.
+	Multiplier float64
+	// Jitter randomizes each wait time by up to this fraction.
+	Jitter float64
+}
+
+// NewBackoffOptions returns the default options of PollWithBackoff.
+func NewBackoffOptions() *BackoffOptions {
+	return &BackoffOptions{
+		Timeout:         pollTimeout,
+		InitialInterval: time.Second,
+		MaxInterval:     pollInterval,
+		Multiplier:      2,
+		Jitter:          0.2,
+	}
+}
+
+// withDefaults returns a copy of o in which the fields that would make
+// PollWithBackoff poll without waiting are replaced by their defaults.
+func (o BackoffOptions) withDefaults() *BackoffOptions {
+	d := NewBackoffOptions()
+	if o.Timeout <= 0 {
+		o.Timeout = d.Timeout
+	}
+	if o.InitialInterval <= 0 {
+		o.InitialInterval = d.InitialInterval
+	}
+	if o.Multiplier <= 1 {
+		o.Multiplier = d.Multiplier
+	}
+	return &o
+}
+
+// PollWithBackoff polls the job until it is complete, waiting with an
+// exponential backoff between the polls. Network timeouts are retried.
+// It returns ErrPollTimeout if the job is still running when
+// opts.Timeout elapses, the errors of the job if it failed, or the
+// context error if ctx is done.
+func PollWithBackoff(ctx context.Context, c Getter, guid string, opts *BackoffOptions) error {
+	if opts == nil {
+		opts = NewBackoffOptions()
+	}
+	opts = opts.withDefaults()
+	deadline := time.Now().Add(opts.Timeout)
+	interval := opts.InitialInterval
+
+	for {
+		j, err := c.Get(ctx, guid)
+		switch {
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case err != nil && !isTransient(err):
+			return err
+		case err == nil && j.State == resource.JobStateComplete:
+			return nil
+		case err == nil && j.State == resource.JobStateFailed:
+			return jobFailed(guid, j)
+		}
+
+		wait := jitter(interval, opts.Jitter)
+		if time.Until(deadline) < wait {
+			return fmt.Errorf("%w: job %s", ErrPollTimeout, guid)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		interval = time.Duration(float64(interval) * opts.Multiplier)
+		if opts.MaxInterval > 0 && interval > opts.MaxInterval {
+			interval = opts.MaxInterval
+		}
+	}
+}
+
+// isTransient returns true if err is a network timeout, which is
+// worth retrying.
+func isTransient(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// jitter randomizes d by up to the fraction f in both directions.
+func jitter(d time.Duration, f float64) time.Duration {
+	if f <= 0 {
+		return d
+	}
+	return time.Duration(float64(d) * (1 + f*(2*rand.Float64()-1)))
+}
+
+// jobFailed returns an error containing the errors reported by a failed job.
+func jobFailed(guid string, j *resource.Job) error {
+	errs := make([]error, 0, len(j.Errors))
+	for _, e := range j.Errors {
+		errs = append(errs, e)
+	}
+	return fmt.Errorf("job %s failed: %w", guid, errors.Join(errs...))
+}
@@ -0,0 +1,155 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/fake"
+)
+
+const jobGUID = "0a9b0d04-d537-4e4e-8c6f-f09ca0e7f56a"
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+var (
+	errHTTPTimeout = &url.Error{Op: "Get", URL: "https://api.cf.example.com/v3/jobs/" + jobGUID, Err: timeoutError{}}
+	errBoom        = errors.New("boom")
+	nilJob         *resource.Job
+)
+
+func withState(state resource.JobState) *resource.Job {
+	return &resource.Job{State: state}
+}
+
+func testOptions() *BackoffOptions {
+	return &BackoffOptions{
+		Timeout:         time.Second,
+		InitialInterval: time.Millisecond,
+		MaxInterval:     5 * time.Millisecond,
+		Multiplier:      2,
+	}
+}
+
+func TestPollWithBackoff(t *testing.T) {
+	cases := map[string]struct {
+		mock    func() *fake.MockJob
+		opts    *BackoffOptions
+		wantErr func(error) bool
+	}{
+		"CompleteAfterTimeouts": {
+			mock: func() *fake.MockJob {
+				m := &fake.MockJob{}
+				m.On("Get", jobGUID).Return(nilJob, errHTTPTimeout).Twice()
+				m.On("Get", jobGUID).Return(withState(resource.JobStateProcessing), nil).Once()
+				m.On("Get", jobGUID).Return(withState(resource.JobStateComplete), nil).Once()
+				return m
+			},
+			opts:    testOptions(),
+			wantErr: func(err error) bool { return err == nil },
+		},
+		"Failed": {
+			mock: func() *fake.MockJob {
+				m := &fake.MockJob{}
+				j := withState(resource.JobStateFailed)
+				j.Errors = []resource.CloudFoundryError{{Code: 10008, Title: "CF-UnprocessableEntity", Detail: "quota exceeded"}}
+				m.On("Get", jobGUID).Return(j, nil).Once()
+				return m
+			},
+			opts: testOptions(),
+			wantErr: func(err error) bool {
+				return err != nil && !errors.Is(err, ErrPollTimeout)
+			},
+		},
+		"GetError": {
+			mock: func() *fake.MockJob {
+				m := &fake.MockJob{}
+				m.On("Get", jobGUID).Return(nilJob, errBoom).Once()
+				return m
+			},
+			opts:    testOptions(),
+			wantErr: func(err error) bool { return errors.Is(err, errBoom) },
+		},
+		"Timeout": {
+			mock: func() *fake.MockJob {
+				m := &fake.MockJob{}
+				m.On("Get", jobGUID).Return(withState(resource.JobStateProcessing), nil)
+				return m
+			},
+			opts: func() *BackoffOptions {
+				o := testOptions()
+				o.Timeout = 20 * time.Millisecond
+				return o
+			}(),
+			wantErr: func(err error) bool { return errors.Is(err, ErrPollTimeout) },
+		},
+		"TimeoutOnNetworkErrors": {
+			mock: func() *fake.MockJob {
+				m := &fake.MockJob{}
+				m.On("Get", jobGUID).Return(nilJob, errHTTPTimeout)
+				return m
+			},
+			opts: func() *BackoffOptions {
+				o := testOptions()
+				o.Timeout = 20 * time.Millisecond
+				return o
+			}(),
+			wantErr: func(err error) bool { return errors.Is(err, ErrPollTimeout) },
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			m := tc.mock()
+			err := PollWithBackoff(context.Background(), m, jobGUID, tc.opts)
+			if !tc.wantErr(err) {
+				t.Errorf("PollWithBackoff(...): unexpected error: %v", err)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPollWithBackoffCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &fake.MockJob{}
+	m.On("Get", jobGUID).Return(withState(resource.JobStateProcessing), nil).Run(func(_ mock.Arguments) { cancel() })
+
+	if err := PollWithBackoff(ctx, m, jobGUID, testOptions()); !errors.Is(err, context.Canceled) {
+		t.Errorf("PollWithBackoff(...): want %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestBackoffOptionsWithDefaults(t *testing.T) {
+	got := BackoffOptions{MaxInterval: time.Second, Multiplier: 0.5}.withDefaults()
+	want := &BackoffOptions{
+		Timeout:         pollTimeout,
+		InitialInterval: time.Second,
+		MaxInterval:     time.Second,
+		Multiplier:      2,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("withDefaults(): -want, +got:\n%s", diff)
+	}
+}
+
+func TestJitter(t *testing.T) {
+	for range 100 {
+		if d := jitter(time.Second, 0.2); d < 800*time.Millisecond || d > 1200*time.Millisecond {
+			t.Fatalf("jitter(...): %v out of range", d)
+		}
+	}
+	if d := jitter(time.Second, 0); d != time.Second {
+		t.Errorf("jitter(...): want %v, got %v", time.Second, d)
+	}
+}
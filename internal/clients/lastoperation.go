@@ -0,0 +1,12 @@
+package clients
+
+import (
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+)
+
+// IsOperationInProgress returns true if the last operation of an asynchronous
+// resource with the given state has not finished yet. CF rejects changes to
+// such a resource, so controllers neither schedule nor issue an update then.
+func IsOperationInProgress(state string) bool {
+	return state == v1alpha1.LastOperationInitial || state == v1alpha1.LastOperationInProgress
+}
@@ -0,0 +1,22 @@
+package clients
+
+import (
+	"testing"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+)
+
+func TestIsOperationInProgress(t *testing.T) {
+	cases := map[string]bool{
+		v1alpha1.LastOperationInitial:    true,
+		v1alpha1.LastOperationInProgress: true,
+		v1alpha1.LastOperationSucceeded:  false,
+		v1alpha1.LastOperationFailed:     false,
+		"":                               false,
+	}
+	for state, want := range cases {
+		if got := IsOperationInProgress(state); got != want {
+			t.Errorf("IsOperationInProgress(%q): want %t, got %t", state, want, got)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package clients
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	xpresource "github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+)
+
+// LastOperationGetter is implemented by asynchronous managed resources that
+// record the last operation on their external resource.
+type LastOperationGetter interface {
+	// GetLastOperation returns the last operation, or nil if none was observed.
+	GetLastOperation() *v1alpha1.LastOperation
+}
+
+// idGetter is implemented by managed resources that record the GUID of
+// their external resource.
+type idGetter interface {
+	GetID() string
+}
+
+// loggerKey is the context key of the logger of a managed resource.
+type loggerKey struct{}
+
+// ResourceLogger returns log with the namespace, name and external name of
+// mg, and with the GUID and the last operation of its external resource if
+// they are observed.
+func ResourceLogger(log logging.Logger, mg xpresource.Managed) logging.Logger {
+	kv := []any{"namespace", mg.GetNamespace(), "name", mg.GetName(), "external-name", meta.GetExternalName(mg)}
+	if r, ok := mg.(idGetter); ok && r.GetID() != "" {
+		kv = append(kv, "guid", r.GetID())
+	}
+	if r, ok := mg.(LastOperationGetter); ok {
+		if op := r.GetLastOperation(); op != nil && op.Type != "" {
+			kv = append(kv, "last-operation", op.Type, "last-operation-state", op.State)
+		}
+	}
+	return log.WithValues(kv...)
+}
+
+// LoggerFrom returns the logger of the managed resource of an operation, or
+// a logger that discards everything if the context does not carry one.
+func LoggerFrom(ctx context.Context) logging.Logger {
+	if log, ok := ctx.Value(loggerKey{}).(logging.Logger); ok {
+		return log
+	}
+	return logging.NewNopLogger()
+}
+
+// WithLogger wraps an ExternalConnecter so that every operation of the
+// ExternalClients it produces, and Connect itself, gets a context carrying log
+// enriched with the identifiers of the managed resource, see ResourceLogger
+// and LoggerFrom.
+func WithLogger(log logging.Logger, c managed.ExternalConnecter) managed.ExternalConnecter {
+	return managed.ExternalConnectorFn(func(ctx context.Context, mg xpresource.Managed) (managed.ExternalClient, error) {
+		ec, err := c.Connect(context.WithValue(ctx, loggerKey{}, ResourceLogger(log, mg)), mg)
+		if err != nil {
+			return nil, err
+		}
+		return &loggingClient{ExternalClient: ec, log: log}, nil
+	})
+}
+
+// loggingClient passes the logger of the managed resource to every operation of an ExternalClient.
+type loggingClient struct {
+	managed.ExternalClient
+	log logging.Logger
+}
+
+func (c *loggingClient) withLogger(ctx context.Context, mg xpresource.Managed) context.Context {
+	return context.WithValue(ctx, loggerKey{}, ResourceLogger(c.log, mg))
+}
+
+func (c *loggingClient) Observe(ctx context.Context, mg xpresource.Managed) (managed.ExternalObservation, error) {
+	return c.ExternalClient.Observe(c.withLogger(ctx, mg), mg)
+}
+
+func (c *loggingClient) Create(ctx context.Context, mg xpresource.Managed) (managed.ExternalCreation, error) {
+	return c.ExternalClient.Create(c.withLogger(ctx, mg), mg)
+}
+
+func (c *loggingClient) Update(ctx context.Context, mg xpresource.Managed) (managed.ExternalUpdate, error) {
+	return c.ExternalClient.Update(c.withLogger(ctx, mg), mg)
+}
+
+func (c *loggingClient) Delete(ctx context.Context, mg xpresource.Managed) (managed.ExternalDelete, error) {
+	return c.ExternalClient.Delete(c.withLogger(ctx, mg), mg)
+}
@@ -0,0 +1,75 @@
+package clients
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	xpresource "github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+)
+
+// recordingLogger is a logging.Logger that records the key-value pairs of the messages logged with it.
+type recordingLogger struct {
+	kv      []any
+	entries *[]map[string]any
+}
+
+func (l recordingLogger) record(msg string, kv ...any) {
+	entry := map[string]any{"msg": msg}
+	all := append(append([]any{}, l.kv...), kv...)
+	for i := 0; i+1 < len(all); i += 2 {
+		entry[all[i].(string)] = all[i+1]
+	}
+	*l.entries = append(*l.entries, entry)
+}
+
+func (l recordingLogger) Info(msg string, kv ...any)  { l.record(msg, kv...) }
+func (l recordingLogger) Debug(msg string, kv ...any) { l.record(msg, kv...) }
+func (l recordingLogger) WithValues(kv ...any) logging.Logger {
+	return recordingLogger{kv: append(append([]any{}, l.kv...), kv...), entries: l.entries}
+}
+
+func TestWithLogger(t *testing.T) {
+	cr := &v1alpha1.ServiceInstance{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "db"}}
+	meta.SetExternalName(cr, "2d8b0d04-d537-4e4e-8c6f-f09ca0e7f56f")
+	cr.Status.AtProvider.ID = ptr.To("2d8b0d04-d537-4e4e-8c6f-f09ca0e7f56f")
+	cr.Status.AtProvider.LastOperation = v1alpha1.LastOperation{Type: v1alpha1.LastOperationUpdate, State: v1alpha1.LastOperationInProgress}
+
+	var entries []map[string]any
+	log := recordingLogger{kv: []any{"controller", "serviceinstance"}, entries: &entries}
+	c, err := WithLogger(log, managed.ExternalConnectorFn(func(context.Context, xpresource.Managed) (managed.ExternalClient, error) {
+		return &managed.ExternalClientFns{
+			UpdateFn: func(ctx context.Context, _ xpresource.Managed) (managed.ExternalUpdate, error) {
+				LoggerFrom(ctx).Debug("Updated service instance")
+				return managed.ExternalUpdate{}, nil
+			},
+		}, nil
+	})).Connect(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("Connect(...): unexpected error: %v", err)
+	}
+	if _, err := c.Update(context.Background(), cr); err != nil {
+		t.Fatalf("Update(...): unexpected error: %v", err)
+	}
+
+	want := []map[string]any{{
+		"msg":                  "Updated service instance",
+		"controller":           "serviceinstance",
+		"namespace":            "default",
+		"name":                 "db",
+		"external-name":        "2d8b0d04-d537-4e4e-8c6f-f09ca0e7f56f",
+		"guid":                 "2d8b0d04-d537-4e4e-8c6f-f09ca0e7f56f",
+		"last-operation":       v1alpha1.LastOperationUpdate,
+		"last-operation-state": v1alpha1.LastOperationInProgress,
+	}}
+	if diff := cmp.Diff(want, entries); diff != "" {
+		t.Errorf("Update(...): -want log entries, +got:\n%s", diff)
+	}
+}
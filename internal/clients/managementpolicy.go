@@ -0,0 +1,15 @@
+package clients
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+)
+
+// IsObserveOnly returns true if the management policies of the managed
+// resource only allow observing its external resource. Such a resource is
+// never created, updated or deleted, so the requirements of these operations,
+// e.g. references, do not apply to it.
+func IsObserveOnly(mg resource.Managed) bool {
+	p := mg.GetManagementPolicies()
+	return len(p) == 1 && p[0] == xpv1.ManagementActionObserve
+}
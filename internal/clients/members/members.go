@@ -82,7 +82,7 @@ func (c *Client) ObserveOrgMembers(ctx context.Context, cr *v1alpha1.OrgMembers)
 	// sync every currently assigned role and remove it from members list if it no longer exists
 	for user, role := range cr.Status.AtProvider.AssignedRoles {
 		_, err := c.Roles.Get(ctx, role)
-		if err != nil && clients.ErrorIsNotFound(err) {
+		if err != nil && clients.IsNotFound(err) {
 			delete(cr.Status.AtProvider.AssignedRoles, user)
 		}
 	}
@@ -210,7 +210,7 @@ func (c *Client) ObserveSpaceMembers(ctx context.Context, cr *v1alpha1.SpaceMemb
 	// sync every currently assigned role and remove it from members list if it no longer exists
 	for user, role := range cr.Status.AtProvider.AssignedRoles {
 		_, err := c.Roles.Get(ctx, role)
-		if err != nil && clients.ErrorIsNotFound(err) {
+		if err != nil && clients.IsNotFound(err) {
 			delete(cr.Status.AtProvider.AssignedRoles, user)
 		}
 	}
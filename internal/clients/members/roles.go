@@ -184,7 +184,7 @@ func (c *Client) RemoveUsersFromRole(ctx context.Context, roleMembers map[string
 func (c *Client) DeleteRole(ctx context.Context, role string) error {
 	_, err := c.Roles.Delete(ctx, role)
 	// suppress not_found
-	if err != nil && !clients.ErrorIsNotFound(err) {
+	if err != nil && !clients.IsNotFound(err) {
 		return err
 	}
 	return nil
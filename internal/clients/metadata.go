@@ -0,0 +1,129 @@
+package clients
+
+import (
+	"slices"
+
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+)
+
+// NewMetadata returns the metadata of a create or update request for the given labels and annotations.
+// It returns nil if neither is set, so that the metadata of the resource is left as is.
+func NewMetadata(labels, annotations map[string]*string) *resource.Metadata {
+	if len(labels) == 0 && len(annotations) == 0 {
+		return nil
+	}
+	return &resource.Metadata{Labels: labels, Annotations: annotations}
+}
+
+// MetadataEqual reports whether the observed metadata has the desired labels and annotations, and none of the
+// managed ones that are no longer desired. Labels and annotations set by others are ignored.
+func MetadataEqual(labels, annotations map[string]*string, managed v1alpha1.ManagedMetadata, observed *resource.Metadata) bool {
+	if observed == nil {
+		observed = &resource.Metadata{}
+	}
+	return metadataMapUpToDate(labels, managed.ManagedLabels, observed.Labels) &&
+		metadataMapUpToDate(annotations, managed.ManagedAnnotations, observed.Annotations)
+}
+
+func metadataMapUpToDate(desired map[string]*string, managed []string, observed map[string]*string) bool {
+	for k, v := range desired {
+		o := observed[k]
+		if (v == nil) != (o == nil) || v != nil && *v != *o {
+			return false
+		}
+	}
+	for _, k := range managed {
+		if _, ok := desired[k]; ok {
+			continue
+		}
+		if _, ok := observed[k]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// MetadataMapEqual compares two metadata maps (labels or annotations).
+func MetadataMapEqual(desired, actual map[string]*string) bool {
+	// check if both are nil/empty
+	if len(desired) == 0 && len(actual) == 0 {
+		return true
+	}
+
+	if len(desired) != len(actual) {
+		return false
+	}
+
+	// Compare each key-value pair
+	for key, desiredVal := range desired {
+		actualVal, exists := actual[key]
+		if !exists {
+			return false
+		}
+
+		if (desiredVal == nil) != (actualVal == nil) {
+			return false
+		}
+		if desiredVal != nil && actualVal != nil && *desiredVal != *actualVal {
+			return false
+		}
+	}
+
+	return true
+}
+
+// MetadataPatch returns the metadata of an update request that sets the desired labels and annotations.
+// The CF API merges metadata on PATCH, so managed keys that are no longer desired are sent with a null value
+// to remove them, and labels and annotations set by others are left as is.
+// It returns nil if there is nothing to patch.
+func MetadataPatch(labels, annotations map[string]*string, managed v1alpha1.ManagedMetadata, observed *resource.Metadata) *resource.Metadata {
+	if observed == nil {
+		observed = &resource.Metadata{}
+	}
+	return NewMetadata(metadataMapPatch(labels, managed.ManagedLabels, observed.Labels),
+		metadataMapPatch(annotations, managed.ManagedAnnotations, observed.Annotations))
+}
+
+func metadataMapPatch(desired map[string]*string, managed []string, observed map[string]*string) map[string]*string {
+	patch := make(map[string]*string, len(desired)+len(managed))
+	for _, k := range managed {
+		if _, ok := observed[k]; ok {
+			patch[k] = nil
+		}
+	}
+	for k, v := range desired {
+		patch[k] = v
+	}
+	return patch
+}
+
+// ObserveManagedMetadata returns the keys of the labels and annotations that a resource manages: the desired
+// keys, and the keys it managed before that are still observed, so that they are removed on the next update.
+func ObserveManagedMetadata(managed v1alpha1.ManagedMetadata, labels, annotations map[string]*string, observed *resource.Metadata) v1alpha1.ManagedMetadata {
+	if observed == nil {
+		observed = &resource.Metadata{}
+	}
+	return v1alpha1.ManagedMetadata{
+		ManagedAnnotations: managedKeys(annotations, managed.ManagedAnnotations, observed.Annotations),
+		ManagedLabels:      managedKeys(labels, managed.ManagedLabels, observed.Labels),
+	}
+}
+
+func managedKeys(desired map[string]*string, managed []string, observed map[string]*string) []string {
+	var keys []string
+	for k := range desired {
+		keys = append(keys, k)
+	}
+	for _, k := range managed {
+		if _, ok := desired[k]; ok {
+			continue
+		}
+		if _, ok := observed[k]; ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
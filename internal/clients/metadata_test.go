@@ -0,0 +1,440 @@
+package clients
+
+import (
+	"testing"
+
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/utils/ptr"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+)
+
+func TestMetadataEqual(t *testing.T) {
+	cases := map[string]struct {
+		desired  v1alpha1.ResourceMetadata
+		managed  v1alpha1.ManagedMetadata
+		observed *resource.Metadata
+		expected bool
+	}{
+		"BothNil": {
+			desired:  v1alpha1.ResourceMetadata{},
+			observed: nil,
+			expected: true,
+		},
+		"BothEmpty": {
+			desired:  v1alpha1.ResourceMetadata{},
+			observed: &resource.Metadata{},
+			expected: true,
+		},
+		"MatchingLabels": {
+			desired: v1alpha1.ResourceMetadata{
+				Labels: map[string]*string{
+					"env":  ptr.To("prod"),
+					"team": ptr.To("platform"),
+				},
+			},
+			observed: &resource.Metadata{
+				Labels: map[string]*string{
+					"env":  ptr.To("prod"),
+					"team": ptr.To("platform"),
+				},
+			},
+			expected: true,
+		},
+		"MatchingAnnotations": {
+			desired: v1alpha1.ResourceMetadata{
+				Annotations: map[string]*string{
+					"description": ptr.To("test binding"),
+				},
+			},
+			observed: &resource.Metadata{
+				Annotations: map[string]*string{
+					"description": ptr.To("test binding"),
+				},
+			},
+			expected: true,
+		},
+		"MatchingBoth": {
+			desired: v1alpha1.ResourceMetadata{
+				Labels: map[string]*string{
+					"env": ptr.To("prod"),
+				},
+				Annotations: map[string]*string{
+					"description": ptr.To("test"),
+				},
+			},
+			observed: &resource.Metadata{
+				Labels: map[string]*string{
+					"env": ptr.To("prod"),
+				},
+				Annotations: map[string]*string{
+					"description": ptr.To("test"),
+				},
+			},
+			expected: true,
+		},
+		"DifferentLabels": {
+			desired: v1alpha1.ResourceMetadata{
+				Labels: map[string]*string{
+					"env": ptr.To("prod"),
+				},
+			},
+			observed: &resource.Metadata{
+				Labels: map[string]*string{
+					"env": ptr.To("dev"),
+				},
+			},
+			expected: false,
+		},
+		"DifferentAnnotations": {
+			desired: v1alpha1.ResourceMetadata{
+				Annotations: map[string]*string{
+					"description": ptr.To("old"),
+				},
+			},
+			observed: &resource.Metadata{
+				Annotations: map[string]*string{
+					"description": ptr.To("new"),
+				},
+			},
+			expected: false,
+		},
+		"MissingLabelInActual": {
+			desired: v1alpha1.ResourceMetadata{
+				Labels: map[string]*string{
+					"env":  ptr.To("prod"),
+					"team": ptr.To("platform"),
+				},
+			},
+			observed: &resource.Metadata{
+				Labels: map[string]*string{
+					"env": ptr.To("prod"),
+				},
+			},
+			expected: false,
+		},
+		"ExtraLabelInActual": {
+			desired: v1alpha1.ResourceMetadata{
+				Labels: map[string]*string{
+					"env": ptr.To("prod"),
+				},
+			},
+			observed: &resource.Metadata{
+				Labels: map[string]*string{
+					"env":  ptr.To("prod"),
+					"team": ptr.To("platform"),
+				},
+			},
+			expected: true,
+		},
+		"SpecWithoutMetadataBindingHasLabels": {
+			desired: v1alpha1.ResourceMetadata{},
+			observed: &resource.Metadata{
+				Labels: map[string]*string{
+					"env": ptr.To("prod"),
+				},
+			},
+			expected: true,
+		},
+		"RemovedManagedLabel": {
+			desired: v1alpha1.ResourceMetadata{
+				Labels: map[string]*string{
+					"env": ptr.To("prod"),
+				},
+			},
+			managed: v1alpha1.ManagedMetadata{ManagedLabels: []string{"env", "team"}},
+			observed: &resource.Metadata{
+				Labels: map[string]*string{
+					"env":  ptr.To("prod"),
+					"team": ptr.To("platform"),
+				},
+			},
+			expected: false,
+		},
+		"RemovedManagedAnnotation": {
+			desired: v1alpha1.ResourceMetadata{},
+			managed: v1alpha1.ManagedMetadata{ManagedAnnotations: []string{"description"}},
+			observed: &resource.Metadata{
+				Annotations: map[string]*string{
+					"description": ptr.To("old"),
+				},
+			},
+			expected: false,
+		},
+		"RemovedManagedLabelGone": {
+			desired: v1alpha1.ResourceMetadata{},
+			managed: v1alpha1.ManagedMetadata{ManagedLabels: []string{"team"}},
+			observed: &resource.Metadata{
+				Labels: map[string]*string{
+					"env": ptr.To("prod"),
+				},
+			},
+			expected: true,
+		},
+		"SpecHasLabelsBindingHasNoMetadata": {
+			desired: v1alpha1.ResourceMetadata{
+				Labels: map[string]*string{
+					"env": ptr.To("prod"),
+				},
+			},
+			observed: nil,
+			expected: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			result := MetadataEqual(tc.desired.Labels, tc.desired.Annotations, tc.managed, tc.observed)
+			if diff := cmp.Diff(tc.expected, result); diff != "" {
+				t.Errorf("MetadataEqual(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMetadataMapEqual(t *testing.T) {
+	cases := map[string]struct {
+		desired  map[string]*string
+		actual   map[string]*string
+		expected bool
+	}{
+		"BothNil": {
+			desired:  nil,
+			actual:   nil,
+			expected: true,
+		},
+		"BothEmpty": {
+			desired:  map[string]*string{},
+			actual:   map[string]*string{},
+			expected: true,
+		},
+		"Equal": {
+			desired: map[string]*string{
+				"key1": ptr.To("value1"),
+				"key2": ptr.To("value2"),
+			},
+			actual: map[string]*string{
+				"key1": ptr.To("value1"),
+				"key2": ptr.To("value2"),
+			},
+			expected: true,
+		},
+		"DifferentValues": {
+			desired: map[string]*string{
+				"key1": ptr.To("value1"),
+			},
+			actual: map[string]*string{
+				"key1": ptr.To("value2"),
+			},
+			expected: false,
+		},
+		"DifferentLengths": {
+			desired: map[string]*string{
+				"key1": ptr.To("value1"),
+			},
+			actual: map[string]*string{
+				"key1": ptr.To("value1"),
+				"key2": ptr.To("value2"),
+			},
+			expected: false,
+		},
+		"MissingKeyInActual": {
+			desired: map[string]*string{
+				"key1": ptr.To("value1"),
+				"key2": ptr.To("value2"),
+			},
+			actual: map[string]*string{
+				"key1": ptr.To("value1"),
+			},
+			expected: false,
+		},
+		"NilDesiredEmptyActual": {
+			desired:  nil,
+			actual:   map[string]*string{},
+			expected: true,
+		},
+		"EmptyDesiredNilActual": {
+			desired:  map[string]*string{},
+			actual:   nil,
+			expected: true,
+		},
+		"NilDesiredNonEmptyActual": {
+			desired: nil,
+			actual: map[string]*string{
+				"key1": ptr.To("value1"),
+			},
+			expected: false,
+		},
+		"NonEmptyDesiredNilActual": {
+			desired: map[string]*string{
+				"key1": ptr.To("value1"),
+			},
+			actual:   nil,
+			expected: false,
+		},
+		"NilPointerValues": {
+			desired: map[string]*string{
+				"key1": nil,
+			},
+			actual: map[string]*string{
+				"key1": nil,
+			},
+			expected: true,
+		},
+		"MismatchedNilPointers": {
+			desired: map[string]*string{
+				"key1": ptr.To("value1"),
+			},
+			actual: map[string]*string{
+				"key1": nil,
+			},
+			expected: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			result := MetadataMapEqual(tc.desired, tc.actual)
+			if diff := cmp.Diff(tc.expected, result); diff != "" {
+				t.Errorf("MetadataMapEqual(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMetadataPatch(t *testing.T) {
+	cases := map[string]struct {
+		desired  v1alpha1.ResourceMetadata
+		managed  v1alpha1.ManagedMetadata
+		observed *resource.Metadata
+		want     *resource.Metadata
+	}{
+		"NothingToPatch": {
+			desired:  v1alpha1.ResourceMetadata{},
+			observed: &resource.Metadata{},
+			want:     nil,
+		},
+		"NoObservedMetadata": {
+			desired: v1alpha1.ResourceMetadata{
+				Labels: map[string]*string{"env": ptr.To("prod")},
+			},
+			observed: nil,
+			want: &resource.Metadata{
+				Labels:      map[string]*string{"env": ptr.To("prod")},
+				Annotations: map[string]*string{},
+			},
+		},
+		"ChangeValue": {
+			desired: v1alpha1.ResourceMetadata{
+				Labels: map[string]*string{"env": ptr.To("prod")},
+			},
+			observed: &resource.Metadata{
+				Labels: map[string]*string{"env": ptr.To("dev")},
+			},
+			want: &resource.Metadata{
+				Labels:      map[string]*string{"env": ptr.To("prod")},
+				Annotations: map[string]*string{},
+			},
+		},
+		"RemoveManagedKeys": {
+			desired: v1alpha1.ResourceMetadata{
+				Labels: map[string]*string{"env": ptr.To("prod")},
+			},
+			managed: v1alpha1.ManagedMetadata{
+				ManagedLabels:      []string{"env", "team", "gone"},
+				ManagedAnnotations: []string{"description"},
+			},
+			observed: &resource.Metadata{
+				Labels:      map[string]*string{"env": ptr.To("prod"), "team": ptr.To("platform")},
+				Annotations: map[string]*string{"description": ptr.To("old")},
+			},
+			want: &resource.Metadata{
+				Labels:      map[string]*string{"env": ptr.To("prod"), "team": nil},
+				Annotations: map[string]*string{"description": nil},
+			},
+		},
+		"KeepKeysSetByOthers": {
+			desired: v1alpha1.ResourceMetadata{
+				Labels: map[string]*string{"env": ptr.To("prod")},
+			},
+			managed: v1alpha1.ManagedMetadata{ManagedLabels: []string{"env"}},
+			observed: &resource.Metadata{
+				Labels:      map[string]*string{"env": ptr.To("dev"), "team": ptr.To("platform")},
+				Annotations: map[string]*string{"description": ptr.To("old")},
+			},
+			want: &resource.Metadata{
+				Labels:      map[string]*string{"env": ptr.To("prod")},
+				Annotations: map[string]*string{},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := MetadataPatch(tc.desired.Labels, tc.desired.Annotations, tc.managed, tc.observed)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("MetadataPatch(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestObserveManagedMetadata(t *testing.T) {
+	cases := map[string]struct {
+		desired  v1alpha1.ResourceMetadata
+		managed  v1alpha1.ManagedMetadata
+		observed *resource.Metadata
+		want     v1alpha1.ManagedMetadata
+	}{
+		"NothingManaged": {
+			observed: &resource.Metadata{
+				Labels: map[string]*string{"team": ptr.To("platform")},
+			},
+			want: v1alpha1.ManagedMetadata{},
+		},
+		"DesiredKeys": {
+			desired: v1alpha1.ResourceMetadata{
+				Labels:      map[string]*string{"env": ptr.To("prod"), "app": ptr.To("web")},
+				Annotations: map[string]*string{"description": ptr.To("test")},
+			},
+			observed: nil,
+			want: v1alpha1.ManagedMetadata{
+				ManagedLabels:      []string{"app", "env"},
+				ManagedAnnotations: []string{"description"},
+			},
+		},
+		"RemovedKeyStillObserved": {
+			desired: v1alpha1.ResourceMetadata{
+				Labels: map[string]*string{"env": ptr.To("prod")},
+			},
+			managed: v1alpha1.ManagedMetadata{ManagedLabels: []string{"env", "team"}},
+			observed: &resource.Metadata{
+				Labels: map[string]*string{"env": ptr.To("prod"), "team": ptr.To("platform")},
+			},
+			want: v1alpha1.ManagedMetadata{ManagedLabels: []string{"env", "team"}},
+		},
+		"RemovedKeyGone": {
+			desired: v1alpha1.ResourceMetadata{
+				Labels: map[string]*string{"env": ptr.To("prod")},
+			},
+			managed: v1alpha1.ManagedMetadata{
+				ManagedLabels:      []string{"env", "team"},
+				ManagedAnnotations: []string{"description"},
+			},
+			observed: &resource.Metadata{
+				Labels: map[string]*string{"env": ptr.To("prod")},
+			},
+			want: v1alpha1.ManagedMetadata{ManagedLabels: []string{"env"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ObserveManagedMetadata(tc.managed, tc.desired.Labels, tc.desired.Annotations, tc.observed)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ObserveManagedMetadata(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package clients
+
+import (
+	"context"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/v1beta1"
+)
+
+const (
+	// DefaultOperationTimeout bounds an operation on an external resource if
+	// neither the managed resource, its ProviderConfig nor its controller set
+	// a timeout.
+	DefaultOperationTimeout = time.Minute
+
+	// MaxOperationTimeout is the reconcile timeout of the controllers, which
+	// caps every operation timeout.
+	MaxOperationTimeout = 30 * time.Minute
+
+	// MinPollInterval is the shortest poll interval a ProviderConfig can set,
+	// so that a small value does not flood the CF API.
+	MinPollInterval = 10 * time.Second
+
+	// pollIntervalHookTimeout bounds the lookup of the ProviderConfig when
+	// computing the poll interval of a managed resource.
+	pollIntervalHookTimeout = 5 * time.Second
+)
+
+// OperationOptions are the defaults a ProviderConfig sets for the managed
+// resources using it. Zero values mean unset.
+type OperationOptions struct {
+	PollInterval     time.Duration
+	OperationTimeout time.Duration
+}
+
+// OperationTimeoutOverrider is implemented by managed resources that can
+// override the operation timeout of their ProviderConfig.
+type OperationTimeoutOverrider interface {
+	// GetOperationTimeout returns the operation timeout of the managed
+	// resource, or nil if it does not set one.
+	GetOperationTimeout() *time.Duration
+}
+
+// GetOperationOptions returns the OperationOptions of the ProviderConfig of
+// the given managed resource.
+func GetOperationOptions(ctx context.Context, client client.Client, mg resource.Managed) (OperationOptions, error) {
+	pc, err := getProviderConfig(ctx, client, mg)
+	if err != nil {
+		return OperationOptions{}, errors.Wrap(err, errGetProviderConfig)
+	}
+	return operationOptions(pc), nil
+}
+
+func operationOptions(pc *v1beta1.ProviderConfig) OperationOptions {
+	o := OperationOptions{}
+	if pc.Spec.PollInterval != nil {
+		o.PollInterval = pc.Spec.PollInterval.Duration
+	}
+	if pc.Spec.OperationTimeout != nil {
+		o.OperationTimeout = pc.Spec.OperationTimeout.Duration
+	}
+	return o
+}
+
+// TimeoutFor returns the timeout of an operation on the external
+// resource of mg. The timeout of mg takes precedence over the one of the
+// ProviderConfig, which takes precedence over the given default. The result
+// is capped at MaxOperationTimeout.
+func (o OperationOptions) TimeoutFor(mg resource.Managed, def time.Duration) time.Duration {
+	timeout := def
+	if o.OperationTimeout > 0 {
+		timeout = o.OperationTimeout
+	}
+	if ov, ok := mg.(OperationTimeoutOverrider); ok {
+		if d := ov.GetOperationTimeout(); d != nil && *d > 0 {
+			timeout = *d
+		}
+	}
+	return min(timeout, MaxOperationTimeout)
+}
+
+// PollIntervalHook returns a managed.PollIntervalHook that polls a managed
+// resource at the poll interval of its ProviderConfig, raised to at least
+// MinPollInterval. It falls back to the poll interval of the controller if
+// the ProviderConfig does not set one or cannot be read.
+func PollIntervalHook(client client.Client) managed.PollIntervalHook {
+	return func(mg resource.Managed, pollInterval time.Duration) time.Duration {
+		ctx, cancel := context.WithTimeout(context.Background(), pollIntervalHookTimeout)
+		defer cancel()
+		o, err := GetOperationOptions(ctx, client, mg)
+		if err != nil || o.PollInterval <= 0 {
+			return pollInterval
+		}
+		return max(o.PollInterval, MinPollInterval)
+	}
+}
+
+// WithOperationTimeout wraps an ExternalConnecter so that every operation of
+// the ExternalClients it produces is bounded by the operation timeout of the
+// managed resource, see OperationOptions.TimeoutFor. The controller
+// must use MaxOperationTimeout as its reconcile timeout for timeouts longer
+// than the default of the managed reconciler to take effect.
+func WithOperationTimeout(client client.Client, c managed.ExternalConnecter, def time.Duration) managed.ExternalConnecter {
+	return managed.ExternalConnectorFn(func(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+		ec, err := c.Connect(ctx, mg)
+		if err != nil {
+			return nil, err
+		}
+		o, err := GetOperationOptions(ctx, client, mg)
+		if err != nil {
+			return nil, err
+		}
+		return &timeoutClient{ExternalClient: ec, timeout: o.TimeoutFor(mg, def)}, nil
+	})
+}
+
+// timeoutClient bounds every operation of an ExternalClient by a timeout.
+type timeoutClient struct {
+	managed.ExternalClient
+	timeout time.Duration
+}
+
+func (c *timeoutClient) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return c.ExternalClient.Observe(ctx, mg)
+}
+
+func (c *timeoutClient) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return c.ExternalClient.Create(ctx, mg)
+}
+
+func (c *timeoutClient) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return c.ExternalClient.Update(ctx, mg)
+}
+
+func (c *timeoutClient) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return c.ExternalClient.Delete(ctx, mg)
+}
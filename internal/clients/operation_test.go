@@ -0,0 +1,115 @@
+package clients
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/v1beta1"
+)
+
+// timeoutSpace is a managed resource that overrides the operation timeout.
+type timeoutSpace struct {
+	*v1alpha1.Space
+	timeout *time.Duration
+}
+
+func (s timeoutSpace) GetOperationTimeout() *time.Duration { return s.timeout }
+
+func newSpace() *v1alpha1.Space {
+	s := &v1alpha1.Space{}
+	s.SetProviderConfigReference(&xpv1.ProviderConfigReference{Name: "default"})
+	return s
+}
+
+func TestTimeoutFor(t *testing.T) {
+	override := 10 * time.Minute
+	cases := map[string]struct {
+		o    OperationOptions
+		mg   resource.Managed
+		want time.Duration
+	}{
+		"Default": {
+			o:    OperationOptions{},
+			mg:   newSpace(),
+			want: DefaultOperationTimeout,
+		},
+		"ProviderConfig": {
+			o:    OperationOptions{OperationTimeout: 3 * time.Minute},
+			mg:   newSpace(),
+			want: 3 * time.Minute,
+		},
+		"ManagedResourceTakesPrecedence": {
+			o:    OperationOptions{OperationTimeout: 3 * time.Minute},
+			mg:   timeoutSpace{Space: newSpace(), timeout: &override},
+			want: override,
+		},
+		"ManagedResourceUnset": {
+			o:    OperationOptions{OperationTimeout: 3 * time.Minute},
+			mg:   timeoutSpace{Space: newSpace()},
+			want: 3 * time.Minute,
+		},
+		"Capped": {
+			o:    OperationOptions{OperationTimeout: 2 * time.Hour},
+			mg:   newSpace(),
+			want: MaxOperationTimeout,
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			got := tc.o.TimeoutFor(tc.mg, DefaultOperationTimeout)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("TimeoutFor(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPollIntervalHook(t *testing.T) {
+	withPC := func(spec v1beta1.ProviderConfigSpec) *test.MockClient {
+		return &test.MockClient{MockGet: func(_ context.Context, _ k8s.ObjectKey, obj k8s.Object) error {
+			obj.(*v1beta1.ProviderConfig).Spec = spec
+			return nil
+		}}
+	}
+	cases := map[string]struct {
+		kube k8s.Client
+		want time.Duration
+	}{
+		"ProviderConfig": {
+			kube: withPC(v1beta1.ProviderConfigSpec{PollInterval: &metav1.Duration{Duration: 5 * time.Minute}}),
+			want: 5 * time.Minute,
+		},
+		"BelowMinimum": {
+			kube: withPC(v1beta1.ProviderConfigSpec{PollInterval: &metav1.Duration{Duration: time.Millisecond}}),
+			want: MinPollInterval,
+		},
+		"Unset": {
+			kube: withPC(v1beta1.ProviderConfigSpec{}),
+			want: time.Minute,
+		},
+		"GetFailed": {
+			kube: &test.MockClient{MockGet: test.NewMockGetFn(errors.New("boom"))},
+			want: time.Minute,
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			got := PollIntervalHook(tc.kube)(newSpace(), time.Minute)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("PollIntervalHook(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
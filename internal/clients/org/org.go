@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
 
 	"github.com/cloudfoundry/go-cfclient/v3/client"
 	"github.com/cloudfoundry/go-cfclient/v3/resource"
@@ -17,6 +18,13 @@ type Client interface {
 	Get(context.Context, string) (*resource.Organization, error)
 	Single(context.Context, *client.OrganizationListOptions) (*resource.Organization, error)
 	Create(context.Context, *resource.OrganizationCreate) (*resource.Organization, error)
+	Update(context.Context, string, *resource.OrganizationUpdate) (*resource.Organization, error)
+	Delete(context.Context, string) (string, error)
+}
+
+// QuotaClient is the interface that defines the methods to apply an organization quota to an Org.
+type QuotaClient interface {
+	Apply(ctx context.Context, guid string, organizationGUIDs []string) ([]string, error)
 }
 
 // Resource is the type that implements the resource.Resource interface for a Org.
@@ -27,6 +35,11 @@ func NewClient(cf *client.Client) Client {
 	return cf.Organizations
 }
 
+// NewQuotaClient creates a new client to apply organization quotas.
+func NewQuotaClient(cf *client.Client) QuotaClient {
+	return cf.OrganizationQuotas
+}
+
 // GetByIDOrName returns an organization by ID or Name.
 func GetByIDOrName(ctx context.Context, c Client, id, name string) (*resource.Organization, error) {
 
@@ -40,20 +53,29 @@ func GetByIDOrName(ctx context.Context, c Client, id, name string) (*resource.Or
 
 // GenerateCreate generates the OrganizationCreate from an *OrgParameters
 func GenerateCreate(spec v1alpha1.OrgParameters) *resource.OrganizationCreate {
-	// if external-name is not set, search by Name and Space
 	create := &resource.OrganizationCreate{}
 	create.Name = spec.Name
 	create.Suspended = spec.Suspended
-
-	// TODO: ADD labels and annotations
+	create.Metadata = clients.NewMetadata(spec.Labels, spec.Annotations)
 
 	return create
 }
 
+// GenerateUpdate generates the OrganizationUpdate from an *OrgParameters. Managed
+// labels and annotations in `observed` that are no longer in the spec are removed.
+func GenerateUpdate(spec v1alpha1.OrgParameters, managed v1alpha1.ManagedMetadata, observed *resource.Metadata) *resource.OrganizationUpdate {
+	return &resource.OrganizationUpdate{
+		Name:      spec.Name,
+		Suspended: spec.Suspended,
+		Metadata:  clients.MetadataPatch(spec.Labels, spec.Annotations, managed, observed),
+	}
+}
+
 // GenerateObservation takes an Organization resource and returns *OrgObservation.
 func GenerateObservation(o *resource.Organization) v1alpha1.OrgObservation {
 	obs := v1alpha1.OrgObservation{
 		ID:        ptr.To(o.GUID),
+		Name:      ptr.To(o.Name),
 		CreatedAt: ptr.To(o.CreatedAt.Format(time.RFC3339)),
 		UpdatedAt: ptr.To(o.UpdatedAt.Format(time.RFC3339)),
 		Suspended: ptr.To(o.Suspended),
@@ -71,22 +93,40 @@ func GenerateObservation(o *resource.Organization) v1alpha1.OrgObservation {
 }
 
 // LateInitialize fills the unassigned fields with values from a Organization resource.
-func LateInitialize(spec *v1alpha1.OrgParameters, from *resource.Organization) {
+// It returns true if any field has been late-initialized. The quota is not
+// late-initialized: a late-initialized quota would make the Organization
+// revert every OrgQuota applied to the org afterwards, and the OrgQuota
+// reapply itself, so the quota of an org without one in its spec is owned
+// by the OrgQuotas that list it.
+func LateInitialize(spec *v1alpha1.OrgParameters, from *resource.Organization) bool {
+	lateInitialized := false
 
 	if spec.Name == "" {
 		spec.Name = from.Name
+		lateInitialized = true
 	}
 
 	if spec.Suspended == nil {
 		spec.Suspended = ptr.To(from.Suspended)
+		lateInitialized = true
 	}
-	// TODO: ADD labels and annotations
+
+	return lateInitialized
 }
 
 // IsUpToDate checks whether current state is up-to-date compared to the given
-// set of parameters.
-func IsUpToDate(spec v1alpha1.OrgParameters, observed *resource.Organization) bool {
-	// return always true, as for now the Org resource is observe only
+// set of parameters and managed labels and annotations.
+func IsUpToDate(spec v1alpha1.OrgParameters, managed v1alpha1.ManagedMetadata, observed *resource.Organization) bool {
+	return spec.Name == observed.Name &&
+		ptr.Deref(spec.Suspended, observed.Suspended) == observed.Suspended &&
+		IsQuotaUpToDate(spec, observed) &&
+		clients.MetadataEqual(spec.Labels, spec.Annotations, managed, observed.Metadata)
+}
 
-	return spec.Name == observed.Name
+// IsQuotaUpToDate checks whether the specified quota is applied to the organization.
+func IsQuotaUpToDate(spec v1alpha1.OrgParameters, observed *resource.Organization) bool {
+	if spec.Quota == nil {
+		return true
+	}
+	return observed.Relationships.Quota.Data != nil && observed.Relationships.Quota.Data.GUID == *spec.Quota
 }
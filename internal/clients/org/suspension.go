@@ -0,0 +1,81 @@
+package org
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+)
+
+// TypeSuspensionWarning is the condition type that warns about running apps in a suspended Org.
+const TypeSuspensionWarning xpv1.ConditionType = "SuspensionWarning"
+
+// Reasons of the SuspensionWarning condition.
+const (
+	ReasonRunningApps xpv1.ConditionReason = "RunningApps"
+	ReasonActive      xpv1.ConditionReason = "Active"
+)
+
+// AppClient is the interface that defines the methods to list the apps of an Org.
+type AppClient interface {
+	ListAll(ctx context.Context, opts *client.AppListOptions) ([]*resource.App, error)
+}
+
+// NewAppClient creates a new client to list the apps of an Org.
+func NewAppClient(cf *client.Client) AppClient {
+	return cf.Applications
+}
+
+// IsSuspending returns true if the spec suspends an Org that is observed as active.
+func IsSuspending(spec v1alpha1.OrgParameters, observed v1alpha1.OrgObservation) bool {
+	return ptr.Deref(spec.Suspended, false) && !ptr.Deref(observed.Suspended, false)
+}
+
+// CountRunningApps returns the number of started apps in an Org.
+func CountRunningApps(ctx context.Context, c AppClient, guid string) (int, error) {
+	opts := client.NewAppListOptions()
+	opts.OrganizationGUIDs.EqualTo(guid)
+
+	apps, err := c.ListAll(ctx, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	running := 0
+	for _, app := range apps {
+		if app.State == "STARTED" {
+			running++
+		}
+	}
+	return running, nil
+}
+
+// SuspendedWithRunningApps returns a condition that warns that an Org was
+// suspended while some of its apps were running. Cloud Foundry keeps these
+// apps running, but they can no longer be changed until the Org is active.
+func SuspendedWithRunningApps(running int) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeSuspensionWarning,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRunningApps,
+		Message:            fmt.Sprintf("organization suspended with %d running apps, they keep running but cannot be changed", running),
+	}
+}
+
+// Active returns a condition that clears the warning once an Org is no longer suspended.
+func Active() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeSuspensionWarning,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonActive,
+	}
+}
@@ -3,79 +3,192 @@ package orgquota
 import (
 	"context"
 	"log/slog"
-	"maps"
+	"slices"
 	"time"
 
 	"github.com/cloudfoundry/go-cfclient/v3/client"
 	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/pkg/errors"
 	"k8s.io/utils/ptr"
 
 	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
 )
 
 // OrgQuota is the interface that defines the methods that a OrgQuota
 // client should implement.
 type OrgQuota interface {
 	Get(ctx context.Context, guid string) (*resource.OrganizationQuota, error)
+	Single(ctx context.Context, opts *client.OrganizationQuotaListOptions) (*resource.OrganizationQuota, error)
+	Apply(ctx context.Context, guid string, organizationGUIDs []string) ([]string, error)
 	Create(ctx context.Context, res *resource.OrganizationQuotaCreateOrUpdate) (*resource.OrganizationQuota, error)
 	Update(ctx context.Context, guid string, r *resource.OrganizationQuotaCreateOrUpdate) (*resource.OrganizationQuota, error)
 	Delete(ctx context.Context, guid string) (string, error)
 }
 
+// defaultQuotaName is the name of the org quota that Cloud Foundry
+// applies to orgs without an explicitly assigned quota.
+const defaultQuotaName = "default"
+
 // NewClient creates a new OrgQuota client
 func NewClient(cf *client.Client) OrgQuota {
 	return cf.OrganizationQuotas
 }
 
-// GenerateCreate generates the OrgazationQuotaCreateOrUpdate from
-// OrgQuotaParameters. The float64 fields of spec with negative or nil
-// values indicate unset values.
+// GenerateCreateOrUpdate generates the OrganizationQuotaCreateOrUpdate
+// from OrgQuotaParameters. The float64 fields of spec with negative values
+// indicate unlimited values, nil values are not sent. Memory limits with
+// a unit of measurement are sent in MB.
 //
 //nolint:gocyclo
 func GenerateCreateOrUpdate(spec v1alpha1.OrgQuotaParameters) *resource.OrganizationQuotaCreateOrUpdate {
 	name := ptr.Deref(spec.Name, "")
 	createOrUpdate := resource.NewOrganizationQuotaCreate(name)
-	if v := spec.AllowPaidServicePlans; v != nil {
-		createOrUpdate = createOrUpdate.WithPaidServicesAllowed(*v)
+	instanceMemory, totalMemory := memoryLimits(spec)
+	if instanceMemory != nil || spec.TotalAppInstances != nil || spec.TotalAppLogRateLimit != nil ||
+		spec.TotalAppTasks != nil || totalMemory != nil {
+		createOrUpdate.Apps = &resource.AppsQuota{
+			PerProcessMemoryInMB:         limit(instanceMemory),
+			TotalInstances:               limit(spec.TotalAppInstances),
+			LogRateLimitInBytesPerSecond: limit(spec.TotalAppLogRateLimit),
+			PerAppTasks:                  limit(spec.TotalAppTasks),
+			TotalMemoryInMB:              limit(totalMemory),
+		}
 	}
-	if v := spec.InstanceMemory; v != nil && *v >= 0.0 {
-		createOrUpdate = createOrUpdate.WithPerProcessMemoryInMB(int(*v))
+	if spec.AllowPaidServicePlans != nil || spec.TotalServiceKeys != nil || spec.TotalServices != nil {
+		createOrUpdate.Services = &resource.ServicesQuota{
+			PaidServicesAllowed:   ptr.Deref(spec.AllowPaidServicePlans, false),
+			TotalServiceKeys:      limit(spec.TotalServiceKeys),
+			TotalServiceInstances: limit(spec.TotalServices),
+		}
 	}
-	if v := spec.TotalAppInstances; v != nil && *v >= 0.0 {
-		createOrUpdate = createOrUpdate.WithTotalInstances(int(*v))
+	if spec.TotalRoutePorts != nil || spec.TotalRoutes != nil {
+		createOrUpdate.Routes = &resource.RoutesQuota{
+			TotalReservedPorts: limit(spec.TotalRoutePorts),
+			TotalRoutes:        limit(spec.TotalRoutes),
+		}
 	}
-	if v := spec.TotalAppLogRateLimit; v != nil && *v >= 0.0 {
-		createOrUpdate = createOrUpdate.WithLogRateLimitInBytesPerSecond(int(*v))
+	if spec.TotalPrivateDomains != nil {
+		createOrUpdate.Domains = &resource.DomainsQuota{
+			TotalDomains: limit(spec.TotalPrivateDomains),
+		}
 	}
-	if v := spec.TotalAppTasks; v != nil && *v >= 0.0 {
-		createOrUpdate = createOrUpdate.WithPerAppTasks(int(*v))
+	orgs := make([]string, 0, len(spec.Orgs))
+	for _, org := range OrgGUIDs(spec.Orgs) {
+		orgs = append(orgs, *org)
 	}
-	if v := spec.TotalMemory; v != nil && *v >= 0.0 {
-		createOrUpdate = createOrUpdate.WithAppsTotalMemoryInMB(int(*v))
+	createOrUpdate.WithOrganizations(orgs...)
+	return createOrUpdate
+}
+
+// GenerateUpdate generates the OrganizationQuotaCreateOrUpdate to update
+// the limits of an org quota. The orgs of an org quota cannot be updated
+// this way, they are applied by ReconcileOrgs.
+func GenerateUpdate(spec v1alpha1.OrgQuotaParameters) *resource.OrganizationQuotaCreateOrUpdate {
+	update := GenerateCreateOrUpdate(spec)
+	update.Relationships = nil
+	return update
+}
+
+// OrgGUIDs function returns the GUIDs of the resolved org references.
+// References that are not resolved yet are skipped.
+func OrgGUIDs(orgs []v1alpha1.OrgReference) []*string {
+	guids := make([]*string, 0, len(orgs))
+	for _, org := range orgs {
+		if org.Org != nil {
+			guids = append(guids, org.Org)
+		}
 	}
-	if v := spec.TotalPrivateDomains; v != nil && *v >= 0.0 {
-		createOrUpdate = createOrUpdate.WithDomains(int(*v))
+	return guids
+}
+
+// OrgsResolved reports whether all org references are resolved. Until
+// then, the orgs that are no longer desired are unknown.
+func OrgsResolved(orgs []v1alpha1.OrgReference) bool {
+	return !slices.ContainsFunc(orgs, func(org v1alpha1.OrgReference) bool { return org.Org == nil })
+}
+
+// ReconcileOrgs applies the org quota to the desired orgs that are not
+// observed yet. Cloud Foundry cannot remove an org quota from an org,
+// hence the observed orgs that are no longer desired are assigned the
+// default org quota instead. No org is detached while an org reference
+// is not resolved, as it may refer to an observed org.
+func ReconcileOrgs(ctx context.Context, c OrgQuota, guid string, orgs []v1alpha1.OrgReference, observed []*string) error {
+	desired := OrgGUIDs(orgs)
+	if toApply := orgsDifference(desired, observed); len(toApply) > 0 {
+		if _, err := c.Apply(ctx, guid, toApply); err != nil {
+			return errors.Wrap(err, "cannot apply org quota to orgs")
+		}
 	}
-	if v := spec.TotalRoutePorts; v != nil && *v >= 0.0 {
-		createOrUpdate = createOrUpdate.WithTotalReservedPorts(int(*v))
+	if !OrgsResolved(orgs) {
+		return nil
 	}
-	if v := spec.TotalRoutes; v != nil && *v >= 0.0 {
-		createOrUpdate = createOrUpdate.WithTotalRoutes(int(*v))
+	return detachOrgs(ctx, c, orgsDifference(observed, desired))
+}
+
+// DetachOrgs assigns the default org quota to the given orgs, so that the
+// org quota they are applied to can be deleted. Cloud Foundry cannot
+// remove an org quota from an org.
+func DetachOrgs(ctx context.Context, c OrgQuota, observed []*string) error {
+	return detachOrgs(ctx, c, orgsDifference(observed, nil))
+}
+
+// detachOrgs assigns the default org quota to the given orgs.
+func detachOrgs(ctx context.Context, c OrgQuota, orgs []string) error {
+	if len(orgs) == 0 {
+		return nil
 	}
-	if v := spec.TotalServiceKeys; v != nil && *v >= 0.0 {
-		createOrUpdate = createOrUpdate.WithTotalServiceKeys(int(*v))
+	defaultQuota, err := c.Single(ctx, &client.OrganizationQuotaListOptions{Names: client.Filter{Values: []string{defaultQuotaName}}})
+	if err != nil {
+		return errors.Wrap(err, "cannot get default org quota")
 	}
-	if v := spec.TotalServices; v != nil && *v >= 0.0 {
-		createOrUpdate = createOrUpdate.WithTotalServiceInstances(int(*v))
+	if _, err := c.Apply(ctx, defaultQuota.GUID, orgs); err != nil {
+		return errors.Wrap(err, "cannot apply default org quota to orgs")
 	}
-	orgs := make([]string, 0, len(spec.Orgs))
-	for _, org := range spec.Orgs {
-		if org != nil {
-			orgs = append(orgs, *org)
+	return nil
+}
+
+// orgsDifference function returns the non-nil values of orgs1 that
+// are not contained in orgs2.
+func orgsDifference(orgs1, orgs2 []*string) []string {
+	result := []string{}
+	for _, org := range orgs1 {
+		if org == nil || slices.Contains(result, *org) ||
+			slices.ContainsFunc(orgs2, func(o *string) bool { return ptr.Equal(o, org) }) {
+			continue
 		}
+		result = append(result, *org)
 	}
-	createOrUpdate.WithOrganizations(orgs...)
-	return createOrUpdate
+	return result
+}
+
+// memoryLimits function returns the instance and total memory limits
+// of the spec in MB, given either in MB or with a unit of measurement.
+func memoryLimits(spec v1alpha1.OrgQuotaParameters) (instanceMemory, totalMemory *float64) {
+	return clients.MemoryLimitInMB(spec.InstanceMemory, spec.InstanceMemoryWithUnit),
+		clients.MemoryLimitInMB(spec.TotalMemory, spec.TotalMemoryWithUnit)
+}
+
+// limit function turns a quota limit of the spec into the value sent
+// to Cloud Foundry. Nil and negative values mean unlimited.
+func limit(v *float64) *int {
+	if v == nil || *v < 0 {
+		return nil
+	}
+	return ptr.To(int(*v))
+}
+
+// limitEqual function compares a quota limit of the spec with the
+// observed one. A nil spec value is not managed and a negative spec
+// value matches an unlimited (nil) observed value.
+func limitEqual(spec, observed *float64) bool {
+	if spec == nil {
+		return true
+	}
+	if *spec < 0 {
+		return observed == nil
+	}
+	return observed != nil && int(*spec) == int(*observed)
 }
 
 // intpToFloatp function takes an *int value and turns it into a
@@ -115,35 +228,19 @@ func GenerateObservation(o *resource.OrganizationQuota) v1alpha1.OrgQuotaObserva
 	return obs
 }
 
-// orgsEqual compares two *string slices. The nil values of
-// the slices are ignored. The order of the values in the two slices
+// orgsContained reports whether all non-nil values of orgs1 are
+// contained in orgs2. The order of the values in the two slices
 // are indifferent.
-//
-// The two slices are equal if they contain the same values.
-func orgsEqual(orgs1, orgs2 []*string) bool {
-	// orgSet1 is a set that contains non-nil strings of org1
-	orgSet1 := map[string]interface{}{}
-	for _, org := range orgs1 {
-		if org != nil {
-			if _, ok := orgSet1[*org]; ok {
-				// org name is listed twice
-				continue
-			}
-			orgSet1[*org] = struct{}{}
-		}
-	}
-	// orgSet2 is a set that contains non-nil strings of org1
-	orgSet2 := map[string]interface{}{}
-	for _, org := range orgs2 {
-		if org != nil {
-			if _, ok := orgSet2[*org]; ok {
-				// org name is listed twice
-				continue
-			}
-			orgSet2[*org] = struct{}{}
-		}
-	}
-	return maps.Equal(orgSet1, orgSet2)
+func orgsContained(orgs1, orgs2 []*string) bool {
+	return len(orgsDifference(orgs1, orgs2)) == 0
+}
+
+// orgsUpToDate reports whether the org quota is applied to exactly the
+// orgs of the spec. While an org reference is not resolved, only the
+// resolved orgs are required to be applied.
+func orgsUpToDate(orgs []v1alpha1.OrgReference, observed []*string) bool {
+	desired := OrgGUIDs(orgs)
+	return orgsContained(desired, observed) && (!OrgsResolved(orgs) || orgsContained(observed, desired))
 }
 
 // NeedsReconciliation function investigates a managed OrgQuota
@@ -152,19 +249,21 @@ func orgsEqual(orgs1, orgs2 []*string) bool {
 //
 //nolint:gocyclo
 func NeedsReconciliation(orgQuota *v1alpha1.OrgQuota) bool {
-	if ptr.Deref(orgQuota.Spec.ForProvider.Name, "") != ptr.Deref(orgQuota.Status.AtProvider.Name, "") ||
-		!ptr.Equal(orgQuota.Spec.ForProvider.AllowPaidServicePlans, orgQuota.Status.AtProvider.AllowPaidServicePlans) ||
-		!ptr.Equal(orgQuota.Spec.ForProvider.InstanceMemory, orgQuota.Status.AtProvider.InstanceMemory) ||
-		!ptr.Equal(orgQuota.Spec.ForProvider.TotalAppInstances, orgQuota.Status.AtProvider.TotalAppInstances) ||
-		!ptr.Equal(orgQuota.Spec.ForProvider.TotalAppLogRateLimit, orgQuota.Status.AtProvider.TotalAppLogRateLimit) ||
-		!ptr.Equal(orgQuota.Spec.ForProvider.TotalAppTasks, orgQuota.Status.AtProvider.TotalAppTasks) ||
-		!ptr.Equal(orgQuota.Spec.ForProvider.TotalMemory, orgQuota.Status.AtProvider.TotalMemory) ||
-		!ptr.Equal(orgQuota.Spec.ForProvider.TotalPrivateDomains, orgQuota.Status.AtProvider.TotalPrivateDomains) ||
-		!ptr.Equal(orgQuota.Spec.ForProvider.TotalRoutePorts, orgQuota.Status.AtProvider.TotalRoutePorts) ||
-		!ptr.Equal(orgQuota.Spec.ForProvider.TotalRoutes, orgQuota.Status.AtProvider.TotalRoutes) ||
-		!ptr.Equal(orgQuota.Spec.ForProvider.TotalServiceKeys, orgQuota.Status.AtProvider.TotalServiceKeys) ||
-		!ptr.Equal(orgQuota.Spec.ForProvider.TotalServices, orgQuota.Status.AtProvider.TotalServices) ||
-		orgsEqual(orgQuota.Spec.ForProvider.Orgs, orgQuota.Status.AtProvider.Orgs) {
+	spec, obs := orgQuota.Spec.ForProvider, orgQuota.Status.AtProvider
+	instanceMemory, totalMemory := memoryLimits(spec)
+	if ptr.Deref(spec.Name, "") != ptr.Deref(obs.Name, "") ||
+		(spec.AllowPaidServicePlans != nil && !ptr.Equal(spec.AllowPaidServicePlans, obs.AllowPaidServicePlans)) ||
+		!limitEqual(instanceMemory, obs.InstanceMemory) ||
+		!limitEqual(spec.TotalAppInstances, obs.TotalAppInstances) ||
+		!limitEqual(spec.TotalAppLogRateLimit, obs.TotalAppLogRateLimit) ||
+		!limitEqual(spec.TotalAppTasks, obs.TotalAppTasks) ||
+		!limitEqual(totalMemory, obs.TotalMemory) ||
+		!limitEqual(spec.TotalPrivateDomains, obs.TotalPrivateDomains) ||
+		!limitEqual(spec.TotalRoutePorts, obs.TotalRoutePorts) ||
+		!limitEqual(spec.TotalRoutes, obs.TotalRoutes) ||
+		!limitEqual(spec.TotalServiceKeys, obs.TotalServiceKeys) ||
+		!limitEqual(spec.TotalServices, obs.TotalServices) ||
+		!orgsUpToDate(spec.Orgs, obs.Orgs) {
 		return true
 	}
 	return false
@@ -199,10 +298,10 @@ func LateInitialize(spec *v1alpha1.OrgQuotaParameters, from *resource.Organizati
 		spec.Name = &from.Name
 		changed = true
 	}
-	if len(spec.Orgs) == 0 {
-		spec.Orgs = make([]*string, len(from.Relationships.Organizations.Data))
+	if len(spec.Orgs) == 0 && len(from.Relationships.Organizations.Data) > 0 {
+		spec.Orgs = make([]v1alpha1.OrgReference, len(from.Relationships.Organizations.Data))
 		for i := range from.Relationships.Organizations.Data {
-			spec.Orgs[i] = &from.Relationships.Organizations.Data[i].GUID
+			spec.Orgs[i] = v1alpha1.OrgReference{Org: ptr.To(from.Relationships.Organizations.Data[i].GUID)}
 		}
 		changed = true
 	}
@@ -210,7 +309,7 @@ func LateInitialize(spec *v1alpha1.OrgQuotaParameters, from *resource.Organizati
 		spec.AllowPaidServicePlans = ptr.To(from.Services.PaidServicesAllowed)
 		changed = true
 	}
-	if spec.InstanceMemory == nil {
+	if spec.InstanceMemory == nil && spec.InstanceMemoryWithUnit == nil {
 		spec.InstanceMemory = ptrCast[int, float64](from.Apps.PerProcessMemoryInMB, -1)
 		changed = true
 	}
@@ -219,35 +318,35 @@ func LateInitialize(spec *v1alpha1.OrgQuotaParameters, from *resource.Organizati
 		changed = true
 	}
 	if spec.TotalAppLogRateLimit == nil {
-		spec.TotalAppInstances = ptrCast[int, float64](from.Apps.LogRateLimitInBytesPerSecond, -1)
+		spec.TotalAppLogRateLimit = ptrCast[int, float64](from.Apps.LogRateLimitInBytesPerSecond, -1)
 		changed = true
 	}
 	if spec.TotalAppTasks == nil {
 		spec.TotalAppTasks = ptrCast[int, float64](from.Apps.PerAppTasks, -1)
 		changed = true
 	}
-	if spec.TotalMemory == nil {
-		spec.TotalAppTasks = ptrCast[int, float64](from.Apps.TotalMemoryInMB, -1)
+	if spec.TotalMemory == nil && spec.TotalMemoryWithUnit == nil {
+		spec.TotalMemory = ptrCast[int, float64](from.Apps.TotalMemoryInMB, -1)
 		changed = true
 	}
 	if spec.TotalPrivateDomains == nil {
-		spec.TotalAppTasks = ptrCast[int, float64](from.Domains.TotalDomains, -1)
+		spec.TotalPrivateDomains = ptrCast[int, float64](from.Domains.TotalDomains, -1)
 		changed = true
 	}
 	if spec.TotalRoutePorts == nil {
-		spec.TotalAppTasks = ptrCast[int, float64](from.Routes.TotalReservedPorts, -1)
+		spec.TotalRoutePorts = ptrCast[int, float64](from.Routes.TotalReservedPorts, -1)
 		changed = true
 	}
 	if spec.TotalRoutes == nil {
-		spec.TotalAppTasks = ptrCast[int, float64](from.Routes.TotalRoutes, -1)
+		spec.TotalRoutes = ptrCast[int, float64](from.Routes.TotalRoutes, -1)
 		changed = true
 	}
 	if spec.TotalServiceKeys == nil {
-		spec.TotalAppTasks = ptrCast[int, float64](from.Services.TotalServiceKeys, -1)
+		spec.TotalServiceKeys = ptrCast[int, float64](from.Services.TotalServiceKeys, -1)
 		changed = true
 	}
 	if spec.TotalServices == nil {
-		spec.TotalAppTasks = ptrCast[int, float64](from.Services.TotalServiceInstances, -1)
+		spec.TotalServices = ptrCast[int, float64](from.Services.TotalServiceInstances, -1)
 		changed = true
 	}
 	slog.Info("LateInitialize done", "changed", changed)
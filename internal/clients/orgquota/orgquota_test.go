@@ -1,11 +1,18 @@
 package orgquota
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
 
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/google/go-cmp/cmp"
 	"k8s.io/utils/ptr"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/fake"
 )
 
 // ptrString turns any pointer into a string. If the pointer is nil,
@@ -48,66 +55,71 @@ func orgsString(orgs []*string) string {
 	return strings.Join(orgStrings, ",")
 }
 
-func TestOrgsEqual(t *testing.T) {
+func TestOrgsContained(t *testing.T) {
 	testValues := []struct {
-		orgs1 []*string
-		orgs2 []*string
-		equal bool
+		orgs1     []*string
+		orgs2     []*string
+		contained bool
 	}{
 		{
-			orgs1: []*string{},
-			orgs2: []*string{},
-			equal: true,
+			orgs1:     []*string{},
+			orgs2:     []*string{},
+			contained: true,
+		},
+		{
+			orgs1:     []*string{nil, nil, nil},
+			orgs2:     []*string{},
+			contained: true,
 		},
 		{
-			orgs1: []*string{nil, nil, nil},
-			orgs2: []*string{},
-			equal: true,
+			orgs1:     []*string{ptr.To("org1")},
+			orgs2:     []*string{ptr.To("org1")},
+			contained: true,
 		},
 		{
-			orgs1: []*string{ptr.To("org1")},
-			orgs2: []*string{ptr.To("org1")},
-			equal: true,
+			orgs1:     []*string{ptr.To("org1"), ptr.To("org2")},
+			orgs2:     []*string{ptr.To("org1"), ptr.To("org2")},
+			contained: true,
 		},
 		{
-			orgs1: []*string{ptr.To("org1"), ptr.To("org2")},
-			orgs2: []*string{ptr.To("org1"), ptr.To("org2")},
-			equal: true,
+			orgs1:     []*string{ptr.To("org2"), ptr.To("org1")},
+			orgs2:     []*string{ptr.To("org1"), ptr.To("org2")},
+			contained: true,
 		},
 		{
-			orgs1: []*string{ptr.To("org2"), ptr.To("org1")},
-			orgs2: []*string{ptr.To("org1"), ptr.To("org2")},
-			equal: true,
+			orgs1:     []*string{ptr.To("org2"), nil, ptr.To("org1")},
+			orgs2:     []*string{ptr.To("org1"), ptr.To("org2")},
+			contained: true,
 		},
 		{
-			orgs1: []*string{ptr.To("org2"), nil, ptr.To("org1")},
-			orgs2: []*string{ptr.To("org1"), ptr.To("org2")},
-			equal: true,
+			orgs1:     []*string{ptr.To("org2")},
+			orgs2:     []*string{ptr.To("org1")},
+			contained: false,
 		},
 		{
-			orgs1: []*string{ptr.To("org2")},
-			orgs2: []*string{ptr.To("org1")},
-			equal: false,
+			orgs1:     []*string{},
+			orgs2:     []*string{ptr.To("org2")},
+			contained: true,
 		},
 		{
-			orgs1: []*string{},
-			orgs2: []*string{ptr.To("org2")},
-			equal: false,
+			orgs1:     []*string{ptr.To("org1")},
+			orgs2:     []*string{ptr.To("org1"), ptr.To("org2")},
+			contained: true,
 		},
 		{
-			orgs1: []*string{ptr.To("org1")},
-			orgs2: []*string{},
-			equal: false,
+			orgs1:     []*string{ptr.To("org1")},
+			orgs2:     []*string{},
+			contained: false,
 		},
 	}
 
 	for _, testValue := range testValues {
-		t.Logf("testing orgsEqual, orgs1: %s - orgs2: %s",
+		t.Logf("testing orgsContained, orgs1: %s - orgs2: %s",
 			orgsString(testValue.orgs1),
 			orgsString(testValue.orgs2),
 		)
-		if result := orgsEqual(testValue.orgs1, testValue.orgs2); result != testValue.equal {
-			t.Errorf("orgsEqual failed - expected: %t, got: %t", testValue.equal, result)
+		if result := orgsContained(testValue.orgs1, testValue.orgs2); result != testValue.contained {
+			t.Errorf("orgsContained failed - expected: %t, got: %t", testValue.contained, result)
 		}
 	}
 }
@@ -147,3 +159,286 @@ func TestPtrDef(t *testing.T) {
 		t.Error("ptrDef(false, true) != false")
 	}
 }
+
+func TestLimitEqual(t *testing.T) {
+	testValues := []struct {
+		spec     *float64
+		observed *float64
+		equal    bool
+	}{
+		{spec: nil, observed: ptr.To(10.0), equal: true},
+		{spec: ptr.To(-1.0), observed: nil, equal: true},
+		{spec: ptr.To(-1.0), observed: ptr.To(10.0), equal: false},
+		{spec: ptr.To(10.0), observed: ptr.To(10.0), equal: true},
+		{spec: ptr.To(10.0), observed: nil, equal: false},
+		{spec: ptr.To(0.0), observed: ptr.To(10.0), equal: false},
+	}
+
+	for _, testValue := range testValues {
+		if result := limitEqual(testValue.spec, testValue.observed); result != testValue.equal {
+			t.Errorf("limitEqual(%s, %s) - expected: %t, got: %t",
+				ptrString(testValue.spec), ptrString(testValue.observed), testValue.equal, result)
+		}
+	}
+}
+
+func TestLateInitialize(t *testing.T) {
+	from := &resource.OrganizationQuota{Name: "quota"}
+	from.Apps.TotalMemoryInMB = ptr.To(10240)
+	from.Apps.LogRateLimitInBytesPerSecond = ptr.To(512)
+	from.Routes.TotalReservedPorts = ptr.To(5)
+	from.Services.TotalServiceKeys = ptr.To(50)
+
+	spec := v1alpha1.OrgQuotaParameters{TotalRoutes: ptr.To(10.0)}
+	if !LateInitialize(&spec, from) {
+		t.Fatal("LateInitialize() = false, want true")
+	}
+
+	want := v1alpha1.OrgQuotaParameters{
+		Name:                  ptr.To("quota"),
+		AllowPaidServicePlans: ptr.To(false),
+		InstanceMemory:        ptr.To(-1.0),
+		TotalAppInstances:     ptr.To(-1.0),
+		TotalAppLogRateLimit:  ptr.To(512.0),
+		TotalAppTasks:         ptr.To(-1.0),
+		TotalMemory:           ptr.To(10240.0),
+		TotalPrivateDomains:   ptr.To(-1.0),
+		TotalRoutePorts:       ptr.To(5.0),
+		TotalRoutes:           ptr.To(10.0),
+		TotalServiceKeys:      ptr.To(50.0),
+		TotalServices:         ptr.To(-1.0),
+	}
+	if diff := cmp.Diff(want, spec); diff != "" {
+		t.Errorf("LateInitialize() -want, +got:\n%s", diff)
+	}
+
+	if LateInitialize(&spec, from) {
+		t.Error("LateInitialize() = true on a late-initialized spec, want false")
+	}
+
+	quota := &v1alpha1.OrgQuota{Spec: v1alpha1.OrgQuotaSpec{ForProvider: spec}}
+	quota.Spec.ForProvider.TotalRoutes = nil
+	quota.Status.AtProvider = GenerateObservation(from)
+	if NeedsReconciliation(quota) {
+		t.Error("NeedsReconciliation() = true for an adopted quota, want false")
+	}
+
+	quota.Spec.ForProvider.TotalMemory = ptr.To(2048.0)
+	if !NeedsReconciliation(quota) {
+		t.Error("NeedsReconciliation() = false for a changed limit, want true")
+	}
+}
+
+func TestGenerateCreateOrUpdate(t *testing.T) {
+	spec := v1alpha1.OrgQuotaParameters{
+		Name:                  ptr.To("quota"),
+		AllowPaidServicePlans: ptr.To(true),
+		TotalMemory:           ptr.To(10240.0),
+		InstanceMemory:        ptr.To(-1.0),
+		TotalRoutes:           ptr.To(100.0),
+	}
+
+	want := &resource.OrganizationQuotaCreateOrUpdate{
+		Name: ptr.To("quota"),
+		Apps: &resource.AppsQuota{
+			TotalMemoryInMB: ptr.To(10240),
+		},
+		Services: &resource.ServicesQuota{
+			PaidServicesAllowed: true,
+		},
+		Routes: &resource.RoutesQuota{
+			TotalRoutes: ptr.To(100),
+		},
+		Relationships: &resource.OrganizationQuotaRelationships{},
+	}
+	if diff := cmp.Diff(want, GenerateCreateOrUpdate(spec)); diff != "" {
+		t.Errorf("GenerateCreateOrUpdate() -want, +got:\n%s", diff)
+	}
+}
+
+func TestMemoryWithUnit(t *testing.T) {
+	spec := v1alpha1.OrgQuotaParameters{
+		Name:                   ptr.To("quota"),
+		InstanceMemoryWithUnit: ptr.To("2G"),
+		TotalMemoryWithUnit:    ptr.To("1TB"),
+	}
+
+	want := &resource.AppsQuota{
+		PerProcessMemoryInMB: ptr.To(2048),
+		TotalMemoryInMB:      ptr.To(1024 * 1024),
+	}
+	if diff := cmp.Diff(want, GenerateCreateOrUpdate(spec).Apps); diff != "" {
+		t.Errorf("GenerateCreateOrUpdate() -want apps, +got:\n%s", diff)
+	}
+
+	from := &resource.OrganizationQuota{Name: "quota"}
+	from.Apps.PerProcessMemoryInMB = ptr.To(2048)
+	from.Apps.TotalMemoryInMB = ptr.To(1024 * 1024)
+	quota := &v1alpha1.OrgQuota{Spec: v1alpha1.OrgQuotaSpec{ForProvider: spec}}
+	LateInitialize(&quota.Spec.ForProvider, from)
+	if quota.Spec.ForProvider.InstanceMemory != nil || quota.Spec.ForProvider.TotalMemory != nil {
+		t.Error("LateInitialize() set a memory limit in MB that is set with a unit")
+	}
+	quota.Status.AtProvider = GenerateObservation(from)
+	if NeedsReconciliation(quota) {
+		t.Error("NeedsReconciliation() = true for memory limits with a unit, want false")
+	}
+
+	quota.Spec.ForProvider.TotalMemoryWithUnit = ptr.To("2T")
+	if !NeedsReconciliation(quota) {
+		t.Error("NeedsReconciliation() = false for a changed memory limit with a unit, want true")
+	}
+}
+
+func TestReconcileOrgs(t *testing.T) {
+	const (
+		quotaGUID   = "33fd5b0b-4f3b-4b1b-8b3d-3b5f7b4b3b4b"
+		defaultGUID = "44fd5b0b-4f3b-4b1b-8b3d-3b5f7b4b3b4b"
+		org1        = "1d8b0d04-d537-4e4e-8c6f-f09ca0e7f56f"
+		org2        = "2d8b0d04-d537-4e4e-8c6f-f09ca0e7f56f"
+	)
+	defaultQuota := &resource.OrganizationQuota{}
+	defaultQuota.GUID = defaultGUID
+
+	tests := []struct {
+		name     string
+		desired  []v1alpha1.OrgReference
+		observed []*string
+		mock     func() *fake.MockOrgQuota
+		wantErr  bool
+	}{
+		{
+			name:     "Already applied",
+			desired:  []v1alpha1.OrgReference{{Org: ptr.To(org1)}},
+			observed: []*string{ptr.To(org1)},
+			mock:     func() *fake.MockOrgQuota { return &fake.MockOrgQuota{} },
+		},
+		{
+			name:     "Apply missing and detach removed orgs",
+			desired:  []v1alpha1.OrgReference{{Org: ptr.To(org2)}},
+			observed: []*string{ptr.To(org1)},
+			mock: func() *fake.MockOrgQuota {
+				m := &fake.MockOrgQuota{}
+				m.On("Apply", quotaGUID, []string{org2}).Return(nil)
+				m.On("Single", []string{defaultQuotaName}).Return(defaultQuota, nil)
+				m.On("Apply", defaultGUID, []string{org1}).Return(nil)
+				return m
+			},
+		},
+		{
+			name:     "Keep orgs while a reference is unresolved",
+			desired:  []v1alpha1.OrgReference{{Org: ptr.To(org2)}, {OrgName: ptr.To("org")}},
+			observed: []*string{ptr.To(org1), ptr.To(org2)},
+			mock:     func() *fake.MockOrgQuota { return &fake.MockOrgQuota{} },
+		},
+		{
+			name:     "Apply error",
+			desired:  []v1alpha1.OrgReference{{Org: ptr.To(org1)}},
+			observed: nil,
+			mock: func() *fake.MockOrgQuota {
+				m := &fake.MockOrgQuota{}
+				m.On("Apply", quotaGUID, []string{org1}).Return(errors.New("boom"))
+				return m
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.mock()
+			err := ReconcileOrgs(context.Background(), m, quotaGUID, tt.desired, tt.observed)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReconcileOrgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOrgsUpToDate(t *testing.T) {
+	cases := map[string]struct {
+		desired  []v1alpha1.OrgReference
+		observed []*string
+		want     bool
+	}{
+		"Equal": {
+			desired:  []v1alpha1.OrgReference{{Org: ptr.To("org1")}},
+			observed: []*string{ptr.To("org1")},
+			want:     true,
+		},
+		"Missing": {
+			desired:  []v1alpha1.OrgReference{{Org: ptr.To("org1")}, {Org: ptr.To("org2")}},
+			observed: []*string{ptr.To("org1")},
+		},
+		"Removed": {
+			desired:  []v1alpha1.OrgReference{{Org: ptr.To("org1")}},
+			observed: []*string{ptr.To("org1"), ptr.To("org2")},
+		},
+		"Unresolved": {
+			desired:  []v1alpha1.OrgReference{{Org: ptr.To("org1")}, {OrgName: ptr.To("org")}},
+			observed: []*string{ptr.To("org1"), ptr.To("org2")},
+			want:     true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := orgsUpToDate(tc.desired, tc.observed); got != tc.want {
+				t.Errorf("orgsUpToDate(...) = %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDetachOrgs(t *testing.T) {
+	const (
+		defaultGUID = "44fd5b0b-4f3b-4b1b-8b3d-3b5f7b4b3b4b"
+		org1        = "1d8b0d04-d537-4e4e-8c6f-f09ca0e7f56f"
+	)
+	defaultQuota := &resource.OrganizationQuota{}
+	defaultQuota.GUID = defaultGUID
+
+	tests := []struct {
+		name     string
+		observed []*string
+		mock     func() *fake.MockOrgQuota
+		wantErr  bool
+	}{
+		{
+			name:     "No orgs",
+			observed: nil,
+			mock:     func() *fake.MockOrgQuota { return &fake.MockOrgQuota{} },
+		},
+		{
+			name:     "Assign default quota",
+			observed: []*string{ptr.To(org1)},
+			mock: func() *fake.MockOrgQuota {
+				m := &fake.MockOrgQuota{}
+				m.On("Single", []string{defaultQuotaName}).Return(defaultQuota, nil)
+				m.On("Apply", defaultGUID, []string{org1}).Return(nil)
+				return m
+			},
+		},
+		{
+			name:     "Default quota not found",
+			observed: []*string{ptr.To(org1)},
+			mock: func() *fake.MockOrgQuota {
+				m := &fake.MockOrgQuota{}
+				m.On("Single", []string{defaultQuotaName}).Return((*resource.OrganizationQuota)(nil), errors.New("boom"))
+				return m
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.mock()
+			err := DetachOrgs(context.Background(), m, tt.observed)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DetachOrgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package clients
+
+import (
+	"context"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	xpresource "github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AnnotationKeyPaused is the annotation that makes a controller observe a
+// resource without creating, updating or deleting it in Cloud Foundry.
+const AnnotationKeyPaused = "cloudfoundry.crossplane.io/paused"
+
+// TypeReconcilePaused is the type of the condition that indicates the
+// resource is observed only because it is paused.
+const TypeReconcilePaused xpv1.ConditionType = "ReconcilePaused"
+
+const (
+	// ReasonPaused is the reason of the ReconcilePaused condition of a
+	// paused resource.
+	ReasonPaused xpv1.ConditionReason = "PausedByAnnotation"
+
+	// ReasonResumed is the reason of the ReconcilePaused condition of a
+	// resource that was paused before.
+	ReasonResumed xpv1.ConditionReason = "Resumed"
+)
+
+// IsPaused returns true if the paused annotation of the object is set to "true".
+func IsPaused(o metav1.Object) bool {
+	return o.GetAnnotations()[AnnotationKeyPaused] == "true"
+}
+
+// ReconcilePaused returns a condition that indicates the resource is paused.
+func ReconcilePaused() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeReconcilePaused,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPaused,
+		Message:            "remove the " + AnnotationKeyPaused + " annotation to resume creating, updating and deleting the resource",
+	}
+}
+
+// ReconcileResumed returns a condition that indicates the resource is no
+// longer paused.
+func ReconcileResumed() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeReconcilePaused,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonResumed,
+	}
+}
+
+// readOnlyKey is the context key of the read-only flag of an observation.
+type readOnlyKey struct{}
+
+// IsReadOnly returns true if the observation of ctx must neither change the
+// resource in Cloud Foundry nor write the managed resource, because the
+// resource is paused.
+func IsReadOnly(ctx context.Context) bool {
+	ro, _ := ctx.Value(readOnlyKey{}).(bool)
+	return ro
+}
+
+// WithPause wraps an ExternalConnecter so that the ExternalClients it
+// produces only observe resources with the paused annotation. A paused
+// resource is observed with a read-only context, see IsReadOnly, and its
+// observation reports it as existing and up to date, so that the managed
+// reconciler neither creates nor updates it, and Create, Update and Delete
+// do nothing. A deleted paused resource keeps its finalizer until it is
+// resumed.
+func WithPause(c managed.ExternalConnecter) managed.ExternalConnecter {
+	return managed.ExternalConnectorFn(func(ctx context.Context, mg xpresource.Managed) (managed.ExternalClient, error) {
+		ec, err := c.Connect(ctx, mg)
+		if err != nil {
+			return nil, err
+		}
+		return &pausingClient{ExternalClient: ec}, nil
+	})
+}
+
+// pausingClient guards every operation of an ExternalClient against paused resources.
+type pausingClient struct {
+	managed.ExternalClient
+}
+
+func (c *pausingClient) Observe(ctx context.Context, mg xpresource.Managed) (managed.ExternalObservation, error) {
+	if !IsPaused(mg) {
+		if mg.GetCondition(TypeReconcilePaused).Status == corev1.ConditionTrue {
+			mg.SetConditions(ReconcileResumed())
+		}
+		return c.ExternalClient.Observe(ctx, mg)
+	}
+	mg.SetConditions(ReconcilePaused())
+	o, err := c.ExternalClient.Observe(context.WithValue(ctx, readOnlyKey{}, true), mg)
+	if err != nil {
+		return o, err
+	}
+	return managed.ExternalObservation{
+		ResourceExists:    true,
+		ResourceUpToDate:  true,
+		ConnectionDetails: o.ConnectionDetails,
+	}, nil
+}
+
+func (c *pausingClient) Create(ctx context.Context, mg xpresource.Managed) (managed.ExternalCreation, error) {
+	if IsPaused(mg) {
+		return managed.ExternalCreation{}, nil
+	}
+	return c.ExternalClient.Create(ctx, mg)
+}
+
+func (c *pausingClient) Update(ctx context.Context, mg xpresource.Managed) (managed.ExternalUpdate, error) {
+	if IsPaused(mg) {
+		return managed.ExternalUpdate{}, nil
+	}
+	return c.ExternalClient.Update(ctx, mg)
+}
+
+func (c *pausingClient) Delete(ctx context.Context, mg xpresource.Managed) (managed.ExternalDelete, error) {
+	if IsPaused(mg) {
+		return managed.ExternalDelete{}, nil
+	}
+	return c.ExternalClient.Delete(ctx, mg)
+}
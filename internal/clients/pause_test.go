@@ -0,0 +1,110 @@
+package clients
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	xpresource "github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWithPause(t *testing.T) {
+	cases := map[string]struct {
+		paused    bool
+		condition xpv1.Condition
+		obs       managed.ExternalObservation
+		want      managed.ExternalObservation
+		wantCond  xpv1.Condition
+		wantCalls int
+	}{
+		"NotPaused": {
+			obs:       managed.ExternalObservation{ResourceExists: false},
+			want:      managed.ExternalObservation{ResourceExists: false},
+			wantCalls: 3,
+		},
+		"PausedNotCreated": {
+			paused:   true,
+			obs:      managed.ExternalObservation{ResourceExists: false},
+			want:     managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			wantCond: ReconcilePaused(),
+		},
+		"PausedDrifted": {
+			paused:   true,
+			obs:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false, ResourceLateInitialized: true},
+			want:     managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			wantCond: ReconcilePaused(),
+		},
+		"Resumed": {
+			condition: ReconcilePaused(),
+			obs:       managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			want:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			wantCond:  ReconcileResumed(),
+			wantCalls: 3,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			readOnly := false
+			ec := &managed.ExternalClientFns{
+				ObserveFn: func(ctx context.Context, _ xpresource.Managed) (managed.ExternalObservation, error) {
+					readOnly = IsReadOnly(ctx)
+					return tc.obs, nil
+				},
+				CreateFn: func(context.Context, xpresource.Managed) (managed.ExternalCreation, error) {
+					calls++
+					return managed.ExternalCreation{}, nil
+				},
+				UpdateFn: func(context.Context, xpresource.Managed) (managed.ExternalUpdate, error) {
+					calls++
+					return managed.ExternalUpdate{}, nil
+				},
+				DeleteFn: func(context.Context, xpresource.Managed) (managed.ExternalDelete, error) {
+					calls++
+					return managed.ExternalDelete{}, nil
+				},
+			}
+			mg := &fake.Managed{}
+			if tc.paused {
+				mg.SetAnnotations(map[string]string{AnnotationKeyPaused: "true"})
+			}
+			if tc.condition.Type != "" {
+				mg.SetConditions(tc.condition)
+			}
+
+			c, err := WithPause(managed.ExternalConnectorFn(func(context.Context, xpresource.Managed) (managed.ExternalClient, error) {
+				return ec, nil
+			})).Connect(context.Background(), mg)
+			if err != nil {
+				t.Fatalf("Connect(...): unexpected error: %v", err)
+			}
+			obs, err := c.Observe(context.Background(), mg)
+			if err != nil {
+				t.Fatalf("Observe(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, obs); diff != "" {
+				t.Errorf("Observe(...): -want, +got:\n%s", diff)
+			}
+			if readOnly != tc.paused {
+				t.Errorf("Observe(...): read-only: want %t, got %t", tc.paused, readOnly)
+			}
+			if tc.wantCond.Type != "" {
+				if diff := cmp.Diff(tc.wantCond, mg.GetCondition(TypeReconcilePaused), test.EquateConditions()); diff != "" {
+					t.Errorf("Observe(...): -want condition, +got:\n%s", diff)
+				}
+			}
+
+			_, _ = c.Create(context.Background(), mg)
+			_, _ = c.Update(context.Background(), mg)
+			_, _ = c.Delete(context.Background(), mg)
+			if calls != tc.wantCalls {
+				t.Errorf("Create, Update and Delete: want %d calls, got %d", tc.wantCalls, calls)
+			}
+		})
+	}
+}
@@ -7,14 +7,17 @@ package clients
 import (
 	"context"
 	"encoding/json"
+	"slices"
 
 	cfv3 "github.com/cloudfoundry/go-cfclient/v3/client"
 	"github.com/cloudfoundry/go-cfclient/v3/config"
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
 	"github.com/SAP/crossplane-provider-cloudfoundry/apis/v1beta1"
 )
 
@@ -38,8 +41,15 @@ const (
 	errUnmarshalCredentials = "cannot unmarshal cloudfoundry credentials as JSON"
 	errUnmarshalEndpoint    = "cannot unmarshal cloudfoundry endpoint as JSON"
 	errNoEndpoint           = "no API endpoint is configured in ProviderConfig"
+	errEndpointNotAllowed   = "API endpoint %s of the managed resource is not allowed by its ProviderConfig"
 )
 
+// AnnotationKeyAPIEndpoint is the annotation that makes a managed resource
+// target another CF API than the one of its ProviderConfig. The API must be
+// one of the allowed API endpoints of the ProviderConfig, whose credentials
+// are used to authenticate with it.
+const AnnotationKeyAPIEndpoint = "cloudfoundry.crossplane.io/api-endpoint"
+
 // GetCredentialConfig returns a config.Config for the given managed resource
 func GetCredentialConfig(ctx context.Context, client client.Client, mg resource.Managed) (*config.Config, error) {
 	pc, err := getProviderConfig(ctx, client, mg)
@@ -55,17 +65,43 @@ func GetCredentialConfig(ctx context.Context, client client.Client, mg resource.
 	if err != nil {
 		return nil, errors.Wrap(err, errExtractEndpoint)
 	}
+	url, err = endpointOverride(mg, pc, url)
+	if err != nil {
+		return nil, err
+	}
 
 	opts := []config.Option{
 		config.UserPassword(cred.Email, cred.Password),
 		config.SkipTLSValidation(),
+		config.HttpClient(newHTTPClient(pc.Spec.Retry)),
 	}
 	if cred.Origin != "" {
 		opts = append(opts, config.Origin(cred.Origin))
 	}
+	if ov, ok := mg.(RequestTimeoutOverrider); ok {
+		if d := ov.GetRequestTimeout(); d != nil && *d > config.DefaultRequestTimeout {
+			opts = append(opts, config.RequestTimeout(*d))
+		}
+	}
 	return config.New(*url, opts...)
 }
 
+// GetProviderConfig returns the ProviderConfig of the given managed resource.
+func GetProviderConfig(ctx context.Context, client client.Client, mg resource.Managed) (*v1beta1.ProviderConfig, error) {
+	pc, err := getProviderConfig(ctx, client, mg)
+	return pc, errors.Wrap(err, errGetProviderConfig)
+}
+
+// ProviderConfigName returns the namespace and name of the ProviderConfig of
+// the given managed resource, or an empty name if it references none.
+func ProviderConfigName(mg resource.Managed) types.NamespacedName {
+	mm, ok := mg.(resource.ModernManaged)
+	if !ok || mm.GetProviderConfigReference() == nil {
+		return types.NamespacedName{}
+	}
+	return types.NamespacedName{Namespace: mg.GetNamespace(), Name: mm.GetProviderConfigReference().Name}
+}
+
 func getProviderConfig(ctx context.Context, client client.Client, mg resource.Managed) (*v1beta1.ProviderConfig, error) {
 	mm, ok := mg.(resource.ModernManaged)
 	if !ok {
@@ -114,8 +150,30 @@ func getEndpoint(ctx context.Context, client client.Client, pc *v1beta1.Provider
 	return nil, errors.New(errNoEndpoint)
 }
 
+// endpointOverride returns the API endpoint annotated on mg if there is one,
+// or else the endpoint of its ProviderConfig. An annotated endpoint that the
+// ProviderConfig does not allow is rejected, so that its credentials are only
+// ever sent to the APIs it names.
+func endpointOverride(mg resource.Managed, pc *v1beta1.ProviderConfig, endpoint *string) (*string, error) {
+	override, ok := mg.GetAnnotations()[AnnotationKeyAPIEndpoint]
+	if !ok || override == "" || override == *endpoint {
+		return endpoint, nil
+	}
+	if !slices.Contains(pc.Spec.AllowedAPIEndpoints, override) {
+		return nil, errors.Errorf(errEndpointNotAllowed, override)
+	}
+	return &override, nil
+}
+
 type ClientFn func(resource.Managed) (*cfv3.Client, error)
 
+// ClientFnBuilder returns a function that creates a cloudfoundry client for
+// the ProviderConfig of a managed resource. If the CF API rejects the token
+// of the client, e.g. because it expired mid-reconcile, the client fetches a
+// new token once and retries the request. If UAA rejects the credentials
+// then, the error is classified as ErrorUnauthorized. The versions of the CF
+// API and UAA are recorded in the status of the ProviderConfig on the first
+// connect, and refreshed every DefaultVersionRefreshInterval.
 func ClientFnBuilder(ctx context.Context, client client.Client) func(resource.Managed) (*cfv3.Client, error) {
 	return func(mg resource.Managed) (*cfv3.Client, error) {
 		cfg, err := GetCredentialConfig(ctx, client, mg)
@@ -123,6 +181,20 @@ func ClientFnBuilder(ctx context.Context, client client.Client) func(resource.Ma
 			return nil, errors.Wrap(err, "cannot config cloudfoundry client")
 		}
 
-		return cfv3.New(cfg)
+		cf, err := cfv3.New(cfg)
+		if err != nil {
+			return nil, err
+		}
+		versions.record(ctx, client, mg, cf)
+		return cf, nil
 	}
 }
+
+// ClientForProviderConfig returns a cloudfoundry client authenticated with the
+// given ProviderConfig, for work that is not tied to a single managed resource.
+func ClientForProviderConfig(ctx context.Context, client client.Client, pc *v1beta1.ProviderConfig) (*cfv3.Client, error) {
+	mg := &v1alpha1.Space{}
+	mg.SetNamespace(pc.Namespace)
+	mg.SetProviderConfigReference(&xpv1.ProviderConfigReference{Kind: v1beta1.ProviderConfigKind, Name: pc.Name})
+	return ClientFnBuilder(ctx, client)(mg)
+}
@@ -0,0 +1,196 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8s "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/v1beta1"
+)
+
+// newAPIRoot returns a CF API that serves its root document and issues tokens as its own UAA.
+func newAPIRoot(t *testing.T) *httptest.Server {
+	t.Helper()
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/oauth/token") {
+			_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "token", "token_type": "bearer", "expires_in": 3600})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"links": map[string]any{
+			"login": map[string]string{"href": srv.URL},
+			"uaa":   map[string]string{"href": srv.URL},
+		}})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// withProviderConfig returns a MockGetFn that gets the given ProviderConfig and its credentials.
+func withProviderConfig(pc *v1beta1.ProviderConfig) test.MockGetFn {
+	return func(_ context.Context, _ k8s.ObjectKey, obj k8s.Object) error {
+		switch o := obj.(type) {
+		case *v1beta1.ProviderConfig:
+			pc.DeepCopyInto(o)
+		case *corev1.Secret:
+			o.Data = map[string][]byte{"credentials": []byte(`{"email":"admin","password":"secret"}`)}
+		}
+		return nil
+	}
+}
+
+func TestGetCredentialConfig(t *testing.T) {
+	def := newAPIRoot(t)
+	staging := newAPIRoot(t)
+	other := newAPIRoot(t)
+
+	pc := &v1beta1.ProviderConfig{Spec: v1beta1.ProviderConfigSpec{
+		APIEndpoint:         &def.URL,
+		AllowedAPIEndpoints: []string{staging.URL},
+		Credentials: v1beta1.ProviderCredentials{
+			Source: xpv1.CredentialsSourceSecret,
+			CommonCredentialSelectors: xpv1.CommonCredentialSelectors{SecretRef: &xpv1.SecretKeySelector{
+				SecretReference: xpv1.SecretReference{Name: "cf", Namespace: "default"},
+				Key:             "credentials",
+			}},
+		},
+	}}
+
+	cases := map[string]struct {
+		endpoint string
+		want     string
+		err      error
+	}{
+		"ProviderConfigEndpoint": {
+			want: def.URL,
+		},
+		"OverrideEndpoint": {
+			endpoint: staging.URL,
+			want:     staging.URL,
+		},
+		"OverrideNotAllowed": {
+			endpoint: other.URL,
+			err:      errors.Errorf(errEndpointNotAllowed, other.URL),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := newSpace()
+			if tc.endpoint != "" {
+				mg.SetAnnotations(map[string]string{AnnotationKeyAPIEndpoint: tc.endpoint})
+			}
+
+			cfg, err := GetCredentialConfig(context.Background(), &test.MockClient{MockGet: withProviderConfig(pc)}, mg)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("GetCredentialConfig(...): -want error, +got error:\n%s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if got := cfg.ApiURL("/"); !strings.HasPrefix(got, tc.want) {
+				t.Errorf("GetCredentialConfig(...): want API %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+// newExpiringAPI returns a CF API that acts as its own UAA and rejects the
+// first token it issues as expired. If refreshFails is true, UAA rejects the
+// credentials once the first token is issued.
+func newExpiringAPI(t *testing.T, refreshFails bool) (srv *httptest.Server, tokens, requests *int) {
+	t.Helper()
+	tokens, requests = new(int), new(int)
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_ = json.NewEncoder(w).Encode(map[string]any{"links": map[string]any{
+				"login": map[string]string{"href": srv.URL},
+				"uaa":   map[string]string{"href": srv.URL},
+			}})
+		case "/oauth/token":
+			*tokens++
+			if refreshFails && *tokens > 1 {
+				w.WriteHeader(http.StatusUnauthorized)
+				_ = json.NewEncoder(w).Encode(map[string]string{"error": "unauthorized", "error_description": "Bad credentials"})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"access_token": fmt.Sprintf("token-%d", *tokens), "token_type": "bearer", "expires_in": 3600})
+		case "/v3/organizations/org-guid":
+			*requests++
+			if r.Header.Get("Authorization") == "Bearer token-1" {
+				w.WriteHeader(http.StatusUnauthorized)
+				_ = json.NewEncoder(w).Encode(map[string]any{"errors": []map[string]any{{"code": 1000, "title": "CF-InvalidAuthToken", "detail": "Invalid Auth Token"}}})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]string{"guid": "org-guid", "name": "dev"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, tokens, requests
+}
+
+func TestClientFnBuilderTokenExpiry(t *testing.T) {
+	type want struct {
+		tokens   int
+		requests int
+		class    ErrorClass
+	}
+	cases := map[string]struct {
+		refreshFails bool
+		want         want
+	}{
+		"RefreshAndRetryOnce": {
+			want: want{tokens: 2, requests: 2},
+		},
+		"RefreshFails": {
+			refreshFails: true,
+			want:         want{tokens: 2, requests: 1, class: ErrorUnauthorized},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			api, tokens, requests := newExpiringAPI(t, tc.refreshFails)
+			pc := &v1beta1.ProviderConfig{Spec: v1beta1.ProviderConfigSpec{
+				APIEndpoint: &api.URL,
+				Credentials: v1beta1.ProviderCredentials{
+					Source: xpv1.CredentialsSourceSecret,
+					CommonCredentialSelectors: xpv1.CommonCredentialSelectors{SecretRef: &xpv1.SecretKeySelector{
+						SecretReference: xpv1.SecretReference{Name: "cf", Namespace: "default"},
+						Key:             "credentials",
+					}},
+				},
+			}}
+
+			cf, err := ClientFnBuilder(context.Background(), &test.MockClient{MockGet: withProviderConfig(pc)})(newSpace())
+			if err != nil {
+				t.Fatalf("ClientFnBuilder(...): %v", err)
+			}
+			org, err := cf.Organizations.Get(context.Background(), "org-guid")
+			if got := ClassifyError(err); got != tc.want.class {
+				t.Errorf("Get(...): want error of class %q, got %q: %v", tc.want.class, got, err)
+			}
+			if err == nil && org.Name != "dev" {
+				t.Errorf("Get(...): want org dev, got %s", org.Name)
+			}
+			got := want{tokens: *tokens, requests: *requests, class: tc.want.class}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("Get(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
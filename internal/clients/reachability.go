@@ -0,0 +1,116 @@
+package clients
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	cfv3 "github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/v1beta1"
+)
+
+// DefaultReachabilityInterval is the default time between two checks of
+// the CF API, during which the result of a check is reused.
+const DefaultReachabilityInterval = 30 * time.Second
+
+const (
+	errListProviderConfigs = "cannot list ProviderConfigs"
+	errReachAPI            = "cannot reach cloudfoundry API with ProviderConfig %s/%s"
+)
+
+// A ReachabilityReporter records the result of a check for each
+// ProviderConfig: nil if the CF API was reachable with it, the error
+// otherwise.
+type ReachabilityReporter func(results map[types.NamespacedName]error)
+
+// ReachabilityChecker checks whether the provider can reach and authenticate
+// against the CF API with each configured ProviderConfig. The result is cached
+// for a while so that frequent checks do not hammer the API.
+type ReachabilityChecker struct {
+	kube   client.Client
+	ttl    time.Duration
+	now    func() time.Time
+	probe  func(ctx context.Context, pc *v1beta1.ProviderConfig) error
+	report ReachabilityReporter
+
+	mu        sync.Mutex
+	checkedAt time.Time
+	err       error
+}
+
+// NewReachabilityChecker returns a ReachabilityChecker that builds its CF
+// clients with ClientFnBuilder, caches its result for ttl and reports the
+// result for each ProviderConfig to report, if not nil.
+func NewReachabilityChecker(kube client.Client, ttl time.Duration, report ReachabilityReporter) *ReachabilityChecker {
+	c := &ReachabilityChecker{kube: kube, ttl: ttl, now: time.Now, report: report}
+	c.probe = c.probeAPI
+	return c
+}
+
+// Start implements manager.Runnable. It checks the CF API every ttl until
+// ctx is done, so that the reported results stay current.
+func (c *ReachabilityChecker) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, func(ctx context.Context) { _ = c.CheckContext(ctx) }, c.ttl)
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable. Every
+// replica reports the reachability of the CF API from its own pod.
+func (c *ReachabilityChecker) NeedLeaderElection() bool {
+	return false
+}
+
+// CheckContext returns an error if the CF API cannot be reached with any
+// ProviderConfig. A provider without any ProviderConfig has no API to reach
+// and succeeds.
+func (c *ReachabilityChecker) CheckContext(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.checkedAt.IsZero() && c.now().Sub(c.checkedAt) < c.ttl {
+		return c.err
+	}
+	c.err = c.check(ctx)
+	c.checkedAt = c.now()
+	return c.err
+}
+
+// check probes every ProviderConfig, even if one fails, reports the results
+// and returns the first error.
+func (c *ReachabilityChecker) check(ctx context.Context) error {
+	pcs := &v1beta1.ProviderConfigList{}
+	if err := c.kube.List(ctx, pcs); err != nil {
+		return errors.Wrap(err, errListProviderConfigs)
+	}
+	results := make(map[types.NamespacedName]error, len(pcs.Items))
+	var first error
+	for i := range pcs.Items {
+		pc := &pcs.Items[i]
+		err := c.probe(ctx, pc)
+		results[types.NamespacedName{Namespace: pc.Namespace, Name: pc.Name}] = err
+		if err != nil && first == nil {
+			first = errors.Wrapf(err, errReachAPI, pc.Namespace, pc.Name)
+		}
+	}
+	if c.report != nil {
+		c.report(results)
+	}
+	return first
+}
+
+// probeAPI builds a CF client for the ProviderConfig and lists a single
+// organization, which requires a valid token.
+func (c *ReachabilityChecker) probeAPI(ctx context.Context, pc *v1beta1.ProviderConfig) error {
+	cf, err := ClientForProviderConfig(ctx, c.kube, pc)
+	if err != nil {
+		return err
+	}
+	opts := cfv3.NewOrganizationListOptions()
+	opts.PerPage = 1
+	_, _, err = cf.Organizations.List(ctx, opts)
+	return err
+}
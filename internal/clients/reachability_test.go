@@ -0,0 +1,115 @@
+package clients
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	k8s "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/v1beta1"
+)
+
+func withProviderConfigs(names ...string) test.MockListFn {
+	return func(_ context.Context, obj k8s.ObjectList, _ ...k8s.ListOption) error {
+		l := obj.(*v1beta1.ProviderConfigList)
+		for _, n := range names {
+			pc := v1beta1.ProviderConfig{}
+			pc.SetNamespace("default")
+			pc.SetName(n)
+			l.Items = append(l.Items, pc)
+		}
+		return nil
+	}
+}
+
+func TestReachabilityChecker(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type check struct {
+		elapsed time.Duration
+		want    error
+	}
+	cases := map[string]struct {
+		list   test.MockListFn
+		failed map[string]bool
+		checks []check
+		probes int
+		report map[types.NamespacedName]error
+	}{
+		"NoProviderConfig": {
+			list:   withProviderConfigs(),
+			checks: []check{{}},
+			report: map[types.NamespacedName]error{},
+		},
+		"Reachable": {
+			list:   withProviderConfigs("a", "b"),
+			checks: []check{{}},
+			probes: 2,
+			report: map[types.NamespacedName]error{{Namespace: "default", Name: "a"}: nil, {Namespace: "default", Name: "b"}: nil},
+		},
+		"Unreachable": {
+			list:   withProviderConfigs("a", "b", "c"),
+			failed: map[string]bool{"b": true},
+			checks: []check{{want: errors.Wrapf(errBoom, errReachAPI, "default", "b")}},
+			probes: 3,
+			report: map[types.NamespacedName]error{{Namespace: "default", Name: "a"}: nil, {Namespace: "default", Name: "b"}: errBoom, {Namespace: "default", Name: "c"}: nil},
+		},
+		"ListFailed": {
+			list:   test.NewMockListFn(errBoom),
+			checks: []check{{want: errors.Wrap(errBoom, errListProviderConfigs)}},
+		},
+		"CachedWithinTTL": {
+			list:   withProviderConfigs("a"),
+			failed: map[string]bool{"a": true},
+			checks: []check{
+				{want: errors.Wrapf(errBoom, errReachAPI, "default", "a")},
+				{elapsed: 10 * time.Second, want: errors.Wrapf(errBoom, errReachAPI, "default", "a")},
+			},
+			probes: 1,
+			report: map[types.NamespacedName]error{{Namespace: "default", Name: "a"}: errBoom},
+		},
+		"RecheckedAfterTTL": {
+			list:   withProviderConfigs("a"),
+			checks: []check{{}, {elapsed: time.Minute}},
+			probes: 2,
+			report: map[types.NamespacedName]error{{Namespace: "default", Name: "a"}: nil},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			now := time.Now()
+			probes := 0
+			var report map[types.NamespacedName]error
+			c := NewReachabilityChecker(&test.MockClient{MockList: tc.list}, DefaultReachabilityInterval, func(results map[types.NamespacedName]error) {
+				report = results
+			})
+			c.now = func() time.Time { return now }
+			c.probe = func(_ context.Context, pc *v1beta1.ProviderConfig) error {
+				probes++
+				if tc.failed[pc.Name] {
+					return errBoom
+				}
+				return nil
+			}
+
+			for i, ch := range tc.checks {
+				now = now.Add(ch.elapsed)
+				err := c.CheckContext(context.Background())
+				if diff := cmp.Diff(ch.want, err, test.EquateErrors()); diff != "" {
+					t.Errorf("check %d: -want error, +got error:\n%s", i, diff)
+				}
+			}
+			if probes != tc.probes {
+				t.Errorf("probes: want %d, got %d", tc.probes, probes)
+			}
+			if diff := cmp.Diff(tc.report, report, test.EquateErrors()); diff != "" {
+				t.Errorf("report: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package clients
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// DefaultReadAfterWriteTimeout is the default time a lookup by spec keeps
+// retrying while Cloud Foundry does not list a resource yet.
+const DefaultReadAfterWriteTimeout = 2 * time.Second
+
+// ReadAfterWriteTimeout bounds how long a lookup by spec retries when Cloud
+// Foundry does not list a just created resource yet. Zero disables retries.
+var ReadAfterWriteTimeout = DefaultReadAfterWriteTimeout
+
+// readAfterWriteInterval is the pause between two lookups by spec.
+const readAfterWriteInterval = 500 * time.Millisecond
+
+// readAfterWriteWindow is how long after a create a missing resource is
+// attributed to a stale list rather than to the resource being absent.
+const readAfterWriteWindow = time.Minute
+
+// ReadAfterWrite calls get until it finds a resource, fails with an error
+// other than not found or ReadAfterWriteTimeout elapses. Lists of the CF API
+// are eventually consistent, so a resource created moments ago may be missing
+// from the first few results. Retries only happen if created, the time the
+// resource was created at, is recent; a resource that was never created or
+// was created long ago is looked up once. When the resource stays missing the
+// result of the last call is returned.
+func ReadAfterWrite[T any](ctx context.Context, created time.Time, get func(context.Context) (*T, error)) (*T, error) {
+	r, err := get(ctx)
+	if ReadAfterWriteTimeout <= 0 || !isMissing(r, err) || !createdRecently(created) {
+		return r, err
+	}
+
+	_ = wait.PollUntilContextTimeout(ctx, readAfterWriteInterval, ReadAfterWriteTimeout, false, func(context.Context) (bool, error) {
+		// get uses the caller's context, so the poll deadline does not cut a lookup short.
+		r, err = get(ctx)
+		return !isMissing(r, err), nil
+	})
+	return r, err
+}
+
+func createdRecently(created time.Time) bool {
+	return !created.IsZero() && time.Since(created) < readAfterWriteWindow
+}
+
+func isMissing[T any](r *T, err error) bool {
+	if err != nil {
+		return IsNotFound(err)
+	}
+	return r == nil
+}
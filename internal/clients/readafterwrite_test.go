@@ -0,0 +1,83 @@
+package clients
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+// listing returns a get function that reports not found for the first misses calls
+// and err afterwards, counting the calls.
+func listing(misses int, found *string, err error, calls *int) func(context.Context) (*string, error) {
+	return func(context.Context) (*string, error) {
+		*calls++
+		if *calls <= misses {
+			return nil, client.ErrNoResultsReturned
+		}
+		return found, err
+	}
+}
+
+func TestReadAfterWrite(t *testing.T) {
+	found := "my-space"
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		created   time.Time
+		misses    int
+		err       error
+		want      *string
+		wantErr   error
+		wantCalls int
+	}{
+		"FoundAtOnce": {
+			created:   time.Now(),
+			want:      &found,
+			wantCalls: 1,
+		},
+		"FoundAfterStaleRead": {
+			created:   time.Now(),
+			misses:    1,
+			want:      &found,
+			wantCalls: 2,
+		},
+		"NeverCreated": {
+			misses:    1,
+			wantErr:   client.ErrNoResultsReturned,
+			wantCalls: 1,
+		},
+		"CreatedLongAgo": {
+			created:   time.Now().Add(-time.Hour),
+			misses:    1,
+			wantErr:   client.ErrNoResultsReturned,
+			wantCalls: 1,
+		},
+		"OtherError": {
+			created:   time.Now(),
+			err:       errBoom,
+			wantErr:   errBoom,
+			wantCalls: 1,
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			calls := 0
+			got, err := ReadAfterWrite(context.Background(), tc.created, listing(tc.misses, &found, tc.err, &calls))
+			if tc.wantErr == nil && err == nil {
+				if diff := cmp.Diff(tc.want, got); diff != "" {
+					t.Errorf("ReadAfterWrite(...): -want, +got:\n%s", diff)
+				}
+			} else if !errors.Is(err, tc.wantErr) {
+				t.Errorf("ReadAfterWrite(...): want error %v, got %v", tc.wantErr, err)
+			}
+			if diff := cmp.Diff(tc.wantCalls, calls); diff != "" {
+				t.Errorf("ReadAfterWrite(...): -want calls, +got:\n%s", diff)
+			}
+		})
+	}
+}
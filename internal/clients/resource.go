@@ -1,9 +1,22 @@
 package clients
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
 
 // IsValidGUID checks if the given string is a valid UUID.
 func IsValidGUID(guid string) bool {
 	_, err := uuid.Parse(guid)
 	return err == nil
 }
+
+// ValidateGUID returns an error if a reference field is set to a value that
+// is not a valid GUID, so that a typo is reported before Cloud Foundry is
+// called. An unset field is valid.
+func ValidateGUID(field string, guid *string) error {
+	if guid == nil || IsValidGUID(*guid) {
+		return nil
+	}
+	return errors.Errorf("%s %q is not a valid GUID", field, *guid)
+}
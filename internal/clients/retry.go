@@ -0,0 +1,114 @@
+package clients
+
+import (
+	"crypto/tls"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/v1beta1"
+)
+
+const (
+	// retryBaseDelay is the wait time before the first retry if the CF API
+	// does not send a Retry-After header.
+	retryBaseDelay = 500 * time.Millisecond
+	// retryMaxDelay caps the wait time before a retry, including the one
+	// requested by a Retry-After header.
+	retryMaxDelay = 30 * time.Second
+)
+
+// retryTransport retries idempotent requests that the CF API rejects
+// because it is rate limited or temporarily unavailable.
+type retryTransport struct {
+	base       http.RoundTripper
+	maxRetries int
+	baseDelay  time.Duration
+	maxDelay   time.Duration
+}
+
+// newHTTPClient returns the http.Client for go-cfclient. It sends the
+// request headers set with WithRequestHeader and, if retry is set, retries
+// idempotent requests up to retry.MaxRetries times. go-cfclient only
+// configures the TLS settings of a plain *http.Transport, hence the TLS
+// validation is skipped here just like config.SkipTLSValidation does.
+func newHTTPClient(retry *v1beta1.RetryConfig) *http.Client {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	base.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} //nolint:gosec // same as config.SkipTLSValidation
+	var rt http.RoundTripper = base
+	if retry != nil {
+		rt = &retryTransport{
+			base:       base,
+			maxRetries: retry.MaxRetries,
+			baseDelay:  retryBaseDelay,
+			maxDelay:   retryMaxDelay,
+		}
+	}
+	return &http.Client{Transport: &headerTransport{base: rt}}
+}
+
+// RoundTrip implements http.RoundTripper. Requests that are not idempotent,
+// such as creates, are sent once.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return t.base.RoundTrip(req)
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if err != nil || attempt >= t.maxRetries || !isTransientStatus(resp.StatusCode) {
+			return resp, err
+		}
+
+		wait := t.delay(attempt, resp.Header.Get("Retry-After"))
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// delay returns the wait time before the retry following the given attempt.
+// A Retry-After header is honored, otherwise the wait time grows
+// exponentially with jitter. Both are capped at maxDelay.
+func (t *retryTransport) delay(attempt int, retryAfter string) time.Duration {
+	if d, ok := parseRetryAfter(retryAfter); ok {
+		return min(d, t.maxDelay)
+	}
+	d := min(t.baseDelay<<attempt, t.maxDelay)
+	// full jitter in [d/2, d]
+	return d/2 + time.Duration(rand.Int64N(int64(d/2)+1))
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either
+// a number of seconds or an HTTP date.
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if s, err := strconv.Atoi(v); err == nil && s >= 0 {
+		return time.Duration(s) * time.Second, true
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		return max(time.Until(at), 0), true
+	}
+	return 0, false
+}
+
+// isTransientStatus returns true if the status code reports that the CF API
+// is rate limited or temporarily unavailable.
+func isTransientStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package clients
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// respondWith returns a handler that responds with the given status codes in
+// order, repeating the last one, and counts the requests.
+func respondWith(calls *int, retryAfter string, codes ...int) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		code := codes[min(*calls, len(codes)-1)]
+		*calls++
+		if code == http.StatusTooManyRequests && retryAfter != "" {
+			w.Header().Set("Retry-After", retryAfter)
+		}
+		w.WriteHeader(code)
+	}
+}
+
+func TestRetryTransport(t *testing.T) {
+	cases := map[string]struct {
+		method     string
+		retryAfter string
+		codes      []int
+		wantCode   int
+		wantCalls  int
+	}{
+		"RateLimitedThenOK": {
+			method:     http.MethodGet,
+			retryAfter: "0",
+			codes:      []int{http.StatusTooManyRequests, http.StatusOK},
+			wantCode:   http.StatusOK,
+			wantCalls:  2,
+		},
+		"UnavailableThenOK": {
+			method:    http.MethodGet,
+			codes:     []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK},
+			wantCode:  http.StatusOK,
+			wantCalls: 3,
+		},
+		"RetriesExhausted": {
+			method:     http.MethodGet,
+			retryAfter: "0",
+			codes:      []int{http.StatusTooManyRequests},
+			wantCode:   http.StatusTooManyRequests,
+			wantCalls:  4,
+		},
+		"NotTransient": {
+			method:    http.MethodGet,
+			codes:     []int{http.StatusNotFound},
+			wantCode:  http.StatusNotFound,
+			wantCalls: 1,
+		},
+		"CreateNotRetried": {
+			method:     http.MethodPost,
+			retryAfter: "0",
+			codes:      []int{http.StatusTooManyRequests, http.StatusCreated},
+			wantCode:   http.StatusTooManyRequests,
+			wantCalls:  1,
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			calls := 0
+			srv := httptest.NewServer(respondWith(&calls, tc.retryAfter, tc.codes...))
+			defer srv.Close()
+
+			c := &http.Client{Transport: &retryTransport{
+				base:       http.DefaultTransport,
+				maxRetries: 3,
+				baseDelay:  time.Millisecond,
+				maxDelay:   10 * time.Millisecond,
+			}}
+			req, err := http.NewRequest(tc.method, srv.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := c.Do(req)
+			if err != nil {
+				t.Fatalf("Do(...): unexpected error: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if diff := cmp.Diff(tc.wantCode, resp.StatusCode); diff != "" {
+				t.Errorf("Do(...): -want status, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantCalls, calls); diff != "" {
+				t.Errorf("Do(...): -want calls, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRetryTransportDelay(t *testing.T) {
+	tr := &retryTransport{baseDelay: time.Second, maxDelay: 4 * time.Second}
+
+	if d := tr.delay(0, "2"); d != 2*time.Second {
+		t.Errorf("delay(0, \"2\"): want %v, got %v", 2*time.Second, d)
+	}
+	if d := tr.delay(0, "60"); d != 4*time.Second {
+		t.Errorf("delay(0, \"60\"): want the cap %v, got %v", 4*time.Second, d)
+	}
+	for attempt := range 5 {
+		want := min(time.Second<<attempt, 4*time.Second)
+		if d := tr.delay(attempt, ""); d < want/2 || d > want {
+			t.Errorf("delay(%d, \"\"): %v not in [%v, %v]", attempt, d, want/2, want)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package role
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	cfv3 "github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"k8s.io/utils/ptr"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/job"
+)
+
+// ObserveOrgRoleAssignments returns the GUID of the org role of each username in spec.Usernames.
+// Users that were previously assigned but are no longer listed are kept as long as their role exists, so that they can be removed.
+// legacyRole is the GUID of the role created while the resource specified a single username; it is adopted so that it is
+// removed if its user is not listed.
+func ObserveOrgRoleAssignments(ctx context.Context, client Role, users Users, spec v1alpha1.OrgRoleParameters, assigned map[string]string, legacyRole *string) (map[string]string, error) {
+	opts, err := NewOrgRoleListOptions(spec)
+	if err != nil {
+		return nil, err
+	}
+	return observeAssignments(ctx, client, users, opts, spec.Usernames, assigned, legacyRole, spec.Origin, OrgRoleType(spec.Type).String())
+}
+
+// ObserveSpaceRoleAssignments returns the GUID of the space role of each username in spec.Usernames.
+// Users that were previously assigned but are no longer listed are kept as long as their role exists, so that they can be removed.
+// legacyRole is the GUID of the role created while the resource specified a single username; it is adopted so that it is
+// removed if its user is not listed.
+func ObserveSpaceRoleAssignments(ctx context.Context, client Role, users Users, spec v1alpha1.SpaceRoleParameters, assigned map[string]string, legacyRole *string) (map[string]string, error) {
+	opts, err := newSpaceRoleListOptions(spec)
+	if err != nil {
+		return nil, err
+	}
+	return observeAssignments(ctx, client, users, opts, spec.Usernames, assigned, legacyRole, spec.Origin, SpaceRoleType(spec.Type).String())
+}
+
+// AssignOrgRoles creates the org roles of the usernames that are not assigned yet and deletes the roles of assigned users that are no longer listed.
+// It returns the resulting assignments together with the aggregated errors of all failed users.
+func AssignOrgRoles(ctx context.Context, client Role, users Users, j job.Job, spec v1alpha1.OrgRoleParameters, assigned map[string]string) (map[string]string, error) {
+	if spec.Org == nil {
+		return assigned, errors.New(ErrOrgNotSpecified)
+	}
+	return reconcileAssignments(ctx, client, j, spec.Usernames, assigned, func(username string) (*resource.Role, error) {
+		origin, err := ResolveOrigin(ctx, users, username, spec.Origin)
+		if err != nil {
+			return nil, err
+		}
+		return client.CreateOrganizationRoleWithUsername(ctx, *spec.Org, username, OrgRoleType(spec.Type), origin)
+	})
+}
+
+// AssignSpaceRoles creates the space roles of the usernames that are not assigned yet and deletes the roles of assigned users that are no longer listed.
+// It returns the resulting assignments together with the aggregated errors of all failed users.
+func AssignSpaceRoles(ctx context.Context, client Role, users Users, j job.Job, spec v1alpha1.SpaceRoleParameters, assigned map[string]string) (map[string]string, error) {
+	if spec.Space == nil {
+		return assigned, errors.New(ErrSpaceNotSpecified)
+	}
+	return reconcileAssignments(ctx, client, j, spec.Usernames, assigned, func(username string) (*resource.Role, error) {
+		origin, err := ResolveOrigin(ctx, users, username, spec.Origin)
+		if err != nil {
+			return nil, err
+		}
+		return client.CreateSpaceRoleWithUsername(ctx, *spec.Space, username, SpaceRoleType(spec.Type), origin)
+	})
+}
+
+// UnassignRoles deletes all assigned roles and returns the assignments that could not be deleted.
+func UnassignRoles(ctx context.Context, client Role, j job.Job, assigned map[string]string) (map[string]string, error) {
+	return reconcileAssignments(ctx, client, j, nil, assigned, nil)
+}
+
+// AssignmentsUpToDate reports whether exactly the given usernames are assigned.
+func AssignmentsUpToDate(usernames []string, assigned map[string]string) bool {
+	if len(usernames) != len(assigned) {
+		return false
+	}
+	for _, u := range usernames {
+		if _, ok := assigned[u]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func observeAssignments(ctx context.Context, client Role, userClient Users, opts *cfv3.RoleListOptions, usernames []string, assigned map[string]string, legacyRole, origin *string, roleType string) (map[string]string, error) {
+	roles, users, err := client.ListIncludeUsersAll(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	observed := make(map[string]string, len(usernames))
+	for _, u := range usernames {
+		o := ptr.Deref(origin, "")
+		if origin == nil {
+			// users with an ambiguous origin are left unassigned, Create reports the error
+			if o, err = ResolveOrigin(ctx, userClient, u, nil); err != nil {
+				continue
+			}
+		}
+		r, err := findRole(roles, users, u, o, roleType)
+		if err != nil {
+			continue
+		}
+		observed[u] = r.GUID
+	}
+
+	if legacyRole != nil {
+		assigned = adoptLegacyRole(roles, users, assigned, *legacyRole)
+	}
+
+	// keep users removed from the spec while their role still exists
+	for u, guid := range assigned {
+		if _, ok := observed[u]; ok {
+			continue
+		}
+		for _, r := range roles {
+			if r.GUID == guid {
+				observed[u] = guid
+				break
+			}
+		}
+	}
+	return observed, nil
+}
+
+// adoptLegacyRole adds the role created for a single username to the assignments, keyed by the username of its user.
+func adoptLegacyRole(roles []*resource.Role, users []*resource.User, assigned map[string]string, guid string) map[string]string {
+	for _, g := range assigned {
+		if g == guid {
+			return assigned
+		}
+	}
+
+	var noUserRelation resource.ToOneRelationship
+	for _, r := range roles {
+		if r.GUID != guid || r.Relationships.User == noUserRelation {
+			continue
+		}
+		key := r.Relationships.User.Data.GUID
+		for _, u := range users {
+			if u.GUID == key && u.Username != nil {
+				key = *u.Username
+				break
+			}
+		}
+		adopted := make(map[string]string, len(assigned)+1)
+		for u, g := range assigned {
+			adopted[u] = g
+		}
+		if _, ok := adopted[key]; !ok {
+			adopted[key] = guid
+		}
+		return adopted
+	}
+	return assigned
+}
+
+func reconcileAssignments(ctx context.Context, client Role, j job.Job, usernames []string, assigned map[string]string, create func(string) (*resource.Role, error)) (map[string]string, error) {
+	result := make(map[string]string, len(usernames))
+	for u, guid := range assigned {
+		result[u] = guid
+	}
+
+	var errs []error
+	for _, u := range usernames {
+		if _, ok := result[u]; ok {
+			continue
+		}
+		r, err := create(u)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("cannot assign role to %s: %w", u, err))
+			continue
+		}
+		result[u] = r.GUID
+	}
+
+	desired := make(map[string]bool, len(usernames))
+	for _, u := range usernames {
+		desired[u] = true
+	}
+	for u, guid := range assigned {
+		if desired[u] {
+			continue
+		}
+		if err := deleteRole(ctx, client, j, guid); err != nil {
+			errs = append(errs, fmt.Errorf("cannot unassign role from %s: %w", u, err))
+			continue
+		}
+		delete(result, u)
+	}
+
+	return result, errors.Join(errs...)
+}
+
+func deleteRole(ctx context.Context, client Role, j job.Job, guid string) error {
+	jobGUID, err := client.Delete(ctx, guid)
+	if err != nil {
+		return clients.IgnoreNotFoundErr(err)
+	}
+	return job.PollJobComplete(ctx, j, jobGUID)
+}
@@ -0,0 +1,218 @@
+package role
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	cfv3 "github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+)
+
+// DefaultCacheTTL is the default lifetime of a cached role listing.
+const DefaultCacheTTL = 10 * time.Second
+
+// CacheTTL is how long a role listing of an org or space is reused by
+// concurrent reconciles. A zero or negative value disables caching.
+var CacheTTL = DefaultCacheTTL
+
+// ListCache is a short-lived cache of role listings keyed by the ProviderConfig
+// and the org or space GUID. It is safe for concurrent use.
+type ListCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*listing
+	scopes  map[string]string
+}
+
+// listing is a single cached ListIncludeUsersAll result. done is closed once
+// the listing has been fetched, so that concurrent callers wait for the
+// in-flight request instead of issuing their own.
+type listing struct {
+	done    chan struct{}
+	expires time.Time
+	roles   []*resource.Role
+	users   []*resource.User
+	err     error
+}
+
+// SharedCache returns the process-wide ListCache used by the role controllers.
+// It is created on first use with the then current CacheTTL.
+var SharedCache = sync.OnceValue(func() *ListCache {
+	return NewListCache(CacheTTL)
+})
+
+// NewListCache returns a ListCache whose entries expire after ttl.
+func NewListCache(ttl time.Duration) *ListCache {
+	return &ListCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[string]*listing{},
+		scopes:  map[string]string{},
+	}
+}
+
+// Wrap returns a Role client of the given ProviderConfig that serves
+// ListIncludeUsersAll from the cache and invalidates the affected scope on
+// Create and Delete. Listings are only shared between the clients of the same
+// ProviderConfig, as the roles they see depend on its endpoint and credentials.
+func (c *ListCache) Wrap(r Role, providerConfig string) Role {
+	if c == nil || c.ttl <= 0 {
+		return r
+	}
+	return &cachedRole{Role: r, cache: c, providerConfig: providerConfig}
+}
+
+// Invalidate drops the cached listing of the given org or space GUID of the
+// given ProviderConfig.
+func (c *ListCache) Invalidate(providerConfig, scope string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, cacheKey(providerConfig, scope))
+}
+
+// list returns the roles of all types in scope, fetching them with fetch
+// unless a fresh listing is already cached or in flight.
+func (c *ListCache) list(providerConfig, scope string, fetch func() ([]*resource.Role, []*resource.User, error)) ([]*resource.Role, []*resource.User, error) {
+	key := cacheKey(providerConfig, scope)
+	c.mu.Lock()
+	if e, ok := c.entries[key]; ok && (e.expires.IsZero() || c.now().Before(e.expires)) {
+		c.mu.Unlock()
+		<-e.done
+		return e.roles, e.users, e.err
+	}
+	e := &listing{done: make(chan struct{})}
+	c.entries[key] = e
+	c.mu.Unlock()
+
+	e.roles, e.users, e.err = fetch()
+
+	c.mu.Lock()
+	if e.err != nil {
+		if c.entries[key] == e {
+			delete(c.entries, key)
+		}
+	} else {
+		e.expires = c.now().Add(c.ttl)
+		for _, r := range e.roles {
+			c.scopes[cacheKey(providerConfig, r.GUID)] = key
+		}
+	}
+	close(e.done)
+	c.mu.Unlock()
+
+	return e.roles, e.users, e.err
+}
+
+// invalidateRole drops the listing of the ProviderConfig that contains the
+// role with the given GUID, or every listing if the role has not been seen.
+func (c *ListCache) invalidateRole(providerConfig, guid string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	role := cacheKey(providerConfig, guid)
+	key, ok := c.scopes[role]
+	if !ok {
+		c.entries = map[string]*listing{}
+		return
+	}
+	delete(c.scopes, role)
+	delete(c.entries, key)
+}
+
+// cacheKey returns the key of a GUID seen with a ProviderConfig
+func cacheKey(providerConfig, guid string) string {
+	return providerConfig + "/" + guid
+}
+
+// cachedRole is a Role client of a ProviderConfig backed by a ListCache
+type cachedRole struct {
+	Role
+	cache          *ListCache
+	providerConfig string
+}
+
+// ListIncludeUsersAll lists the roles of a single org or space through the
+// cache. Listings with any other filter are passed through.
+func (r *cachedRole) ListIncludeUsersAll(ctx context.Context, opts *cfv3.RoleListOptions) ([]*resource.Role, []*resource.User, error) {
+	scope, ok := cacheScope(opts)
+	if !ok {
+		return r.Role.ListIncludeUsersAll(ctx, opts)
+	}
+
+	roles, users, err := r.cache.list(r.providerConfig, scope, func() ([]*resource.Role, []*resource.User, error) {
+		// list all role types at once, so that one listing serves every role type in the scope
+		all := *opts
+		all.Types = cfv3.Filter{}
+		return r.Role.ListIncludeUsersAll(ctx, &all)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	roles, users = filterRoles(roles, users, opts.Types.Values)
+	return roles, users, nil
+}
+
+// CreateOrganizationRoleWithUsername creates the role and invalidates the cached org listing
+func (r *cachedRole) CreateOrganizationRoleWithUsername(ctx context.Context, orgGUID string, username string, roleType resource.OrganizationRoleType, origin string) (*resource.Role, error) {
+	defer r.cache.Invalidate(r.providerConfig, orgGUID)
+	return r.Role.CreateOrganizationRoleWithUsername(ctx, orgGUID, username, roleType, origin)
+}
+
+// CreateSpaceRoleWithUsername creates the role and invalidates the cached space listing
+func (r *cachedRole) CreateSpaceRoleWithUsername(ctx context.Context, spaceGUID string, username string, roleType resource.SpaceRoleType, origin string) (*resource.Role, error) {
+	defer r.cache.Invalidate(r.providerConfig, spaceGUID)
+	return r.Role.CreateSpaceRoleWithUsername(ctx, spaceGUID, username, roleType, origin)
+}
+
+// Delete deletes the role and invalidates the cached listing it was part of
+func (r *cachedRole) Delete(ctx context.Context, guid string) (string, error) {
+	defer r.cache.invalidateRole(r.providerConfig, guid)
+	return r.Role.Delete(ctx, guid)
+}
+
+// cacheScope returns the cache key for list options that select exactly one org or space
+func cacheScope(opts *cfv3.RoleListOptions) (string, bool) {
+	if opts == nil || len(opts.GUIDs.Values) > 0 || len(opts.UserGUIDs.Values) > 0 {
+		return "", false
+	}
+	orgs, spaces := opts.OrganizationGUIDs.Values, opts.SpaceGUIDs.Values
+	switch {
+	case len(orgs) == 1 && len(spaces) == 0:
+		return orgs[0], true
+	case len(spaces) == 1 && len(orgs) == 0:
+		return spaces[0], true
+	default:
+		return "", false
+	}
+}
+
+// filterRoles returns the roles of the given types together with their users
+func filterRoles(roles []*resource.Role, users []*resource.User, types []string) ([]*resource.Role, []*resource.User) {
+	if len(types) == 0 {
+		return roles, users
+	}
+
+	var noUserRelation resource.ToOneRelationship
+	filtered := make([]*resource.Role, 0, len(roles))
+	userGUIDs := map[string]bool{}
+	for _, ro := range roles {
+		if !slices.Contains(types, ro.Type) {
+			continue
+		}
+		filtered = append(filtered, ro)
+		if ro.Relationships.User != noUserRelation {
+			userGUIDs[ro.Relationships.User.Data.GUID] = true
+		}
+	}
+
+	filteredUsers := make([]*resource.User, 0, len(userGUIDs))
+	for _, u := range users {
+		if userGUIDs[u.GUID] {
+			filteredUsers = append(filteredUsers, u)
+		}
+	}
+	return filtered, filteredUsers
+}
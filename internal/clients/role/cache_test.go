@@ -0,0 +1,162 @@
+package role
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cfv3 "github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	guidCacheOrg  = "6a1b0d04-d537-4e4e-8c6f-f09ca0e7f56a"
+	guidCacheUser = "6a1b0d04-d537-4e4e-8c6f-f09ca0e7f56b"
+	guidCacheRole = "6a1b0d04-d537-4e4e-8c6f-f09ca0e7f56c"
+)
+
+// countingRole is a Role client that counts the listings it serves
+type countingRole struct {
+	Role
+	lists atomic.Int64
+}
+
+func (r *countingRole) ListIncludeUsersAll(ctx context.Context, opts *cfv3.RoleListOptions) ([]*resource.Role, []*resource.User, error) {
+	r.lists.Add(1)
+	roles := []*resource.Role{
+		{
+			Resource: resource.Resource{GUID: guidCacheRole},
+			Type:     resource.OrganizationRoleManager.String(),
+			Relationships: resource.RoleSpaceUserOrganizationRelationships{
+				User: resource.ToOneRelationship{Data: &resource.Relationship{GUID: guidCacheUser}},
+			},
+		},
+	}
+	users := []*resource.User{
+		{
+			Resource: resource.Resource{GUID: guidCacheUser},
+			Username: ptr.To("user1"),
+			Origin:   ptr.To("sap.ids"),
+		},
+	}
+	roles, users = filterRoles(roles, users, opts.Types.Values)
+	return roles, users, nil
+}
+
+func (r *countingRole) CreateOrganizationRoleWithUsername(context.Context, string, string, resource.OrganizationRoleType, string) (*resource.Role, error) {
+	return &resource.Role{}, nil
+}
+
+func (r *countingRole) Delete(context.Context, string) (string, error) {
+	return "", nil
+}
+
+func orgListOptions(roleType resource.OrganizationRoleType) *cfv3.RoleListOptions {
+	opts := cfv3.NewRoleListOptions()
+	opts.OrganizationGUIDs.EqualTo(guidCacheOrg)
+	opts.WithOrganizationRoleType(roleType)
+	return opts
+}
+
+func TestListCache(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	inner := &countingRole{}
+	cache := NewListCache(time.Minute)
+	cache.now = func() time.Time { return now }
+	client := cache.Wrap(inner, "default/default")
+
+	// first listing hits the API, a second one of another role type is served from cache
+	roles, users, err := client.ListIncludeUsersAll(ctx, orgListOptions(resource.OrganizationRoleManager))
+	require.NoError(t, err)
+	assert.Len(t, roles, 1)
+	assert.Len(t, users, 1)
+
+	roles, users, err = client.ListIncludeUsersAll(ctx, orgListOptions(resource.OrganizationRoleAuditor))
+	require.NoError(t, err)
+	assert.Empty(t, roles)
+	assert.Empty(t, users)
+	assert.Equal(t, int64(1), inner.lists.Load())
+
+	// create invalidates the org listing
+	_, err = client.CreateOrganizationRoleWithUsername(ctx, guidCacheOrg, "user2", resource.OrganizationRoleAuditor, "sap.ids")
+	require.NoError(t, err)
+	_, _, err = client.ListIncludeUsersAll(ctx, orgListOptions(resource.OrganizationRoleManager))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), inner.lists.Load())
+
+	// delete of a listed role invalidates the org listing
+	_, err = client.Delete(ctx, guidCacheRole)
+	require.NoError(t, err)
+	_, _, err = client.ListIncludeUsersAll(ctx, orgListOptions(resource.OrganizationRoleManager))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), inner.lists.Load())
+
+	// listing expires after the TTL
+	now = now.Add(2 * time.Minute)
+	_, _, err = client.ListIncludeUsersAll(ctx, orgListOptions(resource.OrganizationRoleManager))
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), inner.lists.Load())
+}
+
+func TestListCacheProviderConfigs(t *testing.T) {
+	ctx := context.Background()
+	cache := NewListCache(time.Minute)
+	inner, other := &countingRole{}, &countingRole{}
+	client, otherClient := cache.Wrap(inner, "default/default"), cache.Wrap(other, "default/other")
+
+	// each ProviderConfig lists the roles it sees itself
+	_, _, err := client.ListIncludeUsersAll(ctx, orgListOptions(resource.OrganizationRoleManager))
+	require.NoError(t, err)
+	_, _, err = otherClient.ListIncludeUsersAll(ctx, orgListOptions(resource.OrganizationRoleManager))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), inner.lists.Load())
+	assert.Equal(t, int64(1), other.lists.Load())
+
+	// a create only invalidates the listing of its ProviderConfig
+	_, err = client.CreateOrganizationRoleWithUsername(ctx, guidCacheOrg, "user2", resource.OrganizationRoleAuditor, "sap.ids")
+	require.NoError(t, err)
+	_, _, err = otherClient.ListIncludeUsersAll(ctx, orgListOptions(resource.OrganizationRoleManager))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), other.lists.Load())
+}
+
+func TestListCacheDisabled(t *testing.T) {
+	inner := &countingRole{}
+	client := NewListCache(0).Wrap(inner, "default/default")
+
+	for range 3 {
+		_, _, err := client.ListIncludeUsersAll(context.Background(), orgListOptions(resource.OrganizationRoleManager))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int64(3), inner.lists.Load())
+}
+
+// BenchmarkListCache compares the number of API listings issued by concurrent
+// Observe calls with and without the cache.
+func BenchmarkListCache(b *testing.B) {
+	types := []resource.OrganizationRoleType{resource.OrganizationRoleManager, resource.OrganizationRoleAuditor, resource.OrganizationRoleUser}
+
+	for name, ttl := range map[string]time.Duration{"uncached": 0, "cached": DefaultCacheTTL} {
+		b.Run(name, func(b *testing.B) {
+			inner := &countingRole{}
+			client := NewListCache(ttl).Wrap(inner, "default/default")
+
+			var wg sync.WaitGroup
+			for i := range b.N {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, _, _ = client.ListIncludeUsersAll(context.Background(), orgListOptions(types[i%len(types)]))
+				}()
+			}
+			wg.Wait()
+			b.ReportMetric(float64(inner.lists.Load())/float64(b.N), "api-calls/op")
+		})
+	}
+}
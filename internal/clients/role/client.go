@@ -19,7 +19,17 @@ type Role interface {
 	Delete(context.Context, string) (string, error)
 }
 
+// Users is the interface for the user client
+type Users interface {
+	ListAll(ctx context.Context, opts *client.UserListOptions) ([]*resource.User, error)
+}
+
 // NewClient returns a new CF client with Role interface
 func NewClient(cf *client.Client) (Role, job.Job) {
 	return cf.Roles, cf.Jobs
 }
+
+// NewUserClient returns a new CF client with Users interface
+func NewUserClient(cf *client.Client) Users {
+	return cf.Users
+}
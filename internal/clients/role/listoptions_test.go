@@ -0,0 +1,125 @@
+package role
+
+import (
+	"context"
+	"testing"
+
+	cfv3 "github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+)
+
+const (
+	guidListOrg   = "7a1b0d04-d537-4e4e-8c6f-f09ca0e7f56a"
+	guidListSpace = "7a1b0d04-d537-4e4e-8c6f-f09ca0e7f56b"
+	guidListUser  = "7a1b0d04-d537-4e4e-8c6f-f09ca0e7f56c"
+	guidListRole  = "7a1b0d04-d537-4e4e-8c6f-f09ca0e7f56d"
+)
+
+// recordingRole is a Role client that records the options of its listings and returns the given roles and users
+type recordingRole struct {
+	Role
+	roles []*resource.Role
+	users []*resource.User
+	opts  *cfv3.RoleListOptions
+}
+
+func (r *recordingRole) ListIncludeUsersAll(_ context.Context, opts *cfv3.RoleListOptions) ([]*resource.Role, []*resource.User, error) {
+	r.opts = opts
+	return r.roles, r.users, nil
+}
+
+func TestRoleListOptions(t *testing.T) {
+	user := &resource.User{Resource: resource.Resource{GUID: guidListUser}, Username: ptr.To("User1"), Origin: ptr.To("sap.ids")}
+	newRole := func(roleType string) *resource.Role {
+		ro := &resource.Role{Resource: resource.Resource{GUID: guidListRole}, Type: roleType}
+		ro.Relationships.User.Data = &resource.Relationship{GUID: guidListUser}
+		return ro
+	}
+
+	tests := []struct {
+		name   string
+		get    func(Role) (*resource.Role, error)
+		roles  []*resource.Role
+		users  []*resource.User
+		types  []string
+		orgs   []string
+		spaces []string
+		found  bool
+	}{
+		{
+			name: "OrgRoleOfKnownUser",
+			get: func(r Role) (*resource.Role, error) {
+				return GetOrgRole(context.Background(), r, "", v1alpha1.OrgRoleParameters{
+					OrgReference: v1alpha1.OrgReference{Org: ptr.To(guidListOrg)}, Type: v1alpha1.OrgManager, Username: "user1",
+				})
+			},
+			roles: []*resource.Role{newRole(resource.OrganizationRoleManager.String())},
+			users: []*resource.User{user},
+			types: []string{resource.OrganizationRoleManager.String()},
+			orgs:  []string{guidListOrg},
+			found: true,
+		},
+		{
+			name: "OrgRoleOfUnknownUser",
+			get: func(r Role) (*resource.Role, error) {
+				return GetOrgRole(context.Background(), r, "", v1alpha1.OrgRoleParameters{
+					OrgReference: v1alpha1.OrgReference{Org: ptr.To(guidListOrg)}, Type: v1alpha1.OrgAuditor, Username: "user1",
+				})
+			},
+			types: []string{resource.OrganizationRoleAuditor.String()},
+			orgs:  []string{guidListOrg},
+		},
+		{
+			name: "SpaceRoleOfKnownUserInOrigin",
+			get: func(r Role) (*resource.Role, error) {
+				return GetSpaceRole(context.Background(), r, "", v1alpha1.SpaceRoleParameters{
+					SpaceReference: v1alpha1.SpaceReference{Space: ptr.To(guidListSpace)}, Type: v1alpha1.SpaceDeveloper, Username: "user1", Origin: ptr.To("sap.ids"),
+				})
+			},
+			roles:  []*resource.Role{newRole(resource.SpaceRoleDeveloper.String())},
+			users:  []*resource.User{user},
+			types:  []string{resource.SpaceRoleDeveloper.String()},
+			spaces: []string{guidListSpace},
+			found:  true,
+		},
+		{
+			name: "SpaceRoleOfUserInOtherOrigin",
+			get: func(r Role) (*resource.Role, error) {
+				return GetSpaceRole(context.Background(), r, "", v1alpha1.SpaceRoleParameters{
+					SpaceReference: v1alpha1.SpaceReference{Space: ptr.To(guidListSpace)}, Type: v1alpha1.SpaceDeveloper, Username: "user1", Origin: ptr.To("uaa"),
+				})
+			},
+			roles:  []*resource.Role{newRole(resource.SpaceRoleDeveloper.String())},
+			users:  []*resource.User{user},
+			types:  []string{resource.SpaceRoleDeveloper.String()},
+			spaces: []string{guidListSpace},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &recordingRole{roles: tc.roles, users: tc.users}
+			ro, err := tc.get(r)
+			if tc.found {
+				require.NoError(t, err)
+				assert.Equal(t, guidListRole, ro.GUID)
+			} else {
+				require.Error(t, err)
+			}
+
+			// the listing selects a single org or space only, so that the shared cache serves it
+			require.NotNil(t, r.opts)
+			assert.Equal(t, tc.types, r.opts.Types.Values)
+			assert.Equal(t, tc.orgs, r.opts.OrganizationGUIDs.Values)
+			assert.Equal(t, tc.spaces, r.opts.SpaceGUIDs.Values)
+			assert.Empty(t, r.opts.UserGUIDs.Values)
+			_, cacheable := cacheScope(r.opts)
+			assert.True(t, cacheable)
+		})
+	}
+}
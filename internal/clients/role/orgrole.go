@@ -53,7 +53,7 @@ func findOrgRole(ctx context.Context, client Role, spec v1alpha1.OrgRoleParamete
 		return nil, err
 	}
 
-	return findRole(roles, users, spec.Username, ptr.Deref(spec.Origin, "sap.ids"), OrgRoleType(spec.Type).String())
+	return findRole(roles, users, spec.Username, ptr.Deref(spec.Origin, DefaultOrigin), OrgRoleType(spec.Type).String())
 }
 
 // NewOrgRoleListOptions returns a list options for the given OrgRoleParameters
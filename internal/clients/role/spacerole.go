@@ -54,7 +54,7 @@ func findSpaceRole(ctx context.Context, client Role, spec v1alpha1.SpaceRolePara
 	}
 
 	return findRole(roles, users, spec.Username,
-		ptr.Deref(spec.Origin, "sap.ids"),
+		ptr.Deref(spec.Origin, DefaultOrigin),
 		SpaceRoleType(spec.Type).String(),
 	)
 }
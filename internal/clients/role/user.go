@@ -1,6 +1,8 @@
 package role
 
 import (
+	"context"
+	"fmt"
 	"strings"
 
 	cfv3 "github.com/cloudfoundry/go-cfclient/v3/client"
@@ -8,6 +10,9 @@ import (
 	"k8s.io/utils/ptr"
 )
 
+// DefaultOrigin is the origin assumed for users that are not known to Cloud Foundry
+const DefaultOrigin = "sap.ids"
+
 // Member identifies a user by name and origin
 type Member struct {
 	// Username at the identity provider
@@ -17,6 +22,50 @@ type Member struct {
 	Origin string `json:"origin,omitempty"`
 }
 
+// ResolveOrigin returns the origin of the user with the given username.
+// If origin is unset, the origin is looked up via the Users API and must be unique. If origin is set, it must match
+// one of the origins the user is known with. Users that are not known to Cloud Foundry yet cannot be validated, in
+// which case the given origin or the default origin is returned.
+func ResolveOrigin(ctx context.Context, client Users, username string, origin *string) (string, error) {
+	opts := cfv3.NewUserListOptions()
+	opts.UserNames.EqualTo(username)
+	users, err := client.ListAll(ctx, opts)
+	if err != nil {
+		return "", err
+	}
+
+	origins := make([]string, 0, len(users))
+	for _, u := range users {
+		if strings.EqualFold(ptr.Deref(u.Username, ""), username) && u.Origin != nil {
+			origins = append(origins, *u.Origin)
+		}
+	}
+
+	if origin != nil {
+		for _, o := range origins {
+			if strings.EqualFold(o, *origin) {
+				return o, nil
+			}
+		}
+		if len(origins) > 0 {
+			return "", fmt.Errorf("user %q does not exist in origin %q, the user exists in origin(s) %s", username, *origin, strings.Join(origins, ", "))
+		}
+		return *origin, nil
+	}
+
+	switch len(origins) {
+	case 0:
+		return DefaultOrigin, nil
+	case 1:
+		return origins[0], nil
+	default:
+		return "", fmt.Errorf("user %q exists in multiple origins %s, origin must be specified", username, strings.Join(origins, ", "))
+	}
+}
+
+// findRole returns the role of the given type of the user with the given username and origin. The GUID of the user is
+// resolved from the users included in the role listing, so that a lookup needs no call of the Users API and is served
+// by the role listing of the org or space shared through the ListCache.
 func findRole(roles []*resource.Role, users []*resource.User, username, origin, roleType string) (*resource.Role, error) {
 	var userGUID string
 	for _, u := range users {
@@ -0,0 +1,165 @@
+package route
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"k8s.io/utils/ptr"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
+)
+
+// defaultProcess is the process type of a destination that does not set one.
+const defaultProcess = "web"
+
+// Destination defines the methods to manage the destinations of a route.
+type Destination interface {
+	InsertDestinations(ctx context.Context, guid string, dest []*resource.RouteDestinationInsertOrReplace) (*resource.RouteDestinations, error)
+	ReplaceDestinations(ctx context.Context, guid string, dest []*resource.RouteDestinationInsertOrReplace) (*resource.RouteDestinations, error)
+	RemoveDestination(ctx context.Context, guid, destinationGUID string) error
+	UpdateDestinationProtocol(ctx context.Context, guid, destinationGUID, protocol string) (*resource.RouteDestinationWithLinks, error)
+}
+
+// DestinationDiff is the difference between the desired and the observed
+// destinations of a route.
+type DestinationDiff struct {
+	// Add are the desired destinations that are not observed.
+	Add []v1alpha1.RouteDestinationParameters
+	// Remove are the GUIDs of the observed destinations that are not desired.
+	Remove []string
+	// Protocol are the protocols to set, by GUID of the observed destination.
+	Protocol map[string]string
+	// Weight is true if the weight of an observed destination differs.
+	Weight bool
+}
+
+// IsEmpty returns true if the destinations are up to date.
+func (d DestinationDiff) IsEmpty() bool {
+	return len(d.Add) == 0 && len(d.Remove) == 0 && len(d.Protocol) == 0 && !d.Weight
+}
+
+// DiffDestinations compares the desired destinations with the observed ones.
+// A desired destination matches an observed one with the same app, process
+// type and, if it sets one, port. Protocol and weight are only compared if
+// they are set.
+func DiffDestinations(desired []v1alpha1.RouteDestinationParameters, observed []v1alpha1.RouteDestination) DestinationDiff {
+	diff := DestinationDiff{Protocol: map[string]string{}}
+	matched := make([]bool, len(observed))
+	for _, d := range desired {
+		i := matchDestination(d, observed, matched)
+		if i < 0 {
+			diff.Add = append(diff.Add, d)
+			continue
+		}
+		matched[i] = true
+		o := observed[i]
+		if d.Protocol != nil && *d.Protocol != ptr.Deref(o.Protocol, "") {
+			diff.Protocol[o.GUID] = *d.Protocol
+		}
+		if d.Weight != nil && *d.Weight != ptr.Deref(o.Weight, 0) {
+			diff.Weight = true
+		}
+	}
+	for i, o := range observed {
+		if !matched[i] {
+			diff.Remove = append(diff.Remove, o.GUID)
+		}
+	}
+	return diff
+}
+
+// matchDestination returns the index of the first unmatched observed
+// destination that matches d, or -1 if there is none.
+func matchDestination(d v1alpha1.RouteDestinationParameters, observed []v1alpha1.RouteDestination, matched []bool) int {
+	for i, o := range observed {
+		if matched[i] || o.App == nil || o.App.GUID != ptr.Deref(d.App, "") {
+			continue
+		}
+		if ptr.Deref(o.App.Process, defaultProcess) != ptr.Deref(d.Process, defaultProcess) {
+			continue
+		}
+		if d.Port != nil && ptr.Deref(o.Port, 0) != *d.Port {
+			continue
+		}
+		return i
+	}
+	return -1
+}
+
+// UpdateDestinations reconciles the destinations of a route with the desired
+// ones. Weighted destinations can only be set all at once, so if any desired
+// destination has a weight, all destinations are replaced. Otherwise missing
+// destinations are added, unwanted ones removed and protocols updated.
+func (c *Client) UpdateDestinations(ctx context.Context, guid string, desired []v1alpha1.RouteDestinationParameters, observed []v1alpha1.RouteDestination) error {
+	diff := DiffDestinations(desired, observed)
+	if diff.IsEmpty() {
+		return nil
+	}
+
+	if isWeighted(desired) {
+		_, err := c.Destination.ReplaceDestinations(ctx, guid, formatDestinations(desired))
+		return err
+	}
+
+	for _, d := range diff.Remove {
+		if err := c.Destination.RemoveDestination(ctx, guid, d); err != nil && !clients.IsNotFound(err) {
+			return fmt.Errorf("cannot remove destination %s: %w", d, err)
+		}
+	}
+	if len(diff.Add) > 0 {
+		if _, err := c.Destination.InsertDestinations(ctx, guid, formatDestinations(diff.Add)); err != nil {
+			return fmt.Errorf("cannot add destinations: %w", err)
+		}
+	}
+	for d, protocol := range diff.Protocol {
+		if _, err := c.Destination.UpdateDestinationProtocol(ctx, guid, d, protocol); err != nil {
+			return fmt.Errorf("cannot update protocol of destination %s: %w", d, err)
+		}
+	}
+	return nil
+}
+
+// RemoveDestinations removes the given destinations from a route. Destinations
+// that are already gone are ignored.
+func (c *Client) RemoveDestinations(ctx context.Context, guid string, observed []v1alpha1.RouteDestination) error {
+	var errs []error
+	for _, o := range observed {
+		if err := c.Destination.RemoveDestination(ctx, guid, o.GUID); err != nil && !clients.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("cannot remove destination %s: %w", o.GUID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func isWeighted(desired []v1alpha1.RouteDestinationParameters) bool {
+	for _, d := range desired {
+		if d.Weight != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func formatDestinations(desired []v1alpha1.RouteDestinationParameters) []*resource.RouteDestinationInsertOrReplace {
+	dest := make([]*resource.RouteDestinationInsertOrReplace, 0, len(desired))
+	for _, d := range desired {
+		r := resource.NewRouteDestinationInsertOrReplace(ptr.Deref(d.App, ""))
+		if d.Process != nil {
+			r.WithProcessType(*d.Process)
+		}
+		if d.Port != nil {
+			r.WithPort(*d.Port)
+		}
+		if d.Protocol != nil {
+			r.WithProtocol(*d.Protocol)
+		}
+		if d.Weight != nil {
+			r.WithWeight(*d.Weight)
+		}
+		dest = append(dest, r)
+	}
+	return dest
+}
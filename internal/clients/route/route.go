@@ -23,12 +23,14 @@ type Route interface {
 
 type Client struct {
 	Route
+	Destination Destination
 }
 
 // NewClient creates a new cf client and return interfaces for Route and RouteFeatures
 func NewClient(cf *client.Client) *Client {
 	return &Client{
-		Route: cf.Routes,
+		Route:       cf.Routes,
+		Destination: cf.Routes,
 	}
 }
 
@@ -46,7 +48,7 @@ func (c *Client) GetByIDOrSpec(ctx context.Context, guid string, forProvider v1a
 		r, err = c.Route.Single(ctx, opts)
 	}
 	if err != nil {
-		if clients.ErrorIsNotFound(err) {
+		if clients.IsNotFound(err) {
 			return nil, nil
 		}
 		return nil, err
@@ -181,6 +183,8 @@ func GenerateObservation(o *resource.Route) v1alpha1.RouteObservation {
 			if d.Port != nil {
 				rd.Port = d.Port
 			}
+			rd.Protocol = d.Protocol
+			rd.Weight = d.Weight
 
 			if d.App.GUID != nil {
 				rd.App = &v1alpha1.RouteDestinationApp{GUID: *d.App.GUID}
@@ -200,8 +204,11 @@ func GenerateObservation(o *resource.Route) v1alpha1.RouteObservation {
 // IsUpToDate checks whether current state is up-to-date compared to the given
 // set of parameters.
 func IsUpToDate(forProvider v1alpha1.RouteParameters, atProvider v1alpha1.RouteObservation) bool {
-	// Routes are mostly immutable, expect for metadata
-	return true
+	// Routes are mostly immutable, expect for metadata and the destinations if they are managed
+	if len(forProvider.Destinations) == 0 {
+		return true
+	}
+	return DiffDestinations(forProvider.Destinations, atProvider.Destinations).IsEmpty()
 }
 
 func strToPtr(s string) *string {
@@ -3,11 +3,16 @@ package route
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
 	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/mock"
+	"k8s.io/utils/ptr"
 
 	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
 
 	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/fake"
@@ -350,3 +355,125 @@ func TestDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestDiffDestinations(t *testing.T) {
+	app := "app-guid"
+	other := "other-guid"
+	worker := "worker"
+	http2 := "http2"
+	observed := func(guid, app string, weight int) v1alpha1.RouteDestination {
+		return v1alpha1.RouteDestination{GUID: guid, App: &v1alpha1.RouteDestinationApp{GUID: app}, Port: ptr.To(8080), Protocol: ptr.To("http1"), Weight: &weight}
+	}
+
+	cases := map[string]struct {
+		desired  []v1alpha1.RouteDestinationParameters
+		observed []v1alpha1.RouteDestination
+		want     DestinationDiff
+	}{
+		"UpToDate": {
+			desired:  []v1alpha1.RouteDestinationParameters{{App: &app, Weight: ptr.To(100)}},
+			observed: []v1alpha1.RouteDestination{observed("d1", app, 100)},
+			want:     DestinationDiff{Protocol: map[string]string{}},
+		},
+		"WeightChanged": {
+			desired:  []v1alpha1.RouteDestinationParameters{{App: &app, Weight: ptr.To(50)}, {App: &other, Weight: ptr.To(50)}},
+			observed: []v1alpha1.RouteDestination{observed("d1", app, 70), observed("d2", other, 30)},
+			want:     DestinationDiff{Protocol: map[string]string{}, Weight: true},
+		},
+		"ProtocolChanged": {
+			desired:  []v1alpha1.RouteDestinationParameters{{App: &app, Protocol: &http2}},
+			observed: []v1alpha1.RouteDestination{observed("d1", app, 100)},
+			want:     DestinationDiff{Protocol: map[string]string{"d1": http2}},
+		},
+		"AddAndRemove": {
+			desired:  []v1alpha1.RouteDestinationParameters{{App: &app, Process: &worker}},
+			observed: []v1alpha1.RouteDestination{observed("d1", app, 100)},
+			want: DestinationDiff{
+				Add:      []v1alpha1.RouteDestinationParameters{{App: &app, Process: &worker}},
+				Remove:   []string{"d1"},
+				Protocol: map[string]string{},
+			},
+		},
+		"PortMismatch": {
+			desired:  []v1alpha1.RouteDestinationParameters{{App: &app, Port: ptr.To(9000)}},
+			observed: []v1alpha1.RouteDestination{observed("d1", app, 100)},
+			want: DestinationDiff{
+				Add:      []v1alpha1.RouteDestinationParameters{{App: &app, Port: ptr.To(9000)}},
+				Remove:   []string{"d1"},
+				Protocol: map[string]string{},
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			got := DiffDestinations(tc.desired, tc.observed)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("DiffDestinations(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdateDestinations(t *testing.T) {
+	app := "app-guid"
+	other := "other-guid"
+	observed := []v1alpha1.RouteDestination{
+		{GUID: "d1", App: &v1alpha1.RouteDestinationApp{GUID: app}, Weight: ptr.To(70)},
+		{GUID: "d2", App: &v1alpha1.RouteDestinationApp{GUID: other}, Weight: ptr.To(30)},
+	}
+
+	cases := map[string]struct {
+		desired []v1alpha1.RouteDestinationParameters
+		service func() *fake.MockRoute
+		err     error
+	}{
+		"UpToDate": {
+			desired: []v1alpha1.RouteDestinationParameters{{App: &app, Weight: ptr.To(70)}, {App: &other, Weight: ptr.To(30)}},
+			service: func() *fake.MockRoute {
+				return &fake.MockRoute{}
+			},
+		},
+		"WeightChangeReplaces": {
+			desired: []v1alpha1.RouteDestinationParameters{{App: &app, Weight: ptr.To(50)}, {App: &other, Weight: ptr.To(50)}},
+			service: func() *fake.MockRoute {
+				m := &fake.MockRoute{}
+				m.On("ReplaceDestinations", guid, []*resource.RouteDestinationInsertOrReplace{
+					resource.NewRouteDestinationInsertOrReplace(app).WithWeight(50),
+					resource.NewRouteDestinationInsertOrReplace(other).WithWeight(50),
+				}).Return(&resource.RouteDestinations{}, nil)
+				return m
+			},
+		},
+		"UnweightedAddsAndRemoves": {
+			desired: []v1alpha1.RouteDestinationParameters{{App: &app}, {App: ptr.To("new-guid")}},
+			service: func() *fake.MockRoute {
+				m := &fake.MockRoute{}
+				m.On("RemoveDestination", guid, "d2").Return(nil)
+				m.On("InsertDestinations", guid, []*resource.RouteDestinationInsertOrReplace{
+					resource.NewRouteDestinationInsertOrReplace("new-guid"),
+				}).Return(&resource.RouteDestinations{}, nil)
+				return m
+			},
+		},
+		"InsertFailed": {
+			desired: []v1alpha1.RouteDestinationParameters{{App: &app}, {App: &other}, {App: ptr.To("new-guid")}},
+			service: func() *fake.MockRoute {
+				m := &fake.MockRoute{}
+				m.On("InsertDestinations", guid, mock.Anything).Return(&resource.RouteDestinations{}, errBoom)
+				return m
+			},
+			err: fmt.Errorf("cannot add destinations: %w", errBoom),
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			m := tc.service()
+			c := &Client{Route: m, Destination: m}
+			err := c.UpdateDestinations(context.Background(), guid, tc.desired, observed)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("UpdateDestinations(...): -want error, +got error:\n%s", diff)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
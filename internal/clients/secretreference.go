@@ -37,11 +37,17 @@ func ExtractSecret(ctx context.Context, kube k8s.Client, sr *xpv1.SecretReferenc
 	}
 
 	// if key is not specified, return all data from the secret, also string or nested JSON
-	data := make(map[string]interface{})
-	for k, v := range secret.Data {
+	return dataToJSON(secret.Data)
+}
+
+// dataToJSON returns the data of a Secret or ConfigMap as a JSON object,
+// keeping values that are JSON themselves nested and all others as strings.
+func dataToJSON[V string | []byte](values map[string]V) ([]byte, error) {
+	data := make(map[string]interface{}, len(values))
+	for k, v := range values {
 		// Try to parse as JSON first
 		var jsonValue interface{}
-		if err := json.Unmarshal(v, &jsonValue); err == nil {
+		if err := json.Unmarshal([]byte(v), &jsonValue); err == nil {
 			data[k] = jsonValue
 		} else {
 			// If not JSON, store as string
@@ -0,0 +1,321 @@
+package securitygroup
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
+)
+
+// Client is the interface that defines the methods that a SecurityGroup client should implement.
+type Client interface {
+	Get(ctx context.Context, guid string) (*resource.SecurityGroup, error)
+	Single(ctx context.Context, opts *client.SecurityGroupListOptions) (*resource.SecurityGroup, error)
+	Create(ctx context.Context, r *resource.SecurityGroupCreate) (*resource.SecurityGroup, error)
+	Update(ctx context.Context, guid string, r *resource.SecurityGroupUpdate) (*resource.SecurityGroup, error)
+	Delete(ctx context.Context, guid string) (string, error)
+	BindRunningSecurityGroup(ctx context.Context, guid string, spaceGUIDs []string) ([]string, error)
+	BindStagingSecurityGroup(ctx context.Context, guid string, spaceGUIDs []string) ([]string, error)
+	UnBindRunningSecurityGroup(ctx context.Context, guid string, spaceGUID string) error
+	UnBindStagingSecurityGroup(ctx context.Context, guid string, spaceGUID string) error
+}
+
+// NewClient creates a new client instance from a cfclient.SecurityGroup instance.
+func NewClient(cf *client.Client) Client {
+	return cf.SecurityGroups
+}
+
+// GetByIDOrName returns a security group by GUID, or by name if the GUID is not valid.
+func GetByIDOrName(ctx context.Context, c Client, guid, name string) (*resource.SecurityGroup, error) {
+	if clients.IsValidGUID(guid) {
+		return c.Get(ctx, guid)
+	}
+
+	opts := client.NewSecurityGroupListOptions()
+	opts.Names.EqualTo(name)
+	return c.Single(ctx, opts)
+}
+
+// GenerateCreate generates the SecurityGroupCreate from SecurityGroupParameters.
+func GenerateCreate(spec v1alpha1.SecurityGroupParameters) *resource.SecurityGroupCreate {
+	create := &resource.SecurityGroupCreate{
+		Name:  spec.Name,
+		Rules: toRules(spec.Rules),
+	}
+	if spec.GloballyEnabledRunning != nil || spec.GloballyEnabledStaging != nil {
+		create.GloballyEnabled = &resource.SecurityGroupGloballyEnabled{
+			Running: spec.GloballyEnabledRunning,
+			Staging: spec.GloballyEnabledStaging,
+		}
+	}
+
+	relationships := map[string]resource.ToManyRelationships{}
+	if len(spec.RunningSpaces) > 0 {
+		relationships["running_spaces"] = *resource.NewToManyRelationships(toStrings(spec.RunningSpaces))
+	}
+	if len(spec.StagingSpaces) > 0 {
+		relationships["staging_spaces"] = *resource.NewToManyRelationships(toStrings(spec.StagingSpaces))
+	}
+	if len(relationships) > 0 {
+		create.Relationships = relationships
+	}
+	return create
+}
+
+// GenerateUpdate generates the SecurityGroupUpdate from SecurityGroupParameters.
+func GenerateUpdate(spec v1alpha1.SecurityGroupParameters) *resource.SecurityGroupUpdate {
+	update := &resource.SecurityGroupUpdate{
+		Name:  spec.Name,
+		Rules: toRules(spec.Rules),
+	}
+	if spec.GloballyEnabledRunning != nil || spec.GloballyEnabledStaging != nil {
+		update.GloballyEnabled = &resource.SecurityGroupGloballyEnabled{
+			Running: spec.GloballyEnabledRunning,
+			Staging: spec.GloballyEnabledStaging,
+		}
+	}
+	return update
+}
+
+// GenerateObservation takes a SecurityGroup resource and returns SecurityGroupObservation.
+func GenerateObservation(sg *resource.SecurityGroup) v1alpha1.SecurityGroupObservation {
+	obs := v1alpha1.SecurityGroupObservation{
+		ID:                     ptr.To(sg.GUID),
+		Name:                   ptr.To(sg.Name),
+		GloballyEnabledRunning: ptr.To(ptr.Deref(sg.GloballyEnabled.Running, false)),
+		GloballyEnabledStaging: ptr.To(ptr.Deref(sg.GloballyEnabled.Staging, false)),
+		RunningSpaces:          relationshipGUIDs(sg.Relationships.RunningSpaces),
+		StagingSpaces:          relationshipGUIDs(sg.Relationships.StagingSpaces),
+		CreatedAt:              ptr.To(sg.CreatedAt.Format(time.RFC3339)),
+		UpdatedAt:              ptr.To(sg.UpdatedAt.Format(time.RFC3339)),
+	}
+	for _, r := range sg.Rules {
+		obs.Rules = append(obs.Rules, v1alpha1.SecurityGroupRule{
+			Protocol:    r.Protocol,
+			Destination: r.Destination,
+			Ports:       r.Ports,
+			Type:        r.Type,
+			Code:        r.Code,
+			Description: r.Description,
+			Log:         r.Log,
+		})
+	}
+	return obs
+}
+
+// LateInitialize fills the global default flags that are not set in the spec
+// with the values observed in Cloud Foundry. It returns true if the spec was
+// changed.
+func LateInitialize(spec *v1alpha1.SecurityGroupParameters, sg *resource.SecurityGroup) bool {
+	li := false
+	if spec.GloballyEnabledRunning == nil {
+		spec.GloballyEnabledRunning = ptr.To(ptr.Deref(sg.GloballyEnabled.Running, false))
+		li = true
+	}
+	if spec.GloballyEnabledStaging == nil {
+		spec.GloballyEnabledStaging = ptr.To(ptr.Deref(sg.GloballyEnabled.Staging, false))
+		li = true
+	}
+	return li
+}
+
+// IsUpToDate checks whether the security group is up-to-date compared to the spec.
+// The space bindings are checked separately by IsSpacesUpToDate.
+func IsUpToDate(spec v1alpha1.SecurityGroupParameters, sg *resource.SecurityGroup) bool {
+	if spec.Name != sg.Name {
+		return false
+	}
+	if spec.GloballyEnabledRunning != nil && *spec.GloballyEnabledRunning != ptr.Deref(sg.GloballyEnabled.Running, false) {
+		return false
+	}
+	if spec.GloballyEnabledStaging != nil && *spec.GloballyEnabledStaging != ptr.Deref(sg.GloballyEnabled.Staging, false) {
+		return false
+	}
+	return len(spec.Rules) == 0 || IsRulesUpToDate(toRules(spec.Rules), sg.Rules)
+}
+
+// IsRulesUpToDate compares the desired and observed rules regardless of their order.
+func IsRulesUpToDate(desired []*resource.SecurityGroupRule, observed []resource.SecurityGroupRule) bool {
+	if len(desired) != len(observed) {
+		return false
+	}
+	d := make([]string, 0, len(desired))
+	for _, r := range desired {
+		d = append(d, ruleKey(*r))
+	}
+	o := make([]string, 0, len(observed))
+	for _, r := range observed {
+		o = append(o, ruleKey(r))
+	}
+	slices.Sort(d)
+	slices.Sort(o)
+	return slices.Equal(d, o)
+}
+
+// ObserveManagedSpaces records the spaces the security group is bound to
+// because of the spec in observed: the spaces in the spec, and the spaces that
+// were recorded in previous and are still bound.
+func ObserveManagedSpaces(spec v1alpha1.SecurityGroupParameters, previous v1alpha1.SecurityGroupObservation, observed *v1alpha1.SecurityGroupObservation) {
+	observed.ManagedRunningSpaces = managedSpaces(toStrings(spec.RunningSpaces), previous.ManagedRunningSpaces, toStrings(observed.RunningSpaces))
+	observed.ManagedStagingSpaces = managedSpaces(toStrings(spec.StagingSpaces), previous.ManagedStagingSpaces, toStrings(observed.StagingSpaces))
+}
+
+// IsSpacesUpToDate checks whether the security group is bound to all spaces in
+// the spec and to none of the managed spaces that were removed from the spec.
+// Spaces bound by others are ignored.
+func IsSpacesUpToDate(spec v1alpha1.SecurityGroupParameters, observed v1alpha1.SecurityGroupObservation) bool {
+	return containsGUIDs(observed.RunningSpaces, spec.RunningSpaces) &&
+		containsGUIDs(observed.StagingSpaces, spec.StagingSpaces) &&
+		len(staleSpaces(toStrings(spec.RunningSpaces), observed.ManagedRunningSpaces, toStrings(observed.RunningSpaces))) == 0 &&
+		len(staleSpaces(toStrings(spec.StagingSpaces), observed.ManagedStagingSpaces, toStrings(observed.StagingSpaces))) == 0
+}
+
+// BindSpaces binds the security group to the spaces in the spec that it is
+// not bound to yet, and unbinds it from the managed spaces that were removed
+// from the spec. Spaces bound by others are kept.
+func BindSpaces(ctx context.Context, c Client, guid string, spec v1alpha1.SecurityGroupParameters, observed v1alpha1.SecurityGroupObservation) error {
+	if err := bindSpaces(ctx, "running", c.BindRunningSecurityGroup, c.UnBindRunningSecurityGroup, guid,
+		toStrings(spec.RunningSpaces), observed.ManagedRunningSpaces, toStrings(observed.RunningSpaces)); err != nil {
+		return err
+	}
+	return bindSpaces(ctx, "staging", c.BindStagingSecurityGroup, c.UnBindStagingSecurityGroup, guid,
+		toStrings(spec.StagingSpaces), observed.ManagedStagingSpaces, toStrings(observed.StagingSpaces))
+}
+
+func bindSpaces(ctx context.Context,
+	lifecycle string,
+	bind func(ctx context.Context, guid string, spaceGUIDs []string) ([]string, error),
+	unbind func(ctx context.Context, guid string, spaceGUID string) error,
+	guid string, desired, managed, bound []string) error {
+	missing := []string{}
+	for _, space := range desired {
+		if !slices.Contains(bound, space) && !slices.Contains(missing, space) {
+			missing = append(missing, space)
+		}
+	}
+	if len(missing) > 0 {
+		if _, err := bind(ctx, guid, missing); err != nil {
+			return errors.Wrapf(err, "cannot bind %s spaces", lifecycle)
+		}
+	}
+	for _, space := range staleSpaces(desired, managed, bound) {
+		if err := unbind(ctx, guid, space); err != nil && !clients.IsNotFound(err) {
+			return errors.Wrapf(err, "cannot unbind %s space %s", lifecycle, space)
+		}
+	}
+	return nil
+}
+
+// managedSpaces returns the sorted spaces in desired and the spaces in
+// previous that are still bound.
+func managedSpaces(desired, previous, bound []string) []string {
+	managed := slices.Clone(desired)
+	for _, space := range previous {
+		if slices.Contains(bound, space) {
+			managed = append(managed, space)
+		}
+	}
+	if len(managed) == 0 {
+		return nil
+	}
+	slices.Sort(managed)
+	return slices.Compact(managed)
+}
+
+// staleSpaces returns the managed spaces that are still bound but no longer
+// desired.
+func staleSpaces(desired, managed, bound []string) []string {
+	var stale []string
+	for _, space := range managed {
+		if !slices.Contains(desired, space) && slices.Contains(bound, space) {
+			stale = append(stale, space)
+		}
+	}
+	return stale
+}
+
+func toRules(rules []v1alpha1.SecurityGroupRule) []*resource.SecurityGroupRule {
+	if len(rules) == 0 {
+		return nil
+	}
+	result := make([]*resource.SecurityGroupRule, 0, len(rules))
+	for _, r := range rules {
+		result = append(result, &resource.SecurityGroupRule{
+			Protocol:    r.Protocol,
+			Destination: r.Destination,
+			Ports:       r.Ports,
+			Type:        r.Type,
+			Code:        r.Code,
+			Description: r.Description,
+			Log:         r.Log,
+		})
+	}
+	return result
+}
+
+// ruleKey returns a comparable representation of a rule. Optional fields
+// that Cloud Foundry omits when they are empty are normalized.
+func ruleKey(r resource.SecurityGroupRule) string {
+	icmp := func(v *int) string {
+		if v == nil {
+			return ""
+		}
+		return fmt.Sprint(*v)
+	}
+	return strings.Join([]string{
+		strings.ToLower(r.Protocol),
+		r.Destination,
+		ptr.Deref(r.Ports, ""),
+		icmp(r.Type),
+		icmp(r.Code),
+		ptr.Deref(r.Description, ""),
+		fmt.Sprint(ptr.Deref(r.Log, false)),
+	}, "\x00")
+}
+
+func relationshipGUIDs(r resource.ToManyRelationships) []*string {
+	if len(r.Data) == 0 {
+		return nil
+	}
+	guids := make([]string, 0, len(r.Data))
+	for _, d := range r.Data {
+		guids = append(guids, d.GUID)
+	}
+	slices.Sort(guids)
+	result := make([]*string, len(guids))
+	for i := range guids {
+		result[i] = &guids[i]
+	}
+	return result
+}
+
+// toStrings dereferences a list of GUIDs, skipping nil entries.
+func toStrings(guids []*string) []string {
+	result := make([]string, 0, len(guids))
+	for _, g := range guids {
+		if g != nil {
+			result = append(result, *g)
+		}
+	}
+	return result
+}
+
+// containsGUIDs reports whether all GUIDs of sub are in set.
+func containsGUIDs(set, sub []*string) bool {
+	guids := toStrings(set)
+	for _, g := range toStrings(sub) {
+		if !slices.Contains(guids, g) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,298 @@
+package securitygroup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/fake"
+)
+
+const (
+	sgGUID     = "b85a788e-671f-4549-814d-e34cdb2f539a"
+	spaceGUID1 = "1d8b0d04-d537-4e4e-8c6f-f09ca0e7f56f"
+	spaceGUID2 = "2d8b0d04-d537-4e4e-8c6f-f09ca0e7f56f"
+)
+
+func TestIsRulesUpToDate(t *testing.T) {
+	https := v1alpha1.SecurityGroupRule{Protocol: "tcp", Destination: "10.0.0.0/8", Ports: ptr.To("443")}
+	ping := v1alpha1.SecurityGroupRule{Protocol: "icmp", Destination: "0.0.0.0/0", Type: ptr.To(0), Code: ptr.To(0)}
+
+	tests := []struct {
+		name     string
+		desired  []v1alpha1.SecurityGroupRule
+		observed []resource.SecurityGroupRule
+		expected bool
+	}{
+		{
+			name:     "Same rules",
+			desired:  []v1alpha1.SecurityGroupRule{https, ping},
+			observed: []resource.SecurityGroupRule{*toRules([]v1alpha1.SecurityGroupRule{https})[0], *toRules([]v1alpha1.SecurityGroupRule{ping})[0]},
+			expected: true,
+		},
+		{
+			name:     "Reordered rules",
+			desired:  []v1alpha1.SecurityGroupRule{ping, https},
+			observed: []resource.SecurityGroupRule{*toRules([]v1alpha1.SecurityGroupRule{https})[0], *toRules([]v1alpha1.SecurityGroupRule{ping})[0]},
+			expected: true,
+		},
+		{
+			name:     "Omitted defaults",
+			desired:  []v1alpha1.SecurityGroupRule{https},
+			observed: []resource.SecurityGroupRule{{Protocol: "tcp", Destination: "10.0.0.0/8", Ports: ptr.To("443"), Log: ptr.To(false), Description: ptr.To("")}},
+			expected: true,
+		},
+		{
+			name:     "Changed ports",
+			desired:  []v1alpha1.SecurityGroupRule{https},
+			observed: []resource.SecurityGroupRule{{Protocol: "tcp", Destination: "10.0.0.0/8", Ports: ptr.To("80")}},
+			expected: false,
+		},
+		{
+			name:     "Missing rule",
+			desired:  []v1alpha1.SecurityGroupRule{https, ping},
+			observed: []resource.SecurityGroupRule{*toRules([]v1alpha1.SecurityGroupRule{https})[0]},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := IsRulesUpToDate(toRules(tt.desired), tt.observed); result != tt.expected {
+				t.Errorf("IsRulesUpToDate() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsUpToDate(t *testing.T) {
+	https := v1alpha1.SecurityGroupRule{Protocol: "tcp", Destination: "10.0.0.0/8", Ports: ptr.To("443")}
+	sg := &fake.NewSecurityGroup().SetName("public").SetRules(*toRules([]v1alpha1.SecurityGroupRule{https})[0]).SecurityGroup
+
+	tests := []struct {
+		name     string
+		rules    []v1alpha1.SecurityGroupRule
+		expected bool
+	}{
+		{
+			name:     "Unmanaged rules",
+			rules:    nil,
+			expected: true,
+		},
+		{
+			name:     "Empty rules are unmanaged",
+			rules:    []v1alpha1.SecurityGroupRule{},
+			expected: true,
+		},
+		{
+			name:     "Same rules",
+			rules:    []v1alpha1.SecurityGroupRule{https},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := v1alpha1.SecurityGroupParameters{Name: "public", Rules: tt.rules}
+			if result := IsUpToDate(spec, sg); result != tt.expected {
+				t.Errorf("IsUpToDate() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLateInitialize(t *testing.T) {
+	spec := v1alpha1.SecurityGroupParameters{GloballyEnabledStaging: ptr.To(false)}
+	sg := &fake.NewSecurityGroup().SetGloballyEnabled(true, true).SecurityGroup
+
+	if !LateInitialize(&spec, sg) {
+		t.Errorf("LateInitialize() = false, want true")
+	}
+	if !ptr.Deref(spec.GloballyEnabledRunning, false) {
+		t.Errorf("LateInitialize() did not set globallyEnabledRunning")
+	}
+	if ptr.Deref(spec.GloballyEnabledStaging, true) {
+		t.Errorf("LateInitialize() overwrote globallyEnabledStaging")
+	}
+	if LateInitialize(&spec, sg) {
+		t.Errorf("LateInitialize() = true on initialized spec, want false")
+	}
+}
+
+func TestIsSpacesUpToDate(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     v1alpha1.SecurityGroupParameters
+		managed  []string
+		expected bool
+	}{
+		{
+			name:     "Unmanaged bindings",
+			spec:     v1alpha1.SecurityGroupParameters{},
+			expected: true,
+		},
+		{
+			name:     "Spaces bound by others",
+			spec:     v1alpha1.SecurityGroupParameters{RunningSpaces: []*string{ptr.To(spaceGUID1)}},
+			expected: true,
+		},
+		{
+			name:     "Missing binding",
+			spec:     v1alpha1.SecurityGroupParameters{StagingSpaces: []*string{ptr.To(spaceGUID1)}},
+			expected: false,
+		},
+		{
+			name:     "Removed managed binding",
+			spec:     v1alpha1.SecurityGroupParameters{RunningSpaces: []*string{ptr.To(spaceGUID1)}},
+			managed:  []string{spaceGUID1, spaceGUID2},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			observed := v1alpha1.SecurityGroupObservation{
+				RunningSpaces:        []*string{ptr.To(spaceGUID1), ptr.To(spaceGUID2)},
+				ManagedRunningSpaces: tt.managed,
+			}
+			if result := IsSpacesUpToDate(tt.spec, observed); result != tt.expected {
+				t.Errorf("IsSpacesUpToDate() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBindSpaces(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     v1alpha1.SecurityGroupParameters
+		observed v1alpha1.SecurityGroupObservation
+		mock     func() *fake.MockSecurityGroup
+		wantErr  bool
+	}{
+		{
+			name:     "Unmanaged bindings",
+			spec:     v1alpha1.SecurityGroupParameters{},
+			observed: v1alpha1.SecurityGroupObservation{RunningSpaces: []*string{ptr.To(spaceGUID1)}},
+			mock:     func() *fake.MockSecurityGroup { return &fake.MockSecurityGroup{} },
+		},
+		{
+			name:     "Bind missing spaces",
+			spec:     v1alpha1.SecurityGroupParameters{RunningSpaces: []*string{ptr.To(spaceGUID2)}, StagingSpaces: []*string{ptr.To(spaceGUID1)}},
+			observed: v1alpha1.SecurityGroupObservation{StagingSpaces: []*string{ptr.To(spaceGUID1)}},
+			mock: func() *fake.MockSecurityGroup {
+				m := &fake.MockSecurityGroup{}
+				m.On("BindRunningSecurityGroup", sgGUID, []string{spaceGUID2}).Return(nil)
+				return m
+			},
+		},
+		{
+			name:     "Keep spaces bound by others",
+			spec:     v1alpha1.SecurityGroupParameters{RunningSpaces: []*string{ptr.To(spaceGUID2)}, StagingSpaces: []*string{}},
+			observed: v1alpha1.SecurityGroupObservation{RunningSpaces: []*string{ptr.To(spaceGUID1), ptr.To(spaceGUID2)}, StagingSpaces: []*string{ptr.To(spaceGUID1)}},
+			mock:     func() *fake.MockSecurityGroup { return &fake.MockSecurityGroup{} },
+		},
+		{
+			name: "Unbind removed spaces",
+			spec: v1alpha1.SecurityGroupParameters{RunningSpaces: []*string{ptr.To(spaceGUID1)}},
+			observed: v1alpha1.SecurityGroupObservation{
+				RunningSpaces:        []*string{ptr.To(spaceGUID1), ptr.To(spaceGUID2)},
+				StagingSpaces:        []*string{ptr.To(spaceGUID1), ptr.To(spaceGUID2)},
+				ManagedRunningSpaces: []string{spaceGUID1, spaceGUID2},
+				ManagedStagingSpaces: []string{spaceGUID2},
+			},
+			mock: func() *fake.MockSecurityGroup {
+				m := &fake.MockSecurityGroup{}
+				m.On("UnBindRunningSecurityGroup", sgGUID, spaceGUID2).Return(nil)
+				m.On("UnBindStagingSecurityGroup", sgGUID, spaceGUID2).Return(nil)
+				return m
+			},
+		},
+		{
+			name: "Unbind error",
+			spec: v1alpha1.SecurityGroupParameters{},
+			observed: v1alpha1.SecurityGroupObservation{
+				StagingSpaces:        []*string{ptr.To(spaceGUID1)},
+				ManagedStagingSpaces: []string{spaceGUID1},
+			},
+			mock: func() *fake.MockSecurityGroup {
+				m := &fake.MockSecurityGroup{}
+				m.On("UnBindStagingSecurityGroup", sgGUID, spaceGUID1).Return(errors.New("boom"))
+				return m
+			},
+			wantErr: true,
+		},
+		{
+			name:     "Bind error",
+			spec:     v1alpha1.SecurityGroupParameters{RunningSpaces: []*string{ptr.To(spaceGUID1)}},
+			observed: v1alpha1.SecurityGroupObservation{},
+			mock: func() *fake.MockSecurityGroup {
+				m := &fake.MockSecurityGroup{}
+				m.On("BindRunningSecurityGroup", sgGUID, []string{spaceGUID1}).Return(errors.New("boom"))
+				return m
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.mock()
+			err := BindSpaces(context.Background(), m, sgGUID, tt.spec, tt.observed)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BindSpaces() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestObserveManagedSpaces(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     v1alpha1.SecurityGroupParameters
+		previous []string
+		bound    []*string
+		want     []string
+	}{
+		{
+			name:  "Unmanaged bindings",
+			bound: []*string{ptr.To(spaceGUID1)},
+		},
+		{
+			name:  "Desired spaces",
+			spec:  v1alpha1.SecurityGroupParameters{RunningSpaces: []*string{ptr.To(spaceGUID2), ptr.To(spaceGUID1)}},
+			bound: []*string{ptr.To(spaceGUID1)},
+			want:  []string{spaceGUID1, spaceGUID2},
+		},
+		{
+			name:     "Removed space still bound",
+			previous: []string{spaceGUID1, spaceGUID2},
+			spec:     v1alpha1.SecurityGroupParameters{RunningSpaces: []*string{ptr.To(spaceGUID1)}},
+			bound:    []*string{ptr.To(spaceGUID1), ptr.To(spaceGUID2)},
+			want:     []string{spaceGUID1, spaceGUID2},
+		},
+		{
+			name:     "Removed space unbound",
+			previous: []string{spaceGUID1, spaceGUID2},
+			spec:     v1alpha1.SecurityGroupParameters{RunningSpaces: []*string{ptr.To(spaceGUID1)}},
+			bound:    []*string{ptr.To(spaceGUID1)},
+			want:     []string{spaceGUID1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			observed := v1alpha1.SecurityGroupObservation{RunningSpaces: tt.bound}
+			ObserveManagedSpaces(tt.spec, v1alpha1.SecurityGroupObservation{ManagedRunningSpaces: tt.previous}, &observed)
+			if diff := cmp.Diff(tt.want, observed.ManagedRunningSpaces); diff != "" {
+				t.Errorf("ObserveManagedSpaces(): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
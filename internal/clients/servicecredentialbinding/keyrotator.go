@@ -11,6 +11,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
 )
 
 const ForceRotationKey = "servicecredentialbinding.cloudfoundry.crossplane.io/force-rotation"
@@ -88,9 +89,7 @@ func (c *SCBKeyRotator) DeleteExpiredKeys(ctx context.Context, cr *v1alpha1.Serv
 			key.GUID == meta.GetExternalName(cr) {
 			newRetiredKeys = append(newRetiredKeys, key)
 
-		} else if err := Delete(ctx, c.SCBClient, key.GUID); err != nil &&
-			!cfresource.IsResourceNotFoundError(err) &&
-			!cfresource.IsServiceBindingNotFoundError(err) {
+		} else if err := Delete(ctx, c.SCBClient, key.GUID); err != nil && !clients.IsNotFound(err) {
 
 			// If we cannot delete the key, keep it in the list
 			newRetiredKeys = append(newRetiredKeys, key)
@@ -103,9 +102,7 @@ func (c *SCBKeyRotator) DeleteExpiredKeys(ctx context.Context, cr *v1alpha1.Serv
 
 func (c *SCBKeyRotator) DeleteRetiredKeys(ctx context.Context, cr *v1alpha1.ServiceCredentialBinding) error {
 	for _, retiredKey := range cr.Status.AtProvider.RetiredKeys {
-		if err := Delete(ctx, c.SCBClient, retiredKey.GUID); err != nil &&
-			!cfresource.IsResourceNotFoundError(err) &&
-			!cfresource.IsServiceBindingNotFoundError(err) {
+		if err := Delete(ctx, c.SCBClient, retiredKey.GUID); err != nil && !clients.IsNotFound(err) {
 			return fmt.Errorf("cannot delete retired key %s: %w", retiredKey.GUID, err)
 		}
 	}
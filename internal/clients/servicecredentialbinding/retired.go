@@ -0,0 +1,72 @@
+package servicecredentialbinding
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// RetiredLabel marks a key that this provider retired during a rotation.
+	RetiredLabel = "cloudfoundry.crossplane.io/retired"
+	// RetiredAtLabel holds the time a key was retired, in seconds since the epoch.
+	RetiredAtLabel = "cloudfoundry.crossplane.io/retired-at"
+	// OwnerUIDLabel holds the UID of the ServiceCredentialBinding that retired a key.
+	OwnerUIDLabel = "cloudfoundry.crossplane.io/owner-uid"
+	// ProviderConfigUIDLabel holds the UID of the ProviderConfig a key was
+	// retired with. It tells the keys retired by this cluster apart from the
+	// keys retired by other clusters managing the same foundation.
+	ProviderConfigUIDLabel = "cloudfoundry.crossplane.io/provider-config-uid"
+)
+
+// LabelRetired labels a retired key with its owner, the ProviderConfig it was
+// retired with and the time it was retired, so that it can be garbage-collected
+// if its owner disappears without deleting it.
+func LabelRetired(ctx context.Context, scbClient ServiceCredentialBinding, guid string, owner, providerConfig types.UID, now time.Time) error {
+	_, err := scbClient.Update(ctx, guid, &resource.ServiceCredentialBindingUpdate{
+		Metadata: &resource.Metadata{Labels: map[string]*string{
+			RetiredLabel:           ptr.To("true"),
+			RetiredAtLabel:         ptr.To(strconv.FormatInt(now.Unix(), 10)),
+			OwnerUIDLabel:          ptr.To(string(owner)),
+			ProviderConfigUIDLabel: ptr.To(string(providerConfig)),
+		}},
+	})
+	return err
+}
+
+// ListRetired returns all keys labeled as retired with the given ProviderConfig.
+func ListRetired(ctx context.Context, scbClient ServiceCredentialBinding, providerConfig types.UID) ([]*resource.ServiceCredentialBinding, error) {
+	opts := client.NewServiceCredentialBindingListOptions()
+	opts.LabelSel = client.LabelSelector{}
+	opts.LabelSel.EqualTo(RetiredLabel, "true")
+	opts.LabelSel.EqualTo(ProviderConfigUIDLabel, string(providerConfig))
+	return scbClient.ListAll(ctx, opts)
+}
+
+// OrphanedKeys returns the GUIDs of the retired keys whose owner is not among
+// owners and that were retired with providerConfig longer than retention ago.
+// Keys without an owner or a valid retirement time, and keys retired with
+// another ProviderConfig, e.g. of another cluster, are never returned.
+func OrphanedKeys(keys []*resource.ServiceCredentialBinding, providerConfig types.UID, owners map[types.UID]bool, retention time.Duration, now time.Time) []string {
+	var orphaned []string
+	for _, key := range keys {
+		if key.Metadata == nil || ptr.Deref(key.Metadata.Labels[ProviderConfigUIDLabel], "") != string(providerConfig) {
+			continue
+		}
+		owner := ptr.Deref(key.Metadata.Labels[OwnerUIDLabel], "")
+		if owner == "" || owners[types.UID(owner)] {
+			continue
+		}
+		retiredAt, err := strconv.ParseInt(ptr.Deref(key.Metadata.Labels[RetiredAtLabel], ""), 10, 64)
+		if err != nil || time.Unix(retiredAt, 0).Add(retention).After(now) {
+			continue
+		}
+		orphaned = append(orphaned, key.GUID)
+	}
+	return orphaned
+}
@@ -0,0 +1,79 @@
+package servicecredentialbinding
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	cfresource "github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+const providerConfigUID = "provider-config"
+
+func retiredKey(guid, owner string, retiredAt time.Time) *cfresource.ServiceCredentialBinding {
+	key := &cfresource.ServiceCredentialBinding{Metadata: &cfresource.Metadata{Labels: map[string]*string{
+		RetiredLabel:           ptr.To("true"),
+		RetiredAtLabel:         ptr.To(strconv.FormatInt(retiredAt.Unix(), 10)),
+		OwnerUIDLabel:          ptr.To(owner),
+		ProviderConfigUIDLabel: ptr.To(providerConfigUID),
+	}}}
+	key.GUID = guid
+	return key
+}
+
+func TestOrphanedKeys(t *testing.T) {
+	now := time.Now()
+	retention := 24 * time.Hour
+	owners := map[types.UID]bool{"live": true}
+
+	unparsable := retiredKey("unparsable", "gone", now)
+	unparsable.Metadata.Labels[RetiredAtLabel] = ptr.To("yesterday")
+	unowned := retiredKey("unowned", "", now.Add(-48*time.Hour))
+	unlabeled := &cfresource.ServiceCredentialBinding{}
+	unlabeled.GUID = "unlabeled"
+	otherCluster := retiredKey("other-cluster", "gone", now.Add(-48*time.Hour))
+	otherCluster.Metadata.Labels[ProviderConfigUIDLabel] = ptr.To("other-provider-config")
+	unknownCluster := retiredKey("unknown-cluster", "gone", now.Add(-48*time.Hour))
+	delete(unknownCluster.Metadata.Labels, ProviderConfigUIDLabel)
+
+	cases := map[string]struct {
+		keys []*cfresource.ServiceCredentialBinding
+		want []string
+	}{
+		"OrphanPastRetention": {
+			keys: []*cfresource.ServiceCredentialBinding{retiredKey("orphan", "gone", now.Add(-25*time.Hour))},
+			want: []string{"orphan"},
+		},
+		"OrphanWithinRetention": {
+			keys: []*cfresource.ServiceCredentialBinding{retiredKey("recent", "gone", now.Add(-time.Hour))},
+		},
+		"OwnerExists": {
+			keys: []*cfresource.ServiceCredentialBinding{retiredKey("owned", "live", now.Add(-48*time.Hour))},
+		},
+		"RetiredWithOtherProviderConfig": {
+			keys: []*cfresource.ServiceCredentialBinding{otherCluster, unknownCluster},
+		},
+		"InvalidLabels": {
+			keys: []*cfresource.ServiceCredentialBinding{unparsable, unowned, unlabeled},
+		},
+		"Mixed": {
+			keys: []*cfresource.ServiceCredentialBinding{
+				retiredKey("a", "gone", now.Add(-48*time.Hour)),
+				retiredKey("owned", "live", now.Add(-48*time.Hour)),
+				retiredKey("b", "other", now.Add(-30*time.Hour)),
+			},
+			want: []string{"a", "b"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := OrphanedKeys(tc.keys, providerConfigUID, owners, retention, now)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("OrphanedKeys(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
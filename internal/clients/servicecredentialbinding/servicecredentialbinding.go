@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"math/rand"
+	"net/url"
 	"strings"
 	"sync"
 	"time"
@@ -15,6 +16,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/job"
 )
 
@@ -23,13 +25,21 @@ const (
 	ErrAppMissing             = "app is required for app binding"
 	ErrNameMissing            = "name is required for key binding"
 	ErrBindingTypeUnknown     = "unknown binding type. supported types are key and app"
+	ErrAmbiguousBinding       = "more than one binding matches the spec, set the external name to the GUID of the binding to adopt: "
 )
 
+// ConnectionDetailBindingGUID is the connection detail of a key binding that
+// holds the GUID of the binding, so that consumers of the connection secret
+// can tell when its credentials are rotated.
+const ConnectionDetailBindingGUID = "binding-guid"
+
 // serviceCredentialBinding defines interfaces to CloudFoundry ServiceCredentialBinding resource
 type serviceCredentialBinding interface {
 	Get(ctx context.Context, guid string) (*resource.ServiceCredentialBinding, error)
 	GetDetails(ctx context.Context, guid string) (*resource.ServiceCredentialBindingDetails, error)
 	GetParameters(ctx context.Context, guid string) (map[string]string, error)
+	List(ctx context.Context, opts *client.ServiceCredentialBindingListOptions) ([]*resource.ServiceCredentialBinding, *client.Pager, error)
+	ListAll(ctx context.Context, opts *client.ServiceCredentialBindingListOptions) ([]*resource.ServiceCredentialBinding, error)
 	Single(ctx context.Context, opts *client.ServiceCredentialBindingListOptions) (*resource.ServiceCredentialBinding, error)
 	Create(ctx context.Context, r *resource.ServiceCredentialBindingCreate) (string, *resource.ServiceCredentialBinding, error)
 	Update(ctx context.Context, guid string, r *resource.ServiceCredentialBindingUpdate) (*resource.ServiceCredentialBinding, error)
@@ -50,14 +60,32 @@ func NewClient(cfv3 *client.Client) ServiceCredentialBinding {
 	}{cfv3.ServiceCredentialBindings, cfv3.Jobs}
 }
 
-// GetByIDOrSearch returns a ServiceCredentialBinding resource by guid or by spec
+// GetByIDOrSearch returns a ServiceCredentialBinding resource by guid or, if the guid is
+// not set, by the type, name, app and service instance of the spec, so that an existing
+// binding can be adopted. A search matching more than one binding returns an error
+// instead of picking one of them.
 func GetByIDOrSearch(ctx context.Context, scbClient ServiceCredentialBinding, guid string, forProvider v1alpha1.ServiceCredentialBindingParameters) (*resource.ServiceCredentialBinding, error) {
 	if err := uuid.Validate(guid); err != nil {
 		opts, err := newListOptions(forProvider)
 		if err != nil {
 			return nil, err
 		}
-		return scbClient.Single(ctx, opts)
+		bindings, _, err := scbClient.List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		switch len(bindings) {
+		case 0:
+			return nil, client.ErrNoResultsReturned
+		case 1:
+			return bindings[0], nil
+		default:
+			guids := make([]string, 0, len(bindings))
+			for _, b := range bindings {
+				guids = append(guids, b.GUID)
+			}
+			return nil, errors.New(ErrAmbiguousBinding + strings.Join(guids, ", "))
+		}
 	}
 
 	return scbClient.Get(ctx, guid)
@@ -77,14 +105,22 @@ func Create(ctx context.Context, scbClient ServiceCredentialBinding, forProvider
 	}
 
 	if jobGUID != "" { // async creation waits for the job to complete
-		if err := job.PollJobComplete(ctx, scbClient, jobGUID); err != nil {
+		if err := job.PollJobComplete(ctx, scbClient, jobGUID); err != nil && !isPollTimeout(err) {
 			return nil, err
 		}
 	}
 
+	// after a poll timeout the binding is still in progress, Observe resumes from its last operation
 	return scbClient.Single(ctx, createToListOptions(opt))
 }
 
+// isPollTimeout returns true if polling a job was cut short by an http client timeout,
+// which says nothing about the outcome of the job itself.
+func isPollTimeout(err error) bool {
+	urlErr := &url.Error{}
+	return errors.As(err, &urlErr) && urlErr.Timeout()
+}
+
 // Update updates labels and annotations of a ServiceCredentialBinding resource
 func Update(ctx context.Context, scbClient ServiceCredentialBinding, guid string, forProvider v1alpha1.ServiceCredentialBindingParameters) (*resource.ServiceCredentialBinding, error) {
 	opt := newUpdateOption(forProvider)
@@ -97,28 +133,55 @@ func Delete(ctx context.Context, scbClient ServiceCredentialBinding, guid string
 	return err
 }
 
-// GetConnectionDetails returns the connection details of the ServiceCredentialBinding details
-func GetConnectionDetails(ctx context.Context, scbClient ServiceCredentialBinding, guid string, asJSON bool) managed.ConnectionDetails {
+// DeleteFailed deletes a ServiceCredentialBinding resource whose creation failed and waits for the deletion to complete,
+// so that the failed binding does not linger in Cloud Foundry or conflict with the binding created next
+func DeleteFailed(ctx context.Context, scbClient ServiceCredentialBinding, guid string) error {
+	jobGUID, err := scbClient.Delete(ctx, guid)
+	if err != nil {
+		return clients.IgnoreNotFoundErr(err)
+	}
+
+	if jobGUID != "" {
+		return job.PollJobComplete(ctx, scbClient, jobGUID)
+	}
+	return nil
+}
+
+// IsCreateFailed returns true if the last operation recorded in the observation is a failed create
+func IsCreateFailed(observation v1alpha1.ServiceCredentialBindingObservation) bool {
+	return observation.GUID != "" &&
+		observation.LastOperation != nil &&
+		observation.LastOperation.Type == v1alpha1.LastOperationCreate &&
+		observation.LastOperation.State == v1alpha1.LastOperationFailed
+}
+
+// GetConnectionDetails returns the connection details of the ServiceCredentialBinding details,
+// including a connection detail for each of the given templates. A template that cannot be
+// rendered is omitted and reported by the returned error.
+func GetConnectionDetails(ctx context.Context, scbClient ServiceCredentialBinding, guid string, asJSON bool, templates map[string]string) (managed.ConnectionDetails, error) {
 	bindingDetails, err := scbClient.GetDetails(ctx, guid)
 	if err != nil {
-		return nil
+		return nil, nil
 	}
 
 	connectDetails := managed.ConnectionDetails{}
+	creds := clients.NormalizeMap(bindingDetails.Credentials, make(map[string]string), "", "_")
 	if asJSON {
+		// json.Marshal sorts the keys of maps at every level, so the secret
+		// does not change between reconciles unless the credentials do.
 		jsonCredentials, err := json.Marshal(bindingDetails.Credentials)
 		if err != nil {
-			return nil
+			return nil, nil
 		}
 		connectDetails["credentials"] = jsonCredentials
-		return connectDetails
+		return connectDetails, renderTemplates(connectDetails, templates, creds)
 	}
 
-	for key, value := range normalizeMap(bindingDetails.Credentials, make(map[string]string), "", "_") {
+	for key, value := range creds {
 		connectDetails[key] = []byte(value)
 	}
 
-	return connectDetails
+	return connectDetails, renderTemplates(connectDetails, templates, creds)
 }
 
 // newListOptions generates ServiceCredentialBindingListOptions according to CR's ForProvider spec
@@ -137,6 +200,9 @@ func newListOptions(forProvider v1alpha1.ServiceCredentialBindingParameters) (*c
 			return nil, errors.New(ErrAppMissing)
 		}
 		opt.AppGUIDs.EqualTo(*forProvider.App)
+		if forProvider.Name != nil {
+			opt.Names.EqualTo(*forProvider.Name)
+		}
 	}
 
 	if forProvider.Type == "key" {
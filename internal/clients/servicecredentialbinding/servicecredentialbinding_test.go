@@ -29,14 +29,16 @@ var (
 
 func TestGetConnectionDetails(t *testing.T) {
 	type args struct {
-		ctx    context.Context
-		client ServiceCredentialBinding
-		guid   string
-		asJSON bool
+		ctx       context.Context
+		client    ServiceCredentialBinding
+		guid      string
+		asJSON    bool
+		templates map[string]string
 	}
 
 	type want struct {
 		details managed.ConnectionDetails
+		err     string
 	}
 
 	testCredentials := map[string]interface{}{
@@ -79,6 +81,45 @@ func TestGetConnectionDetails(t *testing.T) {
 				},
 			},
 		},
+		"Template": {
+			args: args{
+				ctx: context.Background(),
+				client: createMockClientWithDetails(map[string]interface{}{
+					"hostname": "db.example.com",
+					"port":     float64(5432),
+					"dbname":   "orders",
+				}, nil),
+				guid:      testGUID,
+				templates: map[string]string{"jdbcUrl": "jdbc:postgresql://{hostname}:{port}/{dbname}"},
+			},
+			want: want{
+				details: managed.ConnectionDetails{
+					"hostname": []byte("db.example.com"),
+					"port":     []byte("5432"),
+					"dbname":   []byte("orders"),
+					"jdbcUrl":  []byte("jdbc:postgresql://db.example.com:5432/orders"),
+				},
+			},
+		},
+		"TemplateMissingField": {
+			args: args{
+				ctx:    context.Background(),
+				client: createMockClientWithDetails(testCredentials, nil),
+				guid:   testGUID,
+				asJSON: true,
+				templates: map[string]string{
+					"user":    "{username}@{nested_key}",
+					"jdbcUrl": "jdbc:postgresql://{hostname}:{port}/{dbname}",
+				},
+			},
+			want: want{
+				details: managed.ConnectionDetails{
+					"credentials": []byte(`{"nested":{"key":"value"},"password":"testpass","username":"testuser"}`),
+					"user":        []byte("testuser@value"),
+				},
+				err: "cannot render connection detail templates: jdbcUrl (missing hostname, port, dbname)",
+			},
+		},
 		"GetDetailsError": {
 			args: args{
 				ctx:    context.Background(),
@@ -94,7 +135,14 @@ func TestGetConnectionDetails(t *testing.T) {
 
 	for n, tc := range cases {
 		t.Run(n, func(t *testing.T) {
-			details := GetConnectionDetails(tc.args.ctx, tc.args.client, tc.args.guid, tc.args.asJSON)
+			details, err := GetConnectionDetails(tc.args.ctx, tc.args.client, tc.args.guid, tc.args.asJSON, tc.args.templates)
+			gotErr := ""
+			if err != nil {
+				gotErr = err.Error()
+			}
+			if diff := cmp.Diff(tc.want.err, gotErr); diff != "" {
+				t.Errorf("GetConnectionDetails(...): -want error, +got error:\n%s", diff)
+			}
 
 			if tc.want.details == nil {
 				if details != nil {
@@ -109,6 +157,114 @@ func TestGetConnectionDetails(t *testing.T) {
 	}
 }
 
+func TestGetConnectionDetailsStableJSON(t *testing.T) {
+	credentials := map[string]interface{}{
+		"username": "testuser",
+		"password": "testpass",
+		"uri":      "postgres://db.example.com:5432",
+		"nested": map[string]interface{}{
+			"zone":   "eu10",
+			"region": "eu",
+			"deeper": map[string]interface{}{"b": "2", "a": "1"},
+		},
+	}
+	want := `{"nested":{"deeper":{"a":"1","b":"2"},"region":"eu","zone":"eu10"},"password":"testpass","uri":"postgres://db.example.com:5432","username":"testuser"}`
+
+	for i := 0; i < 10; i++ {
+		details, err := GetConnectionDetails(context.Background(), createMockClientWithDetails(credentials, nil), testGUID, true, nil)
+		if err != nil {
+			t.Fatalf("GetConnectionDetails(...): unexpected error: %v", err)
+		}
+		if diff := cmp.Diff(want, string(details["credentials"])); diff != "" {
+			t.Fatalf("GetConnectionDetails(...): run %d: -want credentials, +got:\n%s", i, diff)
+		}
+	}
+}
+
+func TestGetByIDOrSearch(t *testing.T) {
+	validGUID := "2d8b0d04-d537-4e4e-8c6f-f09ca0e7f56f"
+	forProvider := v1alpha1.ServiceCredentialBindingParameters{
+		Type:            "key",
+		Name:            &testName,
+		ServiceInstance: &testServiceInstance,
+	}
+
+	type want struct {
+		guid string
+		err  error
+	}
+
+	cases := map[string]struct {
+		guid    string
+		service func() *fake.MockServiceCredentialBinding
+		want    want
+	}{
+		"ByGUID": {
+			guid: validGUID,
+			service: func() *fake.MockServiceCredentialBinding {
+				m := &fake.MockServiceCredentialBinding{}
+				m.On("Get", mock.Anything, validGUID).Return(&cfresource.ServiceCredentialBinding{Resource: cfresource.Resource{GUID: validGUID}}, nil)
+				return m
+			},
+			want: want{guid: validGUID},
+		},
+		"SearchSingleMatch": {
+			service: func() *fake.MockServiceCredentialBinding {
+				m := &fake.MockServiceCredentialBinding{}
+				m.On("List", mock.Anything, mock.Anything).Return([]*cfresource.ServiceCredentialBinding{{Resource: cfresource.Resource{GUID: testGUID}}}, nil)
+				return m
+			},
+			want: want{guid: testGUID},
+		},
+		"SearchNoMatch": {
+			service: func() *fake.MockServiceCredentialBinding {
+				m := &fake.MockServiceCredentialBinding{}
+				m.On("List", mock.Anything, mock.Anything).Return([]*cfresource.ServiceCredentialBinding{}, nil)
+				return m
+			},
+			want: want{err: client.ErrNoResultsReturned},
+		},
+		"SearchAmbiguous": {
+			service: func() *fake.MockServiceCredentialBinding {
+				m := &fake.MockServiceCredentialBinding{}
+				m.On("List", mock.Anything, mock.Anything).Return([]*cfresource.ServiceCredentialBinding{
+					{Resource: cfresource.Resource{GUID: "guid-1"}},
+					{Resource: cfresource.Resource{GUID: "guid-2"}},
+				}, nil)
+				return m
+			},
+			want: want{err: errors.New(ErrAmbiguousBinding + "guid-1, guid-2")},
+		},
+		"SearchFailed": {
+			service: func() *fake.MockServiceCredentialBinding {
+				m := &fake.MockServiceCredentialBinding{}
+				m.On("List", mock.Anything, mock.Anything).Return([]*cfresource.ServiceCredentialBinding(nil), errBoom)
+				return m
+			},
+			want: want{err: errBoom},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			r, err := GetByIDOrSearch(context.Background(), tc.service(), tc.guid, forProvider)
+
+			if tc.want.err != nil && err != nil {
+				if diff := cmp.Diff(tc.want.err.Error(), err.Error()); diff != "" {
+					t.Errorf("GetByIDOrSearch(...): want error string != got error string:\n%s", diff)
+				}
+			} else if diff := cmp.Diff(tc.want.err, err); diff != "" {
+				t.Errorf("GetByIDOrSearch(...): want error != got error:\n%s", diff)
+			}
+			if r != nil {
+				if diff := cmp.Diff(tc.want.guid, r.GUID); diff != "" {
+					t.Errorf("GetByIDOrSearch(...): -want, +got:\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
 func TestNewListOptions(t *testing.T) {
 	type args struct {
 		forProvider v1alpha1.ServiceCredentialBindingParameters
@@ -161,6 +317,27 @@ func TestNewListOptions(t *testing.T) {
 				err: nil,
 			},
 		},
+		"NamedAppBinding": {
+			args: args{
+				forProvider: v1alpha1.ServiceCredentialBindingParameters{
+					Type:            "app",
+					Name:            &testName,
+					App:             &testApp,
+					ServiceInstance: &testServiceInstance,
+				},
+			},
+			want: want{
+				opts: func() *client.ServiceCredentialBindingListOptions {
+					opts := client.NewServiceCredentialBindingListOptions()
+					opts.Type.EqualTo("app")
+					opts.ServiceInstanceGUIDs.EqualTo(testServiceInstance)
+					opts.AppGUIDs.EqualTo(testApp)
+					opts.Names.EqualTo(testName)
+					return opts
+				}(),
+				err: nil,
+			},
+		},
 		"MissingServiceInstance": {
 			args: args{
 				forProvider: v1alpha1.ServiceCredentialBindingParameters{
@@ -222,6 +399,9 @@ func TestNewListOptions(t *testing.T) {
 				if opts.ServiceInstanceGUIDs.Values[0] != tc.want.opts.ServiceInstanceGUIDs.Values[0] {
 					t.Errorf("newListOptions(...): ServiceInstanceGUIDs mismatch, want %s, got %s", tc.want.opts.ServiceInstanceGUIDs.Values[0], opts.ServiceInstanceGUIDs.Values[0])
 				}
+				if diff := cmp.Diff(tc.want.opts.Names.Values, opts.Names.Values); diff != "" {
+					t.Errorf("newListOptions(...): Names -want, +got:\n%s", diff)
+				}
 			}
 		})
 	}
@@ -0,0 +1,80 @@
+package servicecredentialbinding
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TypeConnectionDetailTemplates is the condition type that reports whether the
+// connection detail templates of a binding could be rendered.
+const TypeConnectionDetailTemplates xpv1.ConditionType = "ConnectionDetailTemplates"
+
+// Reasons of the ConnectionDetailTemplates condition.
+const (
+	ReasonTemplatesRendered xpv1.ConditionReason = "Rendered"
+	ReasonMissingField      xpv1.ConditionReason = "MissingField"
+)
+
+// templateField matches a reference to a credential field in a connection
+// detail template, e.g. {host}.
+var templateField = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// renderTemplates adds a connection detail for each of the given templates,
+// rendered against the flattened credentials of a binding. A template that
+// references a missing credential field is omitted; the returned error lists
+// the omitted templates.
+func renderTemplates(details managed.ConnectionDetails, templates map[string]string, creds map[string]string) error {
+	var failed []string
+	for key, tmpl := range templates {
+		var missing []string
+		value := templateField.ReplaceAllStringFunc(tmpl, func(ref string) string {
+			field := ref[1 : len(ref)-1]
+			v, ok := creds[field]
+			if !ok {
+				missing = append(missing, field)
+			}
+			return v
+		})
+		if len(missing) > 0 {
+			failed = append(failed, fmt.Sprintf("%s (missing %s)", key, strings.Join(missing, ", ")))
+			continue
+		}
+		details[key] = []byte(value)
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	slices.Sort(failed)
+	return fmt.Errorf("cannot render connection detail templates: %s", strings.Join(failed, "; "))
+}
+
+// TemplatesRendered returns a condition that indicates all connection detail
+// templates of a binding were rendered.
+func TemplatesRendered() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeConnectionDetailTemplates,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonTemplatesRendered,
+	}
+}
+
+// TemplatesMissingField returns a condition that indicates some connection
+// detail templates of a binding were omitted because they reference a
+// credential field the binding does not have.
+func TemplatesMissingField(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeConnectionDetailTemplates,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonMissingField,
+		Message:            err.Error(),
+	}
+}
@@ -0,0 +1,165 @@
+package serviceinstance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/job"
+)
+
+// TypeDeletionBlocked is the condition type that reports the bindings of a service instance that block its deletion.
+const TypeDeletionBlocked xpv1.ConditionType = "DeletionBlocked"
+
+// ReasonHasBindings is the reason of the DeletionBlocked condition.
+const ReasonHasBindings xpv1.ConditionReason = "HasBindings"
+
+// Kinds of the bindings of a service instance.
+const (
+	BindingCredential = "credential binding"
+	BindingRoute      = "route binding"
+)
+
+// maxBindingNames is the number of names of each kind of binding that DescribeBindings lists.
+const maxBindingNames = 5
+
+// Binding is a credential binding, i.e. an app binding or a service key, or a
+// route binding of a service instance.
+type Binding struct {
+	Kind string
+	GUID string
+	Name string
+}
+
+// Bindings is the interface that defines the methods to list and delete the bindings of a service instance.
+type Bindings interface {
+	// ListBindings returns the credential and route bindings of the service instance.
+	ListBindings(ctx context.Context, guid string) ([]Binding, error)
+	// DeleteBinding starts the deletion of a binding and returns the GUID of
+	// the job deleting it, if the deletion is asynchronous.
+	DeleteBinding(ctx context.Context, binding Binding) (string, error)
+}
+
+type bindingsClient struct {
+	credentialBindings *client.ServiceCredentialBindingClient
+	routeBindings      *client.ServiceRouteBindingClient
+}
+
+// NewBindingsClient creates a new client to list and delete the bindings of a service instance.
+func NewBindingsClient(cf *client.Client) Bindings {
+	return &bindingsClient{credentialBindings: cf.ServiceCredentialBindings, routeBindings: cf.ServiceRouteBindings}
+}
+
+// ListBindings implements Bindings.
+func (c *bindingsClient) ListBindings(ctx context.Context, guid string) ([]Binding, error) {
+	var bindings []Binding
+
+	credOpts := client.NewServiceCredentialBindingListOptions()
+	credOpts.ServiceInstanceGUIDs.EqualTo(guid)
+	creds, err := c.credentialBindings.ListAll(ctx, credOpts)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list credential bindings: %w", err)
+	}
+	for _, b := range creds {
+		bindings = append(bindings, Binding{Kind: BindingCredential, GUID: b.GUID, Name: ptr.Deref(b.Name, b.GUID)})
+	}
+
+	routeOpts := client.NewServiceRouteBindingListOptions()
+	routeOpts.ServiceInstanceGUIDs.EqualTo(guid)
+	routes, err := c.routeBindings.ListAll(ctx, routeOpts)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list route bindings: %w", err)
+	}
+	for _, b := range routes {
+		bindings = append(bindings, Binding{Kind: BindingRoute, GUID: b.GUID, Name: routeName(b)})
+	}
+	return bindings, nil
+}
+
+// routeName returns the GUID of the route of a route binding, as the route
+// binding has no name of its own.
+func routeName(b *resource.ServiceRouteBinding) string {
+	if b.Relationships.Route.Data != nil {
+		return b.Relationships.Route.Data.GUID
+	}
+	return b.GUID
+}
+
+// DeleteBinding implements Bindings.
+func (c *bindingsClient) DeleteBinding(ctx context.Context, binding Binding) (string, error) {
+	switch binding.Kind {
+	case BindingCredential:
+		return c.credentialBindings.Delete(ctx, binding.GUID)
+	case BindingRoute:
+		return c.routeBindings.Delete(ctx, binding.GUID)
+	default:
+		return "", fmt.Errorf("unknown binding %s", binding.Kind)
+	}
+}
+
+// DeleteBindings deletes the given bindings of a service instance and waits
+// for asynchronous deletions to complete. All bindings are deleted even if
+// some fail, and the errors are joined. Bindings that are already gone or
+// whose deletion is already in progress are skipped, so that a failed
+// deletion can simply be retried.
+func (c *Client) DeleteBindings(ctx context.Context, bindings []Binding) error {
+	var errs []error
+	for _, b := range bindings {
+		jobGUID, err := c.DeleteBinding(ctx, b)
+		if err == nil && jobGUID != "" {
+			err = job.PollWithBackoff(ctx, c.Job, jobGUID, nil)
+		}
+		if err != nil && !clients.IsNotFound(err) && !resource.IsAsyncServiceBindingOperationInProgressError(err) {
+			errs = append(errs, fmt.Errorf("cannot delete %s %s: %w", b.Kind, b.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// DescribeBindings returns how many bindings of each kind there are and the
+// names of the first ones, e.g. `2 credential bindings (a, b)`.
+func DescribeBindings(bindings []Binding) string {
+	names := map[string][]string{}
+	for _, b := range bindings {
+		names[b.Kind] = append(names[b.Kind], b.Name)
+	}
+
+	var parts []string
+	for _, kind := range []string{BindingCredential, BindingRoute} {
+		n := len(names[kind])
+		if n == 0 {
+			continue
+		}
+		listed := names[kind]
+		if n > maxBindingNames {
+			listed = append(listed[:maxBindingNames:maxBindingNames], "...")
+		}
+		plural := kind
+		if n != 1 {
+			plural += "s"
+		}
+		parts = append(parts, fmt.Sprintf("%d %s (%s)", n, plural, strings.Join(listed, ", ")))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// DeletionBlocked returns a condition that reports the bindings that block
+// the deletion of a service instance.
+func DeletionBlocked(bindings []Binding) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDeletionBlocked,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonHasBindings,
+		Message:            "the service instance has " + DescribeBindings(bindings) + ", which Cloud Foundry refused to delete with it, delete them or set cascadeDeleteBindings to delete them first",
+	}
+}
@@ -0,0 +1,48 @@
+package serviceinstance
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDescribeBindings(t *testing.T) {
+	cases := map[string]struct {
+		bindings []Binding
+		want     string
+	}{
+		"Empty": {
+			want: "",
+		},
+		"Singular": {
+			bindings: []Binding{{Kind: BindingRoute, Name: "route-guid"}},
+			want:     "1 route binding (route-guid)",
+		},
+		"GroupedByKind": {
+			bindings: []Binding{
+				{Kind: BindingRoute, Name: "route-guid"},
+				{Kind: BindingCredential, Name: "app-binding"},
+				{Kind: BindingCredential, Name: "key"},
+			},
+			want: "2 credential bindings (app-binding, key), 1 route binding (route-guid)",
+		},
+		"Capped": {
+			bindings: []Binding{
+				{Kind: BindingCredential, Name: "a"},
+				{Kind: BindingCredential, Name: "b"},
+				{Kind: BindingCredential, Name: "c"},
+				{Kind: BindingCredential, Name: "d"},
+				{Kind: BindingCredential, Name: "e"},
+				{Kind: BindingCredential, Name: "f"},
+			},
+			want: "6 credential bindings (a, b, c, d, e, ...)",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, DescribeBindings(tc.bindings)); diff != "" {
+				t.Errorf("DescribeBindings(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package serviceinstance
+
+import (
+	"maps"
+
+	"k8s.io/utils/ptr"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+)
+
+// ContextAnnotationPrefix is the prefix of the annotations that hold the
+// context metadata of a managed service instance. Cloud Foundry only passes
+// prefixed annotations to service brokers.
+const ContextAnnotationPrefix = "context.cloudfoundry.crossplane.io/"
+
+// ContextAnnotations returns the context metadata of the spec as annotations
+// of the service instance, or nil if there is none.
+func ContextAnnotations(spec v1alpha1.ServiceInstanceParameters) map[string]*string {
+	if len(spec.ContextMetadata) == 0 {
+		return nil
+	}
+	annotations := make(map[string]*string, len(spec.ContextMetadata))
+	for k, v := range spec.ContextMetadata {
+		annotations[ContextAnnotationPrefix+k] = ptr.To(v)
+	}
+	return annotations
+}
+
+// DesiredAnnotations returns the annotations of the spec together with its
+// context annotations, which take precedence.
+func DesiredAnnotations(spec v1alpha1.ServiceInstanceParameters) map[string]*string {
+	if len(spec.Annotations) == 0 {
+		return ContextAnnotations(spec)
+	}
+	annotations := maps.Clone(spec.Annotations)
+	maps.Copy(annotations, ContextAnnotations(spec))
+	return annotations
+}
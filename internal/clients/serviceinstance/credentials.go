@@ -0,0 +1,49 @@
+package serviceinstance
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// MergeCredentials merges the desired credentials over the actual credentials
+// of a user-provided service instance as a JSON merge patch (RFC 7386): keys
+// that are not desired are preserved, keys desired as null are removed and
+// nested objects are merged. Desired credentials that are not an object
+// replace the actual ones.
+func MergeCredentials(actual, desired json.RawMessage) (json.RawMessage, error) {
+	if len(desired) == 0 {
+		return actual, nil
+	}
+	var patch any
+	if err := json.Unmarshal(desired, &patch); err != nil {
+		return nil, errors.Wrap(err, "cannot parse the desired credentials")
+	}
+	var target any
+	if len(actual) > 0 {
+		if err := json.Unmarshal(actual, &target); err != nil {
+			return nil, errors.Wrap(err, "cannot parse the credentials of the service instance")
+		}
+	}
+	return json.Marshal(mergePatch(target, patch))
+}
+
+// mergePatch applies a JSON merge patch to a decoded JSON value.
+func mergePatch(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = map[string]any{}
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+		t[k] = mergePatch(t[k], v)
+	}
+	return t
+}
@@ -0,0 +1,72 @@
+package serviceinstance
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMergeCredentials(t *testing.T) {
+	cases := map[string]struct {
+		actual  string
+		desired string
+		want    string
+		err     string
+	}{
+		"PreservesKeys": {
+			actual:  `{"user":"admin","password":"old"}`,
+			desired: `{"password":"new"}`,
+			want:    `{"password":"new","user":"admin"}`,
+		},
+		"AddsKeys": {
+			actual:  `{"user":"admin"}`,
+			desired: `{"url":"https://example.com"}`,
+			want:    `{"url":"https://example.com","user":"admin"}`,
+		},
+		"MergesNestedObjects": {
+			actual:  `{"db":{"host":"db.example.com","port":5432},"user":"admin"}`,
+			desired: `{"db":{"port":5433}}`,
+			want:    `{"db":{"host":"db.example.com","port":5433},"user":"admin"}`,
+		},
+		"NullRemovesKey": {
+			actual:  `{"user":"admin","password":"old"}`,
+			desired: `{"password":null}`,
+			want:    `{"user":"admin"}`,
+		},
+		"ReplacesNonObjects": {
+			actual:  `{"hosts":["a","b"]}`,
+			desired: `{"hosts":["c"]}`,
+			want:    `{"hosts":["c"]}`,
+		},
+		"NoActualCredentials": {
+			desired: `{"password":"new"}`,
+			want:    `{"password":"new"}`,
+		},
+		"NoDesiredCredentials": {
+			actual: `{"user":"admin"}`,
+			want:   `{"user":"admin"}`,
+		},
+		"InvalidDesiredCredentials": {
+			actual:  `{"user":"admin"}`,
+			desired: `{"password":`,
+			err:     "cannot parse the desired credentials: unexpected end of JSON input",
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			got, err := MergeCredentials(json.RawMessage(tc.actual), json.RawMessage(tc.desired))
+			gotErr := ""
+			if err != nil {
+				gotErr = err.Error()
+			}
+			if diff := cmp.Diff(tc.err, gotErr); diff != "" {
+				t.Fatalf("MergeCredentials(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want, string(got)); diff != "" {
+				t.Errorf("MergeCredentials(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
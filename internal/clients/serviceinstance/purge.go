@@ -0,0 +1,41 @@
+package serviceinstance
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+)
+
+// Purger purges service instances from Cloud Foundry.
+type Purger interface {
+	// Purge removes a service instance and its bindings, keys and route
+	// bindings from Cloud Foundry without contacting its service broker.
+	Purge(ctx context.Context, guid string) error
+}
+
+// purgeClient implements Purger on the service instances endpoint of the CF
+// API, as go-cfclient does not support purging.
+type purgeClient struct {
+	cf *client.Client
+}
+
+// NewPurger returns a Purger for the given cf client.
+func NewPurger(cf *client.Client) Purger {
+	return &purgeClient{cf: cf}
+}
+
+// Purge implements Purger.
+func (c *purgeClient) Purge(ctx context.Context, guid string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.cf.ApiURL("/v3/service_instances/"+url.PathEscape(guid)), nil)
+	if err != nil {
+		return err
+	}
+	req.URL.RawQuery = url.Values{"purge": {"true"}}.Encode()
+	resp, err := c.cf.ExecuteAuthRequest(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
@@ -0,0 +1,48 @@
+package serviceinstance
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+)
+
+const (
+	errParseSchema     = "cannot parse the parameter schema of the service plan"
+	errParseParameters = "cannot parse the parameters as JSON"
+	errSchemaViolation = "parameters violate the parameter schema of the service plan: "
+)
+
+// ValidateParameters validates the given parameters of a service instance
+// against the JSON schema a service broker publishes for the parameters of
+// a service plan. Empty parameters are validated as an empty object. A nil
+// or empty schema accepts any parameters.
+func ValidateParameters(schema *json.RawMessage, params []byte) error {
+	if schema == nil || len(*schema) == 0 || string(*schema) == "{}" || string(*schema) == "null" {
+		return nil
+	}
+	s := &spec.Schema{}
+	if err := json.Unmarshal(*schema, s); err != nil {
+		return errors.Wrap(err, errParseSchema)
+	}
+
+	var p any = map[string]any{}
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return errors.Wrap(err, errParseParameters)
+		}
+	}
+
+	res := validate.NewSchemaValidator(s, nil, "", strfmt.Default).Validate(p)
+	if res.IsValid() {
+		return nil
+	}
+	msgs := make([]string, 0, len(res.Errors))
+	for _, err := range res.Errors {
+		msgs = append(msgs, strings.TrimPrefix(err.Error(), "."))
+	}
+	return errors.New(errSchemaViolation + strings.Join(msgs, "; "))
+}
@@ -1,19 +1,25 @@
 package serviceinstance
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/url"
+	"regexp"
+	"strconv"
 	"time"
 
 	"github.com/cloudfoundry/go-cfclient/v3/client"
 	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"k8s.io/utils/ptr"
 
 	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/job"
 )
 
 // ServiceInstance defines interfaces to the ServiceInstance resource
@@ -32,6 +38,7 @@ type ServiceInstance interface {
 // Job defines interfaces to async operations/jobs.
 type Job interface {
 	PollComplete(context.Context, string, *client.PollingOptions) error
+	Get(context.Context, string) (*resource.Job, error)
 }
 
 // newPollingOptions creates a new polling options with a timeout
@@ -67,24 +74,77 @@ func (c *Client) pollJobComplete(ctx context.Context, job string) error {
 	return err
 }
 
+// HeaderIdempotencyKey is the request header that carries the idempotency key of a create.
+const HeaderIdempotencyKey = "Idempotency-Key"
+
 // Client operates on ServiceInstance resources and uses Job to poll async operations.
 type Client struct {
 	ServiceInstance
 	Job
+	Purger
+	Bindings
+
+	idempotencyKey string
+	requestTimeout time.Duration
+}
+
+// An Option configures a Client.
+type Option func(*Client)
+
+// WithIdempotencyKey makes the Client send the given key in the
+// Idempotency-Key header of the requests creating a service instance, so that
+// a slow broker can recognize a retried create.
+func WithIdempotencyKey(key string) Option {
+	return func(c *Client) {
+		c.idempotencyKey = key
+	}
+}
+
+// WithRequestTimeout bounds each request creating a service instance by the
+// given timeout, independent of the timeout of the whole operation.
+// Zero keeps the request timeout of the CF client.
+func WithRequestTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.requestTimeout = d
+	}
 }
 
 // NewClient creates a new client instance from a cfclient.ServiceInstance instance.
-func NewClient(cf *client.Client) *Client {
-	return &Client{cf.ServiceInstances, cf.Jobs}
+func NewClient(cf *client.Client, opts ...Option) *Client {
+	c := &Client{ServiceInstance: cf.ServiceInstances, Job: cf.Jobs, Purger: NewPurger(cf), Bindings: NewBindingsClient(cf)}
+	for _, o := range opts {
+		o(c)
+	}
+	return c
 }
 
-// GetByIDOrSpec retrieves external resource by GUID or by matching CR's ForProvider spec
-func GetByIDOrSpec(ctx context.Context, c *Client, guid string, spec v1alpha1.ServiceInstanceParameters) (*resource.ServiceInstance, error) {
+// IdempotencyKey returns the idempotency key of the creates of the given
+// service instance. It is stable across the retries of a create of the same
+// generation of the service instance.
+func IdempotencyKey(cr *v1alpha1.ServiceInstance) string {
+	return fmt.Sprintf("%s-%d", cr.GetUID(), cr.GetGeneration())
+}
+
+// createRequest returns the context of a request creating a service instance
+// with the idempotency key and request timeout of the Client.
+func (c *Client) createRequest(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.idempotencyKey != "" {
+		ctx = clients.WithRequestHeader(ctx, HeaderIdempotencyKey, c.idempotencyKey)
+	}
+	if c.requestTimeout > 0 {
+		return context.WithTimeout(ctx, c.requestTimeout)
+	}
+	return ctx, func() {}
+}
+
+// GetByIDOrSpec retrieves external resource by GUID or by matching CR's ForProvider spec.
+// created is the time the service instance was created at, if it was created by the CR.
+func GetByIDOrSpec(ctx context.Context, c *Client, guid string, spec v1alpha1.ServiceInstanceParameters, created time.Time) (*resource.ServiceInstance, error) {
 	if _, err := uuid.Parse(guid); err == nil {
 		return c.Get(ctx, guid)
 	}
 
-	return c.MatchSingle(ctx, spec)
+	return c.MatchSingle(ctx, spec, created)
 }
 
 // Get retrieves external resource using GUID
@@ -95,8 +155,16 @@ func (c *Client) Get(ctx context.Context, guid string) (*resource.ServiceInstanc
 	return c.ServiceInstance.Get(ctx, guid)
 }
 
-// MatchSingle retrieves external resource by matching CR's ForProvider spec
-func (c *Client) MatchSingle(ctx context.Context, spec v1alpha1.ServiceInstanceParameters) (*resource.ServiceInstance, error) {
+// MatchSingle retrieves external resource by matching CR's ForProvider spec.
+// If the service instance was created recently, it retries briefly, as it may not be listed yet.
+func (c *Client) MatchSingle(ctx context.Context, spec v1alpha1.ServiceInstanceParameters, created time.Time) (*resource.ServiceInstance, error) {
+	return clients.ReadAfterWrite(ctx, created, func(ctx context.Context) (*resource.ServiceInstance, error) {
+		return c.matchSingle(ctx, spec)
+	})
+}
+
+// matchSingle lists service instances matching CR's ForProvider spec once.
+func (c *Client) matchSingle(ctx context.Context, spec v1alpha1.ServiceInstanceParameters) (*resource.ServiceInstance, error) {
 	// if external-name is not set, search by Name and Space
 	opt := client.NewServiceInstanceListOptions()
 	opt.Type = string(spec.Type)
@@ -145,6 +213,31 @@ func (c *Client) GetServiceCredentials(ctx context.Context, r *resource.ServiceI
 	return *raw, err
 }
 
+// GetConnectionDetails flattens the credentials of a user-provided service instance
+// to connection details, joining nested keys with "_". Nil or empty credentials
+// produce no connection details.
+func GetConnectionDetails(creds json.RawMessage) (managed.ConnectionDetails, error) {
+	if len(creds) == 0 {
+		return nil, nil
+	}
+
+	var m map[string]interface{}
+	d := json.NewDecoder(bytes.NewReader(creds))
+	d.UseNumber()
+	if err := d.Decode(&m); err != nil {
+		return nil, errors.Wrap(err, "cannot decode credentials of the service instance")
+	}
+	if len(m) == 0 {
+		return nil, nil
+	}
+
+	details := managed.ConnectionDetails{}
+	for key, value := range clients.NormalizeMap(m, make(map[string]string), "", "_") {
+		details[key] = []byte(value)
+	}
+	return details, nil
+}
+
 // Create creates the external resource according to CR's ForProvider spec
 func (c *Client) Create(ctx context.Context, spec v1alpha1.ServiceInstanceParameters, creds json.RawMessage) (*resource.ServiceInstance, error) {
 	switch spec.Type {
@@ -160,28 +253,73 @@ func (c *Client) Create(ctx context.Context, spec v1alpha1.ServiceInstanceParame
 
 // createManaged creates a managed service instance according to CR's ForProvider spec
 func (c *Client) createManaged(ctx context.Context, spec v1alpha1.ServiceInstanceParameters, params json.RawMessage) (*resource.ServiceInstance, error) {
+	opt, err := newManagedCreate(spec, params)
+	if err != nil {
+		return nil, err
+	}
+
+	rctx, cancel := c.createRequest(ctx)
+	job, err := c.ServiceInstance.CreateManaged(rctx, opt)
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+	// Poll for completion
+	if err = c.pollJobComplete(ctx, job); err != nil {
+		return nil, err
+	}
+
+	r, err := c.MatchSingle(ctx, spec, time.Now())
+	if err == nil && r == nil {
+		return nil, errors.New("service instance not listed after creation")
+	}
+	return r, err
+}
+
+// NewCreatePayload returns the request body that Create sends to Cloud Foundry for the given spec, without sending it.
+// For user-provided service instances, the credentials and URLs that Create sets in a follow-up update are included.
+func NewCreatePayload(spec v1alpha1.ServiceInstanceParameters, creds json.RawMessage) (any, error) {
+	switch spec.Type {
+	case v1alpha1.ManagedService:
+		return newManagedCreate(spec, creds)
+	case v1alpha1.UserProvidedService:
+		if spec.Space == nil {
+			return nil, errors.New("no space reference provided")
+		}
+		opt := resource.NewServiceInstanceCreateUserProvided(*spec.Name, *spec.Space)
+		opt.Metadata = clients.NewMetadata(spec.Labels, DesiredAnnotations(spec))
+		if creds != nil {
+			opt.WithCredentials(creds)
+		}
+		if spec.RouteServiceURL != "" {
+			opt.WithRouteServiceURL(spec.RouteServiceURL)
+		}
+		if spec.SyslogDrainURL != "" {
+			opt.WithSyslogDrainURL(spec.SyslogDrainURL)
+		}
+		return opt, nil
+	default:
+		return nil, errors.New("unknown service instance type")
+	}
+}
 
+// newManagedCreate returns the create request of a managed service instance according to CR's ForProvider spec
+func newManagedCreate(spec v1alpha1.ServiceInstanceParameters, params json.RawMessage) (*resource.ServiceInstanceManagedCreate, error) {
 	// throw error if no space is provided
 	if spec.Space == nil {
 		return nil, errors.New("no space reference provided")
 	}
+	if spec.ServicePlan == nil || spec.ServicePlan.ID == nil {
+		return nil, errors.New("no service plan provided")
+	}
 
 	opt := resource.NewServiceInstanceCreateManaged(*spec.Name, *spec.Space, *spec.ServicePlan.ID)
 
 	if params != nil {
 		opt.Parameters = &params
 	}
-
-	job, err := c.ServiceInstance.CreateManaged(ctx, opt)
-	if err != nil {
-		return nil, err
-	}
-	// Poll for completion
-	if err = c.pollJobComplete(ctx, job); err != nil {
-		return nil, err
-	}
-
-	return c.MatchSingle(ctx, spec)
+	opt.Metadata = clients.NewMetadata(spec.Labels, DesiredAnnotations(spec))
+	return opt, nil
 }
 
 // createUserProvided creates a user-provided service instance according to CR's ForProvider spec
@@ -192,7 +330,10 @@ func (c *Client) createUserProvided(ctx context.Context, spec v1alpha1.ServiceIn
 	}
 	// create the service instance
 	opt := resource.NewServiceInstanceCreateUserProvided(*spec.Name, *spec.Space)
-	si, err := c.ServiceInstance.CreateUserProvided(ctx, opt)
+	opt.Metadata = clients.NewMetadata(spec.Labels, DesiredAnnotations(spec))
+	rctx, cancel := c.createRequest(ctx)
+	si, err := c.ServiceInstance.CreateUserProvided(rctx, opt)
+	cancel()
 	if err != nil {
 		return nil, err
 	}
@@ -209,30 +350,30 @@ func (c *Client) createUserProvided(ctx context.Context, spec v1alpha1.ServiceIn
 }
 
 // Update updates the external resource to keep it in sync with CR's ForProvider spec
-func (c *Client) Update(ctx context.Context, guid string, desired *v1alpha1.ServiceInstanceParameters, creds json.RawMessage) (*resource.ServiceInstance, error) {
+func (c *Client) Update(ctx context.Context, guid string, desired *v1alpha1.ServiceInstanceParameters, managed v1alpha1.ManagedMetadata, creds json.RawMessage) (*resource.ServiceInstance, error) {
 	observed, err := c.Get(ctx, guid)
 	if err != nil {
 		return nil, err
 	}
 	switch desired.Type {
 	case v1alpha1.ManagedService:
-		return c.updateManaged(ctx, observed, desired, creds)
+		return c.updateManaged(ctx, observed, desired, managed, creds)
 	case v1alpha1.UserProvidedService:
-		return c.updateUserProvided(ctx, observed, desired, creds)
+		return c.updateUserProvided(ctx, observed, desired, managed, creds)
 	default:
 		return nil, errors.New("unknown service instance type")
 	}
 }
 
 // updateManaged updates managed service instance according to CR's ForProvider spec
-func (c *Client) updateManaged(ctx context.Context, observed *resource.ServiceInstance, desired *v1alpha1.ServiceInstanceParameters, params json.RawMessage) (*resource.ServiceInstance, error) {
+func (c *Client) updateManaged(ctx context.Context, observed *resource.ServiceInstance, desired *v1alpha1.ServiceInstanceParameters, managed v1alpha1.ManagedMetadata, params json.RawMessage) (*resource.ServiceInstance, error) {
 	upd := resource.NewServiceInstanceManagedUpdate()
 
 	if observed.Name != *desired.Name {
 		upd.WithName(*desired.Name)
 	}
 
-	if desired.ServicePlan.ID != nil && observed.Relationships.ServicePlan.Data.GUID != *desired.ServicePlan.ID {
+	if desired.ServicePlan != nil && desired.ServicePlan.ID != nil && observed.Relationships.ServicePlan.Data.GUID != *desired.ServicePlan.ID {
 		upd.WithServicePlan(*desired.ServicePlan.ID)
 	}
 
@@ -240,6 +381,9 @@ func (c *Client) updateManaged(ctx context.Context, observed *resource.ServiceIn
 		upd.WithParameters(params)
 	}
 
+	// All desired annotations are sent with every update, so that the broker receives the current context
+	upd.Metadata = clients.MetadataPatch(desired.Labels, DesiredAnnotations(*desired), managed, observed.Metadata)
+
 	// Update the service instance
 	job, s, err := c.ServiceInstance.UpdateManaged(ctx, observed.GUID, upd)
 	if err != nil {
@@ -259,7 +403,7 @@ func (c *Client) updateManaged(ctx context.Context, observed *resource.ServiceIn
 }
 
 // updateUserProvided updates user-provided service instance according to CR's ForProvider spec
-func (c *Client) updateUserProvided(ctx context.Context, observed *resource.ServiceInstance, desired *v1alpha1.ServiceInstanceParameters, creds json.RawMessage) (*resource.ServiceInstance, error) {
+func (c *Client) updateUserProvided(ctx context.Context, observed *resource.ServiceInstance, desired *v1alpha1.ServiceInstanceParameters, managed v1alpha1.ManagedMetadata, creds json.RawMessage) (*resource.ServiceInstance, error) {
 	upd := resource.NewServiceInstanceUserProvidedUpdate()
 
 	if observed.Name != *desired.Name {
@@ -271,16 +415,17 @@ func (c *Client) updateUserProvided(ctx context.Context, observed *resource.Serv
 	}
 	upd.WithRouteServiceURL(desired.RouteServiceURL).
 		WithSyslogDrainURL(desired.SyslogDrainURL)
+	upd.Metadata = clients.MetadataPatch(desired.Labels, DesiredAnnotations(*desired), managed, observed.Metadata)
 
 	return c.ServiceInstance.UpdateUserProvided(ctx, observed.GUID, upd)
 }
 
 // Delete deletes a service instance managed by the CR
 func (c *Client) Delete(ctx context.Context, cr *v1alpha1.ServiceInstance) error {
-	job, err := c.ServiceInstance.Delete(ctx, *cr.Status.AtProvider.ID)
+	jobGUID, err := c.ServiceInstance.Delete(ctx, *cr.Status.AtProvider.ID)
 
 	// If the service instance is already deleted, return nil
-	if clients.ErrorIsNotFound(err) {
+	if clients.IsNotFound(err) {
 		return nil
 	}
 
@@ -288,8 +433,13 @@ func (c *Client) Delete(ctx context.Context, cr *v1alpha1.ServiceInstance) error
 		return err
 	}
 
-	// Poll for completion
-	return c.pollJobComplete(ctx, job)
+	// Poll for completion, a deletion that takes longer is left in progress
+	// and observed through the last operation of the service instance
+	err = job.PollWithBackoff(ctx, c.Job, jobGUID, nil)
+	if errors.Is(err, job.ErrPollTimeout) {
+		return nil
+	}
+	return err
 }
 
 // LateInitialize populates EMPTY parameters based on the observed managed resource properties
@@ -310,14 +460,102 @@ func UpdateObservation(in *v1alpha1.ServiceInstanceObservation, r *resource.Serv
 		Description: r.LastOperation.Description,
 		UpdatedAt:   r.LastOperation.UpdatedAt.String(),
 	}
+	in.Progress = ParseProgress(r.LastOperation.Description)
+	in.ProgressStep = ParseProgressStep(r.LastOperation.Description)
+	in.Labels, in.Annotations = nil, nil
+	if r.Metadata != nil {
+		in.Labels = r.Metadata.Labels
+		in.Annotations = r.Metadata.Annotations
+	}
+
+	if r.Type == string(v1alpha1.UserProvidedService) {
+		in.RouteServiceURL = r.RouteServiceURL
+		in.SyslogDrainURL = r.SyslogDrainURL
+	}
 
 	if r.Type == string(v1alpha1.ManagedService) {
 		in.ServicePlan = &r.Relationships.ServicePlan.Data.GUID
+		in.DashboardURL = r.DashboardURL
+		in.MaintenanceInfo = v1alpha1.MaintenanceInfo{}
+		if r.MaintenanceInfo != nil {
+			in.MaintenanceInfo.Version = &r.MaintenanceInfo.Version
+			if r.MaintenanceInfo.Description != "" {
+				in.MaintenanceInfo.Description = &r.MaintenanceInfo.Description
+			}
+		}
+	}
+}
+
+// progressPattern matches a percentage such as "42%" or "42 %" in the description of a last operation.
+var progressPattern = regexp.MustCompile(`\b(\d+)\s*%`)
+
+// ParseProgress returns the percentage that some service brokers report in the
+// description of the last operation, clamped to 100, or nil if the description has none.
+func ParseProgress(description string) *int32 {
+	m := progressPattern.FindStringSubmatch(description)
+	if m == nil {
+		return nil
+	}
+	p, err := strconv.ParseInt(m[1], 10, 32)
+	if err != nil {
+		return nil
 	}
+	return ptr.To(int32(min(p, 100)))
+}
+
+// progressStepPattern matches a step such as "step 2 of 5" or "Step 2/5" in the description of a last operation.
+var progressStepPattern = regexp.MustCompile(`(?i)\bstep\s+(\d+)\s*(?:of|/)\s*(\d+)\b`)
+
+// ParseProgressStep returns the step that some service brokers report in the
+// description of the last operation as "step 2 of 5", or nil if the description has none.
+func ParseProgressStep(description string) *string {
+	m := progressStepPattern.FindStringSubmatch(description)
+	if m == nil {
+		return nil
+	}
+	return ptr.To("step " + m[1] + " of " + m[2])
+}
+
+// PlanUpdate returns the changes an update would apply to the observed service
+// instance, one per field. It compares the same fields as IsUpToDate.
+func PlanUpdate(in *v1alpha1.ServiceInstanceParameters, managed v1alpha1.ManagedMetadata, observed *resource.ServiceInstance) []string {
+	var changes []string
+	if in.Name != nil && *in.Name != observed.Name {
+		changes = append(changes, fmt.Sprintf("name: %q -> %q", observed.Name, *in.Name))
+	}
+
+	switch in.Type {
+	case v1alpha1.ManagedService:
+		if in.ServicePlan != nil && in.ServicePlan.ID != nil && observed.Relationships.ServicePlan.Data.GUID != *in.ServicePlan.ID {
+			changes = append(changes, fmt.Sprintf("servicePlan: %s -> %s", observed.Relationships.ServicePlan.Data.GUID, *in.ServicePlan.ID))
+		}
+	case v1alpha1.UserProvidedService:
+		if in.RouteServiceURL != ptr.Deref(observed.RouteServiceURL, "") {
+			changes = append(changes, fmt.Sprintf("routeServiceUrl: %q -> %q", ptr.Deref(observed.RouteServiceURL, ""), in.RouteServiceURL))
+		}
+		if in.SyslogDrainURL != ptr.Deref(observed.SyslogDrainURL, "") {
+			changes = append(changes, fmt.Sprintf("syslogDrainUrl: %q -> %q", ptr.Deref(observed.SyslogDrainURL, ""), in.SyslogDrainURL))
+		}
+	}
+	if !IsMetadataUpToDate(in, managed, observed) {
+		changes = append(changes, "metadata: changed")
+	}
+	return changes
+}
+
+// PlanChange returns the GUIDs of the observed and the desired service plan if
+// the spec changes the service plan of a managed service instance.
+func PlanChange(in *v1alpha1.ServiceInstanceParameters, observed *resource.ServiceInstance) (from, to string, changed bool) {
+	if in.Type != v1alpha1.ManagedService || in.ServicePlan == nil || in.ServicePlan.ID == nil ||
+		observed.Relationships.ServicePlan == nil || observed.Relationships.ServicePlan.Data == nil {
+		return "", "", false
+	}
+	from, to = observed.Relationships.ServicePlan.Data.GUID, *in.ServicePlan.ID
+	return from, to, from != to
 }
 
 // IsUpToDate checks if the managed resource is in sync with CR.
-func IsUpToDate(in *v1alpha1.ServiceInstanceParameters, observed *resource.ServiceInstance) bool {
+func IsUpToDate(in *v1alpha1.ServiceInstanceParameters, managed v1alpha1.ManagedMetadata, observed *resource.ServiceInstance) bool {
 	if in.Name != nil && *in.Name != observed.Name {
 		return false
 	}
@@ -335,5 +573,12 @@ func IsUpToDate(in *v1alpha1.ServiceInstanceParameters, observed *resource.Servi
 			return false
 		}
 	}
-	return true
+	return IsMetadataUpToDate(in, managed, observed)
+}
+
+// IsMetadataUpToDate checks if the labels and annotations of the service
+// instance match the spec, including the annotations of its context metadata,
+// and if the managed ones removed from the spec are gone.
+func IsMetadataUpToDate(in *v1alpha1.ServiceInstanceParameters, managed v1alpha1.ManagedMetadata, observed *resource.ServiceInstance) bool {
+	return clients.MetadataEqual(in.Labels, DesiredAnnotations(*in), managed, observed.Metadata)
 }
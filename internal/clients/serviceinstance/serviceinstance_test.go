@@ -0,0 +1,626 @@
+package serviceinstance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/fake"
+)
+
+func TestParseProgress(t *testing.T) {
+	cases := map[string]struct {
+		description string
+		want        *int32
+	}{
+		"WithPercentage": {
+			description: "Provisioning in progress: 42% complete",
+			want:        ptr.To[int32](42),
+		},
+		"WithSpacedPercentage": {
+			description: "Creating cluster (75 %)",
+			want:        ptr.To[int32](75),
+		},
+		"WithoutPercentage": {
+			description: "Provisioning in progress",
+			want:        nil,
+		},
+		"AboveHundred": {
+			description: "Progress: 250%",
+			want:        ptr.To[int32](100),
+		},
+		"FourDigits": {
+			description: "Progress: 1050%",
+			want:        ptr.To[int32](100),
+		},
+		"WithinWord": {
+			description: "Progress: v2x50%",
+			want:        nil,
+		},
+		"Empty": {
+			description: "",
+			want:        nil,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ParseProgress(tc.description)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ParseProgress(%q): -want, +got:\n%s", tc.description, diff)
+			}
+		})
+	}
+}
+
+func TestParseProgressStep(t *testing.T) {
+	cases := map[string]struct {
+		description string
+		want        *string
+	}{
+		"StepOf": {
+			description: "Provisioning: Step 2 of 5, creating the database",
+			want:        ptr.To("step 2 of 5"),
+		},
+		"StepSlash": {
+			description: "step 3/4",
+			want:        ptr.To("step 3 of 4"),
+		},
+		"WithoutStep": {
+			description: "Provisioning in progress: 42% complete",
+			want:        nil,
+		},
+		"Empty": {
+			description: "",
+			want:        nil,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ParseProgressStep(tc.description)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ParseProgressStep(%q): -want, +got:\n%s", tc.description, diff)
+			}
+		})
+	}
+}
+
+func TestGetConnectionDetails(t *testing.T) {
+	cases := map[string]struct {
+		creds   json.RawMessage
+		want    managed.ConnectionDetails
+		wantErr bool
+	}{
+		"Nil": {
+			creds: nil,
+			want:  nil,
+		},
+		"EmptyObject": {
+			creds: json.RawMessage("{}"),
+			want:  nil,
+		},
+		"Flattened": {
+			creds: json.RawMessage(`{"user":"admin","tls":true,"db":{"port":5432,"hosts":["a","b"]}}`),
+			want: managed.ConnectionDetails{
+				"user":       []byte("admin"),
+				"tls":        []byte("true"),
+				"db_port":    []byte("5432"),
+				"db_hosts_0": []byte("a"),
+				"db_hosts_1": []byte("b"),
+			},
+		},
+		"Invalid": {
+			creds:   json.RawMessage(`not json`),
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := GetConnectionDetails(tc.creds)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("GetConnectionDetails(...): want error %t, got %v", tc.wantErr, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("GetConnectionDetails(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+// createRecorder records the idempotency key and deadline of the creates it
+// receives and fails them, as a broker that times out would.
+type createRecorder struct {
+	ServiceInstance
+	keys      []string
+	deadlines []bool
+}
+
+func (r *createRecorder) CreateManaged(ctx context.Context, _ *resource.ServiceInstanceManagedCreate) (string, error) {
+	r.keys = append(r.keys, clients.RequestHeaders(ctx).Get(HeaderIdempotencyKey))
+	_, ok := ctx.Deadline()
+	r.deadlines = append(r.deadlines, ok)
+	return "", errors.New("broker timed out")
+}
+
+func TestCreateIdempotencyKey(t *testing.T) {
+	cr := &v1alpha1.ServiceInstance{
+		ObjectMeta: metav1.ObjectMeta{UID: "3c5ae6b4-5b9e-4cb1-8e2c-0b6c2e6a3f0d", Generation: 1},
+		Spec: v1alpha1.ServiceInstanceSpec{
+			ForProvider: v1alpha1.ServiceInstanceParameters{
+				Type:           v1alpha1.ManagedService,
+				Name:           ptr.To("my-service"),
+				SpaceReference: v1alpha1.SpaceReference{Space: ptr.To("space-guid")},
+				Managed:        v1alpha1.Managed{ServicePlan: &v1alpha1.ServicePlanParameters{ID: ptr.To("plan-guid")}},
+			},
+		},
+	}
+	si := &createRecorder{}
+	create := func() {
+		c := &Client{ServiceInstance: si}
+		WithIdempotencyKey(IdempotencyKey(cr))(c)
+		WithRequestTimeout(time.Minute)(c)
+		if _, err := c.Create(context.Background(), cr.Spec.ForProvider, nil); err == nil {
+			t.Fatal("Create(...): expected an error")
+		}
+	}
+
+	// the create fails and is retried for the same generation
+	create()
+	create()
+	// the spec changes
+	cr.Generation = 2
+	create()
+
+	want := []string{
+		"3c5ae6b4-5b9e-4cb1-8e2c-0b6c2e6a3f0d-1",
+		"3c5ae6b4-5b9e-4cb1-8e2c-0b6c2e6a3f0d-1",
+		"3c5ae6b4-5b9e-4cb1-8e2c-0b6c2e6a3f0d-2",
+	}
+	if diff := cmp.Diff(want, si.keys); diff != "" {
+		t.Errorf("Create(...): -want idempotency keys, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff([]bool{true, true, true}, si.deadlines); diff != "" {
+		t.Errorf("Create(...): -want request deadlines, +got:\n%s", diff)
+	}
+}
+
+func TestValidateParameters(t *testing.T) {
+	schema := json.RawMessage(`{
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"type": "object",
+		"properties": {
+			"size": {"type": "integer", "minimum": 1},
+			"tier": {"type": "string", "enum": ["standard", "premium"]}
+		},
+		"required": ["size"],
+		"additionalProperties": false
+	}`)
+
+	cases := map[string]struct {
+		schema  *json.RawMessage
+		params  string
+		wantErr string
+	}{
+		"Valid": {
+			schema: &schema,
+			params: `{"size": 2, "tier": "premium"}`,
+		},
+		"NoSchema": {
+			params: `{"anything": true}`,
+		},
+		"EmptySchema": {
+			schema: ptr.To(json.RawMessage(`{}`)),
+			params: `{"anything": true}`,
+		},
+		"Typo": {
+			schema:  &schema,
+			params:  `{"size": 2, "teir": "premium"}`,
+			wantErr: errSchemaViolation + "teir in body is a forbidden property",
+		},
+		"WrongType": {
+			schema:  &schema,
+			params:  `{"size": "2"}`,
+			wantErr: errSchemaViolation + "size in body must be of type integer: \"string\"",
+		},
+		"NotInEnum": {
+			schema:  &schema,
+			params:  `{"size": 2, "tier": "gold"}`,
+			wantErr: errSchemaViolation + "tier in body should be one of [standard premium]",
+		},
+		"MissingRequired": {
+			schema:  &schema,
+			params:  "",
+			wantErr: errSchemaViolation + "size in body is required",
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			err := ValidateParameters(tc.schema, []byte(tc.params))
+			got := ""
+			if err != nil {
+				got = err.Error()
+			}
+			if diff := cmp.Diff(tc.wantErr, got); diff != "" {
+				t.Errorf("ValidateParameters(...): -want error, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdateObservation(t *testing.T) {
+	cases := map[string]struct {
+		r    *resource.ServiceInstance
+		want v1alpha1.ServiceInstanceObservation
+	}{
+		"Managed": {
+			r: &fake.NewServiceInstance("managed").SetGUID("guid").SetServicePlan("plan").
+				SetDashboardURL("https://dashboard.example.com/guid").SetMaintenanceInfo("1.2.0", "security patch").ServiceInstance,
+			want: v1alpha1.ServiceInstanceObservation{
+				ID:           ptr.To("guid"),
+				ServicePlan:  ptr.To("plan"),
+				DashboardURL: ptr.To("https://dashboard.example.com/guid"),
+				MaintenanceInfo: v1alpha1.MaintenanceInfo{
+					Version:     ptr.To("1.2.0"),
+					Description: ptr.To("security patch"),
+				},
+			},
+		},
+		"ManagedWithoutDashboard": {
+			r: &fake.NewServiceInstance("managed").SetGUID("guid").SetServicePlan("plan").ServiceInstance,
+			want: v1alpha1.ServiceInstanceObservation{
+				ID:          ptr.To("guid"),
+				ServicePlan: ptr.To("plan"),
+			},
+		},
+		"UserProvided": {
+			r: &fake.NewServiceInstance("user-provided").SetGUID("guid").SetDashboardURL("https://dashboard.example.com/guid").ServiceInstance,
+			want: v1alpha1.ServiceInstanceObservation{
+				ID: ptr.To("guid"),
+			},
+		},
+		"UserProvidedWithURLs": {
+			r: &fake.NewServiceInstance("user-provided").SetGUID("guid").
+				SetRouteServiceURL("https://route.example.com").SetSyslogDrainURL("syslog-tls://logs.example.com:6514").ServiceInstance,
+			want: v1alpha1.ServiceInstanceObservation{
+				ID:              ptr.To("guid"),
+				RouteServiceURL: ptr.To("https://route.example.com"),
+				SyslogDrainURL:  ptr.To("syslog-tls://logs.example.com:6514"),
+			},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			got := v1alpha1.ServiceInstanceObservation{}
+			UpdateObservation(&got, tc.r)
+			got.LastOperation = v1alpha1.LastOperation{}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("UpdateObservation(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+// updateRecorder records the updates it receives and returns the service
+// instance unchanged.
+type updateRecorder struct {
+	ServiceInstance
+	observed *resource.ServiceInstance
+	managed  []*resource.ServiceInstanceManagedUpdate
+	ups      []*resource.ServiceInstanceUserProvidedUpdate
+}
+
+func (r *updateRecorder) Get(_ context.Context, _ string) (*resource.ServiceInstance, error) {
+	return r.observed, nil
+}
+
+func (r *updateRecorder) UpdateManaged(_ context.Context, _ string, u *resource.ServiceInstanceManagedUpdate) (string, *resource.ServiceInstance, error) {
+	r.managed = append(r.managed, u)
+	return "", r.observed, nil
+}
+
+func (r *updateRecorder) UpdateUserProvided(_ context.Context, _ string, u *resource.ServiceInstanceUserProvidedUpdate) (*resource.ServiceInstance, error) {
+	r.ups = append(r.ups, u)
+	return r.observed, nil
+}
+
+func TestUpdateRename(t *testing.T) {
+	cases := map[string]struct {
+		observed *resource.ServiceInstance
+		desired  v1alpha1.ServiceInstanceParameters
+		want     *string
+	}{
+		"Managed": {
+			observed: &fake.NewServiceInstance("managed").SetName("old").SetGUID("guid").SetServicePlan("plan").ServiceInstance,
+			desired: v1alpha1.ServiceInstanceParameters{
+				Type:    v1alpha1.ManagedService,
+				Name:    ptr.To("new"),
+				Managed: v1alpha1.Managed{ServicePlan: &v1alpha1.ServicePlanParameters{ID: ptr.To("plan")}},
+			},
+			want: ptr.To("new"),
+		},
+		"ManagedNotRenamed": {
+			observed: &fake.NewServiceInstance("managed").SetName("old").SetGUID("guid").SetServicePlan("plan").ServiceInstance,
+			desired: v1alpha1.ServiceInstanceParameters{
+				Type:    v1alpha1.ManagedService,
+				Name:    ptr.To("old"),
+				Managed: v1alpha1.Managed{ServicePlan: &v1alpha1.ServicePlanParameters{ID: ptr.To("plan")}},
+			},
+		},
+		"UserProvided": {
+			observed: &fake.NewServiceInstance("user-provided").SetName("old").SetGUID("guid").ServiceInstance,
+			desired: v1alpha1.ServiceInstanceParameters{
+				Type: v1alpha1.UserProvidedService,
+				Name: ptr.To("new"),
+			},
+			want: ptr.To("new"),
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			if got, want := IsUpToDate(&tc.desired, v1alpha1.ManagedMetadata{}, tc.observed), tc.want == nil; got != want {
+				t.Errorf("IsUpToDate(...): want %t, got %t", want, got)
+			}
+
+			si := &updateRecorder{observed: tc.observed}
+			c := &Client{ServiceInstance: si}
+			if _, err := c.Update(context.Background(), "guid", &tc.desired, v1alpha1.ManagedMetadata{}, nil); err != nil {
+				t.Fatalf("Update(...): unexpected error: %v", err)
+			}
+
+			var got *string
+			switch tc.desired.Type {
+			case v1alpha1.ManagedService:
+				got = si.managed[0].Name
+			case v1alpha1.UserProvidedService:
+				got = si.ups[0].Name
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Update(...): -want name in payload, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestContextMetadata(t *testing.T) {
+	spec := v1alpha1.ServiceInstanceParameters{
+		Type:           v1alpha1.ManagedService,
+		Name:           ptr.To("db"),
+		SpaceReference: v1alpha1.SpaceReference{Space: ptr.To("space-guid")},
+		Managed: v1alpha1.Managed{
+			ServicePlan:     &v1alpha1.ServicePlanParameters{ID: ptr.To("plan")},
+			ContextMetadata: map[string]string{"cost-center": "1234", "tier": "gold"},
+		},
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		payload, err := NewCreatePayload(spec, nil)
+		if err != nil {
+			t.Fatalf("NewCreatePayload(...): unexpected error: %v", err)
+		}
+		got, err := json.Marshal(payload)
+		if err != nil {
+			t.Fatalf("json.Marshal(...): unexpected error: %v", err)
+		}
+		want := `{"type":"managed","name":"db",` +
+			`"relationships":{"service_plan":{"data":{"guid":"plan"}},"space":{"data":{"guid":"space-guid"}}},` +
+			`"metadata":{"labels":null,"annotations":{"context.cloudfoundry.crossplane.io/cost-center":"1234","context.cloudfoundry.crossplane.io/tier":"gold"}}}`
+		if diff := cmp.Diff(want, string(got)); diff != "" {
+			t.Errorf("NewCreatePayload(...): -want, +got:\n%s", diff)
+		}
+	})
+
+	cases := map[string]struct {
+		observed map[string]*string
+		managed  []string
+		upToDate bool
+		want     map[string]*string
+	}{
+		"NotSet": {
+			observed: map[string]*string{"owner": ptr.To("team")},
+			want: map[string]*string{
+				ContextAnnotationPrefix + "cost-center": ptr.To("1234"),
+				ContextAnnotationPrefix + "tier":        ptr.To("gold"),
+			},
+		},
+		"UpToDate": {
+			observed: map[string]*string{
+				ContextAnnotationPrefix + "cost-center": ptr.To("1234"),
+				ContextAnnotationPrefix + "tier":        ptr.To("gold"),
+			},
+			upToDate: true,
+			want: map[string]*string{
+				ContextAnnotationPrefix + "cost-center": ptr.To("1234"),
+				ContextAnnotationPrefix + "tier":        ptr.To("gold"),
+			},
+		},
+		"Changed": {
+			observed: map[string]*string{
+				ContextAnnotationPrefix + "cost-center": ptr.To("999"),
+				ContextAnnotationPrefix + "tier":        ptr.To("gold"),
+				ContextAnnotationPrefix + "region":      ptr.To("eu"),
+			},
+			managed: []string{ContextAnnotationPrefix + "cost-center", ContextAnnotationPrefix + "region", ContextAnnotationPrefix + "tier"},
+			want: map[string]*string{
+				ContextAnnotationPrefix + "cost-center": ptr.To("1234"),
+				ContextAnnotationPrefix + "tier":        ptr.To("gold"),
+				ContextAnnotationPrefix + "region":      nil,
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			observed := &fake.NewServiceInstance("managed").SetName("db").SetGUID("guid").SetServicePlan("plan").ServiceInstance
+			observed.Metadata = &resource.Metadata{Annotations: tc.observed}
+
+			managed := v1alpha1.ManagedMetadata{ManagedAnnotations: tc.managed}
+			if got := IsUpToDate(&spec, managed, observed); got != tc.upToDate {
+				t.Errorf("IsUpToDate(...): want %t, got %t", tc.upToDate, got)
+			}
+
+			si := &updateRecorder{observed: observed}
+			c := &Client{ServiceInstance: si}
+			if _, err := c.Update(context.Background(), "guid", &spec, managed, nil); err != nil {
+				t.Fatalf("Update(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, si.managed[0].Metadata.Annotations); diff != "" {
+				t.Errorf("Update(...): -want annotations in payload, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIsMetadataUpToDate(t *testing.T) {
+	cases := map[string]struct {
+		spec     v1alpha1.ServiceInstanceParameters
+		managed  v1alpha1.ManagedMetadata
+		observed *resource.Metadata
+		want     bool
+	}{
+		"BothEmpty": {
+			spec:     v1alpha1.ServiceInstanceParameters{Type: v1alpha1.ManagedService},
+			observed: &resource.Metadata{},
+			want:     true,
+		},
+		"NoObservedMetadata": {
+			spec: v1alpha1.ServiceInstanceParameters{Type: v1alpha1.ManagedService},
+			want: true,
+		},
+		"UpToDate": {
+			spec: v1alpha1.ServiceInstanceParameters{
+				Type:             v1alpha1.UserProvidedService,
+				ResourceMetadata: v1alpha1.ResourceMetadata{Labels: map[string]*string{"env": ptr.To("prod")}, Annotations: map[string]*string{"owner": ptr.To("team")}},
+			},
+			observed: &resource.Metadata{Labels: map[string]*string{"env": ptr.To("prod")}, Annotations: map[string]*string{"owner": ptr.To("team")}},
+			want:     true,
+		},
+		"LabelChanged": {
+			spec: v1alpha1.ServiceInstanceParameters{
+				Type:             v1alpha1.ManagedService,
+				ResourceMetadata: v1alpha1.ResourceMetadata{Labels: map[string]*string{"env": ptr.To("prod")}},
+			},
+			observed: &resource.Metadata{Labels: map[string]*string{"env": ptr.To("dev")}},
+		},
+		"AnnotationMissing": {
+			spec: v1alpha1.ServiceInstanceParameters{
+				Type:             v1alpha1.UserProvidedService,
+				ResourceMetadata: v1alpha1.ResourceMetadata{Annotations: map[string]*string{"owner": ptr.To("team")}},
+			},
+			observed: &resource.Metadata{},
+		},
+		"SetByOthers": {
+			spec:     v1alpha1.ServiceInstanceParameters{Type: v1alpha1.ManagedService},
+			observed: &resource.Metadata{Labels: map[string]*string{"env": ptr.To("prod")}},
+			want:     true,
+		},
+		"ManagedLabelRemoved": {
+			spec:     v1alpha1.ServiceInstanceParameters{Type: v1alpha1.ManagedService},
+			managed:  v1alpha1.ManagedMetadata{ManagedLabels: []string{"env"}},
+			observed: &resource.Metadata{Labels: map[string]*string{"env": ptr.To("prod")}},
+		},
+		"ContextMetadataRemoved": {
+			spec:     v1alpha1.ServiceInstanceParameters{Type: v1alpha1.ManagedService},
+			managed:  v1alpha1.ManagedMetadata{ManagedAnnotations: []string{ContextAnnotationPrefix + "tier"}},
+			observed: &resource.Metadata{Annotations: map[string]*string{ContextAnnotationPrefix + "tier": ptr.To("gold")}},
+		},
+		"WithContextMetadata": {
+			spec: v1alpha1.ServiceInstanceParameters{
+				Type:             v1alpha1.ManagedService,
+				ResourceMetadata: v1alpha1.ResourceMetadata{Annotations: map[string]*string{"owner": ptr.To("team")}},
+				Managed:          v1alpha1.Managed{ContextMetadata: map[string]string{"tier": "gold"}},
+			},
+			observed: &resource.Metadata{Annotations: map[string]*string{"owner": ptr.To("team"), ContextAnnotationPrefix + "tier": ptr.To("gold")}},
+			want:     true,
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			observed := &resource.ServiceInstance{Metadata: tc.observed}
+			if got := IsMetadataUpToDate(&tc.spec, tc.managed, observed); got != tc.want {
+				t.Errorf("IsMetadataUpToDate(...): want %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestMetadataPayload(t *testing.T) {
+	labels := map[string]*string{"env": ptr.To("prod")}
+	annotations := map[string]*string{"owner": ptr.To("team")}
+	cases := map[string]struct {
+		spec     v1alpha1.ServiceInstanceParameters
+		observed *resource.ServiceInstance
+	}{
+		"Managed": {
+			spec: v1alpha1.ServiceInstanceParameters{
+				Type:             v1alpha1.ManagedService,
+				Name:             ptr.To("db"),
+				SpaceReference:   v1alpha1.SpaceReference{Space: ptr.To("space-guid")},
+				Managed:          v1alpha1.Managed{ServicePlan: &v1alpha1.ServicePlanParameters{ID: ptr.To("plan")}},
+				ResourceMetadata: v1alpha1.ResourceMetadata{Labels: labels, Annotations: annotations},
+			},
+			observed: &fake.NewServiceInstance("managed").SetName("db").SetGUID("guid").SetServicePlan("plan").ServiceInstance,
+		},
+		"UserProvided": {
+			spec: v1alpha1.ServiceInstanceParameters{
+				Type:             v1alpha1.UserProvidedService,
+				Name:             ptr.To("db"),
+				SpaceReference:   v1alpha1.SpaceReference{Space: ptr.To("space-guid")},
+				ResourceMetadata: v1alpha1.ResourceMetadata{Labels: labels, Annotations: annotations},
+			},
+			observed: &fake.NewServiceInstance("user-provided").SetName("db").SetGUID("guid").ServiceInstance,
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			payload, err := NewCreatePayload(tc.spec, nil)
+			if err != nil {
+				t.Fatalf("NewCreatePayload(...): unexpected error: %v", err)
+			}
+			var create struct {
+				Metadata *resource.Metadata `json:"metadata"`
+			}
+			b, _ := json.Marshal(payload)
+			if err := json.Unmarshal(b, &create); err != nil {
+				t.Fatalf("json.Unmarshal(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(&resource.Metadata{Labels: labels, Annotations: annotations}, create.Metadata); diff != "" {
+				t.Errorf("NewCreatePayload(...): -want metadata, +got:\n%s", diff)
+			}
+
+			// managed labels removed from the spec are removed with explicit nulls,
+			// labels and annotations set by others are kept
+			tc.observed.Metadata = &resource.Metadata{Labels: map[string]*string{"stale": ptr.To("x"), "other": ptr.To("y")}}
+			managed := v1alpha1.ManagedMetadata{ManagedLabels: []string{"env", "stale"}}
+			si := &updateRecorder{observed: tc.observed}
+			c := &Client{ServiceInstance: si}
+			if _, err := c.Update(context.Background(), "guid", &tc.spec, managed, nil); err != nil {
+				t.Fatalf("Update(...): unexpected error: %v", err)
+			}
+			var got *resource.Metadata
+			switch tc.spec.Type {
+			case v1alpha1.ManagedService:
+				got = si.managed[0].Metadata
+			case v1alpha1.UserProvidedService:
+				got = si.ups[0].Metadata
+			}
+			want := &resource.Metadata{Labels: map[string]*string{"env": ptr.To("prod"), "stale": nil}, Annotations: annotations}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("Update(...): -want metadata in payload, +got:\n%s", diff)
+			}
+		})
+	}
+}
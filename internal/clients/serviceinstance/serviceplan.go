@@ -0,0 +1,61 @@
+package serviceinstance
+
+import (
+	"context"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+)
+
+// ServicePlans defines interfaces to look up the service plan of a service instance.
+type ServicePlans interface {
+	Single(context.Context, *client.ServicePlanListOptions) (*resource.ServicePlan, error)
+	// GetIncludeServicePlan returns the service plan with the given GUID and its service offering.
+	GetIncludeServicePlan(context.Context, string) (*resource.ServicePlan, *resource.ServiceOffering, error)
+}
+
+// ResolveServicePlan returns the service plan selected by the given parameters.
+// If the GUID of the plan is set, the plan must exist and match the offering
+// and plan names, if they are set as well. Otherwise the plan is looked up by
+// the offering and plan names.
+//
+// current is the GUID of the plan the service instance uses, if any. A GUID
+// equal to it was resolved from the names or late initialized, so the names
+// take precedence and changing them changes the plan.
+func ResolveServicePlan(ctx context.Context, plans ServicePlans, p v1alpha1.ServicePlanParameters, current *string) (*resource.ServicePlan, error) {
+	byName := p.Offering != nil || p.Plan != nil
+	if p.ID == nil || *p.ID == "" || (byName && ptr.Equal(p.ID, current)) {
+		opt := client.NewServicePlanListOptions()
+		if p.Offering != nil {
+			opt.ServiceOfferingNames.EqualTo(*p.Offering)
+		}
+		if p.Plan != nil {
+			opt.Names.EqualTo(*p.Plan)
+		}
+		sp, err := plans.Single(ctx, opt)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot find service plan %s of offering %s", ptr.Deref(p.Plan, ""), ptr.Deref(p.Offering, ""))
+		}
+		return sp, nil
+	}
+
+	sp, so, err := plans.GetIncludeServicePlan(ctx, *p.ID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot find service plan %s", *p.ID)
+	}
+	if p.Plan != nil && *p.Plan != sp.Name {
+		return nil, errors.Errorf("service plan %s is named %s, not %s", *p.ID, sp.Name, *p.Plan)
+	}
+	if p.Offering != nil && (so == nil || *p.Offering != so.Name) {
+		offering := ""
+		if so != nil {
+			offering = so.Name
+		}
+		return nil, errors.Errorf("service plan %s belongs to offering %s, not %s", *p.ID, offering, *p.Offering)
+	}
+	return sp, nil
+}
@@ -0,0 +1,125 @@
+package serviceinstance
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/utils/ptr"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+)
+
+// servicePlans serves a catalog of service plans and their offerings.
+type servicePlans struct {
+	plans     []*resource.ServicePlan
+	offerings map[string]*resource.ServiceOffering
+}
+
+func (s *servicePlans) Single(_ context.Context, opt *client.ServicePlanListOptions) (*resource.ServicePlan, error) {
+	var found []*resource.ServicePlan
+	for _, sp := range s.plans {
+		if matches(opt.Names, sp.Name) && matches(opt.ServiceOfferingNames, s.offerings[sp.GUID].Name) {
+			found = append(found, sp)
+		}
+	}
+	if len(found) != 1 {
+		return nil, client.ErrExactlyOneResultNotReturned
+	}
+	return found[0], nil
+}
+
+func matches(f client.Filter, v string) bool {
+	return len(f.Values) == 0 || slices.Contains(f.Values, v)
+}
+
+func (s *servicePlans) GetIncludeServicePlan(_ context.Context, guid string) (*resource.ServicePlan, *resource.ServiceOffering, error) {
+	for _, sp := range s.plans {
+		if sp.GUID == guid {
+			return sp, s.offerings[guid], nil
+		}
+	}
+	return nil, nil, resource.CloudFoundryError{Code: 10010, Title: "CF-ResourceNotFound", Detail: "Service plan not found"}
+}
+
+func TestResolveServicePlan(t *testing.T) {
+	catalog := &servicePlans{
+		plans: []*resource.ServicePlan{
+			{Resource: resource.Resource{GUID: "small-guid"}, Name: "small"},
+			{Resource: resource.Resource{GUID: "large-guid"}, Name: "large"},
+		},
+		offerings: map[string]*resource.ServiceOffering{
+			"small-guid": {Name: "postgres"},
+			"large-guid": {Name: "postgres"},
+		},
+	}
+
+	cases := map[string]struct {
+		plan    v1alpha1.ServicePlanParameters
+		current *string
+		want    string
+		err     string
+	}{
+		"Names": {
+			plan: v1alpha1.ServicePlanParameters{Offering: ptr.To("postgres"), Plan: ptr.To("small")},
+			want: "small-guid",
+		},
+		"NamesNotFound": {
+			plan: v1alpha1.ServicePlanParameters{Offering: ptr.To("postgres"), Plan: ptr.To("medium")},
+			err:  "cannot find service plan medium of offering postgres: expected exactly 1 result, but got less or more than 1",
+		},
+		"GUIDOnly": {
+			plan: v1alpha1.ServicePlanParameters{ID: ptr.To("large-guid")},
+			want: "large-guid",
+		},
+		"GUIDNotFound": {
+			plan: v1alpha1.ServicePlanParameters{ID: ptr.To("unknown-guid")},
+			err:  "cannot find service plan unknown-guid: cfclient error (CF-ResourceNotFound|10010): Service plan not found",
+		},
+		"GUIDAndNamesMatch": {
+			plan: v1alpha1.ServicePlanParameters{ID: ptr.To("large-guid"), Offering: ptr.To("postgres"), Plan: ptr.To("large")},
+			want: "large-guid",
+		},
+		"GUIDAndPlanMismatch": {
+			plan: v1alpha1.ServicePlanParameters{ID: ptr.To("large-guid"), Offering: ptr.To("postgres"), Plan: ptr.To("small")},
+			err:  "service plan large-guid is named large, not small",
+		},
+		"GUIDAndOfferingMismatch": {
+			plan: v1alpha1.ServicePlanParameters{ID: ptr.To("large-guid"), Offering: ptr.To("mysql")},
+			err:  "service plan large-guid belongs to offering postgres, not mysql",
+		},
+		"CurrentGUIDAndChangedNames": {
+			plan:    v1alpha1.ServicePlanParameters{ID: ptr.To("small-guid"), Offering: ptr.To("postgres"), Plan: ptr.To("large")},
+			current: ptr.To("small-guid"),
+			want:    "large-guid",
+		},
+		"OtherGUIDAndNamesMismatch": {
+			plan:    v1alpha1.ServicePlanParameters{ID: ptr.To("large-guid"), Offering: ptr.To("postgres"), Plan: ptr.To("small")},
+			current: ptr.To("small-guid"),
+			err:     "service plan large-guid is named large, not small",
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			sp, err := ResolveServicePlan(context.Background(), catalog, tc.plan, tc.current)
+			gotErr := ""
+			if err != nil {
+				gotErr = err.Error()
+			}
+			if diff := cmp.Diff(tc.err, gotErr); diff != "" {
+				t.Fatalf("ResolveServicePlan(...): -want error, +got error:\n%s", diff)
+			}
+			got := ""
+			if sp != nil {
+				got = sp.GUID
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ResolveServicePlan(...): -want plan, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -140,6 +140,17 @@ func UpdateObservation(observation *v1alpha1.ServiceRouteBindingObservation, r *
 	}
 }
 
+// UpdateRouteObservation records the host, path, domain and URL of the route
+// of the binding, so that the bound route can be identified without a lookup.
+func UpdateRouteObservation(observation *v1alpha1.ServiceRouteBindingObservation, r *resource.Route) {
+	observation.RouteHost = r.Host
+	observation.RoutePath = r.Path
+	observation.RouteURL = r.URL
+	if r.Relationships.Domain.Data != nil {
+		observation.RouteDomain = r.Relationships.Domain.Data.GUID
+	}
+}
+
 // builds links map from CF links
 func buildLinks(cfLinks cfresource.Links) v1alpha1.Links {
 	if cfLinks == nil {
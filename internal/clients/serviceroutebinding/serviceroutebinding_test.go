@@ -296,6 +296,47 @@ func TestUpdateObservation(t *testing.T) {
 	}
 }
 
+func TestUpdateRouteObservation(t *testing.T) {
+	cases := map[string]struct {
+		route *cfresource.Route
+		want  v1alpha1.ServiceRouteBindingObservation
+	}{
+		"PathOnInternalDomain": {
+			route: &cfresource.Route{
+				Host: "api",
+				Path: "/v1/orders",
+				URL:  "api.apps.internal/v1/orders",
+				Relationships: cfresource.RouteRelationships{
+					Domain: cfresource.ToOneRelationship{Data: &cfresource.Relationship{GUID: "domain-guid"}},
+				},
+			},
+			want: v1alpha1.ServiceRouteBindingObservation{
+				RouteHost:   "api",
+				RoutePath:   "/v1/orders",
+				RouteDomain: "domain-guid",
+				RouteURL:    "api.apps.internal/v1/orders",
+			},
+		},
+		"WithoutHostAndDomain": {
+			route: &cfresource.Route{Path: "/v1", URL: "example.com/v1"},
+			want: v1alpha1.ServiceRouteBindingObservation{
+				RoutePath: "/v1",
+				RouteURL:  "example.com/v1",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := v1alpha1.ServiceRouteBindingObservation{}
+			UpdateRouteObservation(&got, tc.route)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("UpdateRouteObservation(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestBuildLinks(t *testing.T) {
 	type args struct {
 		cfLinks cfresource.Links
@@ -0,0 +1,161 @@
+package space
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
+)
+
+// TypeDeletionBlocked is the condition type that reports the contents of a space that block its deletion.
+const TypeDeletionBlocked xpv1.ConditionType = "DeletionBlocked"
+
+// ReasonNotEmpty is the reason of the DeletionBlocked condition.
+const ReasonNotEmpty xpv1.ConditionReason = "NotEmpty"
+
+// Kinds of the contents of a space, in the order they are deleted.
+const (
+	ContentApp             = "app"
+	ContentRoute           = "route"
+	ContentServiceInstance = "service instance"
+)
+
+// maxContentNames is the number of names of each kind of content that DescribeContents lists.
+const maxContentNames = 5
+
+// Content is an app, route or service instance in a space.
+type Content struct {
+	Kind string
+	GUID string
+	Name string
+}
+
+// Contents is the interface that defines the methods to list and delete the contents of a space.
+type Contents interface {
+	// ListContents returns the apps, routes and service instances of the space, in the order they are deleted.
+	ListContents(ctx context.Context, spaceGUID string) ([]Content, error)
+	// DeleteContent starts the deletion of an app, route or service instance.
+	DeleteContent(ctx context.Context, content Content) error
+}
+
+type contentsClient struct {
+	apps             *client.AppClient
+	routes           *client.RouteClient
+	serviceInstances *client.ServiceInstanceClient
+}
+
+// NewContentsClient creates a new client to list and delete the contents of a space.
+func NewContentsClient(cf *client.Client) Contents {
+	return &contentsClient{apps: cf.Applications, routes: cf.Routes, serviceInstances: cf.ServiceInstances}
+}
+
+// ListContents implements Contents.
+func (c *contentsClient) ListContents(ctx context.Context, spaceGUID string) ([]Content, error) {
+	var contents []Content
+
+	appOpts := client.NewAppListOptions()
+	appOpts.SpaceGUIDs.EqualTo(spaceGUID)
+	apps, err := c.apps.ListAll(ctx, appOpts)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot list apps")
+	}
+	for _, a := range apps {
+		contents = append(contents, Content{Kind: ContentApp, GUID: a.GUID, Name: a.Name})
+	}
+
+	routeOpts := client.NewRouteListOptions()
+	routeOpts.SpaceGUIDs.EqualTo(spaceGUID)
+	routes, err := c.routes.ListAll(ctx, routeOpts)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot list routes")
+	}
+	for _, r := range routes {
+		contents = append(contents, Content{Kind: ContentRoute, GUID: r.GUID, Name: r.URL})
+	}
+
+	siOpts := client.NewServiceInstanceListOptions()
+	siOpts.SpaceGUIDs.EqualTo(spaceGUID)
+	serviceInstances, err := c.serviceInstances.ListAll(ctx, siOpts)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot list service instances")
+	}
+	for _, si := range serviceInstances {
+		contents = append(contents, Content{Kind: ContentServiceInstance, GUID: si.GUID, Name: si.Name})
+	}
+	return contents, nil
+}
+
+// DeleteContent implements Contents.
+func (c *contentsClient) DeleteContent(ctx context.Context, content Content) error {
+	var err error
+	switch content.Kind {
+	case ContentApp:
+		_, err = c.apps.Delete(ctx, content.GUID)
+	case ContentRoute:
+		_, err = c.routes.Delete(ctx, content.GUID)
+	case ContentServiceInstance:
+		_, err = c.serviceInstances.Delete(ctx, content.GUID)
+	default:
+		return errors.Errorf("unknown content %s", content.Kind)
+	}
+	return err
+}
+
+// DeleteContents starts the deletion of the given contents of a space. Contents
+// that are already gone or whose deletion is already in progress are skipped.
+func DeleteContents(ctx context.Context, c Contents, contents []Content) error {
+	for _, content := range contents {
+		err := c.DeleteContent(ctx, content)
+		if err != nil && !clients.IsNotFound(err) && !resource.IsAsyncServiceInstanceOperationInProgressError(err) {
+			return errors.Wrapf(err, "cannot delete %s %s", content.Kind, content.Name)
+		}
+	}
+	return nil
+}
+
+// DescribeContents returns how many contents of each kind there are and the
+// names of the first ones, e.g. `2 apps (a, b), 1 route (a.example.com)`.
+func DescribeContents(contents []Content) string {
+	names := map[string][]string{}
+	for _, c := range contents {
+		names[c.Kind] = append(names[c.Kind], c.Name)
+	}
+
+	var parts []string
+	for _, kind := range []string{ContentApp, ContentRoute, ContentServiceInstance} {
+		n := len(names[kind])
+		if n == 0 {
+			continue
+		}
+		listed := names[kind]
+		if n > maxContentNames {
+			listed = append(listed[:maxContentNames:maxContentNames], "...")
+		}
+		plural := kind
+		if n != 1 {
+			plural += "s"
+		}
+		parts = append(parts, fmt.Sprintf("%d %s (%s)", n, plural, strings.Join(listed, ", ")))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// DeletionBlocked returns a condition that reports the contents that block
+// the deletion of a space.
+func DeletionBlocked(contents []Content) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDeletionBlocked,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNotEmpty,
+		Message:            "the space contains " + DescribeContents(contents) + ", delete them or set forceDelete to delete them with the space",
+	}
+}
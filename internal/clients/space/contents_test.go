@@ -0,0 +1,48 @@
+package space
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDescribeContents(t *testing.T) {
+	cases := map[string]struct {
+		contents []Content
+		want     string
+	}{
+		"Empty": {
+			want: "",
+		},
+		"Singular": {
+			contents: []Content{{Kind: ContentRoute, Name: "a.example.com"}},
+			want:     "1 route (a.example.com)",
+		},
+		"GroupedByKind": {
+			contents: []Content{
+				{Kind: ContentServiceInstance, Name: "db"},
+				{Kind: ContentApp, Name: "a"},
+				{Kind: ContentApp, Name: "b"},
+			},
+			want: "2 apps (a, b), 1 service instance (db)",
+		},
+		"Capped": {
+			contents: []Content{
+				{Kind: ContentApp, Name: "a"},
+				{Kind: ContentApp, Name: "b"},
+				{Kind: ContentApp, Name: "c"},
+				{Kind: ContentApp, Name: "d"},
+				{Kind: ContentApp, Name: "e"},
+				{Kind: ContentApp, Name: "f"},
+			},
+			want: "6 apps (a, b, c, d, e, ...)",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, DescribeContents(tc.contents)); diff != "" {
+				t.Errorf("DescribeContents(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package space
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+)
+
+// FeatureSSH is the space feature that allows SSH to application containers.
+const FeatureSSH = "ssh"
+
+// Feature is the interface that defines the methods that a Feature client should implement.
+type Feature interface {
+	// ListFeatures returns whether each feature of the space is enabled, by feature name.
+	ListFeatures(ctx context.Context, spaceGUID string) (map[string]bool, error)
+	// EnableFeature enables or disables a feature of the space.
+	EnableFeature(ctx context.Context, spaceGUID, name string, enable bool) error
+}
+
+// featureClient implements Feature on the space features endpoints of the
+// CF API, as go-cfclient only supports the ssh feature.
+type featureClient struct {
+	cf *client.Client
+}
+
+// NewFeatureClient returns a Feature client for the given cf client.
+func NewFeatureClient(cf *client.Client) Feature {
+	return &featureClient{cf: cf}
+}
+
+// ListFeatures implements Feature.
+func (c *featureClient) ListFeatures(ctx context.Context, spaceGUID string) (map[string]bool, error) {
+	var list struct {
+		Resources []resource.SpaceFeature `json:"resources"`
+	}
+	if err := do(ctx, c.cf, http.MethodGet, "/v3/spaces/"+url.PathEscape(spaceGUID)+"/features", nil, &list); err != nil {
+		return nil, err
+	}
+	features := make(map[string]bool, len(list.Resources))
+	for _, f := range list.Resources {
+		features[f.Name] = f.Enabled
+	}
+	return features, nil
+}
+
+// EnableFeature implements Feature.
+func (c *featureClient) EnableFeature(ctx context.Context, spaceGUID, name string, enable bool) error {
+	body, err := json.Marshal(resource.SpaceFeatureUpdate{Enabled: enable})
+	if err != nil {
+		return err
+	}
+	return do(ctx, c.cf, http.MethodPatch, "/v3/spaces/"+url.PathEscape(spaceGUID)+"/features/"+url.PathEscape(name), body, nil)
+}
+
+// do sends an authenticated request to the CF API and decodes its response
+// into result, unless result is nil. Unsuccessful responses are returned as
+// the CF errors they carry.
+func do(ctx context.Context, cf *client.Client, method, path string, body []byte, result any) error {
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, cf.ApiURL(path), r)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := cf.ExecuteAuthRequest(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// DesiredFeatures returns the features of the space as the spec wants them.
+// The ssh feature is always managed: it follows `allowSsh` unless `features`
+// sets it. Other features are only managed if `features` sets them.
+func DesiredFeatures(spec v1alpha1.SpaceParameters) map[string]bool {
+	desired := make(map[string]bool, len(spec.Features)+1)
+	desired[FeatureSSH] = spec.AllowSSH
+	for name, enabled := range spec.Features {
+		desired[name] = enabled
+	}
+	return desired
+}
+
+// FeatureChanges returns the names of the desired features whose observed
+// state differs, in a stable order. A feature that is not observed counts as
+// disabled.
+func FeatureChanges(desired, observed map[string]bool) []string {
+	var changes []string
+	for name, enabled := range desired {
+		if observed[name] != enabled {
+			changes = append(changes, name)
+		}
+	}
+	slices.Sort(changes)
+	return changes
+}
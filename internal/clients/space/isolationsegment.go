@@ -0,0 +1,68 @@
+package space
+
+import (
+	"context"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
+)
+
+// IsolationSegment is the interface that defines the methods to look up isolation segments.
+type IsolationSegment interface {
+	Single(ctx context.Context, opts *client.IsolationSegmentListOptions) (*resource.IsolationSegment, error)
+}
+
+// NewIsolationSegmentClient creates a new client to look up isolation segments.
+func NewIsolationSegmentClient(cf *client.Client) IsolationSegment {
+	return cf.IsolationSegments
+}
+
+// ResolveIsolationSegmentByName sets the isolation segment of a Space to the GUID of the isolation segment named in `isolationSegmentName`.
+func ResolveIsolationSegmentByName(ctx context.Context, clientFn clients.ClientFn, cr *v1alpha1.Space) error {
+	if cr.Spec.ForProvider.IsolationSegmentName == nil {
+		// nothing to resolve.
+		return nil
+	}
+
+	cf, err := clientFn(cr)
+	if err != nil {
+		return errors.Wrap(err, "Could not connect to Cloud Foundry")
+	}
+	segmentGUID, err := GetIsolationSegmentGUID(ctx, NewIsolationSegmentClient(cf), *cr.Spec.ForProvider.IsolationSegmentName)
+	if err != nil {
+		return errors.Wrap(err, "Cannot resolve isolation segment reference by name")
+	}
+	cr.Spec.ForProvider.IsolationSegment = segmentGUID
+	return nil
+}
+
+// GetIsolationSegmentGUID returns the GUID of an isolation segment by name.
+func GetIsolationSegmentGUID(ctx context.Context, c IsolationSegment, name string) (*string, error) {
+	if name == "" {
+		return nil, errors.New("isolationSegmentName is empty")
+	}
+	segment, err := c.Single(ctx, &client.IsolationSegmentListOptions{Names: client.Filter{Values: []string{name}}})
+	if err != nil {
+		return nil, err
+	}
+	return &segment.GUID, nil
+}
+
+// IsIsolationSegmentUpToDate checks whether the isolation segment assigned to a space is the one in the spec.
+// A Space without isolation segment in its spec leaves the assignment as it is.
+func IsIsolationSegmentUpToDate(spec v1alpha1.SpaceParameters, assigned string) bool {
+	return spec.IsolationSegment == nil || *spec.IsolationSegment == assigned
+}
+
+// IsolationSegmentObservation returns the assigned isolation segment for the observation of a space.
+func IsolationSegmentObservation(assigned string) *string {
+	if assigned == "" {
+		return nil
+	}
+	return ptr.To(assigned)
+}
@@ -0,0 +1,65 @@
+package space
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/fake"
+)
+
+func TestGetIsolationSegmentGUID(t *testing.T) {
+	errBoom := errors.New("boom")
+	segGUID := "4d8b0d04-d537-4e4e-8c6f-f09ca0e7f56f"
+
+	tests := map[string]struct {
+		name    string
+		segment func() *fake.MockIsolationSegment
+		want    *string
+		wantErr error
+	}{
+		"Found": {
+			name: "shared",
+			segment: func() *fake.MockIsolationSegment {
+				m := &fake.MockIsolationSegment{}
+				m.On("Single", []string{"shared"}).Return(&resource.IsolationSegment{Resource: resource.Resource{GUID: segGUID}}, nil)
+				return m
+			},
+			want: ptr.To(segGUID),
+		},
+		"NotFound": {
+			name: "missing",
+			segment: func() *fake.MockIsolationSegment {
+				m := &fake.MockIsolationSegment{}
+				m.On("Single", []string{"missing"}).Return((*resource.IsolationSegment)(nil), errBoom)
+				return m
+			},
+			wantErr: errBoom,
+		},
+		"EmptyName": {
+			name: "",
+			segment: func() *fake.MockIsolationSegment {
+				// no expectations, there is nothing to look up
+				return &fake.MockIsolationSegment{}
+			},
+			wantErr: errors.New("isolationSegmentName is empty"),
+		},
+	}
+
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			got, err := GetIsolationSegmentGUID(context.Background(), tc.segment(), tc.name)
+			if diff := cmp.Diff(tc.wantErr, err, test.EquateErrors()); diff != "" {
+				t.Errorf("GetIsolationSegmentGUID(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("GetIsolationSegmentGUID(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
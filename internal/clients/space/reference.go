@@ -6,6 +6,7 @@ import (
 	"github.com/cloudfoundry/go-cfclient/v3/client"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
 	"github.com/pkg/errors"
+	k8s "sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
@@ -16,6 +17,56 @@ type SpaceScoped interface {
 	GetSpaceRef() *v1alpha1.SpaceReference
 }
 
+const (
+	errNoSpaceReference        = "SpaceReference is required: exactly one of spaceName, spaceRef, or spaceSelector must be set"
+	errMultipleSpaceReferences = "SpaceReference validation: only one of spaceName, spaceRef, or spaceSelector can be set"
+)
+
+// SpaceReferencer is a space-scoped managed resource that can resolve the
+// references of its spec, e.g. its spaceRef or spaceSelector.
+type SpaceReferencer interface {
+	resource.Managed
+	SpaceScoped
+	ResolveReferences(ctx context.Context, c k8s.Reader) error
+}
+
+// ResolveSpaceReference resolves the space of a space-scoped managed resource
+// from exactly one of its spaceName, spaceRef or spaceSelector, as required by
+// the validation rules of the space-scoped resources. A managed resource that
+// only observes may omit all of them, and a space GUID set directly is accepted
+// if it is valid.
+func ResolveSpaceReference(ctx context.Context, kube k8s.Client, mg resource.Managed) error {
+	return resolveSpaceReference(ctx, kube, clients.ClientFnBuilder(ctx, kube), mg)
+}
+
+func resolveSpaceReference(ctx context.Context, kube k8s.Reader, clientFn clients.ClientFn, mg resource.Managed) error {
+	cr, ok := mg.(SpaceReferencer)
+	if !ok {
+		return errors.New("Cannot resolve space reference. The resource does not implement SpaceReferencer")
+	}
+	sr := cr.GetSpaceRef()
+
+	set := 0
+	for _, isSet := range []bool{sr.SpaceName != nil, sr.SpaceRef != nil, sr.SpaceSelector != nil} {
+		if isSet {
+			set++
+		}
+	}
+
+	switch {
+	case set > 1:
+		return errors.New(errMultipleSpaceReferences)
+	case set == 0 && (sr.Space != nil || clients.IsObserveOnly(mg)):
+		return clients.ValidateGUID("space", sr.Space)
+	case set == 0:
+		return errors.New(errNoSpaceReference)
+	case sr.SpaceName != nil:
+		return ResolveByName(ctx, clientFn, mg)
+	default:
+		return cr.ResolveReferences(ctx, kube)
+	}
+}
+
 // ResolveByName resolves the space reference by name.
 func ResolveByName(ctx context.Context, clientFn clients.ClientFn, mg resource.Managed) error {
 	cr, ok := mg.(SpaceScoped)
@@ -39,7 +90,10 @@ func ResolveByName(ctx context.Context, clientFn clients.ClientFn, mg resource.M
 		return nil
 	}
 
-	// nothing to resolve.
+	// nothing to resolve, but a space GUID set directly must be valid.
+	if sr != nil {
+		return clients.ValidateGUID("space", sr.Space)
+	}
 	return nil
 }
 
@@ -0,0 +1,106 @@
+package space
+
+import (
+	"context"
+	"testing"
+
+	cfv3 "github.com/cloudfoundry/go-cfclient/v3/client"
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	k8s "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+)
+
+func app(sr v1alpha1.SpaceReference) *v1alpha1.App {
+	a := &v1alpha1.App{}
+	a.SetNamespace("default")
+	a.Spec.ForProvider.SpaceReference = sr
+	return a
+}
+
+func TestResolveSpaceReference(t *testing.T) {
+	errBoom := errors.New("boom")
+	referenced := &v1alpha1.Space{}
+	referenced.SetName("space")
+	referenced.Status.AtProvider.ID = spaceGUID
+
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, _ k8s.ObjectKey, obj k8s.Object) error {
+			referenced.DeepCopyInto(obj.(*v1alpha1.Space))
+			return nil
+		},
+		MockList: func(_ context.Context, obj k8s.ObjectList, _ ...k8s.ListOption) error {
+			obj.(*v1alpha1.SpaceList).Items = []v1alpha1.Space{*referenced}
+			return nil
+		},
+	}
+	clientFn := func(resource.Managed) (*cfv3.Client, error) { return nil, errBoom }
+
+	observeOnly := app(v1alpha1.SpaceReference{})
+	observeOnly.Spec.ManagementPolicies = xpv1.ManagementPolicies{xpv1.ManagementActionObserve}
+
+	cases := map[string]struct {
+		mg        *v1alpha1.App
+		wantErr   string
+		wantSpace *string
+	}{
+		"None": {
+			mg:      app(v1alpha1.SpaceReference{}),
+			wantErr: errNoSpaceReference,
+		},
+		"NameAndRef": {
+			mg:      app(v1alpha1.SpaceReference{SpaceName: ptr.To("dev"), OrgName: ptr.To("org"), SpaceRef: &xpv1.NamespacedReference{Name: "space"}}),
+			wantErr: errMultipleSpaceReferences,
+		},
+		"RefAndSelector": {
+			mg:      app(v1alpha1.SpaceReference{SpaceRef: &xpv1.NamespacedReference{Name: "space"}, SpaceSelector: &xpv1.NamespacedSelector{}}),
+			wantErr: errMultipleSpaceReferences,
+		},
+		"ObserveOnly": {
+			mg: observeOnly,
+		},
+		"GUID": {
+			mg:        app(v1alpha1.SpaceReference{Space: ptr.To(spaceGUID)}),
+			wantSpace: ptr.To(spaceGUID),
+		},
+		"InvalidGUID": {
+			mg:        app(v1alpha1.SpaceReference{Space: ptr.To("dev")}),
+			wantErr:   `space "dev" is not a valid GUID`,
+			wantSpace: ptr.To("dev"),
+		},
+		"Name": {
+			// the name is looked up in Cloud Foundry
+			mg:      app(v1alpha1.SpaceReference{SpaceName: ptr.To("dev"), OrgName: ptr.To("org")}),
+			wantErr: "Could not connect to Cloud Foundry: boom",
+		},
+		"Ref": {
+			mg:        app(v1alpha1.SpaceReference{SpaceRef: &xpv1.NamespacedReference{Name: "space"}}),
+			wantSpace: ptr.To(spaceGUID),
+		},
+		"Selector": {
+			mg:        app(v1alpha1.SpaceReference{SpaceSelector: &xpv1.NamespacedSelector{MatchLabels: map[string]string{"env": "dev"}}}),
+			wantSpace: ptr.To(spaceGUID),
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			err := resolveSpaceReference(context.Background(), kube, clientFn, tc.mg)
+			got := ""
+			if err != nil {
+				got = err.Error()
+			}
+			if diff := cmp.Diff(tc.wantErr, got); diff != "" {
+				t.Errorf("resolveSpaceReference(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantSpace, tc.mg.Spec.ForProvider.Space); diff != "" {
+				t.Errorf("resolveSpaceReference(...): -want space, +got space:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package space
+
+import (
+	"context"
+	"slices"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/pkg/errors"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
+)
+
+// SecurityGroup is the interface that defines the methods to bind application security groups to a space.
+type SecurityGroup interface {
+	ListAll(ctx context.Context, opts *client.SecurityGroupListOptions) ([]*resource.SecurityGroup, error)
+	BindRunningSecurityGroup(ctx context.Context, guid string, spaceGUIDs []string) ([]string, error)
+	BindStagingSecurityGroup(ctx context.Context, guid string, spaceGUIDs []string) ([]string, error)
+	UnBindRunningSecurityGroup(ctx context.Context, guid string, spaceGUID string) error
+	UnBindStagingSecurityGroup(ctx context.Context, guid string, spaceGUID string) error
+}
+
+// NewSecurityGroupClient creates a new client to bind application security groups to a space.
+func NewSecurityGroupClient(cf *client.Client) SecurityGroup {
+	return cf.SecurityGroups
+}
+
+// securityGroupBinding holds the client methods of either running or staging security group bindings.
+type securityGroupBinding struct {
+	lifecycle string
+	filter    func(opts *client.SecurityGroupListOptions) *client.Filter
+	bind      func(ctx context.Context, guid string, spaceGUIDs []string) ([]string, error)
+	unbind    func(ctx context.Context, guid string, spaceGUID string) error
+}
+
+func runningBinding(c SecurityGroup) securityGroupBinding {
+	return securityGroupBinding{
+		lifecycle: "running",
+		filter:    func(opts *client.SecurityGroupListOptions) *client.Filter { return &opts.RunningSpaceGUIDs },
+		bind:      c.BindRunningSecurityGroup,
+		unbind:    c.UnBindRunningSecurityGroup,
+	}
+}
+
+func stagingBinding(c SecurityGroup) securityGroupBinding {
+	return securityGroupBinding{
+		lifecycle: "staging",
+		filter:    func(opts *client.SecurityGroupListOptions) *client.Filter { return &opts.StagingSpaceGUIDs },
+		bind:      c.BindStagingSecurityGroup,
+		unbind:    c.UnBindStagingSecurityGroup,
+	}
+}
+
+// GetSecurityGroups returns the names of the running and staging security groups bound to a space.
+// Only the bindings managed by the spec are looked up, the others are returned as nil.
+func GetSecurityGroups(ctx context.Context, c SecurityGroup, spaceGUID string, spec v1alpha1.SpaceParameters) (running, staging []string, err error) {
+	if spec.RunningSecurityGroups != nil {
+		if running, err = boundSecurityGroupNames(ctx, c, runningBinding(c), spaceGUID); err != nil {
+			return nil, nil, err
+		}
+	}
+	if spec.StagingSecurityGroups != nil {
+		if staging, err = boundSecurityGroupNames(ctx, c, stagingBinding(c), spaceGUID); err != nil {
+			return nil, nil, err
+		}
+	}
+	return running, staging, nil
+}
+
+// IsSecurityGroupsUpToDate checks whether the security groups bound to a space are the ones in the spec.
+// A Space without running or staging security groups in its spec leaves those bindings as they are.
+func IsSecurityGroupsUpToDate(spec v1alpha1.SpaceParameters, observed v1alpha1.SpaceObservation) bool {
+	return (spec.RunningSecurityGroups == nil || sameNames(spec.RunningSecurityGroups, observed.RunningSecurityGroups)) &&
+		(spec.StagingSecurityGroups == nil || sameNames(spec.StagingSecurityGroups, observed.StagingSecurityGroups))
+}
+
+// BindSecurityGroups binds the security groups in the spec to a space and
+// unbinds those that are no longer in the spec.
+func BindSecurityGroups(ctx context.Context, c SecurityGroup, spaceGUID string, spec v1alpha1.SpaceParameters) error {
+	if spec.RunningSecurityGroups != nil {
+		if err := reconcileSecurityGroups(ctx, c, runningBinding(c), spaceGUID, spec.RunningSecurityGroups); err != nil {
+			return err
+		}
+	}
+	if spec.StagingSecurityGroups != nil {
+		if err := reconcileSecurityGroups(ctx, c, stagingBinding(c), spaceGUID, spec.StagingSecurityGroups); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UnbindSecurityGroups unbinds all running and staging security groups managed by the spec from a space.
+func UnbindSecurityGroups(ctx context.Context, c SecurityGroup, spaceGUID string, spec v1alpha1.SpaceParameters) error {
+	if spec.RunningSecurityGroups != nil {
+		if err := reconcileSecurityGroups(ctx, c, runningBinding(c), spaceGUID, []string{}); err != nil {
+			return err
+		}
+	}
+	if spec.StagingSecurityGroups != nil {
+		if err := reconcileSecurityGroups(ctx, c, stagingBinding(c), spaceGUID, []string{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func reconcileSecurityGroups(ctx context.Context, c SecurityGroup, b securityGroupBinding, spaceGUID string, desired []string) error {
+	bound, err := boundSecurityGroups(ctx, c, b, spaceGUID)
+	if err != nil {
+		return err
+	}
+
+	boundNames := make([]string, 0, len(bound))
+	for _, sg := range bound {
+		boundNames = append(boundNames, sg.Name)
+		if slices.Contains(desired, sg.Name) {
+			continue
+		}
+		if err := b.unbind(ctx, sg.GUID, spaceGUID); err != nil && !clients.IsNotFound(err) {
+			return errors.Wrapf(err, "cannot unbind %s security group %q", b.lifecycle, sg.Name)
+		}
+	}
+
+	missing := []string{}
+	for _, name := range desired {
+		if !slices.Contains(boundNames, name) && !slices.Contains(missing, name) {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	opts := client.NewSecurityGroupListOptions()
+	opts.Names.EqualTo(missing...)
+	groups, err := c.ListAll(ctx, opts)
+	if err != nil {
+		return errors.Wrap(err, "cannot look up security groups")
+	}
+	for _, name := range missing {
+		i := slices.IndexFunc(groups, func(sg *resource.SecurityGroup) bool { return sg.Name == name })
+		if i < 0 {
+			return errors.Errorf("security group %q not found", name)
+		}
+		if _, err := b.bind(ctx, groups[i].GUID, []string{spaceGUID}); err != nil {
+			return errors.Wrapf(err, "cannot bind %s security group %q", b.lifecycle, name)
+		}
+	}
+	return nil
+}
+
+func boundSecurityGroups(ctx context.Context, c SecurityGroup, b securityGroupBinding, spaceGUID string) ([]*resource.SecurityGroup, error) {
+	opts := client.NewSecurityGroupListOptions()
+	b.filter(opts).EqualTo(spaceGUID)
+	groups, err := c.ListAll(ctx, opts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot list %s security groups", b.lifecycle)
+	}
+	return groups, nil
+}
+
+func boundSecurityGroupNames(ctx context.Context, c SecurityGroup, b securityGroupBinding, spaceGUID string) ([]string, error) {
+	groups, err := boundSecurityGroups(ctx, c, b, spaceGUID)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(groups))
+	for _, sg := range groups {
+		names = append(names, sg.Name)
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// sameNames reports whether two lists contain the same names, regardless of order and duplicates.
+func sameNames(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(slices.Compact(a), slices.Compact(b))
+}
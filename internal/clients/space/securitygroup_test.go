@@ -0,0 +1,126 @@
+package space
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/fake"
+)
+
+const spaceGUID = "2d8b0d04-d537-4e4e-8c6f-f09ca0e7f56f"
+
+func securityGroup(name, guid string) *resource.SecurityGroup {
+	return &resource.SecurityGroup{Name: name, Resource: resource.Resource{GUID: guid}}
+}
+
+func TestBindSecurityGroups(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	tests := map[string]struct {
+		spec    v1alpha1.SpaceParameters
+		groups  func() *fake.MockSecurityGroup
+		wantErr error
+	}{
+		"Unmanaged": {
+			spec: v1alpha1.SpaceParameters{},
+			groups: func() *fake.MockSecurityGroup {
+				// no expectations, the bindings are left as they are
+				return &fake.MockSecurityGroup{}
+			},
+		},
+		"BindAndUnbindRunning": {
+			spec: v1alpha1.SpaceParameters{RunningSecurityGroups: []string{"dns", "db"}},
+			groups: func() *fake.MockSecurityGroup {
+				m := &fake.MockSecurityGroup{}
+				m.On("ListAll", []string(nil), []string{spaceGUID}, []string(nil)).Return([]*resource.SecurityGroup{securityGroup("dns", "dns-guid"), securityGroup("public", "public-guid")}, nil)
+				m.On("UnBindRunningSecurityGroup", "public-guid", spaceGUID).Return(nil)
+				m.On("ListAll", []string{"db"}, []string(nil), []string(nil)).Return([]*resource.SecurityGroup{securityGroup("db", "db-guid")}, nil)
+				m.On("BindRunningSecurityGroup", "db-guid", []string{spaceGUID}).Return(nil)
+				return m
+			},
+		},
+		"UnbindAllStaging": {
+			spec: v1alpha1.SpaceParameters{StagingSecurityGroups: []string{}},
+			groups: func() *fake.MockSecurityGroup {
+				m := &fake.MockSecurityGroup{}
+				m.On("ListAll", []string(nil), []string(nil), []string{spaceGUID}).Return([]*resource.SecurityGroup{securityGroup("public", "public-guid")}, nil)
+				m.On("UnBindStagingSecurityGroup", "public-guid", spaceGUID).Return(nil)
+				return m
+			},
+		},
+		"SecurityGroupNotFound": {
+			spec: v1alpha1.SpaceParameters{RunningSecurityGroups: []string{"missing"}},
+			groups: func() *fake.MockSecurityGroup {
+				m := &fake.MockSecurityGroup{}
+				m.On("ListAll", []string(nil), []string{spaceGUID}, []string(nil)).Return([]*resource.SecurityGroup{}, nil)
+				m.On("ListAll", []string{"missing"}, []string(nil), []string(nil)).Return([]*resource.SecurityGroup{}, nil)
+				return m
+			},
+			wantErr: errors.New(`security group "missing" not found`),
+		},
+		"ListError": {
+			spec: v1alpha1.SpaceParameters{RunningSecurityGroups: []string{"dns"}},
+			groups: func() *fake.MockSecurityGroup {
+				m := &fake.MockSecurityGroup{}
+				m.On("ListAll", []string(nil), []string{spaceGUID}, []string(nil)).Return([]*resource.SecurityGroup{}, errBoom)
+				return m
+			},
+			wantErr: errors.Wrap(errBoom, "cannot list running security groups"),
+		},
+	}
+
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			m := tc.groups()
+			err := BindSecurityGroups(context.Background(), m, spaceGUID, tc.spec)
+			if diff := cmp.Diff(tc.wantErr, err, test.EquateErrors()); diff != "" {
+				t.Errorf("BindSecurityGroups(...): -want error, +got error:\n%s", diff)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIsSecurityGroupsUpToDate(t *testing.T) {
+	tests := map[string]struct {
+		spec     v1alpha1.SpaceParameters
+		observed v1alpha1.SpaceObservation
+		want     bool
+	}{
+		"Unmanaged": {
+			spec:     v1alpha1.SpaceParameters{},
+			observed: v1alpha1.SpaceObservation{RunningSecurityGroups: []string{"dns"}},
+			want:     true,
+		},
+		"SameInOtherOrder": {
+			spec:     v1alpha1.SpaceParameters{RunningSecurityGroups: []string{"dns", "db"}},
+			observed: v1alpha1.SpaceObservation{RunningSecurityGroups: []string{"db", "dns"}},
+			want:     true,
+		},
+		"MissingRunning": {
+			spec:     v1alpha1.SpaceParameters{RunningSecurityGroups: []string{"dns", "db"}},
+			observed: v1alpha1.SpaceObservation{RunningSecurityGroups: []string{"dns"}},
+			want:     false,
+		},
+		"ExtraStaging": {
+			spec:     v1alpha1.SpaceParameters{StagingSecurityGroups: []string{}},
+			observed: v1alpha1.SpaceObservation{StagingSecurityGroups: []string{"public"}},
+			want:     false,
+		},
+	}
+
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			got := IsSecurityGroupsUpToDate(tc.spec, tc.observed)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("IsSecurityGroupsUpToDate(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -2,10 +2,13 @@ package space
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/cloudfoundry/go-cfclient/v3/client"
 	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
 	"k8s.io/utils/ptr"
 
 	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
@@ -13,6 +16,13 @@ import (
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/org"
 )
 
+const (
+	// SpaceGUIDKey is the connection detail that holds the GUID of the space.
+	SpaceGUIDKey = "spaceGuid"
+	// OrgGUIDKey is the connection detail that holds the GUID of the org of the space.
+	OrgGUIDKey = "orgGuid"
+)
+
 // Space is the interface that defines the methods that a Space client should implement.
 type Space interface {
 	Get(ctx context.Context, guid string) (*resource.Space, error)
@@ -20,27 +30,27 @@ type Space interface {
 	Create(ctx context.Context, r *resource.SpaceCreate) (*resource.Space, error)
 	Update(ctx context.Context, guid string, r *resource.SpaceUpdate) (*resource.Space, error)
 	Delete(ctx context.Context, guid string) (string, error)
-}
-
-// Feature is the interface that defines the methods that a Feature client should implement.
-type Feature interface {
-	EnableSSH(ctx context.Context, spaceGUID string, enable bool) error
-	IsSSHEnabled(ctx context.Context, spaceGUID string) (bool, error)
+	GetAssignedIsolationSegment(ctx context.Context, guid string) (string, error)
+	AssignIsolationSegment(ctx context.Context, guid, isolationSegmentGUID string) error
 }
 
 // NewClient creates a new cf client and return interfaces for Space and SpaceFeatures
 func NewClient(cf *client.Client) (Space, Feature, org.Client) {
 
-	return cf.Spaces, cf.SpaceFeatures, cf.Organizations
+	return cf.Spaces, NewFeatureClient(cf), cf.Organizations
 }
 
 // GetByIDOrSpec retrieves a Space by its GUID or by its specification.
-func GetByIDOrSpec(ctx context.Context, spaceClient Space, guid string, spec v1alpha1.SpaceParameters) (*resource.Space, error) {
+// If the space was created recently, a lookup by specification retries briefly, as it may not be listed yet.
+func GetByIDOrSpec(ctx context.Context, spaceClient Space, guid string, spec v1alpha1.SpaceParameters, created time.Time) (*resource.Space, error) {
 	if clients.IsValidGUID(guid) {
 		return spaceClient.Get(ctx, guid)
 	}
 
-	return spaceClient.Single(ctx, GenerateListOption(spec))
+	opts := GenerateListOption(spec)
+	return clients.ReadAfterWrite(ctx, created, func(ctx context.Context) (*resource.Space, error) {
+		return spaceClient.Single(ctx, opts)
+	})
 }
 
 // GenerateListOption generates the list options for the client.
@@ -58,27 +68,46 @@ func GenerateListOption(spec v1alpha1.SpaceParameters) *client.SpaceListOptions
 	return opts
 }
 
+// nameTakenDetail is the detail of the error the v3 API returns when a space
+// with the name exists in the org.
+const nameTakenDetail = "Name must be unique per organization"
+
+// IsNameTakenError returns whether err reports that a space with the name
+// exists in the org. The v3 API reports it as CF-UnprocessableEntity, the v2
+// API as CF-SpaceNameTaken.
+func IsNameTakenError(err error) bool {
+	if resource.IsSpaceNameTakenError(err) {
+		return true
+	}
+	var cfErr resource.CloudFoundryError
+	return resource.IsUnprocessableEntityError(err) && errors.As(err, &cfErr) && strings.Contains(cfErr.Detail, nameTakenDetail)
+}
+
 // GenerateCreate generates the SpaceCreate from an *SpaceParameters
 func GenerateCreate(spec v1alpha1.SpaceParameters) *resource.SpaceCreate {
 	org := ptr.Deref(spec.Org, "")
-	return resource.NewSpaceCreate(spec.Name, org)
+	create := resource.NewSpaceCreate(spec.Name, org)
+	create.Metadata = clients.NewMetadata(spec.Labels, spec.Annotations)
+	return create
 }
 
-// GenerateUpdate generates the SpaceCreate from an *SpaceParameters
-func GenerateUpdate(spec v1alpha1.SpaceParameters) *resource.SpaceUpdate {
+// GenerateUpdate generates the SpaceUpdate from an *SpaceParameters. Managed
+// labels and annotations in `observed` that are no longer in the spec are removed.
+func GenerateUpdate(spec v1alpha1.SpaceParameters, managed v1alpha1.ManagedMetadata, observed *resource.Metadata) *resource.SpaceUpdate {
 	return &resource.SpaceUpdate{
 		Name:     spec.Name,
-		Metadata: &resource.Metadata{},
+		Metadata: clients.MetadataPatch(spec.Labels, spec.Annotations, managed, observed),
 	}
 }
 
 // GenerateObservation takes an Space resource and returns *SpaceObservation.
-func GenerateObservation(o *resource.Space, ssh bool) v1alpha1.SpaceObservation {
+func GenerateObservation(o *resource.Space, features map[string]bool) v1alpha1.SpaceObservation {
 	obs := v1alpha1.SpaceObservation{
 		ID:        o.GUID,
 		Name:      o.Name,
 		Org:       o.Relationships.Organization.Data.GUID,
-		AllowSSH:  ssh,
+		AllowSSH:  features[FeatureSSH],
+		Features:  features,
 		CreatedAt: ptr.To(o.CreatedAt.Format(time.RFC3339)),
 		UpdatedAt: ptr.To(o.UpdatedAt.Format(time.RFC3339)),
 	}
@@ -92,21 +121,26 @@ func GenerateObservation(o *resource.Space, ssh bool) v1alpha1.SpaceObservation
 	return obs
 }
 
+// GetConnectionDetails returns the GUIDs of the space and of its org as
+// connection details, so that compositions do not need to patch them from the
+// status. The org GUID is taken from the observed relationship of the space.
+func GetConnectionDetails(o *resource.Space) managed.ConnectionDetails {
+	details := managed.ConnectionDetails{SpaceGUIDKey: []byte(o.GUID)}
+	if o.Relationships.Organization != nil && o.Relationships.Organization.Data != nil {
+		details[OrgGUIDKey] = []byte(o.Relationships.Organization.Data.GUID)
+	}
+	return details
+}
+
 // LateInitialize fills the unassigned fields with values from a Space resource.
-func LateInitialize(cr *v1alpha1.Space, from *resource.Space, ssh bool) bool {
+func LateInitialize(cr *v1alpha1.Space, from *resource.Space, features map[string]bool) bool {
 	// nothing to late initialize
 	return false
 }
 
 // IsUpToDate checks whether current state is up-to-date compared to the given
-// set of parameters.
-func IsUpToDate(spec v1alpha1.SpaceParameters, observed *resource.Space, ssh bool) bool {
-	// rename or update ssh setting
-	return spec.Name == observed.Name && (spec.AllowSSH == ssh)
-
-}
-
-// IsSSHEnabled checks whether SSH is enabled for the given space.
-func IsSSHEnabled(ctx context.Context, f Feature, spaceGUID string) (bool, error) {
-	return f.IsSSHEnabled(ctx, spaceGUID)
+// set of parameters and managed labels and annotations.
+func IsUpToDate(spec v1alpha1.SpaceParameters, managed v1alpha1.ManagedMetadata, observed *resource.Space, features map[string]bool) bool {
+	// rename, toggle features or update metadata
+	return spec.Name == observed.Name && len(FeatureChanges(DesiredFeatures(spec), features)) == 0 && clients.MetadataEqual(spec.Labels, spec.Annotations, managed, observed.Metadata)
 }
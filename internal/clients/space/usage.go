@@ -0,0 +1,48 @@
+package space
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+)
+
+// Usage is the interface that defines the methods to get the resource usage of a space.
+type Usage interface {
+	// GetUsageSummary returns the resources the space currently uses, or nil
+	// if the CF API returns none of them.
+	GetUsageSummary(ctx context.Context, spaceGUID string) (*v1alpha1.SpaceUsage, error)
+}
+
+// usageClient implements Usage on the usage summary endpoint of the CF API,
+// as go-cfclient only supports it for orgs.
+type usageClient struct {
+	cf *client.Client
+}
+
+// NewUsageClient returns a Usage client for the given cf client.
+func NewUsageClient(cf *client.Client) Usage {
+	return &usageClient{cf: cf}
+}
+
+// GetUsageSummary implements Usage.
+func (c *usageClient) GetUsageSummary(ctx context.Context, spaceGUID string) (*v1alpha1.SpaceUsage, error) {
+	var summary struct {
+		UsageSummary struct {
+			MemoryInMB       *int `json:"memory_in_mb"`
+			Routes           *int `json:"routes"`
+			StartedInstances *int `json:"started_instances"`
+		} `json:"usage_summary"`
+	}
+	if err := do(ctx, c.cf, http.MethodGet, "/v3/spaces/"+url.PathEscape(spaceGUID)+"/usage_summary", nil, &summary); err != nil {
+		return nil, err
+	}
+	u := summary.UsageSummary
+	if u.MemoryInMB == nil && u.Routes == nil && u.StartedInstances == nil {
+		return nil, nil
+	}
+	return &v1alpha1.SpaceUsage{MemoryInMB: u.MemoryInMB, Routes: u.Routes, StartedInstances: u.StartedInstances}, nil
+}
@@ -2,9 +2,13 @@ package spacequota
 
 import (
 	"context"
+	"errors"
+	"strings"
 
 	"github.com/cloudfoundry/go-cfclient/v3/client"
 	"github.com/cloudfoundry/go-cfclient/v3/resource"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
 )
 
 type SpaceQuotaClient interface {
@@ -19,3 +23,25 @@ type SpaceQuotaClient interface {
 func NewClient(cf *client.Client) SpaceQuotaClient {
 	return cf.SpaceQuotas
 }
+
+// RemoveSpace removes the space quota from a space. Removing it from a space
+// that was deleted out-of-band, or that no longer has the quota applied,
+// succeeds so that a missing space does not block the reconciliation.
+func RemoveSpace(ctx context.Context, c SpaceQuotaClient, guid, spaceGUID string) error {
+	err := c.Remove(ctx, guid, spaceGUID)
+	if clients.IsNotFound(err) || isNotApplied(err) {
+		return nil
+	}
+	return err
+}
+
+// notAppliedDetail is the detail of the error the CF API returns when a space
+// quota is removed from a space it is not applied to.
+const notAppliedDetail = "Ensure the space quota is applied to this space"
+
+// isNotApplied returns true if Cloud Foundry rejects a removal because the
+// space quota is not applied to the space.
+func isNotApplied(err error) bool {
+	var cfErr resource.CloudFoundryError
+	return resource.IsUnprocessableEntityError(err) && errors.As(err, &cfErr) && strings.Contains(cfErr.Detail, notAppliedDetail)
+}
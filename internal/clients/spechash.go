@@ -0,0 +1,51 @@
+package clients
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+)
+
+// SpecHash returns a stable hash of the parameters of a managed resource, i.e.
+// its spec.forProvider. The JSON of the parameters is canonicalized first, so
+// that the order of the keys of maps and of embedded JSON objects, e.g. the
+// parameters of a service instance, does not change the hash.
+func SpecHash(forProvider any) string {
+	raw, _ := json.Marshal(forProvider)
+	s := sha256.Sum256(canonicalJSON(raw))
+	return hex.EncodeToString(s[:])
+}
+
+// canonicalJSON returns the JSON with the keys of all objects sorted. The JSON
+// is returned unchanged if it cannot be decoded.
+func canonicalJSON(raw []byte) []byte {
+	var v any
+	d := json.NewDecoder(bytes.NewReader(raw))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return raw
+	}
+	canonical, err := json.Marshal(v)
+	if err != nil {
+		return raw
+	}
+	return canonical
+}
+
+// ObserveSpec records the hash of the parameters and the generation of a
+// managed resource in its observation. It returns true if the parameters
+// changed since they were last observed, i.e. a change by the user rather than
+// a drift of the external resource. The first observation is not a change.
+func ObserveSpec(o *v1alpha1.ObservedSpec, mg metav1.Object, forProvider any) bool {
+	h := SpecHash(forProvider)
+	changed := o.SpecHash != nil && *o.SpecHash != h
+	o.SpecHash = ptr.To(h)
+	o.ObservedGeneration = mg.GetGeneration()
+	return changed
+}
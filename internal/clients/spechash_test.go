@@ -0,0 +1,104 @@
+package clients
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+)
+
+func TestSpecHash(t *testing.T) {
+	params := func(raw string) v1alpha1.ServiceInstanceParameters {
+		return v1alpha1.ServiceInstanceParameters{
+			Name: ptr.To("my-instance"),
+			Managed: v1alpha1.Managed{
+				Parameters: &runtime.RawExtension{Raw: []byte(raw)},
+			},
+			Tags: []*string{ptr.To("a"), ptr.To("b")},
+		}
+	}
+	labels := func(keys ...string) v1alpha1.ResourceMetadata {
+		m := v1alpha1.ResourceMetadata{Labels: map[string]*string{}}
+		for _, k := range keys {
+			m.Labels[k] = ptr.To(k)
+		}
+		return m
+	}
+
+	cases := map[string]struct {
+		a, b any
+		want bool
+	}{
+		"IdenticalSpecs": {
+			a:    params(`{"plan":"small","size":1}`),
+			b:    params(`{"plan":"small","size":1}`),
+			want: true,
+		},
+		"ReorderedParameters": {
+			a:    params(`{"plan":"small","nested":{"x":1,"y":2},"size":1}`),
+			b:    params(`{"size": 1, "nested": {"y": 2, "x": 1}, "plan": "small"}`),
+			want: true,
+		},
+		"ReorderedMap": {
+			a:    labels("a", "b", "c"),
+			b:    labels("c", "b", "a"),
+			want: true,
+		},
+		"ChangedParameter": {
+			a:    params(`{"plan":"small"}`),
+			b:    params(`{"plan":"large"}`),
+			want: false,
+		},
+		"ReorderedList": {
+			// the order of a list is significant
+			a:    v1alpha1.ServiceInstanceParameters{Tags: []*string{ptr.To("a"), ptr.To("b")}},
+			b:    v1alpha1.ServiceInstanceParameters{Tags: []*string{ptr.To("b"), ptr.To("a")}},
+			want: false,
+		},
+		"LargeNumbers": {
+			// numbers are compared exactly rather than as floats
+			a:    params(`{"id":12345678901234567890}`),
+			b:    params(`{"id":12345678901234567891}`),
+			want: false,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := SpecHash(tc.a) == SpecHash(tc.b); got != tc.want {
+				t.Errorf("SpecHash(a) == SpecHash(b): want %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestObserveSpec(t *testing.T) {
+	mg := &v1alpha1.ServiceInstance{}
+	mg.SetGeneration(2)
+	o := &v1alpha1.ObservedSpec{}
+	p := v1alpha1.ServiceInstanceParameters{Name: ptr.To("my-instance")}
+
+	if ObserveSpec(o, mg, p) {
+		t.Errorf("ObserveSpec(...): the first observation is not a change")
+	}
+	want := v1alpha1.ObservedSpec{SpecHash: ptr.To(SpecHash(p)), ObservedGeneration: 2}
+	if diff := cmp.Diff(want, *o); diff != "" {
+		t.Errorf("ObserveSpec(...): -want, +got:\n%s", diff)
+	}
+
+	if ObserveSpec(o, mg, p) {
+		t.Errorf("ObserveSpec(...): an unchanged spec is not a change")
+	}
+
+	p.Name = ptr.To("renamed")
+	mg.SetGeneration(3)
+	if !ObserveSpec(o, mg, p) {
+		t.Errorf("ObserveSpec(...): a changed spec is a change")
+	}
+	want = v1alpha1.ObservedSpec{SpecHash: ptr.To(SpecHash(p)), ObservedGeneration: 3}
+	if diff := cmp.Diff(want, *o); diff != "" {
+		t.Errorf("ObserveSpec(...): -want, +got:\n%s", diff)
+	}
+}
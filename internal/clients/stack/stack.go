@@ -0,0 +1,59 @@
+package stack
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"k8s.io/utils/ptr"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
+)
+
+// Client is the interface that defines the methods that a Stack client should implement.
+type Client interface {
+	Get(ctx context.Context, guid string) (*resource.Stack, error)
+	Single(ctx context.Context, opts *client.StackListOptions) (*resource.Stack, error)
+	Create(ctx context.Context, r *resource.StackCreate) (*resource.Stack, error)
+	Delete(ctx context.Context, guid string) error
+}
+
+// NewClient creates a new client instance from a cfclient.Stack instance.
+func NewClient(cf *client.Client) Client {
+	return cf.Stacks
+}
+
+// GetByIDOrName returns a stack by GUID, or by name if the GUID is not valid.
+func GetByIDOrName(ctx context.Context, c Client, guid, name string) (*resource.Stack, error) {
+	if clients.IsValidGUID(guid) {
+		return c.Get(ctx, guid)
+	}
+
+	opts := client.NewStackListOptions()
+	opts.Names.EqualTo(name)
+	return c.Single(ctx, opts)
+}
+
+// GenerateCreate generates the StackCreate from StackParameters.
+func GenerateCreate(spec v1alpha1.StackParameters) *resource.StackCreate {
+	return &resource.StackCreate{
+		Name:        spec.Name,
+		Description: spec.Description,
+	}
+}
+
+// GenerateObservation takes a Stack resource and returns StackObservation.
+func GenerateObservation(s *resource.Stack) v1alpha1.StackObservation {
+	return v1alpha1.StackObservation{
+		ID:               ptr.To(s.GUID),
+		Name:             ptr.To(s.Name),
+		Description:      s.Description,
+		RunRootfsImage:   ptr.To(s.RunRootfsImage),
+		BuildRootfsImage: ptr.To(s.BuildRootfsImage),
+		Default:          ptr.To(s.Default),
+		CreatedAt:        ptr.To(s.CreatedAt.Format(time.RFC3339)),
+		UpdatedAt:        ptr.To(s.UpdatedAt.Format(time.RFC3339)),
+	}
+}
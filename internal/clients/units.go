@@ -0,0 +1,48 @@
+package clients
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+)
+
+// memoryUnits maps the units of a memory attribute to their size in MB, longest suffix first.
+var memoryUnits = []struct {
+	suffix string
+	mb     int
+}{
+	{"TB", 1024 * 1024}, {"GB", 1024}, {"MB", 1},
+	{"T", 1024 * 1024}, {"G", 1024}, {"M", 1},
+}
+
+// MemoryInMB converts a memory or disk attribute with a unit of measurement, e.g. 1G or 512MB, to MB.
+func MemoryInMB(memory string) (int, error) {
+	s := strings.ToUpper(strings.TrimSpace(memory))
+	for _, u := range memoryUnits {
+		if !strings.HasSuffix(s, u.suffix) {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSuffix(s, u.suffix))
+		if err != nil || n < 0 {
+			return 0, errors.Errorf("invalid memory %q", memory)
+		}
+		return n * u.mb, nil
+	}
+	return 0, errors.Errorf("invalid memory %q: missing unit of measurement", memory)
+}
+
+// MemoryLimitInMB returns the memory limit of a quota in MB. The limit in MB takes precedence
+// over the limit with a unit of measurement, which is nil if it cannot be parsed. Setting both
+// or an unparseable value is rejected by the CRD validation.
+func MemoryLimitInMB(limit *float64, withUnit *string) *float64 {
+	if limit != nil || withUnit == nil {
+		return limit
+	}
+	mb, err := MemoryInMB(*withUnit)
+	if err != nil {
+		return nil
+	}
+	return ptr.To(float64(mb))
+}
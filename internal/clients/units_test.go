@@ -0,0 +1,62 @@
+package clients
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/utils/ptr"
+)
+
+func TestMemoryInMB(t *testing.T) {
+	cases := map[string]struct {
+		memory  string
+		want    int
+		wantErr bool
+	}{
+		"M":         {memory: "256M", want: 256},
+		"MBLower":   {memory: "512mb", want: 512},
+		"G":         {memory: "1G", want: 1024},
+		"GBLower":   {memory: "2gb", want: 2048},
+		"T":         {memory: "1T", want: 1024 * 1024},
+		"NoUnit":    {memory: "1024", wantErr: true},
+		"Fraction":  {memory: "1.5G", wantErr: true},
+		"Negative":  {memory: "-1G", wantErr: true},
+		"Ambiguous": {memory: "1GM", wantErr: true},
+		"Unknown":   {memory: "1K", wantErr: true},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := MemoryInMB(tc.memory)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("MemoryInMB(%q): error = %v, wantErr %t", tc.memory, err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("MemoryInMB(%q): -want, +got:\n%s", tc.memory, diff)
+			}
+		})
+	}
+}
+
+func TestMemoryLimitInMB(t *testing.T) {
+	cases := map[string]struct {
+		limit    *float64
+		withUnit *string
+		want     *float64
+	}{
+		"Unset":       {},
+		"InMB":        {limit: ptr.To(2048.0), want: ptr.To(2048.0)},
+		"Unlimited":   {limit: ptr.To(-1.0), want: ptr.To(-1.0)},
+		"WithUnit":    {withUnit: ptr.To("10G"), want: ptr.To(10240.0)},
+		"InMBFirst":   {limit: ptr.To(512.0), withUnit: ptr.To("10G"), want: ptr.To(512.0)},
+		"Unparseable": {withUnit: ptr.To("10"), want: nil},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, MemoryLimitInMB(tc.limit, tc.withUnit)); diff != "" {
+				t.Errorf("MemoryLimitInMB(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	cfv3 "github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DefaultVersionRefreshInterval is how often the versions of the CF API and
+// UAA are recorded in the status of a ProviderConfig in use.
+const DefaultVersionRefreshInterval = 10 * time.Minute
+
+const (
+	errGetAPIRoot = "cannot get the root of the cloudfoundry API"
+	errGetUAAInfo = "cannot get the info of UAA"
+)
+
+// Versions are the versions of the CF API and UAA that a client connects to.
+type Versions struct {
+	CloudController string
+	UAA             string
+}
+
+// GetVersions returns the versions of the Cloud Controller V3 API and of the
+// UAA that the client connects to, from the root of the CF API and the info
+// endpoint of UAA.
+func GetVersions(ctx context.Context, cf *cfv3.Client) (*Versions, error) {
+	root, err := cf.Root.Get(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetAPIRoot)
+	}
+	v := &Versions{CloudController: root.Links.CloudControllerV3.Meta.Version}
+	if root.Links.Uaa.Href == "" {
+		return v, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(root.Links.Uaa.Href, "/")+"/info", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetUAAInfo)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := cf.ExecuteRequest(req)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetUAAInfo)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	var info struct {
+		App struct {
+			Version string `json:"version"`
+		} `json:"app"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, errors.Wrap(err, errGetUAAInfo)
+	}
+	v.UAA = info.App.Version
+	return v, nil
+}
+
+// versionRecorder records the versions of the CF API and UAA in the status
+// of the ProviderConfigs that clients are built for, at most once per
+// interval per ProviderConfig.
+type versionRecorder struct {
+	interval time.Duration
+	now      func() time.Time
+
+	mu         sync.Mutex
+	recordedAt map[types.NamespacedName]time.Time
+}
+
+var versions = &versionRecorder{
+	interval:   DefaultVersionRefreshInterval,
+	now:        time.Now,
+	recordedAt: map[types.NamespacedName]time.Time{},
+}
+
+// due returns true if the versions of the ProviderConfig are to be recorded
+// now.
+func (r *versionRecorder) due(pc types.NamespacedName) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	at, ok := r.recordedAt[pc]
+	return !ok || r.now().Sub(at) >= r.interval
+}
+
+// recorded marks the versions of the ProviderConfig as recorded at now.
+func (r *versionRecorder) recorded(pc types.NamespacedName, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.recordedAt[pc] = now
+}
+
+// record records the versions that the client of the managed resource
+// connects to in the status of its ProviderConfig. The versions are
+// informational, so failing to record them does not fail the client; it is
+// only logged at debug level and retried on the next connect. A managed
+// resource that targets another API than its ProviderConfig is skipped.
+func (r *versionRecorder) record(ctx context.Context, kube client.Client, mg resource.Managed, cf *cfv3.Client) {
+	if mg.GetAnnotations()[AnnotationKeyAPIEndpoint] != "" {
+		return
+	}
+	log := LoggerFrom(ctx)
+	pc, err := getProviderConfig(ctx, kube, mg)
+	if err != nil {
+		log.Debug("Cannot record the versions of the cloudfoundry API", "error", err)
+		return
+	}
+	nn := types.NamespacedName{Namespace: pc.Namespace, Name: pc.Name}
+	if !r.due(nn) {
+		return
+	}
+	v, err := GetVersions(ctx, cf)
+	if err != nil {
+		log.Debug("Cannot record the versions of the cloudfoundry API", "error", err)
+		return
+	}
+	now := r.now()
+	patch := client.MergeFrom(pc.DeepCopy())
+	pc.Status.CloudControllerVersion = v.CloudController
+	pc.Status.UAAVersion = v.UAA
+	pc.Status.VersionsObservedAt = &metav1.Time{Time: now}
+	if err := kube.Status().Patch(ctx, pc, patch); err != nil {
+		log.Debug("Cannot record the versions of the cloudfoundry API", "error", err)
+		return
+	}
+	r.recorded(nn, now)
+}
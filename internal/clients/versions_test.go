@@ -0,0 +1,108 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8s "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/v1beta1"
+)
+
+// newVersionedAPI returns a CF API that serves its root document with the
+// version of the Cloud Controller, and acts as its own UAA.
+func newVersionedAPI(t *testing.T) *httptest.Server {
+	t.Helper()
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/oauth/token"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "token", "token_type": "bearer", "expires_in": 3600})
+		case r.URL.Path == "/info":
+			_ = json.NewEncoder(w).Encode(map[string]any{"app": map[string]string{"version": "77.10.0"}})
+		default:
+			_ = json.NewEncoder(w).Encode(map[string]any{"links": map[string]any{
+				"cloud_controller_v3": map[string]any{"href": srv.URL + "/v3", "meta": map[string]string{"version": "3.150.0"}},
+				"login":               map[string]string{"href": srv.URL},
+				"uaa":                 map[string]string{"href": srv.URL},
+			}})
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestClientFnBuilderRecordsVersions(t *testing.T) {
+	api := newVersionedAPI(t)
+	pc := &v1beta1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Spec: v1beta1.ProviderConfigSpec{
+			APIEndpoint: &api.URL,
+			Credentials: v1beta1.ProviderCredentials{
+				Source: xpv1.CredentialsSourceSecret,
+				CommonCredentialSelectors: xpv1.CommonCredentialSelectors{SecretRef: &xpv1.SecretKeySelector{
+					SecretReference: xpv1.SecretReference{Name: "cf", Namespace: "default"},
+					Key:             "credentials",
+				}},
+			},
+		},
+	}
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	old := versions
+	versions = &versionRecorder{
+		interval:   DefaultVersionRefreshInterval,
+		now:        func() time.Time { return now },
+		recordedAt: map[types.NamespacedName]time.Time{},
+	}
+	t.Cleanup(func() { versions = old })
+
+	var recorded []v1beta1.ProviderConfigStatus
+	patchErr := errors.New("boom")
+	kube := &test.MockClient{
+		MockGet: withProviderConfig(pc),
+		MockStatusPatch: func(_ context.Context, obj k8s.Object, _ k8s.Patch, _ ...k8s.SubResourcePatchOption) error {
+			if patchErr != nil {
+				return patchErr
+			}
+			recorded = append(recorded, obj.(*v1beta1.ProviderConfig).Status)
+			return nil
+		},
+	}
+	connect := func() {
+		t.Helper()
+		if _, err := ClientFnBuilder(context.Background(), kube)(newSpace()); err != nil {
+			t.Fatalf("ClientFnBuilder(...): %v", err)
+		}
+	}
+
+	// a connect that fails to record the versions does not count, the next
+	// connect records them, later connects within the refresh interval do not
+	connect()
+	patchErr = nil
+	connect()
+	connect()
+	first := now
+	now = now.Add(DefaultVersionRefreshInterval)
+	connect()
+
+	want := []v1beta1.ProviderConfigStatus{
+		{CloudControllerVersion: "3.150.0", UAAVersion: "77.10.0", VersionsObservedAt: &metav1.Time{Time: first}},
+		{CloudControllerVersion: "3.150.0", UAAVersion: "77.10.0", VersionsObservedAt: &metav1.Time{Time: now}},
+	}
+	if diff := cmp.Diff(want, recorded); diff != "" {
+		t.Errorf("ClientFnBuilder(...): -want recorded status, +got:\n%s", diff)
+	}
+}
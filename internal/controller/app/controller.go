@@ -3,10 +3,14 @@ package app
 import (
 	"bytes"
 	"context"
+	"fmt"
+	"time"
 
+	cfresource "github.com/cloudfoundry/go-cfclient/v3/resource"
 	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/feature"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
@@ -14,8 +18,13 @@ import (
 	"github.com/docker/cli/cli/config/configfile"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	k8s "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
 	pcv1beta1 "github.com/SAP/crossplane-provider-cloudfoundry/apis/v1beta1"
@@ -25,15 +34,29 @@ import (
 )
 
 var (
-	resourceKind       = v1alpha1.App_Kind
-	errWrongKind       = "Wrong resource kind (expected " + resourceKind + " resource)"
-	errTrackUsage      = "Cannot track usage"
-	errConnect         = "Cannot connect to Cloud Foundry"
-	errObserveResource = "Cannot observe" + resourceKind + " by ID or using forProvider spec"
-	errCreateResource  = "Cannot create " + resourceKind + " resource in Cloud Foundry"
-	errUpdateResource  = "Cannot update " + resourceKind + " in Cloud Foundry"
-	errDeleteResource  = "Cannot delete " + resourceKind + " in Cloud Foundry"
-	errSecret          = "Cannot extract credentials from secret"
+	resourceKind        = v1alpha1.App_Kind
+	errWrongKind        = "Wrong resource kind (expected " + resourceKind + " resource)"
+	errTrackUsage       = "Cannot track usage"
+	errConnect          = "Cannot connect to Cloud Foundry"
+	errObserveResource  = "Cannot observe" + resourceKind + " by ID or using forProvider spec"
+	errCreateResource   = "Cannot create " + resourceKind + " resource in Cloud Foundry"
+	errUpdateResource   = "Cannot update " + resourceKind + " in Cloud Foundry"
+	errDeleteResource   = "Cannot delete " + resourceKind + " in Cloud Foundry"
+	errRemoveDependents = "Cannot remove service bindings and routes of " + resourceKind + " in Cloud Foundry"
+	errSecret           = "Cannot extract credentials from secret"
+	errVCAPServices     = "Cannot get " + app.VCAPServicesKey + " of " + resourceKind
+	errDependencies     = "Waiting for dependencies of " + resourceKind
+	errEnvironment      = "Cannot resolve environment of " + resourceKind
+	errGetEnvironment   = "Cannot get environment of " + resourceKind
+	errSetState         = "Cannot start or stop " + resourceKind + " in Cloud Foundry"
+	errManifest         = "Cannot render manifest of " + resourceKind
+	errApplyManifest    = "Cannot apply manifest of " + resourceKind + " in Cloud Foundry"
+	errRestart          = "Cannot restart " + resourceKind + " in Cloud Foundry"
+	errUpdateCommands   = "Cannot update the process commands of " + resourceKind + " in Cloud Foundry"
+	errRestage          = "Cannot restage " + resourceKind + " in Cloud Foundry"
+	errRemoveAction     = "Cannot remove the restart or restage annotation of " + resourceKind
+	errGetDeployment    = "Cannot get the rolling deployment of " + resourceKind + " in Cloud Foundry"
+	errCancelDeployment = "Cannot cancel the rolling deployment of " + resourceKind + " in Cloud Foundry"
 )
 
 // Setup adds a controller that reconciles App resources.
@@ -41,17 +64,24 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 	name := managed.ControllerName(resourceKind)
 
 	options := []managed.ReconcilerOption{
-		managed.WithExternalConnecter(
+		managed.WithExternalConnecter(clients.WithPause(clients.WithLogger(o.Logger.WithValues("controller", name), clients.WithErrorClassification(clients.WithOperationTimeout(mgr.GetClient(),
 			&connector{kube: mgr.GetClient(),
-				usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &pcv1beta1.ProviderConfigUsage{}),
-			}),
+				reader: mgr.GetAPIReader(),
+				usage:  resource.NewProviderConfigUsageTracker(mgr.GetClient(), &pcv1beta1.ProviderConfigUsage{}),
+			}, clients.DefaultOperationTimeout))))),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithTimeout(clients.MaxOperationTimeout),
+		managed.WithPollIntervalHook(clients.PollIntervalHook(mgr.GetClient())),
 		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 		managed.WithInitializers(&spaceInitializer{
 			kube: mgr.GetClient(),
 		}),
 	}
 
+	if o.Features.Enabled(feature.EnableBetaManagementPolicies) {
+		options = append(options, managed.WithManagementPolicies())
+	}
+
 	r := managed.NewReconciler(mgr,
 		resource.ManagedKind(v1alpha1.App_GroupVersionKind),
 		options...)
@@ -60,13 +90,15 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&v1alpha1.App{}).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(appsForConfigMap(mgr.GetClient()))).
 		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
 }
 
 // A connector supplies a function for the Reconciler to create a client to the external CloudFoundry resources.
 type connector struct {
-	kube  k8s.Client
-	usage *resource.ProviderConfigUsageTracker
+	kube   k8s.Client
+	reader k8s.Reader
+	usage  *resource.ProviderConfigUsageTracker
 }
 
 // Connect typically produces an ExternalClient by:
@@ -91,6 +123,7 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	return &external{
 		client: app.NewAppClient(cf),
 		kube:   c.kube,
+		reader: c.reader,
 	}, nil
 }
 
@@ -98,6 +131,7 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 type external struct {
 	client *app.Client
 	kube   k8s.Client
+	reader k8s.Reader
 }
 
 // Observe managed resource
@@ -110,8 +144,8 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	guid := meta.GetExternalName(cr)
 	res, err := c.client.GetByIDOrSpec(ctx, guid, cr.Spec.ForProvider)
 	if err != nil {
-		if clients.ErrorIsNotFound(err) {
-			return managed.ExternalObservation{ResourceExists: false}, nil
+		if clients.IsNotFound(err) {
+			return c.observeMissing(ctx, cr), nil
 		}
 
 		return managed.ExternalObservation{}, errors.Wrap(err, errObserveResource)
@@ -125,35 +159,103 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		lateInitialized = true
 	}
 
-	// Update the status of the resource
+	// Update the status of the resource, keeping the credentials the app was pushed with and its deployment
+	dockerCredentials, deployment := cr.Status.AtProvider.DockerCredentials, cr.Status.AtProvider.Deployment
+	managedMetadata := clients.ObserveManagedMetadata(cr.Status.AtProvider.ManagedMetadata, cr.Spec.ForProvider.Labels, cr.Spec.ForProvider.Annotations, res.Metadata)
 	cr.Status.AtProvider = app.GenerateObservation(res)
+	cr.Status.AtProvider.DockerCredentials = dockerCredentials
+	cr.Status.AtProvider.Deployment = deployment
+	cr.Status.AtProvider.ManagedMetadata = managedMetadata
 	appManifest, err := c.client.GenerateManifest(ctx, res.GUID)
 	if err == nil {
 		cr.Status.AtProvider.AppManifest = appManifest
 	}
 
-	// Set condition according to app State
+	// Set condition according to app State, an app that is stopped as desired is available
+	desired := cr.Spec.ForProvider.DesiredState
 	switch cr.Status.AtProvider.State {
-	case "STARTED":
+	case app.StateStarted:
 		cr.SetConditions(xpv1.Available())
-	case "STOPPED":
-		cr.SetConditions(xpv1.Unavailable())
+	case app.StateStopped:
+		if desired == app.StateStopped {
+			cr.SetConditions(xpv1.Available())
+		} else {
+			cr.SetConditions(xpv1.Unavailable())
+		}
 	default:
 		cr.SetConditions(xpv1.Unavailable())
 	}
+	if desired != "" {
+		cr.SetConditions(app.DesiredState(desired, cr.Status.AtProvider.State))
+	}
+	deploying, err := c.observeDeployment(ctx, cr)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
 
 	isUpToDate, err := app.IsUpToDate(cr.Spec.ForProvider, cr.Status.AtProvider)
 	if err != nil {
 		return managed.ExternalObservation{}, err
 	}
 
+	credentialsUpToDate, err := c.dockerCredentialsUpToDate(ctx, cr)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	isUpToDate = isUpToDate && credentialsUpToDate
+
+	// the environment is only observed if it is managed
+	if isUpToDate && app.ManagesEnvironment(cr.Spec.ForProvider) {
+		env, err := app.Environment(ctx, c.kube, cr.GetNamespace(), cr.Spec.ForProvider)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errEnvironment)
+		}
+		observed, err := c.client.GetEnvironmentVariables(ctx, res.GUID)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errGetEnvironment)
+		}
+		isUpToDate = app.IsEnvironmentUpToDate(env, observed)
+	}
+
+	// a requested restart or restage is performed by an update
+	if app.RequestedAction(cr.GetAnnotations()) != "" {
+		isUpToDate = false
+	}
+	// the app is not updated while a rolling deployment replaces its instances
+	if deploying {
+		isUpToDate = true
+	}
+
+	var details managed.ConnectionDetails
+	if cr.Spec.ForProvider.PublishVCAPServices {
+		vcap, err := c.client.GetVCAPServices(ctx, res.GUID)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errVCAPServices)
+		}
+		details = managed.ConnectionDetails{app.VCAPServicesKey: vcap}
+	}
+
 	return managed.ExternalObservation{
 		ResourceExists:          true,
 		ResourceUpToDate:        isUpToDate,
 		ResourceLateInitialized: lateInitialized,
+		ConnectionDetails:       details,
 	}, nil
 }
 
+// observeMissing reports a missing App as existing while its dependencies are not ready,
+// so that the reconciler waits for them instead of creating the App.
+func (c *external) observeMissing(ctx context.Context, cr *v1alpha1.App) managed.ExternalObservation {
+	if meta.WasDeleted(cr) {
+		return managed.ExternalObservation{ResourceExists: false}
+	}
+	if err := clients.CheckDependencies(ctx, c.reader, cr, cr.Spec.ForProvider.DependsOn); err != nil {
+		cr.SetConditions(clients.WaitingForDependencies(errors.Wrap(err, errDependencies).Error()))
+		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}
+	}
+	return managed.ExternalObservation{ResourceExists: false}
+}
+
 // Create managed resource
 func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
 	cr, ok := mg.(*v1alpha1.App)
@@ -166,13 +268,33 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalCreation{}, errors.Wrap(err, errSecret)
 	}
 
+	env, err := app.Environment(ctx, c.kube, cr.GetNamespace(), cr.Spec.ForProvider)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errEnvironment)
+	}
+
 	cr.SetConditions(xpv1.Creating())
 
-	application, err := c.client.CreateAndPush(ctx, cr.Spec.ForProvider, dockerCredentials)
+	application, err := c.client.CreateAndPush(ctx, cr.Spec.ForProvider, dockerCredentials, env)
 	if err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, errCreateResource)
 	}
 	meta.SetExternalName(cr, application.GUID)
+	clients.LoggerFrom(ctx).Debug("Pushed app", "guid", application.GUID)
+	if err := recordManifest(cr, dockerCredentials, env); err != nil {
+		return managed.ExternalCreation{}, err
+	}
+	cr.Status.AtProvider.DockerCredentials = app.HashDockerCredentials(dockerCredentials)
+
+	// a pushed app is freshly staged and started, there is nothing to restart or restage
+	meta.RemoveAnnotations(cr, app.RestartAnnotation, app.RestageAnnotation)
+
+	// a pushed app is started, stop it right away if it should be stopped
+	if cr.Spec.ForProvider.DesiredState == app.StateStopped && application.State != app.StateStopped {
+		if _, err := c.client.SetState(ctx, application.GUID, app.StateStopped); err != nil {
+			return managed.ExternalCreation{}, errors.Wrap(err, errSetState)
+		}
+	}
 
 	return managed.ExternalCreation{}, nil
 }
@@ -194,25 +316,204 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateResource+": Failed to detect changes")
 	}
 
-	if changes.HasField("docker_image") {
-		dockerCredentials, err := getDockerCredential(ctx, c.kube, cr.Spec.ForProvider)
-		if err != nil {
-			return managed.ExternalUpdate{}, errors.Wrap(err, errSecret)
-		}
-		_, err = c.client.UpdateAndPush(ctx, guid, cr.Spec.ForProvider, dockerCredentials)
+	env, err := app.Environment(ctx, c.kube, cr.GetNamespace(), cr.Spec.ForProvider)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errEnvironment)
+	}
+
+	dockerCredentials, err := getDockerCredential(ctx, c.kube, cr.Spec.ForProvider)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errSecret)
+	}
+
+	// restage the app to pull the image with rotated docker credentials
+	credentials := app.HashDockerCredentials(dockerCredentials)
+	restart := false
+	if changes.HasField("docker_image") || !bytes.Equal(credentials, cr.Status.AtProvider.DockerCredentials) {
+		_, deployment, err := c.client.UpdateAndPush(ctx, guid, cr.Spec.ForProvider, cr.Status.AtProvider, dockerCredentials, env)
 		if err != nil {
 			return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateResource)
 		}
-	} else if changes.HasChanges() {
-		_, err := c.client.Update(ctx, guid, cr.Spec.ForProvider)
+		if err := recordManifest(cr, dockerCredentials, env); err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+		cr.Status.AtProvider.DockerCredentials = credentials
+		if deployment != nil {
+			clients.LoggerFrom(ctx).Debug("Started rolling deployment", "deployment", deployment.GUID)
+			cr.Status.AtProvider.Deployment = app.GenerateDeploymentObservation(deployment)
+			cr.SetConditions(deploymentInProgress(deployment))
+		}
+	} else {
+		if changes.HasField("name") || changes.HasField("metadata") {
+			_, err := c.client.Update(ctx, guid, cr.Spec.ForProvider, cr.Status.AtProvider)
+			if err != nil {
+				return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateResource)
+			}
+		}
+		// pushing applies the manifest, otherwise apply it for the changes it reconciles
+		if changes.HasManifestChanges() {
+			if err := c.applyManifest(ctx, cr, dockerCredentials, env); err != nil {
+				return managed.ExternalUpdate{}, err
+			}
+		}
+		// a changed command needs no new droplet, restarting the processes applies it
+		if changes.HasField("command") {
+			if err := c.client.UpdateCommands(ctx, guid, cr.Spec.ForProvider, cr.Status.AtProvider); err != nil {
+				return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateCommands)
+			}
+			restart = true
+		}
+	}
+
+	state := cr.Status.AtProvider.State
+	if changes.HasField("state") {
+		if _, err := c.client.SetState(ctx, guid, cr.Spec.ForProvider.DesiredState); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errSetState)
+		}
+		state = cr.Spec.ForProvider.DesiredState
+	}
+
+	if env != nil {
+		observed, err := c.client.GetEnvironmentVariables(ctx, guid)
 		if err != nil {
-			return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateResource)
+			return managed.ExternalUpdate{}, errors.Wrap(err, errGetEnvironment)
 		}
+		if !app.IsEnvironmentUpToDate(env, observed) {
+			if err := c.client.UpdateEnvironment(ctx, guid, env, observed); err != nil {
+				return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateResource)
+			}
+		}
+	}
+
+	// an app that is started or stopped now, or restarted as requested, needs no extra restart
+	if restart && state == app.StateStarted && !changes.HasField("state") && app.RequestedAction(cr.GetAnnotations()) == "" {
+		clients.LoggerFrom(ctx).Debug("Restarting app to apply changed process commands")
+		if err := c.client.Restart(ctx, guid); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errRestart)
+		}
+	}
+
+	// the requested action comes last, so that a restage picks up the updated environment
+	if err := c.performRequestedAction(ctx, cr, guid, state == app.StateStarted); err != nil {
+		return managed.ExternalUpdate{}, err
 	}
 
 	return managed.ExternalUpdate{}, nil
 }
 
+// performRequestedAction restarts or restages the app as requested by its
+// annotations, and removes the annotations once the action is performed.
+func (c *external) performRequestedAction(ctx context.Context, cr *v1alpha1.App, guid string, started bool) error {
+	switch app.RequestedAction(cr.GetAnnotations()) {
+	case app.RestageAnnotation:
+		if err := c.client.Restage(ctx, guid, started); err != nil {
+			return errors.Wrap(err, errRestage)
+		}
+	case app.RestartAnnotation:
+		// a stopped app has no instances to restart
+		if started {
+			if err := c.client.Restart(ctx, guid); err != nil {
+				return errors.Wrap(err, errRestart)
+			}
+		}
+	default:
+		return nil
+	}
+
+	// Patch only the annotations and keep the status set by this update, as the
+	// patched object returned by the API server carries the stored status.
+	patch := k8s.MergeFrom(cr.DeepCopy())
+	status := cr.Status.DeepCopy()
+	meta.RemoveAnnotations(cr, app.RestartAnnotation, app.RestageAnnotation)
+	err := c.kube.Patch(ctx, cr, patch)
+	cr.Status = *status
+	return errors.Wrap(err, errRemoveAction)
+}
+
+// applyManifest renders the manifest of the app and applies it to the space of the app.
+func (c *external) applyManifest(ctx context.Context, cr *v1alpha1.App, dockerCredentials *app.DockerCredentials, env map[string]string) error {
+	manifest, err := app.RenderManifest(cr.Spec.ForProvider, dockerCredentials, env)
+	if err != nil {
+		return errors.Wrap(err, errManifest)
+	}
+	if err := c.client.Apply(ctx, ptr.Deref(cr.Spec.ForProvider.Space, ""), manifest); err != nil {
+		return errors.Wrap(err, errApplyManifest)
+	}
+	cr.Status.AtProvider.AppManifest = manifest
+	return nil
+}
+
+// observeDeployment observes the rolling deployment the provider last created for the app, if any, and reports
+// whether it is in progress. The app is unavailable until the deployment finished. A deployment that does not
+// finish in time is canceled, which rolls the app back to its previous droplet.
+func (c *external) observeDeployment(ctx context.Context, cr *v1alpha1.App) (bool, error) {
+	if cr.Status.AtProvider.Deployment == nil {
+		return false, nil
+	}
+	deployment, err := c.client.GetDeployment(ctx, cr.Status.AtProvider.Deployment.GUID)
+	if clients.IsNotFound(err) {
+		cr.Status.AtProvider.Deployment = nil
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrap(err, errGetDeployment)
+	}
+	cr.Status.AtProvider.Deployment = app.GenerateDeploymentObservation(deployment)
+
+	switch {
+	case deployment.Status.Value == app.DeploymentActive:
+		// A paused app is only observed, its deployment is canceled once it is resumed
+		if deployment.Status.Reason == app.DeploymentDeploying && time.Since(deployment.CreatedAt) > app.DeploymentTimeout && !clients.IsReadOnly(ctx) {
+			if err := c.client.CancelDeployment(ctx, deployment.GUID); err != nil {
+				return true, errors.Wrap(err, errCancelDeployment)
+			}
+			cr.SetConditions(xpv1.Unavailable().WithMessage(fmt.Sprintf("rolling deployment %s did not finish within %s and is canceled", deployment.GUID, app.DeploymentTimeout)))
+			return true, nil
+		}
+		cr.SetConditions(deploymentInProgress(deployment))
+		return true, nil
+	case deployment.Status.Reason == app.DeploymentCanceled:
+		// the canceled deployment is reported once, the drift of the rolled back app drives a retry
+		cr.SetConditions(xpv1.Unavailable().WithMessage(fmt.Sprintf("rolling deployment %s was canceled", deployment.GUID)))
+		cr.Status.AtProvider.Deployment = nil
+		return false, nil
+	default:
+		// the deployment finished, or was superseded by another one
+		cr.Status.AtProvider.Deployment = nil
+		return false, nil
+	}
+}
+
+// deploymentInProgress returns the condition of an app whose instances are replaced by a rolling deployment.
+func deploymentInProgress(deployment *cfresource.Deployment) xpv1.Condition {
+	return xpv1.Unavailable().WithMessage(fmt.Sprintf("rolling deployment %s is in progress", deployment.GUID))
+}
+
+// dockerCredentialsUpToDate compares the resolved docker credentials of the app with the credentials
+// it was last pushed with. The credentials of an app that was not pushed by the provider are recorded.
+func (c *external) dockerCredentialsUpToDate(ctx context.Context, cr *v1alpha1.App) (bool, error) {
+	dockerCredentials, err := getDockerCredential(ctx, c.kube, cr.Spec.ForProvider)
+	if err != nil {
+		return false, errors.Wrap(err, errSecret)
+	}
+	credentials := app.HashDockerCredentials(dockerCredentials)
+	if cr.Status.AtProvider.DockerCredentials == nil {
+		cr.Status.AtProvider.DockerCredentials = credentials
+		return true, nil
+	}
+	return bytes.Equal(credentials, cr.Status.AtProvider.DockerCredentials), nil
+}
+
+// recordManifest stores the manifest of the app that was applied by a push.
+func recordManifest(cr *v1alpha1.App, dockerCredentials *app.DockerCredentials, env map[string]string) error {
+	manifest, err := app.RenderManifest(cr.Spec.ForProvider, dockerCredentials, env)
+	if err != nil {
+		return errors.Wrap(err, errManifest)
+	}
+	cr.Status.AtProvider.AppManifest = manifest
+	return nil
+}
+
 // Delete managed resource
 func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
 	cr, ok := mg.(*v1alpha1.App)
@@ -226,8 +527,13 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.Ext
 	}
 
 	cr.SetConditions(xpv1.Deleting())
-	err := c.client.Delete(ctx, guid)
-	if err != nil {
+	clients.LoggerFrom(ctx).Debug("Deleting app and its service bindings and route mappings")
+	// Remove the service bindings and route mappings first, so that none of
+	// them is left dangling. The app is only deleted once they are gone.
+	if err := c.client.RemoveDependents(ctx, guid); err != nil {
+		return managed.ExternalDelete{}, errors.Wrap(err, errRemoveDependents)
+	}
+	if err := c.client.Delete(ctx, guid); clients.IgnoreNotFoundErr(err) != nil {
 		return managed.ExternalDelete{}, errors.Wrap(err, errDeleteResource)
 	}
 
@@ -271,6 +577,26 @@ func getDockerCredential(ctx context.Context, kube k8s.Client, forProvider v1alp
 	return s, nil
 }
 
+// appsForConfigMap returns a function that maps a ConfigMap to the Apps in its
+// namespace that source environment variables from it, so that a change of
+// the ConfigMap is reconciled without waiting for the next poll.
+func appsForConfigMap(kube k8s.Reader) handler.MapFunc {
+	return func(ctx context.Context, o k8s.Object) []reconcile.Request {
+		apps := &v1alpha1.AppList{}
+		if err := kube.List(ctx, apps, k8s.InNamespace(o.GetNamespace())); err != nil {
+			return nil
+		}
+
+		var requests []reconcile.Request
+		for _, a := range apps.Items {
+			if app.UsesConfigMap(a.Spec.ForProvider, o.GetName()) {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: a.GetNamespace(), Name: a.GetName()}})
+			}
+		}
+		return requests
+	}
+}
+
 type initializer struct {
 	kube k8s.Client
 }
@@ -279,14 +605,9 @@ type spaceInitializer initializer
 
 // / Initialize implements the Initializer interface
 func (c *spaceInitializer) Initialize(ctx context.Context, mg resource.Managed) error {
-	cr, ok := mg.(*v1alpha1.App)
-	if !ok {
+	if _, ok := mg.(*v1alpha1.App); !ok {
 		return errors.New(errWrongKind)
 	}
 
-	if cr.Spec.ForProvider.SpaceRef != nil || cr.Spec.ForProvider.SpaceSelector != nil {
-		return cr.ResolveReferences(ctx, c.kube)
-	}
-
-	return space.ResolveByName(ctx, clients.ClientFnBuilder(ctx, c.kube), mg)
+	return space.ResolveSpaceReference(ctx, c.kube, mg)
 }
@@ -2,20 +2,32 @@ package app
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
+	cfresource "github.com/cloudfoundry/go-cfclient/v3/resource"
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	k8s "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/app"
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/fake"
 )
@@ -25,6 +37,8 @@ var (
 	name      = "my-app"
 	spaceGUID = "a46808d1-d09a-4eef-add1-30872dec82f7"
 	guid      = "2d8b0d04-d537-4e4e-8c6f-f09ca0e7f56f"
+
+	deploymentGUID = "7f5dbb3c-4c87-4b58-9e3a-8c8d0c4a9f1e"
 )
 
 type modifier func(*v1alpha1.App)
@@ -35,6 +49,12 @@ func withExternalName(name string) modifier {
 	}
 }
 
+func withAnnotation(key string) modifier {
+	return func(r *v1alpha1.App) {
+		r.ObjectMeta.Annotations[key] = "now"
+	}
+}
+
 func withSpace(space string) modifier {
 	return func(r *v1alpha1.App) {
 		r.Spec.ForProvider.Space = &space
@@ -55,6 +75,79 @@ func withStatus(guid, state string) modifier {
 	}
 }
 
+func withAppManifest(m string) modifier {
+	return func(r *v1alpha1.App) {
+		r.Status.AtProvider.AppManifest = m
+	}
+}
+
+func withInstances(n uint) modifier {
+	return func(r *v1alpha1.App) {
+		r.Spec.ForProvider.Instances = &n
+	}
+}
+
+func withDesiredState(state string) modifier {
+	return func(r *v1alpha1.App) {
+		r.Spec.ForProvider.DesiredState = state
+	}
+}
+
+func withObservedName(n string) modifier {
+	return func(r *v1alpha1.App) {
+		r.Status.AtProvider.Name = n
+	}
+}
+
+func withPublishVCAPServices() modifier {
+	return func(r *v1alpha1.App) {
+		r.Spec.ForProvider.PublishVCAPServices = true
+	}
+}
+
+func withDependsOn(kind, name string) modifier {
+	return func(r *v1alpha1.App) {
+		r.Spec.ForProvider.DependsOn = append(r.Spec.ForProvider.DependsOn, v1alpha1.Dependency{Kind: kind, Name: name})
+	}
+}
+
+func withEnvironmentFromConfigMap(name string) modifier {
+	return func(r *v1alpha1.App) {
+		r.Spec.ForProvider.EnvironmentFrom = append(r.Spec.ForProvider.EnvironmentFrom, v1alpha1.EnvironmentSource{ConfigMapRef: &v1alpha1.LocalObjectReference{Name: name}})
+	}
+}
+
+// withConfigMap returns a kube client that knows a ConfigMap with the given data.
+func withConfigMap(data map[string]string) *test.MockClient {
+	return &test.MockClient{
+		MockGet: func(_ context.Context, _ k8s.ObjectKey, obj k8s.Object) error {
+			obj.(*corev1.ConfigMap).Data = data
+			return nil
+		},
+	}
+}
+
+// withDockerSecret returns a kube client that knows a docker config secret with the given password.
+func withDockerSecret(password string) *test.MockClient {
+	return &test.MockClient{
+		MockGet: func(_ context.Context, _ k8s.ObjectKey, obj k8s.Object) error {
+			config := fmt.Sprintf(`{"auths":{"registry.example.com":{"username":"robot","password":%q}}}`, password)
+			obj.(*corev1.Secret).Data = map[string][]byte{".dockerconfigjson": []byte(config)}
+			return nil
+		},
+		MockUpdate: test.NewMockUpdateFn(nil),
+	}
+}
+
+func withDockerCredentials(password string) modifier {
+	return func(r *v1alpha1.App) {
+		r.Spec.ForProvider.Docker.Credentials = &xpv1.SecretReference{Name: "registry", Namespace: "default"}
+		if password != "" {
+			r.Status.AtProvider.DockerCredentials = app.HashDockerCredentials(&app.DockerCredentials{Username: "robot", Password: password})
+		}
+	}
+}
+
 func withImage(image string) modifier {
 	return func(r *v1alpha1.App) {
 		r.Spec.ForProvider.Docker = &v1alpha1.DockerConfiguration{Image: image}
@@ -83,6 +176,11 @@ func newApp(typ string, m ...modifier) *v1alpha1.App {
 	return r
 }
 
+// dockerManifest renders the manifest of a docker app with the given number of instances
+func dockerManifest(instances int) string {
+	return fmt.Sprintf("applications:\n- name: %s\n  docker:\n    image: docker-image\n  health-check-type: port\n  health-check-http-endpoint: /\n  instances: %d\n  memory: 256M\n", name, instances)
+}
+
 func newMockPush() *fake.MockPush {
 	m := &fake.MockPush{}
 	m.On("GenerateManifest", guid).Return("applicationmanifest", nil)
@@ -100,9 +198,10 @@ func TestObserve(t *testing.T) {
 	}
 
 	type want struct {
-		mg  resource.Managed
-		obs managed.ExternalObservation
-		err error
+		mg         resource.Managed
+		obs        managed.ExternalObservation
+		err        error
+		conditions []xpv1.Condition
 	}
 
 	cases := map[string]struct {
@@ -238,6 +337,165 @@ func TestObserve(t *testing.T) {
 				return m
 			},
 		},
+		"RestartRequested": {
+			args: args{
+				mg: newApp("docker", withExternalName(guid), withSpace(spaceGUID), withAnnotation(app.RestartAnnotation)),
+			},
+			want: want{
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+			service: func() *fake.MockApp {
+				m := &fake.MockApp{}
+				m.On("Get", guid).Return(
+					&fake.NewApp("docker").SetName(name).SetGUID(guid).App,
+					nil,
+				)
+				return m
+			},
+		},
+		"EnvironmentDrift": {
+			args: args{
+				mg: newApp("docker", withExternalName(guid), withSpace(spaceGUID), withEnvironmentFromConfigMap("app-config")),
+			},
+			want: want{
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				err: nil,
+			},
+			service: func() *fake.MockApp {
+				m := &fake.MockApp{}
+				m.On("Get", guid).Return(
+					&fake.NewApp("docker").SetName(name).SetGUID(guid).App,
+					nil,
+				)
+				m.On("GetEnvironment", guid).Return(
+					&cfresource.AppEnvironment{EnvVars: map[string]string{"LOG_LEVEL": "info"}},
+					nil,
+				)
+				return m
+			},
+			kube: withConfigMap(map[string]string{"LOG_LEVEL": "debug"}),
+		},
+		"EnvironmentUpToDate": {
+			args: args{
+				mg: newApp("docker", withExternalName(guid), withSpace(spaceGUID), withEnvironmentFromConfigMap("app-config")),
+			},
+			want: want{
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				err: nil,
+			},
+			service: func() *fake.MockApp {
+				m := &fake.MockApp{}
+				m.On("Get", guid).Return(
+					&fake.NewApp("docker").SetName(name).SetGUID(guid).App,
+					nil,
+				)
+				m.On("GetEnvironment", guid).Return(
+					&cfresource.AppEnvironment{EnvVars: map[string]string{"LOG_LEVEL": "debug"}},
+					nil,
+				)
+				return m
+			},
+			kube: withConfigMap(map[string]string{"LOG_LEVEL": "debug"}),
+		},
+		"StoppedAsDesired": {
+			args: args{
+				mg: newApp("docker", withExternalName(guid), withSpace(spaceGUID), withDesiredState(app.StateStopped)),
+			},
+			want: want{
+				obs:        managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				conditions: []xpv1.Condition{xpv1.Available(), app.DesiredState(app.StateStopped, app.StateStopped)},
+			},
+			service: func() *fake.MockApp {
+				m := &fake.MockApp{}
+				m.On("Get", guid).Return(
+					&fake.NewApp("docker").SetName(name).SetGUID(guid).SetState(app.StateStopped).App,
+					nil,
+				)
+				return m
+			},
+		},
+		"StartedButShouldStop": {
+			args: args{
+				mg: newApp("docker", withExternalName(guid), withSpace(spaceGUID), withDesiredState(app.StateStopped)),
+			},
+			want: want{
+				obs:        managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				conditions: []xpv1.Condition{xpv1.Available(), app.DesiredState(app.StateStopped, app.StateStarted)},
+			},
+			service: func() *fake.MockApp {
+				m := &fake.MockApp{}
+				m.On("Get", guid).Return(
+					&fake.NewApp("docker").SetName(name).SetGUID(guid).SetState(app.StateStarted).App,
+					nil,
+				)
+				return m
+			},
+		},
+		"StoppedButShouldStart": {
+			args: args{
+				mg: newApp("docker", withExternalName(guid), withSpace(spaceGUID), withDesiredState(app.StateStarted)),
+			},
+			want: want{
+				obs:        managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				conditions: []xpv1.Condition{xpv1.Unavailable(), app.DesiredState(app.StateStarted, app.StateStopped)},
+			},
+			service: func() *fake.MockApp {
+				m := &fake.MockApp{}
+				m.On("Get", guid).Return(
+					&fake.NewApp("docker").SetName(name).SetGUID(guid).SetState(app.StateStopped).App,
+					nil,
+				)
+				return m
+			},
+		},
+		"PublishVCAPServices": {
+			args: args{
+				mg: newApp("docker", withExternalName(guid), withSpace(spaceGUID), withPublishVCAPServices()),
+			},
+			want: want{
+				obs: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: managed.ConnectionDetails{app.VCAPServicesKey: []byte(`{"xsuaa":[{"credentials":{"clientid":"id"},"label":"xsuaa","name":"uaa"}]}`)},
+				},
+				err: nil,
+			},
+			service: func() *fake.MockApp {
+				m := &fake.MockApp{}
+				m.On("Get", guid).Return(
+					&fake.NewApp("docker").SetName(name).SetGUID(guid).App,
+					nil,
+				)
+				m.On("GetEnvironment", guid).Return(
+					&cfresource.AppEnvironment{SystemEnvVars: map[string]json.RawMessage{
+						app.VCAPServicesKey: json.RawMessage(`{"xsuaa":[{"name":"uaa","label":"xsuaa","credentials":{"clientid":"id"}}]}`),
+					}},
+					nil,
+				)
+				return m
+			},
+		},
+		"PublishVCAPServicesError": {
+			args: args{
+				mg: newApp("docker", withExternalName(guid), withSpace(spaceGUID), withPublishVCAPServices()),
+			},
+			want: want{
+				obs: managed.ExternalObservation{},
+				err: errors.Wrap(errBoom, errVCAPServices),
+			},
+			service: func() *fake.MockApp {
+				m := &fake.MockApp{}
+				m.On("Get", guid).Return(
+					&fake.NewApp("docker").SetName(name).SetGUID(guid).App,
+					nil,
+				)
+				m.On("GetEnvironment", guid).Return(
+					(*cfresource.AppEnvironment)(nil),
+					errBoom,
+				)
+				return m
+			},
+		},
 	}
 
 	for n, tc := range cases {
@@ -252,6 +510,9 @@ func TestObserve(t *testing.T) {
 					PushClient: newMockPush(),
 				},
 			}
+			if tc.kube != nil {
+				c.kube = tc.kube
+			}
 
 			obs, err := c.Observe(context.Background(), tc.args.mg)
 
@@ -268,6 +529,118 @@ func TestObserve(t *testing.T) {
 			if diff := cmp.Diff(tc.want.obs, obs); diff != "" {
 				t.Errorf("Observe(...): -want, +got:\n%s", diff)
 			}
+			for _, want := range tc.want.conditions {
+				if got := tc.args.mg.GetCondition(want.Type); !got.Equal(want) {
+					t.Errorf("Observe(...): want condition %v, got %v", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestObserveDockerCredentials(t *testing.T) {
+	cases := map[string]struct {
+		mg          *v1alpha1.App
+		kube        k8s.Client
+		upToDate    bool
+		credentials []byte
+	}{
+		"Unchanged": {
+			mg:          newApp("docker", withExternalName(guid), withSpace(spaceGUID), withImage("docker-image"), withDockerCredentials("secret")),
+			kube:        withDockerSecret("secret"),
+			upToDate:    true,
+			credentials: app.HashDockerCredentials(&app.DockerCredentials{Username: "robot", Password: "secret"}),
+		},
+		"Rotated": {
+			mg:          newApp("docker", withExternalName(guid), withSpace(spaceGUID), withImage("docker-image"), withDockerCredentials("secret")),
+			kube:        withDockerSecret("rotated"),
+			upToDate:    false,
+			credentials: app.HashDockerCredentials(&app.DockerCredentials{Username: "robot", Password: "secret"}),
+		},
+		"Recorded": {
+			mg:          newApp("docker", withExternalName(guid), withSpace(spaceGUID), withImage("docker-image"), withDockerCredentials("")),
+			kube:        withDockerSecret("secret"),
+			upToDate:    true,
+			credentials: app.HashDockerCredentials(&app.DockerCredentials{Username: "robot", Password: "secret"}),
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			m := &fake.MockApp{}
+			m.On("Get", guid).Return(&fake.NewApp("docker").SetName(name).SetGUID(guid).SetState(app.StateStarted).App, nil)
+			push := &fake.MockPush{}
+			push.On("GenerateManifest", guid).Return(dockerManifest(1), nil)
+
+			c := &external{kube: tc.kube, client: &app.Client{AppClient: m, PushClient: push}}
+			obs, err := c.Observe(context.Background(), tc.mg)
+			if err != nil {
+				t.Fatalf("Observe(...): unexpected error: %v", err)
+			}
+			if obs.ResourceUpToDate != tc.upToDate {
+				t.Errorf("Observe(...): want ResourceUpToDate %t, got %t", tc.upToDate, obs.ResourceUpToDate)
+			}
+			if diff := cmp.Diff(tc.credentials, tc.mg.Status.AtProvider.DockerCredentials); diff != "" {
+				t.Errorf("Observe(...): -want credentials, +got credentials:\n%s", diff)
+			}
+		})
+	}
+}
+
+func withDeletionTimestamp() modifier {
+	return func(r *v1alpha1.App) {
+		ts := metav1.Now()
+		r.ObjectMeta.DeletionTimestamp = &ts
+	}
+}
+
+func TestObserveDependencies(t *testing.T) {
+	notReady := func(_ context.Context, _ k8s.ObjectKey, obj k8s.Object) error {
+		// the service instance exists but is not ready yet
+		obj.(*unstructured.Unstructured).Object["status"] = map[string]any{
+			"conditions": []any{map[string]any{"type": "Ready", "status": "False"}},
+		}
+		return nil
+	}
+
+	cases := map[string]struct {
+		mg       *v1alpha1.App
+		want     managed.ExternalObservation
+		wantCond *xpv1.Condition
+	}{
+		"DependencyNotReady": {
+			mg:       newApp("docker", withImage("docker-image"), withSpace(spaceGUID), withDependsOn("ServiceInstance", "my-si")),
+			want:     managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			wantCond: ptr.To(clients.WaitingForDependencies(errDependencies + `: dependency ServiceInstance "my-si" is not ready`)),
+		},
+		"Deleted": {
+			mg:   newApp("docker", withImage("docker-image"), withSpace(spaceGUID), withDependsOn("ServiceInstance", "my-si"), withDeletionTimestamp()),
+			want: managed.ExternalObservation{ResourceExists: false},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			m := &fake.MockApp{}
+			m.On("Single").Return(fake.AppNil, fake.ErrNoResultReturned)
+			c := &external{
+				reader: &test.MockClient{MockGet: notReady},
+				client: &app.Client{AppClient: m, PushClient: newMockPush()},
+			}
+
+			obs, err := c.Observe(context.Background(), tc.mg)
+			if err != nil {
+				t.Fatalf("Observe(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, obs); diff != "" {
+				t.Errorf("Observe(...): -want, +got:\n%s", diff)
+			}
+			if tc.wantCond != nil {
+				if diff := cmp.Diff(*tc.wantCond, tc.mg.GetCondition(xpv1.TypeReady), test.EquateConditions()); diff != "" {
+					t.Errorf("Observe(...): -want condition, +got:\n%s", diff)
+				}
+			}
+			m.AssertNotCalled(t, "Create")
 		})
 	}
 }
@@ -300,7 +673,8 @@ func TestCreate(t *testing.T) {
 				mg: newApp("docker", withImage("docker-image"),
 					withSpace(spaceGUID),
 					withConditions(xpv1.Creating()),
-					withExternalName(guid)),
+					withExternalName(guid),
+					withAppManifest(dockerManifest(1))),
 				obs: managed.ExternalCreation{},
 				err: nil,
 			},
@@ -323,6 +697,32 @@ func TestCreate(t *testing.T) {
 			},
 		},
 
+		"RestageRequested": {
+			args: args{
+				mg: newApp("docker", withImage("docker-image"), withSpace(spaceGUID), withAnnotation(app.RestageAnnotation)),
+			},
+			want: want{
+				mg: newApp("docker", withImage("docker-image"),
+					withSpace(spaceGUID),
+					withConditions(xpv1.Creating()),
+					withExternalName(guid),
+					withAppManifest(dockerManifest(1))),
+				obs: managed.ExternalCreation{},
+			},
+			service: func() *fake.MockApp {
+				m := &fake.MockApp{}
+				m.On("Create").Return(
+					&fake.NewApp("docker").SetName(name).SetGUID(guid).App,
+					nil,
+				)
+				m.On("Single").Return(
+					&fake.NewApp("docker").SetName(name).SetGUID(guid).App,
+					nil,
+				)
+				return m
+			},
+		},
+
 		"AlreadyExist": {
 			args: args{
 				mg: newApp("docker", withSpace(spaceGUID), withImage("docker-image")),
@@ -362,6 +762,9 @@ func TestCreate(t *testing.T) {
 					PushClient: newMockPush(),
 				},
 			}
+			if tc.kube != nil {
+				c.kube = tc.kube
+			}
 
 			obs, err := c.Create(context.Background(), tc.args.mg)
 
@@ -388,6 +791,7 @@ func TestCreate(t *testing.T) {
 func TestUpdate(t *testing.T) {
 	type service func() *fake.MockApp
 	type job func() *fake.MockJob
+	type manifest func() *fake.MockManifest
 	type args struct {
 		mg resource.Managed
 	}
@@ -403,7 +807,9 @@ func TestUpdate(t *testing.T) {
 		want    want
 		service service
 		job
-		kube k8s.Client
+		manifest
+		kube  k8s.Client
+		calls []string
 	}{
 		"Successful": {
 			args: args{
@@ -434,49 +840,275 @@ func TestUpdate(t *testing.T) {
 			},
 		},
 
-		"DoesNotExist": {
+		"UpdateEnvironment": {
 			args: args{
 				mg: newApp("docker",
 					withSpace(spaceGUID),
 					withExternalName(guid),
-					withStatus(guid, "STARTED")),
+					withStatus(guid, "STARTED"),
+					withEnvironmentFromConfigMap("app-config")),
 			},
 			want: want{
 				mg: newApp("docker",
 					withSpace(spaceGUID),
 					withExternalName(guid),
-					withStatus(guid, "STARTED")),
+					withStatus(guid, "STARTED"),
+					withEnvironmentFromConfigMap("app-config")),
 				obs: managed.ExternalUpdate{},
-				err: errors.Wrap(errBoom, errUpdateResource),
+				err: nil,
 			},
 			service: func() *fake.MockApp {
 				m := &fake.MockApp{}
 				m.On("Update", guid).Return(
-					fake.AppNil,
-					errBoom,
+					&fake.NewApp("docker").SetName(name).SetGUID(guid).App,
+					nil,
 				)
-				m.On("Get", guid).Return(
-					fake.AppNil,
-					errBoom,
+				m.On("GetEnvironment", guid).Return(
+					&cfresource.AppEnvironment{EnvVars: map[string]string{"LOG_LEVEL": "info", "OBSOLETE": "true"}},
+					nil,
 				)
+				// changed variables are set, removed variables are sent as null
+				m.On("SetEnvironmentVariables", guid, map[string]*string{"LOG_LEVEL": ptr.To("debug"), "OBSOLETE": nil}).Return(nil)
 				return m
 			},
+			kube: withConfigMap(map[string]string{"LOG_LEVEL": "debug"}),
 		},
-	}
 
-	for n, tc := range cases {
-		t.Run(n, func(t *testing.T) {
-			t.Logf("Testing: %s", t.Name())
+		"ApplyManifest": {
+			args: args{
+				mg: newApp("docker",
+					withImage("docker-image"),
+					withSpace(spaceGUID),
+					withExternalName(guid),
+					withStatus(guid, app.StateStarted),
+					withObservedName(name),
+					withAppManifest(dockerManifest(1)),
+					withInstances(3)),
+			},
+			want: want{
+				mg: newApp("docker",
+					withImage("docker-image"),
+					withSpace(spaceGUID),
+					withExternalName(guid),
+					withStatus(guid, app.StateStarted),
+					withObservedName(name),
+					withAppManifest(dockerManifest(3)),
+					withInstances(3)),
+				obs: managed.ExternalUpdate{},
+			},
+			service: func() *fake.MockApp {
+				return &fake.MockApp{}
+			},
+			manifest: func() *fake.MockManifest {
+				m := &fake.MockManifest{}
+				m.On("ApplyManifest", spaceGUID, dockerManifest(3)).Return("job-guid", nil)
+				return m
+			},
+			job: func() *fake.MockJob {
+				m := &fake.MockJob{}
+				m.On("PollComplete").Return(nil)
+				return m
+			},
+		},
+		"ApplyManifestFailed": {
+			args: args{
+				mg: newApp("docker",
+					withImage("docker-image"),
+					withSpace(spaceGUID),
+					withExternalName(guid),
+					withStatus(guid, app.StateStarted),
+					withObservedName(name),
+					withAppManifest(dockerManifest(1)),
+					withInstances(3)),
+			},
+			want: want{
+				mg: newApp("docker",
+					withImage("docker-image"),
+					withSpace(spaceGUID),
+					withExternalName(guid),
+					withStatus(guid, app.StateStarted),
+					withObservedName(name),
+					withAppManifest(dockerManifest(1)),
+					withInstances(3)),
+				obs: managed.ExternalUpdate{},
+				err: errors.Wrap(errBoom, errApplyManifest),
+			},
+			service: func() *fake.MockApp {
+				return &fake.MockApp{}
+			},
+			manifest: func() *fake.MockManifest {
+				m := &fake.MockManifest{}
+				m.On("ApplyManifest", spaceGUID, dockerManifest(3)).Return("", errBoom)
+				return m
+			},
+		},
+		"RestageRotatedCredentials": {
+			args: args{
+				mg: newApp("docker",
+					withImage("docker-image"),
+					withSpace(spaceGUID),
+					withExternalName(guid),
+					withStatus(guid, app.StateStarted),
+					withObservedName(name),
+					withAppManifest(dockerManifest(1)),
+					withDockerCredentials("secret")),
+			},
+			want: want{
+				mg: newApp("docker",
+					withImage("docker-image"),
+					withSpace(spaceGUID),
+					withExternalName(guid),
+					withStatus(guid, app.StateStarted),
+					withObservedName(name),
+					withAppManifest(strings.Replace(dockerManifest(1), "image: docker-image\n", "image: docker-image\n    username: robot\n", 1)),
+					withDockerCredentials("rotated")),
+				obs: managed.ExternalUpdate{},
+			},
+			service: func() *fake.MockApp {
+				m := &fake.MockApp{}
+				m.On("Update", guid).Return(
+					&fake.NewApp("docker").SetName(name).SetGUID(guid).App,
+					nil,
+				)
+				return m
+			},
+			kube:  withDockerSecret("rotated"),
+			calls: []string{"Update"},
+		},
+		"StartToStop": {
+			args: args{
+				mg: newApp("docker",
+					withSpace(spaceGUID),
+					withExternalName(guid),
+					withStatus(guid, app.StateStarted),
+					withObservedName(name),
+					withDesiredState(app.StateStopped)),
+			},
+			want: want{
+				mg: newApp("docker",
+					withSpace(spaceGUID),
+					withExternalName(guid),
+					withStatus(guid, app.StateStarted),
+					withObservedName(name),
+					withDesiredState(app.StateStopped)),
+				obs: managed.ExternalUpdate{},
+			},
+			service: func() *fake.MockApp {
+				m := &fake.MockApp{}
+				m.On("Stop", guid).Return(
+					&fake.NewApp("docker").SetName(name).SetGUID(guid).SetState(app.StateStopped).App,
+					nil,
+				)
+				return m
+			},
+			calls: []string{"Stop"},
+		},
+		"StopToStart": {
+			args: args{
+				mg: newApp("docker",
+					withSpace(spaceGUID),
+					withExternalName(guid),
+					withStatus(guid, app.StateStopped),
+					withObservedName(name),
+					withDesiredState(app.StateStarted)),
+			},
+			want: want{
+				mg: newApp("docker",
+					withSpace(spaceGUID),
+					withExternalName(guid),
+					withStatus(guid, app.StateStopped),
+					withObservedName(name),
+					withDesiredState(app.StateStarted)),
+				obs: managed.ExternalUpdate{},
+			},
+			service: func() *fake.MockApp {
+				m := &fake.MockApp{}
+				m.On("Start", guid).Return(
+					&fake.NewApp("docker").SetName(name).SetGUID(guid).SetState(app.StateStarted).App,
+					nil,
+				)
+				return m
+			},
+			calls: []string{"Start"},
+		},
+		"StopFailed": {
+			args: args{
+				mg: newApp("docker",
+					withSpace(spaceGUID),
+					withExternalName(guid),
+					withStatus(guid, app.StateStarted),
+					withObservedName(name),
+					withDesiredState(app.StateStopped)),
+			},
+			want: want{
+				mg: newApp("docker",
+					withSpace(spaceGUID),
+					withExternalName(guid),
+					withStatus(guid, app.StateStarted),
+					withObservedName(name),
+					withDesiredState(app.StateStopped)),
+				obs: managed.ExternalUpdate{},
+				err: errors.Wrap(errBoom, errSetState),
+			},
+			service: func() *fake.MockApp {
+				m := &fake.MockApp{}
+				m.On("Stop", guid).Return(fake.AppNil, errBoom)
+				return m
+			},
+		},
+		"DoesNotExist": {
+			args: args{
+				mg: newApp("docker",
+					withSpace(spaceGUID),
+					withExternalName(guid),
+					withStatus(guid, "STARTED")),
+			},
+			want: want{
+				mg: newApp("docker",
+					withSpace(spaceGUID),
+					withExternalName(guid),
+					withStatus(guid, "STARTED")),
+				obs: managed.ExternalUpdate{},
+				err: errors.Wrap(errBoom, errUpdateResource),
+			},
+			service: func() *fake.MockApp {
+				m := &fake.MockApp{}
+				m.On("Update", guid).Return(
+					fake.AppNil,
+					errBoom,
+				)
+				m.On("Get", guid).Return(
+					fake.AppNil,
+					errBoom,
+				)
+				return m
+			},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			t.Logf("Testing: %s", t.Name())
+			svc := tc.service()
 			c := &external{
 				kube: &test.MockClient{
 					MockUpdate:       test.NewMockUpdateFn(nil),
 					MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
 				},
 				client: &app.Client{
-					AppClient:  tc.service(),
+					AppClient:  svc,
 					PushClient: newMockPush(),
 				},
 			}
+			if tc.manifest != nil {
+				c.client.ManifestClient = tc.manifest()
+			}
+			if tc.job != nil {
+				c.client.Job = tc.job()
+			}
+			if tc.kube != nil {
+				c.kube = tc.kube
+			}
 
 			obs, err := c.Update(context.Background(), tc.args.mg)
 
@@ -496,6 +1128,573 @@ func TestUpdate(t *testing.T) {
 			if diff := cmp.Diff(tc.want.mg, tc.args.mg); diff != "" {
 				t.Errorf("Observe(...): -want, +got:\n%s", diff)
 			}
+			for _, call := range tc.calls {
+				svc.AssertCalled(t, call, guid)
+			}
+		})
+	}
+}
+
+func TestRequestedAction(t *testing.T) {
+	type deploy func() *fake.MockDeploy
+
+	cases := map[string]struct {
+		mg        *v1alpha1.App
+		deploy    deploy
+		updateErr error
+		want      *v1alpha1.App
+		updated   bool
+		err       error
+	}{
+		"Restart": {
+			mg: newApp("docker", withExternalName(guid), withStatus(guid, app.StateStarted), withObservedName(name), withAnnotation(app.RestartAnnotation)),
+			deploy: func() *fake.MockDeploy {
+				m := &fake.MockDeploy{}
+				m.On("Restart", guid).Return(nil)
+				return m
+			},
+			want:    newApp("docker", withExternalName(guid), withStatus(guid, app.StateStarted), withObservedName(name)),
+			updated: true,
+		},
+		"Restage": {
+			mg: newApp("docker", withExternalName(guid), withStatus(guid, app.StateStarted), withObservedName(name), withAnnotation(app.RestageAnnotation)),
+			deploy: func() *fake.MockDeploy {
+				m := &fake.MockDeploy{}
+				m.On("Restage", guid, true).Return(nil)
+				return m
+			},
+			want:    newApp("docker", withExternalName(guid), withStatus(guid, app.StateStarted), withObservedName(name)),
+			updated: true,
+		},
+		"RestageIncludesRestart": {
+			mg: newApp("docker", withExternalName(guid), withStatus(guid, app.StateStarted), withObservedName(name), withAnnotation(app.RestartAnnotation), withAnnotation(app.RestageAnnotation)),
+			deploy: func() *fake.MockDeploy {
+				m := &fake.MockDeploy{}
+				m.On("Restage", guid, true).Return(nil)
+				return m
+			},
+			want:    newApp("docker", withExternalName(guid), withStatus(guid, app.StateStarted), withObservedName(name)),
+			updated: true,
+		},
+		"RestageStoppedApp": {
+			mg: newApp("docker", withExternalName(guid), withStatus(guid, app.StateStopped), withObservedName(name), withAnnotation(app.RestageAnnotation)),
+			deploy: func() *fake.MockDeploy {
+				m := &fake.MockDeploy{}
+				m.On("Restage", guid, false).Return(nil)
+				return m
+			},
+			want:    newApp("docker", withExternalName(guid), withStatus(guid, app.StateStopped), withObservedName(name)),
+			updated: true,
+		},
+		"RestartStoppedApp": {
+			mg:      newApp("docker", withExternalName(guid), withStatus(guid, app.StateStopped), withObservedName(name), withAnnotation(app.RestartAnnotation)),
+			deploy:  func() *fake.MockDeploy { return &fake.MockDeploy{} },
+			want:    newApp("docker", withExternalName(guid), withStatus(guid, app.StateStopped), withObservedName(name)),
+			updated: true,
+		},
+		"RestartFailed": {
+			mg: newApp("docker", withExternalName(guid), withStatus(guid, app.StateStarted), withObservedName(name), withAnnotation(app.RestartAnnotation)),
+			deploy: func() *fake.MockDeploy {
+				m := &fake.MockDeploy{}
+				m.On("Restart", guid).Return(errBoom)
+				return m
+			},
+			want: newApp("docker", withExternalName(guid), withStatus(guid, app.StateStarted), withObservedName(name), withAnnotation(app.RestartAnnotation)),
+			err:  errors.Wrap(errBoom, errRestart),
+		},
+		"RemoveAnnotationFailed": {
+			mg: newApp("docker", withExternalName(guid), withStatus(guid, app.StateStarted), withObservedName(name), withAnnotation(app.RestartAnnotation)),
+			deploy: func() *fake.MockDeploy {
+				m := &fake.MockDeploy{}
+				m.On("Restart", guid).Return(nil)
+				return m
+			},
+			updateErr: errBoom,
+			want:      newApp("docker", withExternalName(guid), withStatus(guid, app.StateStarted), withObservedName(name)),
+			updated:   true,
+			err:       errors.Wrap(errBoom, errRemoveAction),
+		},
+		"KeepsStatusOfUpdate": {
+			mg: newApp("docker", withExternalName(guid), withStatus(guid, app.StateStarted), withObservedName(name), withDeployment(app.DeploymentActive, app.DeploymentDeploying), withAnnotation(app.RestartAnnotation)),
+			deploy: func() *fake.MockDeploy {
+				m := &fake.MockDeploy{}
+				m.On("Restart", guid).Return(nil)
+				return m
+			},
+			want:    newApp("docker", withExternalName(guid), withStatus(guid, app.StateStarted), withObservedName(name), withDeployment(app.DeploymentActive, app.DeploymentDeploying)),
+			updated: true,
+		},
+		"NotRequested": {
+			mg:     newApp("docker", withExternalName(guid), withStatus(guid, app.StateStarted), withObservedName(name)),
+			deploy: func() *fake.MockDeploy { return &fake.MockDeploy{} },
+			want:   newApp("docker", withExternalName(guid), withStatus(guid, app.StateStarted), withObservedName(name)),
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			deploy := tc.deploy()
+			updated := false
+			c := &external{
+				kube: &test.MockClient{
+					// the API server returns the patched object with its stored status
+					MockPatch: func(_ context.Context, obj k8s.Object, _ k8s.Patch, _ ...k8s.PatchOption) error {
+						updated = true
+						obj.(*v1alpha1.App).Status = v1alpha1.AppStatus{}
+						return tc.updateErr
+					},
+				},
+				client: &app.Client{
+					AppClient:    &fake.MockApp{},
+					PushClient:   newMockPush(),
+					DeployClient: deploy,
+				},
+			}
+
+			_, err := c.Update(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Update(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want, tc.mg); diff != "" {
+				t.Errorf("Update(...): -want, +got:\n%s", diff)
+			}
+			if updated != tc.updated {
+				t.Errorf("Update(...): want annotations persisted %t, got %t", tc.updated, updated)
+			}
+			deploy.AssertExpectations(t)
+		})
+	}
+}
+
+func withDeployment(status, reason string) modifier {
+	return func(r *v1alpha1.App) {
+		r.Status.AtProvider.Deployment = &v1alpha1.DeploymentObservation{GUID: deploymentGUID, Status: status, Reason: reason}
+	}
+}
+
+func newDeployment(status, reason string, created time.Time) *cfresource.Deployment {
+	d := &cfresource.Deployment{Status: cfresource.DeploymentStatus{Value: status, Reason: reason}}
+	d.GUID = deploymentGUID
+	d.CreatedAt = created
+	return d
+}
+
+func TestUpdateRollingDeployment(t *testing.T) {
+	cases := map[string]struct {
+		strategy   v1alpha1.DeploymentStrategy
+		deployment *v1alpha1.DeploymentObservation
+		conditions []xpv1.Condition
+	}{
+		"Rolling": {
+			deployment: &v1alpha1.DeploymentObservation{GUID: deploymentGUID, Status: app.DeploymentActive, Reason: app.DeploymentDeploying},
+			conditions: []xpv1.Condition{xpv1.Unavailable().WithMessage("rolling deployment " + deploymentGUID + " is in progress")},
+		},
+		"Recreate": {
+			strategy: v1alpha1.DeploymentStrategyRecreate,
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			cr := newApp("docker",
+				withSpace(spaceGUID),
+				withExternalName(guid),
+				withStatus(guid, app.StateStarted),
+				withObservedName(name),
+				withImage("docker-image:2"),
+				withAppManifest(dockerManifest(1)))
+			cr.Spec.ForProvider.DeploymentStrategy = tc.strategy
+
+			started := &fake.NewApp("docker").SetName(name).SetGUID(guid).SetState(app.StateStarted).App
+			svc := &fake.MockApp{}
+			svc.On("Update", guid).Return(started, nil)
+			svc.On("Stop", guid).Return(started, nil)
+			svc.On("Start", guid).Return(started, nil)
+			manifest := &fake.MockManifest{}
+			manifest.On("ApplyManifest", spaceGUID, mock.Anything).Return("job", nil)
+			job := &fake.MockJob{}
+			job.On("PollComplete").Return(nil)
+			deploy := &fake.MockDeploy{}
+			deploy.On("Deploy", guid, "docker-image:2").Return(newDeployment(app.DeploymentActive, app.DeploymentDeploying, time.Now()), nil)
+			push := newMockPush()
+
+			c := &external{
+				kube: &test.MockClient{MockPatch: test.NewMockPatchFn(nil)},
+				client: &app.Client{
+					AppClient:      svc,
+					PushClient:     push,
+					DeployClient:   deploy,
+					ManifestClient: manifest,
+					Job:            job,
+				},
+			}
+			if _, err := c.Update(context.Background(), cr); err != nil {
+				t.Fatalf("Update(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.deployment, cr.Status.AtProvider.Deployment); diff != "" {
+				t.Errorf("Update(...): -want deployment, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.conditions, cr.Status.Conditions, test.EquateConditions()); diff != "" {
+				t.Errorf("Update(...): -want conditions, +got:\n%s", diff)
+			}
+			if tc.strategy == v1alpha1.DeploymentStrategyRecreate {
+				push.AssertCalled(t, "Push")
+				svc.AssertCalled(t, "Stop", guid)
+				svc.AssertCalled(t, "Start", guid)
+				deploy.AssertNotCalled(t, "Deploy", guid, "docker-image:2")
+			} else {
+				push.AssertNotCalled(t, "Push")
+				svc.AssertNotCalled(t, "Stop", guid)
+			}
+		})
+	}
+}
+
+func withCommand(command string) modifier {
+	return func(r *v1alpha1.App) {
+		r.Spec.ForProvider.Processes = []v1alpha1.ProcessConfiguration{{Type: ptr.To("web"), Command: ptr.To(command)}}
+	}
+}
+
+func TestUpdateCommand(t *testing.T) {
+	const processGUID = "a9b4c1d2-0d3e-4f5a-8b6c-7d8e9f0a1b2c"
+	manifest := dockerManifest(1) + "  command: ./server\n"
+
+	cases := map[string]struct {
+		mg      *v1alpha1.App
+		restart bool
+		deploy  bool
+	}{
+		"CommandChanged": {
+			mg:      newApp("docker", withStatus(guid, app.StateStarted), withImage("docker-image"), withCommand("./server --verbose")),
+			restart: true,
+		},
+		"CommandChangedStoppedApp": {
+			mg: newApp("docker", withStatus(guid, app.StateStopped), withImage("docker-image"), withCommand("./server --verbose")),
+		},
+		"CommandChangedRestartRequested": {
+			mg: newApp("docker", withStatus(guid, app.StateStarted), withImage("docker-image"), withCommand("./server --verbose"), withAnnotation(app.RestartAnnotation)),
+			// the requested restart applies the command
+			restart: true,
+		},
+		"ImageChanged": {
+			// a new droplet is staged and rolled out, which applies the command
+			mg:     newApp("docker", withStatus(guid, app.StateStarted), withImage("docker-image:2"), withCommand("./server --verbose")),
+			deploy: true,
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			cr := tc.mg
+			withSpace(spaceGUID)(cr)
+			withExternalName(guid)(cr)
+			withObservedName(name)(cr)
+			withAppManifest(manifest)(cr)
+
+			started := &fake.NewApp("docker").SetName(name).SetGUID(guid).SetState(app.StateStarted).App
+			svc := &fake.MockApp{}
+			svc.On("Update", guid).Return(started, nil)
+			manifests := &fake.MockManifest{}
+			manifests.On("ApplyManifest", spaceGUID, mock.Anything).Return("job", nil)
+			job := &fake.MockJob{}
+			job.On("PollComplete").Return(nil)
+			process := &fake.MockProcess{}
+			process.On("SingleForApp", guid, []string{"web"}).Return(&cfresource.Process{Resource: cfresource.Resource{GUID: processGUID}}, nil)
+			process.On("Update", processGUID, "./server --verbose").Return(&cfresource.Process{}, nil)
+			deploy := &fake.MockDeploy{}
+			deploy.On("Restart", guid).Return(nil)
+			deploy.On("Deploy", guid, "docker-image:2").Return(newDeployment(app.DeploymentActive, app.DeploymentDeploying, time.Now()), nil)
+			push := newMockPush()
+
+			c := &external{
+				kube: &test.MockClient{MockPatch: test.NewMockPatchFn(nil)},
+				client: &app.Client{
+					AppClient:      svc,
+					PushClient:     push,
+					DeployClient:   deploy,
+					ManifestClient: manifests,
+					ProcessClient:  process,
+					Job:            job,
+				},
+			}
+			if _, err := c.Update(context.Background(), cr); err != nil {
+				t.Fatalf("Update(...): unexpected error: %v", err)
+			}
+
+			deploy.AssertNotCalled(t, "Restage", guid, mock.Anything)
+			push.AssertNotCalled(t, "Push")
+			if tc.deploy {
+				deploy.AssertCalled(t, "Deploy", guid, "docker-image:2")
+				process.AssertNotCalled(t, "Update", processGUID, mock.Anything)
+			} else {
+				deploy.AssertNotCalled(t, "Deploy", guid, mock.Anything)
+				process.AssertCalled(t, "Update", processGUID, "./server --verbose")
+			}
+			if tc.restart {
+				deploy.AssertNumberOfCalls(t, "Restart", 1)
+			} else {
+				deploy.AssertNotCalled(t, "Restart", guid)
+			}
+		})
+	}
+}
+
+func TestObserveRollingDeployment(t *testing.T) {
+	cases := map[string]struct {
+		deployment *cfresource.Deployment
+		want       managed.ExternalObservation
+		observed   *v1alpha1.DeploymentObservation
+		condition  xpv1.Condition
+		cancel     bool
+		paused     bool
+	}{
+		"InProgress": {
+			deployment: newDeployment(app.DeploymentActive, app.DeploymentDeploying, time.Now()),
+			want:       managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			observed:   &v1alpha1.DeploymentObservation{GUID: deploymentGUID, Status: app.DeploymentActive, Reason: app.DeploymentDeploying},
+			condition:  xpv1.Unavailable().WithMessage("rolling deployment " + deploymentGUID + " is in progress"),
+		},
+		"TimedOutIsCanceled": {
+			deployment: newDeployment(app.DeploymentActive, app.DeploymentDeploying, time.Now().Add(-app.DeploymentTimeout-time.Minute)),
+			want:       managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			observed:   &v1alpha1.DeploymentObservation{GUID: deploymentGUID, Status: app.DeploymentActive, Reason: app.DeploymentDeploying},
+			condition:  xpv1.Unavailable().WithMessage(fmt.Sprintf("rolling deployment %s did not finish within %s and is canceled", deploymentGUID, app.DeploymentTimeout)),
+			cancel:     true,
+		},
+		"PausedTimedOutIsNotCanceled": {
+			deployment: newDeployment(app.DeploymentActive, app.DeploymentDeploying, time.Now().Add(-app.DeploymentTimeout-time.Minute)),
+			want:       managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			observed:   &v1alpha1.DeploymentObservation{GUID: deploymentGUID, Status: app.DeploymentActive, Reason: app.DeploymentDeploying},
+			condition:  xpv1.Unavailable().WithMessage("rolling deployment " + deploymentGUID + " is in progress"),
+			paused:     true,
+		},
+		"Canceled": {
+			deployment: newDeployment(app.DeploymentFinalized, app.DeploymentCanceled, time.Now()),
+			want:       managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			condition:  xpv1.Unavailable().WithMessage("rolling deployment " + deploymentGUID + " was canceled"),
+		},
+		"Deployed": {
+			deployment: newDeployment(app.DeploymentFinalized, "DEPLOYED", time.Now()),
+			want:       managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			condition:  xpv1.Available(),
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			svc := &fake.MockApp{}
+			svc.On("Get", guid).Return(&fake.NewApp("docker").SetName(name).SetGUID(guid).SetState(app.StateStarted).App, nil)
+			deploy := &fake.MockDeploy{}
+			deploy.On("GetDeployment", deploymentGUID).Return(tc.deployment, nil)
+			deploy.On("CancelDeployment", deploymentGUID).Return(nil)
+			c := &external{
+				kube:   &test.MockClient{},
+				client: &app.Client{AppClient: svc, PushClient: newMockPush(), DeployClient: deploy},
+			}
+			cr := newApp("docker", withExternalName(guid), withSpace(spaceGUID), withDeployment(app.DeploymentActive, app.DeploymentDeploying))
+			if tc.paused {
+				meta.AddAnnotations(cr, map[string]string{clients.AnnotationKeyPaused: "true"})
+			}
+			ec, err := clients.WithPause(managed.ExternalConnectorFn(func(context.Context, resource.Managed) (managed.ExternalClient, error) {
+				return c, nil
+			})).Connect(context.Background(), cr)
+			if err != nil {
+				t.Fatalf("Connect(...): unexpected error: %v", err)
+			}
+
+			obs, err := ec.Observe(context.Background(), cr)
+			if err != nil {
+				t.Fatalf("Observe(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, obs); diff != "" {
+				t.Errorf("Observe(...): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.observed, cr.Status.AtProvider.Deployment); diff != "" {
+				t.Errorf("Observe(...): -want deployment, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.condition, cr.GetCondition(xpv1.TypeReady), test.EquateConditions()); diff != "" {
+				t.Errorf("Observe(...): -want condition, +got:\n%s", diff)
+			}
+			if tc.cancel {
+				deploy.AssertCalled(t, "CancelDeployment", deploymentGUID)
+			} else {
+				deploy.AssertNotCalled(t, "CancelDeployment", deploymentGUID)
+			}
+		})
+	}
+}
+
+func TestObserveCanceledDeploymentOnce(t *testing.T) {
+	svc := &fake.MockApp{}
+	svc.On("Get", guid).Return(&fake.NewApp("docker").SetName(name).SetGUID(guid).SetState(app.StateStarted).App, nil)
+	deploy := &fake.MockDeploy{}
+	deploy.On("GetDeployment", deploymentGUID).Return(newDeployment(app.DeploymentFinalized, app.DeploymentCanceled, time.Now()), nil)
+	c := &external{
+		kube:   &test.MockClient{},
+		client: &app.Client{AppClient: svc, PushClient: newMockPush(), DeployClient: deploy},
+	}
+	cr := newApp("docker", withExternalName(guid), withSpace(spaceGUID), withDeployment(app.DeploymentActive, app.DeploymentDeploying))
+
+	if _, err := c.Observe(context.Background(), cr); err != nil {
+		t.Fatalf("Observe(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(xpv1.Unavailable().WithMessage("rolling deployment "+deploymentGUID+" was canceled"), cr.GetCondition(xpv1.TypeReady), test.EquateConditions()); diff != "" {
+		t.Errorf("Observe(...): -want condition, +got:\n%s", diff)
+	}
+
+	// the next observation reports the state of the rolled back app
+	if _, err := c.Observe(context.Background(), cr); err != nil {
+		t.Fatalf("Observe(...): unexpected error: %v", err)
+	}
+	if cr.Status.AtProvider.Deployment != nil {
+		t.Errorf("Observe(...): want no deployment, got %v", cr.Status.AtProvider.Deployment)
+	}
+	if diff := cmp.Diff(xpv1.Available(), cr.GetCondition(xpv1.TypeReady), test.EquateConditions()); diff != "" {
+		t.Errorf("Observe(...): -want condition, +got:\n%s", diff)
+	}
+	deploy.AssertNumberOfCalls(t, "GetDeployment", 1)
+}
+
+func TestAppsForConfigMap(t *testing.T) {
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app-config"}}
+
+	cases := map[string]struct {
+		kube k8s.Reader
+		want []reconcile.Request
+	}{
+		"EnqueueReferencingApps": {
+			kube: &test.MockClient{
+				MockList: func(_ context.Context, obj k8s.ObjectList, _ ...k8s.ListOption) error {
+					referencing := newApp("docker", withEnvironmentFromConfigMap("app-config"))
+					referencing.SetNamespace("default")
+					other := newApp("docker", withEnvironmentFromConfigMap("other-config"))
+					other.SetNamespace("default")
+					other.SetName("other-app")
+					obj.(*v1alpha1.AppList).Items = []v1alpha1.App{*referencing, *other}
+					return nil
+				},
+			},
+			want: []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "default", Name: name}}},
+		},
+		"ListError": {
+			kube: &test.MockClient{
+				MockList: test.NewMockListFn(errBoom),
+			},
+			want: nil,
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			got := appsForConfigMap(tc.kube)(context.Background(), configMap)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("appsForConfigMap(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+// deleteMocks returns the clients of an app whose service binding and route
+// mapping are removed with the given errors, recording the calls in order.
+func deleteMocks(calls *[]string, unbindErr, unmapErr, deleteErr []error) *app.Client {
+	record := func(call string) func(mock.Arguments) {
+		return func(mock.Arguments) { *calls = append(*calls, call) }
+	}
+
+	scb := &fake.MockServiceCredentialBinding{}
+	binding := &cfresource.ServiceCredentialBinding{}
+	binding.GUID = "binding"
+	scb.On("ListAll", mock.Anything, mock.Anything).Return([]*cfresource.ServiceCredentialBinding{binding}, nil)
+	for _, err := range unbindErr {
+		scb.On("Delete", mock.Anything, "binding").Return("", err).Once().Run(record("unbind"))
+	}
+
+	routes := &fake.MockRoute{}
+	route := fake.FakeRoute("route", "my-app.example.com")
+	route.Destinations = []cfresource.RouteDestination{
+		{GUID: ptr.To("destination"), App: cfresource.RouteDestinationApp{GUID: ptr.To(guid)}},
+		{GUID: ptr.To("other"), App: cfresource.RouteDestinationApp{GUID: ptr.To("other-app")}},
+	}
+	routes.On("ListForAppAll", guid).Return([]*cfresource.Route{route}, nil)
+	for _, err := range unmapErr {
+		routes.On("RemoveDestination", "route", "destination").Return(err).Once().Run(record("unmap"))
+	}
+
+	apps := &fake.MockApp{}
+	for _, err := range deleteErr {
+		apps.On("Delete", guid).Return("", err).Once().Run(record("delete"))
+	}
+	jobs := &fake.MockJob{}
+	jobs.On("PollComplete").Return(nil)
+
+	return &app.Client{AppClient: apps, RouteClient: routes, ServiceCredentialBinding: scb, Job: jobs}
+}
+
+func TestDelete(t *testing.T) {
+	cases := map[string]struct {
+		unbindErr []error
+		unmapErr  []error
+		deleteErr []error
+		want      []error
+		calls     []string
+	}{
+		"UnbindsAndUnmapsBeforeDelete": {
+			unbindErr: []error{nil},
+			unmapErr:  []error{nil},
+			deleteErr: []error{nil},
+			want:      []error{nil},
+			calls:     []string{"unbind", "unmap", "delete"},
+		},
+		"IgnoresNotFound": {
+			unbindErr: []error{fake.ErrResourceNotFound},
+			unmapErr:  []error{fake.ErrResourceNotFound},
+			deleteErr: []error{fake.ErrResourceNotFound},
+			want:      []error{nil},
+			calls:     []string{"unbind", "unmap", "delete"},
+		},
+		"AggregatesErrors": {
+			unbindErr: []error{errBoom},
+			unmapErr:  []error{errBoom},
+			want: []error{errors.Wrap(fmt.Errorf("%w\n%w",
+				fmt.Errorf("cannot unbind service binding binding: %w", errBoom),
+				fmt.Errorf("cannot unmap route my-app.example.com: %w", errBoom)), errRemoveDependents)},
+			calls: []string{"unbind", "unmap"},
+		},
+		"RetriesAfterPartialFailure": {
+			unbindErr: []error{errBoom, fake.ErrResourceNotFound},
+			unmapErr:  []error{nil, fake.ErrResourceNotFound},
+			deleteErr: []error{nil},
+			want: []error{
+				errors.Wrap(fmt.Errorf("cannot unbind service binding binding: %w", errBoom), errRemoveDependents),
+				nil,
+			},
+			calls: []string{"unbind", "unmap", "unbind", "unmap", "delete"},
+		},
+		"RetriesFailedAppDelete": {
+			unbindErr: []error{nil, fake.ErrResourceNotFound},
+			unmapErr:  []error{nil, fake.ErrResourceNotFound},
+			deleteErr: []error{errBoom, nil},
+			want:      []error{errors.Wrap(errBoom, errDeleteResource), nil},
+			calls:     []string{"unbind", "unmap", "delete", "unbind", "unmap", "delete"},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			var calls []string
+			c := &external{client: deleteMocks(&calls, tc.unbindErr, tc.unmapErr, tc.deleteErr)}
+			cr := newApp("docker", withExternalName(guid))
+
+			for i, want := range tc.want {
+				_, err := c.Delete(context.Background(), cr)
+				if diff := cmp.Diff(fmt.Sprint(want), fmt.Sprint(err)); diff != "" {
+					t.Errorf("Delete(...) attempt %d: -want error, +got error:\n%s", i, diff)
+				}
+			}
+			if diff := cmp.Diff(tc.calls, calls); diff != "" {
+				t.Errorf("Delete(...) calls: -want, +got:\n%s", diff)
+			}
 		})
 	}
 }
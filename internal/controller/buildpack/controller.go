@@ -0,0 +1,230 @@
+package buildpack
+
+import (
+	"context"
+
+	cfresource "github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	k8s "sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/feature"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	pcv1beta1 "github.com/SAP/crossplane-provider-cloudfoundry/apis/v1beta1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/buildpack"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/job"
+)
+
+const (
+	resourceType         = "Buildpack"
+	externalSystem       = "Cloud Foundry"
+	errNotBuildpack      = "managed resource is not of kind " + resourceType
+	errTrackUsage        = "cannot track usage"
+	errGetClient         = "cannot create a client to talk to the API of " + externalSystem
+	errGet               = "cannot get " + externalSystem + " buildpack"
+	errCreate            = "cannot create " + externalSystem + " buildpack"
+	errUpdate            = "cannot update " + externalSystem + " buildpack"
+	errUnlock            = "cannot unlock " + externalSystem + " buildpack"
+	errUpload            = "cannot upload the bits of " + externalSystem + " buildpack"
+	errDelete            = "cannot delete " + externalSystem + " buildpack"
+	errMissingExternalID = "external name is not set"
+)
+
+// Setup adds a controller that reconciles Buildpack resources.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(v1alpha1.Buildpack_GroupKind)
+
+	options := []managed.ReconcilerOption{
+		managed.WithExternalConnecter(clients.WithPause(clients.WithErrorClassification(clients.WithOperationTimeout(mgr.GetClient(), &connector{
+			kube:  mgr.GetClient(),
+			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &pcv1beta1.ProviderConfigUsage{}),
+		}, clients.DefaultOperationTimeout)))),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithTimeout(clients.MaxOperationTimeout),
+		managed.WithPollIntervalHook(clients.PollIntervalHook(mgr.GetClient())),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithPollInterval(o.PollInterval),
+	}
+
+	if o.Features.Enabled(feature.EnableBetaManagementPolicies) {
+		options = append(options, managed.WithManagementPolicies())
+	}
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.Buildpack_GroupVersionKind),
+		options...)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1alpha1.Buildpack{}).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// A connector supplies a function for the Reconciler to create a client to the external CloudFoundry resources.
+type connector struct {
+	kube  k8s.Client
+	usage *resource.ProviderConfigUsageTracker
+}
+
+// Connect typically produces an ExternalClient by:
+// 1. Tracking that the managed resource is using a ProviderConfig.
+// 2. Getting the managed resource's ProviderConfig.
+// 3. Getting the credentials specified by the ProviderConfig.
+// 4. Using the credentials to form a client.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1alpha1.Buildpack); !ok {
+		return nil, errors.New(errNotBuildpack)
+	}
+
+	if err := c.usage.Track(ctx, mg.(resource.ModernManaged)); err != nil {
+		return nil, errors.Wrap(err, errTrackUsage)
+	}
+
+	cf, err := clients.ClientFnBuilder(ctx, c.kube)(mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetClient)
+	}
+
+	pc, err := clients.GetProviderConfig(ctx, c.kube, mg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &external{client: buildpack.NewClient(cf), job: cf.Jobs, sources: buildpack.NewSources(pc.Spec.AllowedBuildpackSources), kube: c.kube}, nil
+}
+
+// Disconnect implements the managed.ExternalClient interface
+func (c *external) Disconnect(ctx context.Context) error {
+	// No cleanup needed for Cloud Foundry client
+	return nil
+}
+
+// An external is a managed.ExternalConnecter that is using the CloudFoundry API to observe and modify resources.
+type external struct {
+	client  buildpack.Client
+	job     job.Job
+	sources buildpack.Opener
+	kube    k8s.Client
+}
+
+// Observe managed resource Buildpack
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.Buildpack)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotBuildpack)
+	}
+
+	guid := meta.GetExternalName(cr)
+
+	bp, err := buildpack.GetByIDOrName(ctx, c.client, guid, cr.Spec.ForProvider)
+	if err != nil {
+		if clients.IsNotFound(err) {
+			return managed.ExternalObservation{ResourceExists: false}, nil
+		}
+		return managed.ExternalObservation{}, errors.Wrap(err, errGet)
+	}
+
+	lateInitialized := buildpack.LateInitialize(&cr.Spec.ForProvider, bp)
+
+	// set the external name to the GUID
+	if guid != bp.GUID {
+		meta.SetExternalName(cr, bp.GUID)
+		lateInitialized = true
+	}
+
+	cr.Status.AtProvider = buildpack.GenerateObservation(bp, cr.Status.AtProvider.Source)
+	cr.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{
+		ResourceExists: true,
+		ResourceUpToDate: buildpack.IsUpToDate(cr.Spec.ForProvider, bp) &&
+			!buildpack.NeedsUpload(cr.Spec.ForProvider, cr.Status.AtProvider),
+		ResourceLateInitialized: lateInitialized,
+	}, nil
+}
+
+// Create a managed resource Buildpack. The bits are uploaded by the next Update.
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.Buildpack)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotBuildpack)
+	}
+
+	cr.SetConditions(xpv1.Creating())
+
+	bp, err := c.client.Create(ctx, buildpack.GenerateCreate(cr.Spec.ForProvider))
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreate)
+	}
+
+	meta.SetExternalName(cr, bp.GUID)
+
+	return managed.ExternalCreation{}, nil
+}
+
+// Update managed resource Buildpack
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.Buildpack)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotBuildpack)
+	}
+
+	guid := meta.GetExternalName(cr)
+	if !clients.IsValidGUID(guid) {
+		return managed.ExternalUpdate{}, errors.Wrap(errors.New(errMissingExternalID), errUpdate)
+	}
+
+	if buildpack.NeedsUpload(cr.Spec.ForProvider, cr.Status.AtProvider) {
+		// the bits of a locked buildpack cannot be replaced, unlock it before the upload
+		if ptr.Deref(cr.Status.AtProvider.Locked, false) {
+			unlock := &cfresource.BuildpackCreateOrUpdate{Locked: ptr.To(false), Stack: cr.Status.AtProvider.Stack}
+			if _, err := c.client.Update(ctx, guid, unlock); err != nil {
+				return managed.ExternalUpdate{}, errors.Wrap(err, errUnlock)
+			}
+		}
+		if err := buildpack.Upload(ctx, c.client, c.job, c.sources, guid, *cr.Spec.ForProvider.Source); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errUpload)
+		}
+		cr.Status.AtProvider.Source = cr.Spec.ForProvider.Source
+	}
+
+	if _, err := c.client.Update(ctx, guid, buildpack.GenerateUpdate(cr.Spec.ForProvider, cr.Status.AtProvider)); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdate)
+	}
+
+	return managed.ExternalUpdate{}, nil
+}
+
+// Delete managed resource Buildpack
+func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	cr, ok := mg.(*v1alpha1.Buildpack)
+	if !ok {
+		return managed.ExternalDelete{}, errors.New(errNotBuildpack)
+	}
+	cr.SetConditions(xpv1.Deleting())
+
+	guid := meta.GetExternalName(cr)
+	if !clients.IsValidGUID(guid) {
+		return managed.ExternalDelete{}, errors.Wrap(errors.New(errMissingExternalID), errDelete)
+	}
+
+	// Delete is async, wait for the job to complete. A buildpack that is already gone is deleted.
+	jobGUID, err := c.client.Delete(ctx, guid)
+	if err != nil {
+		return managed.ExternalDelete{}, errors.Wrap(clients.IgnoreNotFoundErr(err), errDelete)
+	}
+
+	return managed.ExternalDelete{}, errors.Wrap(job.PollJobComplete(ctx, c.job, jobGUID), errDelete)
+}
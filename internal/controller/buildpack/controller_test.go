@@ -0,0 +1,550 @@
+package buildpack
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	cfresource "github.com/cloudfoundry/go-cfclient/v3/resource"
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/buildpack"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/fake"
+)
+
+var (
+	errBoom   = errors.New("boom")
+	name      = "go_buildpack"
+	stackName = "cflinuxfs4"
+	guid      = "b85a788e-671f-4549-814d-e34cdb2f539a"
+	jobGUID   = "3d8b0d04-d537-4e4e-8c6f-f09ca0e7f56f"
+)
+
+type modifier func(*v1alpha1.Buildpack)
+
+func withExternalName(name string) modifier {
+	return func(r *v1alpha1.Buildpack) {
+		meta.SetExternalName(r, name)
+	}
+}
+
+func withStack(stack string) modifier {
+	return func(r *v1alpha1.Buildpack) {
+		r.Spec.ForProvider.Stack = &stack
+	}
+}
+
+func withAttributes(position int, enabled, locked bool) modifier {
+	return func(r *v1alpha1.Buildpack) {
+		r.Spec.ForProvider.Position = &position
+		r.Spec.ForProvider.Enabled = &enabled
+		r.Spec.ForProvider.Locked = &locked
+	}
+}
+
+func withSource(source string) modifier {
+	return func(r *v1alpha1.Buildpack) {
+		r.Spec.ForProvider.Source = &source
+	}
+}
+
+func withObservation(o v1alpha1.BuildpackObservation) modifier {
+	return func(r *v1alpha1.Buildpack) {
+		r.Status.AtProvider = o
+	}
+}
+
+func withConditions(c ...xpv1.Condition) modifier {
+	return func(r *v1alpha1.Buildpack) { r.Status.SetConditions(c...) }
+}
+
+func fakeBuildpack(m ...modifier) *v1alpha1.Buildpack {
+	r := &v1alpha1.Buildpack{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Finalizers:  []string{},
+			Annotations: map[string]string{},
+		},
+		Spec: v1alpha1.BuildpackSpec{
+			ForProvider: v1alpha1.BuildpackParameters{Name: name},
+		},
+	}
+
+	for _, rm := range m {
+		rm(r)
+	}
+	return r
+}
+
+func TestObserve(t *testing.T) {
+	type service func() *fake.MockBuildpack
+	type args struct {
+		mg resource.Managed
+	}
+
+	type want struct {
+		mg  *v1alpha1.Buildpack
+		obs managed.ExternalObservation
+		err error
+	}
+
+	cases := map[string]struct {
+		args    args
+		want    want
+		service service
+	}{
+		"WrongKind": {
+			args: args{
+				mg: nil,
+			},
+			want: want{
+				err: errors.New(errNotBuildpack),
+			},
+			service: func() *fake.MockBuildpack {
+				return &fake.MockBuildpack{}
+			},
+		},
+		"Error": {
+			args: args{
+				mg: fakeBuildpack(withExternalName(guid)),
+			},
+			want: want{
+				mg:  fakeBuildpack(withExternalName(guid)),
+				err: errors.Wrap(errBoom, errGet),
+			},
+			service: func() *fake.MockBuildpack {
+				m := &fake.MockBuildpack{}
+				m.On("Get", guid).Return(fake.BuildpackNil, errBoom)
+				return m
+			},
+		},
+		"NotFound": {
+			args: args{
+				mg: fakeBuildpack(withStack(stackName)),
+			},
+			want: want{
+				mg:  fakeBuildpack(withStack(stackName)),
+				obs: managed.ExternalObservation{ResourceExists: false},
+			},
+			service: func() *fake.MockBuildpack {
+				m := &fake.MockBuildpack{}
+				m.On("Single", []string{name}, []string{stackName}).Return(fake.BuildpackNil, fake.ErrNoResultReturned)
+				return m
+			},
+		},
+		"LateInitializeByName": {
+			args: args{
+				mg: fakeBuildpack(),
+			},
+			want: want{
+				mg: fakeBuildpack(withExternalName(guid), withAttributes(3, true, false)),
+				obs: managed.ExternalObservation{
+					ResourceExists:          true,
+					ResourceUpToDate:        true,
+					ResourceLateInitialized: true,
+				},
+			},
+			service: func() *fake.MockBuildpack {
+				m := &fake.MockBuildpack{}
+				m.On("Single", []string{name}, []string(nil)).Return(
+					&fake.NewBuildpack().SetName(name).SetGUID(guid).SetPosition(3).SetEnabled(true).Buildpack,
+					nil,
+				)
+				return m
+			},
+		},
+		"PositionDrift": {
+			args: args{
+				mg: fakeBuildpack(withExternalName(guid), withAttributes(1, true, false)),
+			},
+			want: want{
+				mg:  fakeBuildpack(withExternalName(guid), withAttributes(1, true, false)),
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+			service: func() *fake.MockBuildpack {
+				m := &fake.MockBuildpack{}
+				m.On("Get", guid).Return(
+					&fake.NewBuildpack().SetName(name).SetGUID(guid).SetPosition(3).SetEnabled(true).Buildpack,
+					nil,
+				)
+				return m
+			},
+		},
+		"AwaitingUpload": {
+			args: args{
+				mg: fakeBuildpack(withExternalName(guid), withAttributes(1, true, false), withSource("bits.zip")),
+			},
+			want: want{
+				mg:  fakeBuildpack(withExternalName(guid), withAttributes(1, true, false), withSource("bits.zip")),
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+			service: func() *fake.MockBuildpack {
+				m := &fake.MockBuildpack{}
+				m.On("Get", guid).Return(
+					&fake.NewBuildpack().SetName(name).SetGUID(guid).SetPosition(1).SetEnabled(true).SetState(buildpack.StateAwaitingUpload).Buildpack,
+					nil,
+				)
+				return m
+			},
+		},
+		"UploadedSourceUpToDate": {
+			args: args{
+				mg: fakeBuildpack(withExternalName(guid), withAttributes(1, true, true), withSource("bits.zip"),
+					withObservation(v1alpha1.BuildpackObservation{Source: ptr.To("bits.zip")})),
+			},
+			want: want{
+				mg:  fakeBuildpack(withExternalName(guid), withAttributes(1, true, true), withSource("bits.zip")),
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+			service: func() *fake.MockBuildpack {
+				m := &fake.MockBuildpack{}
+				m.On("Get", guid).Return(
+					&fake.NewBuildpack().SetName(name).SetGUID(guid).SetPosition(1).SetEnabled(true).SetLocked(true).SetState("READY").Buildpack,
+					nil,
+				)
+				return m
+			},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			c := &external{client: tc.service()}
+			obs, err := c.Observe(context.Background(), tc.args.mg)
+
+			if tc.want.err != nil && err != nil {
+				if diff := cmp.Diff(tc.want.err.Error(), err.Error()); diff != "" {
+					t.Errorf("Observe(...): want error string != got error string:\n%s", diff)
+				}
+			} else if diff := cmp.Diff(tc.want.err, err); diff != "" {
+				t.Errorf("Observe(...): want error != got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.obs, obs); diff != "" {
+				t.Errorf("Observe(...): -want, +got:\n%s", diff)
+			}
+			if cr, ok := tc.args.mg.(*v1alpha1.Buildpack); ok && tc.want.mg != nil {
+				if diff := cmp.Diff(tc.want.mg.Spec, cr.Spec); diff != "" {
+					t.Errorf("Observe(...): -want spec, +got spec:\n%s", diff)
+				}
+				if diff := cmp.Diff(meta.GetExternalName(tc.want.mg), meta.GetExternalName(cr)); diff != "" {
+					t.Errorf("Observe(...): -want external name, +got external name:\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	type service func() *fake.MockBuildpack
+	type args struct {
+		mg resource.Managed
+	}
+
+	type want struct {
+		mg  *v1alpha1.Buildpack
+		err error
+	}
+
+	cases := map[string]struct {
+		args    args
+		want    want
+		service service
+	}{
+		"Successful": {
+			args: args{
+				mg: fakeBuildpack(withStack(stackName), withAttributes(1, true, true), withSource("bits.zip")),
+			},
+			want: want{
+				mg: fakeBuildpack(withStack(stackName), withAttributes(1, true, true), withSource("bits.zip"), withExternalName(guid), withConditions(xpv1.Creating())),
+			},
+			service: func() *fake.MockBuildpack {
+				m := &fake.MockBuildpack{}
+				m.On("Create", &cfresource.BuildpackCreateOrUpdate{
+					Name:     ptr.To(name),
+					Position: ptr.To(1),
+					Enabled:  ptr.To(true),
+					Stack:    ptr.To(stackName),
+				}).Return(&fake.NewBuildpack().SetName(name).SetGUID(guid).Buildpack, nil)
+				return m
+			},
+		},
+		"Error": {
+			args: args{
+				mg: fakeBuildpack(),
+			},
+			want: want{
+				mg:  fakeBuildpack(withConditions(xpv1.Creating())),
+				err: errors.Wrap(errBoom, errCreate),
+			},
+			service: func() *fake.MockBuildpack {
+				m := &fake.MockBuildpack{}
+				m.On("Create", mock.Anything).Return(fake.BuildpackNil, errBoom)
+				return m
+			},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			c := &external{client: tc.service()}
+			_, err := c.Create(context.Background(), tc.args.mg)
+
+			if tc.want.err != nil && err != nil {
+				if diff := cmp.Diff(tc.want.err.Error(), err.Error()); diff != "" {
+					t.Errorf("Create(...): want error string != got error string:\n%s", diff)
+				}
+			} else if diff := cmp.Diff(tc.want.err, err); diff != "" {
+				t.Errorf("Create(...): want error != got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg); diff != "" {
+				t.Errorf("Create(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+// bitsOpener opens the bits of every source as bits.zip
+type bitsOpener struct{}
+
+func (bitsOpener) Open(context.Context, string) (io.ReadCloser, string, error) {
+	return io.NopCloser(strings.NewReader("bits")), "bits.zip", nil
+}
+
+func TestUpdate(t *testing.T) {
+	bits := "https://example.com/buildpacks/bits.zip"
+
+	type service func() *fake.MockBuildpack
+	type args struct {
+		mg resource.Managed
+	}
+
+	cases := map[string]struct {
+		args    args
+		want    error
+		source  *string
+		sources buildpack.Opener
+		service service
+		job     func() *fake.MockJob
+	}{
+		"UpdateAttributes": {
+			args: args{
+				mg: fakeBuildpack(withExternalName(guid), withAttributes(1, false, false),
+					withObservation(v1alpha1.BuildpackObservation{Stack: ptr.To(stackName)})),
+			},
+			service: func() *fake.MockBuildpack {
+				m := &fake.MockBuildpack{}
+				m.On("Update", guid, &cfresource.BuildpackCreateOrUpdate{
+					Name:     ptr.To(name),
+					Position: ptr.To(1),
+					Enabled:  ptr.To(false),
+					Locked:   ptr.To(false),
+					Stack:    ptr.To(stackName),
+				}).Return(&fake.NewBuildpack().SetName(name).SetGUID(guid).Buildpack, nil)
+				return m
+			},
+		},
+		"UploadAndLock": {
+			args: args{
+				mg: fakeBuildpack(withExternalName(guid), withAttributes(1, true, true), withSource(bits),
+					withObservation(v1alpha1.BuildpackObservation{State: ptr.To(buildpack.StateAwaitingUpload), Locked: ptr.To(false)})),
+			},
+			source: &bits,
+			service: func() *fake.MockBuildpack {
+				m := &fake.MockBuildpack{}
+				m.On("Upload", guid, "bits.zip").Return(jobGUID, fake.BuildpackNil, nil)
+				m.On("Update", guid, &cfresource.BuildpackCreateOrUpdate{
+					Name:     ptr.To(name),
+					Position: ptr.To(1),
+					Enabled:  ptr.To(true),
+					Locked:   ptr.To(true),
+				}).Return(&fake.NewBuildpack().SetName(name).SetGUID(guid).Buildpack, nil)
+				return m
+			},
+			job: func() *fake.MockJob {
+				m := &fake.MockJob{}
+				m.On("PollComplete").Return(nil)
+				return m
+			},
+		},
+		"UnlockToReplaceBits": {
+			args: args{
+				mg: fakeBuildpack(withExternalName(guid), withAttributes(1, true, true), withSource(bits),
+					withObservation(v1alpha1.BuildpackObservation{State: ptr.To("READY"), Locked: ptr.To(true), Source: ptr.To("old.zip")})),
+			},
+			source: &bits,
+			service: func() *fake.MockBuildpack {
+				m := &fake.MockBuildpack{}
+				m.On("Update", guid, &cfresource.BuildpackCreateOrUpdate{Locked: ptr.To(false)}).
+					Return(&fake.NewBuildpack().SetName(name).SetGUID(guid).Buildpack, nil).Once()
+				m.On("Upload", guid, "bits.zip").Return(jobGUID, fake.BuildpackNil, nil)
+				m.On("Update", guid, &cfresource.BuildpackCreateOrUpdate{
+					Name:     ptr.To(name),
+					Position: ptr.To(1),
+					Enabled:  ptr.To(true),
+					Locked:   ptr.To(true),
+				}).Return(&fake.NewBuildpack().SetName(name).SetGUID(guid).Buildpack, nil).Once()
+				return m
+			},
+			job: func() *fake.MockJob {
+				m := &fake.MockJob{}
+				m.On("PollComplete").Return(nil)
+				return m
+			},
+		},
+		"UploadError": {
+			args: args{
+				mg: fakeBuildpack(withExternalName(guid), withAttributes(1, true, false), withSource(bits),
+					withObservation(v1alpha1.BuildpackObservation{State: ptr.To(buildpack.StateAwaitingUpload)})),
+			},
+			want: errors.Wrap(errors.Wrap(errBoom, "cannot upload buildpack bits"), errUpload),
+			service: func() *fake.MockBuildpack {
+				m := &fake.MockBuildpack{}
+				m.On("Upload", guid, "bits.zip").Return("", fake.BuildpackNil, errBoom)
+				return m
+			},
+		},
+		"SourceNotAllowed": {
+			args: args{
+				mg: fakeBuildpack(withExternalName(guid), withAttributes(1, true, false), withSource("https://internal.example.com/bits.zip"),
+					withObservation(v1alpha1.BuildpackObservation{State: ptr.To(buildpack.StateAwaitingUpload)})),
+			},
+			want:    errors.Wrap(errors.Errorf("buildpack source %q is not an https URL that the allowedBuildpackSources of the ProviderConfig allow", "https://internal.example.com/bits.zip"), errUpload),
+			sources: buildpack.NewSources([]string{"https://example.com/buildpacks/"}),
+			service: func() *fake.MockBuildpack {
+				// no expectations, nothing is uploaded
+				return &fake.MockBuildpack{}
+			},
+		},
+		"UpdateError": {
+			args: args{
+				mg: fakeBuildpack(withExternalName(guid)),
+			},
+			want: errors.Wrap(errBoom, errUpdate),
+			service: func() *fake.MockBuildpack {
+				m := &fake.MockBuildpack{}
+				m.On("Update", guid, mock.Anything).Return(fake.BuildpackNil, errBoom)
+				return m
+			},
+		},
+		"NoExternalName": {
+			args: args{
+				mg: fakeBuildpack(),
+			},
+			want: errors.Wrap(errors.New(errMissingExternalID), errUpdate),
+			service: func() *fake.MockBuildpack {
+				return &fake.MockBuildpack{}
+			},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			m, j := tc.service(), &fake.MockJob{}
+			if tc.job != nil {
+				j = tc.job()
+			}
+			if tc.sources == nil {
+				tc.sources = bitsOpener{}
+			}
+			c := &external{client: m, job: j, sources: tc.sources}
+			_, err := c.Update(context.Background(), tc.args.mg)
+
+			if tc.want != nil && err != nil {
+				if diff := cmp.Diff(tc.want.Error(), err.Error()); diff != "" {
+					t.Errorf("Update(...): want error string != got error string:\n%s", diff)
+				}
+			} else if diff := cmp.Diff(tc.want, err); diff != "" {
+				t.Errorf("Update(...): want error != got error:\n%s", diff)
+			}
+			if cr, ok := tc.args.mg.(*v1alpha1.Buildpack); ok && tc.source != nil {
+				if diff := cmp.Diff(tc.source, cr.Status.AtProvider.Source); diff != "" {
+					t.Errorf("Update(...): -want source, +got source:\n%s", diff)
+				}
+			}
+			m.AssertExpectations(t)
+			j.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	type service func() *fake.MockBuildpack
+	type args struct {
+		mg resource.Managed
+	}
+
+	cases := map[string]struct {
+		args    args
+		want    error
+		service service
+		job     func() *fake.MockJob
+	}{
+		"Successful": {
+			args: args{
+				mg: fakeBuildpack(withExternalName(guid)),
+			},
+			service: func() *fake.MockBuildpack {
+				m := &fake.MockBuildpack{}
+				m.On("Delete", guid).Return(jobGUID, nil)
+				return m
+			},
+			job: func() *fake.MockJob {
+				m := &fake.MockJob{}
+				m.On("PollComplete").Return(nil)
+				return m
+			},
+		},
+		"AlreadyDeleted": {
+			args: args{
+				mg: fakeBuildpack(withExternalName(guid)),
+			},
+			service: func() *fake.MockBuildpack {
+				m := &fake.MockBuildpack{}
+				m.On("Delete", guid).Return("", fake.ErrResourceNotFound)
+				return m
+			},
+		},
+		"Error": {
+			args: args{
+				mg: fakeBuildpack(withExternalName(guid)),
+			},
+			want: errors.Wrap(errBoom, errDelete),
+			service: func() *fake.MockBuildpack {
+				m := &fake.MockBuildpack{}
+				m.On("Delete", guid).Return("", errBoom)
+				return m
+			},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			m, j := tc.service(), &fake.MockJob{}
+			if tc.job != nil {
+				j = tc.job()
+			}
+			c := &external{client: m, job: j}
+			_, err := c.Delete(context.Background(), tc.args.mg)
+
+			if tc.want != nil && err != nil {
+				if diff := cmp.Diff(tc.want.Error(), err.Error()); diff != "" {
+					t.Errorf("Delete(...): want error string != got error string:\n%s", diff)
+				}
+			} else if diff := cmp.Diff(tc.want, err); diff != "" {
+				t.Errorf("Delete(...): want error != got error:\n%s", diff)
+			}
+			m.AssertExpectations(t)
+			j.AssertExpectations(t)
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
 
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/controller/app"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/controller/buildpack"
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/controller/domain"
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/controller/org"
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/controller/orgmembers"
@@ -20,10 +21,12 @@ import (
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/controller/spacerole"
 
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/controller/route"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/controller/securitygroup"
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/controller/servicecredentialbinding"
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/controller/serviceinstance"
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/controller/space"
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/controller/spacequota"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/controller/stack"
 
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/controller/providerconfig"
 )
@@ -47,6 +50,9 @@ func CustomSetup(mgr ctrl.Manager, o controller.Options) error {
 		spacequota.Setup,
 		domain.Setup,
 		serviceroutebinding.Setup,
+		securitygroup.Setup,
+		stack.Setup,
+		buildpack.Setup,
 	} {
 		if err := setup(mgr, o); err != nil {
 			return err
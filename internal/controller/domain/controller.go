@@ -13,6 +13,7 @@ import (
 	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/feature"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
@@ -45,17 +46,23 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 
 	options := []managed.ReconcilerOption{
 
-		managed.WithExternalConnecter(&connector{
+		managed.WithExternalConnecter(clients.WithPause(clients.WithErrorClassification(clients.WithOperationTimeout(mgr.GetClient(), &connector{
 			kube:  mgr.GetClient(),
 			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &pcv1beta1.ProviderConfigUsage{}),
-		}),
+		}, clients.DefaultOperationTimeout)))),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithTimeout(clients.MaxOperationTimeout),
+		managed.WithPollIntervalHook(clients.PollIntervalHook(mgr.GetClient())),
 		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 		managed.WithInitializers(initializer{
 			client: mgr.GetClient(),
 		}),
 	}
 
+	if o.Features.Enabled(feature.EnableBetaManagementPolicies) {
+		options = append(options, managed.WithManagementPolicies())
+	}
+
 	r := managed.NewReconciler(mgr,
 		resource.ManagedKind(v1alpha1.Domain_GroupVersionKind),
 		options...)
@@ -119,7 +126,7 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	d, err := domain.GetByIDOrName(ctx, c.client, domainID, cr.Spec.ForProvider.Name)
 
 	if err != nil {
-		if clients.ErrorIsNotFound(err) {
+		if clients.IsNotFound(err) {
 			return managed.ExternalObservation{ResourceExists: false}, nil
 		}
 
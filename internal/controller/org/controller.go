@@ -3,7 +3,9 @@ package org
 import (
 	"context"
 
+	cfresource "github.com/cloudfoundry/go-cfclient/v3/resource"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/utils/ptr"
 
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -12,6 +14,7 @@ import (
 	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/feature"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
@@ -20,6 +23,7 @@ import (
 	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
 	pcv1beta1 "github.com/SAP/crossplane-provider-cloudfoundry/apis/v1beta1"
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/job"
 	org "github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/org"
 )
 
@@ -33,6 +37,10 @@ const (
 	errGetResource       = "cannot get " + externalSystem + " organization according to the specified parameters"
 	errCreate            = "cannot create " + externalSystem + " organization"
 	errGet               = "cannot get " + resourceType + " in " + externalSystem
+	errUpdate            = "cannot update " + externalSystem + " organization"
+	errApplyQuota        = "cannot apply quota to " + externalSystem + " organization"
+	errDelete            = "cannot delete " + externalSystem + " organization"
+	errListApps          = "cannot list apps of " + externalSystem + " organization"
 )
 
 // Setup adds a controller that reconciles Org resources.
@@ -40,12 +48,19 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 	name := managed.ControllerName(v1alpha1.Org_GroupKind)
 
 	options := []managed.ReconcilerOption{
-		managed.WithExternalConnecter(&connector{
+		managed.WithExternalConnecter(clients.WithPause(clients.WithErrorClassification(clients.WithOperationTimeout(mgr.GetClient(), &connector{
 			kube:  mgr.GetClient(),
 			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &pcv1beta1.ProviderConfigUsage{}),
-		}),
+		}, clients.DefaultOperationTimeout)))),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithTimeout(clients.MaxOperationTimeout),
+		managed.WithPollIntervalHook(clients.PollIntervalHook(mgr.GetClient())),
 		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
+	}
+
+	if o.Features.Enabled(feature.EnableBetaManagementPolicies) {
+		options = append(options, managed.WithManagementPolicies())
 	}
 
 	r := managed.NewReconciler(mgr,
@@ -84,7 +99,7 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errGetClient)
 	}
 
-	return &external{client: org.NewClient(cf), kube: c.kube}, nil
+	return &external{client: org.NewClient(cf), quota: org.NewQuotaClient(cf), apps: org.NewAppClient(cf), job: cf.Jobs, kube: c.kube}, nil
 }
 
 // Disconnect implements the managed.ExternalClient interface
@@ -96,6 +111,9 @@ func (c *external) Disconnect(ctx context.Context) error {
 // An external is a managed.ExternalConnecter that is using the CloudFoundry API to observe and modify resources.
 type external struct {
 	client org.Client
+	quota  org.QuotaClient
+	apps   org.AppClient
+	job    job.Getter
 	kube   k8s.Client
 }
 
@@ -117,32 +135,40 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	o, err := org.GetByIDOrName(ctx, c.client, external_name, name)
 
 	if err != nil {
-		if clients.ErrorIsNotFound(err) {
+		if clients.IsNotFound(err) {
 			return managed.ExternalObservation{ResourceExists: false}, nil
 		}
 
 		return managed.ExternalObservation{}, errors.Wrap(err, errGetResource)
 	}
 
-	org.LateInitialize(&cr.Spec.ForProvider, o)
+	lateInitialized := org.LateInitialize(&cr.Spec.ForProvider, o)
 
 	// set the external name to the GUID
 	if external_name != o.GUID {
 		meta.SetExternalName(cr, o.GUID)
-		if err := c.kube.Update(ctx, cr); err != nil {
-			return managed.ExternalObservation{}, errors.Wrap(err, errGet)
+		if !clients.IsReadOnly(ctx) {
+			if err := c.kube.Update(ctx, cr); err != nil {
+				return managed.ExternalObservation{}, errors.Wrap(err, errGet)
+			}
 		}
 	}
 
+	managedMetadata := clients.ObserveManagedMetadata(cr.Status.AtProvider.ManagedMetadata, cr.Spec.ForProvider.Labels, cr.Spec.ForProvider.Annotations, o.Metadata)
 	cr.Status.AtProvider = org.GenerateObservation(o)
+	cr.Status.AtProvider.ManagedMetadata = managedMetadata
 
 	if !ptr.Deref(cr.Status.AtProvider.Suspended, false) {
 		cr.Status.SetConditions(xpv1.Available())
+		if cr.GetCondition(org.TypeSuspensionWarning).Status == corev1.ConditionTrue {
+			cr.Status.SetConditions(org.Active())
+		}
 	}
 
 	return managed.ExternalObservation{
-		ResourceExists:   cr.Status.AtProvider.ID != nil,
-		ResourceUpToDate: org.IsUpToDate(cr.Spec.ForProvider, o),
+		ResourceExists:          cr.Status.AtProvider.ID != nil,
+		ResourceUpToDate:        org.IsUpToDate(cr.Spec.ForProvider, managedMetadata, o),
+		ResourceLateInitialized: lateInitialized,
 	}, nil
 }
 
@@ -160,6 +186,12 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 
 	meta.SetExternalName(cr, o.GUID)
 
+	if cr.Spec.ForProvider.Quota != nil {
+		if _, err := c.quota.Apply(ctx, *cr.Spec.ForProvider.Quota, []string{o.GUID}); err != nil {
+			return managed.ExternalCreation{}, errors.Wrap(err, errApplyQuota)
+		}
+	}
+
 	return managed.ExternalCreation{
 		// Optionally return any details that may be required to connect to the
 		// external resource. These will be stored as the connection secret.
@@ -169,12 +201,37 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 
 // Update managed resource Org
 func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
-	_, ok := mg.(*v1alpha1.Organization)
+	cr, ok := mg.(*v1alpha1.Organization)
 	if !ok {
 		return managed.ExternalUpdate{}, errors.New(errNotOrgKind)
 	}
 
-	// Do nothing, as Org is observe-only
+	guid := meta.GetExternalName(cr)
+	if !clients.IsValidGUID(guid) {
+		return managed.ExternalUpdate{}, errors.New(errUpdate)
+	}
+
+	// suspending does not stop running apps, warn about them but suspend anyway
+	if org.IsSuspending(cr.Spec.ForProvider, cr.Status.AtProvider) {
+		running, err := org.CountRunningApps(ctx, c.apps, guid)
+		if err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errListApps)
+		}
+		if running > 0 {
+			cr.Status.SetConditions(org.SuspendedWithRunningApps(running))
+		}
+	}
+
+	o, err := c.client.Update(ctx, guid, org.GenerateUpdate(cr.Spec.ForProvider, cr.Status.AtProvider.ManagedMetadata, &cfresource.Metadata{Labels: cr.Status.AtProvider.Labels, Annotations: cr.Status.AtProvider.Annotations}))
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdate)
+	}
+
+	if !org.IsQuotaUpToDate(cr.Spec.ForProvider, o) {
+		if _, err := c.quota.Apply(ctx, *cr.Spec.ForProvider.Quota, []string{guid}); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errApplyQuota)
+		}
+	}
 
 	return managed.ExternalUpdate{
 		// Optionally return any details that may be required to connect to the
@@ -189,7 +246,24 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.Ext
 	if !ok {
 		return managed.ExternalDelete{}, errors.New(errNotOrgKind)
 	}
-	// Do nothing, as Org is observe-only
 	cr.SetConditions(xpv1.Deleting())
-	return managed.ExternalDelete{}, nil
+
+	// An adopted organization is left in place by management policies without Delete
+	guid := meta.GetExternalName(cr)
+	if !clients.IsValidGUID(guid) {
+		return managed.ExternalDelete{}, nil
+	}
+
+	// Delete is async, wait for the job to complete. An org that is already gone is deleted.
+	jobGUID, err := c.client.Delete(ctx, guid)
+	if err != nil {
+		return managed.ExternalDelete{}, errors.Wrap(clients.IgnoreNotFoundErr(err), errDelete)
+	}
+
+	// a job that takes longer leaves the org deleting, it is observed again on the next reconcile
+	err = job.PollWithBackoff(ctx, c.job, jobGUID, nil)
+	if errors.Is(err, job.ErrPollTimeout) {
+		return managed.ExternalDelete{}, nil
+	}
+	return managed.ExternalDelete{}, errors.Wrap(err, errDelete)
 }
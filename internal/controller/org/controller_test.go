@@ -4,10 +4,17 @@ import (
 	"context"
 	"testing"
 
+	cfresource "github.com/cloudfoundry/go-cfclient/v3/resource"
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpfake "github.com/crossplane/crossplane-runtime/v2/pkg/resource/fake"
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	k8s "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
@@ -17,12 +24,16 @@ import (
 
 	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/fake"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/org"
 )
 
 var (
 	errBoom = errors.New("boom")
 	name    = "my-org"
 	guid    = "2d8b0d04-d537-4e4e-8c6f-f09ca0e7f56f"
+
+	guidQuota      = "3d8b0d04-d537-4e4e-8c6f-f09ca0e7f56f"
+	guidOtherQuota = "4d8b0d04-d537-4e4e-8c6f-f09ca0e7f56f"
 )
 
 type modifier func(*v1alpha1.Organization)
@@ -39,6 +50,30 @@ func withName(name string) modifier {
 	}
 }
 
+func withQuota(quota string) modifier {
+	return func(r *v1alpha1.Organization) {
+		r.Spec.ForProvider.Quota = &quota
+	}
+}
+
+func withSuspended(suspended bool) modifier {
+	return func(r *v1alpha1.Organization) {
+		r.Spec.ForProvider.Suspended = &suspended
+	}
+}
+
+func withObservedSuspended(suspended bool) modifier {
+	return func(r *v1alpha1.Organization) {
+		r.Status.AtProvider.Suspended = &suspended
+	}
+}
+
+func withLabels(labels map[string]*string) modifier {
+	return func(r *v1alpha1.Organization) {
+		r.Spec.ForProvider.Labels = labels
+	}
+}
+
 func fakeOrg(m ...modifier) *v1alpha1.Organization {
 	r := &v1alpha1.Organization{
 		ObjectMeta: metav1.ObjectMeta{
@@ -190,6 +225,60 @@ func TestObserve(t *testing.T) {
 				return m
 			},
 		},
+		"UnmanagedQuota": {
+			args: args{
+				mg: fakeOrg(withExternalName(guid), withName(name)),
+			},
+			want: want{
+				mg:  fakeOrg(withExternalName(guid), withName(name)),
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				err: nil,
+			},
+			service: func() *fake.MockOrganization {
+				m := &fake.MockOrganization{}
+				m.On("Get", guid).Return(
+					&fake.NewOrganization().SetName(name).SetGUID(guid).SetQuota(guidQuota).Organization,
+					nil,
+				)
+				return m
+			},
+		},
+		"QuotaDrift": {
+			args: args{
+				mg: fakeOrg(withExternalName(guid), withName(name), withQuota(guidOtherQuota)),
+			},
+			want: want{
+				mg:  fakeOrg(withExternalName(guid), withName(name), withQuota(guidOtherQuota)),
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				err: nil,
+			},
+			service: func() *fake.MockOrganization {
+				m := &fake.MockOrganization{}
+				m.On("Get", guid).Return(
+					&fake.NewOrganization().SetName(name).SetGUID(guid).SetQuota(guidQuota).Organization,
+					nil,
+				)
+				return m
+			},
+		},
+		"LabelDrift": {
+			args: args{
+				mg: fakeOrg(withExternalName(guid), withName(name), withLabels(map[string]*string{"env": ptr.To("dev")})),
+			},
+			want: want{
+				mg:  fakeOrg(withExternalName(guid), withName(name), withLabels(map[string]*string{"env": ptr.To("dev")})),
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				err: nil,
+			},
+			service: func() *fake.MockOrganization {
+				m := &fake.MockOrganization{}
+				m.On("Get", guid).Return(
+					&fake.NewOrganization().SetName(name).SetGUID(guid).Organization,
+					nil,
+				)
+				return m
+			},
+		},
 		"Successful when org with guid is found, even after rename": {
 			args: args{
 				mg: fakeOrg(
@@ -300,6 +389,7 @@ func TestCreate(t *testing.T) {
 		args    args
 		want    want
 		service service
+		quota   func() *fake.MockOrgQuota
 		kube    k8s.Client
 	}{
 		"Successful": {
@@ -324,6 +414,29 @@ func TestCreate(t *testing.T) {
 				return m
 			},
 		},
+		"SuccessfulWithQuota": {
+			args: args{
+				mg: fakeOrg(withName(name), withQuota(guidQuota)),
+			},
+			want: want{
+				mg:  fakeOrg(withName(name), withQuota(guidQuota), withExternalName(guid)),
+				obs: managed.ExternalCreation{ConnectionDetails: managed.ConnectionDetails{}},
+				err: nil,
+			},
+			service: func() *fake.MockOrganization {
+				m := &fake.MockOrganization{}
+				m.On("Create").Return(
+					&fake.NewOrganization().SetName(name).SetGUID(guid).Organization,
+					nil,
+				)
+				return m
+			},
+			quota: func() *fake.MockOrgQuota {
+				m := &fake.MockOrgQuota{}
+				m.On("Apply", guidQuota, []string{guid}).Return(nil)
+				return m
+			},
+		},
 		"AlreadyExist": {
 			args: args{
 				mg: fakeOrg(withExternalName(guid)),
@@ -351,12 +464,17 @@ func TestCreate(t *testing.T) {
 	for n, tc := range cases {
 		t.Run(n, func(t *testing.T) {
 			t.Logf("Testing: %s", t.Name())
+			quota := &fake.MockOrgQuota{}
+			if tc.quota != nil {
+				quota = tc.quota()
+			}
 			c := &external{
 				kube: &test.MockClient{
 					MockUpdate:       test.NewMockUpdateFn(nil),
 					MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
 				},
 				client: tc.service(),
+				quota:  quota,
 			}
 
 			obs, err := c.Create(context.Background(), tc.args.mg)
@@ -377,6 +495,408 @@ func TestCreate(t *testing.T) {
 			if diff := cmp.Diff(tc.want.mg, tc.args.mg); diff != "" {
 				t.Errorf("Observe(...): -want, +got:\n%s", diff)
 			}
+			quota.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	type args struct {
+		mg resource.Managed
+	}
+
+	type want struct {
+		warning *xpv1.Condition
+		err     error
+	}
+
+	cases := map[string]struct {
+		args    args
+		want    want
+		service func() *fake.MockOrganization
+		quota   func() *fake.MockOrgQuota
+		apps    func() *fake.MockApp
+	}{
+		"RenameAndSuspend": {
+			args: args{
+				mg: fakeOrg(withExternalName(guid), withName("new-name"), withSuspended(true)),
+			},
+			want: want{err: nil},
+			service: func() *fake.MockOrganization {
+				m := &fake.MockOrganization{}
+				m.On("Update", guid, &cfresource.OrganizationUpdate{Name: "new-name", Suspended: ptr.To(true)}).Return(
+					&fake.NewOrganization().SetName("new-name").SetGUID(guid).SetSuspended(true).Organization,
+					nil,
+				)
+				return m
+			},
+			quota: func() *fake.MockOrgQuota {
+				// no expectations, the quota is up-to-date
+				return &fake.MockOrgQuota{}
+			},
+			apps: func() *fake.MockApp {
+				m := &fake.MockApp{}
+				m.On("ListAll").Return([]*cfresource.App{}, nil)
+				return m
+			},
+		},
+		"Suspend": {
+			args: args{
+				mg: fakeOrg(withExternalName(guid), withName(name), withSuspended(true), withObservedSuspended(false)),
+			},
+			want: want{err: nil},
+			service: func() *fake.MockOrganization {
+				m := &fake.MockOrganization{}
+				m.On("Update", guid, &cfresource.OrganizationUpdate{Name: name, Suspended: ptr.To(true)}).Return(
+					&fake.NewOrganization().SetName(name).SetGUID(guid).SetSuspended(true).Organization,
+					nil,
+				)
+				return m
+			},
+			quota: func() *fake.MockOrgQuota {
+				return &fake.MockOrgQuota{}
+			},
+			apps: func() *fake.MockApp {
+				m := &fake.MockApp{}
+				m.On("ListAll").Return([]*cfresource.App{&fake.NewApp("buildpack").SetState("STOPPED").App}, nil)
+				return m
+			},
+		},
+		"SuspendWithRunningApps": {
+			args: args{
+				mg: fakeOrg(withExternalName(guid), withName(name), withSuspended(true), withObservedSuspended(false)),
+			},
+			want: want{warning: ptr.To(org.SuspendedWithRunningApps(2)), err: nil},
+			service: func() *fake.MockOrganization {
+				m := &fake.MockOrganization{}
+				m.On("Update", guid, &cfresource.OrganizationUpdate{Name: name, Suspended: ptr.To(true)}).Return(
+					&fake.NewOrganization().SetName(name).SetGUID(guid).SetSuspended(true).Organization,
+					nil,
+				)
+				return m
+			},
+			quota: func() *fake.MockOrgQuota {
+				return &fake.MockOrgQuota{}
+			},
+			apps: func() *fake.MockApp {
+				m := &fake.MockApp{}
+				m.On("ListAll").Return([]*cfresource.App{
+					&fake.NewApp("buildpack").SetState("STARTED").App,
+					&fake.NewApp("buildpack").SetState("STOPPED").App,
+					&fake.NewApp("docker").SetState("STARTED").App,
+				}, nil)
+				return m
+			},
+		},
+		"SuspendListAppsError": {
+			args: args{
+				mg: fakeOrg(withExternalName(guid), withName(name), withSuspended(true), withObservedSuspended(false)),
+			},
+			want: want{err: errors.Wrap(errBoom, errListApps)},
+			service: func() *fake.MockOrganization {
+				// no expectations, the org is not updated
+				return &fake.MockOrganization{}
+			},
+			quota: func() *fake.MockOrgQuota {
+				return &fake.MockOrgQuota{}
+			},
+			apps: func() *fake.MockApp {
+				m := &fake.MockApp{}
+				m.On("ListAll").Return([]*cfresource.App(nil), errBoom)
+				return m
+			},
+		},
+		"ApplyQuota": {
+			args: args{
+				mg: fakeOrg(withExternalName(guid), withName(name), withQuota(guidOtherQuota)),
+			},
+			want: want{err: nil},
+			service: func() *fake.MockOrganization {
+				m := &fake.MockOrganization{}
+				m.On("Update", guid, &cfresource.OrganizationUpdate{Name: name, Suspended: ptr.To(false)}).Return(
+					&fake.NewOrganization().SetName(name).SetGUID(guid).SetQuota(guidQuota).Organization,
+					nil,
+				)
+				return m
+			},
+			quota: func() *fake.MockOrgQuota {
+				m := &fake.MockOrgQuota{}
+				m.On("Apply", guidOtherQuota, []string{guid}).Return(nil)
+				return m
+			},
+		},
+		"ApplyQuotaError": {
+			args: args{
+				mg: fakeOrg(withExternalName(guid), withName(name), withQuota(guidOtherQuota)),
+			},
+			want: want{err: errors.Wrap(errBoom, errApplyQuota)},
+			service: func() *fake.MockOrganization {
+				m := &fake.MockOrganization{}
+				m.On("Update", guid, mock.Anything).Return(
+					&fake.NewOrganization().SetName(name).SetGUID(guid).SetQuota(guidQuota).Organization,
+					nil,
+				)
+				return m
+			},
+			quota: func() *fake.MockOrgQuota {
+				m := &fake.MockOrgQuota{}
+				m.On("Apply", guidOtherQuota, []string{guid}).Return(errBoom)
+				return m
+			},
+		},
+		"NoExternalName": {
+			args: args{
+				mg: fakeOrg(withName(name)),
+			},
+			want: want{err: errors.New(errUpdate)},
+			service: func() *fake.MockOrganization {
+				return &fake.MockOrganization{}
+			},
+			quota: func() *fake.MockOrgQuota {
+				return &fake.MockOrgQuota{}
+			},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			service, quota, apps := tc.service(), tc.quota(), &fake.MockApp{}
+			if tc.apps != nil {
+				apps = tc.apps()
+			}
+			c := &external{client: service, quota: quota, apps: apps}
+
+			_, err := c.Update(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Update(...): -want error, +got error:\n%s", diff)
+			}
+			if tc.want.warning != nil {
+				cr, _ := tc.args.mg.(*v1alpha1.Organization)
+				if diff := cmp.Diff(*tc.want.warning, cr.GetCondition(org.TypeSuspensionWarning), test.EquateConditions()); diff != "" {
+					t.Errorf("Update(...): -want condition, +got condition:\n%s", diff)
+				}
+			}
+			service.AssertExpectations(t)
+			quota.AssertExpectations(t)
+			apps.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	type args struct {
+		mg resource.Managed
+	}
+
+	type want struct {
+		err error
+	}
+
+	cases := map[string]struct {
+		args    args
+		want    want
+		service func() *fake.MockOrganization
+		job     func() *fake.MockJob
+	}{
+		"Successful": {
+			args: args{mg: fakeOrg(withExternalName(guid), withName(name))},
+			want: want{err: nil},
+			service: func() *fake.MockOrganization {
+				m := &fake.MockOrganization{}
+				m.On("Delete", guid).Return("JOB", nil)
+				return m
+			},
+			job: func() *fake.MockJob {
+				m := &fake.MockJob{}
+				m.On("Get", "JOB").Return(&cfresource.Job{State: cfresource.JobStateComplete}, nil)
+				return m
+			},
+		},
+		"AlreadyDeleted": {
+			args: args{mg: fakeOrg(withExternalName(guid), withName(name))},
+			want: want{err: nil},
+			service: func() *fake.MockOrganization {
+				m := &fake.MockOrganization{}
+				m.On("Delete", guid).Return("", fake.ErrNoResultReturned)
+				return m
+			},
+			job: func() *fake.MockJob {
+				// no expectations, there is no job to poll
+				return &fake.MockJob{}
+			},
+		},
+		"JobFailed": {
+			args: args{mg: fakeOrg(withExternalName(guid), withName(name))},
+			want: want{err: errors.Wrap(errBoom, errDelete)},
+			service: func() *fake.MockOrganization {
+				m := &fake.MockOrganization{}
+				m.On("Delete", guid).Return("JOB", nil)
+				return m
+			},
+			job: func() *fake.MockJob {
+				m := &fake.MockJob{}
+				m.On("Get", "JOB").Return((*cfresource.Job)(nil), errBoom)
+				return m
+			},
+		},
+		"NeverCreated": {
+			args: args{mg: fakeOrg(withName(name))},
+			want: want{err: nil},
+			service: func() *fake.MockOrganization {
+				return &fake.MockOrganization{}
+			},
+			job: func() *fake.MockJob {
+				return &fake.MockJob{}
+			},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			service, j := tc.service(), tc.job()
+			c := &external{client: service, job: j}
+
+			_, err := c.Delete(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Delete(...): -want error, +got error:\n%s", diff)
+			}
+			service.AssertExpectations(t)
+			j.AssertExpectations(t)
+		})
+	}
+}
+
+func TestResolveReferences(t *testing.T) {
+	orgQuota := func(name, id string) v1alpha1.OrgQuota {
+		q := v1alpha1.OrgQuota{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+		if id != "" {
+			q.Status.AtProvider.ID = &id
+		}
+		return q
+	}
+	withQuotaRef := func(name string) modifier {
+		return func(r *v1alpha1.Organization) {
+			r.Spec.ForProvider.QuotaRef = &xpv1.NamespacedReference{Name: name}
+		}
+	}
+	withQuotaSelector := func(r *v1alpha1.Organization) {
+		r.Spec.ForProvider.QuotaSelector = &xpv1.NamespacedSelector{MatchLabels: map[string]string{"tier": "gold"}}
+	}
+
+	cases := map[string]struct {
+		mg   *v1alpha1.Organization
+		kube k8s.Client
+		want *string
+		err  string
+	}{
+		"Selector": {
+			mg: fakeOrg(withQuotaSelector),
+			kube: &test.MockClient{
+				MockList: func(_ context.Context, obj k8s.ObjectList, _ ...k8s.ListOption) error {
+					obj.(*v1alpha1.OrgQuotaList).Items = []v1alpha1.OrgQuota{orgQuota("gold", guidQuota)}
+					return nil
+				},
+			},
+			want: &guidQuota,
+		},
+		"Reference": {
+			mg: fakeOrg(withQuotaRef("gold")),
+			kube: &test.MockClient{
+				MockGet: func(_ context.Context, _ k8s.ObjectKey, obj k8s.Object) error {
+					*obj.(*v1alpha1.OrgQuota) = orgQuota("gold", guidQuota)
+					return nil
+				},
+			},
+			want: &guidQuota,
+		},
+		"ReferenceNotFound": {
+			mg: fakeOrg(withQuotaRef("gold")),
+			kube: &test.MockClient{
+				MockGet: test.NewMockGetFn(errBoom),
+			},
+			err: "mg.Spec.ForProvider.Quota: cannot get referenced resource: boom",
+		},
+		"ReferenceNotReady": {
+			mg: fakeOrg(withQuotaRef("gold")),
+			kube: &test.MockClient{
+				MockGet: func(_ context.Context, _ k8s.ObjectKey, obj k8s.Object) error {
+					*obj.(*v1alpha1.OrgQuota) = orgQuota("gold", "")
+					return nil
+				},
+			},
+			err: "mg.Spec.ForProvider.Quota: referenced field was empty (referenced resource may not yet be ready)",
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			err := tc.mg.ResolveReferences(context.Background(), tc.kube)
+			gotErr := ""
+			if err != nil {
+				gotErr = err.Error()
+			}
+			if diff := cmp.Diff(tc.err, gotErr); diff != "" {
+				t.Fatalf("ResolveReferences(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want, tc.mg.Spec.ForProvider.Quota); diff != "" {
+				t.Errorf("ResolveReferences(...): -want quota, +got:\n%s", diff)
+			}
 		})
 	}
 }
+
+func TestReconcileDeleteRemovesFinalizer(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	now := metav1.Now()
+	stored := fakeOrg(withExternalName(guid), withName(name))
+	stored.SetNamespace("default")
+	stored.SetDeletionTimestamp(&now)
+	stored.SetFinalizers([]string{managed.FinalizerName})
+
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, _ k8s.ObjectKey, obj k8s.Object) error {
+			stored.DeepCopyInto(obj.(*v1alpha1.Organization))
+			return nil
+		},
+		MockUpdate: func(_ context.Context, obj k8s.Object, _ ...k8s.UpdateOption) error {
+			obj.(*v1alpha1.Organization).DeepCopyInto(stored)
+			return nil
+		},
+		MockStatusUpdate: func(_ context.Context, obj k8s.Object, _ ...k8s.SubResourceUpdateOption) error {
+			stored.Status = obj.(*v1alpha1.Organization).Status
+			return nil
+		},
+	}
+
+	// the org exists until it is deleted
+	service := &fake.MockOrganization{}
+	service.On("Get", guid).Return(&fake.NewOrganization().SetName(name).SetGUID(guid).Organization, nil).Once()
+	service.On("Delete", guid).Return("JOB", nil)
+	service.On("Get", guid).Return(fake.OrganizationNil, fake.ErrNoResultReturned)
+	j := &fake.MockJob{}
+	j.On("Get", "JOB").Return(&cfresource.Job{State: cfresource.JobStateComplete}, nil)
+
+	r := managed.NewReconciler(&xpfake.Manager{Client: kube, Scheme: scheme}, resource.ManagedKind(v1alpha1.Org_GroupVersionKind),
+		managed.WithInitializers(),
+		managed.WithReferenceResolver(managed.ReferenceResolverFn(func(context.Context, resource.Managed) error { return nil })),
+		managed.WithExternalConnector(managed.ExternalConnectorFn(func(context.Context, resource.Managed) (managed.ExternalClient, error) {
+			return &external{client: service, job: j, kube: kube}, nil
+		})),
+	)
+
+	// the first reconcile deletes the org, the second observes it gone
+	for range 2 {
+		if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: name}}); err != nil {
+			t.Fatalf("Reconcile(...): %v", err)
+		}
+	}
+	if got := stored.GetFinalizers(); len(got) != 0 {
+		t.Errorf("Reconcile(...): want no finalizers, got %v", got)
+	}
+	service.AssertExpectations(t)
+	j.AssertExpectations(t)
+}
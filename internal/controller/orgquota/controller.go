@@ -6,6 +6,7 @@ import (
 	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/feature"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
@@ -17,6 +18,7 @@ import (
 	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
 	apisv1beta1 "github.com/SAP/crossplane-provider-cloudfoundry/apis/v1beta1"
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/org"
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/orgquota"
 )
 
@@ -32,6 +34,9 @@ const (
 	errUpdate            = "cannot update cloudfoundry OrgQuota"
 	errDelete            = "cannot delete cloudfoundry OrgQuota"
 	errIDNotSet          = ".Status.AtProvider.ID is not set"
+	errApplyOrgs         = "cannot apply cloudfoundry OrgQuota to orgs"
+	errDetachOrgs        = "cannot detach cloudfoundry OrgQuota from orgs"
+	errResolveOrgs       = "cannot resolve orgs of cloudfoundry OrgQuota"
 )
 
 // externalConnecter specifies how the Reconciler should connect to
@@ -69,13 +74,22 @@ func Setup(mgr ctrl.Manager, controllerOptions controller.Options) error {
 	name := managed.ControllerName(v1alpha1.OrgQuota_GroupKind)
 
 	options := []managed.ReconcilerOption{
-		managed.WithExternalConnecter(&externalConnecter{
+		managed.WithExternalConnecter(clients.WithPause(clients.WithErrorClassification(clients.WithOperationTimeout(mgr.GetClient(), &externalConnecter{
 			kubeClient:   mgr.GetClient(),
 			usageTracker: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1beta1.ProviderConfigUsage{}),
-		}),
+		}, clients.DefaultOperationTimeout)))),
 		managed.WithLogger(controllerOptions.Logger.WithValues("controller", name)),
+		managed.WithTimeout(clients.MaxOperationTimeout),
+		managed.WithPollIntervalHook(clients.PollIntervalHook(mgr.GetClient())),
 		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 		managed.WithPollInterval(controllerOptions.PollInterval),
+		managed.WithInitializers(&orgInitializer{
+			kube: mgr.GetClient(),
+		}),
+	}
+
+	if controllerOptions.Features.Enabled(feature.EnableBetaManagementPolicies) {
+		options = append(options, managed.WithManagementPolicies())
 	}
 
 	r := managed.NewReconciler(mgr,
@@ -124,7 +138,7 @@ func (e *externalClient) Observe(ctx context.Context, res resource.Managed) (man
 
 	// not found or error
 	if err != nil {
-		if clients.ErrorIsNotFound(err) {
+		if clients.IsNotFound(err) {
 			return managed.ExternalObservation{ResourceExists: false}, nil
 		}
 		return managed.ExternalObservation{}, errors.Wrap(err, errGet)
@@ -184,11 +198,16 @@ func (e *externalClient) Update(ctx context.Context, res resource.Managed) (mana
 		return managed.ExternalUpdate{}, errors.New(errUpdate)
 	}
 
-	_, err := e.cloudFoundryClient.Update(ctx, *managedOrgQuota.Status.AtProvider.ID, orgquota.GenerateCreateOrUpdate(managedOrgQuota.Spec.ForProvider))
+	_, err := e.cloudFoundryClient.Update(ctx, *managedOrgQuota.Status.AtProvider.ID, orgquota.GenerateUpdate(managedOrgQuota.Spec.ForProvider))
 	if err != nil {
 		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdate)
 	}
 
+	if err := orgquota.ReconcileOrgs(ctx, e.cloudFoundryClient, *managedOrgQuota.Status.AtProvider.ID,
+		managedOrgQuota.Spec.ForProvider.Orgs, managedOrgQuota.Status.AtProvider.Orgs); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errApplyOrgs)
+	}
+
 	return managed.ExternalUpdate{}, nil
 }
 
@@ -206,6 +225,11 @@ func (e *externalClient) Delete(ctx context.Context, res resource.Managed) (mana
 		return managed.ExternalDelete{}, errors.Wrap(errors.New(errIDNotSet), errDelete)
 	}
 
+	// an org quota that is applied to orgs cannot be deleted
+	if err := orgquota.DetachOrgs(ctx, e.cloudFoundryClient, managedOrgQuota.Status.AtProvider.Orgs); err != nil {
+		return managed.ExternalDelete{}, errors.Wrap(err, errDetachOrgs)
+	}
+
 	_, err := e.cloudFoundryClient.Delete(ctx, *managedOrgQuota.Status.AtProvider.ID)
 	if err != nil {
 		return managed.ExternalDelete{}, errors.Wrap(err, errDelete)
@@ -213,3 +237,44 @@ func (e *externalClient) Delete(ctx context.Context, res resource.Managed) (mana
 
 	return managed.ExternalDelete{}, nil
 }
+
+// orgInitializer resolves the orgs of an OrgQuota that are referenced by name.
+type orgInitializer struct {
+	kube k8s.Client
+}
+
+// Initialize implements the managed.Initializer interface
+func (c *orgInitializer) Initialize(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.OrgQuota)
+	if !ok {
+		return errors.New(errNotOrgQuota)
+	}
+
+	var orgClient org.Client
+	for i := range cr.Spec.ForProvider.Orgs {
+		ref := &cr.Spec.ForProvider.Orgs[i]
+		if ref.OrgRef != nil || ref.OrgSelector != nil {
+			// resolved by the reference resolver
+			continue
+		}
+		if ref.Org != nil || ref.OrgName == nil {
+			if err := clients.ValidateGUID("org", ref.Org); err != nil {
+				return err
+			}
+			continue
+		}
+		if orgClient == nil {
+			cf, err := clients.ClientFnBuilder(ctx, c.kube)(mg)
+			if err != nil {
+				return errors.Wrap(err, errNewClient)
+			}
+			orgClient = org.NewClient(cf)
+		}
+		guid, err := org.GetGUID(ctx, orgClient, *ref.OrgName)
+		if err != nil {
+			return errors.Wrap(err, errResolveOrgs)
+		}
+		ref.Org = guid
+	}
+	return nil
+}
@@ -22,10 +22,13 @@ import (
 )
 
 var (
-	guid        = "33fd5b0b-4f3b-4b1b-8b3d-3b5f7b4b3b4b"
-	name        = "test-org-quota"
-	errBoom     = errors.New("boom")
-	nilOrgQuota *cfresource.OrganizationQuota
+	guid         = "33fd5b0b-4f3b-4b1b-8b3d-3b5f7b4b3b4b"
+	name         = "test-org-quota"
+	errBoom      = errors.New("boom")
+	nilOrgQuota  *cfresource.OrganizationQuota
+	orgGUID      = "1d8b0d04-d537-4e4e-8c6f-f09ca0e7f56f"
+	otherOrgGUID = "2d8b0d04-d537-4e4e-8c6f-f09ca0e7f56f"
+	defaultGUID  = "44fd5b0b-4f3b-4b1b-8b3d-3b5f7b4b3b4b"
 )
 
 type modifier func(*v1alpha1.OrgQuota)
@@ -58,6 +61,28 @@ func withNilID() modifier {
 	}
 }
 
+func withOrgs(orgs ...string) modifier {
+	return func(r *v1alpha1.OrgQuota) {
+		for _, o := range orgs {
+			r.Spec.ForProvider.Orgs = append(r.Spec.ForProvider.Orgs, v1alpha1.OrgReference{Org: ptr.To(o)})
+		}
+	}
+}
+
+func withUnresolvedOrg(name string) modifier {
+	return func(r *v1alpha1.OrgQuota) {
+		r.Spec.ForProvider.Orgs = append(r.Spec.ForProvider.Orgs, v1alpha1.OrgReference{OrgName: ptr.To(name)})
+	}
+}
+
+func withObservedOrgs(orgs ...string) modifier {
+	return func(r *v1alpha1.OrgQuota) {
+		for _, o := range orgs {
+			r.Status.AtProvider.Orgs = append(r.Status.AtProvider.Orgs, ptr.To(o))
+		}
+	}
+}
+
 func fakeOrgQuota(m ...modifier) *v1alpha1.OrgQuota {
 	r := &v1alpha1.OrgQuota{
 		ObjectMeta: metav1.ObjectMeta{
@@ -150,14 +175,14 @@ func TestObserve(t *testing.T) {
 				obs: managed.ExternalObservation{
 					ResourceExists: false,
 				},
-				err: errors.Wrap(errors.New("not found"), errGet),
+				err: nil,
 			},
 			service: func() *fake.MockOrgQuota {
 				m := &fake.MockOrgQuota{}
 
 				m.On("Get", name).Return(
 					nilOrgQuota,
-					errors.New("not found"),
+					fake.ErrResourceNotFound,
 				)
 				return m
 			},
@@ -171,6 +196,24 @@ func TestObserve(t *testing.T) {
 				obs: managed.ExternalObservation{
 					ResourceExists: false,
 				},
+				err: nil,
+			},
+			service: func() *fake.MockOrgQuota {
+				m := &fake.MockOrgQuota{}
+				m.On("Get", guid).Return(
+					nilOrgQuota,
+					fake.ErrResourceNotFound,
+				)
+				return m
+			},
+		},
+		"Error when Get returns a generic error": {
+			args: args{
+				mg: fakeOrgQuota(withExternalName(guid)),
+			},
+			want: want{
+				mg:  fakeOrgQuota(withExternalName(guid)),
+				obs: managed.ExternalObservation{},
 				err: errors.Wrap(errors.New("not found"), errGet),
 			},
 			service: func() *fake.MockOrgQuota {
@@ -224,7 +267,7 @@ func TestObserve(t *testing.T) {
 				),
 				obs: managed.ExternalObservation{
 					ResourceExists:          true,
-					ResourceUpToDate:        false,
+					ResourceUpToDate:        true,
 					ResourceLateInitialized: true,
 				},
 				err: nil,
@@ -238,6 +281,31 @@ func TestObserve(t *testing.T) {
 				return m
 			},
 		},
+		"Adopted quota with limits is late-initialized and up to date": {
+			args: args{
+				mg: fakeOrgQuota(withExternalName(guid)),
+			},
+			want: want{
+				mg: fakeOrgQuota(withExternalName(guid)),
+				obs: managed.ExternalObservation{
+					ResourceExists:          true,
+					ResourceUpToDate:        true,
+					ResourceLateInitialized: true,
+				},
+				err: nil,
+			},
+			service: func() *fake.MockOrgQuota {
+				m := &fake.MockOrgQuota{}
+				r := fakeOrgQuotaResource(guid, false)
+				r.Apps.TotalMemoryInMB = ptr.To(10240)
+				r.Apps.PerProcessMemoryInMB = ptr.To(1024)
+				r.Routes.TotalRoutes = ptr.To(100)
+				r.Services.TotalServiceInstances = ptr.To(20)
+				r.Relationships.Organizations.Data = []cfresource.Relationship{{GUID: "org-guid"}}
+				m.On("Get", guid).Return(r, nil)
+				return m
+			},
+		},
 	}
 
 	for n, tc := range cases {
@@ -409,6 +477,91 @@ func TestUpdate(t *testing.T) {
 				return m
 			},
 		},
+		"ApplyOrgs": {
+			args: args{
+				mg: fakeOrgQuota(
+					withExternalName(guid),
+					withName("test-quota"),
+					withOrgs(orgGUID),
+				),
+			},
+			want: want{
+				mg: fakeOrgQuota(
+					withExternalName(guid),
+					withName("test-quota"),
+					withOrgs(orgGUID),
+				),
+				obs: managed.ExternalUpdate{},
+				err: nil,
+			},
+			service: func() *fake.MockOrgQuota {
+				m := &fake.MockOrgQuota{}
+				m.On("Update").Return(
+					fakeOrgQuotaResource(guid, true),
+					nil,
+				)
+				m.On("Apply", guid, []string{orgGUID}).Return(nil)
+				return m
+			},
+		},
+		"DetachRemovedOrgs": {
+			args: args{
+				mg: fakeOrgQuota(
+					withExternalName(guid),
+					withName("test-quota"),
+					withOrgs(otherOrgGUID),
+					withObservedOrgs(orgGUID, otherOrgGUID),
+				),
+			},
+			want: want{
+				mg: fakeOrgQuota(
+					withExternalName(guid),
+					withName("test-quota"),
+					withOrgs(otherOrgGUID),
+					withObservedOrgs(orgGUID, otherOrgGUID),
+				),
+				obs: managed.ExternalUpdate{},
+				err: nil,
+			},
+			service: func() *fake.MockOrgQuota {
+				m := &fake.MockOrgQuota{}
+				m.On("Update").Return(
+					fakeOrgQuotaResource(guid, true),
+					nil,
+				)
+				m.On("Single", []string{"default"}).Return(fakeOrgQuotaResource(defaultGUID, false), nil)
+				m.On("Apply", defaultGUID, []string{orgGUID}).Return(nil)
+				return m
+			},
+		},
+		"KeepOrgsWhileUnresolved": {
+			args: args{
+				mg: fakeOrgQuota(
+					withExternalName(guid),
+					withName("test-quota"),
+					withUnresolvedOrg("other-org"),
+					withObservedOrgs(orgGUID),
+				),
+			},
+			want: want{
+				mg: fakeOrgQuota(
+					withExternalName(guid),
+					withName("test-quota"),
+					withUnresolvedOrg("other-org"),
+					withObservedOrgs(orgGUID),
+				),
+				obs: managed.ExternalUpdate{},
+				err: nil,
+			},
+			service: func() *fake.MockOrgQuota {
+				m := &fake.MockOrgQuota{}
+				m.On("Update").Return(
+					fakeOrgQuotaResource(guid, true),
+					nil,
+				)
+				return m
+			},
+		},
 		"Failed": {
 			args: args{
 				mg: fakeOrgQuota(
@@ -452,27 +605,25 @@ func TestUpdate(t *testing.T) {
 				err: errors.New(errUpdate),
 			},
 			service: func() *fake.MockOrgQuota {
-				m := &fake.MockOrgQuota{}
-				m.On("Update").Return(
-					"",
-					errBoom,
-				)
-				return m
+				// no Update call expected
+				return &fake.MockOrgQuota{}
 			},
 		},
 	}
 
 	for n, tc := range cases {
 		t.Run(n, func(t *testing.T) {
+			m := tc.service()
 			c := &externalClient{
 				kubeClient: &test.MockClient{
 					MockUpdate:       test.NewMockUpdateFn(nil),
 					MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
 				},
-				cloudFoundryClient: tc.service(),
+				cloudFoundryClient: m,
 			}
 
 			obs, err := c.Update(context.Background(), tc.args.mg)
+			m.AssertExpectations(t)
 
 			if tc.want.err != nil && err != nil {
 				// the case where our mock server returns error.
@@ -532,6 +683,34 @@ func TestDelete(t *testing.T) {
 				return m
 			},
 		},
+		"DetachOrgs": {
+			args: args{
+				mg: fakeOrgQuota(
+					withExternalName(guid),
+					withName("test-quota"),
+					withObservedOrgs(orgGUID),
+				),
+			},
+			want: want{
+				mg: fakeOrgQuota(
+					withExternalName(guid),
+					withName("test-quota"),
+					withObservedOrgs(orgGUID),
+					withConditions(xpv1.Deleting()),
+				),
+				err: nil,
+			},
+			service: func() *fake.MockOrgQuota {
+				m := &fake.MockOrgQuota{}
+				m.On("Single", []string{"default"}).Return(fakeOrgQuotaResource(defaultGUID, false), nil)
+				m.On("Apply", defaultGUID, []string{orgGUID}).Return(nil)
+				m.On("Delete").Return(
+					"",
+					nil,
+				)
+				return m
+			},
+		},
 		"Failed": {
 			args: args{
 				mg: fakeOrgQuota(
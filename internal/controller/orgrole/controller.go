@@ -12,6 +12,7 @@ import (
 	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/feature"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
@@ -33,6 +34,8 @@ const (
 	errGet               = "cannot get organization role according to the specified parameters"
 	errGetResource       = "cannot get organization role via the cloudfoundry API"
 	errCreate            = "cannot create organization role"
+	errUpdate            = "cannot update organization role assignments"
+	errOrigin            = "cannot resolve the origin of the user"
 	errDelete            = "cannot delete organization role"
 )
 
@@ -41,16 +44,22 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 	name := managed.ControllerName(v1alpha1.OrgRole_GroupKind)
 
 	options := []managed.ReconcilerOption{
-		managed.WithExternalConnecter(&connector{kube: mgr.GetClient(),
+		managed.WithExternalConnecter(clients.WithPause(clients.WithErrorClassification(clients.WithOperationTimeout(mgr.GetClient(), &connector{kube: mgr.GetClient(),
 			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &pcv1beta1.ProviderConfigUsage{}),
-		}),
+		}, clients.DefaultOperationTimeout)))),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithTimeout(clients.MaxOperationTimeout),
+		managed.WithPollIntervalHook(clients.PollIntervalHook(mgr.GetClient())),
 		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 		managed.WithInitializers(&orgInitializer{
 			kube: mgr.GetClient(),
 		}),
 	}
 
+	if o.Features.Enabled(feature.EnableBetaManagementPolicies) {
+		options = append(options, managed.WithManagementPolicies())
+	}
+
 	r := managed.NewReconciler(mgr,
 		resource.ManagedKind(v1alpha1.OrgRole_GroupVersionKind),
 		options...)
@@ -86,9 +95,9 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if err != nil {
 		return nil, errors.Wrap(err, errGetClient)
 	}
-	role, job := role.NewClient(cf)
+	r, job := role.NewClient(cf)
 
-	return &external{role: role, kube: c.kube, job: job}, nil
+	return &external{role: role.SharedCache().Wrap(r, clients.ProviderConfigName(mg).String()), users: role.NewUserClient(cf), kube: c.kube, job: job}, nil
 }
 
 // Disconnect implements the managed.ExternalClient interface
@@ -99,9 +108,10 @@ func (c *external) Disconnect(ctx context.Context) error {
 
 // An external is a managed.ExternalConnecter that is using the CloudFoundry API to observe and modify resources.
 type external struct {
-	role role.Role
-	job  job.Job
-	kube k8s.Client
+	role  role.Role
+	users role.Users
+	job   job.Job
+	kube  k8s.Client
 }
 
 // Observe managed resource OrgRole
@@ -111,12 +121,22 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, errors.New(errWrongKind)
 	}
 
-	// Fetch the role object using the CloudFoundry API by guid or according to the specified parameters
+	if len(cr.Spec.ForProvider.Usernames) > 0 {
+		return c.observeAssignments(ctx, cr)
+	}
+
+	// Resolve the origin before looking up the role, so that a role of the user in another origin is not adopted
 	guid := meta.GetExternalName(cr)
+	originLateInitialized, err := c.resolveOrigin(ctx, cr, !clients.IsValidGUID(guid))
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errOrigin)
+	}
+
+	// Fetch the role object using the CloudFoundry API by guid or according to the specified parameters
 	r, err := role.GetOrgRole(ctx, c.role, guid, cr.Spec.ForProvider)
 
 	if err != nil {
-		if clients.ErrorIsNotFound(err) {
+		if clients.IsNotFound(err) {
 			return managed.ExternalObservation{ResourceExists: false}, nil
 		}
 		return managed.ExternalObservation{}, errors.Wrap(err, errGet)
@@ -125,7 +145,7 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{ResourceExists: false}, nil
 	}
 
-	resourceLateInitialized := false
+	resourceLateInitialized := originLateInitialized
 	if guid != r.GUID {
 		meta.SetExternalName(cr, r.GUID)
 		resourceLateInitialized = true
@@ -141,6 +161,44 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	}, nil
 }
 
+// resolveOrigin late-initializes the origin of the user if it is unset and, if validate is set, checks that the
+// specified origin matches the origin the user is known with.
+func (c *external) resolveOrigin(ctx context.Context, cr *v1alpha1.OrgRole, validate bool) (bool, error) {
+	spec := &cr.Spec.ForProvider
+	if spec.Username == "" || (spec.Origin != nil && !validate) {
+		return false, nil
+	}
+
+	origin, err := role.ResolveOrigin(ctx, c.users, spec.Username, spec.Origin)
+	if err != nil || spec.Origin != nil {
+		return false, err
+	}
+	spec.Origin = &origin
+	return true, nil
+}
+
+// observeAssignments observes the roles of a OrgRole that lists multiple usernames
+func (c *external) observeAssignments(ctx context.Context, cr *v1alpha1.OrgRole) (managed.ExternalObservation, error) {
+	assigned, err := role.ObserveOrgRoleAssignments(ctx, c.role, c.users, cr.Spec.ForProvider, cr.Status.AtProvider.AssignedRoles, cr.Status.AtProvider.ID)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGet)
+	}
+
+	cr.Status.AtProvider = v1alpha1.OrgRoleObservation{
+		Type:            ptr.To(role.OrgRoleType(cr.Spec.ForProvider.Type).String()),
+		RoleAssignments: v1alpha1.RoleAssignments{AssignedRoles: assigned},
+	}
+	if len(assigned) == 0 {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	cr.Status.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: role.AssignmentsUpToDate(cr.Spec.ForProvider.Usernames, assigned),
+	}, nil
+}
+
 // Create a managed resource OrgRole
 func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
 	cr, ok := mg.(*v1alpha1.OrgRole)
@@ -149,11 +207,17 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	}
 
 	spec := cr.Spec.ForProvider
+	if len(spec.Usernames) > 0 {
+		assigned, err := role.AssignOrgRoles(ctx, c.role, c.users, c.job, spec, cr.Status.AtProvider.AssignedRoles)
+		cr.Status.AtProvider.AssignedRoles = assigned
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreate)
+	}
+
 	if spec.Org == nil || spec.Username == "" || spec.Type == "" {
 		return managed.ExternalCreation{}, errors.New(errCreate)
 	}
 
-	o, err := c.role.CreateOrganizationRoleWithUsername(ctx, *spec.Org, spec.Username, role.OrgRoleType(spec.Type), ptr.Deref(spec.Origin, "sap.ids"))
+	o, err := c.role.CreateOrganizationRoleWithUsername(ctx, *spec.Org, spec.Username, role.OrgRoleType(spec.Type), ptr.Deref(spec.Origin, role.DefaultOrigin))
 	if err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, errCreate)
 	}
@@ -169,12 +233,18 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 
 // Update managed resource OrgRole
 func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
-	_, ok := mg.(*v1alpha1.OrgRole)
+	cr, ok := mg.(*v1alpha1.OrgRole)
 	if !ok {
 		return managed.ExternalUpdate{}, errors.New(errWrongKind)
 	}
 
-	// Do nothing, as OrgRole is observe-only
+	if len(cr.Spec.ForProvider.Usernames) > 0 {
+		assigned, err := role.AssignOrgRoles(ctx, c.role, c.users, c.job, cr.Spec.ForProvider, cr.Status.AtProvider.AssignedRoles)
+		cr.Status.AtProvider.AssignedRoles = assigned
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdate)
+	}
+
+	// Do nothing, as a OrgRole of a single user cannot be updated
 
 	return managed.ExternalUpdate{
 		// Optionally return any details that may be required to connect to the
@@ -192,6 +262,11 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.Ext
 	// TODO
 
 	cr.SetConditions(xpv1.Deleting())
+	if len(cr.Status.AtProvider.AssignedRoles) > 0 {
+		assigned, err := role.UnassignRoles(ctx, c.role, c.job, cr.Status.AtProvider.AssignedRoles)
+		cr.Status.AtProvider.AssignedRoles = assigned
+		return managed.ExternalDelete{}, errors.Wrap(err, errDelete)
+	}
 	if cr.Status.AtProvider.ID == nil {
 		return managed.ExternalDelete{}, nil
 	}
@@ -227,5 +302,5 @@ func (c *orgInitializer) Initialize(ctx context.Context, mg resource.Managed) er
 		return org.ResolveByName(ctx, clients.ClientFnBuilder(ctx, c.kube), mg)
 	}
 
-	return nil
+	return clients.ValidateGUID("org", cr.Spec.ForProvider.Org)
 }
@@ -2,6 +2,7 @@ package orgrole
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	cfresource "github.com/cloudfoundry/go-cfclient/v3/resource"
@@ -71,6 +72,18 @@ func withUsername(username string) modifier {
 	}
 }
 
+func withUsernames(usernames ...string) modifier {
+	return func(r *v1alpha1.OrgRole) {
+		r.Spec.ForProvider.Usernames = usernames
+	}
+}
+
+func withAssignedRoles(assigned map[string]string) modifier {
+	return func(r *v1alpha1.OrgRole) {
+		r.Status.AtProvider.AssignedRoles = assigned
+	}
+}
+
 func withOrg(org string) modifier {
 	return func(r *v1alpha1.OrgRole) {
 		r.Spec.ForProvider.Org = &org
@@ -117,6 +130,118 @@ func fakeOrgRole(m ...modifier) *v1alpha1.OrgRole {
 	return r
 }
 
+func newMockUsers(users ...*cfresource.User) *fake.MockUser {
+	m := &fake.MockUser{}
+	m.On("ListAll").Return(users, nil)
+	return m
+}
+
+func TestObserveOrigin(t *testing.T) {
+	otherOriginUser := &cfresource.User{
+		Username: ptr.To("user1"),
+		Origin:   ptr.To("uaa"),
+		Resource: cfresource.Resource{GUID: guidNoRefUser}}
+
+	cases := map[string]struct {
+		mg         *v1alpha1.OrgRole
+		users      []*cfresource.User
+		wantOrigin *string
+		wantErr    error
+	}{
+		"LateInitializesOrigin": {
+			mg:         fakeOrgRole(withOrg(guidOrg), withUsername("user1"), withType(v1alpha1.OrgManager)),
+			users:      []*cfresource.User{healthyUser},
+			wantOrigin: ptr.To("sap.ids"),
+		},
+		"AcceptsMatchingOrigin": {
+			mg:         fakeOrgRole(withOrg(guidOrg), withUsername("user1"), withType(v1alpha1.OrgManager), withOrigin("sap.ids")),
+			users:      []*cfresource.User{healthyUser, otherOriginUser},
+			wantOrigin: ptr.To("sap.ids"),
+		},
+		"RejectsMismatchingOrigin": {
+			mg:         fakeOrgRole(withOrg(guidOrg), withUsername("user1"), withType(v1alpha1.OrgManager), withOrigin("my-origin")),
+			users:      []*cfresource.User{healthyUser},
+			wantOrigin: ptr.To("my-origin"),
+			wantErr:    errors.Wrap(errors.New(`user "user1" does not exist in origin "my-origin", the user exists in origin(s) sap.ids`), errOrigin),
+		},
+		"RejectsAmbiguousOrigin": {
+			mg:      fakeOrgRole(withOrg(guidOrg), withUsername("user1"), withType(v1alpha1.OrgManager)),
+			users:   []*cfresource.User{healthyUser, otherOriginUser},
+			wantErr: errors.Wrap(errors.New(`user "user1" exists in multiple origins sap.ids, uaa, origin must be specified`), errOrigin),
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			m := &fake.MockOrgRole{}
+			m.On("ListIncludeUsersAll").Return([]*cfresource.Role{healthyRole}, []*cfresource.User{healthyUser}, nil)
+			c := &external{role: m, users: newMockUsers(tc.users...)}
+
+			obs, err := c.Observe(context.Background(), tc.mg)
+			if tc.wantErr != nil {
+				if err == nil || err.Error() != tc.wantErr.Error() {
+					t.Fatalf("Observe(...): want error %v, got %v", tc.wantErr, err)
+				}
+				m.AssertNotCalled(t, "ListIncludeUsersAll")
+			} else if err != nil {
+				t.Fatalf("Observe(...): unexpected error: %v", err)
+			} else if !obs.ResourceLateInitialized {
+				t.Errorf("Observe(...): want resource to be late-initialized")
+			}
+			if diff := cmp.Diff(tc.wantOrigin, tc.mg.Spec.ForProvider.Origin); diff != "" {
+				t.Errorf("Observe(...): -want origin, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestObserveUsernames(t *testing.T) {
+	guidUaaUser := "3d3b0d04-d537-4e4e-8c6f-f09ca0e7f33f"
+	guidLegacyRole := "9e4b0d04-d537-6a6a-8c6f-f09ca0e7f69e"
+	uaaUser := &cfresource.User{
+		Username: ptr.To("user2"),
+		Origin:   ptr.To("uaa"),
+		Resource: cfresource.Resource{GUID: guidUaaUser}}
+	uaaRole := &cfresource.Role{
+		Resource: cfresource.Resource{GUID: guidLegacyRole},
+		Type:     "organization_manager",
+		Relationships: cfresource.RoleSpaceUserOrganizationRelationships{
+			Org:  cfresource.ToOneRelationship{Data: &cfresource.Relationship{GUID: guidOrg}},
+			User: cfresource.ToOneRelationship{Data: &cfresource.Relationship{GUID: guidUaaUser}}}}
+
+	cases := map[string]struct {
+		mg           *v1alpha1.OrgRole
+		wantAssigned map[string]string
+	}{
+		"ResolvesOriginPerUsername": {
+			mg:           fakeOrgRole(withOrg(guidOrg), withUsernames("user1", "user2"), withType(v1alpha1.OrgManager)),
+			wantAssigned: map[string]string{"user1": guidRole, "user2": guidLegacyRole},
+		},
+		"AdoptsLegacyRole": {
+			mg: fakeOrgRole(withOrg(guidOrg), withUsernames("user1"), withType(v1alpha1.OrgManager),
+				func(r *v1alpha1.OrgRole) { r.Status.AtProvider.ID = ptr.To(guidLegacyRole) }),
+			wantAssigned: map[string]string{"user1": guidRole, "user2": guidLegacyRole},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			m := &fake.MockOrgRole{}
+			m.On("ListIncludeUsersAll").Return([]*cfresource.Role{healthyRole, uaaRole}, []*cfresource.User{healthyUser, uaaUser}, nil)
+			u := &fake.MockUser{}
+			u.On("ListAll").Return([]*cfresource.User{healthyUser, uaaUser}, nil)
+			c := &external{role: m, users: u}
+
+			if _, err := c.Observe(context.Background(), tc.mg); err != nil {
+				t.Fatalf("Observe(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantAssigned, tc.mg.Status.AtProvider.AssignedRoles); diff != "" {
+				t.Errorf("Observe(...): -want assigned roles, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestObserve(t *testing.T) {
 	type service func() *fake.MockOrgRole
 	type args struct {
@@ -248,6 +373,28 @@ func TestObserve(t *testing.T) {
 				return m
 			},
 		},
+		"SuccessfulUsernamesPartiallyAssigned": {
+			args: args{
+				mg: fakeOrgRole(
+					withOrg(guidOrg),
+					withUsernames("user1", "user2"),
+					withType(v1alpha1.OrgManager)),
+			},
+			want: want{
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				err: nil,
+			},
+			service: func() *fake.MockOrgRole {
+				m := &fake.MockOrgRole{}
+
+				m.On("ListIncludeUsersAll").Return(
+					[]*cfresource.Role{healthyRole},
+					[]*cfresource.User{healthyUser},
+					nil,
+				)
+				return m
+			},
+		},
 		"SuccessfulWithUUID": {
 			args: args{
 				mg: fakeOrgRole(
@@ -281,8 +428,9 @@ func TestObserve(t *testing.T) {
 				kube: &test.MockClient{
 					MockUpdate: test.NewMockUpdateFn(nil),
 				},
-				job:  nil,
-				role: tc.service(),
+				job:   nil,
+				role:  tc.service(),
+				users: newMockUsers(healthyUser),
 			}
 			obs, err := c.Observe(context.Background(), tc.args.mg)
 
@@ -335,7 +483,7 @@ func TestCreate(t *testing.T) {
 					withType(v1alpha1.OrgManager),
 					withUsername("user1@test.com"),
 					withOrg("my-org"),
-					withOrigin("my-origin"),
+					withOrigin("sap.ids"),
 					withExternalName(guidOrg),
 				),
 				obs: managed.ExternalCreation{ConnectionDetails: managed.ConnectionDetails{}},
@@ -367,7 +515,7 @@ func TestCreate(t *testing.T) {
 					withUsername("user1@test.com"),
 					withOrgName("my-org"),
 					withOrg("my-org"),
-					withOrigin("my-origin"),
+					withOrigin("sap.ids"),
 					withExternalName(guidOrg),
 				),
 				obs: managed.ExternalCreation{ConnectionDetails: managed.ConnectionDetails{}},
@@ -527,6 +675,74 @@ func TestCreate(t *testing.T) {
 			if diff := cmp.Diff(tc.want.obs, obs); diff != "" {
 				t.Errorf("Observe(...): -want, +got:\n%s", diff)
 			}
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg); diff != "" {
+				t.Errorf("Create(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdateUsernames(t *testing.T) {
+	guidRemovedRole := "9e4b0d04-d537-6a6a-8c6f-f09ca0e7f69c"
+	guidNewRole := "9e4b0d04-d537-6a6a-8c6f-f09ca0e7f69d"
+
+	cases := map[string]struct {
+		mg           *v1alpha1.OrgRole
+		service      func() *fake.MockOrgRole
+		wantAssigned map[string]string
+		wantErr      bool
+	}{
+		"CreatesMissingAndDeletesRemoved": {
+			mg: fakeOrgRole(
+				withOrg(guidOrg),
+				withUsernames("user1", "user2"),
+				withType(v1alpha1.OrgManager),
+				withAssignedRoles(map[string]string{"user1": guidRole, "user3": guidRemovedRole})),
+			service: func() *fake.MockOrgRole {
+				m := &fake.MockOrgRole{}
+				m.On("CreateOrganizationRoleWithUsername").Return(&cfresource.Role{Resource: cfresource.Resource{GUID: guidNewRole}}, nil)
+				m.On("Delete").Return("", nil)
+				return m
+			},
+			wantAssigned: map[string]string{"user1": guidRole, "user2": guidNewRole},
+		},
+		"AggregatesErrors": {
+			mg: fakeOrgRole(
+				withOrg(guidOrg),
+				withUsernames("user1", "user2"),
+				withType(v1alpha1.OrgManager),
+				withAssignedRoles(map[string]string{"user3": guidRemovedRole})),
+			service: func() *fake.MockOrgRole {
+				m := &fake.MockOrgRole{}
+				m.On("CreateOrganizationRoleWithUsername").Return(fake.OrganizationRoleNil, errBoom)
+				m.On("Delete").Return("", errBoom)
+				return m
+			},
+			wantAssigned: map[string]string{"user3": guidRemovedRole},
+			wantErr:      true,
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			j := &fake.MockJob{}
+			j.On("PollComplete").Return(nil)
+			m := tc.service()
+			c := &external{role: m, users: newMockUsers(healthyUser), job: j}
+
+			_, err := c.Update(context.Background(), tc.mg)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Update(...): unexpected error: %v", err)
+			}
+			if tc.wantErr {
+				// one error per failed user
+				if got := strings.Count(err.Error(), errBoom.Error()); got != 3 {
+					t.Errorf("Update(...): want 3 aggregated errors, got %d: %v", got, err)
+				}
+			}
+			if diff := cmp.Diff(tc.wantAssigned, tc.mg.Status.AtProvider.AssignedRoles); diff != "" {
+				t.Errorf("Update(...): -want assigned roles, +got:\n%s", diff)
+			}
 		})
 	}
 }
@@ -646,7 +862,7 @@ func TestInitialize(t *testing.T) {
 				mg: fakeOrgRole(
 					withType(v1alpha1.OrgManager),
 					withUsername("my-org-manager"),
-					withOrg("my-org"),
+					withOrg(guidOrg),
 					withOrigin("my-origin"),
 				),
 			},
@@ -654,12 +870,25 @@ func TestInitialize(t *testing.T) {
 				mg: fakeOrgRole(
 					withType(v1alpha1.OrgManager),
 					withUsername("my-org-manager"),
-					withOrg("my-org"),
+					withOrg(guidOrg),
 					withOrigin("my-origin"),
 				),
 				err: nil,
 			},
 		},
+		"MalformedOrgGUID": {
+			args: args{
+				mg: fakeOrgRole(
+					withType(v1alpha1.OrgManager),
+					withUsername("my-org-manager"),
+					withOrg("my-org"),
+					withOrigin("my-origin"),
+				),
+			},
+			want: want{
+				err: errors.New(`org "my-org" is not a valid GUID`),
+			},
+		},
 	}
 
 	for n, tc := range cases {
@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	k8s "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+)
+
+const (
+	// DefaultRecoveryCheckInterval is the default pause between two checks
+	// whether the Cloud Foundry API recovered.
+	DefaultRecoveryCheckInterval = 30 * time.Second
+
+	// AnnotationKeyRequeuedAt is set to the time a failed resource was
+	// requeued after the Cloud Foundry API recovered. Changing it triggers
+	// a reconcile of the resource.
+	AnnotationKeyRequeuedAt = "cloudfoundry.crossplane.io/requeued-at"
+
+	errListManaged = "cannot list %ss"
+	errRequeue     = "cannot requeue %s %s/%s"
+)
+
+// A RecoveryRequeuer requeues the failed managed resources of every kind as
+// soon as the Cloud Foundry API is reachable again after an outage, instead
+// of at their next poll. A failed resource is one whose Synced condition is
+// False.
+type RecoveryRequeuer struct {
+	kube     k8s.Client
+	health   func(ctx context.Context) error
+	interval time.Duration
+	log      logging.Logger
+	now      func() time.Time
+
+	// healthy is the result of the last check. It starts true, so that
+	// resources are not requeued when the provider starts.
+	healthy bool
+}
+
+// NewRecoveryRequeuer returns a RecoveryRequeuer that checks the health of the
+// Cloud Foundry API every interval, e.g. with the CheckContext method of a
+// clients.ReachabilityChecker.
+func NewRecoveryRequeuer(kube k8s.Client, health func(ctx context.Context) error, interval time.Duration, log logging.Logger) *RecoveryRequeuer {
+	return &RecoveryRequeuer{
+		kube:     kube,
+		health:   health,
+		interval: interval,
+		log:      log,
+		now:      time.Now,
+		healthy:  true,
+	}
+}
+
+// Start implements manager.Runnable. It checks the health of the Cloud
+// Foundry API every interval until ctx is done.
+func (r *RecoveryRequeuer) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, r.poll, r.interval)
+	return nil
+}
+
+// poll requeues the failed resources if the Cloud Foundry API recovered
+// since the last check.
+func (r *RecoveryRequeuer) poll(ctx context.Context) {
+	healthy := r.health(ctx) == nil
+	recovered := healthy && !r.healthy
+	r.healthy = healthy
+	if !recovered {
+		return
+	}
+
+	n, err := r.requeueFailed(ctx)
+	if err != nil {
+		r.log.Info("Cannot requeue all failed resources after Cloud Foundry API recovered", "error", err)
+	}
+	r.log.Info("Requeued failed resources after Cloud Foundry API recovered", "count", n)
+}
+
+// requeueFailed annotates the failed managed resources of every kind of the
+// provider and returns how many were requeued. It continues with the other
+// resources if one cannot be requeued and returns the first error.
+func (r *RecoveryRequeuer) requeueFailed(ctx context.Context) (int, error) {
+	scheme := r.kube.Scheme()
+	requeuedAt := r.now().UTC().Format(time.RFC3339)
+
+	var first error
+	n := 0
+	for gvk := range scheme.AllKnownTypes() {
+		if gvk.GroupVersion() != v1alpha1.CRDGroupVersion {
+			continue
+		}
+		obj, err := scheme.New(gvk)
+		if err != nil {
+			continue
+		}
+		l, ok := obj.(resource.ManagedList)
+		if !ok {
+			continue
+		}
+		kind := strings.TrimSuffix(gvk.Kind, "List")
+		if err := r.kube.List(ctx, l); err != nil {
+			if first == nil {
+				first = errors.Wrapf(err, errListManaged, kind)
+			}
+			continue
+		}
+		for _, mg := range l.GetItems() {
+			if mg.GetCondition(xpv1.TypeSynced).Status != corev1.ConditionFalse {
+				continue
+			}
+			patch := k8s.MergeFrom(mg.DeepCopyObject().(k8s.Object))
+			meta.AddAnnotations(mg, map[string]string{AnnotationKeyRequeuedAt: requeuedAt})
+			if err := r.kube.Patch(ctx, mg, patch); err != nil {
+				if first == nil {
+					first = errors.Wrapf(err, errRequeue, kind, mg.GetNamespace(), mg.GetName())
+				}
+				continue
+			}
+			n++
+		}
+	}
+	return n, first
+}
@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8s "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+)
+
+func TestRecoveryRequeuer(t *testing.T) {
+	errBoom := errors.New("boom")
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	space := func(name string, synced xpv1.Condition) v1alpha1.Space {
+		s := v1alpha1.Space{}
+		s.SetNamespace("default")
+		s.SetName(name)
+		s.SetConditions(synced)
+		return s
+	}
+	list := func(_ context.Context, obj k8s.ObjectList, _ ...k8s.ListOption) error {
+		switch l := obj.(type) {
+		case *v1alpha1.SpaceList:
+			l.Items = []v1alpha1.Space{
+				space("failed", xpv1.ReconcileError(errBoom)),
+				space("synced", xpv1.ReconcileSuccess()),
+				space("new", xpv1.Condition{}),
+			}
+		case *v1alpha1.ServiceInstanceList:
+			si := v1alpha1.ServiceInstance{}
+			si.SetNamespace("default")
+			si.SetName("failed-instance")
+			si.SetConditions(xpv1.ReconcileError(errBoom))
+			l.Items = []v1alpha1.ServiceInstance{si}
+		}
+		return nil
+	}
+
+	cases := map[string]struct {
+		health      []error
+		patchErr    error
+		wantPatched []string
+	}{
+		"HealthyAtStart": {
+			health: []error{nil},
+		},
+		"StillUnhealthy": {
+			health: []error{errBoom, errBoom},
+		},
+		"Recovered": {
+			health:      []error{errBoom, nil},
+			wantPatched: []string{"failed", "failed-instance"},
+		},
+		"RecoveredOnce": {
+			// resources are requeued once per recovery, not at every check
+			health:      []error{errBoom, nil, nil},
+			wantPatched: []string{"failed", "failed-instance"},
+		},
+		"RecoveredTwice": {
+			health:      []error{errBoom, nil, errBoom, nil},
+			wantPatched: []string{"failed", "failed", "failed-instance", "failed-instance"},
+		},
+		"PatchFails": {
+			health:   []error{errBoom, nil},
+			patchErr: errBoom,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var patched []string
+			kube := &test.MockClient{
+				MockScheme: test.NewMockSchemeFn(scheme),
+				MockList:   list,
+				MockPatch: func(_ context.Context, obj k8s.Object, _ k8s.Patch, _ ...k8s.PatchOption) error {
+					if tc.patchErr != nil {
+						return tc.patchErr
+					}
+					if got := obj.GetAnnotations()[AnnotationKeyRequeuedAt]; got != now.Format(time.RFC3339) {
+						t.Errorf("Patch(...): %s annotation of %s: want %s, got %s", AnnotationKeyRequeuedAt, obj.GetName(), now.Format(time.RFC3339), got)
+					}
+					patched = append(patched, obj.GetName())
+					return nil
+				},
+			}
+
+			checks := 0
+			r := NewRecoveryRequeuer(kube, func(context.Context) error {
+				err := tc.health[checks]
+				checks++
+				return err
+			}, time.Second, logging.NewNopLogger())
+			r.now = func() time.Time { return now }
+
+			for range tc.health {
+				r.poll(context.Background())
+			}
+			if diff := cmp.Diff(tc.wantPatched, patched, cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+				t.Errorf("poll(...): -want requeued, +got requeued:\n%s", diff)
+			}
+		})
+	}
+}
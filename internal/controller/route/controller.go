@@ -11,6 +11,7 @@ import (
 	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/feature"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
@@ -30,6 +31,8 @@ type RouteService interface {
 	GetByIDOrSpec(ctx context.Context, guid string, forProvider v1alpha1.RouteParameters) (*v1alpha1.RouteObservation, error)
 	Create(ctx context.Context, forProvider v1alpha1.RouteParameters) (string, error)
 	Update(ctx context.Context, guid string, forProvider v1alpha1.RouteParameters) error
+	UpdateDestinations(ctx context.Context, guid string, desired []v1alpha1.RouteDestinationParameters, observed []v1alpha1.RouteDestination) error
+	RemoveDestinations(ctx context.Context, guid string, observed []v1alpha1.RouteDestination) error
 	Delete(ctx context.Context, guid string) error
 }
 
@@ -44,6 +47,8 @@ const (
 	errUpdate        = "cannot update cloudfoundry Route"
 	errDelete        = "cannot delete cloudfoundry Route"
 	errActiveBinding = "cannot delete route with active bindings. Please remove the bindings first."
+	errDestinations  = "cannot update destinations of cloudfoundry Route"
+	errRemoveDest    = "cannot remove destinations of cloudfoundry Route"
 )
 
 // Setup adds a controller that reconciles Org managed resources.
@@ -55,15 +60,21 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 			domainInitializer{client: mgr.GetClient()},
 			spaceInitializer{client: mgr.GetClient()},
 		),
-		managed.WithExternalConnecter(&connector{
+		managed.WithExternalConnecter(clients.WithPause(clients.WithErrorClassification(clients.WithOperationTimeout(mgr.GetClient(), &connector{
 			kube:  mgr.GetClient(),
 			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1beta1.ProviderConfigUsage{}),
-		}),
+		}, clients.DefaultOperationTimeout)))),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithTimeout(clients.MaxOperationTimeout),
+		managed.WithPollIntervalHook(clients.PollIntervalHook(mgr.GetClient())),
 		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 		managed.WithPollInterval(o.PollInterval),
 	}
 
+	if o.Features.Enabled(feature.EnableBetaManagementPolicies) {
+		options = append(options, managed.WithManagementPolicies())
+	}
+
 	r := managed.NewReconciler(mgr,
 		resource.ManagedKind(v1alpha1.RouteGroupVersionKind),
 		options...)
@@ -191,6 +202,12 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdate)
 	}
 
+	if len(cr.Spec.ForProvider.Destinations) > 0 {
+		if err := c.RouteService.UpdateDestinations(ctx, guid, cr.Spec.ForProvider.Destinations, cr.Status.AtProvider.Destinations); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errDestinations)
+		}
+	}
+
 	return managed.ExternalUpdate{
 		// Optionally return any details that may be required to connect to the
 		// external resource. These will be stored as the connection secret.
@@ -205,8 +222,12 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalDelete{}, errors.New(errNotRoute)
 	}
 
-	// Prevent delete if there are bindings.
-	if len(cr.Status.AtProvider.Destinations) > 0 {
+	// Remove managed destinations first, and prevent delete if there are other bindings.
+	if len(cr.Spec.ForProvider.Destinations) > 0 {
+		if err := c.RouteService.RemoveDestinations(ctx, meta.GetExternalName(cr), cr.Status.AtProvider.Destinations); err != nil {
+			return managed.ExternalDelete{}, errors.Wrap(err, errRemoveDest)
+		}
+	} else if len(cr.Status.AtProvider.Destinations) > 0 {
 		return managed.ExternalDelete{}, errors.New(errActiveBinding)
 	}
 
@@ -267,14 +288,9 @@ func (i domainInitializer) Initialize(ctx context.Context, mg resource.Managed)
 type spaceInitializer initializer
 
 func (s spaceInitializer) Initialize(ctx context.Context, mg resource.Managed) error {
-	cr, ok := mg.(*v1alpha1.Route)
-	if !ok {
+	if _, ok := mg.(*v1alpha1.Route); !ok {
 		return errors.New(errNotRoute)
 	}
 
-	if cr.Spec.ForProvider.SpaceRef != nil || cr.Spec.ForProvider.SpaceSelector != nil {
-		return cr.ResolveReferences(ctx, s.client)
-	}
-
-	return space.ResolveByName(ctx, clients.ClientFnBuilder(ctx, s.client), mg)
+	return space.ResolveSpaceReference(ctx, s.client, mg)
 }
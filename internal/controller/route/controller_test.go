@@ -38,6 +38,16 @@ func (m *Mock) Update(ctx context.Context, guid string, forProvider v1alpha1.Rou
 	return args.Error(0)
 }
 
+func (m *Mock) UpdateDestinations(ctx context.Context, guid string, desired []v1alpha1.RouteDestinationParameters, observed []v1alpha1.RouteDestination) error {
+	args := m.Called(desired, observed)
+	return args.Error(0)
+}
+
+func (m *Mock) RemoveDestinations(ctx context.Context, guid string, observed []v1alpha1.RouteDestination) error {
+	args := m.Called(observed)
+	return args.Error(0)
+}
+
 func (m *Mock) Delete(ctx context.Context, guid string) error {
 	args := m.Called()
 	return args.Error(0)
@@ -47,6 +57,8 @@ var (
 	spaceGUID      = "11fd5b0b-4f3b-4b1b-8b3d-3b5f7b4b3b4b"
 	domainGUID     = "22fd5b0b-4f3b-4b1b-8b3d-3b5f7b4b3b4b"
 	guid           = "33fd5b0b-4f3b-4b1b-8b3d-3b5f7b4b3b4b"
+	blueGUID       = "44fd5b0b-4f3b-4b1b-8b3d-3b5f7b4b3b4b"
+	greenGUID      = "55fd5b0b-4f3b-4b1b-8b3d-3b5f7b4b3b4b"
 	name           = "test-route"
 	errBoom        = errors.New("boom")
 	nilObservation *v1alpha1.RouteObservation
@@ -66,6 +78,30 @@ func withHost(host string) modifier {
 	}
 }
 
+// withDestinations sets desired destinations to the blue and green apps with the given weights.
+func withDestinations(blue, green int) modifier {
+	return func(r *v1alpha1.Route) {
+		r.Spec.ForProvider.Destinations = []v1alpha1.RouteDestinationParameters{
+			{App: &blueGUID, Weight: &blue},
+			{App: &greenGUID, Weight: &green},
+		}
+	}
+}
+
+// withObservedDestinations sets observed destinations to the blue and green apps with the given weights.
+func withObservedDestinations(blue, green int) modifier {
+	return func(r *v1alpha1.Route) {
+		r.Status.AtProvider.Destinations = observedDestinations(blue, green)
+	}
+}
+
+func observedDestinations(blue, green int) []v1alpha1.RouteDestination {
+	return []v1alpha1.RouteDestination{
+		{GUID: "blue", App: &v1alpha1.RouteDestinationApp{GUID: blueGUID}, Weight: &blue},
+		{GUID: "green", App: &v1alpha1.RouteDestinationApp{GUID: greenGUID}, Weight: &green},
+	}
+}
+
 func fakeRoute(m ...modifier) *v1alpha1.Route {
 	r := &v1alpha1.Route{
 		ObjectMeta: metav1.ObjectMeta{
@@ -212,6 +248,23 @@ func TestObserve(t *testing.T) {
 				return m
 			},
 		},
+		"WeightDrift": {
+			args: args{
+				mg: fakeRoute(withExternalName(guid), withDestinations(50, 50)),
+			},
+			want: want{
+				mg:  fakeRoute(withExternalName(guid), withDestinations(50, 50)),
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				err: nil,
+			},
+			service: func() *Mock {
+				m := &Mock{}
+				obs := fakeRouteObservation(guid)
+				obs.Destinations = observedDestinations(70, 30)
+				m.On("GetByIDOrSpec", guid).Return(obs, nil)
+				return m
+			},
+		},
 		"Adopt and set external-name ": {
 			args: args{
 				mg: fakeRoute(withHost(name)),
@@ -273,3 +326,97 @@ func TestObserve(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdate(t *testing.T) {
+	cases := map[string]struct {
+		mg      *v1alpha1.Route
+		service func() *Mock
+		err     error
+	}{
+		"WeightChange": {
+			mg: fakeRoute(withExternalName(guid), withDestinations(50, 50), withObservedDestinations(70, 30)),
+			service: func() *Mock {
+				m := &Mock{}
+				m.On("Update").Return(nil)
+				m.On("UpdateDestinations", fakeRoute(withDestinations(50, 50)).Spec.ForProvider.Destinations, observedDestinations(70, 30)).Return(nil)
+				return m
+			},
+		},
+		"UnmanagedDestinations": {
+			mg: fakeRoute(withExternalName(guid), withObservedDestinations(70, 30)),
+			service: func() *Mock {
+				m := &Mock{}
+				m.On("Update").Return(nil)
+				return m
+			},
+		},
+		"UpdateDestinationsFailed": {
+			mg: fakeRoute(withExternalName(guid), withDestinations(50, 50), withObservedDestinations(70, 30)),
+			service: func() *Mock {
+				m := &Mock{}
+				m.On("Update").Return(nil)
+				m.On("UpdateDestinations", mock.Anything, mock.Anything).Return(errBoom)
+				return m
+			},
+			err: errors.Wrap(errBoom, errDestinations),
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			m := tc.service()
+			c := &external{RouteService: m}
+			_, err := c.Update(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Update(...): -want error, +got error:\n%s", diff)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	cases := map[string]struct {
+		mg      *v1alpha1.Route
+		service func() *Mock
+		err     error
+	}{
+		"RemovesManagedDestinationsFirst": {
+			mg: fakeRoute(withExternalName(guid), withDestinations(50, 50), withObservedDestinations(50, 50)),
+			service: func() *Mock {
+				m := &Mock{}
+				rm := m.On("RemoveDestinations", observedDestinations(50, 50)).Return(nil)
+				m.On("Delete").Return(nil).NotBefore(rm)
+				return m
+			},
+		},
+		"RemoveDestinationsFailed": {
+			mg: fakeRoute(withExternalName(guid), withDestinations(50, 50), withObservedDestinations(50, 50)),
+			service: func() *Mock {
+				m := &Mock{}
+				m.On("RemoveDestinations", mock.Anything).Return(errBoom)
+				return m
+			},
+			err: errors.Wrap(errBoom, errRemoveDest),
+		},
+		"UnmanagedDestinations": {
+			mg: fakeRoute(withExternalName(guid), withObservedDestinations(50, 50)),
+			service: func() *Mock {
+				return &Mock{}
+			},
+			err: errors.New(errActiveBinding),
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			m := tc.service()
+			c := &external{RouteService: m}
+			_, err := c.Delete(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Delete(...): -want error, +got error:\n%s", diff)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
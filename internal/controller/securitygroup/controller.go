@@ -0,0 +1,213 @@
+package securitygroup
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	k8s "sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/feature"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	pcv1beta1 "github.com/SAP/crossplane-provider-cloudfoundry/apis/v1beta1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/job"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/securitygroup"
+)
+
+const (
+	resourceType         = "SecurityGroup"
+	externalSystem       = "Cloud Foundry"
+	errNotSecurityGroup  = "managed resource is not of kind " + resourceType
+	errTrackUsage        = "cannot track usage"
+	errGetClient         = "cannot create a client to talk to the API of " + externalSystem
+	errGet               = "cannot get " + externalSystem + " security group"
+	errCreate            = "cannot create " + externalSystem + " security group"
+	errUpdate            = "cannot update " + externalSystem + " security group"
+	errDelete            = "cannot delete " + externalSystem + " security group"
+	errBindSpaces        = "cannot bind " + externalSystem + " security group to spaces"
+	errMissingExternalID = "external name is not set"
+)
+
+// Setup adds a controller that reconciles SecurityGroup resources.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(v1alpha1.SecurityGroup_GroupKind)
+
+	options := []managed.ReconcilerOption{
+		managed.WithExternalConnecter(clients.WithPause(clients.WithErrorClassification(clients.WithOperationTimeout(mgr.GetClient(), &connector{
+			kube:  mgr.GetClient(),
+			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &pcv1beta1.ProviderConfigUsage{}),
+		}, clients.DefaultOperationTimeout)))),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithTimeout(clients.MaxOperationTimeout),
+		managed.WithPollIntervalHook(clients.PollIntervalHook(mgr.GetClient())),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithPollInterval(o.PollInterval),
+	}
+
+	if o.Features.Enabled(feature.EnableBetaManagementPolicies) {
+		options = append(options, managed.WithManagementPolicies())
+	}
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.SecurityGroup_GroupVersionKind),
+		options...)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1alpha1.SecurityGroup{}).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// A connector supplies a function for the Reconciler to create a client to the external CloudFoundry resources.
+type connector struct {
+	kube  k8s.Client
+	usage *resource.ProviderConfigUsageTracker
+}
+
+// Connect typically produces an ExternalClient by:
+// 1. Tracking that the managed resource is using a ProviderConfig.
+// 2. Getting the managed resource's ProviderConfig.
+// 3. Getting the credentials specified by the ProviderConfig.
+// 4. Using the credentials to form a client.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1alpha1.SecurityGroup); !ok {
+		return nil, errors.New(errNotSecurityGroup)
+	}
+
+	if err := c.usage.Track(ctx, mg.(resource.ModernManaged)); err != nil {
+		return nil, errors.Wrap(err, errTrackUsage)
+	}
+
+	cf, err := clients.ClientFnBuilder(ctx, c.kube)(mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetClient)
+	}
+
+	return &external{client: securitygroup.NewClient(cf), job: cf.Jobs, kube: c.kube}, nil
+}
+
+// Disconnect implements the managed.ExternalClient interface
+func (c *external) Disconnect(ctx context.Context) error {
+	// No cleanup needed for Cloud Foundry client
+	return nil
+}
+
+// An external is a managed.ExternalConnecter that is using the CloudFoundry API to observe and modify resources.
+type external struct {
+	client securitygroup.Client
+	job    job.Job
+	kube   k8s.Client
+}
+
+// Observe managed resource SecurityGroup
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.SecurityGroup)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotSecurityGroup)
+	}
+
+	guid := meta.GetExternalName(cr)
+
+	sg, err := securitygroup.GetByIDOrName(ctx, c.client, guid, cr.Spec.ForProvider.Name)
+	if err != nil {
+		if clients.IsNotFound(err) {
+			return managed.ExternalObservation{ResourceExists: false}, nil
+		}
+		return managed.ExternalObservation{}, errors.Wrap(err, errGet)
+	}
+
+	lateInitialized := securitygroup.LateInitialize(&cr.Spec.ForProvider, sg)
+
+	// set the external name to the GUID
+	if guid != sg.GUID {
+		meta.SetExternalName(cr, sg.GUID)
+		lateInitialized = true
+	}
+
+	previous := cr.Status.AtProvider
+	cr.Status.AtProvider = securitygroup.GenerateObservation(sg)
+	securitygroup.ObserveManagedSpaces(cr.Spec.ForProvider, previous, &cr.Status.AtProvider)
+	cr.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{
+		ResourceExists: true,
+		ResourceUpToDate: securitygroup.IsUpToDate(cr.Spec.ForProvider, sg) &&
+			securitygroup.IsSpacesUpToDate(cr.Spec.ForProvider, cr.Status.AtProvider),
+		ResourceLateInitialized: lateInitialized,
+	}, nil
+}
+
+// Create a managed resource SecurityGroup
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.SecurityGroup)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotSecurityGroup)
+	}
+
+	cr.SetConditions(xpv1.Creating())
+
+	sg, err := c.client.Create(ctx, securitygroup.GenerateCreate(cr.Spec.ForProvider))
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreate)
+	}
+
+	meta.SetExternalName(cr, sg.GUID)
+
+	return managed.ExternalCreation{}, nil
+}
+
+// Update managed resource SecurityGroup
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.SecurityGroup)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotSecurityGroup)
+	}
+
+	guid := meta.GetExternalName(cr)
+	if !clients.IsValidGUID(guid) {
+		return managed.ExternalUpdate{}, errors.Wrap(errors.New(errMissingExternalID), errUpdate)
+	}
+
+	if _, err := c.client.Update(ctx, guid, securitygroup.GenerateUpdate(cr.Spec.ForProvider)); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdate)
+	}
+
+	if err := securitygroup.BindSpaces(ctx, c.client, guid, cr.Spec.ForProvider, cr.Status.AtProvider); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errBindSpaces)
+	}
+
+	return managed.ExternalUpdate{}, nil
+}
+
+// Delete managed resource SecurityGroup
+func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	cr, ok := mg.(*v1alpha1.SecurityGroup)
+	if !ok {
+		return managed.ExternalDelete{}, errors.New(errNotSecurityGroup)
+	}
+	cr.SetConditions(xpv1.Deleting())
+
+	guid := meta.GetExternalName(cr)
+	if !clients.IsValidGUID(guid) {
+		return managed.ExternalDelete{}, errors.Wrap(errors.New(errMissingExternalID), errDelete)
+	}
+
+	// Delete is async, wait for the job to complete. A security group that is already gone is deleted.
+	jobGUID, err := c.client.Delete(ctx, guid)
+	if err != nil {
+		return managed.ExternalDelete{}, errors.Wrap(clients.IgnoreNotFoundErr(err), errDelete)
+	}
+
+	return managed.ExternalDelete{}, errors.Wrap(job.PollJobComplete(ctx, c.job, jobGUID), errDelete)
+}
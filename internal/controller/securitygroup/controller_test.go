@@ -0,0 +1,491 @@
+package securitygroup
+
+import (
+	"context"
+	"testing"
+
+	cfresource "github.com/cloudfoundry/go-cfclient/v3/resource"
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/fake"
+)
+
+var (
+	errBoom   = errors.New("boom")
+	name      = "public-networks"
+	guid      = "b85a788e-671f-4549-814d-e34cdb2f539a"
+	spaceGUID = "2d8b0d04-d537-4e4e-8c6f-f09ca0e7f56f"
+	jobGUID   = "3d8b0d04-d537-4e4e-8c6f-f09ca0e7f56f"
+
+	httpsRule = v1alpha1.SecurityGroupRule{Protocol: "tcp", Destination: "0.0.0.0/0", Ports: ptr.To("443")}
+	dnsRule   = v1alpha1.SecurityGroupRule{Protocol: "udp", Destination: "0.0.0.0/0", Ports: ptr.To("53")}
+)
+
+type modifier func(*v1alpha1.SecurityGroup)
+
+func withExternalName(name string) modifier {
+	return func(r *v1alpha1.SecurityGroup) {
+		meta.SetExternalName(r, name)
+	}
+}
+
+func withRules(rules ...v1alpha1.SecurityGroupRule) modifier {
+	return func(r *v1alpha1.SecurityGroup) {
+		r.Spec.ForProvider.Rules = rules
+	}
+}
+
+func withGloballyEnabled(running, staging bool) modifier {
+	return func(r *v1alpha1.SecurityGroup) {
+		r.Spec.ForProvider.GloballyEnabledRunning = &running
+		r.Spec.ForProvider.GloballyEnabledStaging = &staging
+	}
+}
+
+func withRunningSpaces(guids ...*string) modifier {
+	return func(r *v1alpha1.SecurityGroup) {
+		r.Spec.ForProvider.RunningSpaces = guids
+	}
+}
+
+func withManagedRunningSpaces(guids ...string) modifier {
+	return func(r *v1alpha1.SecurityGroup) {
+		r.Status.AtProvider.ManagedRunningSpaces = guids
+	}
+}
+
+func withConditions(c ...xpv1.Condition) modifier {
+	return func(r *v1alpha1.SecurityGroup) { r.Status.SetConditions(c...) }
+}
+
+func fakeSecurityGroup(m ...modifier) *v1alpha1.SecurityGroup {
+	r := &v1alpha1.SecurityGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Finalizers:  []string{},
+			Annotations: map[string]string{},
+		},
+		Spec: v1alpha1.SecurityGroupSpec{
+			ForProvider: v1alpha1.SecurityGroupParameters{Name: name},
+		},
+	}
+
+	for _, rm := range m {
+		rm(r)
+	}
+	return r
+}
+
+func rule(r v1alpha1.SecurityGroupRule) cfresource.SecurityGroupRule {
+	return cfresource.SecurityGroupRule{
+		Protocol:    r.Protocol,
+		Destination: r.Destination,
+		Ports:       r.Ports,
+	}
+}
+
+func TestObserve(t *testing.T) {
+	type service func() *fake.MockSecurityGroup
+	type args struct {
+		mg resource.Managed
+	}
+
+	type want struct {
+		mg  *v1alpha1.SecurityGroup
+		obs managed.ExternalObservation
+		err error
+	}
+
+	cases := map[string]struct {
+		args    args
+		want    want
+		service service
+	}{
+		"WrongKind": {
+			args: args{
+				mg: nil,
+			},
+			want: want{
+				err: errors.New(errNotSecurityGroup),
+			},
+			service: func() *fake.MockSecurityGroup {
+				return &fake.MockSecurityGroup{}
+			},
+		},
+		"Error": {
+			args: args{
+				mg: fakeSecurityGroup(withExternalName(guid)),
+			},
+			want: want{
+				mg:  fakeSecurityGroup(withExternalName(guid)),
+				obs: managed.ExternalObservation{},
+				err: errors.Wrap(errBoom, errGet),
+			},
+			service: func() *fake.MockSecurityGroup {
+				m := &fake.MockSecurityGroup{}
+				m.On("Get", guid).Return(fake.SecurityGroupNil, errBoom)
+				return m
+			},
+		},
+		"NotFound": {
+			args: args{
+				mg: fakeSecurityGroup(),
+			},
+			want: want{
+				mg:  fakeSecurityGroup(),
+				obs: managed.ExternalObservation{ResourceExists: false},
+			},
+			service: func() *fake.MockSecurityGroup {
+				m := &fake.MockSecurityGroup{}
+				m.On("Single", []string{name}).Return(fake.SecurityGroupNil, fake.ErrNoResultReturned)
+				return m
+			},
+		},
+		"LateInitializeByName": {
+			args: args{
+				mg: fakeSecurityGroup(withRules(httpsRule)),
+			},
+			want: want{
+				mg: fakeSecurityGroup(withRules(httpsRule), withExternalName(guid), withGloballyEnabled(true, false)),
+				obs: managed.ExternalObservation{
+					ResourceExists:          true,
+					ResourceUpToDate:        true,
+					ResourceLateInitialized: true,
+				},
+			},
+			service: func() *fake.MockSecurityGroup {
+				m := &fake.MockSecurityGroup{}
+				m.On("Single", []string{name}).Return(
+					&fake.NewSecurityGroup().SetName(name).SetGUID(guid).SetRules(rule(httpsRule)).SetGloballyEnabled(true, false).SecurityGroup,
+					nil,
+				)
+				return m
+			},
+		},
+		"ReorderedRulesUpToDate": {
+			args: args{
+				mg: fakeSecurityGroup(withExternalName(guid), withGloballyEnabled(false, false), withRules(dnsRule, httpsRule)),
+			},
+			want: want{
+				mg:  fakeSecurityGroup(withExternalName(guid), withGloballyEnabled(false, false), withRules(dnsRule, httpsRule)),
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+			service: func() *fake.MockSecurityGroup {
+				m := &fake.MockSecurityGroup{}
+				m.On("Get", guid).Return(
+					&fake.NewSecurityGroup().SetName(name).SetGUID(guid).SetRules(rule(httpsRule), rule(dnsRule)).SetGloballyEnabled(false, false).SecurityGroup,
+					nil,
+				)
+				return m
+			},
+		},
+		"RuleDrift": {
+			args: args{
+				mg: fakeSecurityGroup(withExternalName(guid), withGloballyEnabled(false, false), withRules(dnsRule, httpsRule)),
+			},
+			want: want{
+				mg:  fakeSecurityGroup(withExternalName(guid), withGloballyEnabled(false, false), withRules(dnsRule, httpsRule)),
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+			service: func() *fake.MockSecurityGroup {
+				m := &fake.MockSecurityGroup{}
+				m.On("Get", guid).Return(
+					&fake.NewSecurityGroup().SetName(name).SetGUID(guid).SetRules(rule(httpsRule)).SetGloballyEnabled(false, false).SecurityGroup,
+					nil,
+				)
+				return m
+			},
+		},
+		"SpaceDrift": {
+			args: args{
+				mg: fakeSecurityGroup(withExternalName(guid), withGloballyEnabled(false, false), withRunningSpaces(&spaceGUID)),
+			},
+			want: want{
+				mg:  fakeSecurityGroup(withExternalName(guid), withGloballyEnabled(false, false), withRunningSpaces(&spaceGUID)),
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+			service: func() *fake.MockSecurityGroup {
+				m := &fake.MockSecurityGroup{}
+				m.On("Get", guid).Return(
+					&fake.NewSecurityGroup().SetName(name).SetGUID(guid).SetGloballyEnabled(false, false).SecurityGroup,
+					nil,
+				)
+				return m
+			},
+		},
+		"RemovedSpaceDrift": {
+			args: args{
+				mg: fakeSecurityGroup(withExternalName(guid), withGloballyEnabled(false, false), withManagedRunningSpaces(spaceGUID)),
+			},
+			want: want{
+				mg:  fakeSecurityGroup(withExternalName(guid), withGloballyEnabled(false, false)),
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+			service: func() *fake.MockSecurityGroup {
+				m := &fake.MockSecurityGroup{}
+				m.On("Get", guid).Return(
+					&fake.NewSecurityGroup().SetName(name).SetGUID(guid).SetGloballyEnabled(false, false).SetRunningSpaces(spaceGUID).SecurityGroup,
+					nil,
+				)
+				return m
+			},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			c := &external{client: tc.service()}
+			obs, err := c.Observe(context.Background(), tc.args.mg)
+
+			if tc.want.err != nil && err != nil {
+				if diff := cmp.Diff(tc.want.err.Error(), err.Error()); diff != "" {
+					t.Errorf("Observe(...): want error string != got error string:\n%s", diff)
+				}
+			} else if diff := cmp.Diff(tc.want.err, err); diff != "" {
+				t.Errorf("Observe(...): want error != got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.obs, obs); diff != "" {
+				t.Errorf("Observe(...): -want, +got:\n%s", diff)
+			}
+			if cr, ok := tc.args.mg.(*v1alpha1.SecurityGroup); ok && tc.want.mg != nil {
+				if diff := cmp.Diff(tc.want.mg.Spec, cr.Spec); diff != "" {
+					t.Errorf("Observe(...): -want spec, +got spec:\n%s", diff)
+				}
+				if diff := cmp.Diff(meta.GetExternalName(tc.want.mg), meta.GetExternalName(cr)); diff != "" {
+					t.Errorf("Observe(...): -want external name, +got external name:\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	type service func() *fake.MockSecurityGroup
+	type args struct {
+		mg resource.Managed
+	}
+
+	type want struct {
+		mg  *v1alpha1.SecurityGroup
+		err error
+	}
+
+	cases := map[string]struct {
+		args    args
+		want    want
+		service service
+	}{
+		"Successful": {
+			args: args{
+				mg: fakeSecurityGroup(withRules(httpsRule), withRunningSpaces(&spaceGUID)),
+			},
+			want: want{
+				mg: fakeSecurityGroup(withRules(httpsRule), withRunningSpaces(&spaceGUID), withExternalName(guid), withConditions(xpv1.Creating())),
+			},
+			service: func() *fake.MockSecurityGroup {
+				m := &fake.MockSecurityGroup{}
+				m.On("Create", &cfresource.SecurityGroupCreate{
+					Name:          name,
+					Rules:         []*cfresource.SecurityGroupRule{ptr.To(rule(httpsRule))},
+					Relationships: map[string]cfresource.ToManyRelationships{"running_spaces": *cfresource.NewToManyRelationships([]string{spaceGUID})},
+				}).Return(&fake.NewSecurityGroup().SetName(name).SetGUID(guid).SecurityGroup, nil)
+				return m
+			},
+		},
+		"Error": {
+			args: args{
+				mg: fakeSecurityGroup(),
+			},
+			want: want{
+				mg:  fakeSecurityGroup(withConditions(xpv1.Creating())),
+				err: errors.Wrap(errBoom, errCreate),
+			},
+			service: func() *fake.MockSecurityGroup {
+				m := &fake.MockSecurityGroup{}
+				m.On("Create", mock.Anything).Return(fake.SecurityGroupNil, errBoom)
+				return m
+			},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			c := &external{client: tc.service()}
+			_, err := c.Create(context.Background(), tc.args.mg)
+
+			if tc.want.err != nil && err != nil {
+				if diff := cmp.Diff(tc.want.err.Error(), err.Error()); diff != "" {
+					t.Errorf("Create(...): want error string != got error string:\n%s", diff)
+				}
+			} else if diff := cmp.Diff(tc.want.err, err); diff != "" {
+				t.Errorf("Create(...): want error != got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg); diff != "" {
+				t.Errorf("Create(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	type service func() *fake.MockSecurityGroup
+	type args struct {
+		mg resource.Managed
+	}
+
+	cases := map[string]struct {
+		args    args
+		want    error
+		service service
+	}{
+		"UpdateRulesAndBindSpaces": {
+			args: args{
+				mg: fakeSecurityGroup(withExternalName(guid), withGloballyEnabled(true, false), withRules(httpsRule), withRunningSpaces(&spaceGUID)),
+			},
+			service: func() *fake.MockSecurityGroup {
+				m := &fake.MockSecurityGroup{}
+				m.On("Update", guid, &cfresource.SecurityGroupUpdate{
+					Name:            name,
+					Rules:           []*cfresource.SecurityGroupRule{ptr.To(rule(httpsRule))},
+					GloballyEnabled: &cfresource.SecurityGroupGloballyEnabled{Running: ptr.To(true), Staging: ptr.To(false)},
+				}).Return(&fake.NewSecurityGroup().SetName(name).SetGUID(guid).SecurityGroup, nil)
+				m.On("BindRunningSecurityGroup", guid, []string{spaceGUID}).Return(nil)
+				return m
+			},
+		},
+		"UpdateError": {
+			args: args{
+				mg: fakeSecurityGroup(withExternalName(guid)),
+			},
+			want: errors.Wrap(errBoom, errUpdate),
+			service: func() *fake.MockSecurityGroup {
+				m := &fake.MockSecurityGroup{}
+				m.On("Update", guid, mock.Anything).Return(fake.SecurityGroupNil, errBoom)
+				return m
+			},
+		},
+		"NoExternalName": {
+			args: args{
+				mg: fakeSecurityGroup(),
+			},
+			want: errors.Wrap(errors.New(errMissingExternalID), errUpdate),
+			service: func() *fake.MockSecurityGroup {
+				return &fake.MockSecurityGroup{}
+			},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			m := tc.service()
+			c := &external{client: m}
+			_, err := c.Update(context.Background(), tc.args.mg)
+
+			if tc.want != nil && err != nil {
+				if diff := cmp.Diff(tc.want.Error(), err.Error()); diff != "" {
+					t.Errorf("Update(...): want error string != got error string:\n%s", diff)
+				}
+			} else if diff := cmp.Diff(tc.want, err); diff != "" {
+				t.Errorf("Update(...): want error != got error:\n%s", diff)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	type service func() *fake.MockSecurityGroup
+	type args struct {
+		mg resource.Managed
+	}
+
+	cases := map[string]struct {
+		args    args
+		want    error
+		service service
+		job     func() *fake.MockJob
+	}{
+		"Successful": {
+			args: args{
+				mg: fakeSecurityGroup(withExternalName(guid)),
+			},
+			service: func() *fake.MockSecurityGroup {
+				m := &fake.MockSecurityGroup{}
+				m.On("Delete", guid).Return(jobGUID, nil)
+				return m
+			},
+			job: func() *fake.MockJob {
+				m := &fake.MockJob{}
+				m.On("PollComplete").Return(nil)
+				return m
+			},
+		},
+		"JobFailed": {
+			args: args{
+				mg: fakeSecurityGroup(withExternalName(guid)),
+			},
+			want: errors.Wrap(errBoom, errDelete),
+			service: func() *fake.MockSecurityGroup {
+				m := &fake.MockSecurityGroup{}
+				m.On("Delete", guid).Return(jobGUID, nil)
+				return m
+			},
+			job: func() *fake.MockJob {
+				m := &fake.MockJob{}
+				m.On("PollComplete").Return(errBoom)
+				return m
+			},
+		},
+		"AlreadyDeleted": {
+			args: args{
+				mg: fakeSecurityGroup(withExternalName(guid)),
+			},
+			service: func() *fake.MockSecurityGroup {
+				m := &fake.MockSecurityGroup{}
+				m.On("Delete", guid).Return("", fake.ErrResourceNotFound)
+				return m
+			},
+		},
+		"Error": {
+			args: args{
+				mg: fakeSecurityGroup(withExternalName(guid)),
+			},
+			want: errors.Wrap(errBoom, errDelete),
+			service: func() *fake.MockSecurityGroup {
+				m := &fake.MockSecurityGroup{}
+				m.On("Delete", guid).Return("", errBoom)
+				return m
+			},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			m, j := tc.service(), &fake.MockJob{}
+			if tc.job != nil {
+				j = tc.job()
+			}
+			c := &external{client: m, job: j}
+			_, err := c.Delete(context.Background(), tc.args.mg)
+
+			if tc.want != nil && err != nil {
+				if diff := cmp.Diff(tc.want.Error(), err.Error()); diff != "" {
+					t.Errorf("Delete(...): want error string != got error string:\n%s", diff)
+				}
+			} else if diff := cmp.Diff(tc.want, err); diff != "" {
+				t.Errorf("Delete(...): want error != got error:\n%s", diff)
+			}
+			m.AssertExpectations(t)
+			j.AssertExpectations(t)
+		})
+	}
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/feature"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
@@ -18,11 +20,17 @@ import (
 	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
 	apisv1beta1 "github.com/SAP/crossplane-provider-cloudfoundry/apis/v1beta1"
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/app"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/org"
 	scb "github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/servicecredentialbinding"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/space"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/metrics"
 
-	cfclient "github.com/cloudfoundry/go-cfclient/v3/client"
 	cfresource "github.com/cloudfoundry/go-cfclient/v3/resource"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 )
 
 const (
@@ -39,29 +47,54 @@ const (
 	errDeleteRetiredKeys = "cannot delete retired keys in " + externalSystem + ": %w"
 	errDeleteExpiredKeys = "cannot delete expired keys in " + externalSystem + ": %w"
 	errUpdateStatus      = "cannot update status after retiring binding: %w"
+	errLabelRetired      = "cannot label retired binding in " + externalSystem + ": %w"
+	errUpdateCR          = "cannot update the managed resource: %w"
 	errExtractParams     = "cannot extract specified parameters: %w"
+	errCleanFailed       = "cannot delete failed " + resourceType + " in " + externalSystem + ": %w"
 	errUnknownState      = "unknown last operation state for " + resourceType + " in " + externalSystem
+	errDependencies      = "waiting for dependencies of " + resourceType + ": %w"
+	errDeleteSecret      = "cannot delete connection secret of " + resourceType + ": %w"
+	errResolveAppName    = "cannot resolve app by name: %w"
+	errResolveAppSpace   = "cannot resolve space of app: %w"
 )
 
+const reasonRotatingBinding event.Reason = "RotatingBinding"
+
 // Setup adds a controller that reconciles ServiceCredentialBinding CR.
 func Setup(mgr ctrl.Manager, o controller.Options) error {
 	name := managed.ControllerName(v1alpha1.ServiceCredentialBindingGroupKind)
 
+	metrics.Register()
+
 	options := []managed.ReconcilerOption{
-		managed.WithInitializers(),
-		managed.WithExternalConnecter(&connector{
-			kube:  mgr.GetClient(),
-			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1beta1.ProviderConfigUsage{}),
-		}),
+		managed.WithInitializers(appNameInitializer{kube: mgr.GetClient()}, guidInitializer{}),
+		managed.WithExternalConnecter(clients.WithPause(clients.WithLogger(o.Logger.WithValues("controller", name), clients.WithErrorClassification(metrics.Instrument(v1alpha1.ServiceCredentialBindingKind, clients.WithOperationTimeout(mgr.GetClient(), &connector{
+			kube:     mgr.GetClient(),
+			reader:   mgr.GetAPIReader(),
+			usage:    resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1beta1.ProviderConfigUsage{}),
+			recorder: event.NewAPIRecorder(mgr.GetEventRecorderFor(name), clients.RateLimitEvents(clients.EventRateLimitInterval)),
+		}, clients.DefaultOperationTimeout)))))),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithTimeout(clients.MaxOperationTimeout),
+		managed.WithPollIntervalHook(clients.PollIntervalHook(mgr.GetClient())),
 		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 		managed.WithPollInterval(o.PollInterval),
 	}
 
+	if o.Features.Enabled(feature.EnableBetaManagementPolicies) {
+		options = append(options, managed.WithManagementPolicies())
+	}
+
 	r := managed.NewReconciler(mgr,
 		resource.ManagedKind(v1alpha1.ServiceCredentialBindingGroupVersionKind),
 		options...)
 
+	if RetiredKeyGC {
+		if err := mgr.Add(newRetiredKeyCollector(mgr.GetClient(), o.Logger.WithValues("controller", name, "task", "retired-key-gc"))); err != nil {
+			return err
+		}
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
@@ -72,8 +105,10 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 // A connector is expected to produce an external client when its Connect method
 // is called.
 type connector struct {
-	kube  k8s.Client
-	usage *resource.ProviderConfigUsageTracker
+	kube     k8s.Client
+	reader   k8s.Reader
+	usage    *resource.ProviderConfigUsageTracker
+	recorder event.Recorder
 }
 
 // Connect typically produces an ExternalClient by:
@@ -94,11 +129,19 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if err != nil {
 		return nil, fmt.Errorf(errNewClient, err)
 	}
+	// Retired keys are labeled with the ProviderConfig to tell them apart from the keys of other clusters
+	pc, err := clients.GetProviderConfig(ctx, c.kube, mg)
+	if err != nil {
+		return nil, err
+	}
 
 	client := scb.NewClient(cf)
 	ext := &external{
-		kube:      c.kube,
-		scbClient: client,
+		kube:              c.kube,
+		reader:            c.reader,
+		scbClient:         client,
+		providerConfigUID: pc.GetUID(),
+		recorder:          c.recorder,
 		keyRotator: &scb.SCBKeyRotator{
 			SCBClient: client,
 		},
@@ -122,9 +165,12 @@ type ObservationStateHandler interface {
 // external resource to ensure it reflects the managed resource's desired state.
 type external struct {
 	kube                    k8s.Client
+	reader                  k8s.Reader
 	scbClient               scb.ServiceCredentialBinding
+	providerConfigUID       types.UID
 	keyRotator              scb.KeyRotator
 	observationStateHandler ObservationStateHandler
+	recorder                event.Recorder
 }
 
 // Observe checks the observed state of the resource and updates the managed resource's status.
@@ -136,19 +182,32 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 
 	guid := meta.GetExternalName(cr)
 	serviceBinding, err := scb.GetByIDOrSearch(ctx, c.scbClient, guid, cr.Spec.ForProvider)
-	if errors.Is(err, cfclient.ErrNoResultsReturned) ||
-		errors.Is(err, cfclient.ErrExactlyOneResultNotReturned) ||
-		cfresource.IsResourceNotFoundError(err) ||
-		cfresource.IsServiceBindingNotFoundError(err) {
-		return managed.ExternalObservation{ResourceExists: false}, nil
+	if clients.IsNotFound(err) {
+		return c.observeMissing(ctx, cr), nil
 	} else if err != nil {
 		return managed.ExternalObservation{}, fmt.Errorf(errGet, err)
 	}
 
+	// adopt a binding found by its spec
+	if guid != serviceBinding.GUID {
+		meta.SetExternalName(cr, serviceBinding.GUID)
+		if !clients.IsReadOnly(ctx) {
+			if err := c.kube.Update(ctx, cr); err != nil {
+				return managed.ExternalObservation{}, fmt.Errorf(errUpdateCR, err)
+			}
+		}
+	}
+
 	cr.Status.AtProvider.GUID = serviceBinding.GUID
 	cr.Status.AtProvider.CreatedAt = &metav1.Time{Time: serviceBinding.CreatedAt}
 
-	if c.keyRotator.RetireBinding(cr, serviceBinding) {
+	// An observed-only or paused binding is never rotated, as rotation creates a new binding
+	if !clients.IsObserveOnly(cr) && !clients.IsReadOnly(ctx) && c.keyRotator.RetireBinding(cr, serviceBinding) {
+		if err := scb.LabelRetired(ctx, c.scbClient, serviceBinding.GUID, cr.GetUID(), c.providerConfigUID, time.Now()); err != nil {
+			return managed.ExternalObservation{}, fmt.Errorf(errLabelRetired, err)
+		}
+		c.recorder.Event(cr, event.Normal(reasonRotatingBinding, "Retired binding "+serviceBinding.GUID+", creating a new binding to rotate its credentials"))
+		clients.LoggerFrom(ctx).Debug("Retired binding to rotate its credentials", "retired-guid", serviceBinding.GUID)
 		if err := c.kube.Status().Update(ctx, cr); err != nil {
 			return managed.ExternalObservation{}, fmt.Errorf(errUpdateStatus, err)
 		}
@@ -160,6 +219,20 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	return c.observationStateHandler.HandleObservationState(serviceBinding, ctx, cr)
 }
 
+// observeMissing reports a missing binding as existing while its dependencies are not ready,
+// so that the reconciler waits for them instead of creating the binding. An observed-only
+// binding is never created, so it does not wait for its dependencies.
+func (c *external) observeMissing(ctx context.Context, cr *v1alpha1.ServiceCredentialBinding) managed.ExternalObservation {
+	if meta.WasDeleted(cr) || clients.IsObserveOnly(cr) {
+		return managed.ExternalObservation{ResourceExists: false}
+	}
+	if err := clients.CheckDependencies(ctx, c.reader, cr, cr.Spec.ForProvider.DependsOn); err != nil {
+		cr.SetConditions(clients.WaitingForDependencies(fmt.Errorf(errDependencies, err).Error()))
+		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}
+	}
+	return managed.ExternalObservation{ResourceExists: false}
+}
+
 // Create a ServiceCredentialBinding resource.
 func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
 	cr, ok := mg.(*v1alpha1.ServiceCredentialBinding)
@@ -167,6 +240,15 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalCreation{}, errors.New(errWrongCRType)
 	}
 
+	// If the last create failed, delete the failed binding observed in Observe before creating a new one
+	if scb.IsCreateFailed(cr.Status.AtProvider) {
+		clients.LoggerFrom(ctx).Debug("Deleting the failed binding before creating it again")
+		if err := scb.DeleteFailed(ctx, c.scbClient, cr.Status.AtProvider.GUID); err != nil {
+			return managed.ExternalCreation{}, fmt.Errorf(errCleanFailed, err)
+		}
+		cr.Status.AtProvider.LastOperation = nil
+	}
+
 	params, err := extractParameters(ctx, c.kube, cr.Spec.ForProvider)
 	if err != nil {
 		return managed.ExternalCreation{}, fmt.Errorf(errExtractParams, err)
@@ -178,6 +260,7 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	}
 
 	meta.SetExternalName(cr, serviceBinding.GUID)
+	clients.LoggerFrom(ctx).Debug("Created binding", "guid", serviceBinding.GUID)
 
 	if cr.ObjectMeta.Annotations != nil {
 		if _, ok := cr.ObjectMeta.Annotations[scb.ForceRotationKey]; ok {
@@ -195,6 +278,12 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, errors.New(errWrongCRType)
 	}
 
+	// CF rejects an update while the last operation is in progress
+	if op := cr.Status.AtProvider.LastOperation; op != nil && clients.IsOperationInProgress(op.State) {
+		clients.LoggerFrom(ctx).Debug("Deferring the update until the last operation completes")
+		return managed.ExternalUpdate{}, nil
+	}
+
 	if externalName := meta.GetExternalName(cr); externalName != "" {
 		if _, err := scb.Update(ctx, c.scbClient, meta.GetExternalName(cr), cr.Spec.ForProvider); err != nil {
 			return managed.ExternalUpdate{}, fmt.Errorf(errUpdate, err)
@@ -220,6 +309,7 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalDelete{}, errors.New(errWrongCRType)
 	}
 	cr.SetConditions(xpv1.Deleting())
+	clients.LoggerFrom(ctx).Debug("Deleting binding and its retired keys")
 
 	if err := c.keyRotator.DeleteRetiredKeys(ctx, cr); err != nil {
 		return managed.ExternalDelete{}, fmt.Errorf(errDeleteRetiredKeys, err)
@@ -230,9 +320,107 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalDelete{}, fmt.Errorf(errDelete, err)
 	}
 
+	if err := deleteConnectionSecret(ctx, c.kube, cr); err != nil {
+		return managed.ExternalDelete{}, fmt.Errorf(errDeleteSecret, err)
+	}
+
 	return managed.ExternalDelete{}, nil
 }
 
+// deleteConnectionSecret deletes the connection secret of a key binding, so
+// that its credentials do not outlive the binding until the secret is garbage
+// collected. A secret that is not controlled by the binding is left alone.
+func deleteConnectionSecret(ctx context.Context, kube k8s.Client, cr *v1alpha1.ServiceCredentialBinding) error {
+	ref := cr.GetWriteConnectionSecretToReference()
+	if ref == nil || cr.Spec.ForProvider.Type != "key" {
+		return nil
+	}
+
+	s := &corev1.Secret{}
+	if err := kube.Get(ctx, types.NamespacedName{Namespace: cr.GetNamespace(), Name: ref.Name}, s); err != nil {
+		return k8s.IgnoreNotFound(err)
+	}
+	if c := metav1.GetControllerOf(s); c == nil || c.UID != cr.GetUID() {
+		return nil
+	}
+	return k8s.IgnoreNotFound(kube.Delete(ctx, s))
+}
+
+// A guidInitializer rejects a serviceInstance or app that is set directly
+// to a value that is not a GUID, before it causes a confusing CF error.
+type guidInitializer struct{}
+
+// Initialize implements the managed.Initializer interface
+func (guidInitializer) Initialize(_ context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.ServiceCredentialBinding)
+	if !ok {
+		return errors.New(errWrongCRType)
+	}
+
+	p := cr.Spec.ForProvider
+	if p.ServiceInstanceRef == nil && p.ServiceInstanceSelector == nil {
+		if err := clients.ValidateGUID("serviceInstance", p.ServiceInstance); err != nil {
+			return err
+		}
+	}
+	if p.AppRef == nil && p.AppSelector == nil {
+		return clients.ValidateGUID("app", p.App)
+	}
+	return nil
+}
+
+// An appNameInitializer resolves the appName of an app binding to the GUID
+// of the app and persists it in app, so that the name is looked up only once.
+type appNameInitializer struct {
+	kube k8s.Client
+}
+
+// Initialize implements the managed.Initializer interface
+func (i appNameInitializer) Initialize(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.ServiceCredentialBinding)
+	if !ok {
+		return errors.New(errWrongCRType)
+	}
+
+	p := &cr.Spec.ForProvider
+	if p.AppName == nil || p.App != nil || p.AppRef != nil || p.AppSelector != nil {
+		return nil
+	}
+
+	cf, err := clients.ClientFnBuilder(ctx, i.kube)(mg)
+	if err != nil {
+		return fmt.Errorf(errNewClient, err)
+	}
+	spaceClient, _, orgClient := space.NewClient(cf)
+	if err := resolveAppName(ctx, cf.Applications, spaceClient, orgClient, p); err != nil {
+		return fmt.Errorf(errResolveAppName, err)
+	}
+	if err := i.kube.Update(ctx, cr); err != nil {
+		return fmt.Errorf(errUpdateCR, err)
+	}
+	return nil
+}
+
+// resolveAppName sets the app of the parameters to the GUID of the app named
+// appName in appSpace, or in the space named appSpaceName of appOrgName.
+func resolveAppName(ctx context.Context, appClient app.AppClient, spaceClient space.Space, orgClient org.Client, p *v1alpha1.ServiceCredentialBindingParameters) error {
+	spaceGUID := p.AppSpace
+	if spaceGUID == nil {
+		var err error
+		spaceGUID, err = space.GetGUID(ctx, orgClient, spaceClient, ptr.Deref(p.AppOrgName, ""), ptr.Deref(p.AppSpaceName, ""))
+		if err != nil {
+			return fmt.Errorf(errResolveAppSpace, err)
+		}
+	}
+
+	guid, err := app.GetGUID(ctx, appClient, *spaceGUID, *p.AppName)
+	if err != nil {
+		return err
+	}
+	p.App = guid
+	return nil
+}
+
 // extractParameters returns the parameters or credentials from the spec
 func extractParameters(ctx context.Context, kube k8s.Client, spec v1alpha1.ServiceCredentialBindingParameters) ([]byte, error) {
 	// If the spec has yaml parameters use those and only those.
@@ -249,13 +437,15 @@ func extractParameters(ctx context.Context, kube k8s.Client, spec v1alpha1.Servi
 }
 
 func (c *external) HandleObservationState(serviceBinding *cfresource.ServiceCredentialBinding, ctx context.Context, cr *v1alpha1.ServiceCredentialBinding) (managed.ExternalObservation, error) {
-	switch serviceBinding.LastOperation.State {
-	case v1alpha1.LastOperationInitial, v1alpha1.LastOperationInProgress:
+	if clients.IsOperationInProgress(serviceBinding.LastOperation.State) {
 		cr.SetConditions(xpv1.Unavailable().WithMessage(serviceBinding.LastOperation.Description))
 		return managed.ExternalObservation{
 			ResourceExists:   true,
 			ResourceUpToDate: true, // Do not update the resource while the last operation is in progress
 		}, nil
+	}
+
+	switch serviceBinding.LastOperation.State {
 	case v1alpha1.LastOperationFailed:
 		cr.SetConditions(xpv1.Unavailable().WithMessage(serviceBinding.LastOperation.Description))
 		return managed.ExternalObservation{
@@ -265,10 +455,22 @@ func (c *external) HandleObservationState(serviceBinding *cfresource.ServiceCred
 	case v1alpha1.LastOperationSucceeded:
 		cr.SetConditions(xpv1.Available())
 
+		details, err := scb.GetConnectionDetails(ctx, c.scbClient, serviceBinding.GUID, cr.Spec.ConnectionDetailsAsJSON, cr.Spec.ForProvider.ConnectionDetailTemplates)
+		if details != nil && cr.Spec.ForProvider.Type == "key" {
+			details[scb.ConnectionDetailBindingGUID] = []byte(serviceBinding.GUID)
+		}
+		if details != nil && len(cr.Spec.ForProvider.ConnectionDetailTemplates) > 0 {
+			if err != nil {
+				cr.SetConditions(scb.TemplatesMissingField(err))
+			} else {
+				cr.SetConditions(scb.TemplatesRendered())
+			}
+		}
+
 		return managed.ExternalObservation{
 			ResourceExists:    true,
 			ResourceUpToDate:  scb.IsUpToDate(ctx, cr.Spec.ForProvider, *serviceBinding) && !c.keyRotator.HasExpiredKeys(cr),
-			ConnectionDetails: scb.GetConnectionDetails(ctx, c.scbClient, serviceBinding.GUID, cr.Spec.ConnectionDetailsAsJSON),
+			ConnectionDetails: details,
 		}, nil
 	}
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/url"
 	"testing"
 	"time"
 
@@ -13,13 +14,20 @@ import (
 	k8s "sigs.k8s.io/controller-runtime/pkg/client"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 
 	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/fake"
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/servicecredentialbinding"
 )
@@ -31,8 +39,16 @@ var (
 	name                      = "my-service-credential-binding"
 	guid                      = "2d8b0d04-d537-4e4e-8c6f-f09ca0e7f56f"
 	serviceInstanceGUID       = "3d8b0d04-d537-4e4e-8c6f-f09ca0e7f56f"
+	failedGUID                = "4d8b0d04-d537-4e4e-8c6f-f09ca0e7f56f"
 )
 
+// timeoutError is a net.Error that reports a timeout of the http client.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
 // MockObservationStateHandler is a mock implementation of ObservationStateHandler
 type MockObservationStateHandler struct {
 	mock.Mock
@@ -57,6 +73,36 @@ func withServiceInstanceID(guid string) modifier {
 	}
 }
 
+func withDependsOn(kind, name string) modifier {
+	return func(r *v1alpha1.ServiceCredentialBinding) {
+		r.Spec.ForProvider.DependsOn = append(r.Spec.ForProvider.DependsOn, v1alpha1.Dependency{Kind: kind, Name: name})
+	}
+}
+
+func withConnectionDetailTemplates(t map[string]string) modifier {
+	return func(r *v1alpha1.ServiceCredentialBinding) {
+		r.Spec.ForProvider.ConnectionDetailTemplates = t
+	}
+}
+
+func withConnectionSecret(name string) modifier {
+	return func(r *v1alpha1.ServiceCredentialBinding) {
+		r.Spec.WriteConnectionSecretToReference = &xpv1.LocalSecretReference{Name: name}
+	}
+}
+
+func withUID(uid types.UID) modifier {
+	return func(r *v1alpha1.ServiceCredentialBinding) {
+		r.ObjectMeta.UID = uid
+	}
+}
+
+func withObserveOnly() modifier {
+	return func(r *v1alpha1.ServiceCredentialBinding) {
+		r.Spec.ManagementPolicies = xpv1.ManagementPolicies{xpv1.ManagementActionObserve}
+	}
+}
+
 func withConditions(c ...xpv1.Condition) modifier {
 	return func(i *v1alpha1.ServiceCredentialBinding) { i.Status.SetConditions(c...) }
 }
@@ -70,6 +116,16 @@ func withStatus(guid string) modifier {
 	}
 }
 
+func withFailedCreate(guid string) modifier {
+	return func(r *v1alpha1.ServiceCredentialBinding) {
+		r.Status.AtProvider.GUID = guid
+		r.Status.AtProvider.LastOperation = &v1alpha1.LastOperation{
+			Type:  v1alpha1.LastOperationCreate,
+			State: v1alpha1.LastOperationFailed,
+		}
+	}
+}
+
 func serviceCredentialBinding(typ string, m ...modifier) *v1alpha1.ServiceCredentialBinding {
 	r := &v1alpha1.ServiceCredentialBinding{
 		ObjectMeta: metav1.ObjectMeta{
@@ -307,6 +363,7 @@ func TestObserve(t *testing.T) {
 				obsHandler = tc.observationStateHandler()
 			}
 			c := &external{
+				recorder: event.NewNopRecorder(),
 				kube: &test.MockClient{
 					MockUpdate: test.NewMockUpdateFn(nil),
 				},
@@ -333,6 +390,84 @@ func TestObserve(t *testing.T) {
 	}
 }
 
+// TestObserveAdopt tests that a binding without external name adopts the single binding matching its spec.
+func TestObserveAdopt(t *testing.T) {
+	cfSucceeded := func() *cfresource.ServiceCredentialBinding {
+		return &fake.NewServiceCredentialBinding("key").SetName(name).SetGUID(guid).SetServiceInstanceRef(serviceInstanceGUID).SetLastOperation(v1alpha1.LastOperationCreate, v1alpha1.LastOperationSucceeded).ServiceCredentialBinding
+	}
+
+	type want struct {
+		externalName string
+		obs          managed.ExternalObservation
+		err          error
+	}
+
+	cases := map[string]struct {
+		matches []*cfresource.ServiceCredentialBinding
+		want    want
+	}{
+		"SingleMatch": {
+			matches: []*cfresource.ServiceCredentialBinding{cfSucceeded()},
+			want: want{
+				externalName: guid,
+				obs:          managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"NoMatch": {
+			matches: []*cfresource.ServiceCredentialBinding{},
+			want: want{
+				obs: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"MultipleMatches": {
+			matches: []*cfresource.ServiceCredentialBinding{cfSucceeded(), cfSucceeded()},
+			want: want{
+				err: fmt.Errorf(errGet, errors.New(servicecredentialbinding.ErrAmbiguousBinding+guid+", "+guid)),
+			},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			m := &fake.MockServiceCredentialBinding{}
+			m.On("List", mock.Anything, mock.Anything).Return(tc.matches, nil)
+			kr := &fake.MockKeyRotator{}
+			kr.On("RetireBinding", mock.Anything, mock.Anything).Return(false)
+			h := &MockObservationStateHandler{}
+			h.On("HandleObservationState", mock.Anything, mock.Anything, mock.Anything).Return(
+				managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				nil,
+			)
+			c := &external{
+				recorder: event.NewNopRecorder(),
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				reader:                  &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+				scbClient:               m,
+				keyRotator:              kr,
+				observationStateHandler: h,
+			}
+			cr := serviceCredentialBinding("key", withServiceInstanceID(serviceInstanceGUID))
+			obs, err := c.Observe(context.Background(), cr)
+
+			if tc.want.err != nil && err != nil {
+				if diff := cmp.Diff(tc.want.err.Error(), err.Error()); diff != "" {
+					t.Errorf("Observe(...): want error string != got error string:\n%s", diff)
+				}
+			} else if diff := cmp.Diff(tc.want.err, err); diff != "" {
+				t.Errorf("Observe(...): want error != got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.obs, obs); diff != "" {
+				t.Errorf("Observe(...): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.externalName, meta.GetExternalName(cr)); diff != "" {
+				t.Errorf("Observe(...): external name -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestUpdate(t *testing.T) {
 	type service func() *fake.MockServiceCredentialBinding
 	type keyRotator func() *fake.MockKeyRotator
@@ -354,6 +489,9 @@ func TestUpdate(t *testing.T) {
 	mgWithRetiredKeys := serviceCredentialBinding("key", withServiceInstanceID(serviceInstanceGUID), withExternalName(guid), withStatus(guid))
 	mgWithRetiredKeys.Status.AtProvider.RetiredKeys = []*v1alpha1.SCBResource{retiredKey1}
 
+	mgInProgress := mgWithRetiredKeys.DeepCopy()
+	mgInProgress.Status.AtProvider.LastOperation = &v1alpha1.LastOperation{Type: v1alpha1.LastOperationCreate, State: v1alpha1.LastOperationInProgress}
+
 	cases := map[string]struct {
 		args       args
 		want       want
@@ -400,6 +538,23 @@ func TestUpdate(t *testing.T) {
 				return m
 			},
 		},
+		"OperationInProgress": {
+			args: args{
+				mg: mgInProgress.DeepCopy(),
+			},
+			want: want{
+				mg:  mgInProgress.DeepCopy(),
+				obs: managed.ExternalUpdate{},
+				err: nil,
+			},
+			service: func() *fake.MockServiceCredentialBinding {
+				// no CF call is issued while the last operation is in progress
+				return &fake.MockServiceCredentialBinding{}
+			},
+			keyRotator: func() *fake.MockKeyRotator {
+				return &fake.MockKeyRotator{}
+			},
+		},
 		"UpdateFailed": {
 			args: args{
 				mg: serviceCredentialBinding("key", withServiceInstanceID(serviceInstanceGUID), withExternalName(guid)),
@@ -480,6 +635,7 @@ func TestUpdate(t *testing.T) {
 		t.Run(n, func(t *testing.T) {
 			t.Logf("Testing: %s", t.Name())
 			c := &external{
+				recorder: event.NewNopRecorder(),
 				kube: &test.MockClient{
 					MockUpdate:       test.NewMockUpdateFn(nil),
 					MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
@@ -569,8 +725,9 @@ func TestHandleObservationState(t *testing.T) {
 	}
 
 	type want struct {
-		obs managed.ExternalObservation
-		err error
+		obs       managed.ExternalObservation
+		err       error
+		templates *xpv1.Condition
 	}
 
 	ctx := context.Background()
@@ -650,11 +807,54 @@ func TestHandleObservationState(t *testing.T) {
 				obs: managed.ExternalObservation{
 					ResourceExists:    true,
 					ResourceUpToDate:  true, // Assuming IsUpToDate returns true and no expired keys
-					ConnectionDetails: managed.ConnectionDetails{},
+					ConnectionDetails: managed.ConnectionDetails{servicecredentialbinding.ConnectionDetailBindingGUID: []byte(guid)},
 				},
 				err: nil,
 			},
 		},
+		"RotatedBinding": {
+			args: args{
+				serviceBinding: &fake.NewServiceCredentialBinding("key").SetName(name).SetGUID(failedGUID).SetServiceInstanceRef(serviceInstanceGUID).SetLastOperation(v1alpha1.LastOperationCreate, v1alpha1.LastOperationSucceeded).ServiceCredentialBinding,
+				ctx:            ctx,
+				cr:             cr.DeepCopy(),
+			},
+			want: want{
+				obs: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: managed.ConnectionDetails{servicecredentialbinding.ConnectionDetailBindingGUID: []byte(failedGUID)},
+				},
+			},
+		},
+		"AppBindingWithoutBindingGUID": {
+			args: args{
+				serviceBinding: &fake.NewServiceCredentialBinding("app").SetName(name).SetGUID(guid).SetServiceInstanceRef(serviceInstanceGUID).SetLastOperation(v1alpha1.LastOperationCreate, v1alpha1.LastOperationSucceeded).ServiceCredentialBinding,
+				ctx:            ctx,
+				cr:             serviceCredentialBinding("app", withExternalName(guid), withServiceInstanceID(serviceInstanceGUID)),
+			},
+			want: want{
+				obs: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"TemplateMissingField": {
+			args: args{
+				serviceBinding: scbCreate(v1alpha1.LastOperationSucceeded),
+				ctx:            ctx,
+				cr:             serviceCredentialBinding("key", withExternalName(guid), withServiceInstanceID(serviceInstanceGUID), withConnectionDetailTemplates(map[string]string{"url": "https://{host}"})),
+			},
+			want: want{
+				obs: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: managed.ConnectionDetails{servicecredentialbinding.ConnectionDetailBindingGUID: []byte(guid)},
+				},
+				templates: ptr.To(servicecredentialbinding.TemplatesMissingField(errors.New("cannot render connection detail templates: url (missing host)"))),
+			},
+		},
 		"UnknownState": {
 			args: args{
 				serviceBinding: &cfresource.ServiceCredentialBinding{
@@ -679,6 +879,7 @@ func TestHandleObservationState(t *testing.T) {
 
 			// Create external with mocked dependencies
 			c := &external{
+				recorder:   event.NewNopRecorder(),
 				scbClient:  &fake.MockServiceCredentialBinding{},
 				keyRotator: &fake.MockKeyRotator{},
 			}
@@ -686,8 +887,8 @@ func TestHandleObservationState(t *testing.T) {
 			// Set up mocks for the successful case
 			if tc.args.serviceBinding.LastOperation.State == v1alpha1.LastOperationSucceeded {
 				mockSCB := c.scbClient.(*fake.MockServiceCredentialBinding)
-				mockSCB.On("GetDetails", mock.Anything, guid).Return(
-					fake.NewServiceCredentialBindingDetails(guid),
+				mockSCB.On("GetDetails", mock.Anything, tc.args.serviceBinding.GUID).Return(
+					fake.NewServiceCredentialBindingDetails(tc.args.serviceBinding.GUID),
 					nil,
 				)
 
@@ -709,10 +910,66 @@ func TestHandleObservationState(t *testing.T) {
 			if diff := cmp.Diff(tc.want.obs, obs); diff != "" {
 				t.Errorf("HandleObservationState(...): -want, +got:\n%s", diff)
 			}
+			if tc.want.templates != nil {
+				if got := tc.args.cr.GetCondition(servicecredentialbinding.TypeConnectionDetailTemplates); !got.Equal(*tc.want.templates) {
+					t.Errorf("HandleObservationState(...): want condition %v, got %v", *tc.want.templates, got)
+				}
+			}
 		})
 	}
 }
 
+func TestObserveDependencies(t *testing.T) {
+	notReady := func(_ context.Context, _ k8s.ObjectKey, obj k8s.Object) error {
+		// the service instance exists but is not ready yet
+		obj.(*unstructured.Unstructured).Object["status"] = map[string]any{
+			"conditions": []any{map[string]any{"type": "Ready", "status": "False"}},
+		}
+		return nil
+	}
+
+	m := &fake.MockServiceCredentialBinding{}
+	m.On("List", mock.Anything, mock.Anything).Return([]*cfresource.ServiceCredentialBinding{}, nil)
+	c := &external{
+		recorder:  event.NewNopRecorder(),
+		reader:    &test.MockClient{MockGet: notReady},
+		scbClient: m,
+	}
+
+	cr := serviceCredentialBinding("key", withServiceInstanceID(serviceInstanceGUID), withDependsOn("ServiceInstance", "my-si"))
+	obs, err := c.Observe(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("Observe(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, obs); diff != "" {
+		t.Errorf("Observe(...): -want, +got:\n%s", diff)
+	}
+	want := clients.WaitingForDependencies(`waiting for dependencies of ServiceCredentialBinding: dependency ServiceInstance "my-si" is not ready`)
+	if diff := cmp.Diff(want, cr.GetCondition(xpv1.TypeReady), test.EquateConditions()); diff != "" {
+		t.Errorf("Observe(...): -want condition, +got:\n%s", diff)
+	}
+	m.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestObserveOnlyIgnoresDependencies(t *testing.T) {
+	m := &fake.MockServiceCredentialBinding{}
+	m.On("List", mock.Anything, mock.Anything).Return([]*cfresource.ServiceCredentialBinding{}, nil)
+	c := &external{
+		recorder:  event.NewNopRecorder(),
+		reader:    &test.MockClient{MockGet: test.NewMockGetFn(errors.New("no dependency must be fetched"))},
+		scbClient: m,
+	}
+
+	cr := serviceCredentialBinding("key", withServiceInstanceID(serviceInstanceGUID), withDependsOn("ServiceInstance", "my-si"), withObserveOnly())
+	obs, err := c.Observe(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("Observe(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(managed.ExternalObservation{ResourceExists: false}, obs); diff != "" {
+		t.Errorf("Observe(...): -want, +got:\n%s", diff)
+	}
+}
+
 func TestCreate(t *testing.T) {
 	type service func() *fake.MockServiceCredentialBinding
 	type args struct {
@@ -767,6 +1024,52 @@ func TestCreate(t *testing.T) {
 				return m
 			},
 		},
+		"CleanupFailedBindingBeforeRecreate": {
+			args: args{
+				mg: serviceCredentialBinding("key", withServiceInstanceID(serviceInstanceGUID), withExternalName(failedGUID), withFailedCreate(failedGUID)),
+			},
+			want: want{
+				mg: serviceCredentialBinding(
+					"key",
+					withExternalName(guid),
+					withServiceInstanceID(serviceInstanceGUID),
+					withStatus(failedGUID),
+				),
+				obs: managed.ExternalCreation{},
+				err: nil,
+			},
+			service: func() *fake.MockServiceCredentialBinding {
+				m := &fake.MockServiceCredentialBinding{}
+				del := m.On("Delete", mock.Anything, failedGUID).Return("JOB", nil).Once()
+				m.On("PollComplete", mock.Anything, "JOB", mock.Anything).Return(nil).NotBefore(del)
+				m.On("Create", mock.Anything, mock.Anything).Return(
+					guid,
+					scbKey(),
+					nil,
+				).NotBefore(del)
+				m.On("Single", mock.Anything, mock.Anything).Return(
+					scbKey(),
+					nil,
+				)
+				m.On("PollComplete", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+				return m
+			},
+		},
+		"CleanupFailedBindingError": {
+			args: args{
+				mg: serviceCredentialBinding("key", withServiceInstanceID(serviceInstanceGUID), withExternalName(failedGUID), withFailedCreate(failedGUID)),
+			},
+			want: want{
+				mg:  serviceCredentialBinding("key", withServiceInstanceID(serviceInstanceGUID), withExternalName(failedGUID), withFailedCreate(failedGUID)),
+				obs: managed.ExternalCreation{},
+				err: fmt.Errorf(errCleanFailed, errBoom),
+			},
+			service: func() *fake.MockServiceCredentialBinding {
+				m := &fake.MockServiceCredentialBinding{}
+				m.On("Delete", mock.Anything, failedGUID).Return("", errBoom)
+				return m
+			},
+		},
 		"Should fail if Service Instance is missing": {
 			args: args{
 				mg: serviceCredentialBinding("key"),
@@ -852,6 +1155,41 @@ func TestCreate(t *testing.T) {
 				return m
 			},
 		},
+		"PollTimeout": {
+			args: args{
+				mg: serviceCredentialBinding("key", withServiceInstanceID(serviceInstanceGUID)),
+			},
+			want: want{
+				mg: serviceCredentialBinding(
+					"key",
+					withExternalName(guid),
+					withServiceInstanceID(serviceInstanceGUID),
+				),
+				obs: managed.ExternalCreation{},
+				err: nil,
+			},
+			service: func() *fake.MockServiceCredentialBinding {
+				m := &fake.MockServiceCredentialBinding{}
+
+				m.On("Create", mock.Anything, mock.Anything).Return(
+					"JOB",
+					&cfresource.ServiceCredentialBinding{},
+					nil,
+				)
+
+				m.On("Single", mock.Anything, mock.Anything).Return(
+					&fake.NewServiceCredentialBinding("key").SetName(name).SetGUID(guid).SetServiceInstanceRef(serviceInstanceGUID).SetLastOperation(v1alpha1.LastOperationCreate, v1alpha1.LastOperationInProgress).ServiceCredentialBinding,
+					nil,
+				)
+				m.On("PollComplete", mock.Anything, "JOB", mock.Anything).Return(&url.Error{
+					Op:  "Get",
+					URL: "https://api.cf.example.com/v3/jobs/JOB",
+					Err: timeoutError{},
+				})
+
+				return m
+			},
+		},
 		"AlreadyExist": {
 			args: args{
 				mg: serviceCredentialBinding("key", withServiceInstanceID(serviceInstanceGUID)),
@@ -890,12 +1228,16 @@ func TestCreate(t *testing.T) {
 		t.Run(n, func(t *testing.T) {
 			t.Logf("Testing: %s", t.Name())
 			c := &external{
+				recorder: event.NewNopRecorder(),
 				kube: &test.MockClient{
 					MockUpdate:       test.NewMockUpdateFn(nil),
 					MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
 				},
 				scbClient: tc.service(),
 			}
+			if tc.kube != nil {
+				c.kube = tc.kube
+			}
 			obs, err := c.Create(context.Background(), tc.args.mg)
 
 			if tc.want.err != nil && err != nil {
@@ -932,13 +1274,79 @@ func TestDelete(t *testing.T) {
 
 	mgArg := serviceCredentialBinding("key", withServiceInstanceID(serviceInstanceGUID), withExternalName(guid), withStatus(guid))
 	mgWant := serviceCredentialBinding("key", withServiceInstanceID(serviceInstanceGUID), withExternalName(guid), withStatus(guid), withConditions(xpv1.Deleting()))
+	withSecret := []modifier{withServiceInstanceID(serviceInstanceGUID), withExternalName(guid), withStatus(guid), withUID("scb-uid"), withConnectionSecret("creds")}
+
+	deleted := func() *fake.MockServiceCredentialBinding {
+		m := &fake.MockServiceCredentialBinding{}
+		m.On("Delete", mock.Anything, guid).Return(guid, nil)
+		return m
+	}
+	noRetiredKeys := func() *fake.MockKeyRotator {
+		m := &fake.MockKeyRotator{}
+		m.On("DeleteRetiredKeys", mock.Anything, mock.Anything).Return(nil)
+		return m
+	}
+	// secretOwnedBy returns a MockGetFn that gets a connection secret controlled by uid.
+	secretOwnedBy := func(uid types.UID) test.MockGetFn {
+		return func(_ context.Context, _ k8s.ObjectKey, obj k8s.Object) error {
+			obj.SetOwnerReferences([]metav1.OwnerReference{{UID: uid, Controller: ptr.To(true)}})
+			return nil
+		}
+	}
 
 	cases := map[string]struct {
 		args       args
 		want       want
 		service    service
 		keyRotator keyRotator
+		kube       k8s.Client
 	}{
+		"DeletesConnectionSecret": {
+			args: args{
+				mg: serviceCredentialBinding("key", withSecret...),
+			},
+			want: want{
+				mg: serviceCredentialBinding("key", append(withSecret, withConditions(xpv1.Deleting()))...),
+			},
+			service:    deleted,
+			keyRotator: noRetiredKeys,
+			kube:       &test.MockClient{MockGet: secretOwnedBy("scb-uid"), MockDelete: test.NewMockDeleteFn(nil)},
+		},
+		"DeleteConnectionSecretFailed": {
+			args: args{
+				mg: serviceCredentialBinding("key", withSecret...),
+			},
+			want: want{
+				mg:  serviceCredentialBinding("key", append(withSecret, withConditions(xpv1.Deleting()))...),
+				err: fmt.Errorf(errDeleteSecret, errBoom),
+			},
+			service:    deleted,
+			keyRotator: noRetiredKeys,
+			kube:       &test.MockClient{MockGet: secretOwnedBy("scb-uid"), MockDelete: test.NewMockDeleteFn(errBoom)},
+		},
+		"KeepsConnectionSecretOfOtherOwner": {
+			args: args{
+				mg: serviceCredentialBinding("key", withSecret...),
+			},
+			want: want{
+				mg: serviceCredentialBinding("key", append(withSecret, withConditions(xpv1.Deleting()))...),
+			},
+			service:    deleted,
+			keyRotator: noRetiredKeys,
+			// deleting the secret would fail the test
+			kube: &test.MockClient{MockGet: secretOwnedBy("other-uid"), MockDelete: test.NewMockDeleteFn(errBoom)},
+		},
+		"ConnectionSecretNotFound": {
+			args: args{
+				mg: serviceCredentialBinding("key", withSecret...),
+			},
+			want: want{
+				mg: serviceCredentialBinding("key", append(withSecret, withConditions(xpv1.Deleting()))...),
+			},
+			service:    deleted,
+			keyRotator: noRetiredKeys,
+			kube:       &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, "creds"))},
+		},
 		"Successful": {
 			args: args{
 				mg: mgArg.DeepCopy(),
@@ -1027,11 +1435,16 @@ func TestDelete(t *testing.T) {
 	for n, tc := range cases {
 		t.Run(n, func(t *testing.T) {
 			t.Logf("Testing: %s", t.Name())
-			c := &external{
-				kube: &test.MockClient{
+			kube := tc.kube
+			if kube == nil {
+				kube = &test.MockClient{
 					MockUpdate:       test.NewMockUpdateFn(nil),
 					MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
-				},
+				}
+			}
+			c := &external{
+				recorder:   event.NewNopRecorder(),
+				kube:       kube,
 				scbClient:  tc.service(),
 				keyRotator: tc.keyRotator(),
 			}
@@ -1053,3 +1466,140 @@ func TestDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestGUIDInitializer(t *testing.T) {
+	withoutServiceInstanceRef := func(r *v1alpha1.ServiceCredentialBinding) {
+		r.Spec.ForProvider.ServiceInstanceRef = nil
+	}
+	withApp := func(app string) modifier {
+		return func(r *v1alpha1.ServiceCredentialBinding) {
+			r.Spec.ForProvider.App = &app
+		}
+	}
+
+	cases := map[string]struct {
+		mg   resource.Managed
+		want error
+	}{
+		"WrongCRType": {
+			mg:   nil,
+			want: errors.New(errWrongCRType),
+		},
+		"ValidGUIDs": {
+			mg:   serviceCredentialBinding("app", withoutServiceInstanceRef, withServiceInstanceID(serviceInstanceGUID), withApp(guid)),
+			want: nil,
+		},
+		"MalformedServiceInstance": {
+			mg:   serviceCredentialBinding("key", withoutServiceInstanceRef, withServiceInstanceID("my-service-instance")),
+			want: errors.New(`serviceInstance "my-service-instance" is not a valid GUID`),
+		},
+		"MalformedApp": {
+			mg:   serviceCredentialBinding("app", withoutServiceInstanceRef, withServiceInstanceID(serviceInstanceGUID), withApp("2d8b0d04d5374e4e8c6ff09ca0e7f56")),
+			want: errors.New(`app "2d8b0d04d5374e4e8c6ff09ca0e7f56" is not a valid GUID`),
+		},
+		"ResolvedByReference": {
+			// the reference is resolved after the initializer, so the value is not validated
+			mg:   serviceCredentialBinding("key", withServiceInstanceID("my-service-instance")),
+			want: nil,
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			err := guidInitializer{}.Initialize(context.Background(), tc.mg)
+			if tc.want != nil && err != nil {
+				if diff := cmp.Diff(tc.want.Error(), err.Error()); diff != "" {
+					t.Errorf("Initialize(...): want error string != got error string:\n%s", diff)
+				}
+			} else if diff := cmp.Diff(tc.want, err); diff != "" {
+				t.Errorf("Initialize(...): want error != got error:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestResolveAppName(t *testing.T) {
+	spaceGUID := "5d8b0d04-d537-4e4e-8c6f-f09ca0e7f56f"
+
+	cases := map[string]struct {
+		params  v1alpha1.ServiceCredentialBindingParameters
+		apps    []*cfresource.App
+		appsErr error
+		space   *cfresource.Space
+		wantApp *string
+		wantErr string
+	}{
+		"BySpaceGUID": {
+			params:  v1alpha1.ServiceCredentialBindingParameters{AppName: ptr.To("my-app"), AppSpace: ptr.To(spaceGUID)},
+			apps:    []*cfresource.App{{Resource: cfresource.Resource{GUID: guid}}},
+			wantApp: ptr.To(guid),
+		},
+		"BySpaceName": {
+			params:  v1alpha1.ServiceCredentialBindingParameters{AppName: ptr.To("my-app"), AppSpaceName: ptr.To("dev"), AppOrgName: ptr.To("org")},
+			apps:    []*cfresource.App{{Resource: cfresource.Resource{GUID: guid}}},
+			space:   &cfresource.Space{Resource: cfresource.Resource{GUID: spaceGUID}},
+			wantApp: ptr.To(guid),
+		},
+		"NotFound": {
+			params:  v1alpha1.ServiceCredentialBindingParameters{AppName: ptr.To("my-app"), AppSpace: ptr.To(spaceGUID)},
+			apps:    []*cfresource.App{},
+			wantErr: `cannot find app "my-app" in space ` + spaceGUID,
+		},
+		"Ambiguous": {
+			params:  v1alpha1.ServiceCredentialBindingParameters{AppName: ptr.To("my-app"), AppSpace: ptr.To(spaceGUID)},
+			apps:    []*cfresource.App{{Resource: cfresource.Resource{GUID: guid}}, {Resource: cfresource.Resource{GUID: failedGUID}}},
+			wantErr: `app name "my-app" is ambiguous in space ` + spaceGUID,
+		},
+		"ListFails": {
+			params:  v1alpha1.ServiceCredentialBindingParameters{AppName: ptr.To("my-app"), AppSpace: ptr.To(spaceGUID)},
+			apps:    []*cfresource.App{},
+			appsErr: errBoom,
+			wantErr: errBoom.Error(),
+		},
+		"SpaceNotFound": {
+			params:  v1alpha1.ServiceCredentialBindingParameters{AppName: ptr.To("my-app"), AppSpaceName: ptr.To("dev")},
+			wantErr: "cannot resolve space of app: " + errBoom.Error(),
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			apps := &fake.MockApp{}
+			apps.On("ListAll").Return(tc.apps, tc.appsErr)
+			spaces := &fake.MockSpace{}
+			if tc.space != nil {
+				spaces.On("Single").Return(tc.space, nil)
+			} else {
+				spaces.On("Single").Return(&cfresource.Space{}, errBoom)
+			}
+			orgs := &fake.MockOrganization{}
+			orgs.On("Single").Return(&cfresource.Organization{Resource: cfresource.Resource{GUID: "org-guid"}}, nil)
+
+			err := resolveAppName(context.Background(), apps, spaces, orgs, &tc.params)
+			got := ""
+			if err != nil {
+				got = err.Error()
+			}
+			if diff := cmp.Diff(tc.wantErr, got); diff != "" {
+				t.Errorf("resolveAppName(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantApp, tc.params.App); diff != "" {
+				t.Errorf("resolveAppName(...): -want app, +got app:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAppNameInitializerResolvedOnce(t *testing.T) {
+	// an app that is already resolved is not looked up again, so no client is needed
+	mg := serviceCredentialBinding("app", withServiceInstanceID(serviceInstanceGUID))
+	mg.Spec.ForProvider.AppName = ptr.To("my-app")
+	mg.Spec.ForProvider.App = ptr.To(guid)
+
+	if err := (appNameInitializer{}).Initialize(context.Background(), mg); err != nil {
+		t.Errorf("Initialize(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(ptr.To(guid), mg.Spec.ForProvider.App); diff != "" {
+		t.Errorf("Initialize(...): -want app, +got app:\n%s", diff)
+	}
+}
@@ -0,0 +1,124 @@
+package servicecredentialbinding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	k8s "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	apisv1beta1 "github.com/SAP/crossplane-provider-cloudfoundry/apis/v1beta1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
+	scb "github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/servicecredentialbinding"
+)
+
+const (
+	// DefaultRetiredKeyGCInterval is the default pause between two collections of orphaned retired keys.
+	DefaultRetiredKeyGCInterval = time.Hour
+	// DefaultRetiredKeyRetention is the default time an orphaned retired key is kept before it is deleted.
+	DefaultRetiredKeyRetention = 24 * time.Hour
+
+	errListBindings        = "cannot list " + resourceType + "s: %w"
+	errListProviderConfigs = "cannot list ProviderConfigs: %w"
+	errListRetired         = "cannot list retired keys in " + externalSystem + " with ProviderConfig %s/%s: %w"
+	errDeleteOrphanedKey   = "cannot delete orphaned retired key %s: %w"
+)
+
+var (
+	// RetiredKeyGC enables the periodic deletion of retired keys whose
+	// ServiceCredentialBinding was removed without deleting them.
+	RetiredKeyGC = false
+	// RetiredKeyGCInterval is the pause between two collections of orphaned retired keys.
+	RetiredKeyGCInterval = DefaultRetiredKeyGCInterval
+	// RetiredKeyRetention is how long a retired key is kept after it was
+	// retired before it is deleted as an orphan.
+	RetiredKeyRetention = DefaultRetiredKeyRetention
+)
+
+// retiredKeyCollector deletes the retired keys of ServiceCredentialBindings
+// that no longer exist, e.g. because they were deleted without their finalizer
+// completing. Only keys labeled by scb.LabelRetired are considered.
+type retiredKeyCollector struct {
+	kube      k8s.Client
+	log       logging.Logger
+	interval  time.Duration
+	retention time.Duration
+	newClient func(ctx context.Context, pc *apisv1beta1.ProviderConfig) (scb.ServiceCredentialBinding, error)
+}
+
+func newRetiredKeyCollector(kube k8s.Client, log logging.Logger) *retiredKeyCollector {
+	return &retiredKeyCollector{
+		kube:      kube,
+		log:       log,
+		interval:  RetiredKeyGCInterval,
+		retention: RetiredKeyRetention,
+		newClient: func(ctx context.Context, pc *apisv1beta1.ProviderConfig) (scb.ServiceCredentialBinding, error) {
+			cf, err := clients.ClientForProviderConfig(ctx, kube, pc)
+			if err != nil {
+				return nil, err
+			}
+			return scb.NewClient(cf), nil
+		},
+	}
+}
+
+// Start implements manager.Runnable. It collects orphaned retired keys every
+// interval until ctx is done.
+func (c *retiredKeyCollector) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := c.collect(ctx, time.Now()); err != nil {
+			c.log.Info("Cannot collect orphaned retired keys", "error", err)
+		}
+	}, c.interval)
+	return nil
+}
+
+// collect deletes the orphaned retired keys retired with each ProviderConfig.
+// A ProviderConfig that fails does not stop the collection with the others,
+// their errors are returned together.
+func (c *retiredKeyCollector) collect(ctx context.Context, now time.Time) error {
+	bindings := &v1alpha1.ServiceCredentialBindingList{}
+	if err := c.kube.List(ctx, bindings); err != nil {
+		return fmt.Errorf(errListBindings, err)
+	}
+	owners := make(map[types.UID]bool, len(bindings.Items))
+	for _, b := range bindings.Items {
+		owners[b.GetUID()] = true
+	}
+
+	pcs := &apisv1beta1.ProviderConfigList{}
+	if err := c.kube.List(ctx, pcs); err != nil {
+		return fmt.Errorf(errListProviderConfigs, err)
+	}
+	var errs []error
+	for i := range pcs.Items {
+		errs = append(errs, c.collectProviderConfig(ctx, &pcs.Items[i], owners, now)...)
+	}
+	return errors.Join(errs...)
+}
+
+// collectProviderConfig deletes the orphaned retired keys retired with pc.
+func (c *retiredKeyCollector) collectProviderConfig(ctx context.Context, pc *apisv1beta1.ProviderConfig, owners map[types.UID]bool, now time.Time) []error {
+	client, err := c.newClient(ctx, pc)
+	if err != nil {
+		return []error{fmt.Errorf(errNewClient, err)}
+	}
+	keys, err := scb.ListRetired(ctx, client, pc.GetUID())
+	if err != nil {
+		return []error{fmt.Errorf(errListRetired, pc.Namespace, pc.Name, err)}
+	}
+	var errs []error
+	for _, guid := range scb.OrphanedKeys(keys, pc.GetUID(), owners, c.retention, now) {
+		if err := scb.Delete(ctx, client, guid); err != nil && !clients.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf(errDeleteOrphanedKey, guid, err))
+			continue
+		}
+		c.log.Info("Deleted orphaned retired key", "guid", guid)
+	}
+	return errs
+}
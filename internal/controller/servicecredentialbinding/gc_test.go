@@ -0,0 +1,176 @@
+package servicecredentialbinding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	cfresource "github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/mock"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	k8s "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	apisv1beta1 "github.com/SAP/crossplane-provider-cloudfoundry/apis/v1beta1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/fake"
+	scb "github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/servicecredentialbinding"
+)
+
+const providerConfigUID = "provider-config"
+
+// withBindingsAndConfigs returns a MockListFn that lists bindings with the given UIDs and a single ProviderConfig.
+func withBindingsAndConfigs(uids ...types.UID) test.MockListFn {
+	return withBindingsAndNamedConfigs(uids, "default")
+}
+
+// withBindingsAndNamedConfigs returns a MockListFn that lists bindings with the given UIDs and a ProviderConfig per name,
+// whose UID is providerConfigUID for the first one and its name for the others.
+func withBindingsAndNamedConfigs(uids []types.UID, names ...string) test.MockListFn {
+	return func(_ context.Context, obj k8s.ObjectList, _ ...k8s.ListOption) error {
+		switch l := obj.(type) {
+		case *v1alpha1.ServiceCredentialBindingList:
+			for _, uid := range uids {
+				b := v1alpha1.ServiceCredentialBinding{}
+				b.SetUID(uid)
+				l.Items = append(l.Items, b)
+			}
+		case *apisv1beta1.ProviderConfigList:
+			for i, name := range names {
+				pc := apisv1beta1.ProviderConfig{}
+				pc.SetNamespace("default")
+				pc.SetName(name)
+				pc.SetUID(types.UID(name))
+				if i == 0 {
+					pc.SetUID(providerConfigUID)
+				}
+				l.Items = append(l.Items, pc)
+			}
+		}
+		return nil
+	}
+}
+
+func retiredBinding(guid string, owner types.UID, retiredAt time.Time) *cfresource.ServiceCredentialBinding {
+	b := &cfresource.ServiceCredentialBinding{Metadata: &cfresource.Metadata{Labels: map[string]*string{
+		scb.RetiredLabel:           ptr.To("true"),
+		scb.RetiredAtLabel:         ptr.To(strconv.FormatInt(retiredAt.Unix(), 10)),
+		scb.OwnerUIDLabel:          ptr.To(string(owner)),
+		scb.ProviderConfigUIDLabel: ptr.To(providerConfigUID),
+	}}}
+	b.GUID = guid
+	return b
+}
+
+func TestRetiredKeyCollector(t *testing.T) {
+	now := time.Now()
+	keys := []*cfresource.ServiceCredentialBinding{
+		retiredBinding("orphan", "gone", now.Add(-48*time.Hour)),
+		retiredBinding("recent", "gone", now.Add(-time.Hour)),
+		retiredBinding("owned", "live", now.Add(-48*time.Hour)),
+	}
+	otherCluster := retiredBinding("other-cluster", "gone", now.Add(-48*time.Hour))
+	otherCluster.Metadata.Labels[scb.ProviderConfigUIDLabel] = ptr.To("other-cluster")
+
+	cases := map[string]struct {
+		list    test.MockListFn
+		client  func() *fake.MockServiceCredentialBinding
+		deleted []string
+		want    error
+	}{
+		"KeepsKeysOfOtherClusters": {
+			list: withBindingsAndConfigs("live"),
+			client: func() *fake.MockServiceCredentialBinding {
+				m := &fake.MockServiceCredentialBinding{}
+				m.On("ListAll", mock.Anything, mock.Anything).Return([]*cfresource.ServiceCredentialBinding{otherCluster}, nil)
+				return m
+			},
+		},
+		"ContinuesAfterFailedProviderConfig": {
+			list: withBindingsAndNamedConfigs([]types.UID{"live"}, "default", "failing"),
+			client: func() *fake.MockServiceCredentialBinding {
+				m := &fake.MockServiceCredentialBinding{}
+				m.On("ListAll", mock.Anything, mock.MatchedBy(func(o *client.ServiceCredentialBindingListOptions) bool {
+					return slices.Contains(o.LabelSel[scb.ProviderConfigUIDLabel].Values, "failing")
+				})).Return([]*cfresource.ServiceCredentialBinding{}, errBoom)
+				m.On("ListAll", mock.Anything, mock.Anything).Return(keys, nil)
+				m.On("Delete", mock.Anything, "orphan").Return("", nil)
+				return m
+			},
+			deleted: []string{"orphan"},
+			want:    errors.Join(fmt.Errorf(errListRetired, "default", "failing", errBoom)),
+		},
+		"DeletesOrphans": {
+			list: withBindingsAndConfigs("live"),
+			client: func() *fake.MockServiceCredentialBinding {
+				m := &fake.MockServiceCredentialBinding{}
+				m.On("ListAll", mock.Anything, mock.Anything).Return(keys, nil)
+				m.On("Delete", mock.Anything, "orphan").Return("", nil)
+				return m
+			},
+			deleted: []string{"orphan"},
+		},
+		"ListBindingsFailed": {
+			list: test.NewMockListFn(errBoom),
+			client: func() *fake.MockServiceCredentialBinding {
+				return &fake.MockServiceCredentialBinding{}
+			},
+			want: fmt.Errorf(errListBindings, errBoom),
+		},
+		"ListRetiredFailed": {
+			list: withBindingsAndConfigs("live"),
+			client: func() *fake.MockServiceCredentialBinding {
+				m := &fake.MockServiceCredentialBinding{}
+				m.On("ListAll", mock.Anything, mock.Anything).Return([]*cfresource.ServiceCredentialBinding{}, errBoom)
+				return m
+			},
+			want: errors.Join(fmt.Errorf(errListRetired, "default", "default", errBoom)),
+		},
+		"DeleteFailed": {
+			list: withBindingsAndConfigs("live"),
+			client: func() *fake.MockServiceCredentialBinding {
+				m := &fake.MockServiceCredentialBinding{}
+				m.On("ListAll", mock.Anything, mock.Anything).Return(keys, nil)
+				m.On("Delete", mock.Anything, "orphan").Return("", errBoom)
+				return m
+			},
+			deleted: []string{"orphan"},
+			want:    errors.Join(fmt.Errorf(errDeleteOrphanedKey, "orphan", errBoom)),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := tc.client()
+			c := &retiredKeyCollector{
+				kube:      &test.MockClient{MockList: tc.list},
+				log:       logging.NewNopLogger(),
+				retention: DefaultRetiredKeyRetention,
+				newClient: func(context.Context, *apisv1beta1.ProviderConfig) (scb.ServiceCredentialBinding, error) {
+					return m, nil
+				},
+			}
+
+			err := c.collect(context.Background(), now)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("collect(...): -want error, +got error:\n%s", diff)
+			}
+			var deleted []string
+			for _, call := range m.Calls {
+				if call.Method == "Delete" {
+					deleted = append(deleted, call.Arguments.String(1))
+				}
+			}
+			if diff := cmp.Diff(tc.deleted, deleted); diff != "" {
+				t.Errorf("deleted keys: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
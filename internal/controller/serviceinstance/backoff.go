@@ -0,0 +1,115 @@
+package serviceinstance
+
+import (
+	"fmt"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
+)
+
+const (
+	// createBackoffBase is the delay before the first retry of a failed create.
+	createBackoffBase = 30 * time.Second
+	// createBackoffMax caps the delay between the retries of a failed create.
+	createBackoffMax = 30 * time.Minute
+	// maxCreateFailures is the number of consecutive failed creates after which
+	// the service instance is reported as failing.
+	maxCreateFailures = 5
+
+	reasonCreateBackoff xpv1.ConditionReason = "CreateBackoff"
+	reasonCreateFailing xpv1.ConditionReason = "CreateFailing"
+)
+
+// createBackoff returns the delay before retrying a create after the given
+// number of consecutive failures, doubling with every failure up to createBackoffMax.
+func createBackoff(failures int32) time.Duration {
+	if failures <= 0 {
+		return 0
+	}
+	d := createBackoffBase
+	for i := int32(1); i < failures && d < createBackoffMax; i++ {
+		d *= 2
+	}
+	return min(d, createBackoffMax)
+}
+
+// recordCreateFailure counts a failed create of the service instance. The count
+// restarts if the spec changed since the last failure.
+func recordCreateFailure(cr *v1alpha1.ServiceInstance, now time.Time) {
+	o := &cr.Status.AtProvider
+	h := clients.SpecHash(cr.Spec.ForProvider)
+	if o.CreateFailureSpecHash == nil || *o.CreateFailureSpecHash != h {
+		o.CreateFailures = 0
+	}
+	o.CreateFailures++
+	o.LastCreateFailure = &metav1.Time{Time: now}
+	o.CreateFailureSpecHash = &h
+}
+
+// recordFailedCreateAttempt counts a create that Cloud Foundry rejected, which
+// the reconciler records in the external-create-failed annotation, once: when
+// the annotation is newer than the last counted failure and the last create
+// that succeeded.
+func recordFailedCreateAttempt(cr *v1alpha1.ServiceInstance) {
+	failed := meta.GetExternalCreateFailed(cr)
+	if failed.IsZero() || !failed.After(meta.GetExternalCreateSucceeded(cr)) {
+		return
+	}
+	if last := cr.Status.AtProvider.LastCreateFailure; last != nil && !failed.After(last.Time) {
+		return
+	}
+	recordCreateFailure(cr, failed)
+}
+
+// resetCreateFailures clears the failed creates of the service instance if
+// a create succeeded or the spec changed since the last failure.
+func resetCreateFailures(cr *v1alpha1.ServiceInstance, succeeded bool) {
+	o := &cr.Status.AtProvider
+	if o.CreateFailures == 0 {
+		return
+	}
+	if succeeded || o.CreateFailureSpecHash == nil || *o.CreateFailureSpecHash != clients.SpecHash(cr.Spec.ForProvider) {
+		o.CreateFailures = 0
+		o.LastCreateFailure = nil
+		o.CreateFailureSpecHash = nil
+	}
+}
+
+// createBackoffRemaining returns how long the next create of the service
+// instance must wait after its failed creates, or zero if it may create now.
+func createBackoffRemaining(cr *v1alpha1.ServiceInstance, now time.Time) time.Duration {
+	o := cr.Status.AtProvider
+	if o.CreateFailures == 0 || o.LastCreateFailure == nil {
+		return 0
+	}
+	return max(o.LastCreateFailure.Add(createBackoff(o.CreateFailures)).Sub(now), 0)
+}
+
+// createBackoffCondition returns the Ready condition of a service instance whose
+// next create waits for the given delay. After maxCreateFailures it reports the
+// service instance as failing until its spec changes.
+func createBackoffCondition(cr *v1alpha1.ServiceInstance, wait time.Duration, cause string) xpv1.Condition {
+	failures := cr.Status.AtProvider.CreateFailures
+	reason := reasonCreateBackoff
+	msg := fmt.Sprintf("create failed %d times, retrying in %s", failures, wait.Round(time.Second))
+	if failures >= maxCreateFailures {
+		reason = reasonCreateFailing
+		msg += ", change the spec to retry immediately"
+	}
+	if cause != "" {
+		msg += ": " + cause
+	}
+	return xpv1.Condition{
+		Type:               xpv1.TypeReady,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            msg,
+	}
+}
@@ -0,0 +1,210 @@
+package serviceinstance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
+)
+
+func TestCreateBackoff(t *testing.T) {
+	cases := map[string]struct {
+		failures int32
+		want     time.Duration
+	}{
+		"NoFailure":      {failures: 0, want: 0},
+		"FirstFailure":   {failures: 1, want: createBackoffBase},
+		"ThirdFailure":   {failures: 3, want: 4 * createBackoffBase},
+		"Capped":         {failures: 7, want: createBackoffMax},
+		"CappedOverflow": {failures: 1000, want: createBackoffMax},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, createBackoff(tc.failures)); diff != "" {
+				t.Errorf("createBackoff(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRecordCreateFailure(t *testing.T) {
+	now := time.Now()
+	otherPlan := "other-plan"
+
+	cases := map[string]struct {
+		mg   *v1alpha1.ServiceInstance
+		want int32
+	}{
+		"FirstFailure": {
+			mg:   serviceInstance("managed", withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan})),
+			want: 1,
+		},
+		"Increment": {
+			mg:   serviceInstance("managed", withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withCreateFailures(2, now)),
+			want: 3,
+		},
+		"SpecChanged": {
+			mg: func() *v1alpha1.ServiceInstance {
+				cr := serviceInstance("managed", withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withCreateFailures(2, now))
+				cr.Spec.ForProvider.ServicePlan.ID = &otherPlan
+				return cr
+			}(),
+			want: 1,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			recordCreateFailure(tc.mg, now)
+			if diff := cmp.Diff(tc.want, tc.mg.Status.AtProvider.CreateFailures); diff != "" {
+				t.Errorf("recordCreateFailure(...): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(clients.SpecHash(tc.mg.Spec.ForProvider), *tc.mg.Status.AtProvider.CreateFailureSpecHash); diff != "" {
+				t.Errorf("recordCreateFailure(...): spec hash -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRecordFailedCreateAttempt(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	failedAt := func(at time.Time) modifier {
+		return func(r *v1alpha1.ServiceInstance) { meta.SetExternalCreateFailed(r, at) }
+	}
+
+	cases := map[string]struct {
+		mg   *v1alpha1.ServiceInstance
+		want int32
+	}{
+		"NoFailedCreate": {
+			mg:   serviceInstance("managed"),
+			want: 0,
+		},
+		"FailedCreate": {
+			mg:   serviceInstance("managed", failedAt(now)),
+			want: 1,
+		},
+		"FailedCreateCounted": {
+			mg:   serviceInstance("managed", withCreateFailures(1, now), failedAt(now)),
+			want: 1,
+		},
+		"AnotherFailedCreate": {
+			mg:   serviceInstance("managed", withCreateFailures(1, now.Add(-time.Minute)), failedAt(now)),
+			want: 2,
+		},
+		"CreateSucceededSince": {
+			mg: serviceInstance("managed", failedAt(now.Add(-time.Minute)), func(r *v1alpha1.ServiceInstance) {
+				meta.SetExternalCreateSucceeded(r, now)
+			}),
+			want: 0,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			recordFailedCreateAttempt(tc.mg)
+			if diff := cmp.Diff(tc.want, tc.mg.Status.AtProvider.CreateFailures); diff != "" {
+				t.Errorf("recordFailedCreateAttempt(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestResetCreateFailures(t *testing.T) {
+	now := time.Now()
+	otherPlan := "other-plan"
+
+	cases := map[string]struct {
+		mg        *v1alpha1.ServiceInstance
+		succeeded bool
+		want      int32
+	}{
+		"Unchanged": {
+			mg:   serviceInstance("managed", withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withCreateFailures(3, now)),
+			want: 3,
+		},
+		"Succeeded": {
+			mg:        serviceInstance("managed", withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withCreateFailures(3, now)),
+			succeeded: true,
+			want:      0,
+		},
+		"SpecChanged": {
+			mg: func() *v1alpha1.ServiceInstance {
+				cr := serviceInstance("managed", withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withCreateFailures(3, now))
+				cr.Spec.ForProvider.ServicePlan.ID = &otherPlan
+				return cr
+			}(),
+			want: 0,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			resetCreateFailures(tc.mg, tc.succeeded)
+			if diff := cmp.Diff(tc.want, tc.mg.Status.AtProvider.CreateFailures); diff != "" {
+				t.Errorf("resetCreateFailures(...): -want, +got:\n%s", diff)
+			}
+			if remaining := createBackoffRemaining(tc.mg, now); tc.want == 0 && remaining != 0 {
+				t.Errorf("createBackoffRemaining(...): want 0 after reset, got %s", remaining)
+			}
+		})
+	}
+}
+
+func TestCreateBackoffRemaining(t *testing.T) {
+	now := time.Now()
+
+	cases := map[string]struct {
+		mg   *v1alpha1.ServiceInstance
+		want time.Duration
+	}{
+		"NoFailure": {
+			mg:   serviceInstance("managed"),
+			want: 0,
+		},
+		"Waiting": {
+			mg:   serviceInstance("managed", withCreateFailures(2, now.Add(-30*time.Second))),
+			want: 2*createBackoffBase - 30*time.Second,
+		},
+		"Elapsed": {
+			mg:   serviceInstance("managed", withCreateFailures(2, now.Add(-time.Hour))),
+			want: 0,
+		},
+		"CappedAfterManyFailures": {
+			mg:   serviceInstance("managed", withCreateFailures(20, now)),
+			want: createBackoffMax,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, createBackoffRemaining(tc.mg, now)); diff != "" {
+				t.Errorf("createBackoffRemaining(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCreateBackoffCondition(t *testing.T) {
+	cases := map[string]struct {
+		failures int32
+		want     string
+	}{
+		"Backoff": {failures: 1, want: string(reasonCreateBackoff)},
+		"Failing": {failures: maxCreateFailures, want: string(reasonCreateFailing)},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := serviceInstance("managed", withCreateFailures(tc.failures, time.Now()))
+			if diff := cmp.Diff(tc.want, string(createBackoffCondition(cr, time.Minute, "").Reason)); diff != "" {
+				t.Errorf("createBackoffCondition(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
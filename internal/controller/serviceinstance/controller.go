@@ -4,12 +4,16 @@ import (
 	"bytes"
 	"context"
 	"crypto/sha256"
+	"encoding/json"
+	"strconv"
+	"strings"
 	"time"
 
-	"github.com/cloudfoundry/go-cfclient/v3/client"
+	cfresource "github.com/cloudfoundry/go-cfclient/v3/resource"
 	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/feature"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
@@ -17,14 +21,19 @@ import (
 	"github.com/google/uuid"
 	"github.com/nsf/jsondiff"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	k8s "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
 
 	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
 	apisv1beta1 "github.com/SAP/crossplane-provider-cloudfoundry/apis/v1beta1"
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/serviceinstance"
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/space"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/metrics"
 )
 
 const (
@@ -39,22 +48,47 @@ const (
 	errUpdate             = "cannot update " + resourceType + " in " + externalSystem
 	errDelete             = "cannot delete " + resourceType + " in " + externalSystem
 	errCleanFailed        = "cannot delete failed service instance"
-	errSecret             = "cannot resolve secret reference"
-	errGetParameters      = "cannot get parameters of the service instance for drift detection. Please check this is supported or set enableParameterDriftDetection to false."
+	errResolveParams      = "cannot resolve parameters or credentials"
+	errYAMLParams         = "cannot convert yamlParams to JSON"
+	errParamsConfigMap    = "cannot resolve paramsConfigMapRef"
+	errConfigMapAndSecret = "paramsConfigMapRef and paramsSecretRef cannot both be set"
+	errGetParameters      = "cannot get parameters of the service instance for drift detection"
 	errMissingServicePlan = "managed resource service instance requires a service plan"
+	errInitServicePlan    = "cannot initialize service plan"
+	errDryRun             = "cannot compute the create payload of " + resourceType
+	errGetCredentials     = "cannot get credentials of the user-provided service instance to publish them as connection details"
+	errPlanUpdate         = "cannot plan the update of " + resourceType
+	errMergeCredentials   = "cannot merge the credentials of the user-provided service instance"
+	errListBindings       = "cannot list the bindings of " + resourceType
+	errDeleteBindings     = "cannot delete the bindings of " + resourceType
+	errHasBindings        = "cannot delete " + resourceType + " that has bindings, see the DeletionBlocked condition"
+
+	// redacted replaces parameters or credentials sourced from a Secret in a dry-run payload
+	redacted = `"REDACTED"`
+)
+
+const (
+	reasonProvisionStarted   event.Reason = "ProvisionStarted"
+	reasonProvisionSucceeded event.Reason = "ProvisionSucceeded"
+	reasonProvisionFailed    event.Reason = "ProvisionFailed"
+	reasonDeletingBindings   event.Reason = "DeletingBindings"
 )
 
 // Setup adds a controller that reconciles ServiceInstance CR.
 func Setup(mgr ctrl.Manager, o controller.Options) error {
 	name := managed.ControllerName(v1alpha1.ServiceInstance_GroupKind)
 
+	metrics.Register()
+
 	options := []managed.ReconcilerOption{
-		managed.WithExternalConnecter(&connector{
-			kube:  mgr.GetClient(),
-			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1beta1.ProviderConfigUsage{}),
-		}),
+		managed.WithExternalConnecter(clients.WithPause(clients.WithLogger(o.Logger.WithValues("controller", name), clients.WithErrorClassification(metrics.Instrument(v1alpha1.ServiceInstance_Kind, clients.WithOperationTimeout(mgr.GetClient(), &connector{
+			kube:     mgr.GetClient(),
+			usage:    resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1beta1.ProviderConfigUsage{}),
+			recorder: event.NewAPIRecorder(mgr.GetEventRecorderFor(name), clients.RateLimitEvents(clients.EventRateLimitInterval)),
+		}, 5*time.Minute)))))), // increase the default timeout for long-running operations
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
-		managed.WithTimeout(5 * time.Minute), // increase timeout for long-running operations
+		managed.WithTimeout(clients.MaxOperationTimeout),
+		managed.WithPollIntervalHook(clients.PollIntervalHook(mgr.GetClient())),
 		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 		managed.WithPollInterval(o.PollInterval),
 		managed.WithInitializers(
@@ -63,6 +97,10 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		),
 	}
 
+	if o.Features.Enabled(feature.EnableBetaManagementPolicies) {
+		options = append(options, managed.WithManagementPolicies())
+	}
+
 	r := managed.NewReconciler(mgr,
 		resource.ManagedKind(v1alpha1.ServiceInstance_GroupVersionKind),
 		options...)
@@ -77,8 +115,9 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 // A connector is expected to produce an external client when its Connect method
 // is called.
 type connector struct {
-	kube  k8s.Client
-	usage *resource.ProviderConfigUsageTracker
+	kube     k8s.Client
+	usage    *resource.ProviderConfigUsageTracker
+	recorder event.Recorder
 }
 
 // Connect typically produces an ExternalClient by:
@@ -87,7 +126,8 @@ type connector struct {
 // 3. Getting the credentials specified by the ProviderConfig.
 // 4. Using the credentials to form a client.
 func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
-	if _, ok := mg.(*v1alpha1.ServiceInstance); !ok {
+	cr, ok := mg.(*v1alpha1.ServiceInstance)
+	if !ok {
 		return nil, errors.New(errWrongCRType)
 	}
 
@@ -102,10 +142,23 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 
 	return &external{
 		kube:            c.kube,
-		serviceinstance: serviceinstance.NewClient(cf),
+		serviceinstance: serviceinstance.NewClient(cf, clientOptions(cr)...),
+		recorder:        c.recorder,
 	}, nil
 }
 
+// clientOptions returns the options of the service instance client for the
+// given service instance: creates carry an idempotency key that is stable
+// across retries of the same generation, and are bounded by the request
+// timeout of the service instance if it sets one.
+func clientOptions(cr *v1alpha1.ServiceInstance) []serviceinstance.Option {
+	opts := []serviceinstance.Option{serviceinstance.WithIdempotencyKey(serviceinstance.IdempotencyKey(cr))}
+	if d := cr.GetRequestTimeout(); d != nil {
+		opts = append(opts, serviceinstance.WithRequestTimeout(*d))
+	}
+	return opts
+}
+
 // Disconnect implements the managed.ExternalClient interface
 func (c *external) Disconnect(ctx context.Context) error {
 	// No cleanup needed for Cloud Foundry client
@@ -117,6 +170,7 @@ func (c *external) Disconnect(ctx context.Context) error {
 type external struct {
 	kube            k8s.Client
 	serviceinstance *serviceinstance.Client
+	recorder        event.Recorder
 }
 
 // Observe checks if the external resource exists and if it does, it observes it.
@@ -126,30 +180,69 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, errors.New(errWrongCRType)
 	}
 
+	if clients.ObserveSpec(&cr.Status.AtProvider.ObservedSpec, cr, cr.Spec.ForProvider) {
+		clients.LoggerFrom(ctx).Debug("Spec changed since the last observation", "generation", cr.GetGeneration())
+	}
+
+	// A changed spec may fix the failed creates, so retry it without backoff
+	resetCreateFailures(cr, false)
+
 	// Check if the external resource exists
 	guid := meta.GetExternalName(cr)
 
 	// Normal (non‑deletion) observe path.
-	r, err := serviceinstance.GetByIDOrSpec(ctx, c.serviceinstance, guid, cr.Spec.ForProvider)
-	if err != nil {
-		if clients.ErrorIsNotFound(err) {
-			return managed.ExternalObservation{ResourceExists: false}, nil
-		}
+	r, err := serviceinstance.GetByIDOrSpec(ctx, c.serviceinstance, guid, cr.Spec.ForProvider, meta.GetExternalCreateSucceeded(cr))
+	if err != nil && !clients.IsNotFound(err) {
 		return managed.ExternalObservation{}, errors.Wrap(err, errGet)
 	}
-	if r == nil {
-		return managed.ExternalObservation{}, nil
+	if r == nil || err != nil {
+		if clients.IsDryRun(cr) && !meta.WasDeleted(cr) {
+			return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, c.observeDryRun(ctx, cr)
+		}
+		recordFailedCreateAttempt(cr)
+		// Report the missing service instance as existing until the backoff of the failed creates elapsed
+		if wait := createBackoffRemaining(cr, time.Now()); wait > 0 && !meta.WasDeleted(cr) {
+			cr.SetConditions(createBackoffCondition(cr, wait, ""))
+			return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+		}
+		return managed.ExternalObservation{ResourceExists: false}, nil
 	}
 	// resource exists, set/update the external name
 	if guid != r.GUID {
 		meta.SetExternalName(cr, r.GUID)
-		if err := c.kube.Update(ctx, cr); err != nil {
-			return managed.ExternalObservation{}, errors.Wrap(err, errUpdateCR)
+		if !clients.IsReadOnly(ctx) {
+			if err := c.kube.Update(ctx, cr); err != nil {
+				return managed.ExternalObservation{}, errors.Wrap(err, errUpdateCR)
+			}
+		}
+	}
+	// Seed the hash of the desired parameters of a service instance that is
+	// observed for the first time, as it was adopted or just created, otherwise
+	// the missing hash is reported as a drift of its parameters.
+	if (guid != r.GUID || cr.Status.AtProvider.ID == nil) && cr.Status.AtProvider.Credentials == nil {
+		creds, err := extractCredentialSpec(ctx, c.kube, cr.Spec.ForProvider)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errResolveParams)
 		}
+		if mergesCredentials(cr.Spec.ForProvider) {
+			if creds, err = c.mergeCredentials(ctx, r, creds); err != nil {
+				return managed.ExternalObservation{}, err
+			}
+		}
+		cr.Status.AtProvider.Credentials = iSha256(creds)
 	}
 
 	// Update atProvider from the retrieved the service instance
+	last := cr.Status.AtProvider.LastOperation
+	if last.State == "" && !meta.GetExternalCreateSucceeded(cr).IsZero() {
+		// The first observation of a service instance this provider created
+		// records the outcome of its create
+		last = v1alpha1.LastOperation{Type: v1alpha1.LastOperationCreate, State: v1alpha1.LastOperationInProgress}
+	}
 	serviceinstance.UpdateObservation(&cr.Status.AtProvider, r)
+	cr.Status.AtProvider.DryRunPayload = nil
+	c.recordProvision(cr, last)
+	cr.Status.AtProvider.ManagedMetadata = clients.ObserveManagedMetadata(cr.Status.AtProvider.ManagedMetadata, cr.Spec.ForProvider.Labels, serviceinstance.DesiredAnnotations(cr.Spec.ForProvider), r.Metadata)
 
 	// If the CR is marked for deletion we stop normal observe logic.
 	// We report "resource exists" so Crossplane will call Delete() next.
@@ -158,18 +251,30 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{ResourceExists: true}, nil
 	}
 
-	switch r.LastOperation.State {
-	case v1alpha1.LastOperationInitial, v1alpha1.LastOperationInProgress:
+	if clients.IsOperationInProgress(r.LastOperation.State) {
 		// Set the CR to unavailable and signal that the reconciler should not update the resource
-		cr.SetConditions(xpv1.Unavailable().WithMessage(r.LastOperation.Description))
+		cr.SetConditions(xpv1.Unavailable().WithMessage(inProgressMessage(cr.Status.AtProvider)))
 		return managed.ExternalObservation{
 			ResourceExists:   true,
 			ResourceUpToDate: true, // Set to true so that the reconciler do not schedule another update while the last operation is in progress
 		}, nil
+	}
+
+	switch r.LastOperation.State {
 	// If the last operation failed, set the CR to unavailable and signal that the reconciler should retry the last operation
 	case v1alpha1.LastOperationFailed:
 		// If the last operation failed, set the CR to unavailable and signal that the reconciler should retry the last operation
 		cr.SetConditions(xpv1.Unavailable().WithMessage(r.LastOperation.Description))
+		if r.LastOperation.Type == v1alpha1.LastOperationCreate {
+			// count the failed create once, when it is first observed
+			if last.Type != r.LastOperation.Type || last.State != r.LastOperation.State {
+				recordCreateFailure(cr, time.Now())
+			}
+			if wait := createBackoffRemaining(cr, time.Now()); wait > 0 {
+				cr.SetConditions(createBackoffCondition(cr, wait, r.LastOperation.Description))
+				return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+			}
+		}
 		return managed.ExternalObservation{
 			ResourceExists:   r.LastOperation.Type != v1alpha1.LastOperationCreate, // set to false when the last operation is create, hence the reconciler will retry create
 			ResourceUpToDate: r.LastOperation.Type != v1alpha1.LastOperationUpdate, // set to false when the last operation is update, hence the reconciler will retry update
@@ -177,27 +282,66 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	case v1alpha1.LastOperationSucceeded:
 		// If the last operation succeeded, set the CR to available
 		cr.SetConditions(xpv1.Available())
+		resetCreateFailures(cr, true)
 		var credentialsUpToDate bool
 		desiredCredentials, err := extractCredentialSpec(ctx, c.kube, cr.Spec.ForProvider)
 		if err != nil {
-			return managed.ExternalObservation{}, errors.Wrap(err, errSecret)
+			return managed.ExternalObservation{}, errors.Wrap(err, errResolveParams)
 		}
-		// If parameter drift detection is enable, get actual credentials from the service instance
-		if cr.Spec.EnableParameterDriftDetection {
-			// Get the parameters of the service instance for drift detection
-			cred, err := c.serviceinstance.GetServiceCredentials(ctx, r)
-			if err != nil {
+		if err := recordRedactedParameters(cr, desiredCredentials); err != nil {
+			return managed.ExternalObservation{ResourceExists: true}, err
+		}
+		if mergesCredentials(cr.Spec.ForProvider) {
+			if desiredCredentials, err = c.mergeCredentials(ctx, r, desiredCredentials); err != nil {
+				return managed.ExternalObservation{ResourceExists: true}, err
+			}
+		}
+		// Get the actual parameters or credentials of the service instance for drift detection or to publish them
+		var cred json.RawMessage
+		driftDetection := cr.Spec.EnableParameterDriftDetection
+		if driftDetection {
+			cred, err = c.serviceinstance.GetServiceCredentials(ctx, r)
+			switch {
+			case cfresource.IsServiceFetchInstanceParametersNotSupportedError(err):
+				// Fall back to comparing the hash of the desired parameters rather than failing every reconcile
+				driftDetection = false
+				cr.SetConditions(driftDetectionUnsupported())
+			case err != nil:
 				return managed.ExternalObservation{ResourceExists: true}, errors.Wrap(err, errGetParameters)
+			case cr.GetCondition(typeDriftDetectionUnsupported).Status == corev1.ConditionTrue:
+				cr.SetConditions(driftDetectionSupported())
+			}
+		} else if publishConnectionDetails(cr, r) {
+			cred, err = c.serviceinstance.GetServiceCredentials(ctx, r)
+			if err != nil {
+				return managed.ExternalObservation{ResourceExists: true}, errors.Wrap(err, errGetCredentials)
 			}
+		}
+		var details managed.ConnectionDetails
+		if publishConnectionDetails(cr, r) {
+			if details, err = serviceinstance.GetConnectionDetails(cred); err != nil {
+				return managed.ExternalObservation{ResourceExists: true}, errors.Wrap(err, errGetCredentials)
+			}
+			// Remove the keys of credentials that are no longer set from the connection secret
+			if details, err = clients.PruneConnectionDetails(ctx, c.kube, cr, details); err != nil {
+				return managed.ExternalObservation{ResourceExists: true}, err
+			}
+		}
+		// If parameter drift detection is enable, compare with the actual credentials of the service instance
+		if driftDetection {
 			cr.Status.AtProvider.Credentials = iSha256(cred)
-			credentialsUpToDate = jsonContain(cred, desiredCredentials)
+			credentialsUpToDate = jsonMatch(cred, desiredCredentials, cr.Spec.ParameterComparison)
 		} else {
 			desiredHash := iSha256(desiredCredentials)
 			credentialsUpToDate = bytes.Equal(desiredHash, cr.Status.AtProvider.Credentials)
 		}
-		// Check if the credentials in the spec match the credentials in the external resource
-		upToDate := credentialsUpToDate && serviceinstance.IsUpToDate(&cr.Spec.ForProvider, r)
-		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: upToDate}, nil
+		// Check if the credentials in the spec match the credentials in the external resource,
+		// ignoring a plan change that awaits approval
+		upToDate := credentialsUpToDate && serviceinstance.IsUpToDate(gatedSpec(cr, r), cr.Status.AtProvider.ManagedMetadata, r)
+		if upToDate && cr.GetCondition(clients.TypePendingUpdate).Status == corev1.ConditionTrue {
+			cr.SetConditions(clients.NoPendingUpdate())
+		}
+		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: upToDate, ConnectionDetails: details}, nil
 	default:
 		// should never reach here
 		cr.SetConditions(xpv1.Unavailable().WithMessage(r.LastOperation.Description))
@@ -215,6 +359,7 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 
 	// If the last operation is create and it failed, clean up the failed service instance before retry create
 	if cr.Status.AtProvider.LastOperation.Type == v1alpha1.LastOperationCreate && cr.Status.AtProvider.LastOperation.State == v1alpha1.LastOperationFailed {
+		clients.LoggerFrom(ctx).Debug("Deleting the failed service instance before creating it again")
 		err := c.serviceinstance.Delete(ctx, cr)
 		if err != nil {
 			return managed.ExternalCreation{}, errors.Wrap(err, errCleanFailed)
@@ -226,29 +371,91 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	// Extract the parameters or credentials from the spec as a json.RawMessage
 	creds, err := extractCredentialSpec(ctx, c.kube, cr.Spec.ForProvider)
 	if err != nil {
-		return managed.ExternalCreation{}, errors.Wrap(err, errSecret)
+		return managed.ExternalCreation{}, errors.Wrap(err, errResolveParams)
 	}
 
 	r, err := c.serviceinstance.Create(ctx, cr.Spec.ForProvider, creds)
 	if err != nil {
+		// The reconciler records the failed create in the external-create-failed
+		// annotation, which the next observation counts, see recordFailedCreateAttempt.
+		c.recorder.Event(cr, event.Warning(reasonProvisionFailed, err))
 		return managed.ExternalCreation{}, errors.Wrap(err, errCreate)
 	}
+	c.recorder.Event(cr, event.Normal(reasonProvisionStarted, "Started provisioning service instance "+r.GUID))
+	clients.LoggerFrom(ctx).Debug("Started provisioning service instance", "guid", r.GUID)
 
-	// Set the external name of the CR
+	// Set the external name of the CR, the reconciler persists it. The status
+	// is observed once the service instance is found by its external name.
 	meta.SetExternalName(cr, r.GUID)
 
-	// Update the CR before updating the status so that the status update is not lost.
-	if err = c.kube.Update(ctx, cr); err != nil {
-		return managed.ExternalCreation{}, errors.Wrap(err, errUpdateCR)
+	return managed.ExternalCreation{}, nil
+}
+
+// recordProvision records an event when the create operation of the service instance
+// completed since the given last observed operation. It does not record an event for
+// a service instance that was never observed before, e.g. an imported one.
+func (c *external) recordProvision(cr *v1alpha1.ServiceInstance, last v1alpha1.LastOperation) {
+	op := cr.Status.AtProvider.LastOperation
+	if op.Type != v1alpha1.LastOperationCreate || last.State == "" || (last.Type == op.Type && last.State == op.State) {
+		return
+	}
+	switch op.State {
+	case v1alpha1.LastOperationSucceeded:
+		c.recorder.Event(cr, event.Normal(reasonProvisionSucceeded, withDescription("Provisioned service instance", op.Description)))
+	case v1alpha1.LastOperationFailed:
+		c.recorder.Event(cr, event.Warning(reasonProvisionFailed, errors.New(withDescription("Cannot provision service instance", op.Description))))
 	}
+}
 
-	// Save hash value of credentials in the status of the CR
-	cr.Status.AtProvider.Credentials = iSha256(creds)
-	if err = c.kube.Status().Update(ctx, cr); err != nil {
-		return managed.ExternalCreation{}, errors.Wrap(err, errUpdateCR)
+// inProgressMessage returns the message of the Ready condition of a service instance whose
+// last operation is in progress. It leads with the progress reported by the service broker,
+// if any, and falls back to the description of the last operation.
+func inProgressMessage(obs v1alpha1.ServiceInstanceObservation) string {
+	var progress []string
+	if obs.Progress != nil {
+		progress = append(progress, strconv.Itoa(int(*obs.Progress))+"%")
+	}
+	if obs.ProgressStep != nil {
+		progress = append(progress, *obs.ProgressStep)
 	}
+	if len(progress) == 0 {
+		return obs.LastOperation.Description
+	}
+	return withDescription(obs.LastOperation.Type+" in progress ("+strings.Join(progress, ", ")+")", obs.LastOperation.Description)
+}
 
-	return managed.ExternalCreation{}, nil
+// withDescription appends the description of a last operation to an event message.
+func withDescription(msg, description string) string {
+	if description == "" {
+		return msg
+	}
+	return msg + ": " + description
+}
+
+// observeDryRun records the payload Create would send to Cloud Foundry in the status of the CR,
+// without calling Cloud Foundry. The missing service instance is reported as existing, so that
+// the reconciler does not call Create until the dry-run annotation is removed.
+func (c *external) observeDryRun(ctx context.Context, cr *v1alpha1.ServiceInstance) error {
+	creds := json.RawMessage(redacted)
+	if !credentialsFromSecret(cr.Spec.ForProvider) {
+		var err error
+		if creds, err = extractCredentialSpec(ctx, c.kube, cr.Spec.ForProvider); err != nil {
+			return errors.Wrap(err, errResolveParams)
+		}
+	}
+
+	payload, err := serviceinstance.NewCreatePayload(cr.Spec.ForProvider, creds)
+	if err != nil {
+		return errors.Wrap(err, errDryRun)
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrap(err, errDryRun)
+	}
+
+	cr.Status.AtProvider.DryRunPayload = ptr.To(string(raw))
+	cr.SetConditions(clients.DryRun("create payload recorded in status.atProvider.dryRunPayload, remove the " + clients.AnnotationKeyDryRun + " annotation to create the service instance"))
+	return nil
 }
 
 // Update attempts to update the external resource to reflect the managed resource's desired state.
@@ -262,25 +469,105 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, errors.New(errUpdate)
 	}
 
+	// The service broker rejects an update while the last operation is in progress
+	if clients.IsOperationInProgress(cr.Status.AtProvider.LastOperation.State) {
+		clients.LoggerFrom(ctx).Debug("Deferring the update until the last operation completes")
+		return managed.ExternalUpdate{}, nil
+	}
+
 	creds, err := extractCredentialSpec(ctx, c.kube, cr.Spec.ForProvider)
 	if err != nil {
-		return managed.ExternalUpdate{}, errors.Wrap(err, errSecret)
+		return managed.ExternalUpdate{}, errors.Wrap(err, errResolveParams)
 	}
 
-	if _, err := c.serviceinstance.Update(ctx, *cr.Status.AtProvider.ID, &cr.Spec.ForProvider, creds); err != nil {
-		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdate)
+	if mergesCredentials(cr.Spec.ForProvider) {
+		r, err := c.serviceinstance.Get(ctx, *cr.Status.AtProvider.ID)
+		if err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errMergeCredentials)
+		}
+		if creds, err = c.mergeCredentials(ctx, r, creds); err != nil {
+			return managed.ExternalUpdate{}, err
+		}
 	}
 
-	if creds != nil {
-		cr.Status.AtProvider.Credentials = iSha256(creds)
-		if err := c.kube.Status().Update(ctx, cr); err != nil {
-			return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateCR)
+	if clients.IsDryRunUpdate(cr) {
+		return managed.ExternalUpdate{}, c.planUpdate(ctx, cr, creds)
+	}
+
+	// Apply the other changes while a plan change awaits approval
+	spec := &cr.Spec.ForProvider
+	if cr.Spec.RequirePlanChangeApproval {
+		r, err := c.serviceinstance.Get(ctx, *cr.Status.AtProvider.ID)
+		if err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errUpdate)
+		}
+		if r == nil {
+			return managed.ExternalUpdate{}, errors.New(errUpdate)
 		}
+		spec = gatedSpec(cr, r)
 	}
 
+	if _, err := c.serviceinstance.Update(ctx, *cr.Status.AtProvider.ID, spec, cr.Status.AtProvider.ManagedMetadata, creds); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdate)
+	}
+	clients.LoggerFrom(ctx).Debug("Updated service instance")
+
+	// Store the hash even if the parameters were removed, otherwise the stale
+	// hash keeps reporting a drift.
+	cr.Status.AtProvider.Credentials = iSha256(creds)
+
 	return managed.ExternalUpdate{}, nil
 }
 
+// planUpdate records the change Update would apply to the service instance in the
+// PendingUpdate condition of the CR, without calling the service broker. The CR stays
+// not up to date, so that the change is applied once the update dry-run annotation is removed.
+func (c *external) planUpdate(ctx context.Context, cr *v1alpha1.ServiceInstance, creds json.RawMessage) error {
+	r, err := c.serviceinstance.Get(ctx, *cr.Status.AtProvider.ID)
+	if err != nil {
+		return errors.Wrap(err, errPlanUpdate)
+	}
+	if r == nil {
+		return errors.New(errPlanUpdate)
+	}
+
+	changes := serviceinstance.PlanUpdate(gatedSpec(cr, r), cr.Status.AtProvider.ManagedMetadata, r)
+	change, err := c.planParameters(ctx, cr, r, creds)
+	if err != nil {
+		return errors.Wrap(err, errPlanUpdate)
+	}
+	if change != "" {
+		changes = append(changes, change)
+	}
+
+	cr.SetConditions(clients.PendingUpdate("update suppressed, remove the " + clients.AnnotationKeyDryRunUpdate + " annotation to apply: " + strings.Join(changes, "; ")))
+	return nil
+}
+
+// planParameters returns the change Update would apply to the parameters or credentials
+// of the service instance, or an empty string if they are up to date. The values of
+// parameters or credentials sourced from a Secret are not included.
+func (c *external) planParameters(ctx context.Context, cr *v1alpha1.ServiceInstance, r *cfresource.ServiceInstance, creds json.RawMessage) (string, error) {
+	if !cr.Spec.EnableParameterDriftDetection {
+		if bytes.Equal(iSha256(creds), cr.Status.AtProvider.Credentials) {
+			return "", nil
+		}
+		return "parameters: changed", nil
+	}
+
+	actual, err := c.serviceinstance.GetServiceCredentials(ctx, r)
+	if err != nil {
+		return "", errors.Wrap(err, errGetParameters)
+	}
+	if jsonMatch(actual, creds, cr.Spec.ParameterComparison) {
+		return "", nil
+	}
+	if credentialsFromSecret(cr.Spec.ForProvider) {
+		return "parameters: changed", nil
+	}
+	return "parameters: " + jsonDiff(actual, creds), nil
+}
+
 // Delete attempts to delete the external resource.
 func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
 	cr, ok := mg.(*v1alpha1.ServiceInstance)
@@ -288,27 +575,91 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalDelete{}, errors.New(errWrongCRType)
 	}
 	cr.SetConditions(xpv1.Deleting())
+	clients.LoggerFrom(ctx).Debug("Deleting service instance")
+
+	if purgeDue(cr) {
+		return managed.ExternalDelete{}, c.purge(ctx, cr)
+	}
+
+	// CF deletes the bindings of a service instance with it. Deleting them
+	// first, one by one, is opt-in.
+	if cr.Spec.CascadeDeleteBindings {
+		bindings, err := c.serviceinstance.ListBindings(ctx, *cr.Status.AtProvider.ID)
+		if err != nil {
+			return managed.ExternalDelete{}, errors.Wrap(err, errListBindings)
+		}
+		if len(bindings) > 0 {
+			c.recorder.Event(cr, event.Normal(reasonDeletingBindings, "Deleting "+serviceinstance.DescribeBindings(bindings)))
+			if err := c.serviceinstance.DeleteBindings(ctx, bindings); err != nil {
+				return managed.ExternalDelete{}, errors.Wrap(err, errDeleteBindings)
+			}
+		}
+	}
 
 	if err := c.serviceinstance.Delete(ctx, cr); err != nil {
+		// the count is kept in the status, which the reconciler persists after a failed delete
+		cr.Status.AtProvider.DeleteFailures++
+		if cfresource.IsAssociationNotEmptyError(err) {
+			return managed.ExternalDelete{}, c.deletionBlocked(ctx, cr)
+		}
 		return managed.ExternalDelete{}, errors.New(errDelete)
 	}
+	cr.Status.AtProvider.DeleteFailures = 0
 	return managed.ExternalDelete{}, nil
 }
 
+// deletionBlocked reports the bindings of a service instance that CF refused
+// to delete with it by the DeletionBlocked condition.
+func (c *external) deletionBlocked(ctx context.Context, cr *v1alpha1.ServiceInstance) error {
+	bindings, err := c.serviceinstance.ListBindings(ctx, *cr.Status.AtProvider.ID)
+	if err != nil {
+		return errors.Wrap(err, errListBindings)
+	}
+	if len(bindings) == 0 {
+		return errors.New(errDelete)
+	}
+	cr.SetConditions(serviceinstance.DeletionBlocked(bindings))
+	return errors.New(errHasBindings)
+}
+
+// mergesCredentials returns true if the desired credentials of a user-provided
+// service instance are merged over its credentials in Cloud Foundry.
+func mergesCredentials(spec v1alpha1.ServiceInstanceParameters) bool {
+	return spec.Type == v1alpha1.UserProvidedService && spec.CredentialMergeStrategy == v1alpha1.CredentialMergeStrategyMerge
+}
+
+// mergeCredentials merges the desired credentials over the credentials of the
+// observed service instance. The merged credentials are applied, hashed and
+// compared like desired credentials that replace them.
+func (c *external) mergeCredentials(ctx context.Context, r *cfresource.ServiceInstance, desired []byte) ([]byte, error) {
+	actual, err := c.serviceinstance.GetServiceCredentials(ctx, r)
+	if err != nil {
+		return nil, errors.Wrap(err, errMergeCredentials)
+	}
+	merged, err := serviceinstance.MergeCredentials(actual, desired)
+	return merged, errors.Wrap(err, errMergeCredentials)
+}
+
+// publishConnectionDetails returns true if the credentials of the observed service instance are published as connection details
+func publishConnectionDetails(cr *v1alpha1.ServiceInstance, r *cfresource.ServiceInstance) bool {
+	return cr.Spec.PublishConnectionDetails && r.Type == string(v1alpha1.UserProvidedService)
+}
+
 // extractCredentialSpec returns the parameters or credentials from the spec
 func extractCredentialSpec(ctx context.Context, kube k8s.Client, spec v1alpha1.ServiceInstanceParameters) ([]byte, error) {
 	if spec.Type == v1alpha1.ManagedService {
-		if spec.Parameters != nil {
-			return spec.Parameters.Raw, nil
+		if credentialsFromSecret(spec) {
+			if spec.ParametersConfigMapRef != nil {
+				return nil, errors.New(errConfigMapAndSecret)
+			}
+			return clients.ExtractSecret(ctx, kube, spec.ParametersSecretRef.SecretReference, spec.ParametersSecretRef.Key)
 		}
 
-		if spec.JSONParams != nil {
-			return []byte(*spec.JSONParams), nil
-		}
-
-		if spec.ParametersSecretRef != nil {
-			return clients.ExtractSecret(ctx, kube, spec.ParametersSecretRef.SecretReference, spec.ParametersSecretRef.Key)
+		params, err := inlineParams(spec)
+		if err != nil || spec.ParametersConfigMapRef == nil {
+			return params, err
 		}
+		return configMapParams(ctx, kube, *spec.ParametersConfigMapRef, params)
 	}
 
 	if spec.Type == v1alpha1.UserProvidedService {
@@ -326,6 +677,68 @@ func extractCredentialSpec(ctx context.Context, kube k8s.Client, spec v1alpha1.S
 	return nil, nil
 }
 
+// inlineParams returns the parameters of a managed service instance set in its spec as JSON
+func inlineParams(spec v1alpha1.ServiceInstanceParameters) ([]byte, error) {
+	if spec.Parameters != nil {
+		return spec.Parameters.Raw, nil
+	}
+
+	if spec.JSONParams != nil {
+		return []byte(*spec.JSONParams), nil
+	}
+
+	if spec.YAMLParams != nil {
+		params, err := yamlToJSON(spec.YAMLParams.Raw)
+		return params, errors.Wrap(err, errYAMLParams)
+	}
+	return nil, nil
+}
+
+// configMapParams returns the parameters of the ConfigMap with the inline parameters merged over them
+func configMapParams(ctx context.Context, kube k8s.Client, ref v1alpha1.ConfigMapKeySelector, inline []byte) ([]byte, error) {
+	params, err := clients.ExtractConfigMap(ctx, kube, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, ref.Key)
+	if err != nil {
+		return nil, errors.Wrap(err, errParamsConfigMap)
+	}
+	merged, err := serviceinstance.MergeCredentials(params, inline)
+	return merged, errors.Wrap(err, errParamsConfigMap)
+}
+
+// yamlToJSON converts YAML parameters to canonical JSON, so that they are
+// sent, hashed and compared just like JSON parameters. The parameters are
+// either structured YAML or a string holding a YAML document.
+func yamlToJSON(raw []byte) ([]byte, error) {
+	useNumber := func(d *json.Decoder) *json.Decoder {
+		d.UseNumber()
+		return d
+	}
+	var v any
+	if err := yaml.Unmarshal(raw, &v, useNumber); err != nil {
+		return nil, err
+	}
+	if doc, ok := v.(string); ok {
+		v = nil
+		if err := yaml.Unmarshal([]byte(doc), &v, useNumber); err != nil {
+			return nil, err
+		}
+	}
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+// credentialsFromSecret returns true if extractCredentialSpec reads the parameters or credentials from a Secret
+func credentialsFromSecret(spec v1alpha1.ServiceInstanceParameters) bool {
+	switch spec.Type {
+	case v1alpha1.ManagedService:
+		return spec.Parameters == nil && spec.JSONParams == nil && spec.YAMLParams == nil && spec.ParametersSecretRef != nil
+	case v1alpha1.UserProvidedService:
+		return spec.Credentials == nil && spec.JSONCredentials == nil && spec.CredentialsSecretRef != nil
+	}
+	return false
+}
+
 // jsonContain returns true if the first JSON message is a superset or identical to the second JSON message
 func jsonContain(a, b []byte) bool {
 	// if b is "{}", it is considered as empty
@@ -343,6 +756,44 @@ func jsonContain(a, b []byte) bool {
 	return diff == jsondiff.FullMatch || diff == jsondiff.SupersetMatch
 }
 
+// jsonEqual returns true if the first JSON message is identical to the second JSON message
+func jsonEqual(a, b []byte) bool {
+	// if a is nil it is considered as intention to break reconciliation
+	if a == nil {
+		return true
+	}
+
+	opt := jsondiff.DefaultConsoleOptions()
+	diff, _ := jsondiff.Compare(emptyAsObject(a), emptyAsObject(b), &opt)
+	return diff == jsondiff.FullMatch
+}
+
+// emptyAsObject returns "{}" for an empty JSON message
+func emptyAsObject(m []byte) []byte {
+	if len(m) == 0 {
+		return []byte("{}")
+	}
+	return m
+}
+
+// jsonDiff returns the differences between the first and the second JSON message
+func jsonDiff(a, b []byte) string {
+	opt := jsondiff.DefaultJSONOptions()
+	opt.SkipMatches = true
+	opt.Indent = ""
+	_, diff := jsondiff.Compare(emptyAsObject(a), emptyAsObject(b), &opt)
+	return strings.ReplaceAll(strings.TrimSpace(diff), "\n", " ")
+}
+
+// jsonMatch compares the observed JSON message a to the desired JSON message b
+// according to the given comparison, which defaults to Subset
+func jsonMatch(a, b []byte, c v1alpha1.ParameterComparison) bool {
+	if c == v1alpha1.ParameterComparisonExact {
+		return jsonEqual(a, b)
+	}
+	return jsonContain(a, b)
+}
+
 type spaceInitializer struct {
 	kube k8s.Client
 }
@@ -353,12 +804,12 @@ func (c spaceInitializer) Initialize(ctx context.Context, mg resource.Managed) e
 	if !ok {
 		return errors.New(errWrongCRType)
 	}
-
-	if cr.Spec.ForProvider.SpaceRef != nil || cr.Spec.ForProvider.SpaceSelector != nil {
-		return cr.ResolveReferences(ctx, c.kube)
+	// A service instance imported by its GUID is observed without its space
+	if clients.IsObserveOnly(cr) && clients.IsValidGUID(meta.GetExternalName(cr)) {
+		return nil
 	}
 
-	return space.ResolveByName(ctx, clients.ClientFnBuilder(ctx, c.kube), mg)
+	return space.ResolveSpaceReference(ctx, c.kube, mg)
 }
 
 // A servicePlanInitializer is expected to initialize the service plan of a ServiceInstance
@@ -372,7 +823,8 @@ func (s servicePlanInitializer) Initialize(ctx context.Context, mg resource.Mana
 	if !ok {
 		return errors.New(errWrongCRType)
 	}
-	if cr.Spec.ForProvider.Type != "managed" {
+	// The service plan is only needed to create or update a managed service instance
+	if cr.Spec.ForProvider.Type != "managed" || clients.IsObserveOnly(cr) {
 		return nil
 	}
 
@@ -384,20 +836,17 @@ func (s servicePlanInitializer) Initialize(ctx context.Context, mg resource.Mana
 			return errors.Wrapf(err, errNewClient)
 		}
 
-		opt := client.NewServicePlanListOptions()
-		if cr.Spec.ForProvider.ServicePlan.Offering != nil {
-			opt.ServiceOfferingNames.EqualTo(*cr.Spec.ForProvider.ServicePlan.Offering)
-		}
-		if cr.Spec.ForProvider.ServicePlan.Plan != nil {
-			opt.Names.EqualTo(*cr.Spec.ForProvider.ServicePlan.Plan)
-		}
-		sp, err := cf.ServicePlans.Single(ctx, opt)
+		sp, err := serviceinstance.ResolveServicePlan(ctx, cf.ServicePlans, *cr.Spec.ForProvider.ServicePlan, cr.Status.AtProvider.ServicePlan)
 		if err != nil {
-			return errors.Wrapf(err, "Cannot initialize service plan using serviceName/servicePlanName: %s:%s`", *cr.Spec.ForProvider.ServicePlan.Offering, *cr.Spec.ForProvider.ServicePlan.Plan)
+			return errors.Wrap(err, errInitServicePlan)
 		}
 
 		cr.Spec.ForProvider.ServicePlan.ID = &sp.GUID
 
+		if err := validateParameterSchema(ctx, s.kube, cr, sp); err != nil {
+			return err
+		}
+
 		return s.kube.Update(ctx, cr)
 	}
 
@@ -413,6 +862,23 @@ func (s servicePlanInitializer) Initialize(ctx context.Context, mg resource.Mana
 	return errors.New(errMissingServicePlan)
 }
 
+// validateParameterSchema validates the parameters of a service instance that
+// is yet to be created against the create parameter schema of its service
+// plan, if the service instance opts in.
+func validateParameterSchema(ctx context.Context, kube k8s.Client, cr *v1alpha1.ServiceInstance, sp *cfresource.ServicePlan) error {
+	if !cr.Spec.ValidateParameterSchema {
+		return nil
+	}
+	if _, err := uuid.Parse(meta.GetExternalName(cr)); err == nil {
+		return nil
+	}
+	params, err := extractCredentialSpec(ctx, kube, cr.Spec.ForProvider)
+	if err != nil {
+		return errors.Wrap(err, errResolveParams)
+	}
+	return serviceinstance.ValidateParameters(sp.Schemas.ServiceInstance.Create.Parameters, params)
+}
+
 // Small wrapper around sha256.Sum256()
 // info: if creds == nil, it will result in a hash value anyway (e3b0c44298...).
 // This should not be a security problem.
@@ -2,21 +2,34 @@ package serviceinstance
 
 import (
 	"context"
+	"encoding/json"
 	"net/url"
 	"testing"
+	"time"
 
+	cfresource "github.com/cloudfoundry/go-cfclient/v3/resource"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
 	k8s "sigs.k8s.io/controller-runtime/pkg/client"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpfake "github.com/crossplane/crossplane-runtime/v2/pkg/resource/fake"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/fake"
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/serviceinstance"
 )
@@ -27,7 +40,11 @@ var (
 	spaceGUID       = "a46808d1-d09a-4eef-add1-30872dec82f7"
 	guid            = "2d8b0d04-d537-4e4e-8c6f-f09ca0e7f56f"
 	servicePlan     = "c595293f-2696-438d-887e-053200ec47c8"
+	newServicePlan  = "5e3a4f2c-3b6e-4a39-9f3b-0f6f2b6a7d1e"
 	jsonCredentials = `{"json":"bar"}`
+
+	dryRunPayload         = `{"type":"managed","name":"my-service-instance","relationships":{"service_plan":{"data":{"guid":"c595293f-2696-438d-887e-053200ec47c8"}},"space":{"data":{"guid":"a46808d1-d09a-4eef-add1-30872dec82f7"}}},"parameters":{"json":"bar"}}`
+	dryRunPayloadRedacted = `{"type":"managed","name":"my-service-instance","relationships":{"service_plan":{"data":{"guid":"c595293f-2696-438d-887e-053200ec47c8"}},"space":{"data":{"guid":"a46808d1-d09a-4eef-add1-30872dec82f7"}}},"parameters":"REDACTED"}`
 )
 
 type modifier func(*v1alpha1.ServiceInstance)
@@ -38,6 +55,24 @@ func withExternalName(name string) modifier {
 	}
 }
 
+func withDryRun() modifier {
+	return func(r *v1alpha1.ServiceInstance) {
+		r.ObjectMeta.Annotations[clients.AnnotationKeyDryRun] = "true"
+	}
+}
+
+func withDryRunUpdate() modifier {
+	return func(r *v1alpha1.ServiceInstance) {
+		r.ObjectMeta.Annotations[clients.AnnotationKeyDryRunUpdate] = "true"
+	}
+}
+
+func withPaused() modifier {
+	return func(r *v1alpha1.ServiceInstance) {
+		r.ObjectMeta.Annotations[clients.AnnotationKeyPaused] = "true"
+	}
+}
+
 func withCredentials(credentials *string) modifier {
 	return func(r *v1alpha1.ServiceInstance) {
 		switch r.Spec.ForProvider.Type {
@@ -55,6 +90,12 @@ func withServicePlan(servicePlan v1alpha1.ServicePlanParameters) modifier {
 	}
 }
 
+func withName(n string) modifier {
+	return func(r *v1alpha1.ServiceInstance) {
+		r.Spec.ForProvider.Name = &n
+	}
+}
+
 func withSpace(spaceGUID string) modifier {
 	return func(r *v1alpha1.ServiceInstance) {
 		r.Spec.ForProvider.Space = &spaceGUID
@@ -71,18 +112,79 @@ func withStatus(s v1alpha1.ServiceInstanceObservation) modifier {
 	}
 }
 
+func withLastOperation(typ, state string) modifier {
+	return func(r *v1alpha1.ServiceInstance) {
+		r.Status.AtProvider.LastOperation = v1alpha1.LastOperation{Type: typ, State: state}
+	}
+}
+
+// withCreateFailures records failed creates of the spec set by the preceding modifiers.
+func withCreateFailures(n int32, last time.Time) modifier {
+	return func(r *v1alpha1.ServiceInstance) {
+		h := clients.SpecHash(r.Spec.ForProvider)
+		r.Status.AtProvider.CreateFailures = n
+		r.Status.AtProvider.LastCreateFailure = &metav1.Time{Time: last}
+		r.Status.AtProvider.CreateFailureSpecHash = &h
+	}
+}
+
 func withParameters(params string) modifier {
 	return func(r *v1alpha1.ServiceInstance) {
 		r.Spec.ForProvider.JSONParams = &params
 	}
 }
 
+func withYAMLParams(raw string) modifier {
+	return func(r *v1alpha1.ServiceInstance) {
+		r.Spec.ForProvider.YAMLParams = &runtime.RawExtension{Raw: []byte(raw)}
+	}
+}
+
+func withParametersSecretRef() modifier {
+	return func(r *v1alpha1.ServiceInstance) {
+		r.Spec.ForProvider.ParametersSecretRef = &v1alpha1.SecretKeySelector{
+			SecretReference: &xpv1.SecretReference{Name: "params", Namespace: "default"},
+			Key:             "params",
+		}
+	}
+}
+
+func withParametersConfigMapRef() modifier {
+	return func(r *v1alpha1.ServiceInstance) {
+		r.Spec.ForProvider.ParametersConfigMapRef = &v1alpha1.ConfigMapKeySelector{Name: "params", Namespace: "default", Key: "params"}
+	}
+}
+
 func withDriftDetection(d bool) modifier {
 	return func(r *v1alpha1.ServiceInstance) {
 		r.Spec.EnableParameterDriftDetection = d
 	}
 }
 
+func withParameterComparison(c v1alpha1.ParameterComparison) modifier {
+	return func(r *v1alpha1.ServiceInstance) {
+		r.Spec.ParameterComparison = c
+	}
+}
+
+func withPublishConnectionDetails() modifier {
+	return func(r *v1alpha1.ServiceInstance) {
+		r.Spec.PublishConnectionDetails = true
+	}
+}
+
+func withConnectionSecret(name string) modifier {
+	return func(r *v1alpha1.ServiceInstance) {
+		r.Spec.WriteConnectionSecretToReference = &xpv1.LocalSecretReference{Name: name}
+	}
+}
+
+func withValidateParameterSchema() modifier {
+	return func(r *v1alpha1.ServiceInstance) {
+		r.Spec.ValidateParameterSchema = true
+	}
+}
+
 func withDeletionTimestamp() modifier {
 	return func(r *v1alpha1.ServiceInstance) {
 		ts := metav1.Now()
@@ -304,7 +406,7 @@ func TestObserve(t *testing.T) {
 					withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid, ServicePlan: &servicePlan}),
 					withConditions(xpv1.Available()),
 				),
-				obs: managed.ExternalObservation{ResourceExists: false, ResourceUpToDate: true},
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
 				err: nil,
 			},
 			service: func() *fake.MockServiceInstance {
@@ -324,6 +426,40 @@ func TestObserve(t *testing.T) {
 				return m
 			},
 		},
+		"CreateFailedBackoffElapsed": {
+			args: args{
+				mg: serviceInstance("managed", withExternalName(guid), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withLastOperation(v1alpha1.LastOperationCreate, v1alpha1.LastOperationFailed), withCreateFailures(1, time.Now().Add(-time.Hour))),
+			},
+			want: want{
+				obs: managed.ExternalObservation{ResourceExists: false, ResourceUpToDate: true},
+				err: nil,
+			},
+			service: func() *fake.MockServiceInstance {
+				m := &fake.MockServiceInstance{}
+				m.On("Get", guid).Return(
+					&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).SetLastOperation(v1alpha1.LastOperationCreate, v1alpha1.LastOperationFailed).ServiceInstance,
+					nil,
+				)
+				return m
+			},
+		},
+		"NotFoundCreateBackoff": {
+			args: args{
+				mg: serviceInstance("managed", withExternalName(guid), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withCreateFailures(1, time.Now())),
+			},
+			want: want{
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				err: nil,
+			},
+			service: func() *fake.MockServiceInstance {
+				m := &fake.MockServiceInstance{}
+				m.On("Get", guid).Return(
+					fake.ServiceInstanceNil,
+					fake.ErrNoResultReturned,
+				)
+				return m
+			},
+		},
 		"UpdateFailed": {
 			args: args{
 				mg: serviceInstance("managed", withExternalName(guid), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan})),
@@ -355,6 +491,23 @@ func TestObserve(t *testing.T) {
 				return m
 			},
 		},
+		"Renamed": {
+			args: args{
+				mg: serviceInstance("managed", withName("renamed"), withExternalName(guid), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan})),
+			},
+			want: want{
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				err: nil,
+			},
+			service: func() *fake.MockServiceInstance {
+				m := &fake.MockServiceInstance{}
+				m.On("Get", guid).Return(
+					&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).SetLastOperation(v1alpha1.LastOperationCreate, v1alpha1.LastOperationSucceeded).ServiceInstance,
+					nil,
+				)
+				return m
+			},
+		},
 		"InProgress": {
 			args: args{
 				mg: serviceInstance("managed", withExternalName(guid), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan})),
@@ -459,20 +612,20 @@ func TestObserve(t *testing.T) {
 				return m
 			},
 		},
-		"DriftDetectionBreak": {
+		"DriftDetectionYAMLParams": {
 			args: args{
-				mg: serviceInstance("managed", withExternalName(guid), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withParameters("{\"foo\":\"bar\", \"baz\": 1}"), withDriftDetection(false), withStatus(v1alpha1.ServiceInstanceObservation{Credentials: iSha256([]byte("{\"foo\":\"bar\", \"baz\": 1}"))})),
+				mg: serviceInstance("managed", withExternalName(guid), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withYAMLParams(`"foo: bar\nbaz: 1\n"`), withDriftDetection(true)),
 			},
 			want: want{
 				mg: serviceInstance("managed",
 					withExternalName(guid),
 					withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}),
-					withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid, ServicePlan: &servicePlan, Credentials: iSha256([]byte("{\"foo\":\"bar\", \"baz\": 1}"))}),
+					withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid, ServicePlan: &servicePlan, Credentials: iSha256(*fake.JSONRawMessage("{\"foo\":\"bar\"}"))}),
 					withConditions(xpv1.Available()),
-					withParameters("{\"foo\":\"bar\", \"baz\": 1}"),
-					withDriftDetection(false),
+					withYAMLParams(`"foo: bar\nbaz: 1\n"`),
+					withDriftDetection(true),
 				),
-				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
 				err: nil,
 			},
 			service: func() *fake.MockServiceInstance {
@@ -481,271 +634,549 @@ func TestObserve(t *testing.T) {
 					&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).SetLastOperation(v1alpha1.LastOperationCreate, v1alpha1.LastOperationSucceeded).ServiceInstance,
 					nil,
 				)
-				m.On("Single").Return(
-					&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).SetLastOperation(v1alpha1.LastOperationCreate, v1alpha1.LastOperationSucceeded).ServiceInstance,
-					nil,
-				)
 				m.On("GetManagedParameters", guid).Return(
 					fake.JSONRawMessage("{\"foo\":\"bar\"}"),
 					nil, // no error
 				)
 				return m
 			},
-		}}
-
-	for n, tc := range cases {
-		t.Run(n, func(t *testing.T) {
-			t.Logf("Testing: %s", t.Name())
-			c := &external{
-				kube: &test.MockClient{
-					MockUpdate: test.NewMockUpdateFn(nil),
-				},
-				serviceinstance: &serviceinstance.Client{
-					ServiceInstance: tc.service(),
-					Job:             nil,
-				},
-			}
-			obs, err := c.Observe(context.Background(), tc.args.mg)
-
-			if tc.want.err != nil && err != nil {
-				// the case where our mock server returns error.
-				if diff := cmp.Diff(tc.want.err.Error(), err.Error()); diff != "" {
-					t.Errorf("Observe(...): want error string != got error string:\n%s", diff)
-				}
-			} else {
-				if diff := cmp.Diff(tc.want.err, err); diff != "" {
-					t.Errorf("Observe(...): want error != got error:\n%s", diff)
-				}
-			}
-			if diff := cmp.Diff(tc.want.obs, obs); diff != "" {
-				t.Errorf("Observe(...): -want, +got:\n%s", diff)
-			}
-		})
-	}
-}
-
-type timeoutError struct {
-	timeout bool
-}
-
-func (e *timeoutError) Error() string { return "timeout error" }
-func (e *timeoutError) Timeout() bool { return e.timeout }
-
-func TestCreate(t *testing.T) {
-	type service func() *fake.MockServiceInstance
-	type job func() *fake.MockJob
-	type args struct {
-		mg resource.Managed
-	}
-
-	type want struct {
-		mg  resource.Managed
-		obs managed.ExternalCreation
-		err error
-	}
-
-	cases := map[string]struct {
-		args    args
-		want    want
-		service service
-		job
-		kube k8s.Client
-	}{
-		"Successful": {
+		},
+		"DriftDetectionYAMLParamsUpToDate": {
 			args: args{
-				mg: serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan})),
+				mg: serviceInstance("managed", withExternalName(guid), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withYAMLParams(`"foo: bar\nbaz: 1\n"`), withDriftDetection(true)),
 			},
 			want: want{
-				mg:  serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withConditions(xpv1.Creating()), withExternalName(guid)),
-				obs: managed.ExternalCreation{},
+				mg: serviceInstance("managed",
+					withExternalName(guid),
+					withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}),
+					withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid, ServicePlan: &servicePlan, Credentials: iSha256(*fake.JSONRawMessage("{\"baz\":1,\"foo\":\"bar\"}"))}),
+					withConditions(xpv1.Available()),
+					withYAMLParams(`"foo: bar\nbaz: 1\n"`),
+					withDriftDetection(true),
+				),
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
 				err: nil,
 			},
 			service: func() *fake.MockServiceInstance {
 				m := &fake.MockServiceInstance{}
-				m.On("CreateManaged").Return(
-					"JOB123",
-					nil,
-				)
-				m.On("Single").Return(
-					&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).ServiceInstance,
+				m.On("Get", guid).Return(
+					&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).SetLastOperation(v1alpha1.LastOperationCreate, v1alpha1.LastOperationSucceeded).ServiceInstance,
 					nil,
 				)
 				m.On("GetManagedParameters", guid).Return(
-					nil,
+					fake.JSONRawMessage("{\"baz\":1,\"foo\":\"bar\"}"),
 					nil, // no error
 				)
 				return m
 			},
-			job: func() *fake.MockJob {
-				m := &fake.MockJob{}
-				m.On("PollComplete").Return(nil)
-				return m
-			},
 		},
-		"SuccessfulWithParams": {
+		"DriftDetectionExact": {
 			args: args{
-				mg: serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withCredentials(&jsonCredentials)),
+				mg: serviceInstance("managed", withExternalName(guid), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withParameters("{\"foo\":\"bar\"}"), withDriftDetection(true), withParameterComparison(v1alpha1.ParameterComparisonExact)),
 			},
 			want: want{
-				mg:  serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withCredentials(&jsonCredentials), withConditions(xpv1.Creating()), withExternalName(guid), withStatus(v1alpha1.ServiceInstanceObservation{Credentials: iSha256([]byte(jsonCredentials))})),
-				obs: managed.ExternalCreation{},
+				mg: serviceInstance("managed",
+					withExternalName(guid),
+					withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}),
+					withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid, ServicePlan: &servicePlan, Credentials: iSha256(*fake.JSONRawMessage("{\"foo\":\"bar\", \"baz\": 1}"))}),
+					withConditions(xpv1.Available()),
+					withParameters("{\"foo\":\"bar\"}"),
+					withDriftDetection(true),
+					withParameterComparison(v1alpha1.ParameterComparisonExact),
+				),
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
 				err: nil,
 			},
 			service: func() *fake.MockServiceInstance {
 				m := &fake.MockServiceInstance{}
-				m.On("CreateManaged").Return(
-					"JOB123",
-					nil,
-				)
-				m.On("Single").Return(
-					&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).ServiceInstance,
+				m.On("Get", guid).Return(
+					&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).SetLastOperation(v1alpha1.LastOperationCreate, v1alpha1.LastOperationSucceeded).ServiceInstance,
 					nil,
 				)
 				m.On("GetManagedParameters", guid).Return(
-					fake.JSONRawMessage(jsonCredentials),
+					fake.JSONRawMessage("{\"foo\":\"bar\", \"baz\": 1}"),
 					nil, // no error
 				)
 				return m
 			},
-			job: func() *fake.MockJob {
-				m := &fake.MockJob{}
-				m.On("PollComplete").Return(nil)
-				return m
-			},
 		},
-		"HTTPClientTimeout": {
+		"DriftDetectionBreak": {
 			args: args{
-				mg: serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withCredentials(&jsonCredentials)),
+				mg: serviceInstance("managed", withExternalName(guid), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withParameters("{\"foo\":\"bar\", \"baz\": 1}"), withDriftDetection(false), withStatus(v1alpha1.ServiceInstanceObservation{Credentials: iSha256([]byte("{\"foo\":\"bar\", \"baz\": 1}"))})),
 			},
 			want: want{
-				mg:  serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withCredentials(&jsonCredentials), withConditions(xpv1.Creating()), withExternalName(guid), withStatus(v1alpha1.ServiceInstanceObservation{Credentials: iSha256([]byte(jsonCredentials))})),
-				obs: managed.ExternalCreation{},
+				mg: serviceInstance("managed",
+					withExternalName(guid),
+					withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}),
+					withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid, ServicePlan: &servicePlan, Credentials: iSha256([]byte("{\"foo\":\"bar\", \"baz\": 1}"))}),
+					withConditions(xpv1.Available()),
+					withParameters("{\"foo\":\"bar\", \"baz\": 1}"),
+					withDriftDetection(false),
+				),
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
 				err: nil,
 			},
 			service: func() *fake.MockServiceInstance {
 				m := &fake.MockServiceInstance{}
-				m.On("CreateManaged").Return(
-					"JOB123",
+				m.On("Get", guid).Return(
+					&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).SetLastOperation(v1alpha1.LastOperationCreate, v1alpha1.LastOperationSucceeded).ServiceInstance,
 					nil,
 				)
 				m.On("Single").Return(
-					&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).ServiceInstance,
+					&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).SetLastOperation(v1alpha1.LastOperationCreate, v1alpha1.LastOperationSucceeded).ServiceInstance,
 					nil,
 				)
 				m.On("GetManagedParameters", guid).Return(
-					fake.JSONRawMessage(jsonCredentials),
+					fake.JSONRawMessage("{\"foo\":\"bar\"}"),
 					nil, // no error
 				)
 				return m
 			},
-			job: func() *fake.MockJob {
-				m := &fake.MockJob{}
-				m.On("PollComplete").Return(&url.Error{
-					Err: &timeoutError{true},
-				})
-				return m
-			},
 		},
-		"CannotPollCreationJob": {
+		"ParametersSecretRefRemoved": {
 			args: args{
-				mg: serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan})),
+				mg: serviceInstance("managed", withExternalName(guid), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withStatus(v1alpha1.ServiceInstanceObservation{Credentials: iSha256([]byte("{\"foo\":\"bar\"}"))})),
 			},
 			want: want{
-				mg:  serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withConditions(xpv1.Creating())),
-				obs: managed.ExternalCreation{},
-				err: errors.Wrap(errBoom, errCreate),
+				mg: serviceInstance("managed",
+					withExternalName(guid),
+					withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}),
+					withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid, ServicePlan: &servicePlan, Credentials: iSha256([]byte("{\"foo\":\"bar\"}"))}),
+					withConditions(xpv1.Available()),
+				),
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				err: nil,
 			},
 			service: func() *fake.MockServiceInstance {
 				m := &fake.MockServiceInstance{}
-				m.On("CreateManaged").Return(
-					"JOB123",
+				m.On("Get", guid).Return(
+					&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).SetLastOperation(v1alpha1.LastOperationUpdate, v1alpha1.LastOperationSucceeded).ServiceInstance,
 					nil,
 				)
-				m.On("Single").Return(
-					&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).ServiceInstance,
+				return m
+			},
+		},
+		"PublishConnectionDetails": {
+			args: args{
+				mg: serviceInstance("user-provided", withExternalName(guid), withSpace(spaceGUID), withPublishConnectionDetails()),
+			},
+			want: want{
+				obs: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+					ConnectionDetails: managed.ConnectionDetails{
+						"user":    []byte("admin"),
+						"db_port": []byte("5432"),
+					},
+				},
+				err: nil,
+			},
+			service: func() *fake.MockServiceInstance {
+				m := &fake.MockServiceInstance{}
+				m.On("Get", guid).Return(
+					&fake.NewServiceInstance("user-provided").SetName(name).SetGUID(guid).SetLastOperation(v1alpha1.LastOperationCreate, v1alpha1.LastOperationSucceeded).ServiceInstance,
 					nil,
 				)
-				m.On("GetManagedParameters", guid).Return(
+				m.On("GetUserProvidedCredentials", guid).Return(
+					fake.JSONRawMessage(`{"user":"admin","db":{"port":5432}}`),
 					nil,
-					nil, // no error
 				)
 				return m
 			},
-			job: func() *fake.MockJob {
-				m := &fake.MockJob{}
-				m.On("PollComplete").Return(errBoom)
+		},
+		"PublishConnectionDetailsRemovesStaleKeys": {
+			args: args{
+				mg: serviceInstance("user-provided", withExternalName(guid), withSpace(spaceGUID), withPublishConnectionDetails(), withConnectionSecret("conn")),
+			},
+			want: want{
+				obs: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+					ConnectionDetails: managed.ConnectionDetails{
+						"user":     []byte("admin"),
+						"db_port":  []byte("5432"),
+						"password": nil,
+					},
+				},
+				err: nil,
+			},
+			service: func() *fake.MockServiceInstance {
+				m := &fake.MockServiceInstance{}
+				m.On("Get", guid).Return(
+					&fake.NewServiceInstance("user-provided").SetName(name).SetGUID(guid).SetLastOperation(v1alpha1.LastOperationCreate, v1alpha1.LastOperationSucceeded).ServiceInstance,
+					nil,
+				)
+				m.On("GetUserProvidedCredentials", guid).Return(
+					fake.JSONRawMessage(`{"user":"admin","db":{"port":5432}}`),
+					nil,
+				)
 				return m
 			},
 		},
-		"AlreadyExist": {
+		"PublishConnectionDetailsNoCredentials": {
 			args: args{
-				mg: serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan})),
+				mg: serviceInstance("user-provided", withExternalName(guid), withSpace(spaceGUID), withPublishConnectionDetails()),
 			},
 			want: want{
-				mg:  serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withConditions(xpv1.Creating())),
-				obs: managed.ExternalCreation{},
-				err: errors.Wrap(errBoom, errCreate),
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				err: nil,
 			},
 			service: func() *fake.MockServiceInstance {
 				m := &fake.MockServiceInstance{}
-				m.On("CreateManaged").Return(
-					"JOB123",
-					errBoom,
-				)
-				m.On("Single").Return(
-					&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).ServiceInstance,
+				m.On("Get", guid).Return(
+					&fake.NewServiceInstance("user-provided").SetName(name).SetGUID(guid).SetLastOperation(v1alpha1.LastOperationCreate, v1alpha1.LastOperationSucceeded).ServiceInstance,
 					nil,
 				)
-				m.On("GetManagedParameters", guid).Return(
+				m.On("GetUserProvidedCredentials", guid).Return(
+					fake.JSONRawMessage("{}"),
 					nil,
-					nil, // no error
 				)
 				return m
 			},
-			job: func() *fake.MockJob {
-				m := &fake.MockJob{}
-				m.On("PollComplete").Return(nil)
+		},
+		"PublishConnectionDetailsManaged": {
+			args: args{
+				mg: serviceInstance("managed", withExternalName(guid), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withPublishConnectionDetails()),
+			},
+			want: want{
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				err: nil,
+			},
+			service: func() *fake.MockServiceInstance {
+				m := &fake.MockServiceInstance{}
+				m.On("Get", guid).Return(
+					&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).SetLastOperation(v1alpha1.LastOperationCreate, v1alpha1.LastOperationSucceeded).ServiceInstance,
+					nil,
+				)
 				return m
 			},
-		},
-	}
+		}}
 
 	for n, tc := range cases {
 		t.Run(n, func(t *testing.T) {
 			t.Logf("Testing: %s", t.Name())
 			c := &external{
+				recorder: event.NewNopRecorder(),
 				kube: &test.MockClient{
-					MockUpdate:       test.NewMockUpdateFn(nil),
-					MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+					MockUpdate: test.NewMockUpdateFn(nil),
+					// the connection secret still holds a key that is no longer published
+					MockGet: func(_ context.Context, key k8s.ObjectKey, obj k8s.Object) error {
+						s, ok := obj.(*corev1.Secret)
+						if !ok || key.Name != "conn" {
+							return errBoom
+						}
+						s.Data = map[string][]byte{"user": []byte("root"), "password": []byte("secret")}
+						return nil
+					},
 				},
 				serviceinstance: &serviceinstance.Client{
 					ServiceInstance: tc.service(),
-					Job:             tc.job(),
+					Job:             nil,
 				},
 			}
-			obs, err := c.Create(context.Background(), tc.args.mg)
+			obs, err := c.Observe(context.Background(), tc.args.mg)
 
 			if tc.want.err != nil && err != nil {
 				// the case where our mock server returns error.
 				if diff := cmp.Diff(tc.want.err.Error(), err.Error()); diff != "" {
-					t.Errorf("Create(...): want error string != got error string:\n%s", diff)
+					t.Errorf("Observe(...): want error string != got error string:\n%s", diff)
 				}
 			} else {
 				if diff := cmp.Diff(tc.want.err, err); diff != "" {
-					t.Errorf("Create(...): want error != got error:\n%s", diff)
+					t.Errorf("Observe(...): want error != got error:\n%s", diff)
 				}
 			}
 			if diff := cmp.Diff(tc.want.obs, obs); diff != "" {
-				t.Errorf("Create(...): -want, +got:\n%s", diff)
+				t.Errorf("Observe(...): -want, +got:\n%s", diff)
 			}
-			if diff := cmp.Diff(tc.want.mg, tc.args.mg); diff != "" {
-				t.Errorf("Create(...): -want, +got:\n%s", diff)
+		})
+	}
+}
+
+func TestObservePausedDrift(t *testing.T) {
+	m := &fake.MockServiceInstance{}
+	m.On("Get", guid).Return(
+		&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).SetLastOperation(v1alpha1.LastOperationCreate, v1alpha1.LastOperationSucceeded).ServiceInstance,
+		nil,
+	)
+	m.On("Single").Return(
+		&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).SetLastOperation(v1alpha1.LastOperationCreate, v1alpha1.LastOperationSucceeded).ServiceInstance,
+		nil,
+	)
+	m.On("GetManagedParameters", guid).Return(fake.JSONRawMessage(`{"foo":"bar"}`), nil)
+
+	connecter := clients.WithPause(managed.ExternalConnectorFn(func(context.Context, resource.Managed) (managed.ExternalClient, error) {
+		return &external{
+			recorder:        event.NewNopRecorder(),
+			kube:            &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+			serviceinstance: &serviceinstance.Client{ServiceInstance: m},
+		}, nil
+	}))
+	c, err := connecter.Connect(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Connect(...): unexpected error: %v", err)
+	}
+
+	// the parameters drifted from {"foo":"bar"} but the paused service instance is reported up to date
+	mg := serviceInstance("managed", withPaused(), withExternalName(guid), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withParameters(`{"foo":"bar", "baz": 1}`), withDriftDetection(true))
+	obs, err := c.Observe(context.Background(), mg)
+	if err != nil {
+		t.Fatalf("Observe(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, obs); diff != "" {
+		t.Errorf("Observe(...): -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff(clients.ReconcilePaused(), mg.GetCondition(clients.TypeReconcilePaused), test.EquateConditions()); diff != "" {
+		t.Errorf("Observe(...): -want condition, +got:\n%s", diff)
+	}
+
+	if _, err := c.Update(context.Background(), mg); err != nil {
+		t.Fatalf("Update(...): unexpected error: %v", err)
+	}
+	m.AssertNotCalled(t, "UpdateManaged", guid)
+}
+
+func TestObserveInProgressMessage(t *testing.T) {
+	cases := map[string]struct {
+		description string
+		want        string
+	}{
+		"Percentage": {
+			description: "Provisioning: 42% complete",
+			want:        "create in progress (42%): Provisioning: 42% complete",
+		},
+		"Step": {
+			description: "Step 2 of 5: creating the database",
+			want:        "create in progress (step 2 of 5): Step 2 of 5: creating the database",
+		},
+		"PercentageAndStep": {
+			description: "step 3/4, 75%",
+			want:        "create in progress (75%, step 3 of 4): step 3/4, 75%",
+		},
+		"NoProgress": {
+			description: "Provisioning in progress",
+			want:        "Provisioning in progress",
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			r := fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).SetLastOperation(v1alpha1.LastOperationCreate, v1alpha1.LastOperationInProgress)
+			r.LastOperation.Description = tc.description
+			m := &fake.MockServiceInstance{}
+			m.On("Get", guid).Return(&r.ServiceInstance, nil)
+			c := &external{
+				recorder:        event.NewNopRecorder(),
+				kube:            &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				serviceinstance: &serviceinstance.Client{ServiceInstance: m},
+			}
+
+			mg := serviceInstance("managed", withExternalName(guid), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}))
+			if _, err := c.Observe(context.Background(), mg); err != nil {
+				t.Fatalf("Observe(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(xpv1.Unavailable().WithMessage(tc.want), mg.GetCondition(xpv1.TypeReady), test.EquateConditions()); diff != "" {
+				t.Errorf("Observe(...): -want condition, +got:\n%s", diff)
 			}
 		})
 	}
 }
 
-func TestUpdate(t *testing.T) {
+func TestObserveAdoptSeedsCredentials(t *testing.T) {
+	m := &fake.MockServiceInstance{}
+	m.On("Get", "not-guid").Return(fake.ServiceInstanceNil, fake.ErrNoResultReturned)
+	m.On("Single").Return(
+		&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).SetLastOperation(v1alpha1.LastOperationCreate, v1alpha1.LastOperationSucceeded).ServiceInstance,
+		nil,
+	)
+	m.On("Get", guid).Return(
+		&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).SetLastOperation(v1alpha1.LastOperationCreate, v1alpha1.LastOperationSucceeded).ServiceInstance,
+		nil,
+	)
+	c := &external{
+		recorder:        event.NewNopRecorder(),
+		kube:            &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+		serviceinstance: &serviceinstance.Client{ServiceInstance: m},
+	}
+	cr := serviceInstance("managed", withExternalName("not-guid"), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withCredentials(&jsonCredentials))
+
+	// the first reconcile adopts the service instance by its spec
+	obs, err := c.Observe(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("Observe(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, obs); diff != "" {
+		t.Errorf("Observe(...): adopt: -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff(iSha256([]byte(jsonCredentials)), cr.Status.AtProvider.Credentials); diff != "" {
+		t.Errorf("Observe(...): adopt: -want credentials hash, +got:\n%s", diff)
+	}
+
+	// the following reconcile observes the adopted service instance by its GUID
+	obs, err = c.Observe(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("Observe(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, obs); diff != "" {
+		t.Errorf("Observe(...): after adopt: -want, +got:\n%s", diff)
+	}
+}
+
+func TestCredentialMergeStrategy(t *testing.T) {
+	actual := `{"user":"admin","password":"old"}`
+	desired := `{"password":"new"}`
+
+	cases := map[string]struct {
+		strategy v1alpha1.CredentialMergeStrategy
+		// the credentials of the service instance after the update
+		updated  string
+		wantHash []byte
+		// whether exact drift detection reports the updated service instance as up to date
+		wantUpToDate bool
+	}{
+		"Replace": {
+			strategy:     v1alpha1.CredentialMergeStrategyReplace,
+			updated:      desired,
+			wantHash:     iSha256([]byte(desired)),
+			wantUpToDate: true,
+		},
+		"ReplaceKeepsNoOtherKeys": {
+			strategy:     v1alpha1.CredentialMergeStrategyReplace,
+			updated:      `{"user":"admin","password":"new"}`,
+			wantHash:     iSha256([]byte(desired)),
+			wantUpToDate: false,
+		},
+		"Merge": {
+			strategy:     v1alpha1.CredentialMergeStrategyMerge,
+			updated:      `{"user":"admin","password":"new"}`,
+			wantHash:     iSha256([]byte(`{"password":"new","user":"admin"}`)),
+			wantUpToDate: true,
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			ups := fake.NewServiceInstance("user-provided").SetName(name).SetGUID(guid).SetLastOperation(v1alpha1.LastOperationUpdate, v1alpha1.LastOperationSucceeded).ServiceInstance
+			m := &fake.MockServiceInstance{}
+			m.On("Get", guid).Return(&ups, nil)
+			// only a merge reads the credentials of the service instance to update them
+			if tc.strategy == v1alpha1.CredentialMergeStrategyMerge {
+				m.On("GetUserProvidedCredentials", guid).Return(fake.JSONRawMessage(actual), nil).Once()
+			}
+			m.On("UpdateUserProvided", guid).Return(&ups, nil)
+			c := &external{
+				recorder:        event.NewNopRecorder(),
+				kube:            &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil), MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil)},
+				serviceinstance: &serviceinstance.Client{ServiceInstance: m},
+			}
+			cr := serviceInstance("user-provided", withExternalName(guid), withSpace(spaceGUID), withCredentials(&desired),
+				withDriftDetection(true), withParameterComparison(v1alpha1.ParameterComparisonExact))
+			cr.Spec.ForProvider.CredentialMergeStrategy = tc.strategy
+			cr.Status.AtProvider.ID = &guid
+
+			if _, err := c.Update(context.Background(), cr); err != nil {
+				t.Fatalf("Update(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantHash, cr.Status.AtProvider.Credentials); diff != "" {
+				t.Errorf("Update(...): -want credentials hash, +got:\n%s", diff)
+			}
+
+			m.On("GetUserProvidedCredentials", guid).Return(fake.JSONRawMessage(tc.updated), nil)
+			obs, err := c.Observe(context.Background(), cr)
+			if err != nil {
+				t.Fatalf("Observe(...): unexpected error: %v", err)
+			}
+			if obs.ResourceUpToDate != tc.wantUpToDate {
+				t.Errorf("Observe(...): want up to date %t, got %t", tc.wantUpToDate, obs.ResourceUpToDate)
+			}
+		})
+	}
+}
+
+type timeoutError struct {
+	timeout bool
+}
+
+func (e *timeoutError) Error() string { return "timeout error" }
+func (e *timeoutError) Timeout() bool { return e.timeout }
+
+func TestObserveDriftDetectionUnsupported(t *testing.T) {
+	type want struct {
+		obs       managed.ExternalObservation
+		err       error
+		condition xpv1.Condition
+	}
+	cases := map[string]struct {
+		mg     *v1alpha1.ServiceInstance
+		params *json.RawMessage
+		err    error
+		want   want
+	}{
+		"FallBackToHash": {
+			mg:  serviceInstance("managed", withExternalName(guid), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withCredentials(&jsonCredentials), withDriftDetection(true), withStatus(v1alpha1.ServiceInstanceObservation{Credentials: iSha256([]byte(jsonCredentials))})),
+			err: cfresource.NewServiceFetchInstanceParametersNotSupportedError(),
+			want: want{
+				obs:       managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				condition: driftDetectionUnsupported(),
+			},
+		},
+		"FallBackToHashDrift": {
+			mg:  serviceInstance("managed", withExternalName(guid), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withCredentials(&jsonCredentials), withDriftDetection(true), withStatus(v1alpha1.ServiceInstanceObservation{Credentials: iSha256([]byte(`{"json":"foo"}`))})),
+			err: cfresource.NewServiceFetchInstanceParametersNotSupportedError(),
+			want: want{
+				obs:       managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				condition: driftDetectionUnsupported(),
+			},
+		},
+		"SupportedAgain": {
+			mg:     serviceInstance("managed", withExternalName(guid), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withCredentials(&jsonCredentials), withDriftDetection(true), withConditions(driftDetectionUnsupported())),
+			params: fake.JSONRawMessage(jsonCredentials),
+			want: want{
+				obs:       managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				condition: driftDetectionSupported(),
+			},
+		},
+		"OtherErrorsPropagate": {
+			mg:  serviceInstance("managed", withExternalName(guid), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withCredentials(&jsonCredentials), withDriftDetection(true)),
+			err: errBoom,
+			want: want{
+				obs: managed.ExternalObservation{ResourceExists: true},
+				err: errors.Wrap(errBoom, errGetParameters),
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			m := &fake.MockServiceInstance{}
+			m.On("Get", guid).Return(
+				&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).SetLastOperation(v1alpha1.LastOperationCreate, v1alpha1.LastOperationSucceeded).ServiceInstance,
+				nil,
+			)
+			m.On("GetManagedParameters", guid).Return(tc.params, tc.err)
+			c := &external{
+				recorder:        event.NewNopRecorder(),
+				kube:            &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				serviceinstance: &serviceinstance.Client{ServiceInstance: m},
+			}
+
+			obs, err := c.Observe(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Observe(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.obs, obs); diff != "" {
+				t.Errorf("Observe(...): -want, +got:\n%s", diff)
+			}
+			if tc.want.err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.condition, tc.mg.GetCondition(typeDriftDetectionUnsupported), test.EquateConditions()); diff != "" {
+				t.Errorf("Observe(...): -want condition, +got condition:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
 	type service func() *fake.MockServiceInstance
 	type job func() *fake.MockJob
 	type args struct {
@@ -754,7 +1185,7 @@ func TestUpdate(t *testing.T) {
 
 	type want struct {
 		mg  resource.Managed
-		obs managed.ExternalUpdate
+		obs managed.ExternalCreation
 		err error
 	}
 
@@ -767,20 +1198,20 @@ func TestUpdate(t *testing.T) {
 	}{
 		"Successful": {
 			args: args{
-				mg: serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withExternalName(guid), withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid})),
+				mg: serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan})),
 			},
 			want: want{
-				mg:  serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withExternalName(guid), withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid})),
-				obs: managed.ExternalUpdate{},
+				mg:  serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withConditions(xpv1.Creating()), withExternalName(guid)),
+				obs: managed.ExternalCreation{},
 				err: nil,
 			},
 			service: func() *fake.MockServiceInstance {
 				m := &fake.MockServiceInstance{}
-				m.On("UpdateManaged", guid).Return(
+				m.On("CreateManaged").Return(
 					"JOB123",
 					nil,
 				)
-				m.On("Get", guid).Return(
+				m.On("Single").Return(
 					&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).ServiceInstance,
 					nil,
 				)
@@ -796,29 +1227,30 @@ func TestUpdate(t *testing.T) {
 				return m
 			},
 		},
-		"SuccessfulWithParams": {
+		"SuccessfulAfterStaleRead": {
 			args: args{
-				mg: serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withExternalName(guid), withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid}), withCredentials(&jsonCredentials)),
+				mg: serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan})),
 			},
 			want: want{
-				mg:  serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withCredentials(&jsonCredentials), withExternalName(guid), withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid, Credentials: iSha256([]byte(jsonCredentials))})),
-				obs: managed.ExternalUpdate{},
+				mg:  serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withConditions(xpv1.Creating()), withExternalName(guid)),
+				obs: managed.ExternalCreation{},
 				err: nil,
 			},
 			service: func() *fake.MockServiceInstance {
 				m := &fake.MockServiceInstance{}
-				m.On("UpdateManaged", guid).Return(
+				m.On("CreateManaged").Return(
 					"JOB123",
 					nil,
 				)
-				m.On("Get", guid).Return(
+				// the first listing after the create job does not contain the instance yet
+				m.On("Single").Return(
+					fake.ServiceInstanceNil,
+					fake.ErrNoResultReturned,
+				).Once()
+				m.On("Single").Return(
 					&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).ServiceInstance,
 					nil,
 				)
-				m.On("GetManagedParameters", guid).Return(
-					fake.JSONRawMessage(jsonCredentials),
-					nil, // no error
-				)
 				return m
 			},
 			job: func() *fake.MockJob {
@@ -827,42 +1259,521 @@ func TestUpdate(t *testing.T) {
 				return m
 			},
 		},
-		"HTTPClientTimeout": {
+		"NotListedAfterCreate": {
 			args: args{
-				mg: serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withExternalName(guid), withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid}), withCredentials(&jsonCredentials)),
+				mg: serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan})),
 			},
 			want: want{
-				mg:  serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withCredentials(&jsonCredentials), withExternalName(guid), withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid, Credentials: iSha256([]byte(jsonCredentials))})),
-				obs: managed.ExternalUpdate{},
-				err: nil,
+				mg:  serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withConditions(xpv1.Creating())),
+				obs: managed.ExternalCreation{},
+				err: errors.Wrap(errors.New("service instance not listed after creation"), errCreate),
 			},
 			service: func() *fake.MockServiceInstance {
 				m := &fake.MockServiceInstance{}
-				m.On("UpdateManaged", guid).Return(
+				m.On("CreateManaged").Return(
 					"JOB123",
 					nil,
 				)
-				m.On("Get", guid).Return(
-					&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).ServiceInstance,
-					nil,
-				)
-				m.On("GetManagedParameters", guid).Return(
-					fake.JSONRawMessage(jsonCredentials),
-					nil, // no error
+				m.On("Single").Return(
+					fake.ServiceInstanceNil,
+					fake.ErrNoResultReturned,
 				)
 				return m
 			},
 			job: func() *fake.MockJob {
 				m := &fake.MockJob{}
-				m.On("PollComplete").Return(&url.Error{
-					Err: &timeoutError{true},
-				})
+				m.On("PollComplete").Return(nil)
 				return m
 			},
 		},
-		"CannotPollCreationJob": {
+		"SuccessfulWithParams": {
 			args: args{
-				mg: serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withExternalName(guid), withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid})),
+				mg: serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withCredentials(&jsonCredentials)),
+			},
+			want: want{
+				mg:  serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withCredentials(&jsonCredentials), withConditions(xpv1.Creating()), withExternalName(guid)),
+				obs: managed.ExternalCreation{},
+				err: nil,
+			},
+			service: func() *fake.MockServiceInstance {
+				m := &fake.MockServiceInstance{}
+				m.On("CreateManaged").Return(
+					"JOB123",
+					nil,
+				)
+				m.On("Single").Return(
+					&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).ServiceInstance,
+					nil,
+				)
+				m.On("GetManagedParameters", guid).Return(
+					fake.JSONRawMessage(jsonCredentials),
+					nil, // no error
+				)
+				return m
+			},
+			job: func() *fake.MockJob {
+				m := &fake.MockJob{}
+				m.On("PollComplete").Return(nil)
+				return m
+			},
+		},
+		"SuccessfulWithYAMLParams": {
+			args: args{
+				mg: serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withYAMLParams(`"json: bar\n"`)),
+			},
+			want: want{
+				mg:  serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withYAMLParams(`"json: bar\n"`), withConditions(xpv1.Creating()), withExternalName(guid)),
+				obs: managed.ExternalCreation{},
+				err: nil,
+			},
+			service: func() *fake.MockServiceInstance {
+				m := &fake.MockServiceInstance{}
+				m.On("CreateManaged").Return(
+					"JOB123",
+					nil,
+				)
+				m.On("Single").Return(
+					&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).ServiceInstance,
+					nil,
+				)
+				return m
+			},
+			job: func() *fake.MockJob {
+				m := &fake.MockJob{}
+				m.On("PollComplete").Return(nil)
+				return m
+			},
+		},
+		"HTTPClientTimeout": {
+			args: args{
+				mg: serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withCredentials(&jsonCredentials)),
+			},
+			want: want{
+				mg:  serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withCredentials(&jsonCredentials), withConditions(xpv1.Creating()), withExternalName(guid)),
+				obs: managed.ExternalCreation{},
+				err: nil,
+			},
+			service: func() *fake.MockServiceInstance {
+				m := &fake.MockServiceInstance{}
+				m.On("CreateManaged").Return(
+					"JOB123",
+					nil,
+				)
+				m.On("Single").Return(
+					&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).ServiceInstance,
+					nil,
+				)
+				m.On("GetManagedParameters", guid).Return(
+					fake.JSONRawMessage(jsonCredentials),
+					nil, // no error
+				)
+				return m
+			},
+			job: func() *fake.MockJob {
+				m := &fake.MockJob{}
+				m.On("PollComplete").Return(&url.Error{
+					Err: &timeoutError{true},
+				})
+				return m
+			},
+		},
+		"CannotPollCreationJob": {
+			args: args{
+				mg: serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan})),
+			},
+			want: want{
+				mg:  serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withConditions(xpv1.Creating())),
+				obs: managed.ExternalCreation{},
+				err: errors.Wrap(errBoom, errCreate),
+			},
+			service: func() *fake.MockServiceInstance {
+				m := &fake.MockServiceInstance{}
+				m.On("CreateManaged").Return(
+					"JOB123",
+					nil,
+				)
+				m.On("Single").Return(
+					&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).ServiceInstance,
+					nil,
+				)
+				m.On("GetManagedParameters", guid).Return(
+					nil,
+					nil, // no error
+				)
+				return m
+			},
+			job: func() *fake.MockJob {
+				m := &fake.MockJob{}
+				m.On("PollComplete").Return(errBoom)
+				return m
+			},
+		},
+		"AlreadyExist": {
+			args: args{
+				mg: serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan})),
+			},
+			want: want{
+				mg:  serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withConditions(xpv1.Creating())),
+				obs: managed.ExternalCreation{},
+				err: errors.Wrap(errBoom, errCreate),
+			},
+			service: func() *fake.MockServiceInstance {
+				m := &fake.MockServiceInstance{}
+				m.On("CreateManaged").Return(
+					"JOB123",
+					errBoom,
+				)
+				m.On("Single").Return(
+					&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).ServiceInstance,
+					nil,
+				)
+				m.On("GetManagedParameters", guid).Return(
+					nil,
+					nil, // no error
+				)
+				return m
+			},
+			job: func() *fake.MockJob {
+				m := &fake.MockJob{}
+				m.On("PollComplete").Return(nil)
+				return m
+			},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			t.Logf("Testing: %s", t.Name())
+			c := &external{
+				recorder: event.NewNopRecorder(),
+				// the reconciler persists the external name and the status
+				kube: &test.MockClient{},
+				serviceinstance: &serviceinstance.Client{
+					ServiceInstance: tc.service(),
+					Job:             tc.job(),
+				},
+			}
+			// bounds the read-after-write retry of the instances that are not listed after creation
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			obs, err := c.Create(ctx, tc.args.mg)
+
+			if tc.want.err != nil && err != nil {
+				// the case where our mock server returns error.
+				if diff := cmp.Diff(tc.want.err.Error(), err.Error()); diff != "" {
+					t.Errorf("Create(...): want error string != got error string:\n%s", diff)
+				}
+			} else {
+				if diff := cmp.Diff(tc.want.err, err); diff != "" {
+					t.Errorf("Create(...): want error != got error:\n%s", diff)
+				}
+			}
+			if diff := cmp.Diff(tc.want.obs, obs); diff != "" {
+				t.Errorf("Create(...): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg); diff != "" {
+				t.Errorf("Create(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReconcileCreateFailurePersisted(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	stored := serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}))
+	stored.SetNamespace("default")
+
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, _ k8s.ObjectKey, obj k8s.Object) error {
+			stored.DeepCopyInto(obj.(*v1alpha1.ServiceInstance))
+			return nil
+		},
+		// like the API server, an update ignores the status and returns the stored one
+		MockUpdate: func(_ context.Context, obj k8s.Object, _ ...k8s.UpdateOption) error {
+			cr := obj.(*v1alpha1.ServiceInstance)
+			status := stored.Status
+			cr.DeepCopyInto(stored)
+			stored.Status = status
+			stored.DeepCopyInto(cr)
+			return nil
+		},
+		MockStatusUpdate: func(_ context.Context, obj k8s.Object, _ ...k8s.SubResourceUpdateOption) error {
+			obj.(*v1alpha1.ServiceInstance).Status.DeepCopyInto(&stored.Status)
+			return nil
+		},
+	}
+
+	service := &fake.MockServiceInstance{}
+	service.On("Single").Return(fake.ServiceInstanceNil, fake.ErrNoResultReturned)
+	service.On("CreateManaged").Return("", errBoom)
+
+	r := managed.NewReconciler(&xpfake.Manager{Client: kube, Scheme: scheme}, resource.ManagedKind(v1alpha1.ServiceInstance_GroupVersionKind),
+		managed.WithInitializers(),
+		managed.WithReferenceResolver(managed.ReferenceResolverFn(func(context.Context, resource.Managed) error { return nil })),
+		managed.WithExternalConnector(managed.ExternalConnectorFn(func(context.Context, resource.Managed) (managed.ExternalClient, error) {
+			return &external{kube: kube, serviceinstance: &serviceinstance.Client{ServiceInstance: service}, recorder: event.NewNopRecorder()}, nil
+		})),
+	)
+
+	// the first reconcile fails to create the service instance, the second one
+	// counts the failed create and waits for its backoff instead of creating again
+	for range 2 {
+		if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: name}}); err != nil {
+			t.Fatalf("Reconcile(...): %v", err)
+		}
+	}
+	if got := stored.Status.AtProvider.CreateFailures; got != 1 {
+		t.Errorf("Reconcile(...): want 1 persisted create failure, got %d", got)
+	}
+	if stored.Status.AtProvider.LastCreateFailure == nil {
+		t.Error("Reconcile(...): want the time of the failed create persisted")
+	}
+	service.AssertExpectations(t)
+}
+
+func TestObserveDryRun(t *testing.T) {
+	dryRunCondition := clients.DryRun("create payload recorded in status.atProvider.dryRunPayload, remove the " + clients.AnnotationKeyDryRun + " annotation to create the service instance")
+
+	cases := map[string]struct {
+		mg   *v1alpha1.ServiceInstance
+		want *v1alpha1.ServiceInstance
+	}{
+		"RecordsPayload": {
+			mg: serviceInstance("managed", withDryRun(), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withCredentials(&jsonCredentials)),
+			want: serviceInstance("managed", withDryRun(), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withCredentials(&jsonCredentials),
+				withConditions(dryRunCondition),
+				withStatus(v1alpha1.ServiceInstanceObservation{DryRunPayload: &dryRunPayload})),
+		},
+		"RedactsSecretParams": {
+			mg: serviceInstance("managed", withDryRun(), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withParametersSecretRef()),
+			want: serviceInstance("managed", withDryRun(), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withParametersSecretRef(),
+				withConditions(dryRunCondition),
+				withStatus(v1alpha1.ServiceInstanceObservation{DryRunPayload: &dryRunPayloadRedacted})),
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			m := &fake.MockServiceInstance{}
+			m.On("Single").Return(fake.ServiceInstanceNil, fake.ErrNoResultReturned)
+
+			// the reconciler persists the recorded payload
+			c := &external{
+				recorder:        event.NewNopRecorder(),
+				kube:            &test.MockClient{},
+				serviceinstance: &serviceinstance.Client{ServiceInstance: m, Job: &fake.MockJob{}},
+			}
+
+			obs, err := c.Observe(context.Background(), tc.mg)
+			if err != nil {
+				t.Fatalf("Observe(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, obs); diff != "" {
+				t.Errorf("Observe(...): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want, tc.mg, test.EquateConditions(), cmpopts.IgnoreFields(v1alpha1.ServiceInstanceObservation{}, "ObservedSpec")); diff != "" {
+				t.Errorf("Observe(...): -want, +got:\n%s", diff)
+			}
+			m.AssertNotCalled(t, "CreateManaged")
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	type service func() *fake.MockServiceInstance
+	type job func() *fake.MockJob
+	type args struct {
+		mg resource.Managed
+	}
+
+	type want struct {
+		mg  resource.Managed
+		obs managed.ExternalUpdate
+		err error
+	}
+
+	cases := map[string]struct {
+		args    args
+		want    want
+		service service
+		job
+		kube k8s.Client
+	}{
+		"DryRunUpdate": {
+			args: args{
+				mg: serviceInstance("managed", withDryRunUpdate(), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &newServicePlan}), withExternalName(guid), withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid}), withCredentials(&jsonCredentials)),
+			},
+			want: want{
+				mg:  serviceInstance("managed", withDryRunUpdate(), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &newServicePlan}), withExternalName(guid), withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid}), withCredentials(&jsonCredentials), withConditions(clients.PendingUpdate("update suppressed, remove the "+clients.AnnotationKeyDryRunUpdate+" annotation to apply: servicePlan: "+servicePlan+" -> "+newServicePlan+"; parameters: changed"))),
+				obs: managed.ExternalUpdate{},
+				err: nil,
+			},
+			service: func() *fake.MockServiceInstance {
+				m := &fake.MockServiceInstance{}
+				m.On("Get", guid).Return(
+					&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).ServiceInstance,
+					nil,
+				)
+				return m
+			},
+			job: func() *fake.MockJob {
+				return &fake.MockJob{}
+			},
+		},
+		"DryRunUpdateParameterDiff": {
+			args: args{
+				mg: serviceInstance("managed", withDryRunUpdate(), withDriftDetection(true), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withExternalName(guid), withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid}), withCredentials(&jsonCredentials)),
+			},
+			want: want{
+				mg:  serviceInstance("managed", withDryRunUpdate(), withDriftDetection(true), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withExternalName(guid), withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid}), withCredentials(&jsonCredentials), withConditions(clients.PendingUpdate("update suppressed, remove the "+clients.AnnotationKeyDryRunUpdate+" annotation to apply: parameters: { \"json\": {\"changed\":[\"foo\", \"bar\"]} }"))),
+				obs: managed.ExternalUpdate{},
+				err: nil,
+			},
+			service: func() *fake.MockServiceInstance {
+				m := &fake.MockServiceInstance{}
+				m.On("Get", guid).Return(
+					&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).ServiceInstance,
+					nil,
+				)
+				m.On("GetManagedParameters", guid).Return(
+					fake.JSONRawMessage(`{"json":"foo"}`),
+					nil,
+				)
+				return m
+			},
+			job: func() *fake.MockJob {
+				return &fake.MockJob{}
+			},
+		},
+		"Successful": {
+			args: args{
+				mg: serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withExternalName(guid), withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid})),
+			},
+			want: want{
+				mg:  serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withExternalName(guid), withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid})),
+				obs: managed.ExternalUpdate{},
+				err: nil,
+			},
+			service: func() *fake.MockServiceInstance {
+				m := &fake.MockServiceInstance{}
+				m.On("UpdateManaged", guid).Return(
+					"JOB123",
+					nil,
+				)
+				m.On("Get", guid).Return(
+					&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).ServiceInstance,
+					nil,
+				)
+				m.On("GetManagedParameters", guid).Return(
+					nil,
+					nil, // no error
+				)
+				return m
+			},
+			job: func() *fake.MockJob {
+				m := &fake.MockJob{}
+				m.On("PollComplete").Return(nil)
+				return m
+			},
+		},
+		"SuccessfulWithParams": {
+			args: args{
+				mg: serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withExternalName(guid), withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid}), withCredentials(&jsonCredentials)),
+			},
+			want: want{
+				mg:  serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withCredentials(&jsonCredentials), withExternalName(guid), withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid, Credentials: iSha256([]byte(jsonCredentials))})),
+				obs: managed.ExternalUpdate{},
+				err: nil,
+			},
+			service: func() *fake.MockServiceInstance {
+				m := &fake.MockServiceInstance{}
+				m.On("UpdateManaged", guid).Return(
+					"JOB123",
+					nil,
+				)
+				m.On("Get", guid).Return(
+					&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).ServiceInstance,
+					nil,
+				)
+				m.On("GetManagedParameters", guid).Return(
+					fake.JSONRawMessage(jsonCredentials),
+					nil, // no error
+				)
+				return m
+			},
+			job: func() *fake.MockJob {
+				m := &fake.MockJob{}
+				m.On("PollComplete").Return(nil)
+				return m
+			},
+		},
+		"ParametersSecretRefRemoved": {
+			args: args{
+				mg: serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withExternalName(guid), withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid, Credentials: iSha256([]byte("{\"foo\":\"bar\"}"))})),
+			},
+			want: want{
+				mg:  serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withExternalName(guid), withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid})),
+				obs: managed.ExternalUpdate{},
+				err: nil,
+			},
+			service: func() *fake.MockServiceInstance {
+				m := &fake.MockServiceInstance{}
+				m.On("UpdateManaged", guid).Return(
+					"JOB123",
+					nil,
+				)
+				m.On("Get", guid).Return(
+					&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).ServiceInstance,
+					nil,
+				)
+				return m
+			},
+			job: func() *fake.MockJob {
+				m := &fake.MockJob{}
+				m.On("PollComplete").Return(nil)
+				return m
+			},
+		},
+		"HTTPClientTimeout": {
+			args: args{
+				mg: serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withExternalName(guid), withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid}), withCredentials(&jsonCredentials)),
+			},
+			want: want{
+				mg:  serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withCredentials(&jsonCredentials), withExternalName(guid), withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid, Credentials: iSha256([]byte(jsonCredentials))})),
+				obs: managed.ExternalUpdate{},
+				err: nil,
+			},
+			service: func() *fake.MockServiceInstance {
+				m := &fake.MockServiceInstance{}
+				m.On("UpdateManaged", guid).Return(
+					"JOB123",
+					nil,
+				)
+				m.On("Get", guid).Return(
+					&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).ServiceInstance,
+					nil,
+				)
+				m.On("GetManagedParameters", guid).Return(
+					fake.JSONRawMessage(jsonCredentials),
+					nil, // no error
+				)
+				return m
+			},
+			job: func() *fake.MockJob {
+				m := &fake.MockJob{}
+				m.On("PollComplete").Return(&url.Error{
+					Err: &timeoutError{true},
+				})
+				return m
+			},
+		},
+		"CannotPollCreationJob": {
+			args: args{
+				mg: serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withExternalName(guid), withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid})),
 			},
 			want: want{
 				mg:  serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withExternalName(guid), withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid})),
@@ -891,6 +1802,23 @@ func TestUpdate(t *testing.T) {
 				return m
 			},
 		},
+		"OperationInProgress": {
+			args: args{
+				mg: serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &newServicePlan}), withExternalName(guid), withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid, LastOperation: v1alpha1.LastOperation{Type: v1alpha1.LastOperationUpdate, State: v1alpha1.LastOperationInProgress}})),
+			},
+			want: want{
+				mg:  serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &newServicePlan}), withExternalName(guid), withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid, LastOperation: v1alpha1.LastOperation{Type: v1alpha1.LastOperationUpdate, State: v1alpha1.LastOperationInProgress}})),
+				obs: managed.ExternalUpdate{},
+				err: nil,
+			},
+			service: func() *fake.MockServiceInstance {
+				// no CF call is issued while the last operation is in progress
+				return &fake.MockServiceInstance{}
+			},
+			job: func() *fake.MockJob {
+				return &fake.MockJob{}
+			},
+		},
 		"DoesNotExist": {
 			args: args{
 				mg: serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withExternalName(guid), withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid})),
@@ -922,34 +1850,402 @@ func TestUpdate(t *testing.T) {
 
 	for n, tc := range cases {
 		t.Run(n, func(t *testing.T) {
-			t.Logf("Testing: %s", t.Name())
+			t.Logf("Testing: %s", t.Name())
+			c := &external{
+				recorder: event.NewNopRecorder(),
+				kube: &test.MockClient{
+					MockUpdate:       test.NewMockUpdateFn(nil),
+					MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+				},
+				serviceinstance: &serviceinstance.Client{
+					ServiceInstance: tc.service(),
+					Job:             tc.job(),
+				},
+			}
+			obs, err := c.Update(context.Background(), tc.args.mg)
+
+			if tc.want.err != nil && err != nil {
+				// the case where our mock server returns error.
+				if diff := cmp.Diff(tc.want.err.Error(), err.Error()); diff != "" {
+					t.Errorf("Update(...): want error string != got error string:\n%s", diff)
+				}
+			} else {
+				if diff := cmp.Diff(tc.want.err, err); diff != "" {
+					t.Errorf("Update(...): want error != got error:\n%s", diff)
+				}
+			}
+			if diff := cmp.Diff(tc.want.obs, obs); diff != "" {
+				t.Errorf("Update(...): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg); diff != "" {
+				t.Errorf("Update(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+// mockBindings mocks serviceinstance.Bindings
+type mockBindings struct {
+	mock.Mock
+}
+
+func (m *mockBindings) ListBindings(ctx context.Context, guid string) ([]serviceinstance.Binding, error) {
+	args := m.Called(guid)
+	return args.Get(0).([]serviceinstance.Binding), args.Error(1)
+}
+
+func (m *mockBindings) DeleteBinding(ctx context.Context, binding serviceinstance.Binding) (string, error) {
+	args := m.Called(binding)
+	return args.String(0), args.Error(1)
+}
+
+func TestDelete(t *testing.T) {
+	const jobGUID = "3d8b0d04-d537-4e4e-8c6f-f09ca0e7f56f"
+
+	withDeleteFailures := func(n int32) modifier {
+		return func(r *v1alpha1.ServiceInstance) {
+			r.Status.AtProvider.ID = &guid
+			r.Status.AtProvider.DeleteFailures = n
+		}
+	}
+	withPurge := func(after int32) modifier {
+		return func(r *v1alpha1.ServiceInstance) {
+			r.Spec.PurgeOnDeleteFailure = true
+			r.Spec.PurgeAfterDeleteFailures = after
+		}
+	}
+	deleteFails := func() *fake.MockServiceInstance {
+		m := &fake.MockServiceInstance{}
+		m.On("Delete", guid).Return("", errBoom)
+		return m
+	}
+	noJob := func() *fake.MockJob { return &fake.MockJob{} }
+	unbound := func() *mockBindings {
+		m := &mockBindings{}
+		m.On("ListBindings", guid).Return([]serviceinstance.Binding(nil), nil)
+		return m
+	}
+	// CF deletes the bindings with the service instance, so they are only
+	// listed to delete them first or to report that they block the deletion
+	notListed := func() *mockBindings { return &mockBindings{} }
+
+	credential := serviceinstance.Binding{Kind: serviceinstance.BindingCredential, GUID: "binding-guid", Name: "my-binding"}
+	route := serviceinstance.Binding{Kind: serviceinstance.BindingRoute, GUID: "route-binding-guid", Name: "route-guid"}
+	bound := func(deleted ...error) func() *mockBindings {
+		return func() *mockBindings {
+			m := &mockBindings{}
+			m.On("ListBindings", guid).Return([]serviceinstance.Binding{credential, route}, nil)
+			if len(deleted) > 0 {
+				m.On("DeleteBinding", credential).Return(jobGUID, deleted[0])
+				m.On("DeleteBinding", route).Return("", deleted[1])
+			}
+			return m
+		}
+	}
+	withCascade := func() modifier {
+		return func(r *v1alpha1.ServiceInstance) {
+			r.Spec.CascadeDeleteBindings = true
+		}
+	}
+
+	cases := map[string]struct {
+		mg       *v1alpha1.ServiceInstance
+		want     error
+		failures int32
+		blocked  bool
+		service  func() *fake.MockServiceInstance
+		job      func() *fake.MockJob
+		bindings func() *mockBindings
+	}{
+		"Successful": {
+			mg: serviceInstance("managed", withExternalName(guid), withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid})),
+			service: func() *fake.MockServiceInstance {
+				m := &fake.MockServiceInstance{}
+				m.On("Delete", guid).Return(jobGUID, nil)
+				return m
+			},
+			job: func() *fake.MockJob {
+				m := &fake.MockJob{}
+				m.On("Get", jobGUID).Return(&cfresource.Job{State: cfresource.JobStateComplete}, nil)
+				return m
+			},
+		},
+		"SuccessfulAfterFailures": {
+			mg: serviceInstance("managed", withExternalName(guid), withDeleteFailures(2)),
+			service: func() *fake.MockServiceInstance {
+				m := &fake.MockServiceInstance{}
+				m.On("Delete", guid).Return(jobGUID, nil)
+				return m
+			},
+			job: func() *fake.MockJob {
+				m := &fake.MockJob{}
+				m.On("Get", jobGUID).Return(&cfresource.Job{State: cfresource.JobStateComplete}, nil)
+				return m
+			},
+		},
+		"AlreadyDeleted": {
+			mg: serviceInstance("managed", withExternalName(guid), withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid})),
+			service: func() *fake.MockServiceInstance {
+				m := &fake.MockServiceInstance{}
+				m.On("Delete", guid).Return("", fake.ErrResourceNotFound)
+				return m
+			},
+			// no expectations, there is no job to poll
+			job: noJob,
+		},
+		"JobFailed": {
+			mg:       serviceInstance("managed", withExternalName(guid), withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid})),
+			want:     errors.New(errDelete),
+			failures: 1,
+			service: func() *fake.MockServiceInstance {
+				m := &fake.MockServiceInstance{}
+				m.On("Delete", guid).Return(jobGUID, nil)
+				return m
+			},
+			job: func() *fake.MockJob {
+				m := &fake.MockJob{}
+				m.On("Get", jobGUID).Return(&cfresource.Job{State: cfresource.JobStateFailed}, nil)
+				return m
+			},
+		},
+		"RetriedWithoutPurge": {
+			mg:       serviceInstance("managed", withExternalName(guid), withDeleteFailures(5)),
+			want:     errors.New(errDelete),
+			failures: 6,
+			service:  deleteFails,
+			job:      noJob,
+		},
+		"RetriedBeforePurge": {
+			mg:       serviceInstance("managed", withExternalName(guid), withDeleteFailures(2), withPurge(3)),
+			want:     errors.New(errDelete),
+			failures: 3,
+			service:  deleteFails,
+			job:      noJob,
+		},
+		"Purged": {
+			mg:       serviceInstance("managed", withExternalName(guid), withDeleteFailures(3), withPurge(3)),
+			failures: 3,
+			service: func() *fake.MockServiceInstance {
+				m := &fake.MockServiceInstance{}
+				m.On("Purge", guid).Return(nil)
+				return m
+			},
+			job: noJob,
+		},
+		"PurgedAfterDefaultFailures": {
+			mg:       serviceInstance("managed", withExternalName(guid), withDeleteFailures(defaultPurgeAfterDeleteFailures), withPurge(0)),
+			failures: defaultPurgeAfterDeleteFailures,
+			service: func() *fake.MockServiceInstance {
+				m := &fake.MockServiceInstance{}
+				m.On("Purge", guid).Return(nil)
+				return m
+			},
+			job: noJob,
+		},
+		"AlreadyPurged": {
+			mg:       serviceInstance("managed", withExternalName(guid), withDeleteFailures(1), withPurge(1)),
+			failures: 1,
+			service: func() *fake.MockServiceInstance {
+				m := &fake.MockServiceInstance{}
+				m.On("Purge", guid).Return(fake.ErrResourceNotFound)
+				return m
+			},
+			job: noJob,
+		},
+		"PurgeFailed": {
+			mg:       serviceInstance("managed", withExternalName(guid), withDeleteFailures(1), withPurge(1)),
+			want:     errors.Wrap(errBoom, errPurge),
+			failures: 1,
+			service: func() *fake.MockServiceInstance {
+				m := &fake.MockServiceInstance{}
+				m.On("Purge", guid).Return(errBoom)
+				return m
+			},
+			job: noJob,
+		},
+		"ListBindingsFailed": {
+			mg:   serviceInstance("managed", withExternalName(guid), withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid}), withCascade()),
+			want: errors.Wrap(errBoom, errListBindings),
+			service: func() *fake.MockServiceInstance {
+				return &fake.MockServiceInstance{}
+			},
+			job: noJob,
+			bindings: func() *mockBindings {
+				m := &mockBindings{}
+				m.On("ListBindings", guid).Return([]serviceinstance.Binding(nil), errBoom)
+				return m
+			},
+		},
+		"BlockedByBindings": {
+			mg:       serviceInstance("managed", withExternalName(guid), withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid})),
+			want:     errors.New(errHasBindings),
+			failures: 1,
+			blocked:  true,
+			service: func() *fake.MockServiceInstance {
+				m := &fake.MockServiceInstance{}
+				m.On("Delete", guid).Return("", cfresource.NewAssociationNotEmptyError())
+				return m
+			},
+			job:      noJob,
+			bindings: bound(),
+		},
+		"NotBlockedWithoutBindings": {
+			mg:       serviceInstance("managed", withExternalName(guid), withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid})),
+			want:     errors.New(errDelete),
+			failures: 1,
+			service: func() *fake.MockServiceInstance {
+				m := &fake.MockServiceInstance{}
+				m.On("Delete", guid).Return("", cfresource.NewAssociationNotEmptyError())
+				return m
+			},
+			job:      noJob,
+			bindings: unbound,
+		},
+		"CascadeDeleted": {
+			mg: serviceInstance("managed", withExternalName(guid), withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid}), withCascade()),
+			service: func() *fake.MockServiceInstance {
+				m := &fake.MockServiceInstance{}
+				m.On("Delete", guid).Return(jobGUID, nil)
+				return m
+			},
+			job: func() *fake.MockJob {
+				m := &fake.MockJob{}
+				m.On("Get", jobGUID).Return(&cfresource.Job{State: cfresource.JobStateComplete}, nil)
+				return m
+			},
+			bindings: bound(nil, nil),
+		},
+		"CascadeAlreadyDeleted": {
+			mg: serviceInstance("managed", withExternalName(guid), withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid}), withCascade()),
+			service: func() *fake.MockServiceInstance {
+				m := &fake.MockServiceInstance{}
+				m.On("Delete", guid).Return(jobGUID, nil)
+				return m
+			},
+			job: func() *fake.MockJob {
+				m := &fake.MockJob{}
+				m.On("Get", jobGUID).Return(&cfresource.Job{State: cfresource.JobStateComplete}, nil)
+				return m
+			},
+			bindings: bound(fake.ErrResourceNotFound, fake.ErrResourceNotFound),
+		},
+		"CascadeFailed": {
+			mg:   serviceInstance("managed", withExternalName(guid), withStatus(v1alpha1.ServiceInstanceObservation{ID: &guid}), withCascade()),
+			want: errors.Wrap(errors.New("cannot delete credential binding my-binding: boom\ncannot delete route binding route-guid: boom"), errDeleteBindings),
+			// the service instance is not deleted while it has bindings
+			service: func() *fake.MockServiceInstance {
+				return &fake.MockServiceInstance{}
+			},
+			job:      noJob,
+			bindings: bound(errBoom, errBoom),
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			service, j := tc.service(), tc.job()
+			if tc.bindings == nil {
+				tc.bindings = notListed
+			}
+			b := tc.bindings()
 			c := &external{
-				kube: &test.MockClient{
-					MockUpdate:       test.NewMockUpdateFn(nil),
-					MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
-				},
-				serviceinstance: &serviceinstance.Client{
-					ServiceInstance: tc.service(),
-					Job:             tc.job(),
-				},
+				recorder:        event.NewNopRecorder(),
+				serviceinstance: &serviceinstance.Client{ServiceInstance: service, Job: j, Purger: service, Bindings: b},
 			}
-			obs, err := c.Update(context.Background(), tc.args.mg)
+			_, err := c.Delete(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Delete(...): -want error, +got error:\n%s", diff)
+			}
+			if got := tc.mg.Status.AtProvider.DeleteFailures; got != tc.failures {
+				t.Errorf("Delete(...): want %d failed deletes, got %d", tc.failures, got)
+			}
+			if got := tc.mg.GetCondition(serviceinstance.TypeDeletionBlocked).Status == corev1.ConditionTrue; got != tc.blocked {
+				t.Errorf("Delete(...): want blocked %t, got %t", tc.blocked, got)
+			}
+			service.AssertExpectations(t)
+			j.AssertExpectations(t)
+			b.AssertExpectations(t)
+		})
+	}
+}
 
-			if tc.want.err != nil && err != nil {
-				// the case where our mock server returns error.
-				if diff := cmp.Diff(tc.want.err.Error(), err.Error()); diff != "" {
-					t.Errorf("Update(...): want error string != got error string:\n%s", diff)
-				}
-			} else {
-				if diff := cmp.Diff(tc.want.err, err); diff != "" {
-					t.Errorf("Update(...): want error != got error:\n%s", diff)
-				}
+func TestExtractCredentialSpec(t *testing.T) {
+	params := func(m ...modifier) v1alpha1.ServiceInstanceParameters {
+		return serviceInstance("managed", m...).Spec.ForProvider
+	}
+	_, errYAML := yamlToJSON([]byte(`"foo: [bar"`))
+
+	cases := map[string]struct {
+		spec v1alpha1.ServiceInstanceParameters
+		want string
+		err  error
+	}{
+		"StructuredYAML": {
+			spec: params(withYAMLParams(`{"foo":{"bar":[1,2]},"baz":"qux"}`)),
+			want: `{"baz":"qux","foo":{"bar":[1,2]}}`,
+		},
+		"YAMLDocument": {
+			spec: params(withYAMLParams(`"foo:\n  bar: [1, 2]\nbaz: qux\n"`)),
+			want: `{"baz":"qux","foo":{"bar":[1,2]}}`,
+		},
+		"YAMLDocumentKeepsLargeNumbers": {
+			spec: params(withYAMLParams(`"size: 12345678901234567890"`)),
+			want: `{"size":12345678901234567890}`,
+		},
+		"EmptyYAMLDocument": {
+			spec: params(withYAMLParams(`""`)),
+		},
+		"InvalidYAML": {
+			spec: params(withYAMLParams(`"foo: [bar"`)),
+			err:  errors.Wrap(errYAML, errYAMLParams),
+		},
+		"JSONParamsUnchanged": {
+			spec: params(withParameters(`{"foo": "bar"}`)),
+			want: `{"foo": "bar"}`,
+		},
+		"ParametersTakePrecedence": {
+			spec: params(withParameters(`{"foo":"bar"}`), withYAMLParams(`{"foo":"baz"}`)),
+			want: `{"foo":"bar"}`,
+		},
+		"YAMLParamsTakePrecedenceOverSecretRef": {
+			spec: params(withYAMLParams(`{"foo":"bar"}`), withParametersSecretRef()),
+			want: `{"foo":"bar"}`,
+		},
+		"ConfigMapOnly": {
+			spec: params(withParametersConfigMapRef()),
+			want: `{"foo":{"bar":1,"baz":2},"qux":"quux"}`,
+		},
+		"ConfigMapMergedWithInline": {
+			spec: params(withParametersConfigMapRef(), withYAMLParams(`{"foo":{"baz":3},"qux":null,"corge":true}`)),
+			want: `{"corge":true,"foo":{"bar":1,"baz":3}}`,
+		},
+		"ConfigMapKeyNotFound": {
+			spec: params(withParametersConfigMapRef(), func(r *v1alpha1.ServiceInstance) { r.Spec.ForProvider.ParametersConfigMapRef.Key = "missing" }),
+			err:  errors.Wrap(errors.New("key missing not found in ConfigMap default/params"), errParamsConfigMap),
+		},
+		"ConfigMapAndSecretRef": {
+			spec: params(withParametersConfigMapRef(), withParametersSecretRef()),
+			err:  errors.New(errConfigMapAndSecret),
+		},
+	}
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, key k8s.ObjectKey, obj k8s.Object) error {
+			cm, ok := obj.(*corev1.ConfigMap)
+			if !ok || key.Name != "params" {
+				return errBoom
 			}
-			if diff := cmp.Diff(tc.want.obs, obs); diff != "" {
-				t.Errorf("Update(...): -want, +got:\n%s", diff)
+			cm.Data = map[string]string{"params": `{"foo":{"bar":1,"baz":2},"qux":"quux"}`}
+			return nil
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			got, err := extractCredentialSpec(context.Background(), kube, tc.spec)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("extractCredentialSpec(...): -want error, +got error:\n%s", diff)
 			}
-			if diff := cmp.Diff(tc.want.mg, tc.args.mg); diff != "" {
-				t.Errorf("Update(...): -want, +got:\n%s", diff)
+			if diff := cmp.Diff(tc.want, string(got)); diff != "" {
+				t.Errorf("extractCredentialSpec(...): -want, +got:\n%s", diff)
 			}
 		})
 	}
@@ -1052,3 +2348,336 @@ func TestJSONContain(t *testing.T) {
 		})
 	}
 }
+
+func TestJSONMatch(t *testing.T) {
+	type args struct {
+		a string
+		b string
+		c v1alpha1.ParameterComparison
+	}
+	type want struct {
+		obs bool
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"DefaultSuperset": {
+			args: args{
+				a: `{"foo":"foo", "bar": 1}`,
+				b: `{"foo":"foo"}`,
+			},
+			want: want{
+				obs: true,
+			},
+		},
+		"SubsetSuperset": {
+			args: args{
+				a: `{"foo":"foo", "bar": 1}`,
+				b: `{"foo":"foo"}`,
+				c: v1alpha1.ParameterComparisonSubset,
+			},
+			want: want{
+				obs: true,
+			},
+		},
+		"ExactEqual": {
+			args: args{
+				a: `{"foo":"foo", "bar": 1}`,
+				b: `{"bar": 1, "foo":"foo"}`,
+				c: v1alpha1.ParameterComparisonExact,
+			},
+			want: want{
+				obs: true,
+			},
+		},
+		"ExactSuperset": {
+			args: args{
+				a: `{"foo":"foo", "bar": 1}`,
+				b: `{"foo":"foo"}`,
+				c: v1alpha1.ParameterComparisonExact,
+			},
+			want: want{
+				obs: false,
+			},
+		},
+		"ExactDiffValue": {
+			args: args{
+				a: `{"foo":"foo"}`,
+				b: `{"foo":"bar"}`,
+				c: v1alpha1.ParameterComparisonExact,
+			},
+			want: want{
+				obs: false,
+			},
+		},
+		"ExactEmpties": {
+			args: args{
+				a: "{}",
+				b: "",
+				c: v1alpha1.ParameterComparisonExact,
+			},
+			want: want{
+				obs: true,
+			},
+		},
+		"ExactEmptyDesired": {
+			args: args{
+				a: `{"foo":"foo"}`,
+				b: "",
+				c: v1alpha1.ParameterComparisonExact,
+			},
+			want: want{
+				obs: false,
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			obs := jsonMatch([]byte(tc.args.a), []byte(tc.args.b), tc.args.c)
+			if diff := cmp.Diff(tc.want.obs, obs); diff != "" {
+				t.Errorf("jsonMatch(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestObserveOnlyInitializers(t *testing.T) {
+	observeOnly := func(r *v1alpha1.ServiceInstance) {
+		r.Spec.ManagementPolicies = xpv1.ManagementPolicies{xpv1.ManagementActionObserve}
+	}
+	offering, plan := "offering", "plan"
+
+	cases := map[string]struct {
+		init managed.Initializer
+		mg   *v1alpha1.ServiceInstance
+	}{
+		"SpaceNotRequired": {
+			init: spaceInitializer{},
+			mg:   serviceInstance("managed", withExternalName(guid), observeOnly),
+		},
+		"SpaceNameNotResolved": {
+			init: spaceInitializer{},
+			mg: serviceInstance("managed", withExternalName(guid), observeOnly, func(r *v1alpha1.ServiceInstance) {
+				r.Spec.ForProvider.SpaceName = ptr.To("space")
+				r.Spec.ForProvider.OrgName = ptr.To("org")
+			}),
+		},
+		"ServicePlanNotResolved": {
+			init: servicePlanInitializer{},
+			mg:   serviceInstance("managed", withExternalName(guid), withServicePlan(v1alpha1.ServicePlanParameters{Offering: &offering, Plan: &plan}), observeOnly),
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			// the initializers have no kube client, so they must not look anything up
+			if err := tc.init.Initialize(context.Background(), tc.mg); err != nil {
+				t.Errorf("Initialize(...): unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestObserveImportByGUID(t *testing.T) {
+	m := &fake.MockServiceInstance{}
+	m.On("Get", guid).Return(
+		&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).SetLastOperation(v1alpha1.LastOperationCreate, v1alpha1.LastOperationSucceeded).ServiceInstance,
+		nil,
+	)
+	c := &external{
+		recorder:        event.NewNopRecorder(),
+		kube:            &test.MockClient{},
+		serviceinstance: &serviceinstance.Client{ServiceInstance: m},
+	}
+	// neither the name, the space nor the service plan is set
+	cr := serviceInstance("managed", withExternalName(guid), func(r *v1alpha1.ServiceInstance) {
+		r.Spec.ManagementPolicies = xpv1.ManagementPolicies{xpv1.ManagementActionObserve}
+		r.Spec.ForProvider.Name = nil
+	})
+
+	for _, init := range []managed.Initializer{spaceInitializer{}, servicePlanInitializer{}} {
+		if err := init.Initialize(context.Background(), cr); err != nil {
+			t.Fatalf("Initialize(...): unexpected error: %v", err)
+		}
+	}
+	obs, err := c.Observe(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("Observe(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, obs); diff != "" {
+		t.Errorf("Observe(...): -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff(&servicePlan, cr.Status.AtProvider.ServicePlan); diff != "" {
+		t.Errorf("Observe(...): -want service plan, +got:\n%s", diff)
+	}
+	m.AssertNotCalled(t, "Single")
+}
+
+// recorder records the events it receives.
+type recorder struct {
+	events []event.Event
+}
+
+func (r *recorder) Event(_ runtime.Object, e event.Event) { r.events = append(r.events, e) }
+
+func (r *recorder) WithAnnotations(_ ...string) event.Recorder { return r }
+
+func TestProvisionEvents(t *testing.T) {
+	type service func() *fake.MockServiceInstance
+
+	cases := map[string]struct {
+		mg      *v1alpha1.ServiceInstance
+		create  bool
+		service service
+		poll    error
+		want    []event.Event
+	}{
+		"CreateFailed": {
+			mg:     serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan})),
+			create: true,
+			service: func() *fake.MockServiceInstance {
+				m := &fake.MockServiceInstance{}
+				m.On("CreateManaged").Return("JOB123", nil)
+				return m
+			},
+			poll: errBoom,
+			want: []event.Event{event.Warning(reasonProvisionFailed, errBoom)},
+		},
+		"CreateStarted": {
+			mg:     serviceInstance("managed", withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan})),
+			create: true,
+			service: func() *fake.MockServiceInstance {
+				m := &fake.MockServiceInstance{}
+				m.On("CreateManaged").Return("JOB123", nil)
+				m.On("Single").Return(
+					&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).ServiceInstance,
+					nil,
+				)
+				return m
+			},
+			want: []event.Event{event.Normal(reasonProvisionStarted, "Started provisioning service instance "+guid)},
+		},
+		"LastOperationFailed": {
+			mg: serviceInstance("managed", withExternalName(guid), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withLastOperation(v1alpha1.LastOperationCreate, v1alpha1.LastOperationInProgress)),
+			service: func() *fake.MockServiceInstance {
+				m := &fake.MockServiceInstance{}
+				m.On("Get", guid).Return(
+					&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).SetLastOperation(v1alpha1.LastOperationCreate, v1alpha1.LastOperationFailed).ServiceInstance,
+					nil,
+				)
+				return m
+			},
+			want: []event.Event{event.Warning(reasonProvisionFailed, errors.New("Cannot provision service instance: create failed"))},
+		},
+		"LastOperationSucceeded": {
+			mg: serviceInstance("managed", withExternalName(guid), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withLastOperation(v1alpha1.LastOperationCreate, v1alpha1.LastOperationInProgress)),
+			service: func() *fake.MockServiceInstance {
+				m := &fake.MockServiceInstance{}
+				m.On("Get", guid).Return(
+					&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).SetLastOperation(v1alpha1.LastOperationCreate, v1alpha1.LastOperationSucceeded).ServiceInstance,
+					nil,
+				)
+				return m
+			},
+			want: []event.Event{event.Normal(reasonProvisionSucceeded, "Provisioned service instance: create succeeded")},
+		},
+		"LastOperationInProgress": {
+			mg: serviceInstance("managed", withExternalName(guid), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), withLastOperation(v1alpha1.LastOperationCreate, v1alpha1.LastOperationInProgress)),
+			service: func() *fake.MockServiceInstance {
+				m := &fake.MockServiceInstance{}
+				m.On("Get", guid).Return(
+					&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).SetLastOperation(v1alpha1.LastOperationCreate, v1alpha1.LastOperationInProgress).ServiceInstance,
+					nil,
+				)
+				return m
+			},
+		},
+		"NeverObserved": {
+			mg: serviceInstance("managed", withExternalName(guid), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan})),
+			service: func() *fake.MockServiceInstance {
+				m := &fake.MockServiceInstance{}
+				m.On("Get", guid).Return(
+					&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).SetLastOperation(v1alpha1.LastOperationCreate, v1alpha1.LastOperationFailed).ServiceInstance,
+					nil,
+				)
+				return m
+			},
+		},
+		"FirstObservationAfterCreate": {
+			mg: serviceInstance("managed", withExternalName(guid), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}), func(r *v1alpha1.ServiceInstance) {
+				meta.SetExternalCreateSucceeded(r, time.Now())
+			}),
+			service: func() *fake.MockServiceInstance {
+				m := &fake.MockServiceInstance{}
+				m.On("Get", guid).Return(
+					&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).SetLastOperation(v1alpha1.LastOperationCreate, v1alpha1.LastOperationSucceeded).ServiceInstance,
+					nil,
+				)
+				return m
+			},
+			want: []event.Event{event.Normal(reasonProvisionSucceeded, "Provisioned service instance: create succeeded")},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			rec := &recorder{}
+			job := &fake.MockJob{}
+			job.On("PollComplete").Return(tc.poll)
+			c := &external{
+				recorder: rec,
+				kube: &test.MockClient{
+					MockUpdate:       test.NewMockUpdateFn(nil),
+					MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+				},
+				serviceinstance: &serviceinstance.Client{
+					ServiceInstance: tc.service(),
+					Job:             job,
+				},
+			}
+			if tc.create {
+				_, _ = c.Create(context.Background(), tc.mg)
+			} else {
+				_, _ = c.Observe(context.Background(), tc.mg)
+			}
+			if diff := cmp.Diff(tc.want, rec.events); diff != "" {
+				t.Errorf("events: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateParameterSchema(t *testing.T) {
+	schema := json.RawMessage(`{"type":"object","properties":{"size":{"type":"integer"}},"additionalProperties":false}`)
+	plan := &cfresource.ServicePlan{}
+	plan.Schemas.ServiceInstance.Create.Parameters = &schema
+
+	cases := map[string]struct {
+		mg      *v1alpha1.ServiceInstance
+		wantErr bool
+	}{
+		"Disabled": {
+			mg: serviceInstance("managed", withParameters(`{"sise":1}`)),
+		},
+		"Valid": {
+			mg: serviceInstance("managed", withValidateParameterSchema(), withParameters(`{"size":1}`)),
+		},
+		"Invalid": {
+			mg:      serviceInstance("managed", withValidateParameterSchema(), withParameters(`{"sise":1}`)),
+			wantErr: true,
+		},
+		"AlreadyCreated": {
+			mg: serviceInstance("managed", withValidateParameterSchema(), withExternalName(guid), withParameters(`{"sise":1}`)),
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			err := validateParameterSchema(context.Background(), nil, tc.mg, plan)
+			if diff := cmp.Diff(tc.wantErr, err != nil); diff != "" {
+				t.Errorf("validateParameterSchema(...): -want error, +got error: %v\n%s", err, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package serviceinstance
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	typeDriftDetectionUnsupported xpv1.ConditionType = "DriftDetectionUnsupported"
+
+	reasonParametersNotRetrievable xpv1.ConditionReason = "ParametersNotRetrievable"
+	reasonParametersRetrievable    xpv1.ConditionReason = "ParametersRetrievable"
+)
+
+// driftDetectionUnsupported returns a condition that warns that the broker of
+// a service instance cannot return its parameters, so that drift detection
+// falls back to comparing the hash of the desired parameters.
+func driftDetectionUnsupported() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               typeDriftDetectionUnsupported,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reasonParametersNotRetrievable,
+		Message: "the service broker does not support fetching the parameters of the service instance, " +
+			"changes outside of this resource are not detected, set enableParameterDriftDetection to false",
+	}
+}
+
+// driftDetectionSupported returns a condition that indicates the broker of a
+// service instance returns its parameters for drift detection.
+func driftDetectionSupported() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               typeDriftDetectionUnsupported,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reasonParametersRetrievable,
+	}
+}
@@ -0,0 +1,75 @@
+package serviceinstance
+
+import (
+	cfresource "github.com/cloudfoundry/go-cfclient/v3/resource"
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/serviceinstance"
+)
+
+const (
+	// annotationKeyApprovePlanChange is the annotation that approves the change
+	// to the service plan with the GUID in its value.
+	annotationKeyApprovePlanChange = "cloudfoundry.crossplane.io/approve-plan-change"
+
+	typePendingPlanChange xpv1.ConditionType = "PendingPlanChange"
+
+	reasonApprovalRequired    xpv1.ConditionReason = "ApprovalRequired"
+	reasonNoPendingPlanChange xpv1.ConditionReason = "NoPendingPlanChange"
+)
+
+// withholdPlanChange reports whether the update of the service instance is
+// withheld because it changes the service plan without approval. The approval
+// names the new service plan, so that it does not approve later plan changes.
+func withholdPlanChange(cr *v1alpha1.ServiceInstance, r *cfresource.ServiceInstance) bool {
+	from, to, changed := serviceinstance.PlanChange(&cr.Spec.ForProvider, r)
+	if !cr.Spec.RequirePlanChangeApproval || !changed || cr.GetAnnotations()[annotationKeyApprovePlanChange] == to {
+		if cr.GetCondition(typePendingPlanChange).Status == corev1.ConditionTrue {
+			cr.SetConditions(noPendingPlanChange())
+		}
+		return false
+	}
+	cr.SetConditions(pendingPlanChange(from, to))
+	return true
+}
+
+// gatedSpec returns the spec of the service instance to compare with and to
+// apply to the observed service instance. While a plan change is withheld, the
+// observed service plan replaces the desired one, so that only the plan change
+// is withheld and the other changes are still applied.
+func gatedSpec(cr *v1alpha1.ServiceInstance, r *cfresource.ServiceInstance) *v1alpha1.ServiceInstanceParameters {
+	if !withholdPlanChange(cr, r) {
+		return &cr.Spec.ForProvider
+	}
+	spec := cr.Spec.ForProvider.DeepCopy()
+	spec.ServicePlan.ID = ptr.To(r.Relationships.ServicePlan.Data.GUID)
+	return spec
+}
+
+// pendingPlanChange returns a condition that indicates the update of a service
+// instance is withheld until its plan change is approved.
+func pendingPlanChange(from, to string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               typePendingPlanChange,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reasonApprovalRequired,
+		Message: "update withheld, the service plan changes from " + from + " to " + to +
+			", set the " + annotationKeyApprovePlanChange + " annotation to " + to + " to approve",
+	}
+}
+
+// noPendingPlanChange returns a condition that indicates a service instance has
+// no plan change awaiting approval.
+func noPendingPlanChange() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               typePendingPlanChange,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reasonNoPendingPlanChange,
+	}
+}
@@ -0,0 +1,180 @@
+package serviceinstance
+
+import (
+	"context"
+	"testing"
+
+	cfresource "github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/fake"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/serviceinstance"
+)
+
+func withRequirePlanChangeApproval() modifier {
+	return func(r *v1alpha1.ServiceInstance) {
+		r.Spec.RequirePlanChangeApproval = true
+	}
+}
+
+func withPlanChangeApproval(plan string) modifier {
+	return func(r *v1alpha1.ServiceInstance) {
+		r.ObjectMeta.Annotations[annotationKeyApprovePlanChange] = plan
+	}
+}
+
+func TestObservePlanChange(t *testing.T) {
+	type want struct {
+		obs     managed.ExternalObservation
+		pending corev1.ConditionStatus
+	}
+
+	cases := map[string]struct {
+		mg   *v1alpha1.ServiceInstance
+		want want
+	}{
+		"Ungated": {
+			mg: serviceInstance("managed", withExternalName(guid), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &newServicePlan})),
+			want: want{
+				obs:     managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				pending: corev1.ConditionUnknown,
+			},
+		},
+		"GatedWithoutApproval": {
+			mg: serviceInstance("managed", withExternalName(guid), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &newServicePlan}),
+				withRequirePlanChangeApproval()),
+			want: want{
+				obs:     managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				pending: corev1.ConditionTrue,
+			},
+		},
+		"GatedWithOtherChange": {
+			mg: serviceInstance("managed", withName("renamed"), withExternalName(guid), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &newServicePlan}),
+				withRequirePlanChangeApproval()),
+			want: want{
+				obs:     managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				pending: corev1.ConditionTrue,
+			},
+		},
+		"GatedWithStaleApproval": {
+			mg: serviceInstance("managed", withExternalName(guid), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &newServicePlan}),
+				withRequirePlanChangeApproval(), withPlanChangeApproval(servicePlan)),
+			want: want{
+				obs:     managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				pending: corev1.ConditionTrue,
+			},
+		},
+		"GatedWithApproval": {
+			mg: serviceInstance("managed", withExternalName(guid), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &newServicePlan}),
+				withRequirePlanChangeApproval(), withPlanChangeApproval(newServicePlan), withConditions(pendingPlanChange(servicePlan, newServicePlan))),
+			want: want{
+				obs:     managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				pending: corev1.ConditionFalse,
+			},
+		},
+		"GatedWithoutPlanChange": {
+			mg: serviceInstance("managed", withExternalName(guid), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &servicePlan}),
+				withRequirePlanChangeApproval()),
+			want: want{
+				obs:     managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				pending: corev1.ConditionUnknown,
+			},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			m := &fake.MockServiceInstance{}
+			m.On("Get", guid).Return(
+				&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).SetLastOperation(v1alpha1.LastOperationUpdate, v1alpha1.LastOperationSucceeded).ServiceInstance,
+				nil,
+			)
+			c := &external{
+				recorder:        event.NewNopRecorder(),
+				kube:            &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				serviceinstance: &serviceinstance.Client{ServiceInstance: m},
+			}
+
+			obs, err := c.Observe(context.Background(), tc.mg)
+			if err != nil {
+				t.Fatalf("Observe(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want.obs, obs); diff != "" {
+				t.Errorf("Observe(...): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.pending, tc.mg.GetCondition(typePendingPlanChange).Status); diff != "" {
+				t.Errorf("Observe(...): -want PendingPlanChange status, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+// updateRecorder records the managed updates sent to the service broker.
+type updateRecorder struct {
+	*fake.MockServiceInstance
+	updates []*cfresource.ServiceInstanceManagedUpdate
+}
+
+func (u *updateRecorder) UpdateManaged(ctx context.Context, guid string, opt *cfresource.ServiceInstanceManagedUpdate) (string, *cfresource.ServiceInstance, error) {
+	u.updates = append(u.updates, opt)
+	return u.MockServiceInstance.UpdateManaged(ctx, guid, opt)
+}
+
+func TestUpdatePlanChange(t *testing.T) {
+	cases := map[string]struct {
+		mg       *v1alpha1.ServiceInstance
+		wantPlan bool
+	}{
+		"Ungated": {
+			mg:       serviceInstance("managed", withName("renamed"), withExternalName(guid), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &newServicePlan})),
+			wantPlan: true,
+		},
+		"GatedWithoutApproval": {
+			mg: serviceInstance("managed", withName("renamed"), withExternalName(guid), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &newServicePlan}),
+				withRequirePlanChangeApproval()),
+			wantPlan: false,
+		},
+		"GatedWithApproval": {
+			mg: serviceInstance("managed", withName("renamed"), withExternalName(guid), withSpace(spaceGUID), withServicePlan(v1alpha1.ServicePlanParameters{ID: &newServicePlan}),
+				withRequirePlanChangeApproval(), withPlanChangeApproval(newServicePlan)),
+			wantPlan: true,
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			m := &updateRecorder{MockServiceInstance: &fake.MockServiceInstance{}}
+			m.On("Get", guid).Return(
+				&fake.NewServiceInstance("managed").SetName(name).SetGUID(guid).SetServicePlan(servicePlan).SetLastOperation(v1alpha1.LastOperationCreate, v1alpha1.LastOperationSucceeded).ServiceInstance,
+				nil,
+			)
+			m.On("UpdateManaged", guid).Return("", nil)
+			tc.mg.Status.AtProvider.ID = &guid
+			c := &external{
+				recorder:        event.NewNopRecorder(),
+				kube:            &test.MockClient{},
+				serviceinstance: &serviceinstance.Client{ServiceInstance: m},
+			}
+
+			if _, err := c.Update(context.Background(), tc.mg); err != nil {
+				t.Fatalf("Update(...): unexpected error: %v", err)
+			}
+			if len(m.updates) != 1 {
+				t.Fatalf("Update(...): want 1 update, got %d", len(m.updates))
+			}
+			// the other changes are applied with or without the plan change
+			if diff := cmp.Diff("renamed", ptr.Deref(m.updates[0].Name, "")); diff != "" {
+				t.Errorf("Update(...): -want name, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantPlan, m.updates[0].Relationships != nil); diff != "" {
+				t.Errorf("Update(...): -want plan change, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package serviceinstance
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/pkg/errors"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
+)
+
+const (
+	// defaultPurgeAfterDeleteFailures is the number of consecutive failed
+	// deletes after which a service instance is purged, if the spec does not
+	// set it.
+	defaultPurgeAfterDeleteFailures = 3
+
+	reasonPurge event.Reason = "PurgeAfterDeleteFailures"
+
+	errPurge = "cannot purge " + resourceType + " in " + externalSystem
+)
+
+// purgeDue returns true if the service instance is purged rather than
+// deleted, because purging is enabled and enough deletes failed.
+func purgeDue(cr *v1alpha1.ServiceInstance) bool {
+	if !cr.Spec.PurgeOnDeleteFailure {
+		return false
+	}
+	threshold := cr.Spec.PurgeAfterDeleteFailures
+	if threshold <= 0 {
+		threshold = defaultPurgeAfterDeleteFailures
+	}
+	return cr.Status.AtProvider.DeleteFailures >= threshold
+}
+
+// purge removes the service instance from Cloud Foundry without contacting
+// its service broker. A purge may leave the service instance orphaned at the
+// broker, so it is logged and recorded as a warning event.
+func (c *external) purge(ctx context.Context, cr *v1alpha1.ServiceInstance) error {
+	guid := *cr.Status.AtProvider.ID
+	failures := cr.Status.AtProvider.DeleteFailures
+	clients.LoggerFrom(ctx).Info("Purging service instance without its service broker after failed deletes", "guid", guid, "deleteFailures", failures)
+	c.recorder.Event(cr, event.Warning(reasonPurge, errors.Errorf("purging service instance %s after %d failed deletes, it may be left orphaned at its service broker", guid, failures)))
+
+	if err := c.serviceinstance.Purge(ctx, guid); err != nil && !clients.IsNotFound(err) {
+		return errors.Wrap(err, errPurge)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package serviceinstance
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+)
+
+const errRedactParams = "cannot redact the parameters or credentials of " + resourceType
+
+// redactJSON returns the JSON with the keys of all objects and the elements
+// of all arrays kept, and every string, number, boolean and null replaced by
+// the redacted marker, so that it shows which keys are set but not their
+// values. Empty JSON is returned as nil.
+func redactJSON(raw []byte) ([]byte, error) {
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return nil, nil
+	}
+	var v any
+	d := json.NewDecoder(bytes.NewReader(raw))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(redactValue(v))
+}
+
+// redactValue redacts a decoded JSON value, recursing into objects and arrays.
+func redactValue(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, e := range t {
+			t[k] = redactValue(e)
+		}
+		return t
+	case []any:
+		for i, e := range t {
+			t[i] = redactValue(e)
+		}
+		return t
+	default:
+		return json.RawMessage(redacted)
+	}
+}
+
+// recordRedactedParameters records the redacted parameters or credentials of
+// the service instance in its observation if the spec asks for it, and clears
+// them otherwise.
+func recordRedactedParameters(cr *v1alpha1.ServiceInstance, params []byte) error {
+	if !cr.Spec.RecordRedactedParameters {
+		cr.Status.AtProvider.RedactedParameters = nil
+		return nil
+	}
+	r, err := redactJSON(params)
+	if err != nil {
+		return errors.Wrap(err, errRedactParams)
+	}
+	if r == nil {
+		cr.Status.AtProvider.RedactedParameters = nil
+		return nil
+	}
+	cr.Status.AtProvider.RedactedParameters = &runtime.RawExtension{Raw: r}
+	return nil
+}
@@ -0,0 +1,102 @@
+package serviceinstance
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+)
+
+func TestRedactJSON(t *testing.T) {
+	cases := map[string]struct {
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		"Empty": {
+			raw: "",
+		},
+		"Flat": {
+			raw:  `{"user":"admin","password":"s3cr3t","port":5432,"tls":true}`,
+			want: `{"password":"REDACTED","port":"REDACTED","tls":"REDACTED","user":"REDACTED"}`,
+		},
+		"NestedObjects": {
+			raw:  `{"db":{"auth":{"user":"admin","password":"s3cr3t"},"size":12345678901234567890},"region":"eu10"}`,
+			want: `{"db":{"auth":{"password":"REDACTED","user":"REDACTED"},"size":"REDACTED"},"region":"REDACTED"}`,
+		},
+		"NestedArrays": {
+			raw:  `{"hosts":["a","b"],"users":[{"name":"a","keys":[1,2]},{"name":"b"}],"matrix":[[1],[]]}`,
+			want: `{"hosts":["REDACTED","REDACTED"],"matrix":[["REDACTED"],[]],"users":[{"keys":["REDACTED","REDACTED"],"name":"REDACTED"},{"name":"REDACTED"}]}`,
+		},
+		"EmptyContainers": {
+			raw:  `{"labels":{},"tags":[]}`,
+			want: `{"labels":{},"tags":[]}`,
+		},
+		"Null": {
+			raw:  `{"removed":null}`,
+			want: `{"removed":"REDACTED"}`,
+		},
+		"Scalar": {
+			raw:  `"s3cr3t"`,
+			want: `"REDACTED"`,
+		},
+		"Invalid": {
+			raw:     `{"user":`,
+			wantErr: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := redactJSON([]byte(tc.raw))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("redactJSON(...): want error %t, got %v", tc.wantErr, err)
+			}
+			if diff := cmp.Diff(tc.want, string(got)); diff != "" {
+				t.Errorf("redactJSON(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRecordRedactedParameters(t *testing.T) {
+	stale := &runtime.RawExtension{Raw: []byte(`{"old":"REDACTED"}`)}
+	withRecord := func(r *v1alpha1.ServiceInstance) {
+		r.Spec.RecordRedactedParameters = true
+	}
+	withStale := func(r *v1alpha1.ServiceInstance) {
+		r.Status.AtProvider.RedactedParameters = stale
+	}
+
+	cases := map[string]struct {
+		mg     *v1alpha1.ServiceInstance
+		params string
+		want   *runtime.RawExtension
+	}{
+		"Recorded": {
+			mg:     serviceInstance("managed", withRecord),
+			params: `{"db":{"password":"s3cr3t"}}`,
+			want:   &runtime.RawExtension{Raw: []byte(`{"db":{"password":"REDACTED"}}`)},
+		},
+		"NoParameters": {
+			mg:   serviceInstance("managed", withRecord, withStale),
+			want: nil,
+		},
+		"Disabled": {
+			mg:     serviceInstance("managed", withStale),
+			params: `{"db":{"password":"s3cr3t"}}`,
+			want:   nil,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if err := recordRedactedParameters(tc.mg, []byte(tc.params)); err != nil {
+				t.Fatalf("recordRedactedParameters(...): %v", err)
+			}
+			if diff := cmp.Diff(tc.want, tc.mg.Status.AtProvider.RedactedParameters); diff != "" {
+				t.Errorf("recordRedactedParameters(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
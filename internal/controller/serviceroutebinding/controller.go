@@ -4,13 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strings"
 
 	cfclient "github.com/cloudfoundry/go-cfclient/v3/client"
 	cfresource "github.com/cloudfoundry/go-cfclient/v3/resource"
 	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/feature"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
@@ -23,6 +23,7 @@ import (
 	apisv1beta1 "github.com/SAP/crossplane-provider-cloudfoundry/apis/v1beta1"
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/job"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/route"
 	srb "github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/serviceroutebinding"
 )
 
@@ -45,6 +46,8 @@ const (
 	errMissingRelationshipGUIDs = "missing relationship GUIDs (route=%q serviceInstance=%q)"
 	errNoBindingReturned        = "no binding returned after creation"
 	errParametersFromCF         = "cannot get parameters from " + resourceType + " in " + externalSystem + ": %w"
+	errRouteNotFound            = "route %s does not exist in " + externalSystem
+	errGetRoute                 = "cannot get route of " + resourceType + " in " + externalSystem + ": %w"
 )
 
 // Setup adds a controller that reconciles ServiceRouteBinding CR.
@@ -53,15 +56,21 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 
 	options := []managed.ReconcilerOption{
 		managed.WithInitializers(),
-		managed.WithExternalConnecter(&connector{
+		managed.WithExternalConnecter(clients.WithPause(clients.WithErrorClassification(clients.WithOperationTimeout(mgr.GetClient(), &connector{
 			kube:  mgr.GetClient(),
 			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1beta1.ProviderConfigUsage{}),
-		}),
+		}, clients.DefaultOperationTimeout)))),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithTimeout(clients.MaxOperationTimeout),
+		managed.WithPollIntervalHook(clients.PollIntervalHook(mgr.GetClient())),
 		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 		managed.WithPollInterval(o.PollInterval),
 	}
 
+	if o.Features.Enabled(feature.EnableBetaManagementPolicies) {
+		options = append(options, managed.WithManagementPolicies())
+	}
+
 	r := managed.NewReconciler(mgr,
 		resource.ManagedKind(v1alpha1.ServiceRouteBinding_GroupVersionKind),
 		options...)
@@ -98,18 +107,20 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	client := srb.NewClient(cf)
 
 	ext := &external{
-		kube:      c.kube,
-		srbClient: client,
-		job:       cf.Jobs,
+		kube:        c.kube,
+		srbClient:   client,
+		routeClient: cf.Routes,
+		job:         cf.Jobs,
 	}
 	return ext, nil
 }
 
 // external implements the managed.ExternalClient interface for ServiceRouteBinding.
 type external struct {
-	kube      k8s.Client
-	srbClient srb.ServiceRouteBinding
-	job       job.Job
+	kube        k8s.Client
+	srbClient   srb.ServiceRouteBinding
+	routeClient route.Route
+	job         job.Job
 }
 
 // Disconnect implements the managed.ExternalClient interface
@@ -131,7 +142,7 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{ResourceExists: false}, nil
 	}
 	servicerouteBinding, err := srb.GetByID(ctx, e.srbClient, guid, cr.Spec.ForProvider)
-	if isNotFoundError(err) {
+	if clients.IsNotFound(err) {
 		return managed.ExternalObservation{ResourceExists: false}, nil
 	} else if err != nil {
 		return managed.ExternalObservation{}, fmt.Errorf(errGet, err)
@@ -148,6 +159,17 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 
 	srb.UpdateObservation(&cr.Status.AtProvider, servicerouteBinding, paramMap)
 
+	// the route of a binding is immutable, so its details are only looked up once
+	if cr.Status.AtProvider.RouteURL == "" && cr.Status.AtProvider.Route != "" {
+		r, err := e.routeClient.Get(ctx, cr.Status.AtProvider.Route)
+		if err != nil && !clients.IsNotFound(err) {
+			return managed.ExternalObservation{}, fmt.Errorf(errGetRoute, err)
+		}
+		if r != nil {
+			srb.UpdateRouteObservation(&cr.Status.AtProvider, r)
+		}
+	}
+
 	obs, herr := handleObservationState(servicerouteBinding, cr)
 	if herr != nil {
 		return managed.ExternalObservation{}, herr
@@ -167,6 +189,19 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	if routeGUID == "" || serviceInstanceGUID == "" {
 		return managed.ExternalCreation{}, fmt.Errorf(errCreate, fmt.Errorf(errMissingRelationshipGUIDs, routeGUID, serviceInstanceGUID))
 	}
+	if err := clients.ValidateGUID("route", &routeGUID); err != nil {
+		return managed.ExternalCreation{}, fmt.Errorf(errCreate, err)
+	}
+
+	// the binding targets the exact route, e.g. one with a path or on an
+	// internal domain, so a missing route is reported as such
+	r, err := e.routeClient.Get(ctx, routeGUID)
+	if clients.IsNotFound(err) {
+		return managed.ExternalCreation{}, fmt.Errorf(errCreate, fmt.Errorf(errRouteNotFound, routeGUID))
+	} else if err != nil {
+		return managed.ExternalCreation{}, fmt.Errorf(errGetRoute, err)
+	}
+	srb.UpdateRouteObservation(&cr.Status.AtProvider, r)
 
 	// Get ParametersSecretRef if provided
 	parameterFromSecret := runtime.RawExtension{}
@@ -220,6 +255,11 @@ func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, nil
 	}
 
+	// CF rejects an update while the last operation is in progress
+	if op := cr.Status.AtProvider.LastOperation; op != nil && clients.IsOperationInProgress(op.State) {
+		return managed.ExternalUpdate{}, nil
+	}
+
 	// Update metadata (labels and annotations) - only supported fields for ServiceRouteBindings
 	_, err := srb.Update(ctx, e.srbClient, guid, cr.Spec.ForProvider)
 	if err != nil {
@@ -240,7 +280,7 @@ func (e *external) Delete(ctx context.Context, mg resource.Managed) (managed.Ext
 
 	err := srb.Delete(ctx, e.srbClient, meta.GetExternalName(cr))
 
-	if isNotFoundError(err) {
+	if clients.IsNotFound(err) {
 		return managed.ExternalDelete{}, nil
 	}
 	if err != nil && !errors.Is(err, cfclient.AsyncProcessTimeoutError) {
@@ -260,13 +300,15 @@ func handleObservationState(binding *cfresource.ServiceRouteBinding, cr *v1alpha
 	state := binding.LastOperation.State
 	typ := binding.LastOperation.Type
 
-	switch state {
-	case v1alpha1.LastOperationInitial, v1alpha1.LastOperationInProgress:
+	if clients.IsOperationInProgress(state) {
 		cr.SetConditions(xpv1.Unavailable().WithMessage(binding.LastOperation.Description))
 		return managed.ExternalObservation{
 			ResourceExists:   true,
 			ResourceUpToDate: true, // Do not update the resource while the last operation is in progress
 		}, nil
+	}
+
+	switch state {
 	case v1alpha1.LastOperationFailed:
 		cr.SetConditions(xpv1.Unavailable().WithMessage(binding.LastOperation.Description))
 		// Service Route Bindings do not support updates, only create and delete operations
@@ -281,75 +323,10 @@ func handleObservationState(binding *cfresource.ServiceRouteBinding, cr *v1alpha
 		cr.SetConditions(xpv1.Available(), xpv1.ReconcileSuccess())
 
 		// Check if metadata (labels/annotations) needs to be updated
-		upToDate := isMetadataUpToDate(cr.Spec.ForProvider, binding)
+		upToDate := clients.MetadataEqual(cr.Spec.ForProvider.Labels, cr.Spec.ForProvider.Annotations, v1alpha1.ManagedMetadata{}, binding.Metadata)
 
 		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: upToDate}, nil
 	}
 
 	return managed.ExternalObservation{}, errors.New(errUnknownState)
 }
-
-// isMetadataUpToDate checks if labels and annotations match between desired state (spec) and actual state (CF).
-// Returns true if metadata is up-to-date, false if an update is needed.
-func isMetadataUpToDate(spec v1alpha1.ServiceRouteBindingParameters, binding *cfresource.ServiceRouteBinding) bool {
-	// If no metadata in CF resource, check if spec wants to set any
-	if binding.Metadata == nil {
-		return spec.Labels == nil && spec.Annotations == nil
-	}
-
-	if !metadataMapEqual(spec.Labels, binding.Metadata.Labels) {
-		return false
-	}
-
-	if !metadataMapEqual(spec.Annotations, binding.Metadata.Annotations) {
-		return false
-	}
-
-	return true
-}
-
-// metadataMapEqual compares two metadata maps (labels or annotations).
-func metadataMapEqual(desired, actual map[string]*string) bool {
-	// check if both are nil/empty
-	if len(desired) == 0 && len(actual) == 0 {
-		return true
-	}
-
-	if len(desired) != len(actual) {
-		return false
-	}
-
-	// Compare each key-value pair
-	for key, desiredVal := range desired {
-		actualVal, exists := actual[key]
-		if !exists {
-			return false
-		}
-
-		if (desiredVal == nil) != (actualVal == nil) {
-			return false
-		}
-		if desiredVal != nil && actualVal != nil && *desiredVal != *actualVal {
-			return false
-		}
-	}
-
-	return true
-}
-
-func isNotFoundError(err error) bool {
-	if err == nil {
-		return false
-	}
-	if errors.Is(err, cfclient.ErrNoResultsReturned) || errors.Is(err, cfclient.ErrExactlyOneResultNotReturned) {
-		return true
-	}
-	msg := err.Error()
-	if strings.Contains(msg, "CF-ResourceNotFound") {
-		return true
-	}
-	if strings.Contains(strings.ToLower(msg), "service route binding not found") {
-		return true
-	}
-	return false
-}
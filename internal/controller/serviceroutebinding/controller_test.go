@@ -29,6 +29,7 @@ var (
 	routeGUID           = "3d8b0d04-d537-4e4e-8c6f-f09ca0e7f56f"
 	serviceInstanceGUID = "4d8b0d04-d537-4e4e-8c6f-f09ca0e7f56f"
 	routeServiceURL     = "https://route-service.example.com"
+	domainGUID          = "5d8b0d04-d537-4e4e-8c6f-f09ca0e7f56f"
 )
 
 // Helper function to create string pointers
@@ -71,6 +72,12 @@ func withStatus(guid string) modifier {
 	}
 }
 
+func withLastOperation(typ, state string) modifier {
+	return func(r *v1alpha1.ServiceRouteBinding) {
+		r.Status.AtProvider.LastOperation = &v1alpha1.LastOperation{Type: typ, State: state}
+	}
+}
+
 func withLabels(labels map[string]*string) modifier {
 	return func(r *v1alpha1.ServiceRouteBinding) {
 		r.Spec.ForProvider.Labels = labels
@@ -83,6 +90,20 @@ func withAnnotations(annotations map[string]*string) modifier {
 	}
 }
 
+// pathRoute returns a route with a path on an internal domain.
+func pathRoute() *cfresource.Route {
+	r := &cfresource.Route{Host: "api", Path: "/v1", URL: "api.apps.internal/v1"}
+	r.GUID = routeGUID
+	r.Relationships.Domain.Data = &cfresource.Relationship{GUID: domainGUID}
+	return r
+}
+
+func routeFound() *fake.MockRoute {
+	m := &fake.MockRoute{}
+	m.On("Get", routeGUID).Return(pathRoute(), nil)
+	return m
+}
+
 func serviceRouteBinding(m ...modifier) *v1alpha1.ServiceRouteBinding {
 	r := &v1alpha1.ServiceRouteBinding{
 		ObjectMeta: metav1.ObjectMeta{
@@ -338,7 +359,8 @@ func TestObserve(t *testing.T) {
 				kube: &test.MockClient{
 					MockUpdate: test.NewMockUpdateFn(nil),
 				},
-				srbClient: tc.service(),
+				srbClient:   tc.service(),
+				routeClient: routeFound(),
 			}
 			obs, err := c.Observe(context.Background(), tc.args.mg)
 
@@ -372,17 +394,74 @@ func TestCreate(t *testing.T) {
 
 	srb := serviceRouteBinding(withRouteID(routeGUID), withServiceInstanceID(serviceInstanceGUID))
 
+	// the created binding records the route, which has a path on an internal domain
+	created := serviceRouteBinding(withRouteID(routeGUID), withServiceInstanceID(serviceInstanceGUID), withExternalName(guid), withConditions(xpv1.Creating()))
+	created.Status.AtProvider.RouteHost = "api"
+	created.Status.AtProvider.RoutePath = "/v1"
+	created.Status.AtProvider.RouteDomain = domainGUID
+	created.Status.AtProvider.RouteURL = "api.apps.internal/v1"
+
 	cases := map[string]struct {
 		args    args
 		want    want
 		service service
+		route   func() *fake.MockRoute
 	}{
+		"RouteNotFound": {
+			args: args{
+				mg: srb.DeepCopy(),
+			},
+			want: want{
+				mg:  serviceRouteBinding(withRouteID(routeGUID), withServiceInstanceID(serviceInstanceGUID)),
+				obs: managed.ExternalCreation{},
+				err: fmt.Errorf(errCreate, fmt.Errorf(errRouteNotFound, routeGUID)),
+			},
+			service: func() *fake.MockServiceRouteBinding {
+				return &fake.MockServiceRouteBinding{}
+			},
+			route: func() *fake.MockRoute {
+				m := &fake.MockRoute{}
+				m.On("Get", routeGUID).Return((*cfresource.Route)(nil), fake.ErrResourceNotFound)
+				return m
+			},
+		},
+		"GetRouteFailed": {
+			args: args{
+				mg: srb.DeepCopy(),
+			},
+			want: want{
+				mg:  serviceRouteBinding(withRouteID(routeGUID), withServiceInstanceID(serviceInstanceGUID)),
+				obs: managed.ExternalCreation{},
+				err: fmt.Errorf(errGetRoute, errBoom),
+			},
+			service: func() *fake.MockServiceRouteBinding {
+				return &fake.MockServiceRouteBinding{}
+			},
+			route: func() *fake.MockRoute {
+				m := &fake.MockRoute{}
+				m.On("Get", routeGUID).Return((*cfresource.Route)(nil), errBoom)
+				return m
+			},
+		},
+		"MalformedRouteGUID": {
+			args: args{
+				mg: serviceRouteBinding(withRouteID("my-route"), withServiceInstanceID(serviceInstanceGUID)),
+			},
+			want: want{
+				mg:  serviceRouteBinding(withRouteID("my-route"), withServiceInstanceID(serviceInstanceGUID)),
+				obs: managed.ExternalCreation{},
+				err: fmt.Errorf(errCreate, errors.New(`route "my-route" is not a valid GUID`)),
+			},
+			service: func() *fake.MockServiceRouteBinding {
+				return &fake.MockServiceRouteBinding{}
+			},
+		},
 		"Successful": {
 			args: args{
 				mg: srb.DeepCopy(),
 			},
 			want: want{
-				mg:  serviceRouteBinding(withRouteID(routeGUID), withServiceInstanceID(serviceInstanceGUID), withExternalName(guid)),
+				mg:  created,
 				obs: managed.ExternalCreation{},
 				err: nil,
 			},
@@ -462,7 +541,11 @@ func TestCreate(t *testing.T) {
 				kube: &test.MockClient{
 					MockUpdate: test.NewMockUpdateFn(nil),
 				},
-				srbClient: tc.service(),
+				srbClient:   tc.service(),
+				routeClient: routeFound(),
+			}
+			if tc.route != nil {
+				c.routeClient = tc.route()
 			}
 			obs, err := c.Create(context.Background(), tc.args.mg)
 
@@ -478,6 +561,11 @@ func TestCreate(t *testing.T) {
 			if diff := cmp.Diff(tc.want.obs, obs); diff != "" {
 				t.Errorf("Create(...): -want, +got:\n%s", diff)
 			}
+			if tc.want.err == nil {
+				if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+					t.Errorf("Create(...): -want managed resource, +got managed resource:\n%s", diff)
+				}
+			}
 		})
 	}
 }
@@ -549,6 +637,30 @@ func TestUpdate(t *testing.T) {
 				return m
 			},
 		},
+		"OperationInProgress": {
+			args: args{
+				mg: serviceRouteBinding(
+					withServiceInstanceID(serviceInstanceGUID),
+					withExternalName(guid),
+					withLabels(map[string]*string{"env": toStringPointer("prod")}),
+					withLastOperation(v1alpha1.LastOperationCreate, v1alpha1.LastOperationInProgress),
+				),
+			},
+			want: want{
+				mg: serviceRouteBinding(
+					withServiceInstanceID(serviceInstanceGUID),
+					withExternalName(guid),
+					withLabels(map[string]*string{"env": toStringPointer("prod")}),
+					withLastOperation(v1alpha1.LastOperationCreate, v1alpha1.LastOperationInProgress),
+				),
+				obs: managed.ExternalUpdate{},
+				err: nil,
+			},
+			service: func() *fake.MockServiceRouteBinding {
+				// no CF call is issued while the last operation is in progress
+				return &fake.MockServiceRouteBinding{}
+			},
+		},
 		"UpdateWithAnnotations": {
 			args: args{
 				mg: serviceRouteBinding(
@@ -827,338 +939,43 @@ func TestHandleObservationState(t *testing.T) {
 	}
 }
 
-func TestIsNotFoundError(t *testing.T) {
-	cases := map[string]struct {
-		err  error
-		want bool
-	}{
-		"Nil": {
-			err:  nil,
-			want: false,
-		},
-		"ErrNoResultsReturned": {
-			err:  fake.ErrNoResultReturned,
-			want: true,
-		},
-		"ErrExactlyOneResultNotReturned": {
-			err:  fake.ErrExactlyOneResultNotReturned,
-			want: true,
-		},
-		"CF-ResourceNotFound": {
-			err:  errors.New("CF-ResourceNotFound: The resource could not be found"),
-			want: true,
-		},
-		"ServiceRouteBindingNotFound": {
-			err:  errors.New("service route binding not found"),
-			want: true,
-		},
-		"OtherError": {
-			err:  errBoom,
-			want: false,
-		},
-	}
-
-	for n, tc := range cases {
-		t.Run(n, func(t *testing.T) {
-			got := isNotFoundError(tc.err)
-			if diff := cmp.Diff(tc.want, got); diff != "" {
-				t.Errorf("isNotFoundError(...): -want, +got:\n%s", diff)
-			}
-		})
-	}
-}
-
-func TestIsMetadataUpToDate(t *testing.T) {
-	cases := map[string]struct {
-		spec     v1alpha1.ServiceRouteBindingParameters
-		binding  *cfresource.ServiceRouteBinding
-		expected bool
-	}{
-		"BothNil": {
-			spec:     v1alpha1.ServiceRouteBindingParameters{},
-			binding:  &cfresource.ServiceRouteBinding{},
-			expected: true,
-		},
-		"BothEmpty": {
-			spec: v1alpha1.ServiceRouteBindingParameters{
-				ResourceMetadata: v1alpha1.ResourceMetadata{},
-			},
-			binding: &cfresource.ServiceRouteBinding{
-				Metadata: &cfresource.Metadata{},
-			},
-			expected: true,
-		},
-		"MatchingLabels": {
-			spec: v1alpha1.ServiceRouteBindingParameters{
-				ResourceMetadata: v1alpha1.ResourceMetadata{
-					Labels: map[string]*string{
-						"env":  toStringPointer("prod"),
-						"team": toStringPointer("platform"),
-					},
-				},
-			},
-			binding: &cfresource.ServiceRouteBinding{
-				Metadata: &cfresource.Metadata{
-					Labels: map[string]*string{
-						"env":  toStringPointer("prod"),
-						"team": toStringPointer("platform"),
-					},
-				},
-			},
-			expected: true,
-		},
-		"MatchingAnnotations": {
-			spec: v1alpha1.ServiceRouteBindingParameters{
-				ResourceMetadata: v1alpha1.ResourceMetadata{
-					Annotations: map[string]*string{
-						"description": toStringPointer("test binding"),
-					},
-				},
-			},
-			binding: &cfresource.ServiceRouteBinding{
-				Metadata: &cfresource.Metadata{
-					Annotations: map[string]*string{
-						"description": toStringPointer("test binding"),
-					},
-				},
-			},
-			expected: true,
-		},
-		"MatchingBoth": {
-			spec: v1alpha1.ServiceRouteBindingParameters{
-				ResourceMetadata: v1alpha1.ResourceMetadata{
-					Labels: map[string]*string{
-						"env": toStringPointer("prod"),
-					},
-					Annotations: map[string]*string{
-						"description": toStringPointer("test"),
-					},
-				},
-			},
-			binding: &cfresource.ServiceRouteBinding{
-				Metadata: &cfresource.Metadata{
-					Labels: map[string]*string{
-						"env": toStringPointer("prod"),
-					},
-					Annotations: map[string]*string{
-						"description": toStringPointer("test"),
-					},
-				},
-			},
-			expected: true,
-		},
-		"DifferentLabels": {
-			spec: v1alpha1.ServiceRouteBindingParameters{
-				ResourceMetadata: v1alpha1.ResourceMetadata{
-					Labels: map[string]*string{
-						"env": toStringPointer("prod"),
-					},
-				},
-			},
-			binding: &cfresource.ServiceRouteBinding{
-				Metadata: &cfresource.Metadata{
-					Labels: map[string]*string{
-						"env": toStringPointer("dev"),
-					},
-				},
-			},
-			expected: false,
-		},
-		"DifferentAnnotations": {
-			spec: v1alpha1.ServiceRouteBindingParameters{
-				ResourceMetadata: v1alpha1.ResourceMetadata{
-					Annotations: map[string]*string{
-						"description": toStringPointer("old"),
-					},
-				},
-			},
-			binding: &cfresource.ServiceRouteBinding{
-				Metadata: &cfresource.Metadata{
-					Annotations: map[string]*string{
-						"description": toStringPointer("new"),
-					},
-				},
-			},
-			expected: false,
-		},
-		"MissingLabelInActual": {
-			spec: v1alpha1.ServiceRouteBindingParameters{
-				ResourceMetadata: v1alpha1.ResourceMetadata{
-					Labels: map[string]*string{
-						"env":  toStringPointer("prod"),
-						"team": toStringPointer("platform"),
-					},
-				},
-			},
-			binding: &cfresource.ServiceRouteBinding{
-				Metadata: &cfresource.Metadata{
-					Labels: map[string]*string{
-						"env": toStringPointer("prod"),
-					},
-				},
-			},
-			expected: false,
-		},
-		"ExtraLabelInActual": {
-			spec: v1alpha1.ServiceRouteBindingParameters{
-				ResourceMetadata: v1alpha1.ResourceMetadata{
-					Labels: map[string]*string{
-						"env": toStringPointer("prod"),
-					},
-				},
-			},
-			binding: &cfresource.ServiceRouteBinding{
-				Metadata: &cfresource.Metadata{
-					Labels: map[string]*string{
-						"env":  toStringPointer("prod"),
-						"team": toStringPointer("platform"),
-					},
-				},
-			},
-			expected: false,
-		},
-		"SpecWithoutMetadataBindingHasLabels": {
-			spec: v1alpha1.ServiceRouteBindingParameters{},
-			binding: &cfresource.ServiceRouteBinding{
-				Metadata: &cfresource.Metadata{
-					Labels: map[string]*string{
-						"env": toStringPointer("prod"),
-					},
-				},
-			},
-			expected: false,
-		},
-		"SpecHasLabelsBindingHasNoMetadata": {
-			spec: v1alpha1.ServiceRouteBindingParameters{
-				ResourceMetadata: v1alpha1.ResourceMetadata{
-					Labels: map[string]*string{
-						"env": toStringPointer("prod"),
-					},
-				},
-			},
-			binding: &cfresource.ServiceRouteBinding{
-				Metadata: nil,
-			},
-			expected: false,
-		},
-	}
-
-	for name, tc := range cases {
-		t.Run(name, func(t *testing.T) {
-			result := isMetadataUpToDate(tc.spec, tc.binding)
-			if diff := cmp.Diff(tc.expected, result); diff != "" {
-				t.Errorf("isMetadataUpToDate(...): -want, +got:\n%s", diff)
-			}
-		})
-	}
-}
-
-func TestMetadataMapEqual(t *testing.T) {
-	cases := map[string]struct {
-		desired  map[string]*string
-		actual   map[string]*string
-		expected bool
-	}{
-		"BothNil": {
-			desired:  nil,
-			actual:   nil,
-			expected: true,
-		},
-		"BothEmpty": {
-			desired:  map[string]*string{},
-			actual:   map[string]*string{},
-			expected: true,
-		},
-		"Equal": {
-			desired: map[string]*string{
-				"key1": toStringPointer("value1"),
-				"key2": toStringPointer("value2"),
-			},
-			actual: map[string]*string{
-				"key1": toStringPointer("value1"),
-				"key2": toStringPointer("value2"),
-			},
-			expected: true,
-		},
-		"DifferentValues": {
-			desired: map[string]*string{
-				"key1": toStringPointer("value1"),
-			},
-			actual: map[string]*string{
-				"key1": toStringPointer("value2"),
-			},
-			expected: false,
-		},
-		"DifferentLengths": {
-			desired: map[string]*string{
-				"key1": toStringPointer("value1"),
-			},
-			actual: map[string]*string{
-				"key1": toStringPointer("value1"),
-				"key2": toStringPointer("value2"),
-			},
-			expected: false,
-		},
-		"MissingKeyInActual": {
-			desired: map[string]*string{
-				"key1": toStringPointer("value1"),
-				"key2": toStringPointer("value2"),
-			},
-			actual: map[string]*string{
-				"key1": toStringPointer("value1"),
-			},
-			expected: false,
-		},
-		"NilDesiredEmptyActual": {
-			desired:  nil,
-			actual:   map[string]*string{},
-			expected: true,
-		},
-		"EmptyDesiredNilActual": {
-			desired:  map[string]*string{},
-			actual:   nil,
-			expected: true,
-		},
-		"NilDesiredNonEmptyActual": {
-			desired: nil,
-			actual: map[string]*string{
-				"key1": toStringPointer("value1"),
-			},
-			expected: false,
-		},
-		"NonEmptyDesiredNilActual": {
-			desired: map[string]*string{
-				"key1": toStringPointer("value1"),
-			},
-			actual:   nil,
-			expected: false,
-		},
-		"NilPointerValues": {
-			desired: map[string]*string{
-				"key1": nil,
-			},
-			actual: map[string]*string{
-				"key1": nil,
-			},
-			expected: true,
-		},
-		"MismatchedNilPointers": {
-			desired: map[string]*string{
-				"key1": toStringPointer("value1"),
-			},
-			actual: map[string]*string{
-				"key1": nil,
-			},
-			expected: false,
-		},
-	}
-
-	for name, tc := range cases {
-		t.Run(name, func(t *testing.T) {
-			result := metadataMapEqual(tc.desired, tc.actual)
-			if diff := cmp.Diff(tc.expected, result); diff != "" {
-				t.Errorf("metadataMapEqual(...): -want, +got:\n%s", diff)
-			}
-		})
+func TestObserveRecordsRoute(t *testing.T) {
+	succeeded := &fake.NewServiceRouteBinding().
+		SetGUID(guid).
+		SetRouteRef(routeGUID).
+		SetServiceInstanceRef(serviceInstanceGUID).
+		SetLastOperation(v1alpha1.LastOperationCreate, v1alpha1.LastOperationSucceeded).
+		ServiceRouteBinding
+	m := &fake.MockServiceRouteBinding{}
+	m.On("Get", mock.Anything, guid).Return(succeeded, nil)
+	routes := routeFound()
+
+	c := &external{kube: &test.MockClient{}, srbClient: m, routeClient: routes}
+	mg := serviceRouteBinding(withExternalName(guid), withRouteID(routeGUID), withServiceInstanceID(serviceInstanceGUID))
+
+	// the route is only looked up until its details are recorded
+	for range 2 {
+		if _, err := c.Observe(context.Background(), mg); err != nil {
+			t.Fatalf("Observe(...): unexpected error: %v", err)
+		}
+	}
+	routes.AssertNumberOfCalls(t, "Get", 1)
+
+	want := v1alpha1.ServiceRouteBindingObservation{
+		Route:       routeGUID,
+		RouteHost:   "api",
+		RoutePath:   "/v1",
+		RouteDomain: domainGUID,
+		RouteURL:    "api.apps.internal/v1",
+	}
+	got := v1alpha1.ServiceRouteBindingObservation{
+		Route:       mg.Status.AtProvider.Route,
+		RouteHost:   mg.Status.AtProvider.RouteHost,
+		RoutePath:   mg.Status.AtProvider.RoutePath,
+		RouteDomain: mg.Status.AtProvider.RouteDomain,
+		RouteURL:    mg.Status.AtProvider.RouteURL,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Observe(...): -want route, +got route:\n%s", diff)
 	}
 }
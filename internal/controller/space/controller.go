@@ -3,14 +3,17 @@ package space
 import (
 	"context"
 
+	cfresource "github.com/cloudfoundry/go-cfclient/v3/resource"
 	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/feature"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
 	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	k8s "sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -32,7 +35,21 @@ const (
 	errCreate            = "cannot create cloudfoundry Space"
 	errUpdate            = "cannot update cloudfoundry Space"
 	errDelete            = "cannot delete cloudfoundry Space"
-	errEnableSSH         = "cannot enable SSH for space"
+	errEnableFeature     = "cannot enable feature %s for space"
+	errDisableFeature    = "cannot disable feature %s for space"
+	errIsolationSegment  = "cannot assign isolation segment to space"
+	errSecurityGroups    = "cannot bind security groups to space"
+	errListContents      = "cannot list contents of space"
+	errDeleteContents    = "cannot delete contents of space"
+	errNotEmpty          = "cannot delete cloudfoundry Space: space is not empty, set forceDelete to delete its contents"
+	errAdopt             = "cannot adopt existing cloudfoundry Space"
+)
+
+const (
+	reasonFeatureEnabled   event.Reason = "FeatureEnabled"
+	reasonFeatureDisabled  event.Reason = "FeatureDisabled"
+	reasonDeletingContents event.Reason = "DeletingContents"
+	reasonAdopted          event.Reason = "Adopted"
 )
 
 // Setup adds a controller that reconciles Org managed resources.
@@ -41,18 +58,27 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 
 	options := []managed.ReconcilerOption{
 
-		managed.WithExternalConnecter(&connector{
-			kube:  mgr.GetClient(),
-			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1beta1.ProviderConfigUsage{}),
-		}),
+		managed.WithExternalConnecter(clients.WithPause(clients.WithErrorClassification(clients.WithOperationTimeout(mgr.GetClient(), &connector{
+			kube:     mgr.GetClient(),
+			usage:    resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1beta1.ProviderConfigUsage{}),
+			recorder: event.NewAPIRecorder(mgr.GetEventRecorderFor(name), clients.RateLimitEvents(clients.EventRateLimitInterval)),
+		}, clients.DefaultOperationTimeout)))),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithTimeout(clients.MaxOperationTimeout),
+		managed.WithPollIntervalHook(clients.PollIntervalHook(mgr.GetClient())),
 		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 		managed.WithPollInterval(o.PollInterval),
 		managed.WithInitializers(&orgInitializer{
 			kube: mgr.GetClient(),
+		}, &isolationSegmentInitializer{
+			kube: mgr.GetClient(),
 		}),
 	}
 
+	if o.Features.Enabled(feature.EnableBetaManagementPolicies) {
+		options = append(options, managed.WithManagementPolicies())
+	}
+
 	r := managed.NewReconciler(mgr,
 		resource.ManagedKind(v1alpha1.Space_GroupVersionKind),
 		options...)
@@ -67,8 +93,9 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 // A connector is expected to produce an ExternalClient when its Connect method
 // is called.
 type connector struct {
-	kube  k8s.Client
-	usage *resource.ProviderConfigUsageTracker
+	kube     k8s.Client
+	usage    *resource.ProviderConfigUsageTracker
+	recorder event.Recorder
 }
 
 // Connect typically produces an ExternalClient by:
@@ -93,9 +120,13 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	spaceClient, featureClient, _ := space.NewClient(cf)
 
 	return &external{
-		kube:    c.kube,
-		client:  spaceClient,
-		feature: featureClient,
+		kube:           c.kube,
+		client:         spaceClient,
+		feature:        featureClient,
+		securityGroups: space.NewSecurityGroupClient(cf),
+		contents:       space.NewContentsClient(cf),
+		usage:          space.NewUsageClient(cf),
+		recorder:       c.recorder,
 	}, nil
 
 }
@@ -109,9 +140,13 @@ func (c *external) Disconnect(ctx context.Context) error {
 // An ExternalClient observes, then either creates, updates, or deletes an
 // external resource to ensure it reflects the managed resource's desired state.
 type external struct {
-	kube    k8s.Client
-	client  space.Space
-	feature space.Feature
+	kube           k8s.Client
+	client         space.Space
+	feature        space.Feature
+	securityGroups space.SecurityGroup
+	contents       space.Contents
+	usage          space.Usage
+	recorder       event.Recorder
 }
 
 // Observe generates observation for a space
@@ -124,11 +159,11 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	// Check if the external resource exists
 	guid := meta.GetExternalName(cr)
 
-	s, err := space.GetByIDOrSpec(ctx, c.client, guid, cr.Spec.ForProvider)
+	s, err := space.GetByIDOrSpec(ctx, c.client, guid, cr.Spec.ForProvider, meta.GetExternalCreateSucceeded(cr))
 
 	// not found or error
 	if err != nil {
-		if clients.ErrorIsNotFound(err) {
+		if clients.IsNotFound(err) {
 			return managed.ExternalObservation{ResourceExists: false}, nil
 		}
 		return managed.ExternalObservation{ResourceExists: false}, errors.Wrap(err, errGet)
@@ -138,27 +173,56 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{ResourceExists: false}, nil
 	}
 
-	// ssh
-	ssh, err := c.feature.IsSSHEnabled(ctx, s.GUID)
+	features, err := c.feature.ListFeatures(ctx, s.GUID)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGet)
+	}
+
+	// the isolation segment is only observed if it is managed
+	segment := ""
+	if cr.Spec.ForProvider.IsolationSegment != nil {
+		segment, err = c.client.GetAssignedIsolationSegment(ctx, s.GUID)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errGet)
+		}
+	}
+
+	// security groups are only observed if they are managed
+	running, staging, err := space.GetSecurityGroups(ctx, c.securityGroups, s.GUID, cr.Spec.ForProvider)
 	if err != nil {
-		// new error message cannot get space feature
 		return managed.ExternalObservation{}, errors.Wrap(err, errGet)
 	}
 
-	resourceLateInitialized := space.LateInitialize(cr, s, ssh)
+	// usage is purely observational, so the space is observed without it if
+	// the CF API does not return it
+	usage, err := c.usage.GetUsageSummary(ctx, s.GUID)
+	if err != nil {
+		usage = nil
+	}
+
+	resourceLateInitialized := space.LateInitialize(cr, s, features)
 	// update external name, if needed
 	if guid != s.GUID {
 		meta.SetExternalName(cr, s.GUID)
 		resourceLateInitialized = true // force update
 	}
 
-	cr.Status.AtProvider = space.GenerateObservation(s, ssh)
+	managedMetadata := clients.ObserveManagedMetadata(cr.Status.AtProvider.ManagedMetadata, cr.Spec.ForProvider.Labels, cr.Spec.ForProvider.Annotations, s.Metadata)
+	cr.Status.AtProvider = space.GenerateObservation(s, features)
+	cr.Status.AtProvider.ManagedMetadata = managedMetadata
+	cr.Status.AtProvider.IsolationSegment = space.IsolationSegmentObservation(segment)
+	cr.Status.AtProvider.RunningSecurityGroups = running
+	cr.Status.AtProvider.StagingSecurityGroups = staging
+	cr.Status.AtProvider.Usage = usage
 	cr.SetConditions(xpv1.Available())
 
 	return managed.ExternalObservation{
-		ResourceExists:          true,
-		ResourceUpToDate:        space.IsUpToDate(cr.Spec.ForProvider, s, ssh),
+		ResourceExists: true,
+		ResourceUpToDate: space.IsUpToDate(cr.Spec.ForProvider, managedMetadata, s, features) &&
+			space.IsIsolationSegmentUpToDate(cr.Spec.ForProvider, segment) &&
+			space.IsSecurityGroupsUpToDate(cr.Spec.ForProvider, cr.Status.AtProvider),
 		ResourceLateInitialized: resourceLateInitialized,
+		ConnectionDetails:       space.GetConnectionDetails(s),
 	}, nil
 }
 
@@ -172,24 +236,34 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	cr.SetConditions(xpv1.Creating())
 
 	s, err := c.client.Create(ctx, space.GenerateCreate(cr.Spec.ForProvider))
+	if space.IsNameTakenError(err) {
+		// The space was created before, e.g. by a create whose external name was not persisted.
+		// Adopt it, the next reconcile observes and updates it.
+		return managed.ExternalCreation{}, c.adopt(ctx, cr)
+	}
 	if err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, errCreate)
 	}
 
 	meta.SetExternalName(cr, s.GUID)
 
-	if err := c.kube.Update(ctx, cr); err != nil {
-		return managed.ExternalCreation{}, errors.Wrap(err, errUpdate)
+	// enable features, the ones to disable are disabled by the next update
+	for _, name := range space.FeatureChanges(space.DesiredFeatures(cr.Spec.ForProvider), nil) {
+		if err := c.toggleFeature(ctx, cr, s.GUID, name, true); err != nil {
+			return managed.ExternalCreation{}, err
+		}
 	}
 
-	// enable SSH if allowed
-	if cr.Spec.ForProvider.AllowSSH {
-		err = c.feature.EnableSSH(ctx, s.GUID, true)
-		if err != nil {
-			return managed.ExternalCreation{}, errors.Wrap(err, errEnableSSH)
+	if segment := cr.Spec.ForProvider.IsolationSegment; segment != nil && *segment != "" {
+		if err := c.client.AssignIsolationSegment(ctx, s.GUID, *segment); err != nil {
+			return managed.ExternalCreation{}, errors.Wrap(err, errIsolationSegment)
 		}
 	}
 
+	if err := space.BindSecurityGroups(ctx, c.securityGroups, s.GUID, cr.Spec.ForProvider); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errSecurityGroups)
+	}
+
 	return managed.ExternalCreation{
 		// Optionally return any details that may be required to connect to the
 		// external resource. These will be stored as the connection secret.
@@ -197,6 +271,18 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	}, nil
 }
 
+// adopt sets the external name of the space to the GUID of the existing space
+// with its name in its org. The managed reconciler persists it.
+func (c *external) adopt(ctx context.Context, cr *v1alpha1.Space) error {
+	s, err := c.client.Single(ctx, space.GenerateListOption(cr.Spec.ForProvider))
+	if err != nil {
+		return errors.Wrap(err, errAdopt)
+	}
+	meta.SetExternalName(cr, s.GUID)
+	c.recorder.Event(cr, event.Normal(reasonAdopted, "Adopted existing space "+s.GUID))
+	return nil
+}
+
 // Update updates a space
 func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
 	cr, ok := mg.(*v1alpha1.Space)
@@ -209,17 +295,33 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, errors.New(errUpdate)
 	}
 
-	// reconcile SSH
-	if cr.Spec.ForProvider.AllowSSH != cr.Status.AtProvider.AllowSSH {
-		err := c.feature.EnableSSH(ctx, cr.Status.AtProvider.ID, cr.Spec.ForProvider.AllowSSH)
+	// enable or disable the features that changed
+	desired := space.DesiredFeatures(cr.Spec.ForProvider)
+	for _, name := range space.FeatureChanges(desired, cr.Status.AtProvider.Features) {
+		if err := c.toggleFeature(ctx, cr, cr.Status.AtProvider.ID, name, desired[name]); err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+	}
+
+	// (un)assign isolation segment
+	if !space.IsIsolationSegmentUpToDate(cr.Spec.ForProvider, ptr.Deref(cr.Status.AtProvider.IsolationSegment, "")) {
+		err := c.client.AssignIsolationSegment(ctx, cr.Status.AtProvider.ID, *cr.Spec.ForProvider.IsolationSegment)
 		if err != nil {
-			return managed.ExternalUpdate{}, errors.Wrap(err, errEnableSSH)
+			return managed.ExternalUpdate{}, errors.Wrap(err, errIsolationSegment)
+		}
+	}
+
+	// (un)bind security groups
+	if !space.IsSecurityGroupsUpToDate(cr.Spec.ForProvider, cr.Status.AtProvider) {
+		if err := space.BindSecurityGroups(ctx, c.securityGroups, cr.Status.AtProvider.ID, cr.Spec.ForProvider); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errSecurityGroups)
 		}
 	}
 
-	// rename
-	if cr.Spec.ForProvider.Name != cr.Status.AtProvider.Name {
-		_, err := c.client.Update(ctx, cr.Status.AtProvider.ID, space.GenerateUpdate(cr.Spec.ForProvider))
+	// rename or update metadata
+	observed := &cfresource.Metadata{Labels: cr.Status.AtProvider.Labels, Annotations: cr.Status.AtProvider.Annotations}
+	if cr.Spec.ForProvider.Name != cr.Status.AtProvider.Name || !clients.MetadataEqual(cr.Spec.ForProvider.Labels, cr.Spec.ForProvider.Annotations, cr.Status.AtProvider.ManagedMetadata, observed) {
+		_, err := c.client.Update(ctx, cr.Status.AtProvider.ID, space.GenerateUpdate(cr.Spec.ForProvider, cr.Status.AtProvider.ManagedMetadata, observed))
 		if err != nil {
 			return managed.ExternalUpdate{}, errors.Wrap(err, errUpdate)
 		}
@@ -228,6 +330,22 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 	return managed.ExternalUpdate{}, nil
 }
 
+// toggleFeature enables or disables a feature of a space and records an event.
+func (c *external) toggleFeature(ctx context.Context, cr *v1alpha1.Space, guid, name string, enable bool) error {
+	if err := c.feature.EnableFeature(ctx, guid, name, enable); err != nil {
+		if enable {
+			return errors.Wrapf(err, errEnableFeature, name)
+		}
+		return errors.Wrapf(err, errDisableFeature, name)
+	}
+	if enable {
+		c.recorder.Event(cr, event.Normal(reasonFeatureEnabled, "Enabled feature "+name+" for space"))
+	} else {
+		c.recorder.Event(cr, event.Normal(reasonFeatureDisabled, "Disabled feature "+name+" for space"))
+	}
+	return nil
+}
+
 // Delete deletes a space
 func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
 	cr, ok := mg.(*v1alpha1.Space)
@@ -241,7 +359,30 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalDelete{}, errors.New(errDelete)
 	}
 
-	_, err := c.client.Delete(ctx, cr.Status.AtProvider.ID)
+	if err := space.UnbindSecurityGroups(ctx, c.securityGroups, cr.Status.AtProvider.ID, cr.Spec.ForProvider); err != nil {
+		return managed.ExternalDelete{}, errors.Wrap(err, errSecurityGroups)
+	}
+
+	// CF refuses to delete a space that is not empty, so its contents are
+	// either deleted first or reported as blocking the deletion.
+	contents, err := c.contents.ListContents(ctx, cr.Status.AtProvider.ID)
+	if err != nil {
+		return managed.ExternalDelete{}, errors.Wrap(err, errListContents)
+	}
+	if len(contents) > 0 {
+		if !cr.Spec.ForProvider.ForceDelete {
+			cr.SetConditions(space.DeletionBlocked(contents))
+			return managed.ExternalDelete{}, errors.New(errNotEmpty)
+		}
+		c.recorder.Event(cr, event.Normal(reasonDeletingContents, "Deleting "+space.DescribeContents(contents)))
+		if err := space.DeleteContents(ctx, c.contents, contents); err != nil {
+			return managed.ExternalDelete{}, errors.Wrap(err, errDeleteContents)
+		}
+		// The space is deleted once its contents are gone.
+		return managed.ExternalDelete{}, nil
+	}
+
+	_, err = c.client.Delete(ctx, cr.Status.AtProvider.ID)
 	if err != nil {
 		return managed.ExternalDelete{}, errors.Wrap(err, errDelete)
 	}
@@ -271,5 +412,17 @@ func (c *orgInitializer) Initialize(ctx context.Context, mg resource.Managed) er
 		return org.ResolveByName(ctx, clients.ClientFnBuilder(ctx, c.kube), mg)
 	}
 
-	return nil
+	return clients.ValidateGUID("org", cr.Spec.ForProvider.Org)
+}
+
+type isolationSegmentInitializer initializer
+
+// Initialize resolves the isolation segment of a Space by name
+func (c *isolationSegmentInitializer) Initialize(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.Space)
+	if !ok {
+		return errors.New(errNotSpace)
+	}
+
+	return space.ResolveIsolationSegmentByName(ctx, clients.ClientFnBuilder(ctx, c.kube), cr)
 }
@@ -3,19 +3,27 @@ package space
 import (
 	"context"
 	"testing"
+	"time"
 
+	cfresource "github.com/cloudfoundry/go-cfclient/v3/resource"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
 	k8s "sigs.k8s.io/controller-runtime/pkg/client"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/fake"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/space"
 )
 
 var (
@@ -23,6 +31,8 @@ var (
 	name    = "my-space"
 	guid    = "2d8b0d04-d537-4e4e-8c6f-f09ca0e7f56f"
 	orgGuid = "3d8b0d04-d537-4e4e-8c6f-f09ca0e7f56f"
+	segGuid = "4d8b0d04-d537-4e4e-8c6f-f09ca0e7f56f"
+	sgGuid  = "5d8b0d04-d537-4e4e-8c6f-f09ca0e7f56f"
 )
 
 type modifier func(*v1alpha1.Space)
@@ -51,6 +61,73 @@ func withAllowSSH(allowSSH bool) modifier {
 	}
 }
 
+func withFeatures(features map[string]bool) modifier {
+	return func(r *v1alpha1.Space) {
+		r.Spec.ForProvider.Features = features
+	}
+}
+
+func withObservedFeatures(features map[string]bool) modifier {
+	return func(r *v1alpha1.Space) {
+		r.Status.AtProvider.Features = features
+	}
+}
+
+func withIsolationSegment(segment string) modifier {
+	return func(r *v1alpha1.Space) {
+		r.Spec.ForProvider.IsolationSegment = &segment
+	}
+}
+
+func withObservedIsolationSegment(segment string) modifier {
+	return func(r *v1alpha1.Space) {
+		r.Status.AtProvider.IsolationSegment = &segment
+	}
+}
+
+func withLabels(labels map[string]*string) modifier {
+	return func(r *v1alpha1.Space) {
+		r.Spec.ForProvider.Labels = labels
+	}
+}
+
+func withObservedName(name string) modifier {
+	return func(r *v1alpha1.Space) {
+		r.Status.AtProvider.Name = name
+	}
+}
+
+func withObservedAllowSSH(allowSSH bool) modifier {
+	return func(r *v1alpha1.Space) {
+		r.Status.AtProvider.AllowSSH = allowSSH
+		r.Status.AtProvider.Features = map[string]bool{space.FeatureSSH: allowSSH}
+	}
+}
+
+func withObservedLabels(labels map[string]*string) modifier {
+	return func(r *v1alpha1.Space) {
+		r.Status.AtProvider.Labels = labels
+	}
+}
+
+func withManagedLabels(keys ...string) modifier {
+	return func(r *v1alpha1.Space) {
+		r.Status.AtProvider.ManagedLabels = keys
+	}
+}
+
+func withRunningSecurityGroups(names ...string) modifier {
+	return func(r *v1alpha1.Space) {
+		r.Spec.ForProvider.RunningSecurityGroups = names
+	}
+}
+
+func withExternalCreateSucceeded(t time.Time) modifier {
+	return func(r *v1alpha1.Space) {
+		meta.SetExternalCreateSucceeded(r, t)
+	}
+}
+
 func withOrg(org string) modifier {
 	return func(r *v1alpha1.Space) {
 		r.Spec.ForProvider.Org = &org
@@ -75,12 +152,59 @@ func fakeSpace(m ...modifier) *v1alpha1.Space {
 	return r
 }
 
+// connectionDetails returns the connection details of an observed space.
+func connectionDetails() managed.ConnectionDetails {
+	return managed.ConnectionDetails{
+		space.SpaceGUIDKey: []byte(guid),
+		space.OrgGUIDKey:   []byte(orgGuid),
+	}
+}
+
 type MockSpaceFeature struct {
 	*fake.MockSpace
 	*fake.MockFeature
 }
 
+// mockContents mocks space.Contents
+type mockContents struct {
+	mock.Mock
+}
+
+func (m *mockContents) ListContents(ctx context.Context, spaceGUID string) ([]space.Content, error) {
+	args := m.Called(spaceGUID)
+	return args.Get(0).([]space.Content), args.Error(1)
+}
+
+func (m *mockContents) DeleteContent(ctx context.Context, content space.Content) error {
+	args := m.Called(content)
+	return args.Error(0)
+}
+
+// mockUsage mocks space.Usage
+type mockUsage struct {
+	mock.Mock
+}
+
+func (m *mockUsage) GetUsageSummary(ctx context.Context, spaceGUID string) (*v1alpha1.SpaceUsage, error) {
+	args := m.Called(spaceGUID)
+	return args.Get(0).(*v1alpha1.SpaceUsage), args.Error(1)
+}
+
+func noUsage() *mockUsage {
+	m := &mockUsage{}
+	m.On("GetUsageSummary", mock.Anything).Return((*v1alpha1.SpaceUsage)(nil), nil)
+	return m
+}
+
+func withForceDelete() modifier {
+	return func(r *v1alpha1.Space) {
+		r.Spec.ForProvider.ForceDelete = true
+	}
+}
+
 func TestObserve(t *testing.T) {
+	created := time.Now()
+
 	type service func() *MockSpaceFeature
 	type args struct {
 		mg resource.Managed
@@ -93,10 +217,11 @@ func TestObserve(t *testing.T) {
 	}
 
 	cases := map[string]struct {
-		args    args
-		want    want
-		service service
-		kube    k8s.Client
+		args           args
+		want           want
+		service        service
+		securityGroups func() *fake.MockSecurityGroup
+		kube           k8s.Client
 	}{
 		"Nil": {
 			args: args{
@@ -200,7 +325,7 @@ func TestObserve(t *testing.T) {
 				mg: fakeSpace(withName("existing-space"),
 					withExternalName(guid), withAllowSSH(false), withOrg(orgGuid),
 				),
-				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true},
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true, ConnectionDetails: connectionDetails()},
 				err: nil,
 			},
 			service: func() *MockSpaceFeature {
@@ -211,8 +336,8 @@ func TestObserve(t *testing.T) {
 					&fake.NewSpace().SetName("existing-space").SetGUID(guid).SetRelationships(orgGuid).Space,
 					nil,
 				)
-				f.On("IsSSHEnabled").Return(
-					false,
+				f.On("ListFeatures").Return(
+					map[string]bool{space.FeatureSSH: false},
 					nil,
 				)
 
@@ -220,6 +345,200 @@ func TestObserve(t *testing.T) {
 			},
 			kube: &test.MockClient{},
 		},
+		"IsolationSegmentDrift": {
+			args: args{
+				mg: fakeSpace(withExternalName(guid), withName(name), withOrg(orgGuid), withIsolationSegment(segGuid)),
+			},
+			want: want{
+				mg:  fakeSpace(withExternalName(guid), withName(name), withOrg(orgGuid), withIsolationSegment(segGuid)),
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false, ResourceLateInitialized: false, ConnectionDetails: connectionDetails()},
+				err: nil,
+			},
+			service: func() *MockSpaceFeature {
+				m := &fake.MockSpace{}
+				f := &fake.MockFeature{}
+
+				m.On("Get", guid).Return(
+					&fake.NewSpace().SetName(name).SetGUID(guid).SetRelationships(orgGuid).Space,
+					nil,
+				)
+				// no isolation segment assigned yet
+				m.On("GetAssignedIsolationSegment", guid).Return("", nil)
+				f.On("ListFeatures").Return(
+					map[string]bool{space.FeatureSSH: false},
+					nil,
+				)
+
+				return &MockSpaceFeature{m, f}
+			},
+		},
+		"IsolationSegmentUpToDate": {
+			args: args{
+				mg: fakeSpace(withExternalName(guid), withName(name), withOrg(orgGuid), withIsolationSegment(segGuid)),
+			},
+			want: want{
+				mg:  fakeSpace(withExternalName(guid), withName(name), withOrg(orgGuid), withIsolationSegment(segGuid)),
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: false, ConnectionDetails: connectionDetails()},
+				err: nil,
+			},
+			service: func() *MockSpaceFeature {
+				m := &fake.MockSpace{}
+				f := &fake.MockFeature{}
+
+				m.On("Get", guid).Return(
+					&fake.NewSpace().SetName(name).SetGUID(guid).SetRelationships(orgGuid).Space,
+					nil,
+				)
+				m.On("GetAssignedIsolationSegment", guid).Return(segGuid, nil)
+				f.On("ListFeatures").Return(
+					map[string]bool{space.FeatureSSH: false},
+					nil,
+				)
+
+				return &MockSpaceFeature{m, f}
+			},
+		},
+		"MetadataDrift": {
+			args: args{
+				mg: fakeSpace(withExternalName(guid), withName(name), withOrg(orgGuid), withLabels(map[string]*string{"env": ptr.To("prod")})),
+			},
+			want: want{
+				mg:  fakeSpace(withExternalName(guid), withName(name), withOrg(orgGuid), withLabels(map[string]*string{"env": ptr.To("prod")})),
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false, ResourceLateInitialized: false, ConnectionDetails: connectionDetails()},
+				err: nil,
+			},
+			service: func() *MockSpaceFeature {
+				m := &fake.MockSpace{}
+				f := &fake.MockFeature{}
+
+				m.On("Get", guid).Return(
+					&fake.NewSpace().SetName(name).SetGUID(guid).SetRelationships(orgGuid).SetLabels(map[string]*string{"env": ptr.To("dev")}).Space,
+					nil,
+				)
+				f.On("ListFeatures").Return(
+					map[string]bool{space.FeatureSSH: false},
+					nil,
+				)
+
+				return &MockSpaceFeature{m, f}
+			},
+		},
+		"SecurityGroupDrift": {
+			args: args{
+				mg: fakeSpace(withExternalName(guid), withName(name), withOrg(orgGuid), withRunningSecurityGroups("dns", "db")),
+			},
+			want: want{
+				mg:  fakeSpace(withExternalName(guid), withName(name), withOrg(orgGuid), withRunningSecurityGroups("dns", "db")),
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false, ResourceLateInitialized: false, ConnectionDetails: connectionDetails()},
+				err: nil,
+			},
+			service: func() *MockSpaceFeature {
+				m := &fake.MockSpace{}
+				f := &fake.MockFeature{}
+
+				m.On("Get", guid).Return(
+					&fake.NewSpace().SetName(name).SetGUID(guid).SetRelationships(orgGuid).Space,
+					nil,
+				)
+				f.On("ListFeatures").Return(
+					map[string]bool{space.FeatureSSH: false},
+					nil,
+				)
+
+				return &MockSpaceFeature{m, f}
+			},
+			securityGroups: func() *fake.MockSecurityGroup {
+				m := &fake.MockSecurityGroup{}
+				// only dns is bound yet
+				m.On("ListAll", []string(nil), []string{guid}, []string(nil)).Return(
+					[]*cfresource.SecurityGroup{{Name: "dns"}},
+					nil,
+				)
+				return m
+			},
+		},
+		"SSHDrift": {
+			args: args{
+				mg: fakeSpace(withExternalName(guid), withName(name), withAllowSSH(false), withOrg(orgGuid)),
+			},
+			want: want{
+				mg:  fakeSpace(withExternalName(guid), withName(name), withAllowSSH(false), withOrg(orgGuid)),
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false, ConnectionDetails: connectionDetails()},
+				err: nil,
+			},
+			service: func() *MockSpaceFeature {
+				m := &fake.MockSpace{}
+				f := &fake.MockFeature{}
+
+				m.On("Get", guid).Return(
+					&fake.NewSpace().SetName(name).SetGUID(guid).SetRelationships(orgGuid).Space,
+					nil,
+				)
+				f.On("ListFeatures").Return(
+					map[string]bool{space.FeatureSSH: true},
+					nil,
+				)
+
+				return &MockSpaceFeature{m, f}
+			},
+		},
+		"FeatureDrift": {
+			args: args{
+				mg: fakeSpace(withExternalName(guid), withName(name), withFeatures(map[string]bool{"example": true}), withOrg(orgGuid)),
+			},
+			want: want{
+				mg:  fakeSpace(withExternalName(guid), withName(name), withFeatures(map[string]bool{"example": true}), withOrg(orgGuid)),
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false, ConnectionDetails: connectionDetails()},
+				err: nil,
+			},
+			service: func() *MockSpaceFeature {
+				m := &fake.MockSpace{}
+				f := &fake.MockFeature{}
+
+				m.On("Get", guid).Return(
+					&fake.NewSpace().SetName(name).SetGUID(guid).SetRelationships(orgGuid).Space,
+					nil,
+				)
+				f.On("ListFeatures").Return(
+					map[string]bool{space.FeatureSSH: false, "example": false},
+					nil,
+				)
+
+				return &MockSpaceFeature{m, f}
+			},
+		},
+		"AdoptAfterStaleRead": {
+			args: args{
+				mg: fakeSpace(withName(name), withOrg(orgGuid), withExternalCreateSucceeded(created)),
+			},
+			want: want{
+				mg: fakeSpace(withName(name),
+					withExternalName(guid), withAllowSSH(false), withOrg(orgGuid), withExternalCreateSucceeded(created),
+				),
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true, ConnectionDetails: connectionDetails()},
+				err: nil,
+			},
+			service: func() *MockSpaceFeature {
+				m := &fake.MockSpace{}
+				f := &fake.MockFeature{}
+
+				// the first listing after a create does not contain the space yet
+				m.On("Single").Return(
+					fake.SpaceNil,
+					fake.ErrNoResultReturned,
+				).Once()
+				m.On("Single").Return(
+					&fake.NewSpace().SetName(name).SetGUID(guid).SetRelationships(orgGuid).Space,
+					nil,
+				)
+				f.On("ListFeatures").Return(
+					map[string]bool{space.FeatureSSH: false},
+					nil,
+				)
+
+				return &MockSpaceFeature{m, f}
+			},
+		},
 		"Successful": {
 			args: args{
 				mg: fakeSpace(
@@ -233,7 +552,7 @@ func TestObserve(t *testing.T) {
 					withAllowSSH(false),
 					withOrg(orgGuid),
 				),
-				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: false},
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: false, ConnectionDetails: connectionDetails()},
 				err: nil,
 			},
 			service: func() *MockSpaceFeature {
@@ -245,8 +564,8 @@ func TestObserve(t *testing.T) {
 					nil,
 				)
 
-				f.On("IsSSHEnabled").Return(
-					false,
+				f.On("ListFeatures").Return(
+					map[string]bool{space.FeatureSSH: false},
 					nil,
 				)
 
@@ -258,12 +577,18 @@ func TestObserve(t *testing.T) {
 	for n, tc := range cases {
 		t.Run(n, func(t *testing.T) {
 			c := &external{
+				recorder: event.NewNopRecorder(),
 				kube: &test.MockClient{
 					MockUpdate:       test.NewMockUpdateFn(nil),
 					MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
 				},
-				feature: tc.service().MockFeature,
-				client:  tc.service().MockSpace,
+				feature:        tc.service().MockFeature,
+				client:         tc.service().MockSpace,
+				securityGroups: &fake.MockSecurityGroup{},
+				usage:          noUsage(),
+			}
+			if tc.securityGroups != nil {
+				c.securityGroups = tc.securityGroups()
 			}
 
 			obs, err := c.Observe(context.Background(), tc.args.mg)
@@ -296,6 +621,63 @@ func TestObserve(t *testing.T) {
 	}
 }
 
+func TestObserveUsage(t *testing.T) {
+	type want struct {
+		usage *v1alpha1.SpaceUsage
+		err   error
+	}
+
+	cases := map[string]struct {
+		usage func() *mockUsage
+		want  want
+	}{
+		"Usage": {
+			usage: func() *mockUsage {
+				m := &mockUsage{}
+				m.On("GetUsageSummary", guid).Return(&v1alpha1.SpaceUsage{MemoryInMB: ptr.To(2048), Routes: ptr.To(3), StartedInstances: ptr.To(4)}, nil)
+				return m
+			},
+			want: want{usage: &v1alpha1.SpaceUsage{MemoryInMB: ptr.To(2048), Routes: ptr.To(3), StartedInstances: ptr.To(4)}},
+		},
+		"NoUsage": {
+			usage: noUsage,
+		},
+		"UsageNotAvailable": {
+			// the space is observed without usage
+			usage: func() *mockUsage {
+				m := &mockUsage{}
+				m.On("GetUsageSummary", guid).Return((*v1alpha1.SpaceUsage)(nil), errBoom)
+				return m
+			},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			m := &fake.MockSpace{}
+			m.On("Get", guid).Return(&fake.NewSpace().SetName(name).SetGUID(guid).SetRelationships(orgGuid).Space, nil)
+			f := &fake.MockFeature{}
+			f.On("ListFeatures").Return(map[string]bool{space.FeatureSSH: false}, nil)
+
+			c := &external{
+				recorder:       event.NewNopRecorder(),
+				client:         m,
+				feature:        f,
+				securityGroups: &fake.MockSecurityGroup{},
+				usage:          tc.usage(),
+			}
+			cr := fakeSpace(withExternalName(guid), withName(name), withOrg(orgGuid))
+			_, err := c.Observe(context.Background(), cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Observe(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.usage, cr.Status.AtProvider.Usage); diff != "" {
+				t.Errorf("Observe(...): -want usage, +got usage:\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestCreate(t *testing.T) {
 	type service func() *MockSpaceFeature
 	type args struct {
@@ -330,7 +712,7 @@ func TestCreate(t *testing.T) {
 					&fake.NewSpace().SetName(name).SetGUID(guid).Space,
 					nil,
 				)
-				f.On("EnableSSH").Return(
+				f.On("EnableFeature", space.FeatureSSH, true).Return(
 					nil,
 				)
 				return &MockSpaceFeature{m, f}
@@ -353,41 +735,116 @@ func TestCreate(t *testing.T) {
 					&fake.NewSpace().SetName(name).SetGUID(guid).Space,
 					errBoom,
 				)
-				f.On("EnableSSH").Return(
+				f.On("EnableFeature", space.FeatureSSH, true).Return(
 					nil,
 				)
 				return &MockSpaceFeature{m, f}
 			},
 		},
-	}
-
-	for n, tc := range cases {
-		t.Run(n, func(t *testing.T) {
-			t.Logf("Testing: %s", t.Name())
-			c := &external{
-				kube: &test.MockClient{
-					MockUpdate:       test.NewMockUpdateFn(nil),
-					MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
-				},
-				feature: tc.service().MockFeature,
-				client:  tc.service().MockSpace,
-			}
-
-			obs, err := c.Create(context.Background(), tc.args.mg)
-
-			if tc.want.err != nil && err != nil {
-				// the case where our mock server returns error.
-				if diff := cmp.Diff(tc.want.err.Error(), err.Error()); diff != "" {
-					t.Errorf("Observe(...): want error string != got error string:\n%s", diff)
-				}
-			} else {
-				if diff := cmp.Diff(tc.want.err, err); diff != "" {
+		"NameTakenAdopts": {
+			args: args{
+				mg: fakeSpace(),
+			},
+			want: want{
+				mg:  fakeSpace(withExternalName(guid)),
+				obs: managed.ExternalCreation{},
+				err: nil,
+			},
+			service: func() *MockSpaceFeature {
+				m := &fake.MockSpace{}
+				f := &fake.MockFeature{}
+				m.On("Create").Return(fake.SpaceNil, cfresource.NewSpaceNameTakenError())
+				m.On("Single").Return(
+					&fake.NewSpace().SetName(name).SetGUID(guid).Space,
+					nil,
+				)
+				return &MockSpaceFeature{m, f}
+			},
+		},
+		"NameNotUniqueAdopts": {
+			args: args{
+				mg: fakeSpace(),
+			},
+			want: want{
+				mg:  fakeSpace(withExternalName(guid)),
+				obs: managed.ExternalCreation{},
+				err: nil,
+			},
+			service: func() *MockSpaceFeature {
+				m := &fake.MockSpace{}
+				f := &fake.MockFeature{}
+				m.On("Create").Return(fake.SpaceNil, cfresource.CloudFoundryError{Code: 10008, Title: "CF-UnprocessableEntity", Detail: "Name must be unique per organization"})
+				m.On("Single").Return(
+					&fake.NewSpace().SetName(name).SetGUID(guid).Space,
+					nil,
+				)
+				return &MockSpaceFeature{m, f}
+			},
+		},
+		"OtherUnprocessableEntity": {
+			args: args{
+				mg: fakeSpace(),
+			},
+			want: want{
+				mg:  fakeSpace(),
+				obs: managed.ExternalCreation{},
+				err: errors.Wrap(cfresource.CloudFoundryError{Code: 10008, Title: "CF-UnprocessableEntity", Detail: "Name is too long"}, errCreate),
+			},
+			service: func() *MockSpaceFeature {
+				m := &fake.MockSpace{}
+				f := &fake.MockFeature{}
+				m.On("Create").Return(fake.SpaceNil, cfresource.CloudFoundryError{Code: 10008, Title: "CF-UnprocessableEntity", Detail: "Name is too long"})
+				return &MockSpaceFeature{m, f}
+			},
+		},
+		"NameTakenAdoptFails": {
+			args: args{
+				mg: fakeSpace(),
+			},
+			want: want{
+				mg:  fakeSpace(),
+				obs: managed.ExternalCreation{},
+				err: errors.Wrap(errBoom, errAdopt),
+			},
+			service: func() *MockSpaceFeature {
+				m := &fake.MockSpace{}
+				f := &fake.MockFeature{}
+				m.On("Create").Return(fake.SpaceNil, cfresource.NewSpaceNameTakenError())
+				m.On("Single").Return(fake.SpaceNil, errBoom)
+				return &MockSpaceFeature{m, f}
+			},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			t.Logf("Testing: %s", t.Name())
+			c := &external{
+				recorder: event.NewNopRecorder(),
+				// The managed reconciler persists the external name.
+				kube:    &test.MockClient{},
+				feature: tc.service().MockFeature,
+				client:  tc.service().MockSpace,
+			}
+
+			obs, err := c.Create(context.Background(), tc.args.mg)
+
+			if tc.want.err != nil && err != nil {
+				// the case where our mock server returns error.
+				if diff := cmp.Diff(tc.want.err.Error(), err.Error()); diff != "" {
+					t.Errorf("Observe(...): want error string != got error string:\n%s", diff)
+				}
+			} else {
+				if diff := cmp.Diff(tc.want.err, err); diff != "" {
 					t.Errorf("Observe(...): want error != got error:\n%s", diff)
 				}
 			}
 			if diff := cmp.Diff(tc.want.obs, obs); diff != "" {
 				t.Errorf("Observe(...): -want, +got:\n%s", diff)
 			}
+			if diff := cmp.Diff(meta.GetExternalName(tc.want.mg), meta.GetExternalName(tc.args.mg)); diff != "" {
+				t.Errorf("Create(...): -want external name, +got:\n%s", diff)
+			}
 		})
 	}
 }
@@ -429,6 +886,57 @@ func TestUpdate(t *testing.T) {
 				return &MockSpaceFeature{m, f}
 			},
 		},
+		"UpdateMetadata": {
+			args: args{
+				mg: fakeSpace(withExternalName(guid), withID(guid), withName(name), withObservedName(name), withObservedLabels(map[string]*string{"team": ptr.To("platform")}), withManagedLabels("team")),
+			},
+			want: want{
+				mg:  fakeSpace(withExternalName(guid), withID(guid), withName(name), withObservedName(name), withObservedLabels(map[string]*string{"team": ptr.To("platform")}), withManagedLabels("team")),
+				obs: managed.ExternalUpdate{},
+				err: nil,
+			},
+			service: func() *MockSpaceFeature {
+				m := &fake.MockSpace{}
+				f := &fake.MockFeature{}
+				m.On("Update").Return(
+					&fake.NewSpace().SetName(name).SetGUID(guid).Space,
+					nil,
+				)
+				return &MockSpaceFeature{m, f}
+			},
+		},
+		"LabelsSetByOthers": {
+			args: args{
+				mg: fakeSpace(withExternalName(guid), withID(guid), withName(name), withObservedName(name), withObservedLabels(map[string]*string{"team": ptr.To("platform")})),
+			},
+			want: want{
+				mg:  fakeSpace(withExternalName(guid), withID(guid), withName(name), withObservedName(name), withObservedLabels(map[string]*string{"team": ptr.To("platform")})),
+				obs: managed.ExternalUpdate{},
+				err: nil,
+			},
+			service: func() *MockSpaceFeature {
+				// no Update call expected
+				m := &fake.MockSpace{}
+				f := &fake.MockFeature{}
+				return &MockSpaceFeature{m, f}
+			},
+		},
+		"MetadataUpToDate": {
+			args: args{
+				mg: fakeSpace(withExternalName(guid), withID(guid), withName(name), withObservedName(name), withLabels(map[string]*string{"env": ptr.To("prod")}), withObservedLabels(map[string]*string{"env": ptr.To("prod")})),
+			},
+			want: want{
+				mg:  fakeSpace(withExternalName(guid), withID(guid), withName(name), withObservedName(name), withLabels(map[string]*string{"env": ptr.To("prod")}), withObservedLabels(map[string]*string{"env": ptr.To("prod")})),
+				obs: managed.ExternalUpdate{},
+				err: nil,
+			},
+			service: func() *MockSpaceFeature {
+				// no Update call expected
+				m := &fake.MockSpace{}
+				f := &fake.MockFeature{}
+				return &MockSpaceFeature{m, f}
+			},
+		},
 		"IDNotSet": {
 			args: args{
 				mg: fakeSpace(withExternalName(guid)),
@@ -444,6 +952,62 @@ func TestUpdate(t *testing.T) {
 				return &MockSpaceFeature{m, f}
 			},
 		},
+		"AssignIsolationSegment": {
+			args: args{
+				mg: fakeSpace(withExternalName(guid), withID(guid), withName(name), withIsolationSegment(segGuid)),
+			},
+			want: want{
+				mg:  fakeSpace(withExternalName(guid), withID(guid), withName(name), withIsolationSegment(segGuid)),
+				obs: managed.ExternalUpdate{},
+				err: nil,
+			},
+			service: func() *MockSpaceFeature {
+				m := &fake.MockSpace{}
+				f := &fake.MockFeature{}
+				m.On("AssignIsolationSegment", guid, segGuid).Return(nil)
+				m.On("Update").Return(
+					&fake.NewSpace().SetName(name).SetGUID(guid).Space,
+					nil,
+				)
+				return &MockSpaceFeature{m, f}
+			},
+		},
+		"UnassignIsolationSegment": {
+			args: args{
+				mg: fakeSpace(withExternalName(guid), withID(guid), withName(name), withIsolationSegment(""), withObservedIsolationSegment(segGuid)),
+			},
+			want: want{
+				mg:  fakeSpace(withExternalName(guid), withID(guid), withName(name), withIsolationSegment(""), withObservedIsolationSegment(segGuid)),
+				obs: managed.ExternalUpdate{},
+				err: nil,
+			},
+			service: func() *MockSpaceFeature {
+				m := &fake.MockSpace{}
+				f := &fake.MockFeature{}
+				m.On("AssignIsolationSegment", guid, "").Return(nil)
+				m.On("Update").Return(
+					&fake.NewSpace().SetName(name).SetGUID(guid).Space,
+					nil,
+				)
+				return &MockSpaceFeature{m, f}
+			},
+		},
+		"AssignIsolationSegmentError": {
+			args: args{
+				mg: fakeSpace(withExternalName(guid), withID(guid), withName(name), withIsolationSegment(segGuid)),
+			},
+			want: want{
+				mg:  fakeSpace(withExternalName(guid), withID(guid), withName(name), withIsolationSegment(segGuid)),
+				obs: managed.ExternalUpdate{},
+				err: errors.Wrap(errBoom, errIsolationSegment),
+			},
+			service: func() *MockSpaceFeature {
+				m := &fake.MockSpace{}
+				f := &fake.MockFeature{}
+				m.On("AssignIsolationSegment", guid, segGuid).Return(errBoom)
+				return &MockSpaceFeature{m, f}
+			},
+		},
 		"EnableSSH": {
 			args: args{
 				mg: fakeSpace(withExternalName(guid), withID(guid), withName(name), withAllowSSH(true)),
@@ -456,7 +1020,73 @@ func TestUpdate(t *testing.T) {
 			service: func() *MockSpaceFeature {
 				m := &fake.MockSpace{}
 				f := &fake.MockFeature{}
-				f.On("EnableSSH").Return(
+				f.On("EnableFeature", space.FeatureSSH, true).Return(
+					nil,
+				)
+				m.On("Update").Return(
+					&fake.NewSpace().SetName(name).SetGUID(guid).Space,
+					nil,
+				)
+				return &MockSpaceFeature{m, f}
+			},
+		},
+		"DisableSSH": {
+			args: args{
+				mg: fakeSpace(withExternalName(guid), withID(guid), withName(name), withAllowSSH(false), withObservedAllowSSH(true)),
+			},
+			want: want{
+				mg:  fakeSpace(withExternalName(guid), withID(guid), withName(name), withAllowSSH(false), withObservedAllowSSH(true)),
+				obs: managed.ExternalUpdate{},
+				err: nil,
+			},
+			service: func() *MockSpaceFeature {
+				m := &fake.MockSpace{}
+				f := &fake.MockFeature{}
+				f.On("EnableFeature", space.FeatureSSH, false).Return(
+					nil,
+				)
+				m.On("Update").Return(
+					&fake.NewSpace().SetName(name).SetGUID(guid).Space,
+					nil,
+				)
+				return &MockSpaceFeature{m, f}
+			},
+		},
+		"EnableFeature": {
+			args: args{
+				mg: fakeSpace(withExternalName(guid), withID(guid), withName(name), withFeatures(map[string]bool{"example": true}), withObservedFeatures(map[string]bool{space.FeatureSSH: false, "example": false})),
+			},
+			want: want{
+				mg:  fakeSpace(withExternalName(guid), withID(guid), withName(name), withFeatures(map[string]bool{"example": true}), withObservedFeatures(map[string]bool{space.FeatureSSH: false, "example": false})),
+				obs: managed.ExternalUpdate{},
+				err: nil,
+			},
+			service: func() *MockSpaceFeature {
+				m := &fake.MockSpace{}
+				f := &fake.MockFeature{}
+				f.On("EnableFeature", "example", true).Return(
+					nil,
+				)
+				m.On("Update").Return(
+					&fake.NewSpace().SetName(name).SetGUID(guid).Space,
+					nil,
+				)
+				return &MockSpaceFeature{m, f}
+			},
+		},
+		"DisableFeature": {
+			args: args{
+				mg: fakeSpace(withExternalName(guid), withID(guid), withName(name), withFeatures(map[string]bool{"example": false}), withObservedFeatures(map[string]bool{space.FeatureSSH: false, "example": true})),
+			},
+			want: want{
+				mg:  fakeSpace(withExternalName(guid), withID(guid), withName(name), withFeatures(map[string]bool{"example": false}), withObservedFeatures(map[string]bool{space.FeatureSSH: false, "example": true})),
+				obs: managed.ExternalUpdate{},
+				err: nil,
+			},
+			service: func() *MockSpaceFeature {
+				m := &fake.MockSpace{}
+				f := &fake.MockFeature{}
+				f.On("EnableFeature", "example", false).Return(
 					nil,
 				)
 				m.On("Update").Return(
@@ -466,12 +1096,50 @@ func TestUpdate(t *testing.T) {
 				return &MockSpaceFeature{m, f}
 			},
 		},
+		"FeaturesTakePrecedenceOverAllowSSH": {
+			args: args{
+				mg: fakeSpace(withExternalName(guid), withID(guid), withName(name), withAllowSSH(false), withFeatures(map[string]bool{space.FeatureSSH: true}), withObservedFeatures(map[string]bool{space.FeatureSSH: true})),
+			},
+			want: want{
+				mg:  fakeSpace(withExternalName(guid), withID(guid), withName(name), withAllowSSH(false), withFeatures(map[string]bool{space.FeatureSSH: true}), withObservedFeatures(map[string]bool{space.FeatureSSH: true})),
+				obs: managed.ExternalUpdate{},
+				err: nil,
+			},
+			service: func() *MockSpaceFeature {
+				m := &fake.MockSpace{}
+				f := &fake.MockFeature{}
+				m.On("Update").Return(
+					&fake.NewSpace().SetName(name).SetGUID(guid).Space,
+					nil,
+				)
+				return &MockSpaceFeature{m, f}
+			},
+		},
+		"DisableSSHError": {
+			args: args{
+				mg: fakeSpace(withExternalName(guid), withID(guid), withName(name), withAllowSSH(false), withObservedAllowSSH(true)),
+			},
+			want: want{
+				mg:  fakeSpace(withExternalName(guid), withID(guid), withName(name), withAllowSSH(false), withObservedAllowSSH(true)),
+				obs: managed.ExternalUpdate{},
+				err: errors.Wrapf(errBoom, errDisableFeature, space.FeatureSSH),
+			},
+			service: func() *MockSpaceFeature {
+				m := &fake.MockSpace{}
+				f := &fake.MockFeature{}
+				f.On("EnableFeature", space.FeatureSSH, false).Return(
+					errBoom,
+				)
+				return &MockSpaceFeature{m, f}
+			},
+		},
 	}
 
 	for n, tc := range cases {
 		t.Run(n, func(t *testing.T) {
 			t.Logf("Testing: %s", t.Name())
 			c := &external{
+				recorder: event.NewNopRecorder(),
 				kube: &test.MockClient{
 					MockUpdate:       test.NewMockUpdateFn(nil),
 					MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
@@ -509,15 +1177,25 @@ func TestDelete(t *testing.T) {
 	}
 
 	type want struct {
-		mg  resource.Managed
-		err error
+		mg      resource.Managed
+		err     error
+		blocked *xpv1.Condition
+	}
+
+	blocking := []space.Content{
+		{Kind: space.ContentApp, GUID: "app-guid", Name: "my-app"},
+		{Kind: space.ContentRoute, GUID: "route-guid", Name: "my-app.example.com"},
+		{Kind: space.ContentServiceInstance, GUID: "si-guid", Name: "my-db"},
 	}
+	blocked := space.DeletionBlocked(blocking)
 
 	cases := map[string]struct {
-		args    args
-		want    want
-		service service
-		kube    k8s.Client
+		args           args
+		want           want
+		service        service
+		securityGroups func() *fake.MockSecurityGroup
+		contents       func() *mockContents
+		kube           k8s.Client
 	}{
 		"SuccessfulDelete": {
 			args: args{
@@ -537,6 +1215,143 @@ func TestDelete(t *testing.T) {
 				return &MockSpaceFeature{m, f}
 			},
 		},
+		"UnbindSecurityGroups": {
+			args: args{
+				mg: fakeSpace(withExternalName(guid), withID(guid), withRunningSecurityGroups("dns")),
+			},
+			want: want{
+				mg:  fakeSpace(withExternalName(guid), withID(guid), withRunningSecurityGroups("dns")),
+				err: nil,
+			},
+			service: func() *MockSpaceFeature {
+				m := &fake.MockSpace{}
+				f := &fake.MockFeature{}
+				m.On("Delete").Return(
+					"",
+					nil,
+				)
+				return &MockSpaceFeature{m, f}
+			},
+			securityGroups: func() *fake.MockSecurityGroup {
+				m := &fake.MockSecurityGroup{}
+				m.On("ListAll", []string(nil), []string{guid}, []string(nil)).Return(
+					[]*cfresource.SecurityGroup{{Name: "dns", Resource: cfresource.Resource{GUID: sgGuid}}},
+					nil,
+				)
+				m.On("UnBindRunningSecurityGroup", sgGuid, guid).Return(nil)
+				return m
+			},
+		},
+		"UnbindSecurityGroupsError": {
+			args: args{
+				mg: fakeSpace(withExternalName(guid), withID(guid), withRunningSecurityGroups("dns")),
+			},
+			want: want{
+				mg:  fakeSpace(withExternalName(guid), withID(guid), withRunningSecurityGroups("dns")),
+				err: errors.Wrap(errors.Wrap(errBoom, "cannot list running security groups"), errSecurityGroups),
+			},
+			service: func() *MockSpaceFeature {
+				// the space is not deleted
+				m := &fake.MockSpace{}
+				f := &fake.MockFeature{}
+				return &MockSpaceFeature{m, f}
+			},
+			securityGroups: func() *fake.MockSecurityGroup {
+				m := &fake.MockSecurityGroup{}
+				m.On("ListAll", []string(nil), []string{guid}, []string(nil)).Return(
+					[]*cfresource.SecurityGroup{},
+					errBoom,
+				)
+				return m
+			},
+		},
+		"NotEmpty": {
+			args: args{
+				mg: fakeSpace(withExternalName(guid), withID(guid)),
+			},
+			want: want{
+				mg:      fakeSpace(withExternalName(guid), withID(guid)),
+				err:     errors.New(errNotEmpty),
+				blocked: &blocked,
+			},
+			service: func() *MockSpaceFeature {
+				// the space is not deleted
+				return &MockSpaceFeature{&fake.MockSpace{}, &fake.MockFeature{}}
+			},
+			contents: func() *mockContents {
+				m := &mockContents{}
+				m.On("ListContents", guid).Return(blocking, nil)
+				return m
+			},
+		},
+		"ForceDeleteContents": {
+			args: args{
+				mg: fakeSpace(withExternalName(guid), withID(guid), withForceDelete()),
+			},
+			want: want{
+				mg: fakeSpace(withExternalName(guid), withID(guid), withForceDelete()),
+			},
+			service: func() *MockSpaceFeature {
+				// the space is deleted once it is empty
+				return &MockSpaceFeature{&fake.MockSpace{}, &fake.MockFeature{}}
+			},
+			contents: func() *mockContents {
+				m := &mockContents{}
+				m.On("ListContents", guid).Return(blocking, nil)
+				m.On("DeleteContent", blocking[0]).Return(nil)
+				m.On("DeleteContent", blocking[1]).Return(cfresource.CloudFoundryError{Code: 10010, Title: "CF-ResourceNotFound"})
+				m.On("DeleteContent", blocking[2]).Return(cfresource.CloudFoundryError{Code: 60016, Title: "CF-AsyncServiceInstanceOperationInProgress"})
+				return m
+			},
+		},
+		"ForceDeleteContentsError": {
+			args: args{
+				mg: fakeSpace(withExternalName(guid), withID(guid), withForceDelete()),
+			},
+			want: want{
+				mg:  fakeSpace(withExternalName(guid), withID(guid), withForceDelete()),
+				err: errors.Wrap(errors.Wrap(errBoom, "cannot delete app my-app"), errDeleteContents),
+			},
+			service: func() *MockSpaceFeature {
+				return &MockSpaceFeature{&fake.MockSpace{}, &fake.MockFeature{}}
+			},
+			contents: func() *mockContents {
+				m := &mockContents{}
+				m.On("ListContents", guid).Return(blocking, nil)
+				m.On("DeleteContent", blocking[0]).Return(errBoom)
+				return m
+			},
+		},
+		"ForceDeleteEmptySpace": {
+			args: args{
+				mg: fakeSpace(withExternalName(guid), withID(guid), withForceDelete()),
+			},
+			want: want{
+				mg: fakeSpace(withExternalName(guid), withID(guid), withForceDelete()),
+			},
+			service: func() *MockSpaceFeature {
+				m := &fake.MockSpace{}
+				m.On("Delete").Return("", nil)
+				return &MockSpaceFeature{m, &fake.MockFeature{}}
+			},
+		},
+		"ListContentsError": {
+			args: args{
+				mg: fakeSpace(withExternalName(guid), withID(guid)),
+			},
+			want: want{
+				mg:  fakeSpace(withExternalName(guid), withID(guid)),
+				err: errors.Wrap(errBoom, errListContents),
+			},
+			service: func() *MockSpaceFeature {
+				return &MockSpaceFeature{&fake.MockSpace{}, &fake.MockFeature{}}
+			},
+			contents: func() *mockContents {
+				m := &mockContents{}
+				m.On("ListContents", guid).Return([]space.Content(nil), errBoom)
+				return m
+			},
+		},
 		"IDNotSet": {
 			args: args{
 				mg: fakeSpace(withExternalName(guid)),
@@ -557,13 +1372,24 @@ func TestDelete(t *testing.T) {
 		t.Run(n, func(t *testing.T) {
 			t.Logf("Testing: %s", t.Name())
 			c := &external{
+				recorder: event.NewNopRecorder(),
 				kube: &test.MockClient{
 					MockUpdate:       test.NewMockUpdateFn(nil),
 					MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
 				},
-				feature: tc.service().MockFeature,
-				client:  tc.service().MockSpace,
+				feature:        tc.service().MockFeature,
+				client:         tc.service().MockSpace,
+				securityGroups: &fake.MockSecurityGroup{},
+			}
+			if tc.securityGroups != nil {
+				c.securityGroups = tc.securityGroups()
+			}
+			contents := &mockContents{}
+			contents.On("ListContents", guid).Return([]space.Content(nil), nil)
+			if tc.contents != nil {
+				contents = tc.contents()
 			}
+			c.contents = contents
 
 			_, err := c.Delete(context.Background(), tc.args.mg)
 
@@ -577,6 +1403,15 @@ func TestDelete(t *testing.T) {
 					t.Errorf("Observe(...): want error != got error:\n%s", diff)
 				}
 			}
+			if tc.want.blocked != nil {
+				got := tc.args.mg.(*v1alpha1.Space).GetCondition(space.TypeDeletionBlocked)
+				if diff := cmp.Diff(*tc.want.blocked, got, cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")); diff != "" {
+					t.Errorf("Delete(...): -want condition, +got condition:\n%s", diff)
+				}
+			}
+			if tc.contents != nil {
+				contents.AssertExpectations(t)
+			}
 		})
 	}
 }
@@ -629,6 +1464,16 @@ func TestInitialize(t *testing.T) {
 				err: nil,
 			},
 		},
+		"MalformedOrgGUID": {
+			args: args{
+				mg: fakeSpace(
+					withExternalName(guid), withName(name), withOrg("3d8b0d04-d537-4e4e-8c6f"),
+				),
+			},
+			want: want{
+				err: errors.New(`org "3d8b0d04-d537-4e4e-8c6f" is not a valid GUID`),
+			},
+		},
 	}
 
 	for n, tc := range cases {
@@ -2,11 +2,11 @@ package spacequota
 
 import (
 	"context"
-	"slices"
 
 	cfresource "github.com/cloudfoundry/go-cfclient/v3/resource"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/feature"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
@@ -24,6 +24,7 @@ import (
 	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
 	apisv1beta1 "github.com/SAP/crossplane-provider-cloudfoundry/apis/v1beta1"
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/org"
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/spacequota"
 )
 
@@ -34,9 +35,11 @@ const (
 	errUnexpectedObject  = "managed resource is not a cloudfoundry SpaceQuota"
 	errGet               = "cannot get cloudfoundry SpaceQuota"
 	errResolveReferences = "cannot resolve references"
+	errResolveOrgName    = "cannot resolve the org of cloudfoundry SpaceQuota by name"
+	errUpdateCR          = "cannot update the managed resource"
 	errCreate            = "cannot create cloudfoundry SpaceQuota"
 	errUpdate            = "cannot update cloudfoundry SpaceQuota"
-	errUpdateOrg         = "cannot update org of cloudfoundry SpaceQuota"
+	errUpdateOrg         = "cannot move cloudfoundry SpaceQuota to another org, recreate the SpaceQuota instead"
 	errDelete            = "cannot delete cloudfoundry SpaceQuota"
 )
 
@@ -45,11 +48,13 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 	name := managed.ControllerName(v1alpha1.SpaceQuota_GroupKind)
 	options := []managed.ReconcilerOption{
 
-		managed.WithExternalConnecter(&connector{
+		managed.WithExternalConnecter(clients.WithPause(clients.WithErrorClassification(clients.WithOperationTimeout(mgr.GetClient(), &connector{
 			kube:  mgr.GetClient(),
 			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1beta1.ProviderConfigUsage{}),
-		}),
+		}, clients.DefaultOperationTimeout)))),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithTimeout(clients.MaxOperationTimeout),
+		managed.WithPollIntervalHook(clients.PollIntervalHook(mgr.GetClient())),
 		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 		managed.WithPollInterval(o.PollInterval),
 		managed.WithInitializers(initializer{
@@ -57,6 +62,10 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		}),
 	}
 
+	if o.Features.Enabled(feature.EnableBetaManagementPolicies) {
+		options = append(options, managed.WithManagementPolicies())
+	}
+
 	r := managed.NewReconciler(mgr,
 		resource.ManagedKind(v1alpha1.SpaceQuota_GroupVersionKind),
 		options...)
@@ -120,20 +129,47 @@ func ResolveReferences(ctx context.Context, mg *v1alpha1.SpaceQuota, client k8s.
 	return nil
 }
 
+// resolveOrgName looks up the GUID of the org named orgName in Cloud Foundry.
+func resolveOrgName(ctx context.Context, orgClient org.Client, p *v1alpha1.SpaceQuotaParameters) error {
+	guid, err := org.GetGUID(ctx, orgClient, *p.OrgName)
+	if err != nil {
+		return errors.Wrap(err, errResolveOrgName)
+	}
+	p.Org = guid
+	return nil
+}
+
 // initializer type implements the managed.Initializer interface
 type initializer struct {
-	client k8s.Reader
+	client k8s.Client
 }
 
 // Initialize method resolves the references which are not resolved by
-// the crossplane reconciler.
+// the crossplane reconciler, and the org by name if it is not resolved by
+// a reference or selector. The org resolved by name is persisted, so that
+// the name is looked up only once.
 func (i initializer) Initialize(ctx context.Context, mg resource.Managed) error {
 	cr, ok := mg.(*v1alpha1.SpaceQuota)
 	if !ok {
 		return errors.New(errUnexpectedObject)
 	}
 
-	return ResolveReferences(ctx, cr, i.client)
+	if err := ResolveReferences(ctx, cr, i.client); err != nil {
+		return err
+	}
+
+	p := &cr.Spec.ForProvider
+	if p.Org != nil || p.OrgName == nil {
+		return nil
+	}
+	cf, err := clients.ClientFnBuilder(ctx, i.client)(mg)
+	if err != nil {
+		return errors.Wrap(err, errNewClient)
+	}
+	if err := resolveOrgName(ctx, org.NewClient(cf), p); err != nil {
+		return err
+	}
+	return errors.Wrap(i.client.Update(ctx, cr), errUpdateCR)
 }
 
 // isUpToDate function checks whether an managed resource is up to
@@ -149,7 +185,7 @@ func isUpToDate(ctx context.Context,
 			return false, nil
 		}
 	}
-	if v := spec.InstanceMemory; v != nil {
+	if v := clients.MemoryLimitInMB(spec.InstanceMemory, spec.InstanceMemoryWithUnit); v != nil {
 		vInt := int(*v)
 		if !ptr.Equal(&vInt, resp.Apps.PerProcessMemoryInMB) {
 			return false, nil
@@ -160,23 +196,7 @@ func isUpToDate(ctx context.Context,
 			return false, nil
 		}
 	}
-	if v := spec.Org; v != nil {
-		if *v != resp.Relationships.Organization.Data.GUID {
-			return false, errors.New(errUpdateOrg)
-		}
-	}
-	if len(spec.Spaces) != len(resp.Relationships.Spaces.Data) {
-		return false, nil
-	}
-	specSpaces := make([]string, len(spec.Spaces))
-	respSpaces := make([]string, len(spec.Spaces))
-	for i := range specSpaces {
-		specSpaces[i] = *spec.Spaces[i]
-		respSpaces[i] = resp.Relationships.Spaces.Data[i].GUID
-	}
-	slices.Sort(specSpaces)
-	slices.Sort(respSpaces)
-	if slices.Compare(specSpaces, respSpaces) != 0 {
+	if !getSpaceStatusHelper(spec.Spaces, observedSpaces(resp)).inSync() {
 		return false, nil
 	}
 	if v := spec.TotalAppInstances; v != nil {
@@ -197,7 +217,7 @@ func isUpToDate(ctx context.Context,
 			return false, nil
 		}
 	}
-	if v := spec.TotalMemory; v != nil {
+	if v := clients.MemoryLimitInMB(spec.TotalMemory, spec.TotalMemoryWithUnit); v != nil {
 		vInt := int(*v)
 		if !ptr.Equal(&vInt, resp.Apps.TotalMemoryInMB) {
 			return false, nil
@@ -230,6 +250,16 @@ func isUpToDate(ctx context.Context,
 	return true, nil
 }
 
+// orgChanged function reports whether the org in the spec differs
+// from the org the space quota belongs to.
+func orgChanged(cr *v1alpha1.SpaceQuota, resp *cfresource.SpaceQuota) bool {
+	org := cr.Spec.ForProvider.Org
+	if org == nil || resp.Relationships.Organization == nil || resp.Relationships.Organization.Data == nil {
+		return false
+	}
+	return *org != resp.Relationships.Organization.Data.GUID
+}
+
 // // Connect typically produces an ExternalClient by:
 // 1. Tracking that the managed resource is using a ProviderConfig.
 // 2. Getting the managed resource's ProviderConfig.
@@ -281,7 +311,7 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 
 	resp, err := e.client.Get(ctx, meta.GetExternalName(cr))
 	if err != nil {
-		if clients.ErrorIsNotFound(err) {
+		if clients.IsNotFound(err) {
 			return managed.ExternalObservation{ResourceExists: false}, nil
 		}
 		return managed.ExternalObservation{ResourceExists: false}, errors.Wrap(err, errGet)
@@ -299,6 +329,12 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		}
 	}
 
+	// moving a space quota between orgs is not supported by Cloud
+	// Foundry, so an org change is reported instead of reconciled
+	if orgChanged(cr, resp) {
+		cr.SetConditions(xpv1.Unavailable().WithMessage(errUpdateOrg))
+	}
+
 	return managed.ExternalObservation{
 		ResourceExists:          true,
 		ResourceUpToDate:        upToDate,
@@ -352,13 +388,16 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 	}
 	if toDelete := sStatus.toDelete(); len(toDelete) > 0 {
 		for i := range toDelete {
-			err := c.client.Remove(ctx, *cr.Status.AtProvider.ID, toDelete[i])
+			err := spacequota.RemoveSpace(ctx, c.client, *cr.Status.AtProvider.ID, toDelete[i])
 			if err != nil {
 				return managed.ExternalUpdate{}, errors.Wrap(err, errUpdate)
 			}
 		}
 	}
 	GenerateSpaceQuota(resp).Status.AtProvider.DeepCopyInto(&cr.Status.AtProvider)
+	// the response of the update does not reflect the applied and
+	// removed spaces, which now match the spec
+	cr.Status.AtProvider.Spaces = sStatus.inSpec()
 	return managed.ExternalUpdate{}, nil
 }
 
@@ -376,7 +415,7 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.Ext
 	}
 
 	for i := range cr.Status.AtProvider.Spaces {
-		err := c.client.Remove(ctx, *cr.Status.AtProvider.ID, *cr.Status.AtProvider.Spaces[i])
+		err := spacequota.RemoveSpace(ctx, c.client, *cr.Status.AtProvider.ID, *cr.Status.AtProvider.Spaces[i])
 		if err != nil {
 			return managed.ExternalDelete{}, errors.Wrap(err, errDelete)
 		}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	cfresource "github.com/cloudfoundry/go-cfclient/v3/resource"
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
@@ -14,6 +15,7 @@ import (
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
+	k8s "sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
 	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/fake"
@@ -23,6 +25,10 @@ var (
 	errBoom = errors.New("boom")
 	name    = "my-space-quota"
 	guid    = "2d8b0d04-d537-4e4e-8c6f-f09ca0e7f56a"
+
+	spaceGUID  = "3d8b0d04-d537-4e4e-8c6f-f09ca0e7f56a"
+	spaceGUID2 = "4d8b0d04-d537-4e4e-8c6f-f09ca0e7f56a"
+	spaceGUID3 = "5d8b0d04-d537-4e4e-8c6f-f09ca0e7f56a"
 )
 
 type modifier func(*v1alpha1.SpaceQuota)
@@ -52,6 +58,22 @@ func withID(guid string) modifier {
 	}
 }
 
+func withSpaces(spaces ...string) modifier {
+	return func(r *v1alpha1.SpaceQuota) {
+		for i := range spaces {
+			r.Spec.ForProvider.Spaces = append(r.Spec.ForProvider.Spaces, &spaces[i])
+		}
+	}
+}
+
+func withObservedSpaces(spaces ...string) modifier {
+	return func(r *v1alpha1.SpaceQuota) {
+		for i := range spaces {
+			r.Status.AtProvider.Spaces = append(r.Status.AtProvider.Spaces, &spaces[i])
+		}
+	}
+}
+
 var zeroTime = time.Time{}.Format(time.RFC3339)
 
 func fakeSpaceQuota(m ...modifier) *v1alpha1.SpaceQuota {
@@ -232,6 +254,199 @@ func TestObserve(t *testing.T) {
 	}
 }
 
+func TestObserveOrgChanged(t *testing.T) {
+	otherOrg := "6d8b0d04-d537-4e4e-8c6f-f09ca0e7f56a"
+	cr := fakeSpaceQuota(withExternalName(guid), withName(name), withOrg(otherOrg))
+
+	m := &fake.MockSpaceQuota{}
+	m.On("Get", guid).Return(
+		&fake.NewSpaceQuota().SetName(name).SetGUID(guid).SetOrgGUID(guid).SpaceQuota,
+		nil,
+	)
+	c := &external{kube: &test.MockClient{}, client: m, isUpToDate: isUpToDate}
+
+	obs, err := c.Observe(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("Observe(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, obs); diff != "" {
+		t.Errorf("Observe(...): -want, +got:\n%s", diff)
+	}
+	want := xpv1.Unavailable().WithMessage(errUpdateOrg)
+	if got := cr.GetCondition(xpv1.TypeReady); !got.Equal(want) {
+		t.Errorf("Observe(...): want condition %v, got %v", want, got)
+	}
+}
+
+func TestIsUpToDateSpaces(t *testing.T) {
+	cases := map[string]struct {
+		spec     []string
+		observed []string
+		want     bool
+	}{
+		"SameSpaces": {
+			spec:     []string{spaceGUID2, spaceGUID},
+			observed: []string{spaceGUID, spaceGUID2},
+			want:     true,
+		},
+		"SpaceAddedOutOfBand": {
+			spec:     []string{spaceGUID},
+			observed: []string{spaceGUID, spaceGUID2},
+			want:     false,
+		},
+		"SpaceRemovedOutOfBand": {
+			spec:     []string{spaceGUID, spaceGUID2},
+			observed: []string{spaceGUID},
+			want:     false,
+		},
+		"SpaceReplacedOutOfBand": {
+			spec:     []string{spaceGUID, spaceGUID2},
+			observed: []string{spaceGUID, spaceGUID3},
+			want:     false,
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			cr := fakeSpaceQuota(withSpaces(tc.spec...))
+			resp := &fake.NewSpaceQuota().SetSpaces(tc.observed...).SpaceQuota
+			got, err := isUpToDate(context.Background(), cr, resp)
+			if err != nil {
+				t.Fatalf("isUpToDate(...): unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("isUpToDate(...): want %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestIsUpToDateMemory(t *testing.T) {
+	cases := map[string]struct {
+		spec v1alpha1.SpaceQuotaParameters
+		want bool
+	}{
+		"InMB": {
+			spec: v1alpha1.SpaceQuotaParameters{InstanceMemory: ptr.To(1024.0), TotalMemory: ptr.To(10240.0)},
+			want: true,
+		},
+		"WithUnit": {
+			spec: v1alpha1.SpaceQuotaParameters{InstanceMemoryWithUnit: ptr.To("1G"), TotalMemoryWithUnit: ptr.To("10GB")},
+			want: true,
+		},
+		"WithUnitChanged": {
+			spec: v1alpha1.SpaceQuotaParameters{TotalMemoryWithUnit: ptr.To("20G")},
+			want: false,
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			cr := fakeSpaceQuota()
+			cr.Spec.ForProvider = tc.spec
+			resp := &fake.NewSpaceQuota().SpaceQuota
+			resp.Apps.PerProcessMemoryInMB = ptr.To(1024)
+			resp.Apps.TotalMemoryInMB = ptr.To(10240)
+			got, err := isUpToDate(context.Background(), cr, resp)
+			if err != nil {
+				t.Fatalf("isUpToDate(...): unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("isUpToDate(...): want %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestResolveOrgName(t *testing.T) {
+	orgGUID := "6d8b0d04-d537-4e4e-8c6f-f09ca0e7f56a"
+
+	cases := map[string]struct {
+		org     func() *fake.MockOrganization
+		wantOrg *string
+		err     error
+	}{
+		"Resolved": {
+			org: func() *fake.MockOrganization {
+				m := &fake.MockOrganization{}
+				o := &cfresource.Organization{Name: "my-org"}
+				o.GUID = orgGUID
+				m.On("Single").Return(o, nil)
+				return m
+			},
+			wantOrg: &orgGUID,
+		},
+		"NotFound": {
+			org: func() *fake.MockOrganization {
+				m := &fake.MockOrganization{}
+				m.On("Single").Return(&cfresource.Organization{}, errBoom)
+				return m
+			},
+			err: errors.Wrap(errBoom, errResolveOrgName),
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			p := &v1alpha1.SpaceQuotaParameters{OrgName: ptr.To("my-org")}
+			err := resolveOrgName(context.Background(), tc.org(), p)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("resolveOrgName(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantOrg, p.Org); diff != "" {
+				t.Errorf("resolveOrgName(...): -want org, +got org:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestInitializeOrgName(t *testing.T) {
+	orgGUID := "6d8b0d04-d537-4e4e-8c6f-f09ca0e7f56a"
+	refGUID := "7d8b0d04-d537-4e4e-8c6f-f09ca0e7f56a"
+
+	cases := map[string]struct {
+		mg      *v1alpha1.SpaceQuota
+		wantOrg *string
+	}{
+		"OrgTakesPrecedence": {
+			mg: fakeSpaceQuota(withOrg(orgGUID), func(r *v1alpha1.SpaceQuota) {
+				r.Spec.ForProvider.OrgName = ptr.To("my-org")
+			}),
+			wantOrg: &orgGUID,
+		},
+		"OrgRefTakesPrecedence": {
+			mg: fakeSpaceQuota(func(r *v1alpha1.SpaceQuota) {
+				r.Spec.ForProvider.OrgName = ptr.To("my-org")
+				r.Spec.ForProvider.OrgRef = &xpv1.NamespacedReference{Name: "my-org"}
+			}),
+			wantOrg: &refGUID,
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			// the org is not looked up by name in Cloud Foundry, which would
+			// need the ProviderConfig of the SpaceQuota
+			kube := &test.MockClient{
+				MockGet: func(_ context.Context, _ k8s.ObjectKey, obj k8s.Object) error {
+					o, ok := obj.(*v1alpha1.Organization)
+					if !ok {
+						return errBoom
+					}
+					o.Status.AtProvider.ID = ptr.To(refGUID)
+					return nil
+				},
+			}
+			if err := (initializer{client: kube}).Initialize(context.Background(), tc.mg); err != nil {
+				t.Fatalf("Initialize(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantOrg, tc.mg.Spec.ForProvider.Org); diff != "" {
+				t.Errorf("Initialize(...): -want org, +got org:\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestCreate(t *testing.T) {
 	type args struct {
 		mg resource.Managed
@@ -346,6 +561,47 @@ func TestUpdate(t *testing.T) {
 				return m
 			}(),
 		},
+		"SpaceDeletedExternally": {
+			args: args{
+				mg: fakeSpaceQuota(withExternalName(guid), withID(guid), withName(name), withObservedSpaces(spaceGUID)),
+			},
+			want: want{
+				mg:  fakeSpaceQuota(withExternalName(guid), withID(guid), withName(name)),
+				obs: managed.ExternalUpdate{},
+				err: nil,
+			},
+			cfClient: func() *fake.MockSpaceQuota {
+				m := &fake.MockSpaceQuota{}
+				m.On("Update").Return(
+					&fake.NewSpaceQuota().SetName(name).SetGUID(guid).SpaceQuota,
+					nil,
+				)
+				m.On("Remove", spaceGUID).Return(fake.ErrResourceNotFound)
+				return m
+			}(),
+		},
+		"ConvergeSpaces": {
+			args: args{
+				mg: fakeSpaceQuota(withExternalName(guid), withID(guid), withName(name),
+					withSpaces(spaceGUID3, spaceGUID2), withObservedSpaces(spaceGUID, spaceGUID2)),
+			},
+			want: want{
+				mg: fakeSpaceQuota(withExternalName(guid), withID(guid), withName(name),
+					withSpaces(spaceGUID3, spaceGUID2), withObservedSpaces(spaceGUID2, spaceGUID3)),
+				obs: managed.ExternalUpdate{},
+				err: nil,
+			},
+			cfClient: func() *fake.MockSpaceQuota {
+				m := &fake.MockSpaceQuota{}
+				m.On("Update").Return(
+					&fake.NewSpaceQuota().SetName(name).SetGUID(guid).SetSpaces(spaceGUID, spaceGUID2).SpaceQuota,
+					nil,
+				)
+				m.On("Apply", []string{spaceGUID3}).Return(nil)
+				m.On("Remove", spaceGUID).Return(nil)
+				return m
+			}(),
+		},
 		"IDNotSet": {
 			args: args{
 				mg: fakeSpaceQuota(withExternalName(guid)),
@@ -419,6 +675,70 @@ func TestDelete(t *testing.T) {
 				return m
 			}(),
 		},
+		"SpaceDeletedExternally": {
+			args: args{
+				mg: fakeSpaceQuota(withExternalName(guid), withID(guid), withObservedSpaces(spaceGUID)),
+			},
+			want: want{
+				mg:  fakeSpaceQuota(withExternalName(guid), withID(guid), withObservedSpaces(spaceGUID)),
+				err: nil,
+			},
+			cfClient: func() *fake.MockSpaceQuota {
+				m := &fake.MockSpaceQuota{}
+				m.On("Remove", spaceGUID).Return(fake.ErrResourceNotFound)
+				m.On("Delete").Return(
+					"",
+					nil,
+				)
+				return m
+			}(),
+		},
+		"QuotaNotApplied": {
+			args: args{
+				mg: fakeSpaceQuota(withExternalName(guid), withID(guid), withObservedSpaces(spaceGUID)),
+			},
+			want: want{
+				mg:  fakeSpaceQuota(withExternalName(guid), withID(guid), withObservedSpaces(spaceGUID)),
+				err: nil,
+			},
+			cfClient: func() *fake.MockSpaceQuota {
+				m := &fake.MockSpaceQuota{}
+				m.On("Remove", spaceGUID).Return(cfresource.CloudFoundryError{Code: 10008, Title: "CF-UnprocessableEntity", Detail: "Unable to remove quota from space with guid '" + spaceGUID + "'. Ensure the space quota is applied to this space."})
+				m.On("Delete").Return(
+					"",
+					nil,
+				)
+				return m
+			}(),
+		},
+		"OtherUnprocessableEntity": {
+			args: args{
+				mg: fakeSpaceQuota(withExternalName(guid), withID(guid), withObservedSpaces(spaceGUID)),
+			},
+			want: want{
+				mg:  fakeSpaceQuota(withExternalName(guid), withID(guid), withObservedSpaces(spaceGUID)),
+				err: errors.Wrap(cfresource.CloudFoundryError{Code: 10008, Title: "CF-UnprocessableEntity", Detail: "Space quota is in use"}, errDelete),
+			},
+			cfClient: func() *fake.MockSpaceQuota {
+				m := &fake.MockSpaceQuota{}
+				m.On("Remove", spaceGUID).Return(cfresource.CloudFoundryError{Code: 10008, Title: "CF-UnprocessableEntity", Detail: "Space quota is in use"})
+				return m
+			}(),
+		},
+		"RemoveSpaceError": {
+			args: args{
+				mg: fakeSpaceQuota(withExternalName(guid), withID(guid), withObservedSpaces(spaceGUID)),
+			},
+			want: want{
+				mg:  fakeSpaceQuota(withExternalName(guid), withID(guid), withObservedSpaces(spaceGUID)),
+				err: errors.Wrap(errBoom, errDelete),
+			},
+			cfClient: func() *fake.MockSpaceQuota {
+				m := &fake.MockSpaceQuota{}
+				m.On("Remove", spaceGUID).Return(errBoom)
+				return m
+			}(),
+		},
 		"IDNotSet": {
 			args: args{
 				mg: fakeSpaceQuota(withExternalName(guid)),
@@ -451,6 +771,7 @@ func TestDelete(t *testing.T) {
 					t.Errorf("Observe(...): want error != got error:\n%s", diff)
 				}
 			}
+			tc.cfClient.AssertExpectations(t)
 		})
 	}
 }
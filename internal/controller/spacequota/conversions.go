@@ -9,6 +9,7 @@ import (
 	"k8s.io/utils/ptr"
 
 	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
 )
 
 // GenerateSpaceQuota returns the current state in the form of
@@ -38,20 +39,7 @@ func GenerateSpaceQuota(resp *resource.SpaceQuota) *v1alpha1.SpaceQuota {
 	if resp.Relationships.Organization != nil && resp.Relationships.Organization.Data != nil {
 		cr.Status.AtProvider.Org = &resp.Relationships.Organization.Data.GUID
 	}
-	if resp.Relationships.Spaces != nil {
-		if l := len(resp.Relationships.Spaces.Data); l != len(cr.Status.AtProvider.Spaces) {
-			cr.Status.AtProvider.Spaces = make([]*string, l)
-		}
-		for i := range resp.Relationships.Spaces.Data {
-			cr.Status.AtProvider.Spaces[i] = &resp.Relationships.Spaces.Data[i].GUID
-		}
-		slices.SortFunc(cr.Status.AtProvider.Spaces, func(a, b *string) int {
-			if a != nil && b != nil {
-				return strings.Compare(*a, *b)
-			}
-			return 0
-		})
-	}
+	cr.Status.AtProvider.Spaces = observedSpaces(resp)
 	if v := resp.Routes.TotalReservedPorts; v != nil {
 		cr.Status.AtProvider.TotalRoutePorts = ptr.To(float64(*v))
 	}
@@ -69,6 +57,22 @@ func GenerateSpaceQuota(resp *resource.SpaceQuota) *v1alpha1.SpaceQuota {
 	return cr
 }
 
+// observedSpaces function returns the sorted IDs of the Spaces the
+// space quota is applied to.
+func observedSpaces(resp *resource.SpaceQuota) []*string {
+	if resp.Relationships.Spaces == nil {
+		return nil
+	}
+	spaces := make([]*string, len(resp.Relationships.Spaces.Data))
+	for i := range resp.Relationships.Spaces.Data {
+		spaces[i] = &resp.Relationships.Spaces.Data[i].GUID
+	}
+	slices.SortFunc(spaces, func(a, b *string) int {
+		return strings.Compare(*a, *b)
+	})
+	return spaces
+}
+
 // GenerateCreateSpaceQuota returns a create input.
 //
 //nolint:gocyclo
@@ -91,7 +95,7 @@ func GenerateCreateSpaceQuota(cr *v1alpha1.SpaceQuota) *resource.SpaceQuotaCreat
 	if v := spec.TotalAppTasks; v != nil {
 		res = res.WithPerAppTasks(int(*v))
 	}
-	if v := spec.InstanceMemory; v != nil {
+	if v := clients.MemoryLimitInMB(spec.InstanceMemory, spec.InstanceMemoryWithUnit); v != nil {
 		res = res.WithPerProcessMemoryInMB(int(*v))
 	}
 	if v := spec.Spaces; v != nil {
@@ -110,7 +114,7 @@ func GenerateCreateSpaceQuota(cr *v1alpha1.SpaceQuota) *resource.SpaceQuotaCreat
 	if v := spec.TotalAppInstances; v != nil {
 		res = res.WithTotalInstances(int(*v))
 	}
-	if v := spec.TotalMemory; v != nil {
+	if v := clients.MemoryLimitInMB(spec.TotalMemory, spec.TotalMemoryWithUnit); v != nil {
 		res = res.WithTotalMemoryInMB(int(*v))
 	}
 	if v := spec.TotalRoutePorts; v != nil {
@@ -151,13 +155,13 @@ func GenerateUpdateSpaceQuota(cr *v1alpha1.SpaceQuota) *resource.SpaceQuotaCreat
 	if v := spec.TotalAppTasks; v != nil {
 		res = res.WithPerAppTasks(int(*v))
 	}
-	if v := spec.InstanceMemory; v != nil {
+	if v := clients.MemoryLimitInMB(spec.InstanceMemory, spec.InstanceMemoryWithUnit); v != nil {
 		res = res.WithPerProcessMemoryInMB(int(*v))
 	}
 	if v := spec.TotalAppInstances; v != nil {
 		res = res.WithTotalInstances(int(*v))
 	}
-	if v := spec.TotalMemory; v != nil {
+	if v := clients.MemoryLimitInMB(spec.TotalMemory, spec.TotalMemoryWithUnit); v != nil {
 		res = res.WithTotalMemoryInMB(int(*v))
 	}
 	if v := spec.TotalRoutePorts; v != nil {
@@ -200,6 +204,7 @@ func (ss spaceStatuses) toCreate() []string {
 			result = append(result, guid)
 		}
 	}
+	slices.Sort(result)
 	return result
 }
 
@@ -213,9 +218,31 @@ func (ss spaceStatuses) toDelete() []string {
 			result = append(result, guid)
 		}
 	}
+	slices.Sort(result)
 	return result
 }
 
+// inSpec method of spaceStatuses returns the sorted IDs of the
+// expected Spaces, or nil if no space is expected.
+func (ss spaceStatuses) inSpec() []*string {
+	var result []*string
+	for guid, status := range ss {
+		if status.inSpec {
+			result = append(result, ptr.To(guid))
+		}
+	}
+	slices.SortFunc(result, func(a, b *string) int {
+		return strings.Compare(*a, *b)
+	})
+	return result
+}
+
+// inSync method of spaceStatuses reports whether the observed Spaces
+// are exactly the expected ones.
+func (ss spaceStatuses) inSync() bool {
+	return len(ss.toCreate()) == 0 && len(ss.toDelete()) == 0
+}
+
 // getSpaceStatusHelper function generates the spaceStatuses type
 // based on the ID of the spaces which are expected (specSpaces) and
 // which are observed (statusSpaces).
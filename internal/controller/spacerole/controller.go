@@ -12,6 +12,7 @@ import (
 	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/feature"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
@@ -33,6 +34,8 @@ const (
 	errGet               = "cannot get space role according to the specified parameters"
 	errGetResource       = "cannot get space role via the cloudfoundry API"
 	errCreate            = "cannot create space role"
+	errUpdate            = "cannot update space role"
+	errOrigin            = "cannot resolve the origin of the user"
 	errDelete            = "cannot delete space role"
 )
 
@@ -41,16 +44,22 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 	name := managed.ControllerName(v1alpha1.SpaceRole_GroupKind)
 
 	options := []managed.ReconcilerOption{
-		managed.WithExternalConnecter(&connector{kube: mgr.GetClient(),
+		managed.WithExternalConnecter(clients.WithPause(clients.WithErrorClassification(clients.WithOperationTimeout(mgr.GetClient(), &connector{kube: mgr.GetClient(),
 			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &pcv1beta1.ProviderConfigUsage{}),
-		}),
+		}, clients.DefaultOperationTimeout)))),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithTimeout(clients.MaxOperationTimeout),
+		managed.WithPollIntervalHook(clients.PollIntervalHook(mgr.GetClient())),
 		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 		managed.WithInitializers(&initializer{
 			kube: mgr.GetClient(),
 		}),
 	}
 
+	if o.Features.Enabled(feature.EnableBetaManagementPolicies) {
+		options = append(options, managed.WithManagementPolicies())
+	}
+
 	r := managed.NewReconciler(mgr,
 		resource.ManagedKind(v1alpha1.SpaceRole_GroupVersionKind),
 		options...)
@@ -87,8 +96,9 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errGetClient)
 	}
 
-	role, job := role.NewClient(cf)
-	return &external{role: role, kube: c.kube, job: job}, nil
+	r, job := role.NewClient(cf)
+
+	return &external{role: role.SharedCache().Wrap(r, clients.ProviderConfigName(mg).String()), users: role.NewUserClient(cf), kube: c.kube, job: job}, nil
 }
 
 // Disconnect implements the managed.ExternalClient interface
@@ -99,9 +109,10 @@ func (c *external) Disconnect(ctx context.Context) error {
 
 // An external is a managed.ExternalConnecter that is using the CloudFoundry API to observe and modify resources.
 type external struct {
-	role role.Role
-	job  job.Job
-	kube k8s.Client
+	role  role.Role
+	users role.Users
+	job   job.Job
+	kube  k8s.Client
 }
 
 // Observe managed resource SpaceRole
@@ -111,12 +122,22 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, errors.New(errWrongKind)
 	}
 
-	// Fetch the role object using the CloudFoundry API by guid or according to the specified parameters
+	if len(cr.Spec.ForProvider.Usernames) > 0 {
+		return c.observeAssignments(ctx, cr)
+	}
+
+	// Resolve the origin before looking up the role, so that a role of the user in another origin is not adopted
 	guid := meta.GetExternalName(cr)
+	originLateInitialized, err := c.resolveOrigin(ctx, cr, !clients.IsValidGUID(guid))
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errOrigin)
+	}
+
+	// Fetch the role object using the CloudFoundry API by guid or according to the specified parameters
 	r, err := role.GetSpaceRole(ctx, c.role, guid, cr.Spec.ForProvider)
 
 	if err != nil {
-		if clients.ErrorIsNotFound(err) {
+		if clients.IsNotFound(err) {
 			return managed.ExternalObservation{ResourceExists: false}, nil
 		}
 		return managed.ExternalObservation{}, errors.Wrap(err, errGet)
@@ -126,7 +147,7 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{ResourceExists: false}, nil
 	}
 
-	resourceLateInitialized := false
+	resourceLateInitialized := originLateInitialized
 	if guid != r.GUID {
 		meta.SetExternalName(cr, r.GUID)
 		resourceLateInitialized = true
@@ -135,13 +156,52 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	cr.Status.AtProvider = role.GenerateSpaceRoleObservation(r)
 	cr.Status.SetConditions(xpv1.Available())
 
+	// Roles cannot be updated, a role of another type is replaced in Update
 	return managed.ExternalObservation{
 		ResourceExists:          cr.Status.AtProvider.ID != nil,
-		ResourceUpToDate:        true,
+		ResourceUpToDate:        r.Type == role.SpaceRoleType(cr.Spec.ForProvider.Type).String(),
 		ResourceLateInitialized: resourceLateInitialized,
 	}, nil
 }
 
+// resolveOrigin late-initializes the origin of the user if it is unset and, if validate is set, checks that the
+// specified origin matches the origin the user is known with.
+func (c *external) resolveOrigin(ctx context.Context, cr *v1alpha1.SpaceRole, validate bool) (bool, error) {
+	spec := &cr.Spec.ForProvider
+	if spec.Username == "" || (spec.Origin != nil && !validate) {
+		return false, nil
+	}
+
+	origin, err := role.ResolveOrigin(ctx, c.users, spec.Username, spec.Origin)
+	if err != nil || spec.Origin != nil {
+		return false, err
+	}
+	spec.Origin = &origin
+	return true, nil
+}
+
+// observeAssignments observes the roles of a SpaceRole that lists multiple usernames
+func (c *external) observeAssignments(ctx context.Context, cr *v1alpha1.SpaceRole) (managed.ExternalObservation, error) {
+	assigned, err := role.ObserveSpaceRoleAssignments(ctx, c.role, c.users, cr.Spec.ForProvider, cr.Status.AtProvider.AssignedRoles, cr.Status.AtProvider.ID)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGet)
+	}
+
+	cr.Status.AtProvider = v1alpha1.SpaceRoleObservation{
+		Type:            ptr.To(role.SpaceRoleType(cr.Spec.ForProvider.Type).String()),
+		RoleAssignments: v1alpha1.RoleAssignments{AssignedRoles: assigned},
+	}
+	if len(assigned) == 0 {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	cr.Status.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: role.AssignmentsUpToDate(cr.Spec.ForProvider.Usernames, assigned),
+	}, nil
+}
+
 // Create a managed resource SpaceRole
 func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
 	cr, ok := mg.(*v1alpha1.SpaceRole)
@@ -150,11 +210,17 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	}
 
 	spec := cr.Spec.ForProvider
+	if len(spec.Usernames) > 0 {
+		assigned, err := role.AssignSpaceRoles(ctx, c.role, c.users, c.job, spec, cr.Status.AtProvider.AssignedRoles)
+		cr.Status.AtProvider.AssignedRoles = assigned
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreate)
+	}
+
 	if spec.Space == nil || spec.Username == "" || spec.Type == "" {
 		return managed.ExternalCreation{}, errors.New(errCreate)
 	}
 
-	o, err := c.role.CreateSpaceRoleWithUsername(ctx, *spec.Space, spec.Username, role.SpaceRoleType(spec.Type), ptr.Deref(spec.Origin, "sap.ids"))
+	o, err := c.role.CreateSpaceRoleWithUsername(ctx, *spec.Space, spec.Username, role.SpaceRoleType(spec.Type), ptr.Deref(spec.Origin, role.DefaultOrigin))
 	if err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, errCreate)
 	}
@@ -170,12 +236,20 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 
 // Update managed resource SpaceRole
 func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
-	_, ok := mg.(*v1alpha1.SpaceRole)
+	cr, ok := mg.(*v1alpha1.SpaceRole)
 	if !ok {
 		return managed.ExternalUpdate{}, errors.New(errWrongKind)
 	}
 
-	// Do nothing, as SpaceRole is observe-only
+	if len(cr.Spec.ForProvider.Usernames) > 0 {
+		assigned, err := role.AssignSpaceRoles(ctx, c.role, c.users, c.job, cr.Spec.ForProvider, cr.Status.AtProvider.AssignedRoles)
+		cr.Status.AtProvider.AssignedRoles = assigned
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdate)
+	}
+
+	if err := c.replaceRole(ctx, cr); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdate)
+	}
 
 	return managed.ExternalUpdate{
 		// Optionally return any details that may be required to connect to the
@@ -184,6 +258,40 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 	}, nil
 }
 
+// replaceRole replaces the observed role of the user with a role of the specified type. CF roles are immutable,
+// so the old role is deleted and a new role is created for the same user and space.
+func (c *external) replaceRole(ctx context.Context, cr *v1alpha1.SpaceRole) error {
+	spec := cr.Spec.ForProvider
+	roleType := role.SpaceRoleType(spec.Type)
+	if cr.Status.AtProvider.ID == nil || ptr.Deref(cr.Status.AtProvider.Type, "") == roleType.String() {
+		return nil
+	}
+	if spec.Space == nil {
+		return errors.New(role.ErrSpaceNotSpecified)
+	}
+
+	jobGUID, err := c.role.Delete(ctx, *cr.Status.AtProvider.ID)
+	switch {
+	case clients.IsNotFound(err):
+		// the old role is already gone
+	case err != nil:
+		return err
+	default:
+		if err := job.PollJobComplete(ctx, c.job, jobGUID); err != nil {
+			return err
+		}
+	}
+
+	o, err := c.role.CreateSpaceRoleWithUsername(ctx, *spec.Space, spec.Username, roleType, ptr.Deref(spec.Origin, role.DefaultOrigin))
+	if err != nil {
+		return err
+	}
+
+	// The reconciler does not persist annotations set in Update, so the new external name is persisted here
+	meta.SetExternalName(cr, o.GUID)
+	return c.kube.Update(ctx, cr)
+}
+
 // Delete managed resource SpaceRole
 func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
 	cr, ok := mg.(*v1alpha1.SpaceRole)
@@ -193,6 +301,11 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.Ext
 	// TODO
 
 	cr.SetConditions(xpv1.Deleting())
+	if len(cr.Status.AtProvider.AssignedRoles) > 0 {
+		assigned, err := role.UnassignRoles(ctx, c.role, c.job, cr.Status.AtProvider.AssignedRoles)
+		cr.Status.AtProvider.AssignedRoles = assigned
+		return managed.ExternalDelete{}, errors.Wrap(err, errDelete)
+	}
 	if cr.Status.AtProvider.ID == nil {
 		return managed.ExternalDelete{}, nil
 	}
@@ -212,14 +325,9 @@ type initializer struct {
 
 // / Initialize implements the Initializer interface
 func (c *initializer) Initialize(ctx context.Context, mg resource.Managed) error {
-	cr, ok := mg.(*v1alpha1.SpaceRole)
-	if !ok {
+	if _, ok := mg.(*v1alpha1.SpaceRole); !ok {
 		return errors.New(errWrongKind)
 	}
 
-	if cr.Spec.ForProvider.SpaceRef != nil || cr.Spec.ForProvider.SpaceSelector != nil {
-		return cr.ResolveReferences(ctx, c.kube)
-	}
-
-	return space.ResolveByName(ctx, clients.ClientFnBuilder(ctx, c.kube), mg)
+	return space.ResolveSpaceReference(ctx, c.kube, mg)
 }
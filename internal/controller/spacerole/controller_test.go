@@ -88,6 +88,13 @@ func withExternalName(name string) modifier {
 	}
 }
 
+func withObservedRole(guid, roleType string) modifier {
+	return func(r *v1alpha1.SpaceRole) {
+		r.Status.AtProvider.ID = &guid
+		r.Status.AtProvider.Type = &roleType
+	}
+}
+
 func fakeSpaceRole(m ...modifier) *v1alpha1.SpaceRole {
 	r := &v1alpha1.SpaceRole{
 		ObjectMeta: metav1.ObjectMeta{
@@ -106,6 +113,71 @@ func fakeSpaceRole(m ...modifier) *v1alpha1.SpaceRole {
 	return r
 }
 
+func newMockUsers(users ...*cfresource.User) *fake.MockUser {
+	m := &fake.MockUser{}
+	m.On("ListAll").Return(users, nil)
+	return m
+}
+
+func TestObserveOrigin(t *testing.T) {
+	otherOriginUser := &cfresource.User{
+		Username: ptr.To("user1"),
+		Origin:   ptr.To("uaa"),
+		Resource: cfresource.Resource{GUID: guidNoRefUser}}
+
+	cases := map[string]struct {
+		mg         *v1alpha1.SpaceRole
+		users      []*cfresource.User
+		wantOrigin *string
+		wantErr    error
+	}{
+		"LateInitializesOrigin": {
+			mg:         fakeSpaceRole(withSpace(guidSpace), withUsername("user1"), withType(v1alpha1.SpaceManager)),
+			users:      []*cfresource.User{healthyUser},
+			wantOrigin: ptr.To("sap.ids"),
+		},
+		"AcceptsMatchingOrigin": {
+			mg:         fakeSpaceRole(withSpace(guidSpace), withUsername("user1"), withType(v1alpha1.SpaceManager), withOrigin("sap.ids")),
+			users:      []*cfresource.User{healthyUser, otherOriginUser},
+			wantOrigin: ptr.To("sap.ids"),
+		},
+		"RejectsMismatchingOrigin": {
+			mg:         fakeSpaceRole(withSpace(guidSpace), withUsername("user1"), withType(v1alpha1.SpaceManager), withOrigin("my-origin")),
+			users:      []*cfresource.User{healthyUser},
+			wantOrigin: ptr.To("my-origin"),
+			wantErr:    errors.Wrap(errors.New(`user "user1" does not exist in origin "my-origin", the user exists in origin(s) sap.ids`), errOrigin),
+		},
+		"RejectsAmbiguousOrigin": {
+			mg:      fakeSpaceRole(withSpace(guidSpace), withUsername("user1"), withType(v1alpha1.SpaceManager)),
+			users:   []*cfresource.User{healthyUser, otherOriginUser},
+			wantErr: errors.Wrap(errors.New(`user "user1" exists in multiple origins sap.ids, uaa, origin must be specified`), errOrigin),
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			m := &fake.MockSpaceRole{}
+			m.On("ListIncludeUsersAll").Return([]*cfresource.Role{healthyRole}, []*cfresource.User{healthyUser}, nil)
+			c := &external{role: m, users: newMockUsers(tc.users...)}
+
+			obs, err := c.Observe(context.Background(), tc.mg)
+			if tc.wantErr != nil {
+				if err == nil || err.Error() != tc.wantErr.Error() {
+					t.Fatalf("Observe(...): want error %v, got %v", tc.wantErr, err)
+				}
+				m.AssertNotCalled(t, "ListIncludeUsersAll")
+			} else if err != nil {
+				t.Fatalf("Observe(...): unexpected error: %v", err)
+			} else if !obs.ResourceLateInitialized {
+				t.Errorf("Observe(...): want resource to be late-initialized")
+			}
+			if diff := cmp.Diff(tc.wantOrigin, tc.mg.Spec.ForProvider.Origin); diff != "" {
+				t.Errorf("Observe(...): -want origin, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestObserve(t *testing.T) {
 	type service func() *fake.MockSpaceRole
 	type args struct {
@@ -222,12 +294,31 @@ func TestObserve(t *testing.T) {
 				return m
 			},
 		},
+		"TypeChanged": {
+			args: args{
+				mg: fakeSpaceRole(withSpace("my-space"), withUsername("user1"), withOrigin("sap.ids"), withType(v1alpha1.SpaceDeveloper), withExternalName(guidRole)),
+			},
+			want: want{
+				mg:  fakeSpaceRole(withSpace("my-space"), withUsername("user1"), withOrigin("sap.ids"), withType(v1alpha1.SpaceDeveloper), withExternalName(guidRole)),
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				err: nil,
+			},
+			service: func() *fake.MockSpaceRole {
+				m := &fake.MockSpaceRole{}
+
+				m.On("Get", guidRole).Return(
+					healthyRole,
+					nil,
+				)
+				return m
+			},
+		},
 		"Successful when SpaceRole guid is found": {
 			args: args{
-				mg: fakeSpaceRole(withSpace("my-space"), withUsername("user1"), withType(v1alpha1.SpaceManager), withExternalName(guidRole)),
+				mg: fakeSpaceRole(withSpace("my-space"), withUsername("user1"), withOrigin("sap.ids"), withType(v1alpha1.SpaceManager), withExternalName(guidRole)),
 			},
 			want: want{
-				mg:  fakeSpaceRole(withSpace("my-space"), withUsername("user1"), withType(v1alpha1.SpaceManager), withExternalName(guidRole)),
+				mg:  fakeSpaceRole(withSpace("my-space"), withUsername("user1"), withOrigin("sap.ids"), withType(v1alpha1.SpaceManager), withExternalName(guidRole)),
 				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
 				err: nil,
 			},
@@ -249,8 +340,9 @@ func TestObserve(t *testing.T) {
 				kube: &test.MockClient{
 					MockUpdate: test.NewMockUpdateFn(nil),
 				},
-				job:  nil,
-				role: tc.service(),
+				job:   nil,
+				role:  tc.service(),
+				users: newMockUsers(healthyUser),
 			}
 			obs, err := c.Observe(context.Background(), tc.args.mg)
 
@@ -467,3 +559,93 @@ func TestCreate(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdate(t *testing.T) {
+	type args struct {
+		mg resource.Managed
+	}
+
+	type want struct {
+		mg  resource.Managed
+		err error
+	}
+
+	guidNewRole := "9e4b0d04-d537-6a6a-8c6f-f09ca0e7f69c"
+
+	cases := map[string]struct {
+		args    args
+		want    want
+		service func() *fake.MockSpaceRole
+	}{
+		"TypeChanged": {
+			args: args{
+				mg: fakeSpaceRole(withSpace(guidSpace), withUsername("user1"), withOrigin("sap.ids"), withType(v1alpha1.SpaceDeveloper), withExternalName(guidRole), withObservedRole(guidRole, "space_manager")),
+			},
+			want: want{
+				mg:  fakeSpaceRole(withSpace(guidSpace), withUsername("user1"), withOrigin("sap.ids"), withType(v1alpha1.SpaceDeveloper), withExternalName(guidNewRole), withObservedRole(guidRole, "space_manager")),
+				err: nil,
+			},
+			service: func() *fake.MockSpaceRole {
+				m := &fake.MockSpaceRole{}
+				del := m.On("Delete").Return("JOB", nil).Once()
+				m.On("CreateSpaceRoleWithUsername").Return(
+					&fake.NewSpaceRole().SetType("space_developer").SetGUID(guidNewRole).Role,
+					nil,
+				).Once().NotBefore(del)
+				return m
+			},
+		},
+		"TypeMatches": {
+			args: args{
+				mg: fakeSpaceRole(withSpace(guidSpace), withUsername("user1"), withOrigin("sap.ids"), withType(v1alpha1.SpaceManager), withExternalName(guidRole), withObservedRole(guidRole, "space_manager")),
+			},
+			want: want{
+				mg:  fakeSpaceRole(withSpace(guidSpace), withUsername("user1"), withOrigin("sap.ids"), withType(v1alpha1.SpaceManager), withExternalName(guidRole), withObservedRole(guidRole, "space_manager")),
+				err: nil,
+			},
+			service: func() *fake.MockSpaceRole {
+				// no expectations, the role must be neither deleted nor created
+				return &fake.MockSpaceRole{}
+			},
+		},
+		"CreateFails": {
+			args: args{
+				mg: fakeSpaceRole(withSpace(guidSpace), withUsername("user1"), withOrigin("sap.ids"), withType(v1alpha1.SpaceDeveloper), withExternalName(guidRole), withObservedRole(guidRole, "space_manager")),
+			},
+			want: want{
+				mg:  fakeSpaceRole(withSpace(guidSpace), withUsername("user1"), withOrigin("sap.ids"), withType(v1alpha1.SpaceDeveloper), withExternalName(guidRole), withObservedRole(guidRole, "space_manager")),
+				err: errors.Wrap(errBoom, errUpdate),
+			},
+			service: func() *fake.MockSpaceRole {
+				m := &fake.MockSpaceRole{}
+				m.On("Delete").Return("JOB", nil)
+				m.On("CreateSpaceRoleWithUsername").Return(fake.SpaceRoleNil, errBoom)
+				return m
+			},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			j := &fake.MockJob{}
+			j.On("PollComplete").Return(nil)
+			service := tc.service()
+			c := &external{
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				job:  j,
+				role: service,
+			}
+			_, err := c.Update(context.Background(), tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Update(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg); diff != "" {
+				t.Errorf("Update(...): -want, +got:\n%s", diff)
+			}
+			service.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package stack
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	k8s "sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/feature"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	pcv1beta1 "github.com/SAP/crossplane-provider-cloudfoundry/apis/v1beta1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/stack"
+)
+
+const (
+	resourceType         = "Stack"
+	externalSystem       = "Cloud Foundry"
+	errNotStack          = "managed resource is not of kind " + resourceType
+	errTrackUsage        = "cannot track usage"
+	errGetClient         = "cannot create a client to talk to the API of " + externalSystem
+	errGet               = "cannot get " + externalSystem + " stack"
+	errCreate            = "cannot create " + externalSystem + " stack"
+	errDelete            = "cannot delete " + externalSystem + " stack"
+	errMissingExternalID = "external name is not set"
+)
+
+// Setup adds a controller that reconciles Stack resources.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(v1alpha1.Stack_GroupKind)
+
+	options := []managed.ReconcilerOption{
+		managed.WithExternalConnecter(clients.WithPause(clients.WithErrorClassification(clients.WithOperationTimeout(mgr.GetClient(), &connector{
+			kube:  mgr.GetClient(),
+			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &pcv1beta1.ProviderConfigUsage{}),
+		}, clients.DefaultOperationTimeout)))),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithTimeout(clients.MaxOperationTimeout),
+		managed.WithPollIntervalHook(clients.PollIntervalHook(mgr.GetClient())),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithPollInterval(o.PollInterval),
+	}
+
+	if o.Features.Enabled(feature.EnableBetaManagementPolicies) {
+		options = append(options, managed.WithManagementPolicies())
+	}
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.Stack_GroupVersionKind),
+		options...)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1alpha1.Stack{}).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// A connector supplies a function for the Reconciler to create a client to the external CloudFoundry resources.
+type connector struct {
+	kube  k8s.Client
+	usage *resource.ProviderConfigUsageTracker
+}
+
+// Connect typically produces an ExternalClient by:
+// 1. Tracking that the managed resource is using a ProviderConfig.
+// 2. Getting the managed resource's ProviderConfig.
+// 3. Getting the credentials specified by the ProviderConfig.
+// 4. Using the credentials to form a client.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1alpha1.Stack); !ok {
+		return nil, errors.New(errNotStack)
+	}
+
+	if err := c.usage.Track(ctx, mg.(resource.ModernManaged)); err != nil {
+		return nil, errors.Wrap(err, errTrackUsage)
+	}
+
+	cf, err := clients.ClientFnBuilder(ctx, c.kube)(mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetClient)
+	}
+
+	return &external{client: stack.NewClient(cf), kube: c.kube}, nil
+}
+
+// Disconnect implements the managed.ExternalClient interface
+func (c *external) Disconnect(ctx context.Context) error {
+	// No cleanup needed for Cloud Foundry client
+	return nil
+}
+
+// An external is a managed.ExternalConnecter that is using the CloudFoundry API to observe and modify resources.
+type external struct {
+	client stack.Client
+	kube   k8s.Client
+}
+
+// Observe managed resource Stack
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.Stack)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotStack)
+	}
+
+	guid := meta.GetExternalName(cr)
+
+	s, err := stack.GetByIDOrName(ctx, c.client, guid, cr.Spec.ForProvider.Name)
+	if err != nil {
+		if clients.IsNotFound(err) {
+			return managed.ExternalObservation{ResourceExists: false}, nil
+		}
+		return managed.ExternalObservation{}, errors.Wrap(err, errGet)
+	}
+
+	// set the external name to the GUID
+	lateInitialized := false
+	if guid != s.GUID {
+		meta.SetExternalName(cr, s.GUID)
+		lateInitialized = true
+	}
+
+	cr.Status.AtProvider = stack.GenerateObservation(s)
+	cr.SetConditions(xpv1.Available())
+
+	// The name and description of a stack are immutable, there is nothing to update.
+	return managed.ExternalObservation{
+		ResourceExists:          true,
+		ResourceUpToDate:        true,
+		ResourceLateInitialized: lateInitialized,
+	}, nil
+}
+
+// Create a managed resource Stack
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.Stack)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotStack)
+	}
+
+	cr.SetConditions(xpv1.Creating())
+
+	s, err := c.client.Create(ctx, stack.GenerateCreate(cr.Spec.ForProvider))
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreate)
+	}
+
+	meta.SetExternalName(cr, s.GUID)
+
+	return managed.ExternalCreation{}, nil
+}
+
+// Update managed resource Stack. Stacks have no updatable attributes.
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	if _, ok := mg.(*v1alpha1.Stack); !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotStack)
+	}
+	return managed.ExternalUpdate{}, nil
+}
+
+// Delete managed resource Stack
+func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	cr, ok := mg.(*v1alpha1.Stack)
+	if !ok {
+		return managed.ExternalDelete{}, errors.New(errNotStack)
+	}
+	cr.SetConditions(xpv1.Deleting())
+
+	guid := meta.GetExternalName(cr)
+	if !clients.IsValidGUID(guid) {
+		return managed.ExternalDelete{}, errors.Wrap(errors.New(errMissingExternalID), errDelete)
+	}
+
+	// A stack that is already gone is deleted.
+	return managed.ExternalDelete{}, errors.Wrap(clients.IgnoreNotFoundErr(c.client.Delete(ctx, guid)), errDelete)
+}
@@ -0,0 +1,313 @@
+package stack
+
+import (
+	"context"
+	"testing"
+
+	cfresource "github.com/cloudfoundry/go-cfclient/v3/resource"
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients/fake"
+)
+
+var (
+	errBoom     = errors.New("boom")
+	name        = "cflinuxfs4"
+	description = "Cloud Foundry Linux-based filesystem"
+	guid        = "b85a788e-671f-4549-814d-e34cdb2f539a"
+)
+
+type modifier func(*v1alpha1.Stack)
+
+func withExternalName(name string) modifier {
+	return func(r *v1alpha1.Stack) {
+		meta.SetExternalName(r, name)
+	}
+}
+
+func withDescription(description string) modifier {
+	return func(r *v1alpha1.Stack) {
+		r.Spec.ForProvider.Description = &description
+	}
+}
+
+func withConditions(c ...xpv1.Condition) modifier {
+	return func(r *v1alpha1.Stack) { r.Status.SetConditions(c...) }
+}
+
+func fakeStack(m ...modifier) *v1alpha1.Stack {
+	r := &v1alpha1.Stack{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Finalizers:  []string{},
+			Annotations: map[string]string{},
+		},
+		Spec: v1alpha1.StackSpec{
+			ForProvider: v1alpha1.StackParameters{Name: name},
+		},
+	}
+
+	for _, rm := range m {
+		rm(r)
+	}
+	return r
+}
+
+func TestObserve(t *testing.T) {
+	type service func() *fake.MockStack
+	type args struct {
+		mg resource.Managed
+	}
+
+	type want struct {
+		mg  *v1alpha1.Stack
+		obs managed.ExternalObservation
+		err error
+	}
+
+	cases := map[string]struct {
+		args    args
+		want    want
+		service service
+	}{
+		"WrongKind": {
+			args: args{
+				mg: nil,
+			},
+			want: want{
+				err: errors.New(errNotStack),
+			},
+			service: func() *fake.MockStack {
+				return &fake.MockStack{}
+			},
+		},
+		"Error": {
+			args: args{
+				mg: fakeStack(withExternalName(guid)),
+			},
+			want: want{
+				mg:  fakeStack(withExternalName(guid)),
+				err: errors.Wrap(errBoom, errGet),
+			},
+			service: func() *fake.MockStack {
+				m := &fake.MockStack{}
+				m.On("Get", guid).Return(fake.StackNil, errBoom)
+				return m
+			},
+		},
+		"NotFound": {
+			args: args{
+				mg: fakeStack(),
+			},
+			want: want{
+				mg:  fakeStack(),
+				obs: managed.ExternalObservation{ResourceExists: false},
+			},
+			service: func() *fake.MockStack {
+				m := &fake.MockStack{}
+				m.On("Single", []string{name}).Return(fake.StackNil, fake.ErrNoResultReturned)
+				return m
+			},
+		},
+		"ExistsByName": {
+			args: args{
+				mg: fakeStack(),
+			},
+			want: want{
+				mg: fakeStack(withExternalName(guid)),
+				obs: managed.ExternalObservation{
+					ResourceExists:          true,
+					ResourceUpToDate:        true,
+					ResourceLateInitialized: true,
+				},
+			},
+			service: func() *fake.MockStack {
+				m := &fake.MockStack{}
+				m.On("Single", []string{name}).Return(&fake.NewStack().SetName(name).SetGUID(guid).Stack, nil)
+				return m
+			},
+		},
+		"ExistsByGUID": {
+			args: args{
+				mg: fakeStack(withExternalName(guid), withDescription(description)),
+			},
+			want: want{
+				mg:  fakeStack(withExternalName(guid), withDescription(description)),
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+			service: func() *fake.MockStack {
+				m := &fake.MockStack{}
+				m.On("Get", guid).Return(&fake.NewStack().SetName(name).SetGUID(guid).SetDescription(description).Stack, nil)
+				return m
+			},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			c := &external{client: tc.service()}
+			obs, err := c.Observe(context.Background(), tc.args.mg)
+
+			if tc.want.err != nil && err != nil {
+				if diff := cmp.Diff(tc.want.err.Error(), err.Error()); diff != "" {
+					t.Errorf("Observe(...): want error string != got error string:\n%s", diff)
+				}
+			} else if diff := cmp.Diff(tc.want.err, err); diff != "" {
+				t.Errorf("Observe(...): want error != got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.obs, obs); diff != "" {
+				t.Errorf("Observe(...): -want, +got:\n%s", diff)
+			}
+			if cr, ok := tc.args.mg.(*v1alpha1.Stack); ok && tc.want.mg != nil {
+				if diff := cmp.Diff(meta.GetExternalName(tc.want.mg), meta.GetExternalName(cr)); diff != "" {
+					t.Errorf("Observe(...): -want external name, +got external name:\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	type service func() *fake.MockStack
+	type args struct {
+		mg resource.Managed
+	}
+
+	type want struct {
+		mg  *v1alpha1.Stack
+		err error
+	}
+
+	cases := map[string]struct {
+		args    args
+		want    want
+		service service
+	}{
+		"Successful": {
+			args: args{
+				mg: fakeStack(withDescription(description)),
+			},
+			want: want{
+				mg: fakeStack(withDescription(description), withExternalName(guid), withConditions(xpv1.Creating())),
+			},
+			service: func() *fake.MockStack {
+				m := &fake.MockStack{}
+				m.On("Create", &cfresource.StackCreate{Name: name, Description: ptr.To(description)}).
+					Return(&fake.NewStack().SetName(name).SetGUID(guid).Stack, nil)
+				return m
+			},
+		},
+		"Error": {
+			args: args{
+				mg: fakeStack(),
+			},
+			want: want{
+				mg:  fakeStack(withConditions(xpv1.Creating())),
+				err: errors.Wrap(errBoom, errCreate),
+			},
+			service: func() *fake.MockStack {
+				m := &fake.MockStack{}
+				m.On("Create", mock.Anything).Return(fake.StackNil, errBoom)
+				return m
+			},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			c := &external{client: tc.service()}
+			_, err := c.Create(context.Background(), tc.args.mg)
+
+			if tc.want.err != nil && err != nil {
+				if diff := cmp.Diff(tc.want.err.Error(), err.Error()); diff != "" {
+					t.Errorf("Create(...): want error string != got error string:\n%s", diff)
+				}
+			} else if diff := cmp.Diff(tc.want.err, err); diff != "" {
+				t.Errorf("Create(...): want error != got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg); diff != "" {
+				t.Errorf("Create(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	type service func() *fake.MockStack
+	type args struct {
+		mg resource.Managed
+	}
+
+	cases := map[string]struct {
+		args    args
+		want    error
+		service service
+	}{
+		"Successful": {
+			args: args{
+				mg: fakeStack(withExternalName(guid)),
+			},
+			service: func() *fake.MockStack {
+				m := &fake.MockStack{}
+				m.On("Delete", guid).Return(nil)
+				return m
+			},
+		},
+		"AlreadyDeleted": {
+			args: args{
+				mg: fakeStack(withExternalName(guid)),
+			},
+			service: func() *fake.MockStack {
+				m := &fake.MockStack{}
+				m.On("Delete", guid).Return(fake.ErrResourceNotFound)
+				return m
+			},
+		},
+		"Error": {
+			args: args{
+				mg: fakeStack(withExternalName(guid)),
+			},
+			want: errors.Wrap(errBoom, errDelete),
+			service: func() *fake.MockStack {
+				m := &fake.MockStack{}
+				m.On("Delete", guid).Return(errBoom)
+				return m
+			},
+		},
+		"NoExternalName": {
+			args: args{
+				mg: fakeStack(),
+			},
+			want: errors.Wrap(errors.New(errMissingExternalID), errDelete),
+			service: func() *fake.MockStack {
+				return &fake.MockStack{}
+			},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			m := tc.service()
+			c := &external{client: m}
+			_, err := c.Delete(context.Background(), tc.args.mg)
+
+			if tc.want != nil && err != nil {
+				if diff := cmp.Diff(tc.want.Error(), err.Error()); diff != "" {
+					t.Errorf("Delete(...): want error string != got error string:\n%s", diff)
+				}
+			} else if diff := cmp.Diff(tc.want, err); diff != "" {
+				t.Errorf("Delete(...): want error != got error:\n%s", diff)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,160 @@
+// Package metrics exports Prometheus metrics of the operations controllers
+// perform on external resources and of the reachability of the Cloud Foundry
+// API.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
+)
+
+const namespace = "provider_cloudfoundry"
+
+// Operations on an external resource.
+const (
+	OperationObserve = "observe"
+	OperationCreate  = "create"
+	OperationUpdate  = "update"
+	OperationDelete  = "delete"
+)
+
+// Categories of the errors of an operation.
+const (
+	// CategoryNotFound is an error because the resource or one it depends on
+	// does not exist in Cloud Foundry.
+	CategoryNotFound = "not_found"
+	// CategoryTimeout is an operation that did not complete in time.
+	CategoryTimeout = "timeout"
+	// CategoryAPI is any other error, e.g. returned by the CF API or a
+	// service broker.
+	CategoryAPI = "api_error"
+)
+
+var (
+	operations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "operations_total",
+		Help:      "Number of operations on external resources.",
+	}, []string{"kind", "operation"})
+
+	durations = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "operation_duration_seconds",
+		Help:      "Duration of operations on external resources.",
+		Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+	}, []string{"kind", "operation"})
+
+	operationErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "operation_errors_total",
+		Help:      "Number of failed operations on external resources by error category.",
+	}, []string{"kind", "operation", "category"})
+
+	providerConfigReachable = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "providerconfig_api_reachable",
+		Help:      "Whether the Cloud Foundry API was reachable with a ProviderConfig at the last check, 1 if it was, 0 if not.",
+	}, []string{"namespace", "name"})
+
+	registerOnce sync.Once
+)
+
+// Register registers the metrics with the controller-runtime metrics
+// registry. It is safe to call from the Setup of every controller.
+func Register() {
+	registerOnce.Do(func() {
+		ctrlmetrics.Registry.MustRegister(operations, durations, operationErrors, providerConfigReachable)
+	})
+}
+
+// Category returns the category of the error of an operation.
+func Category(err error) string {
+	switch {
+	case clients.IsNotFound(err):
+		return CategoryNotFound
+	case errors.Is(err, context.DeadlineExceeded):
+		return CategoryTimeout
+	default:
+		return CategoryAPI
+	}
+}
+
+// ReportProviderConfigReachability records for each ProviderConfig whether
+// the Cloud Foundry API was reachable with it, i.e. whether its error is nil.
+// ProviderConfigs that were not checked, e.g. deleted ones, are dropped.
+func ReportProviderConfigReachability(results map[types.NamespacedName]error) {
+	providerConfigReachable.Reset()
+	for pc, err := range results {
+		reachable := 0.0
+		if err == nil {
+			reachable = 1
+		}
+		providerConfigReachable.WithLabelValues(pc.Namespace, pc.Name).Set(reachable)
+	}
+}
+
+// observe records an operation of the given kind that started at start and
+// failed with err, if not nil.
+func observe(kind, operation string, start time.Time, err error) {
+	operations.WithLabelValues(kind, operation).Inc()
+	durations.WithLabelValues(kind, operation).Observe(time.Since(start).Seconds())
+	if err != nil {
+		operationErrors.WithLabelValues(kind, operation, Category(err)).Inc()
+	}
+}
+
+// Instrument wraps an ExternalConnecter so that the operations of the
+// ExternalClients it produces are recorded for the given resource kind.
+func Instrument(kind string, c managed.ExternalConnecter) managed.ExternalConnecter {
+	return managed.ExternalConnectorFn(func(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+		ec, err := c.Connect(ctx, mg)
+		if err != nil {
+			return nil, err
+		}
+		return &instrumentedClient{ExternalClient: ec, kind: kind}, nil
+	})
+}
+
+// instrumentedClient records the operations of an ExternalClient.
+type instrumentedClient struct {
+	managed.ExternalClient
+	kind string
+}
+
+func (c *instrumentedClient) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	start := time.Now()
+	o, err := c.ExternalClient.Observe(ctx, mg)
+	observe(c.kind, OperationObserve, start, err)
+	return o, err
+}
+
+func (c *instrumentedClient) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	start := time.Now()
+	o, err := c.ExternalClient.Create(ctx, mg)
+	observe(c.kind, OperationCreate, start, err)
+	return o, err
+}
+
+func (c *instrumentedClient) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	start := time.Now()
+	o, err := c.ExternalClient.Update(ctx, mg)
+	observe(c.kind, OperationUpdate, start, err)
+	return o, err
+}
+
+func (c *instrumentedClient) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	start := time.Now()
+	o, err := c.ExternalClient.Delete(ctx, mg)
+	observe(c.kind, OperationDelete, start, err)
+	return o, err
+}
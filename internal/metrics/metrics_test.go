@@ -0,0 +1,139 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+)
+
+func TestCategory(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		want string
+	}{
+		"NotFound": {
+			err:  errors.Wrap(client.ErrExactlyOneResultNotReturned, "cannot get ServiceInstance"),
+			want: CategoryNotFound,
+		},
+		"Timeout": {
+			err:  fmt.Errorf("cannot create ServiceInstance: %w", context.DeadlineExceeded),
+			want: CategoryTimeout,
+		},
+		"API": {
+			err:  errors.New("CF-ServiceBrokerRequestRejected"),
+			want: CategoryAPI,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, Category(tc.err)); diff != "" {
+				t.Errorf("Category(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+// value returns the value of a counter.
+func value(t *testing.T, c prometheus.Counter) float64 {
+	t.Helper()
+	m := &dto.Metric{}
+	if err := c.Write(m); err != nil {
+		t.Fatalf("Write(...): %v", err)
+	}
+	return m.GetCounter().GetValue()
+}
+
+func TestInstrument(t *testing.T) {
+	const kind = "TestInstrument"
+	errBroker := errors.New("broker rejected the request")
+
+	ec := &managed.ExternalClientFns{
+		ObserveFn: func(context.Context, resource.Managed) (managed.ExternalObservation, error) {
+			return managed.ExternalObservation{}, errors.Wrap(client.ErrNoResultsReturned, "cannot observe")
+		},
+		CreateFn: func(context.Context, resource.Managed) (managed.ExternalCreation, error) {
+			return managed.ExternalCreation{}, errBroker
+		},
+		UpdateFn: func(context.Context, resource.Managed) (managed.ExternalUpdate, error) {
+			return managed.ExternalUpdate{}, nil
+		},
+	}
+	c := Instrument(kind, managed.ExternalConnectorFn(func(context.Context, resource.Managed) (managed.ExternalClient, error) {
+		return ec, nil
+	}))
+
+	mg := &v1alpha1.ServiceInstance{}
+	ext, err := c.Connect(context.Background(), mg)
+	if err != nil {
+		t.Fatalf("Connect(...): %v", err)
+	}
+	_, _ = ext.Observe(context.Background(), mg)
+	_, _ = ext.Create(context.Background(), mg)
+	_, _ = ext.Update(context.Background(), mg)
+
+	want := map[string]float64{
+		"observe":           1,
+		"create":            1,
+		"update":            1,
+		"observe/not_found": 1,
+		"create/api_error":  1,
+		"update/api_error":  0,
+	}
+	got := map[string]float64{
+		"observe":           value(t, operations.WithLabelValues(kind, OperationObserve)),
+		"create":            value(t, operations.WithLabelValues(kind, OperationCreate)),
+		"update":            value(t, operations.WithLabelValues(kind, OperationUpdate)),
+		"observe/not_found": value(t, operationErrors.WithLabelValues(kind, OperationObserve, CategoryNotFound)),
+		"create/api_error":  value(t, operationErrors.WithLabelValues(kind, OperationCreate, CategoryAPI)),
+		"update/api_error":  value(t, operationErrors.WithLabelValues(kind, OperationUpdate, CategoryAPI)),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Instrument(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestReportProviderConfigReachability(t *testing.T) {
+	a := types.NamespacedName{Namespace: "default", Name: "a"}
+	b := types.NamespacedName{Namespace: "default", Name: "b"}
+
+	ReportProviderConfigReachability(map[types.NamespacedName]error{a: nil, b: errors.New("boom")})
+	gauge := func(pc types.NamespacedName) float64 {
+		m := &dto.Metric{}
+		if err := providerConfigReachable.WithLabelValues(pc.Namespace, pc.Name).Write(m); err != nil {
+			t.Fatalf("Write(...): %v", err)
+		}
+		return m.GetGauge().GetValue()
+	}
+	if got := gauge(a); got != 1 {
+		t.Errorf("reachable a: want 1, got %v", got)
+	}
+	if got := gauge(b); got != 0 {
+		t.Errorf("reachable b: want 0, got %v", got)
+	}
+
+	// A ProviderConfig that is gone is no longer reported.
+	ReportProviderConfigReachability(map[types.NamespacedName]error{a: nil})
+	if got := reportedProviderConfigs(); got != 1 {
+		t.Errorf("reported ProviderConfigs: want 1, got %d", got)
+	}
+}
+
+// reportedProviderConfigs returns the number of ProviderConfigs that are reported.
+func reportedProviderConfigs() int {
+	ch := make(chan prometheus.Metric, 10)
+	providerConfigReachable.Collect(ch)
+	close(ch)
+	return len(ch)
+}
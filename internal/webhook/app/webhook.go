@@ -0,0 +1,139 @@
+package app
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/clients"
+)
+
+const (
+	lifecycleBuildpack = "buildpack"
+	lifecycleDocker    = "docker"
+)
+
+// +kubebuilder:webhook:path=/mutate-cloudfoundry-crossplane-io-v1alpha1-app,mutating=true,failurePolicy=fail,sideEffects=None,groups=cloudfoundry.crossplane.io,resources=apps,verbs=create;update,versions=v1alpha1,name=mapp.cloudfoundry.crossplane.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-cloudfoundry-crossplane-io-v1alpha1-app,mutating=false,failurePolicy=fail,sideEffects=None,groups=cloudfoundry.crossplane.io,resources=apps,verbs=create;update,versions=v1alpha1,name=vapp.cloudfoundry.crossplane.io,admissionReviewVersions=v1
+
+// Setup adds the defaulting and validating webhooks of App to the manager.
+func Setup(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.App{}).
+		WithDefaulter(&defaulter{}).
+		WithValidator(&validator{}).
+		Complete()
+}
+
+// defaulter defaults the lifecycle of an App.
+type defaulter struct{}
+
+// Default sets the lifecycle of an App with a docker image to docker. The
+// lifecycle is defaulted to buildpack by the CRD before, so an App with a
+// docker image and the buildpack lifecycle gets the docker lifecycle.
+func (d *defaulter) Default(_ context.Context, obj runtime.Object) error {
+	cr, ok := obj.(*v1alpha1.App)
+	if !ok {
+		return errors.Errorf("expected an App but got %T", obj)
+	}
+	p := &cr.Spec.ForProvider
+	switch {
+	case p.Docker != nil && (p.Lifecycle == "" || p.Lifecycle == lifecycleBuildpack):
+		p.Lifecycle = lifecycleDocker
+	case p.Lifecycle == "":
+		p.Lifecycle = lifecycleBuildpack
+	}
+	return nil
+}
+
+// validator validates the spec of an App.
+type validator struct{}
+
+// ValidateCreate validates a new App.
+func (v *validator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	cr, ok := obj.(*v1alpha1.App)
+	if !ok {
+		return nil, errors.Errorf("expected an App but got %T", obj)
+	}
+	return nil, validate(cr)
+}
+
+// ValidateUpdate validates an updated App. An App that is being deleted is
+// not validated, so that an invalid spec does not block removing its finalizer.
+func (v *validator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	cr, ok := newObj.(*v1alpha1.App)
+	if !ok {
+		return nil, errors.Errorf("expected an App but got %T", newObj)
+	}
+	if cr.GetDeletionTimestamp() != nil {
+		return nil, nil
+	}
+	return nil, validate(cr)
+}
+
+// ValidateDelete does not validate a deleted App.
+func (v *validator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate returns an Invalid error listing all invalid fields of the App, or nil.
+func validate(cr *v1alpha1.App) error {
+	errs := validateParameters(cr.Spec.ForProvider, field.NewPath("spec", "forProvider"))
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(v1alpha1.App_GroupVersionKind.GroupKind(), cr.GetName(), errs)
+}
+
+func validateParameters(p v1alpha1.AppParameters, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	// the app is pushed from either a docker image or a path
+	if p.Docker != nil && p.Path != nil {
+		errs = append(errs, field.Forbidden(path.Child("path"), "path and docker are mutually exclusive"))
+	}
+
+	if p.Lifecycle == lifecycleDocker {
+		if p.Docker == nil || p.Docker.Image == "" {
+			errs = append(errs, field.Required(path.Child("docker", "image"), "a docker image is required for the docker lifecycle"))
+		}
+		for _, f := range []struct {
+			name string
+			set  bool
+		}{
+			{"buildpacks", len(p.Buildpacks) > 0},
+			{"buildpackRefs", len(p.BuildpackRefs) > 0},
+			{"buildpackSelector", p.BuildpackSelector != nil},
+		} {
+			if f.set {
+				errs = append(errs, field.Forbidden(path.Child(f.name), "buildpacks cannot be used with the docker lifecycle"))
+			}
+		}
+	} else if p.Docker != nil {
+		errs = append(errs, field.Forbidden(path.Child("docker"), "a docker image can only be used with the docker lifecycle"))
+	}
+
+	errs = append(errs, validateUnit(p.Memory, path.Child("memory"))...)
+	for i, process := range p.Processes {
+		errs = append(errs, validateUnit(process.Memory, path.Child("processes").Index(i).Child("memory"))...)
+		errs = append(errs, validateUnit(process.DiskQuota, path.Child("processes").Index(i).Child("diskQuota"))...)
+	}
+	return errs
+}
+
+// validateUnit validates that a memory or disk attribute has a unit of measurement, e.g. 1G or 512MB.
+func validateUnit(value *string, path *field.Path) field.ErrorList {
+	if value == nil {
+		return nil
+	}
+	if _, err := clients.MemoryInMB(*value); err != nil {
+		return field.ErrorList{field.Invalid(path, *value, "must be a number with a unit of measurement, such as M, MB, G, GB, T, or TB")}
+	}
+	return nil
+}
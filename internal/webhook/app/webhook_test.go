@@ -0,0 +1,154 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	v1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/apis/resources/v1alpha1"
+)
+
+type modifier func(*v1alpha1.AppParameters)
+
+func withLifecycle(l string) modifier {
+	return func(p *v1alpha1.AppParameters) { p.Lifecycle = l }
+}
+
+func withDocker(image string) modifier {
+	return func(p *v1alpha1.AppParameters) { p.Docker = &v1alpha1.DockerConfiguration{Image: image} }
+}
+
+func withProcess(memory, disk string) modifier {
+	return func(p *v1alpha1.AppParameters) {
+		p.Processes = append(p.Processes, v1alpha1.ProcessConfiguration{Type: ptr.To("web"), Memory: &memory, DiskQuota: &disk})
+	}
+}
+
+func newApp(m ...modifier) *v1alpha1.App {
+	cr := &v1alpha1.App{ObjectMeta: metav1.ObjectMeta{Name: "my-app"}}
+	cr.Spec.ForProvider.Name = "my-app"
+	for _, f := range m {
+		f(&cr.Spec.ForProvider)
+	}
+	return cr
+}
+
+func TestDefault(t *testing.T) {
+	cases := map[string]struct {
+		cr   *v1alpha1.App
+		want string
+	}{
+		"Buildpack": {
+			cr:   newApp(),
+			want: "buildpack",
+		},
+		"DockerImage": {
+			cr:   newApp(withDocker("nginx")),
+			want: "docker",
+		},
+		"DockerImageWithDefaultLifecycle": {
+			cr:   newApp(withLifecycle("buildpack"), withDocker("nginx")),
+			want: "docker",
+		},
+		"CNB": {
+			cr:   newApp(withLifecycle("cnb")),
+			want: "cnb",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if err := (&defaulter{}).Default(context.Background(), tc.cr); err != nil {
+				t.Fatalf("Default(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, tc.cr.Spec.ForProvider.Lifecycle); diff != "" {
+				t.Errorf("Default(...): -want lifecycle, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cases := map[string]struct {
+		cr     *v1alpha1.App
+		fields []string
+	}{
+		"Valid": {
+			cr: newApp(withLifecycle("buildpack"), func(p *v1alpha1.AppParameters) {
+				p.Buildpacks = []string{"go_buildpack"}
+				p.Memory = ptr.To("512M")
+			}, withProcess("1G", "2GB")),
+		},
+		"ValidDocker": {
+			cr: newApp(withLifecycle("docker"), withDocker("nginx")),
+		},
+		"DockerAndPath": {
+			cr: newApp(withLifecycle("docker"), withDocker("nginx"), func(p *v1alpha1.AppParameters) {
+				p.Path = ptr.To("app.zip")
+			}),
+			fields: []string{"spec.forProvider.path"},
+		},
+		"DockerWithoutImage": {
+			cr:     newApp(withLifecycle("docker")),
+			fields: []string{"spec.forProvider.docker.image"},
+		},
+		"DockerWithBuildpacks": {
+			cr: newApp(withLifecycle("docker"), withDocker("nginx"), func(p *v1alpha1.AppParameters) {
+				p.Buildpacks = []string{"go_buildpack"}
+				p.BuildpackRefs = []v1.NamespacedReference{{Name: "go"}}
+				p.BuildpackSelector = &v1.NamespacedSelector{}
+			}),
+			fields: []string{"spec.forProvider.buildpacks", "spec.forProvider.buildpackRefs", "spec.forProvider.buildpackSelector"},
+		},
+		"DockerImageWithBuildpackLifecycle": {
+			cr:     newApp(withLifecycle("buildpack"), withDocker("nginx")),
+			fields: []string{"spec.forProvider.docker"},
+		},
+		"InvalidMemory": {
+			cr: newApp(func(p *v1alpha1.AppParameters) {
+				p.Memory = ptr.To("512")
+			}),
+			fields: []string{"spec.forProvider.memory"},
+		},
+		"InvalidProcessMemoryAndDisk": {
+			cr:     newApp(withProcess("1 gigabyte", "2X")),
+			fields: []string{"spec.forProvider.processes[0].memory", "spec.forProvider.processes[0].diskQuota"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := (&validator{}).ValidateCreate(context.Background(), tc.cr)
+			var fields []string
+			if err != nil {
+				status, ok := err.(apierrors.APIStatus)
+				if !ok || !apierrors.IsInvalid(err) {
+					t.Fatalf("ValidateCreate(...): want an Invalid error, got: %v", err)
+				}
+				for _, c := range status.Status().Details.Causes {
+					fields = append(fields, c.Field)
+				}
+			}
+			if diff := cmp.Diff(tc.fields, fields); diff != "" {
+				t.Errorf("ValidateCreate(...): -want invalid fields, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateUpdateDeleted(t *testing.T) {
+	cr := newApp(withLifecycle("docker"))
+	if _, err := (&validator{}).ValidateUpdate(context.Background(), cr, cr); err == nil {
+		t.Errorf("ValidateUpdate(...): want an error for an invalid App")
+	}
+	cr.SetDeletionTimestamp(&metav1.Time{})
+	if _, err := (&validator{}).ValidateUpdate(context.Background(), cr, cr); err != nil {
+		t.Errorf("ValidateUpdate(...): unexpected error for a deleted App: %v", err)
+	}
+}
@@ -0,0 +1,23 @@
+/*
+Copyright 2023 SAP SE
+*/
+
+package webhook
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/SAP/crossplane-provider-cloudfoundry/internal/webhook/app"
+)
+
+// Setup adds all admission webhooks to the webhook server of the supplied manager.
+func Setup(mgr ctrl.Manager) error {
+	for _, setup := range []func(ctrl.Manager) error{
+		app.Setup,
+	} {
+		if err := setup(mgr); err != nil {
+			return err
+		}
+	}
+	return nil
+}